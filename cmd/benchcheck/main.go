@@ -0,0 +1,214 @@
+// Command benchcheck compares go test benchmark output with a baseline and
+// fails when a benchmark regressed beyond the allowed thresholds.
+//
+//	go test -run '^$' -bench . -benchmem ./internal/... | go run ./cmd/benchcheck
+//
+// Allocations are compared by default since they hardly depend on the
+// machine; time per operation only with -max-time, on the machine that
+// recorded the baseline.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	baselinePath = flag.String("baseline", "benchmarks/baseline.txt", "Baseline benchmark results")
+	update       = flag.Bool("update", false, "Write the results to the baseline instead of comparing")
+	maxAllocs    = flag.Float64("max-allocs", 0.1, "Allowed increase of allocs/op as a fraction, negative disables the check")
+	maxBytes     = flag.Float64("max-bytes", 0.25, "Allowed increase of B/op as a fraction, negative disables the check")
+	maxTime      = flag.Float64("max-time", -1, "Allowed increase of ns/op as a fraction, negative disables the check")
+)
+
+// Result holds the measurements of a benchmark. Of repeated runs (-count)
+// the lowest values are kept, which are the least disturbed by noise.
+type Result struct {
+	Name   string
+	NsOp   float64
+	BOp    float64
+	Allocs float64
+}
+
+// procSuffix is the GOMAXPROCS suffix of benchmark names
+var procSuffix = regexp.MustCompile(`-\d+$`)
+
+// parse reads the benchmark results of go test output, with the package
+// path prefixed to the benchmark names
+func parse(r io.Reader) (map[string]*Result, error) {
+	results := make(map[string]*Result)
+	pkg, pending := "", ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "pkg:" {
+			pkg = fields[1]
+			continue
+		}
+		if len(fields) > 0 && strings.HasPrefix(fields[0], "Benchmark") {
+			pending = procSuffix.ReplaceAllString(fields[0], "")
+			if pkg != "" {
+				pending = pkg + "." + pending
+			}
+			fields = fields[1:]
+		}
+		// The measurements follow the name on the same line, or on a later
+		// one if the benchmark logged something
+		if pending == "" || len(fields) < 3 || fields[2] != "ns/op" {
+			continue
+		}
+		name := pending
+		pending = ""
+
+		measured := Result{Name: name}
+		for i := 1; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				measured.NsOp = value
+			case "B/op":
+				measured.BOp = value
+			case "allocs/op":
+				measured.Allocs = value
+			}
+		}
+
+		result, ok := results[name]
+		if !ok {
+			results[name] = &measured
+			continue
+		}
+		result.NsOp = min(result.NsOp, measured.NsOp)
+		result.BOp = min(result.BOp, measured.BOp)
+		result.Allocs = min(result.Allocs, measured.Allocs)
+	}
+	return results, scanner.Err()
+}
+
+// regression describes a measurement exceeding its threshold, empty if it
+// does not
+func regression(unit string, baseline, current, allowed float64) string {
+	if allowed < 0 || baseline <= 0 || current <= baseline*(1+allowed) {
+		return ""
+	}
+	return fmt.Sprintf("%s %.0f -> %.0f (+%.0f%%, allowed +%.0f%%)", unit, baseline, current, (current/baseline-1)*100, allowed*100)
+}
+
+// compare returns the regressions of the current results against the
+// baseline and the baseline benchmarks that did not run
+func compare(baseline, current map[string]*Result) (regressions, missing []string) {
+	names := make([]string, 0, len(baseline))
+	for name := range baseline {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		base := baseline[name]
+		result, ok := current[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		for _, r := range []string{
+			regression("allocs/op", base.Allocs, result.Allocs, *maxAllocs),
+			regression("B/op", base.BOp, result.BOp, *maxBytes),
+			regression("ns/op", base.NsOp, result.NsOp, *maxTime),
+		} {
+			if r != "" {
+				regressions = append(regressions, name+": "+r)
+			}
+		}
+	}
+	return regressions, missing
+}
+
+// write writes results in the go test benchmark format
+func write(w io.Writer, results map[string]*Result) error {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pkg := ""
+	for _, name := range names {
+		result := results[name]
+		dot := strings.LastIndex(name[:strings.Index(name, "Benchmark")], ".")
+		if namePkg := name[:max(dot, 0)]; namePkg != pkg {
+			pkg = namePkg
+			if _, err := fmt.Fprintf(w, "pkg: %s\n", pkg); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s\t1\t%.0f ns/op\t%.0f B/op\t%.0f allocs/op\n",
+			name[dot+1:], result.NsOp, result.BOp, result.Allocs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	current, err := parse(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read benchmark results: %v\n", err)
+		os.Exit(1)
+	}
+	if len(current) == 0 {
+		fmt.Fprintln(os.Stderr, "No benchmark results on stdin")
+		os.Exit(1)
+	}
+
+	if *update {
+		file, err := os.Create(*baselinePath)
+		if err == nil {
+			err = write(file, current)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write baseline: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %d benchmarks to %s\n", len(current), *baselinePath)
+		return
+	}
+
+	file, err := os.Open(*baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read baseline: %v\n", err)
+		os.Exit(1)
+	}
+	baseline, err := parse(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read baseline: %v\n", err)
+		os.Exit(1)
+	}
+
+	regressions, missing := compare(baseline, current)
+	for _, name := range missing {
+		fmt.Printf("not run: %s\n", name)
+	}
+	for _, r := range regressions {
+		fmt.Printf("REGRESSION %s\n", r)
+	}
+	if len(regressions) > 0 {
+		os.Exit(1)
+	}
+	fmt.Printf("%d benchmarks within thresholds\n", len(baseline)-len(missing))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const benchOutput = `goos: linux
+pkg: example.com/mod/api
+BenchmarkDecode-8   	    1000	   1200 ns/op	   500 B/op	      10 allocs/op
+BenchmarkDecode-8   	    1000	   1100 ns/op	   520 B/op	      11 allocs/op
+BenchmarkLogging-8  	time="..." level=debug msg="request"
+   5000	    300 ns/op	    64 B/op	       2 allocs/op
+PASS
+pkg: example.com/mod/mcp
+BenchmarkTool/Search-8	     100	  90000 ns/op	  8000 B/op	     150 allocs/op
+ok  	example.com/mod/mcp	1.2s
+`
+
+func TestParse(t *testing.T) {
+	results, err := parse(strings.NewReader(benchOutput))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	decode := results["example.com/mod/api.BenchmarkDecode"]
+	require.NotNil(t, decode)
+	assert.Equal(t, 1100.0, decode.NsOp, "lowest of repeated runs")
+	assert.Equal(t, 500.0, decode.BOp)
+	assert.Equal(t, 10.0, decode.Allocs)
+
+	logging := results["example.com/mod/api.BenchmarkLogging"]
+	require.NotNil(t, logging, "measurements after log output")
+	assert.Equal(t, 2.0, logging.Allocs)
+
+	assert.Equal(t, 150.0, results["example.com/mod/mcp.BenchmarkTool/Search"].Allocs)
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	results, err := parse(strings.NewReader(benchOutput))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf, results))
+	reread, err := parse(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, results, reread)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := map[string]*Result{
+		"a.BenchmarkDecode": {NsOp: 1000, BOp: 500, Allocs: 10},
+		"a.BenchmarkTool":   {NsOp: 1000, BOp: 500, Allocs: 100},
+		"a.BenchmarkGone":   {NsOp: 1000, BOp: 500, Allocs: 10},
+	}
+	current := map[string]*Result{
+		// Slower and slightly more memory, both within the defaults
+		"a.BenchmarkDecode": {NsOp: 5000, BOp: 600, Allocs: 11},
+		"a.BenchmarkTool":   {NsOp: 1000, BOp: 500, Allocs: 150},
+	}
+
+	regressions, missing := compare(baseline, current)
+	assert.Equal(t, []string{"a.BenchmarkGone"}, missing)
+	require.Len(t, regressions, 1)
+	assert.Contains(t, regressions[0], "a.BenchmarkTool: allocs/op 100 -> 150 (+50%")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+var (
+	port    = flag.Int("port", 8080, "Port to listen on")
+	latency = flag.Duration("latency", 0, "Artificial latency added to every response")
+	players = flag.Int("players", 0, "Serve a generated data set with this many players instead of the static one")
+	clubs   = flag.Int("clubs", 0, "Clubs of the generated data set (default 250)")
+	seed    = flag.Int64("seed", 1, "Seed of the generated data set")
+)
+
+// Mock API server to provide the /api/v1/ endpoints expected by the MCP client
+func main() {
+	flag.Parse()
+
+	var dataset *testserver.Dataset
+	if *players > 0 {
+		dataset = testserver.GenerateDataset(testserver.GenerateOptions{Seed: *seed, Players: *players, Clubs: *clubs})
+		fmt.Printf("Generated %d players, %d clubs and %d tournaments (seed %d)\n",
+			len(dataset.Players), len(dataset.Clubs), len(dataset.Tournaments), *seed)
+	}
+
+	server := testserver.NewMockPortal64Server(testserver.Config{
+		Dataset: dataset,
+		Latency: *latency,
+	})
+
+	addr := fmt.Sprintf(":%d", *port)
+	fmt.Printf("Mock DWZ API Server starting on %s\n", addr)
+	fmt.Println("Endpoints available:")
+	for _, endpoint := range testserver.Endpoints() {
+		fmt.Printf("  %s\n", endpoint)
+	}
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Fatal(httpServer.ListenAndServe())
+}
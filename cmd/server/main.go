@@ -1,28 +1,66 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"regexp"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/svw-info/portal64gomcp/internal/api"
 	"github.com/svw-info/portal64gomcp/internal/config"
 	"github.com/svw-info/portal64gomcp/internal/mcp"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
 )
 
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
 var (
 	configPath = flag.String("config", "", "Path to configuration file")
 	logLevel   = flag.String("log-level", "", "Log level (debug, info, warn, error)")
+	envRef     = flag.Bool("env-reference", false, "Print the environment variables of all configuration keys as markdown and exit")
+	strict     = flag.Bool("strict-config", false, "Reject unknown keys in the configuration file")
+	validate   = flag.Bool("validate-config", false, "Validate the configuration, report all problems and exit")
+	schema     = flag.Bool("config-schema", false, "Print the JSON schema of the configuration file and exit")
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+		os.Exit(runSelfTest(os.Args[2:]))
+	}
+
 	flag.Parse()
 
+	if *envRef {
+		if err := config.WriteEnvReference(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write environment reference: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *schema {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(config.JSONSchema()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write configuration schema: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *validate {
+		os.Exit(validateConfig(*configPath))
+	}
+
 	// Load configuration
-	cfg, err := config.Load(*configPath)
+	cfg, err := config.LoadWithOptions(*configPath, config.LoadOptions{Strict: *strict})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
@@ -44,26 +82,107 @@ func main() {
 
 	logger.WithFields(logrus.Fields{
 		"api_url":   cfg.API.BaseURL,
+		"fallbacks": len(cfg.API.FallbackURLs),
 		"timeout":   cfg.API.Timeout,
 		"log_level": cfg.Logger.Level,
 		"mode":      cfg.MCP.Mode,
 		"http_port": cfg.MCP.HTTPPort,
 	}).Info("Starting Portal64 MCP Server")
 
+	if cfg.File == "" {
+		logger.Info("No configuration file found, using environment variables and defaults")
+	} else {
+		logger.WithField("file", cfg.File).Info("Loaded configuration file")
+	}
+	logger.WithFields(logrus.Fields(cfg.EffectiveSettings())).Info("Effective configuration")
+
 	// Create API client
-	apiClient := api.NewClient(cfg.API.BaseURL, cfg.API.Timeout, logger)
+	apiClient, err := newAPIClient(cfg.API, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid Portal64 API configuration")
+	}
 
 	// Create MCP server
 	server := mcp.NewServer(cfg, logger, apiClient)
 
-	// Setup graceful shutdown
+	// Create the API clients of the upstream profiles
+	profileClients := map[string]*api.Client{config.DefaultProfile: apiClient}
+	for name := range cfg.API.Profiles {
+		profileConfig, _ := cfg.API.Profile(name)
+		profileClient, err := newAPIClient(profileConfig, logger.WithField("profile", name))
+		if err != nil {
+			logger.WithError(err).WithField("profile", name).Fatal("Invalid Portal64 API configuration")
+		}
+		if err := server.AddProfile(name, profileClient); err != nil {
+			logger.WithError(err).Fatal("Failed to add upstream profile")
+		}
+		profileClients[name] = profileClient
+		logger.WithFields(logrus.Fields{
+			"profile": name,
+			"api_url": profileConfig.BaseURL,
+			"timeout": profileConfig.Timeout,
+		}).Info("Added upstream profile")
+	}
+
+	// Record upstream responses deviating from the models, in one log
+	// shared by all profiles
+	if cfg.API.Anomalies.Enabled {
+		anomalyLog, err := api.NewAnomalyLog(cfg.API.Anomalies.LogFile, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to open API anomaly log")
+		}
+		defer anomalyLog.Close()
+		for _, client := range profileClients {
+			client.SetAnomalyLog(anomalyLog)
+		}
+	}
+
+	// Report errors to the error tracker
+	if tracker := setupErrorTracker(cfg.Telemetry.Errors, logger); tracker != nil {
+		server.SetErrorReporter(tracker)
+		for _, client := range profileClients {
+			client.OnServerError(tracker.ReportUpstreamError)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := tracker.Close(ctx); err != nil {
+				logger.WithError(err).Warn("Failed to send pending error events")
+			}
+		}()
+	}
+
+	// Notify operators through Slack or a webhook
+	if alerter := setupAlerter(cfg.Telemetry.Alerts, logger); alerter != nil {
+		server.SetAlerter(alerter)
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := alerter.Close(ctx); err != nil {
+				logger.WithError(err).Warn("Failed to send pending alerts")
+			}
+		}()
+	}
+
+	// Setup graceful shutdown. On a handover signal a new process takes
+	// over the listening socket before this one drains.
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, append([]os.Signal{syscall.SIGINT, syscall.SIGTERM}, handoverSignals...)...)
 
 	go func() {
-		sig := <-sigChan
-		logger.WithField("signal", sig).Info("Received shutdown signal")
-		server.Stop()
+		for sig := range sigChan {
+			if isHandoverSignal(sig) {
+				logger.WithField("signal", sig).Info("Received handover signal")
+				if err := server.Handover(); err != nil {
+					logger.WithError(err).Error("Handover failed, continuing to serve")
+					continue
+				}
+			} else {
+				logger.WithField("signal", sig).Info("Received shutdown signal")
+			}
+			server.Stop()
+			return
+		}
 	}()
 
 	// Start server
@@ -75,6 +194,220 @@ func main() {
 	logger.Info("MCP server stopped")
 }
 
+// newAPIClient creates a Portal64 API client with the connection, TLS,
+// authentication, signing, cache and failover settings of the configuration
+func newAPIClient(cfg config.APIConfig, logger api.Logger) (*api.Client, error) {
+	client := api.NewClient(cfg.BaseURL, cfg.Timeout, logger)
+	client.SetReadOnly(cfg.ReadOnly)
+	client.ConfigureConnection(api.ConnectionOptions{
+		DisableHTTP2:        !cfg.Connection.HTTP2,
+		DisableKeepAlives:   !cfg.Connection.KeepAlive,
+		KeepAliveInterval:   cfg.Connection.KeepAliveInterval,
+		IdleTimeout:         cfg.Connection.IdleTimeout,
+		MaxIdleConnsPerHost: cfg.Connection.MaxIdleConnsPerHost,
+	})
+	if err := client.ConfigureTLS(api.TLSOptions{
+		CAFile:             cfg.SSL.CAFile,
+		ClientCert:         cfg.SSL.ClientCert,
+		ClientKey:          cfg.SSL.ClientKey,
+		InsecureSkipVerify: cfg.SSL.InsecureSkipVerify,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	if err := detectScheme(client, cfg, logger); err != nil {
+		return nil, err
+	}
+	if err := client.ConfigureAuth(api.AuthOptions{
+		Type:         cfg.Auth.Type,
+		Header:       cfg.Auth.Header,
+		APIKey:       cfg.Auth.APIKey,
+		Username:     cfg.Auth.Username,
+		Password:     cfg.Auth.Password,
+		TokenURL:     cfg.Auth.OAuth2.TokenURL,
+		ClientID:     cfg.Auth.OAuth2.ClientID,
+		ClientSecret: cfg.Auth.OAuth2.ClientSecret,
+		Scopes:       cfg.Auth.OAuth2.Scopes,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid authentication configuration: %w", err)
+	}
+	if cfg.Signing.Algorithm != "" {
+		if err := client.ConfigureRequestSigning(api.RequestSigningOptions{
+			Key:           cfg.Signing.Key,
+			KeyID:         cfg.Signing.KeyID,
+			PreviousKey:   cfg.Signing.PreviousKey,
+			PreviousKeyID: cfg.Signing.PreviousKeyID,
+			Header:        cfg.Signing.Header,
+			ClockSkew:     cfg.Signing.ClockSkew,
+		}); err != nil {
+			return nil, fmt.Errorf("invalid request signing configuration: %w", err)
+		}
+	}
+	policies := make(map[string]api.CachePolicy)
+	for entity, policy := range cfg.Cache.Policies.ByEntity() {
+		policies[entity] = api.CachePolicy{TTL: policy.TTL, Stale: policy.Stale}
+	}
+	client.ConfigureCache(api.CacheOptions{Policies: policies, MaxEntries: cfg.Cache.MaxEntries, NotFoundTTL: cfg.Cache.NotFoundTTL})
+	windows, err := cfg.Maintenance.ParseWindows()
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance configuration: %w", err)
+	}
+	maintenance := api.MaintenanceOptions{Windows: windows, Hold: cfg.Maintenance.Hold}
+	if cfg.Maintenance.Pattern != "" {
+		if maintenance.Pattern, err = regexp.Compile(cfg.Maintenance.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid maintenance configuration: %w", err)
+		}
+	}
+	client.ConfigureMaintenance(maintenance)
+	if err := client.ConfigureFailover(api.FailoverOptions{
+		FallbackURLs:     cfg.FallbackURLs,
+		FailureThreshold: cfg.Failover.FailureThreshold,
+		Cooldown:         cfg.Failover.Cooldown,
+	}); err != nil {
+		return nil, fmt.Errorf("invalid failover configuration: %w", err)
+	}
+	return client, nil
+}
+
+// detectScheme probes the scheme and port of the base URL. A base URL that
+// does not answer while another scheme or port of its host does is
+// corrected or rejected, depending on the mode. An upstream that does not
+// answer at all only logs a warning, it may come up later.
+func detectScheme(client *api.Client, cfg config.APIConfig, logger api.Logger) error {
+	mode := cfg.SchemeDetection.Mode
+	if mode == "" || mode == "off" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*cfg.SchemeDetection.Timeout)
+	defer cancel()
+	detection, err := client.DetectScheme(ctx, cfg.SchemeDetection.Candidates, cfg.SchemeDetection.Timeout)
+	switch {
+	case err != nil:
+		return fmt.Errorf("invalid scheme detection configuration: %w", err)
+	case detection.Detected == "":
+		logger.Warnf("Portal64 API does not answer at startup: %s", detection.Diagnostic())
+	case !detection.Mismatch():
+	case mode == "fail":
+		return fmt.Errorf("wrong Portal64 API base URL: %s", detection.Diagnostic())
+	default:
+		logger.WithField("base_url", detection.Detected).Warnf("Using the detected Portal64 API base URL: %s", detection.Diagnostic())
+		client.SetBaseURL(detection.Detected)
+	}
+	return nil
+}
+
+// validateConfig checks the configuration file against the configuration
+// schema, which rejects unknown keys, and validates the loaded
+// configuration. It prints all problems and returns the process exit code.
+func validateConfig(path string) int {
+	cfg, problems := checkConfig(path)
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+
+	source := cfg.File
+	if source == "" {
+		source = "environment variables and defaults"
+	}
+	fmt.Printf("Configuration from %s is valid\n", source)
+	return 0
+}
+
+// checkConfig loads the configuration and checks the configuration file
+// against the configuration schema and the loaded configuration. It returns
+// the configuration, nil if it cannot be loaded, and all problems found.
+func checkConfig(path string) (*config.Config, []string) {
+	cfg, loadErr := config.Load(path)
+	file := path
+	if cfg != nil {
+		file = cfg.File
+	}
+
+	var problems []string
+	if file != "" {
+		errs, err := config.CheckFile(file)
+		if err != nil {
+			errs = []error{err}
+		}
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("%s: %v", file, err))
+		}
+	}
+
+	switch {
+	case loadErr != nil:
+		problems = append(problems, fmt.Sprintf("Failed to load configuration: %v", loadErr))
+	case len(problems) == 0:
+		// Semantic checks only make sense for a well-formed file
+		if err := cfg.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("Invalid configuration: %v", err))
+		}
+	}
+	return cfg, problems
+}
+
+// setupErrorTracker creates the error tracker, or returns nil if error
+// tracking is not configured
+func setupErrorTracker(cfg config.ErrorTrackingConfig, logger *logrus.Logger) *telemetry.ErrorTracker {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	release := cfg.Release
+	if release == "" {
+		release = version
+	}
+	tracker, err := telemetry.NewErrorTracker(telemetry.Options{
+		DSN:            cfg.DSN,
+		Endpoint:       cfg.Endpoint,
+		Environment:    cfg.Environment,
+		Release:        release,
+		Timeout:        cfg.Timeout,
+		BurstThreshold: cfg.UpstreamBurstThreshold,
+		BurstWindow:    cfg.UpstreamBurstWindow,
+	}, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid error tracker configuration")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"environment": cfg.Environment,
+		"release":     release,
+	}).Info("Error tracking enabled")
+	return tracker
+}
+
+// setupAlerter creates the alerter, or returns nil if alerting is not
+// configured
+func setupAlerter(cfg config.AlertsConfig, logger *logrus.Logger) *telemetry.Alerter {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	alerter, err := telemetry.NewAlerter(telemetry.AlertOptions{
+		SlackWebhookURL:        cfg.SlackWebhookURL,
+		WebhookURL:             cfg.WebhookURL,
+		Template:               cfg.Template,
+		Kinds:                  cfg.Events,
+		Cooldown:               cfg.Cooldown,
+		Timeout:                cfg.Timeout,
+		UpstreamErrorThreshold: cfg.UpstreamErrorThreshold,
+		UpstreamErrorWindow:    cfg.UpstreamErrorWindow,
+	}, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid alerting configuration")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"slack":   cfg.SlackWebhookURL != "",
+		"webhook": cfg.WebhookURL != "",
+	}).Info("Alerting enabled")
+	return alerter
+}
+
 // setupLogger configures the logger based on configuration
 func setupLogger(cfg config.LoggerConfig) *logrus.Logger {
 	logger := logrus.New()
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
 	"github.com/svw-info/portal64gomcp/internal/config"
 	"github.com/svw-info/portal64gomcp/test/testutil"
 )
@@ -208,7 +214,9 @@ this is not valid yaml
 						Timeout: 30 * time.Second,
 					},
 					MCP: config.MCPConfig{
-						Port: tc.port,
+						Port:     tc.port,
+						Mode:     "stdio",
+						HTTPPort: 8888,
 					},
 				}
 				
@@ -334,3 +342,27 @@ func BenchmarkConfigurationValidation(b *testing.B) {
 		}
 	})
 }
+
+func TestDetectScheme(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer upstream.Close()
+	wrongScheme := "https://" + strings.TrimPrefix(upstream.URL, "http://")
+	logger := logrus.New()
+
+	cfg := config.APIConfig{
+		BaseURL:         wrongScheme,
+		SchemeDetection: config.APISchemeDetectionConfig{Mode: "fail", Timeout: time.Second},
+	}
+	client := api.NewClient(cfg.BaseURL, time.Second, logger)
+	err := detectScheme(client, cfg, logger)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), upstream.URL+" answers, set api.base_url to it")
+
+	cfg.SchemeDetection.Mode = "correct"
+	client = api.NewClient(cfg.BaseURL, time.Second, logger)
+	require.NoError(t, detectScheme(client, cfg, logger))
+	_, err = client.Health(context.Background())
+	assert.NoError(t, err, "requests go to the detected base URL")
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/mcp"
+)
+
+// Statuses of self-test checks
+const (
+	checkOK      = "ok"
+	checkWarning = "warning"
+	checkFailed  = "failed"
+	checkSkipped = "skipped"
+)
+
+// selfTestCheck is the result of a check of the selftest command
+type selfTestCheck struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Details []string `json:"details,omitempty"`
+}
+
+// selfTestReport is the output of the selftest command
+type selfTestReport struct {
+	Passed bool            `json:"passed"`
+	Checks []selfTestCheck `json:"checks"`
+}
+
+// selfTestOptions controls the checks of the selftest command
+type selfTestOptions struct {
+	ConfigPath   string
+	Timeout      time.Duration // Of the upstream health checks
+	SkipUpstream bool
+	Logger       *logrus.Logger
+}
+
+// runSelfTest checks that the server can start with its configuration: the
+// configuration, the TLS files, the writability of the log and store
+// directories, the reachability of the Portal64 API of all profiles and the
+// consistency of the tool registry. It prints a report and returns the
+// process exit code: 1 if a check failed, 2 on invalid arguments. Warnings
+// do not fail the self-test.
+func runSelfTest(args []string) int {
+	flags := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	configFile := flags.String("config", "", "Path to configuration file")
+	format := flags.String("format", "text", "Report format (text, json)")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout of the health check of each upstream")
+	skipUpstream := flags.Bool("skip-upstream", false, "Do not connect to the Portal64 API")
+	verbose := flags.Bool("v", false, "Print the server logs to stderr")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Invalid report format %q, expected text or json\n", *format)
+		return 2
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	if *verbose {
+		logger.SetOutput(os.Stderr)
+	}
+
+	report := selfTest(selfTestOptions{
+		ConfigPath:   *configFile,
+		Timeout:      *timeout,
+		SkipUpstream: *skipUpstream,
+		Logger:       logger,
+	})
+	var err error
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeSelfTestReport(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		return 2
+	}
+
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
+
+// selfTest runs the checks of the selftest command. Checks that need the
+// configuration are skipped if it cannot be loaded.
+func selfTest(opts selfTestOptions) selfTestReport {
+	cfg, problems := checkConfig(opts.ConfigPath)
+	configCheck := selfTestCheck{Name: "config", Status: checkOK, Details: problems}
+	if len(problems) > 0 {
+		configCheck.Status = checkFailed
+	} else if cfg.File == "" {
+		configCheck.Details = []string{"no configuration file found, using environment variables and defaults"}
+	} else {
+		configCheck.Details = []string{"loaded " + cfg.File}
+	}
+
+	checks := []selfTestCheck{configCheck}
+	if cfg == nil {
+		for _, name := range []string{"tls", "directories", "upstream"} {
+			checks = append(checks, selfTestCheck{Name: name, Status: checkSkipped, Details: []string{"the configuration cannot be loaded"}})
+		}
+	} else {
+		checks = append(checks, checkTLSFiles(cfg.API.SSL), checkDirectories(cfg))
+		if opts.SkipUpstream {
+			checks = append(checks, selfTestCheck{Name: "upstream", Status: checkSkipped, Details: []string{"-skip-upstream is set"}})
+		} else {
+			checks = append(checks, checkUpstreams(cfg, opts.Timeout, opts.Logger))
+		}
+	}
+	checks = append(checks, checkToolRegistry())
+
+	report := selfTestReport{Passed: true, Checks: checks}
+	for _, check := range checks {
+		if check.Status == checkFailed {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+// checkTLSFiles checks the files of api.ssl like check_ssl_config. Errors
+// fail the check.
+func checkTLSFiles(ssl config.APISSLConfig) selfTestCheck {
+	result := api.CheckTLS(api.TLSOptions{
+		CAFile:             ssl.CAFile,
+		ClientCert:         ssl.ClientCert,
+		ClientKey:          ssl.ClientKey,
+		InsecureSkipVerify: ssl.InsecureSkipVerify,
+	}, time.Now())
+
+	check := selfTestCheck{Name: "tls", Status: checkOK}
+	switch result.Status {
+	case api.SeverityError:
+		check.Status = checkFailed
+	case api.SeverityWarning:
+		check.Status = checkWarning
+	}
+	for _, finding := range result.Findings {
+		detail := finding.Severity + ": " + finding.Message
+		if finding.File != "" {
+			detail = finding.Severity + ": " + finding.File + ": " + finding.Message
+		}
+		if finding.Action != "" {
+			detail += " (" + finding.Action + ")"
+		}
+		check.Details = append(check.Details, detail)
+	}
+	return check
+}
+
+// checkDirectories checks that the server can create files in the
+// directories of the anomaly log, the sampled log directory and the history
+// store
+func checkDirectories(cfg *config.Config) selfTestCheck {
+	dirs := make(map[string]string)
+	if cfg.API.Anomalies.Enabled && cfg.API.Anomalies.LogFile != "" {
+		dirs["api.anomalies.log_file"] = filepath.Dir(cfg.API.Anomalies.LogFile)
+	}
+	if cfg.Telemetry.System.LogDir != "" {
+		dirs["telemetry.system.log_dir"] = cfg.Telemetry.System.LogDir
+	}
+	if cfg.Store.Path != "" {
+		dirs["store.path"] = filepath.Dir(cfg.Store.Path)
+	}
+
+	check := selfTestCheck{Name: "directories", Status: checkOK}
+	if len(dirs) == 0 {
+		check.Status = checkSkipped
+		check.Details = []string{"no log files, log directory or store configured, logs are written to stderr"}
+		return check
+	}
+	keys := make([]string, 0, len(dirs))
+	for key := range dirs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := checkWritable(dirs[key]); err != nil {
+			check.Status = checkFailed
+			check.Details = append(check.Details, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		check.Details = append(check.Details, fmt.Sprintf("%s: %s is writable", key, dirs[key]))
+	}
+	return check
+}
+
+// checkWritable creates and removes a file in a directory
+func checkWritable(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	file, err := os.CreateTemp(dir, ".portal64gomcp-selftest-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkUpstreams checks the health of the Portal64 API of the default and
+// every other upstream profile. Degraded upstreams are warnings.
+func checkUpstreams(cfg *config.Config, timeout time.Duration, logger *logrus.Logger) selfTestCheck {
+	names := []string{config.DefaultProfile}
+	for name := range cfg.API.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	check := selfTestCheck{Name: "upstream", Status: checkOK}
+	for _, name := range names {
+		profile, ok := cfg.API.Profile(name)
+		if !ok {
+			profile = cfg.API
+		}
+		client, err := newAPIClient(profile, logger.WithField("profile", name))
+		if err != nil {
+			check.Status = checkFailed
+			check.Details = append(check.Details, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		health, err := client.Health(ctx)
+		cancel()
+		switch {
+		case err != nil:
+			check.Status = checkFailed
+			check.Details = append(check.Details, fmt.Sprintf("%s: %s does not answer: %v", name, profile.BaseURL, err))
+		case health.Status == "unhealthy":
+			check.Status = checkFailed
+			check.Details = append(check.Details, fmt.Sprintf("%s: %s is unhealthy", name, profile.BaseURL))
+		default:
+			if health.Status == "degraded" && check.Status == checkOK {
+				check.Status = checkWarning
+			}
+			check.Details = append(check.Details, fmt.Sprintf("%s: %s is %s (%d ms)", name, profile.BaseURL, health.Status, health.ResponseTime))
+		}
+	}
+	return check
+}
+
+// checkToolRegistry checks that every registered tool has a schema and
+// every schema a registered tool
+func checkToolRegistry() selfTestCheck {
+	check := selfTestCheck{Name: "tool_registry", Status: checkOK, Details: mcp.CheckToolRegistry()}
+	if len(check.Details) > 0 {
+		check.Status = checkFailed
+	}
+	return check
+}
+
+// writeSelfTestReport writes the self-test report as text, a line per check
+// followed by its details
+func writeSelfTestReport(w io.Writer, report selfTestReport) error {
+	var b strings.Builder
+	failed := 0
+	for _, check := range report.Checks {
+		fmt.Fprintf(&b, "%-8s %s\n", strings.ToUpper(check.Status), check.Name)
+		for _, detail := range check.Details {
+			fmt.Fprintf(&b, "         %s\n", detail)
+		}
+		if check.Status == checkFailed {
+			failed++
+		}
+	}
+	if report.Passed {
+		b.WriteString("Self-test passed\n")
+	} else {
+		fmt.Fprintf(&b, "Self-test failed: %d of %d checks failed\n", failed, len(report.Checks))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/test/testutil"
+)
+
+func TestSelfTest(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "degraded", "response_time": 12}`))
+	}))
+	defer upstream.Close()
+	dir := t.TempDir()
+
+	configFile := testutil.CreateTempConfigFile(t, `
+api:
+  base_url: "`+upstream.URL+`"
+  scheme_detection:
+    mode: "off"
+  anomalies:
+    log_file: "`+filepath.Join(dir, "missing", "anomalies.jsonl")+`"
+store:
+  path: "`+filepath.Join(dir, "history.json")+`"
+`)
+	logger := logrus.New()
+	report := selfTest(selfTestOptions{ConfigPath: configFile, Timeout: 5 * time.Second, Logger: logger})
+
+	checks := make(map[string]selfTestCheck)
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	require.Len(t, checks, 5)
+	assert.Equal(t, checkOK, checks["config"].Status, checks["config"].Details)
+	assert.Equal(t, checkOK, checks["tls"].Status)
+	assert.Equal(t, checkOK, checks["tool_registry"].Status, checks["tool_registry"].Details)
+
+	upstreamCheck := checks["upstream"]
+	assert.Equal(t, checkWarning, upstreamCheck.Status, "degraded upstreams are warnings")
+	assert.Equal(t, []string{"default: " + upstream.URL + " is degraded (12 ms)"}, upstreamCheck.Details)
+
+	directories := checks["directories"]
+	assert.Equal(t, checkFailed, directories.Status)
+	require.Len(t, directories.Details, 2)
+	assert.Contains(t, directories.Details[0], "api.anomalies.log_file: stat ")
+	assert.Equal(t, "store.path: "+dir+" is writable", directories.Details[1])
+	assert.False(t, report.Passed)
+
+	var text strings.Builder
+	require.NoError(t, writeSelfTestReport(&text, report))
+	assert.Contains(t, text.String(), "WARNING  upstream\n")
+	assert.Contains(t, text.String(), "Self-test failed: 1 of 5 checks failed\n")
+
+	// Without a loadable configuration the dependent checks are skipped
+	report = selfTest(selfTestOptions{ConfigPath: filepath.Join(dir, "missing.yaml"), SkipUpstream: true, Logger: logger})
+	assert.False(t, report.Passed)
+	assert.Equal(t, checkFailed, report.Checks[0].Status)
+	assert.Equal(t, checkSkipped, report.Checks[1].Status)
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// handoverSignals is empty, handover needs Unix signals
+var handoverSignals []os.Signal
+
+func isHandoverSignal(sig os.Signal) bool {
+	return false
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// handoverSignals make the server hand its listening socket over to a new
+// process and drain
+var handoverSignals = []os.Signal{syscall.SIGUSR2}
+
+func isHandoverSignal(sig os.Signal) bool {
+	return sig == syscall.SIGUSR2
+}
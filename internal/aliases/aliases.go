@@ -0,0 +1,184 @@
+// Package aliases maps historic club IDs to the IDs of the clubs they were
+// merged into or renamed to.
+package aliases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// Alias records that a club ID was replaced by another
+type Alias struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Date   string `json:"date,omitempty"`   // YYYY-MM-DD
+	Reason string `json:"reason,omitempty"` // e.g. "merger" or "renaming"
+	Name   string `json:"name,omitempty"`   // Name of the club under the old ID
+}
+
+// Map holds the aliases by their old club ID. A nil Map has no aliases.
+type Map struct {
+	aliases map[string]Alias
+}
+
+// New builds a map of aliases. IDs are normalized; an ID replaced twice, an
+// alias to itself and chains leading back to their start are rejected.
+func New(list []Alias) (*Map, error) {
+	m := &Map{aliases: make(map[string]Alias, len(list))}
+	for i, alias := range list {
+		var err error
+		if alias.From, err = api.NormalizeClubID(alias.From); err != nil {
+			return nil, fmt.Errorf("alias %d: from: %w", i+1, err)
+		}
+		if alias.To, err = api.NormalizeClubID(alias.To); err != nil {
+			return nil, fmt.Errorf("alias %d: to: %w", i+1, err)
+		}
+		if alias.From == alias.To {
+			return nil, fmt.Errorf("alias %d: %s is an alias of itself", i+1, alias.From)
+		}
+		if alias.Date != "" {
+			if _, err := time.Parse("2006-01-02", alias.Date); err != nil {
+				return nil, fmt.Errorf("alias %d: invalid date %q, expected YYYY-MM-DD", i+1, alias.Date)
+			}
+		}
+		if _, exists := m.aliases[alias.From]; exists {
+			return nil, fmt.Errorf("alias %d: %s is replaced more than once", i+1, alias.From)
+		}
+		m.aliases[alias.From] = alias
+	}
+	if err := m.checkCycles(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// checkCycles rejects chains of aliases that lead back to their start
+func (m *Map) checkCycles() error {
+	for from := range m.aliases {
+		id := from
+		for steps := 0; ; steps++ {
+			alias, ok := m.aliases[id]
+			if !ok {
+				break
+			}
+			if steps >= len(m.aliases) {
+				return fmt.Errorf("the aliases of %s form a cycle", from)
+			}
+			id = alias.To
+		}
+	}
+	return nil
+}
+
+// Parse reads a JSON list of aliases
+func Parse(data []byte) (*Map, error) {
+	var list []Alias
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid alias list: %w", err)
+	}
+	return New(list)
+}
+
+// LoadFile reads a JSON list of aliases from a file
+func LoadFile(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read club aliases: %w", err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// maxFetchBytes bounds the size of an alias list fetched from a URL
+const maxFetchBytes = 10 << 20
+
+// Fetch downloads a JSON list of aliases
+func Fetch(ctx context.Context, client *http.Client, url string) (*Map, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch club aliases: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch club aliases: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read club aliases: %w", err)
+	}
+	return Parse(data)
+}
+
+// Merge combines maps, aliases of later maps replacing those of earlier
+// ones with the same old ID
+func Merge(maps ...*Map) (*Map, error) {
+	var list []Alias
+	seen := make(map[string]int)
+	for _, m := range maps {
+		for _, alias := range m.List() {
+			if i, ok := seen[alias.From]; ok {
+				list[i] = alias
+				continue
+			}
+			seen[alias.From] = len(list)
+			list = append(list, alias)
+		}
+	}
+	return New(list)
+}
+
+// Resolve follows the aliases of a club ID. It returns the current ID and
+// the aliases followed in order, none if the ID is current.
+func (m *Map) Resolve(id string) (string, []Alias) {
+	if m == nil {
+		return id, nil
+	}
+	var chain []Alias
+	for {
+		alias, ok := m.aliases[id]
+		if !ok {
+			return id, chain
+		}
+		chain = append(chain, alias)
+		id = alias.To
+	}
+}
+
+// List returns the aliases ordered by old ID
+func (m *Map) List() []Alias {
+	if m == nil {
+		return nil
+	}
+	list := make([]Alias, 0, len(m.aliases))
+	for _, alias := range m.aliases {
+		list = append(list, alias)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].From < list[j].From })
+	return list
+}
+
+// Len returns the number of aliases
+func (m *Map) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.aliases)
+}
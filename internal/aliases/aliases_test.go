@@ -0,0 +1,108 @@
+package aliases
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	m, err := New([]Alias{
+		{From: "c100", To: "C0200", Date: "2010-01-01", Reason: "merger"},
+		{From: "C0200", To: "C0300", Reason: "renaming"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, m.Len())
+
+	current, chain := m.Resolve("C0100")
+	assert.Equal(t, "C0300", current)
+	require.Len(t, chain, 2)
+	assert.Equal(t, "C0100", chain[0].From)
+	assert.Equal(t, "C0200", chain[1].From)
+
+	current, chain = m.Resolve("C0300")
+	assert.Equal(t, "C0300", current)
+	assert.Empty(t, chain)
+
+	var none *Map
+	current, chain = none.Resolve("C0100")
+	assert.Equal(t, "C0100", current)
+	assert.Empty(t, chain)
+	assert.Zero(t, none.Len())
+}
+
+func TestNew_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		aliases []Alias
+		err     string
+	}{
+		{"invalid ID", []Alias{{From: "Altbach", To: "C0200"}}, "alias 1: from: "},
+		{"alias of itself", []Alias{{From: "C0100", To: "c100"}}, "alias 1: C0100 is an alias of itself"},
+		{"invalid date", []Alias{{From: "C0100", To: "C0200", Date: "01.07.2019"}}, `alias 1: invalid date "01.07.2019", expected YYYY-MM-DD`},
+		{"replaced twice", []Alias{{From: "C0100", To: "C0200"}, {From: "C0100", To: "C0300"}}, "alias 2: C0100 is replaced more than once"},
+		{"cycle", []Alias{{From: "C0100", To: "C0200"}, {From: "C0200", To: "C0100"}}, "form a cycle"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.aliases)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	upstream, err := Parse([]byte(`[{"from":"C0100","to":"C0200"},{"from":"C0101","to":"C0201"}]`))
+	require.NoError(t, err)
+	local, err := Parse([]byte(`[{"from":"C0100","to":"C0300","reason":"merger"}]`))
+	require.NoError(t, err)
+
+	merged, err := Merge(upstream, local, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []Alias{
+		{From: "C0100", To: "C0300", Reason: "merger"},
+		{From: "C0101", To: "C0201"},
+	}, merged.List())
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"from":"C0100","to":"C0200"}]`), 0o600))
+	m, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, m.Len())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"from":"C0100"}`), 0o600))
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, "invalid alias list")
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read club aliases")
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/aliases.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[{"from":"C0100","to":"C0200"}]`))
+	}))
+	defer server.Close()
+
+	m, err := Fetch(context.Background(), server.Client(), server.URL+"/aliases.json")
+	require.NoError(t, err)
+	current, _ := m.Resolve("C0100")
+	assert.Equal(t, "C0200", current)
+
+	_, err = Fetch(context.Background(), server.Client(), server.URL+"/missing.json")
+	assert.EqualError(t, err, "failed to fetch club aliases: HTTP 404")
+}
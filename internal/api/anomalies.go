@@ -0,0 +1,447 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Kinds of upstream response anomalies
+const (
+	AnomalyUnknownField = "unknown_field"
+	AnomalyMissingField = "missing_field"
+	AnomalyTypeMismatch = "type_mismatch"
+)
+
+const (
+	// maxAnomalies bounds the anomalies kept in memory, the oldest are
+	// dropped first
+	maxAnomalies = 500
+	// maxAnomalyItems bounds the list items checked per response
+	maxAnomalyItems = 20
+)
+
+// requiredFields are the fields a response object must have, by model type
+var requiredFields = map[reflect.Type][]string{
+	reflect.TypeOf(PlayerResponse{}):     {"id", "name"},
+	reflect.TypeOf(ClubResponse{}):       {"id", "name"},
+	reflect.TypeOf(TournamentResponse{}): {"id", "name"},
+}
+
+// aliasFields are the fields accepted by custom decoders besides the model
+// fields, by model type
+var aliasFields = map[reflect.Type][]string{
+	reflect.TypeOf(PaginationMetadata{}): {"total_count", "total_items", "per_page", "page_size", "total_pages", "current_page"},
+}
+
+// Anomaly is a deviation of upstream responses from the expected schema.
+// Repeated deviations of an endpoint and field are counted in one entry.
+type Anomaly struct {
+	Endpoint  string    `json:"endpoint"` // Request path with IDs replaced by {id}
+	Kind      string    `json:"kind"`
+	Field     string    `json:"field"` // e.g. "club_id" or "[].rating_stats.average_dwz"
+	Expected  string    `json:"expected,omitempty"`
+	Actual    string    `json:"actual,omitempty"`
+	Count     int64     `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// AnomalyLog records the anomalies of upstream responses. Each new anomaly
+// is logged and, with a log file, appended to it as a JSON line, so that the
+// file is a changelog of the upstream API as seen by the server.
+type AnomalyLog struct {
+	logger  Logger
+	mu      sync.Mutex
+	entries []*Anomaly
+	index   map[string]*Anomaly
+	file    *os.File
+}
+
+// NewAnomalyLog creates an anomaly log appending to the file at path, or
+// keeping anomalies in memory only if path is empty
+func NewAnomalyLog(path string, logger Logger) (*AnomalyLog, error) {
+	log := &AnomalyLog{logger: logger, index: make(map[string]*Anomaly)}
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening anomaly log: %w", err)
+		}
+		log.file = file
+	}
+	return log, nil
+}
+
+// Close closes the log file
+func (l *AnomalyLog) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Anomalies returns the recorded anomalies in the order they were first seen
+func (l *AnomalyLog) Anomalies() []Anomaly {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	anomalies := make([]Anomaly, len(l.entries))
+	for i, entry := range l.entries {
+		anomalies[i] = *entry
+	}
+	return anomalies
+}
+
+// record adds the anomalies found in a response
+func (l *AnomalyLog) record(found []Anomaly) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for _, anomaly := range found {
+		key := anomaly.Endpoint + " " + anomaly.Kind + " " + anomaly.Field
+		if entry, ok := l.index[key]; ok {
+			entry.Count++
+			entry.LastSeen = now
+			continue
+		}
+
+		entry := anomaly
+		entry.Count, entry.FirstSeen, entry.LastSeen = 1, now, now
+		if len(l.entries) >= maxAnomalies {
+			oldest := l.entries[0]
+			delete(l.index, oldest.Endpoint+" "+oldest.Kind+" "+oldest.Field)
+			l.entries = l.entries[1:]
+		}
+		l.entries = append(l.entries, &entry)
+		l.index[key] = &entry
+
+		if l.logger != nil {
+			l.logger.WithFields(map[string]interface{}{
+				"endpoint": entry.Endpoint,
+				"kind":     entry.Kind,
+				"field":    entry.Field,
+				"expected": entry.Expected,
+				"actual":   entry.Actual,
+			}).Warn("Upstream API response deviates from the expected schema")
+		}
+		if l.file != nil {
+			if data, err := json.Marshal(entry); err == nil {
+				l.file.Write(append(data, '\n'))
+			}
+		}
+	}
+}
+
+// SetAnomalyLog sets the log that deviations of responses from the models
+// are recorded in. Several clients may share a log.
+func (c *Client) SetAnomalyLog(log *AnomalyLog) {
+	c.anomalies = log
+}
+
+// AnomalyLog returns the anomaly log of the client, nil if none is set
+func (c *Client) AnomalyLog() *AnomalyLog {
+	return c.anomalies
+}
+
+// maxResponseWarnings bounds the warnings reported per response
+const maxResponseWarnings = 5
+
+type warningsKey struct{}
+
+// WithWarnings returns a context whose API requests report deviations of
+// the response data from the models to warn: unknown and missing fields,
+// values of the wrong type and values that cannot be decoded. Such
+// responses are decoded as far as possible instead of failing.
+func WithWarnings(ctx context.Context, warn func(warning string)) context.Context {
+	return context.WithValue(ctx, warningsKey{}, warn)
+}
+
+// checkAnomalies compares response data with the model type it is decoded
+// into, records the deviations and reports them, with the problems found
+// while decoding, as warnings of the request. Problems are logged as well,
+// deviations are logged by the anomaly log when first seen.
+func (c *Client) checkAnomalies(resp *http.Response, data json.RawMessage, t reflect.Type, problems []string) {
+	if resp.Request == nil {
+		return
+	}
+	endpoint := anomalyEndpoint(resp.Request.URL.Path)
+	for _, problem := range problems {
+		c.logger.WithField("endpoint", endpoint).Warnf("Upstream API response decoded partially: %s", problem)
+	}
+	warn, _ := resp.Request.Context().Value(warningsKey{}).(func(string))
+	if c.anomalies == nil && warn == nil {
+		return
+	}
+
+	var found []Anomaly
+	if value, err := sampleJSON(data); err == nil {
+		found = findAnomalies(value, t, "")
+	}
+	for i := range found {
+		found[i].Endpoint = endpoint
+	}
+	if c.anomalies != nil && len(found) > 0 {
+		c.anomalies.record(found)
+	}
+	if warn == nil {
+		return
+	}
+
+	var warnings []string
+	for _, anomaly := range found {
+		// Whole items of the wrong type are skipped and reported as problems
+		if anomaly.Kind == AnomalyTypeMismatch && (anomaly.Field == "" || anomaly.Field == "[]") {
+			continue
+		}
+		warnings = append(warnings, anomaly.describe())
+	}
+	slices.Sort(warnings)
+	for _, problem := range problems {
+		// Values of the wrong type are already reported as anomalies
+		if !slices.ContainsFunc(found, func(a Anomaly) bool {
+			return a.Kind == AnomalyTypeMismatch && strings.HasPrefix(problem, "field "+a.Field+" ")
+		}) {
+			warnings = append(warnings, problem)
+		}
+	}
+	if len(warnings) > maxResponseWarnings {
+		warnings = append(warnings[:maxResponseWarnings-1], fmt.Sprintf("%d more deviations", len(warnings)-maxResponseWarnings+1))
+	}
+	for _, warning := range warnings {
+		warn(fmt.Sprintf("Upstream response of %s: %s", endpoint, warning))
+	}
+}
+
+// describe states an anomaly for a warning
+func (a Anomaly) describe() string {
+	switch a.Kind {
+	case AnomalyUnknownField:
+		return fmt.Sprintf("unknown field %s ignored", a.Field)
+	case AnomalyMissingField:
+		return fmt.Sprintf("field %s missing", a.Field)
+	default:
+		return fmt.Sprintf("field %s is %s instead of %s, left empty", a.Field, a.Actual, a.Expected)
+	}
+}
+
+// sampleJSON decodes response data for findAnomalies. Of a list only the
+// first maxAnomalyItems items are decoded, since only those are checked.
+func sampleJSON(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 || data[0] != '[' {
+		var value interface{}
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	items := []interface{}{}
+	for len(items) < maxAnomalyItems && decoder.More() {
+		var item interface{}
+		if err := decoder.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// anomalyEndpoint replaces the path segments containing digits, such as
+// player and club IDs, by {id}
+func anomalyEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if i > 2 && strings.IndexFunc(segment, unicode.IsDigit) >= 0 {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	customDateType  = reflect.TypeOf(CustomDate{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// jsonKind returns the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// expectedKind returns the JSON type of a Go type, empty if any value is
+// accepted
+func expectedKind(t reflect.Type) string {
+	if t == timeType || t == customDateType {
+		return "string"
+	}
+	if t.Kind() != reflect.Struct && reflect.PointerTo(t).Implements(unmarshalerType) {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+// modelFields returns the fields of a struct type by JSON name, including
+// the fields of embedded structs
+func modelFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded, embeddedType := range modelFields(field.Type) {
+				fields[embedded] = embeddedType
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// fieldIndexes returns the indexes of the fields of a struct type by JSON
+// name, including the fields of embedded structs
+func fieldIndexes(t reflect.Type) map[string][]int {
+	indexes := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded, index := range fieldIndexes(field.Type) {
+				indexes[embedded] = append([]int{i}, index...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		indexes[name] = []int{i}
+	}
+	return indexes
+}
+
+// findAnomalies compares a decoded JSON value with a Go type. Null values
+// are accepted for every type.
+func findAnomalies(value interface{}, t reflect.Type, path string) []Anomaly {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if value == nil || t.Kind() == reflect.Interface {
+		return nil
+	}
+	expected := expectedKind(t)
+	if expected == "" {
+		return nil
+	}
+	if actual := jsonKind(value); actual != expected {
+		return []Anomaly{{Kind: AnomalyTypeMismatch, Field: fieldPath(path), Expected: expected, Actual: actual}}
+	}
+
+	var found []Anomaly
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := value.([]interface{})
+		for _, item := range items[:min(len(items), maxAnomalyItems)] {
+			found = append(found, findAnomalies(item, t.Elem(), path+"[]")...)
+		}
+	case reflect.Struct:
+		if t == timeType || t == customDateType {
+			return nil
+		}
+		object := value.(map[string]interface{})
+		fields := modelFields(t)
+		for _, name := range aliasFields[t] {
+			fields[name] = reflect.TypeOf(0)
+		}
+		for name, fieldValue := range object {
+			fieldType, ok := fields[name]
+			if !ok {
+				found = append(found, Anomaly{Kind: AnomalyUnknownField, Field: joinFieldPath(path, name), Actual: jsonKind(fieldValue)})
+				continue
+			}
+			found = append(found, findAnomalies(fieldValue, fieldType, joinFieldPath(path, name))...)
+		}
+		for _, name := range requiredFields[t] {
+			if object[name] == nil {
+				found = append(found, Anomaly{Kind: AnomalyMissingField, Field: joinFieldPath(path, name), Expected: expectedKind(fields[name])})
+			}
+		}
+	}
+	return dedupeAnomalies(found)
+}
+
+// dedupeAnomalies removes repeated anomalies of list items
+func dedupeAnomalies(found []Anomaly) []Anomaly {
+	seen := make(map[string]bool, len(found))
+	unique := found[:0]
+	for _, anomaly := range found {
+		key := anomaly.Kind + " " + anomaly.Field
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, anomaly)
+		}
+	}
+	return unique
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" || strings.HasSuffix(path, "[]") {
+		return path + name
+	}
+	return path + "." + name
+}
+
+// fieldPath names the top level of a response
+func fieldPath(path string) string {
+	if path == "" {
+		return "(response)"
+	}
+	return path
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAnomalies(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Anomaly
+	}{
+		{
+			name: "Matching player",
+			body: `{"id": "C0327-297", "name": "Tran", "current_dwz": 1850, "club_id": null}`,
+		},
+		{
+			name: "Unknown field",
+			body: `{"id": "C0327-297", "name": "Tran", "elo": 1900}`,
+			want: []Anomaly{{Kind: AnomalyUnknownField, Field: "elo", Actual: "number"}},
+		},
+		{
+			name: "Missing required field",
+			body: `{"id": "C0327-297"}`,
+			want: []Anomaly{{Kind: AnomalyMissingField, Field: "name", Expected: "string"}},
+		},
+		{
+			name: "Type mismatch",
+			body: `{"id": "C0327-297", "name": "Tran", "current_dwz": "1850"}`,
+			want: []Anomaly{{Kind: AnomalyTypeMismatch, Field: "current_dwz", Expected: "number", Actual: "string"}},
+		},
+		{
+			name: "Object instead of player",
+			body: `[]`,
+			want: []Anomaly{{Kind: AnomalyTypeMismatch, Field: "(response)", Expected: "object", Actual: "array"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &value))
+			assert.Equal(t, tt.want, findAnomalies(value, reflect.TypeOf(&PlayerResponse{}), ""))
+		})
+	}
+}
+
+func TestFindAnomalies_ListItemsReportedOnce(t *testing.T) {
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[{"id": "C0327", "name": "SF Ulm", "founded": 1920}, {"id": "C0328", "name": "SK Ulm", "founded": 1901}]`), &value))
+
+	found := findAnomalies(value, reflect.TypeOf([]ClubResponse{}), "")
+	assert.Equal(t, []Anomaly{{Kind: AnomalyUnknownField, Field: "[]founded", Actual: "number"}}, found)
+}
+
+func TestSampleJSON(t *testing.T) {
+	items := make([]string, maxAnomalyItems+5)
+	for i := range items {
+		items[i] = `{"id": "C0327"}`
+	}
+	value, err := sampleJSON(json.RawMessage("[" + strings.Join(items, ",") + "]"))
+	require.NoError(t, err)
+	assert.Len(t, value, maxAnomalyItems)
+
+	value, err = sampleJSON(json.RawMessage(`{"id": "C0327"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": "C0327"}, value)
+
+	_, err = sampleJSON(json.RawMessage(`[{"id": `))
+	assert.Error(t, err)
+}
+
+func TestAnomalyEndpoint(t *testing.T) {
+	assert.Equal(t, "/api/v1/players/{id}", anomalyEndpoint("/api/v1/players/C0327-297"))
+	assert.Equal(t, "/api/v1/clubs/{id}/profile", anomalyEndpoint("/api/v1/clubs/C0327/profile"))
+	assert.Equal(t, "/api/v1/players", anomalyEndpoint("/api/v1/players"))
+}
+
+func TestClient_RecordsAnomalies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true, "data": {"id": "C0327-297", "name": "Tran", "elo": 1900}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "anomalies.jsonl")
+	anomalyLog, err := NewAnomalyLog(path, nil)
+	require.NoError(t, err)
+
+	client := NewClient(server.URL, 5*time.Second, nil)
+	client.SetAnomalyLog(anomalyLog)
+	for _, id := range []string{"C0327-297", "C0327-298"} {
+		_, err := client.GetPlayerProfile(context.Background(), id)
+		require.NoError(t, err)
+	}
+	require.NoError(t, anomalyLog.Close())
+
+	anomalies := anomalyLog.Anomalies()
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, "/api/v1/players/{id}", anomalies[0].Endpoint)
+	assert.Equal(t, AnomalyUnknownField, anomalies[0].Kind)
+	assert.Equal(t, "elo", anomalies[0].Field)
+	assert.Equal(t, int64(2), anomalies[0].Count)
+
+	// The file records each anomaly once, when it is first seen
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"field":"elo"`)
+}
+
+func TestClient_ReportsWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true, "data": [
+			{"id": "C350-C01-SMU", "name": "Stadtmeisterschaft", "start_date": "next week", "rounds": "seven", "sponsor": "Sparkasse"},
+			"C350-C02-SMU"
+		]}`))
+	}))
+	defer server.Close()
+
+	var warnings []string
+	ctx := WithWarnings(context.Background(), func(warning string) { warnings = append(warnings, warning) })
+	client := NewClient(server.URL, 5*time.Second, nil)
+	result, err := client.SearchTournaments(ctx, SearchParams{})
+	require.NoError(t, err)
+
+	tournaments := result.Data.([]TournamentResponse)
+	require.Len(t, tournaments, 1)
+	assert.Equal(t, "Stadtmeisterschaft", tournaments[0].Name)
+	assert.Nil(t, tournaments[0].StartDate)
+	assert.ElementsMatch(t, []string{
+		"Upstream response of /api/v1/tournaments: unknown field []sponsor ignored",
+		"Upstream response of /api/v1/tournaments: field []rounds is string instead of number, left empty",
+		"Upstream response of /api/v1/tournaments: field []start_date cannot be decoded, left empty",
+		"Upstream response of /api/v1/tournaments: list item 1 skipped: expected object, got string",
+	}, warnings)
+}
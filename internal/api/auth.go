@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authentication types of AuthOptions
+const (
+	AuthNone   = "none"
+	AuthAPIKey = "api_key"
+	AuthBasic  = "basic"
+	AuthOAuth2 = "oauth2"
+)
+
+const (
+	// defaultAPIKeyHeader carries the API key unless another header is set
+	defaultAPIKeyHeader = "X-API-Key"
+	// tokenExpiryMargin renews OAuth2 tokens this long before they expire,
+	// so a token does not expire while a request is on its way
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// AuthOptions configures the credentials sent with every request to the
+// Portal64 API
+type AuthOptions struct {
+	Type     string // AuthNone, AuthAPIKey, AuthBasic or AuthOAuth2; empty means none
+	Header   string // Header of the API key, default X-API-Key
+	APIKey   string
+	Username string
+	Password string
+	// OAuth2 client credentials grant
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// ConfigureAuth adds credentials to every request of the client. OAuth2
+// tokens are acquired with the client's TLS settings on first use and
+// renewed before they expire or when the API rejects them. Like
+// ConfigureTLS, it must be called before ConfigureFailover and before the
+// client is used.
+func (c *Client) ConfigureAuth(opts AuthOptions) error {
+	auth := &authTransport{base: c.httpClient.Transport}
+	switch opts.Type {
+	case "", AuthNone:
+		return nil
+	case AuthAPIKey:
+		if opts.APIKey == "" {
+			return fmt.Errorf("API key authentication requires a key")
+		}
+		header := opts.Header
+		if header == "" {
+			header = defaultAPIKeyHeader
+		}
+		auth.authorize = func(req *http.Request) error {
+			req.Header.Set(header, opts.APIKey)
+			return nil
+		}
+	case AuthBasic:
+		if opts.Username == "" {
+			return fmt.Errorf("basic authentication requires a username")
+		}
+		auth.authorize = func(req *http.Request) error {
+			req.SetBasicAuth(opts.Username, opts.Password)
+			return nil
+		}
+	case AuthOAuth2:
+		if u, err := url.Parse(opts.TokenURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid OAuth2 token URL %q", opts.TokenURL)
+		}
+		if opts.ClientID == "" {
+			return fmt.Errorf("OAuth2 authentication requires a client ID")
+		}
+		auth.tokens = &tokenSource{
+			client:       &http.Client{Transport: c.transport, Timeout: c.httpClient.Timeout},
+			tokenURL:     opts.TokenURL,
+			clientID:     opts.ClientID,
+			clientSecret: opts.ClientSecret,
+			scopes:       opts.Scopes,
+			now:          time.Now,
+		}
+		auth.authorize = func(req *http.Request) error {
+			token, err := auth.tokens.Token(req.Context())
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown authentication type %q", opts.Type)
+	}
+	c.httpClient.Transport = auth
+	return nil
+}
+
+// authTransport adds credentials to requests
+type authTransport struct {
+	base      http.RoundTripper
+	authorize func(*http.Request) error
+	tokens    *tokenSource // Nil unless OAuth2 is used
+}
+
+// RoundTrip implements http.RoundTripper. A request rejected with 401 is
+// repeated once with a new OAuth2 token, as the token may have been revoked
+// before it expired.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorized := req.Clone(req.Context())
+	if err := t.authorize(authorized); err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(authorized)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || t.tokens == nil {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	t.tokens.invalidate(strings.TrimPrefix(authorized.Header.Get("Authorization"), "Bearer "))
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	if err := t.authorize(retry); err != nil {
+		closeRequestBody(retry)
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// closeRequestBody closes the body of a request that is not sent, as
+// http.RoundTripper requires
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// tokenSource acquires and caches OAuth2 access tokens with the client
+// credentials grant
+type tokenSource struct {
+	client       *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	now          func() time.Time
+
+	// mu is held while a token is requested, so concurrent requests wait
+	// for one token instead of requesting their own
+	mu      sync.Mutex
+	token   string
+	expires time.Time // Zero if the token server sent no lifetime
+}
+
+// tokenResponse is the response of an OAuth2 token endpoint
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Token returns a valid access token, requesting a new one if the cached
+// token is missing or about to expire
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && (s.expires.IsZero() || s.now().Before(s.expires.Add(-tokenExpiryMargin))) {
+		return s.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request OAuth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&token); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to decode OAuth2 token response: %w", err)
+	}
+	switch {
+	case resp.StatusCode != http.StatusOK && token.Error != "":
+		return "", fmt.Errorf("OAuth2 token request failed with status %d: %s", resp.StatusCode, firstNonEmpty(token.ErrorDescription, token.Error))
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("OAuth2 token request failed with status %d", resp.StatusCode)
+	case token.AccessToken == "":
+		return "", fmt.Errorf("OAuth2 token response contains no access token")
+	case token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer"):
+		return "", fmt.Errorf("unsupported OAuth2 token type %q", token.TokenType)
+	}
+
+	s.token = token.AccessToken
+	s.expires = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expires = s.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return s.token, nil
+}
+
+// invalidate drops the cached token if it is the rejected one, so the next
+// request acquires a new token
+func (s *tokenSource) invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureAuth_StaticCredentials(t *testing.T) {
+	var header http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, client.ConfigureAuth(AuthOptions{Type: AuthAPIKey, APIKey: "secret"}))
+	_, err := client.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secret", header.Get("X-API-Key"))
+
+	client = NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, client.ConfigureAuth(AuthOptions{Type: AuthAPIKey, Header: "Api-Token", APIKey: "secret"}))
+	_, err = client.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secret", header.Get("Api-Token"))
+
+	client = NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, client.ConfigureAuth(AuthOptions{Type: AuthBasic, Username: "portal", Password: "hunter2"}))
+	_, err = client.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Basic cG9ydGFsOmh1bnRlcjI=", header.Get("Authorization"))
+
+	for _, opts := range []AuthOptions{
+		{Type: AuthAPIKey},
+		{Type: AuthBasic, Password: "hunter2"},
+		{Type: AuthOAuth2, TokenURL: "/token", ClientID: "mcp"},
+		{Type: AuthOAuth2, TokenURL: "https://auth.example.org/token"},
+		{Type: "digest"},
+	} {
+		assert.Error(t, NewClient(upstream.URL, time.Second, nil).ConfigureAuth(opts), opts.Type)
+	}
+}
+
+func TestConfigureAuth_OAuth2(t *testing.T) {
+	var issued, rejected int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		require.NoError(t, r.ParseForm())
+		if user != "mcp" || password != "s3cret" || r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client", "error_description": "unknown client"}`))
+			return
+		}
+		assert.Equal(t, "read:players read:clubs", r.PostForm.Get("scope"))
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first token is revoked after its first use
+		if r.Header.Get("Authorization") == "Bearer token-1" && atomic.AddInt32(&rejected, 1) > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer upstream.Close()
+
+	opts := AuthOptions{Type: AuthOAuth2, TokenURL: tokenServer.URL, ClientID: "mcp", ClientSecret: "s3cret", Scopes: []string{"read:players", "read:clubs"}}
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, client.ConfigureAuth(opts))
+	ctx := context.Background()
+
+	_, err := client.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&issued), "the token is acquired on first use")
+
+	_, err = client.Health(ctx)
+	require.NoError(t, err, "a rejected token is replaced")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued))
+
+	_, err = client.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issued), "valid tokens are reused")
+
+	// Tokens are renewed shortly before they expire
+	tokens := client.httpClient.Transport.(*authTransport).tokens
+	tokens.now = func() time.Time { return time.Now().Add(time.Hour - 10*time.Second) }
+	_, err = client.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&issued))
+
+	opts.ClientSecret = "wrong"
+	client = NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, client.ConfigureAuth(opts))
+	_, err = client.Health(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OAuth2 token request failed with status 401: unknown client")
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+// benchDataset is a generated data set shared by the benchmarks
+var benchDataset = testserver.GenerateDataset(testserver.GenerateOptions{Seed: 1, Players: 1000, Clubs: 20, Tournaments: 100})
+
+// benchPlayersPage returns a wrapped search response of 100 players
+func benchPlayersPage(b *testing.B) []byte {
+	body, err := json.Marshal(map[string]interface{}{
+		"success": true,
+		"data": map[string]interface{}{
+			"data": benchDataset.Players[:100],
+			"meta": map[string]int{"total": len(benchDataset.Players), "limit": 100, "offset": 0},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return body
+}
+
+// benchResponse returns a response with body for the request path
+func benchResponse(path string, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    httptest.NewRequest(http.MethodGet, path, nil),
+	}
+}
+
+func BenchmarkUnwrapPayload(b *testing.B) {
+	body := benchPlayersPage(b)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnwrapPayload(body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSearchResponse(b *testing.B) {
+	body := benchPlayersPage(b)
+	params := SearchParams{Limit: 100}
+
+	b.Run("Players", func(b *testing.B) {
+		client := NewClient("http://localhost", time.Second, nil)
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeSearchResponse[PlayerResponse](client, benchResponse("/api/v1/players", body), params); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("PlayersWithAnomalyLog", func(b *testing.B) {
+		client := NewClient("http://localhost", time.Second, nil)
+		anomalyLog, _ := NewAnomalyLog("", nil)
+		client.SetAnomalyLog(anomalyLog)
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeSearchResponse[PlayerResponse](client, benchResponse("/api/v1/players", body), params); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeTournamentDetails(b *testing.B) {
+	participants := make([]map[string]interface{}, 0, 100)
+	for _, p := range benchDataset.Players[:100] {
+		participants = append(participants, map[string]interface{}{
+			"id": p.ID, "name": p.Name, "firstname": p.Firstname, "club_id": p.ClubID, "dwz": p.CurrentDWZ,
+		})
+	}
+	tournament := benchDataset.Tournaments[0]
+	data, err := json.Marshal(map[string]interface{}{
+		"tournament":   tournament,
+		"participants": participants,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, errs := DecodeTournamentDetails(data); len(errs) > 0 {
+			b.Fatal(errs)
+		}
+	}
+}
+
+// BenchmarkGetClubStatistics covers the conversion of the club profile,
+// including the member list decoded via an intermediate marshal
+func BenchmarkGetClubStatistics(b *testing.B) {
+	body, err := json.Marshal(map[string]interface{}{
+		"success": true,
+		"data": map[string]interface{}{
+			"club":    benchDataset.Clubs[0],
+			"players": benchDataset.Players[:200],
+			"rating_stats": map[string]interface{}{
+				"average_dwz": 1550.5, "median_dwz": 1540, "highest_dwz": 2210, "lowest_dwz": 800,
+				"rating_distribution": map[string]int{"1400_1599": 40, "1600_1799": 30},
+			},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second, nil)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.GetClubStatistics(ctx, "C0327"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+// Entity types of cached responses, by the path below /api/v1
+const (
+	CacheRegions     = "regions"     // /addresses/regions
+	CacheAddresses   = "addresses"   // /addresses/{region}
+	CachePlayers     = "players"     // /players...
+	CacheClubs       = "clubs"       // /clubs...
+	CacheTournaments = "tournaments" // /tournaments...
+)
+
+// defaultMaxCacheEntries bounds the response cache unless configured
+const defaultMaxCacheEntries = 10000
+
+// CachePolicy sets how long the responses of an entity type are cached
+type CachePolicy struct {
+	TTL time.Duration // Served from the cache; 0 disables caching of the type
+	// Stale is the time beyond the TTL a response is still served while
+	// it is refreshed in the background (stale-while-revalidate)
+	Stale time.Duration
+}
+
+// CacheOptions configures the response cache of the client
+type CacheOptions struct {
+	Policies   map[string]CachePolicy // By entity type, e.g. CacheRegions
+	MaxEntries int                    // Least recently used responses are evicted beyond this, default 10000
+	// NotFoundTTL caches 404 responses of all entity types for this long,
+	// so lookups of unknown IDs do not reach the API every time; 0 disables
+	// negative caching
+	NotFoundTTL time.Duration
+}
+
+// Outcomes of CacheLookup
+const (
+	CacheHit    = "hit"    // Fresh response or 404 served from the cache
+	CacheStale  = "stale"  // Served from the cache while refreshed in the background
+	CacheMiss   = "miss"   // Fetched from the API
+	CacheBypass = "bypass" // Fetched from the API for WithCacheBypass
+)
+
+// CacheLookup describes how the response cache answered a request
+type CacheLookup struct {
+	URL     string
+	Entity  string
+	Status  string    // CacheHit, CacheStale, CacheMiss or CacheBypass
+	Fetched time.Time // When the response was fetched from the API
+	Expires time.Time // End of its TTL, zero if it was not cached
+}
+
+type cacheBypassKey struct{}
+
+type cacheObserverKey struct{}
+
+// WithCacheObserver returns a context whose GET requests report to observe
+// how the response cache answered them. Requests the cache does not handle
+// are not reported.
+func WithCacheObserver(ctx context.Context, observe func(CacheLookup)) context.Context {
+	return context.WithValue(ctx, cacheObserverKey{}, observe)
+}
+
+// observeCache reports a lookup to the observer of the context, if any
+func observeCache(ctx context.Context, lookup CacheLookup) {
+	if observe, ok := ctx.Value(cacheObserverKey{}).(func(CacheLookup)); ok {
+		observe(lookup)
+	}
+}
+
+// WithCacheBypass returns a context whose GET requests skip cached
+// responses and misses. The fresh response replaces the cached one.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// bypassesCache reports whether the context was created by WithCacheBypass
+func bypassesCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// ResponseCacheStats reports the use of the response cache
+type ResponseCacheStats struct {
+	Entries       int   `json:"entries"`
+	Hits          int64 `json:"hits"`
+	StaleHits     int64 `json:"stale_hits"` // Served while refreshed in the background
+	Misses        int64 `json:"misses"`
+	Refreshes     int64 `json:"refreshes"`      // Background refreshes started
+	SharedFetches int64 `json:"shared_fetches"` // Misses that waited for a fetch already in flight
+	Invalidations int64 `json:"invalidations"`  // Entries dropped after writes
+	NotFoundHits  int64 `json:"not_found_hits"` // 404 responses served from the cache
+	Bypasses      int64 `json:"bypasses"`       // Requests that skipped the cache to revalidate
+	// MaintenanceHits are responses served regardless of their age while
+	// the API is under maintenance
+	MaintenanceHits int64 `json:"maintenance_hits"`
+}
+
+// ConfigureCache caches successful GET responses of the API per entity
+// type. Concurrent misses of a key wait for a single upstream request, so
+// hot keys such as the regions list do not stampede the API when they
+// expire. Like ConfigureTLS, it must be called before the client is used.
+func (c *Client) ConfigureCache(opts CacheOptions) {
+	policies := make(map[string]CachePolicy)
+	for entity, policy := range opts.Policies {
+		if policy.TTL > 0 {
+			policies[entity] = policy
+		}
+	}
+	if len(policies) == 0 && opts.NotFoundTTL <= 0 {
+		return
+	}
+	c.cache = &ResponseCache{
+		policies:    policies,
+		notFoundTTL: max(opts.NotFoundTTL, 0),
+		maxEntries:  opts.MaxEntries,
+		now:         time.Now,
+		logger:      c.logger,
+		entries:     make(map[string]*cachedResponse),
+		lru:         list.New(),
+		calls:       make(map[string]*cacheCall),
+	}
+	if c.cache.maxEntries <= 0 {
+		c.cache.maxEntries = defaultMaxCacheEntries
+	}
+}
+
+// ResponseCache returns the response cache, nil if it is not configured
+func (c *Client) ResponseCache() *ResponseCache {
+	return c.cache
+}
+
+// SetEnabled sets a function asked on every request whether the cache is
+// used, e.g. for a feature flag. Without it the cache is always used. It
+// must be set before the client is used.
+func (rc *ResponseCache) SetEnabled(enabled func() bool) {
+	rc.enabled = enabled
+}
+
+// cacheEntity returns the entity type of an API URL, empty for URLs that
+// are not cached such as /health and admin endpoints
+func cacheEntity(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	_, path, ok := strings.Cut(u.Path, "/api/v1/")
+	if !ok {
+		return ""
+	}
+	resource, rest, _ := strings.Cut(path, "/")
+	switch {
+	case resource == "addresses" && rest == "regions":
+		return CacheRegions
+	case resource == "addresses" && rest != "":
+		return CacheAddresses
+	case resource == CachePlayers, resource == CacheClubs, resource == CacheTournaments:
+		return resource
+	default:
+		return ""
+	}
+}
+
+// ResponseCache holds API responses by URL. It implements memory.Store.
+type ResponseCache struct {
+	policies    map[string]CachePolicy
+	notFoundTTL time.Duration
+	maxEntries  int
+	enabled     func() bool
+	now         func() time.Time
+	logger      Logger
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	lru     *list.List            // Keys of entries, most recently used first
+	calls   map[string]*cacheCall // Upstream requests in flight by URL
+	stats   ResponseCacheStats
+}
+
+// cachedResponse is a successful or 404 response of the API
+type cachedResponse struct {
+	entity   string
+	notFound *APIError // Set for 404 responses, which have no body
+	body     []byte
+	header   http.Header
+	request  *http.Request // Kept for the anomaly checks of decoding
+	size     int64
+	fetched  time.Time
+	element  *list.Element // Position in the LRU list
+}
+
+// cacheCall is an upstream request shared by all callers of a URL
+type cacheCall struct {
+	done    chan struct{}
+	entry   *cachedResponse
+	err     error
+	fetched time.Time
+	expires time.Time // Zero unless the response or 404 was cached
+}
+
+// response returns a new response serving the cached body. Its request
+// carries the context of the caller, so the anomaly checks of decoding
+// report to the caller instead of the request that fetched the body.
+func (e *cachedResponse) response(ctx context.Context) *http.Response {
+	request := e.request
+	if request != nil {
+		request = request.WithContext(ctx)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       request,
+	}
+}
+
+// expires returns the end of the TTL of the entry
+func (rc *ResponseCache) expires(entry *cachedResponse, policy CachePolicy) time.Time {
+	if entry.notFound != nil {
+		return entry.fetched.Add(rc.notFoundTTL)
+	}
+	return entry.fetched.Add(policy.TTL)
+}
+
+// miss returns the cached 404 error, marked as served from the cache
+func (e *cachedResponse) miss() error {
+	apiErr := *e.notFound
+	apiErr.CachedMiss = true
+	return &apiErr
+}
+
+// policy returns the policy of a URL, false if neither its responses nor
+// its 404s are cached
+func (rc *ResponseCache) policy(rawURL string) (string, CachePolicy, bool) {
+	if rc == nil || (rc.enabled != nil && !rc.enabled()) {
+		return "", CachePolicy{}, false
+	}
+	entity := cacheEntity(rawURL)
+	if entity == "" {
+		return "", CachePolicy{}, false
+	}
+	policy, ok := rc.policies[entity]
+	return entity, policy, ok || rc.notFoundTTL > 0
+}
+
+// get serves a URL from the cache. Fresh entries are returned at once,
+// stale ones as well while a refresh starts in the background. Misses wait
+// for the fetch in flight for the URL or start one. Fetches are detached
+// from the context of the caller, so a caller that gives up does not fail
+// the others. Contexts of WithCacheBypass skip the cached entry.
+func (rc *ResponseCache) get(ctx context.Context, key, entity string, policy CachePolicy, fetch func(context.Context) (*cachedResponse, error)) (*http.Response, error) {
+	now := rc.now()
+	detached := context.WithoutCancel(ctx)
+
+	lookup := CacheLookup{URL: key, Entity: entity, Status: CacheMiss}
+
+	rc.mu.Lock()
+	entry, ok := rc.entries[key]
+	if bypassesCache(ctx) {
+		rc.stats.Bypasses++
+		lookup.Status = CacheBypass
+		ok = false
+	}
+	if ok && entry.notFound != nil && now.Sub(entry.fetched) < rc.notFoundTTL {
+		rc.lru.MoveToFront(entry.element)
+		rc.stats.NotFoundHits++
+		lookup.Status, lookup.Fetched, lookup.Expires = CacheHit, entry.fetched, rc.expires(entry, policy)
+		rc.mu.Unlock()
+		observeCache(ctx, lookup)
+		return nil, entry.miss()
+	}
+	if ok && entry.notFound == nil {
+		rc.lru.MoveToFront(entry.element)
+		lookup.Fetched, lookup.Expires = entry.fetched, rc.expires(entry, policy)
+		age := now.Sub(entry.fetched)
+		if age < policy.TTL {
+			rc.stats.Hits++
+			rc.mu.Unlock()
+			lookup.Status = CacheHit
+			observeCache(ctx, lookup)
+			return entry.response(ctx), nil
+		}
+		if age < policy.TTL+policy.Stale {
+			rc.stats.StaleHits++
+			if _, running := rc.calls[key]; !running {
+				rc.stats.Refreshes++
+				rc.startFetch(detached, key, entity, policy, fetch)
+			}
+			rc.mu.Unlock()
+			lookup.Status = CacheStale
+			observeCache(ctx, lookup)
+			return entry.response(ctx), nil
+		}
+	}
+
+	rc.stats.Misses++
+	call, running := rc.calls[key]
+	if running {
+		rc.stats.SharedFetches++
+	} else {
+		call = rc.startFetch(detached, key, entity, policy, fetch)
+	}
+	rc.mu.Unlock()
+
+	select {
+	case <-call.done:
+		lookup.Fetched, lookup.Expires = call.fetched, call.expires
+		observeCache(ctx, lookup)
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.entry.response(ctx), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lookupAny returns the cached response or 404 of a URL regardless of its
+// age, for requests while the API is under maintenance. The lookup is
+// reported as stale.
+func (rc *ResponseCache) lookupAny(ctx context.Context, key string) (*cachedResponse, bool) {
+	if rc == nil || (rc.enabled != nil && !rc.enabled()) {
+		return nil, false
+	}
+	rc.mu.Lock()
+	entry, ok := rc.entries[key]
+	if !ok {
+		rc.mu.Unlock()
+		return nil, false
+	}
+	rc.lru.MoveToFront(entry.element)
+	rc.stats.MaintenanceHits++
+	lookup := CacheLookup{URL: key, Entity: entry.entity, Status: CacheStale, Fetched: entry.fetched, Expires: rc.expires(entry, rc.policies[entry.entity])}
+	rc.mu.Unlock()
+	observeCache(ctx, lookup)
+	return entry, true
+}
+
+// startFetch fetches a URL in the background and stores the response, or
+// the 404 if negative caching is enabled. It must be called with mu held.
+func (rc *ResponseCache) startFetch(ctx context.Context, key, entity string, policy CachePolicy, fetch func(context.Context) (*cachedResponse, error)) *cacheCall {
+	call := &cacheCall{done: make(chan struct{})}
+	rc.calls[key] = call
+	go func() {
+		entry, err := fetch(ctx)
+		rc.mu.Lock()
+		delete(rc.calls, key)
+		call.fetched = rc.now()
+		var apiErr *APIError
+		switch {
+		case err == nil && policy.TTL > 0:
+			rc.store(key, entity, entry)
+			call.expires = rc.expires(entry, policy)
+		case err == nil:
+			// Only 404s of the type are cached, a stale miss is dropped
+			rc.remove(key)
+		case rc.notFoundTTL > 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			miss := &cachedResponse{notFound: apiErr, size: int64(len(key) + len(apiErr.Message) + len(apiErr.Code))}
+			rc.store(key, entity, miss)
+			call.expires = rc.expires(miss, policy)
+		default:
+			rc.logger.WithError(err).WithField("url", key).Debug("Failed to fetch cached API response")
+		}
+		rc.mu.Unlock()
+		call.entry, call.err = entry, err
+		close(call.done)
+	}()
+	return call
+}
+
+// store adds an entry to the cache, replacing the one of the key. It must
+// be called with mu held.
+func (rc *ResponseCache) store(key, entity string, entry *cachedResponse) {
+	rc.remove(key)
+	entry.entity = entity
+	entry.fetched = rc.now()
+	entry.element = rc.lru.PushFront(key)
+	rc.entries[key] = entry
+	rc.evictBeyond(rc.maxEntries)
+}
+
+// remove drops the entry of a key, if any. It must be called with mu held.
+func (rc *ResponseCache) remove(key string) {
+	if entry, ok := rc.entries[key]; ok {
+		rc.lru.Remove(entry.element)
+		delete(rc.entries, key)
+	}
+}
+
+// oldest returns the key of the least recently used entry, false if the
+// cache is empty. It must be called with mu held.
+func (rc *ResponseCache) oldest() (string, bool) {
+	back := rc.lru.Back()
+	if back == nil {
+		return "", false
+	}
+	return back.Value.(string), true
+}
+
+// invalidate drops the cached responses of the entity type of a URL, e.g.
+// after a write to it
+func (rc *ResponseCache) invalidate(rawURL string) {
+	if rc == nil {
+		return
+	}
+	entity := cacheEntity(rawURL)
+	if entity == "" {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key, entry := range rc.entries {
+		if entry.entity == entity {
+			rc.remove(key)
+			rc.stats.Invalidations++
+		}
+	}
+}
+
+// evictBeyond removes the least recently used entries until at most limit
+// are left. It must be called with mu held.
+func (rc *ResponseCache) evictBeyond(limit int) {
+	for len(rc.entries) > limit {
+		key, _ := rc.oldest()
+		rc.remove(key)
+	}
+}
+
+// Stats returns the cache statistics
+func (rc *ResponseCache) Stats() ResponseCacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	stats := rc.stats
+	stats.Entries = len(rc.entries)
+	return stats
+}
+
+// MemoryUsage implements memory.Store
+func (rc *ResponseCache) MemoryUsage() memory.Usage {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	usage := memory.Usage{Entries: len(rc.entries)}
+	for _, entry := range rc.entries {
+		usage.Bytes += entry.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the least recently used responses
+func (rc *ResponseCache) Evict(bytes int64) int64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var freed int64
+	for freed < bytes {
+		key, ok := rc.oldest()
+		if !ok {
+			break
+		}
+		freed += rc.entries[key].size
+		rc.remove(key)
+	}
+	return freed
+}
+
+// fetchCacheable requests a URL and reads the successful response for the
+// cache
+func (c *Client) fetchCacheable(ctx context.Context, url string) (*cachedResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	entry := &cachedResponse{body: body, header: resp.Header, request: resp.Request}
+	entry.size = int64(len(body)+len(url)) + cachedHeaderSize(resp.Header)
+	return entry, nil
+}
+
+// cachedHeaderSize estimates the bytes of a cached header
+func cachedHeaderSize(header http.Header) int64 {
+	var size int64
+	for name, values := range header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	return size
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheEntity(t *testing.T) {
+	for url, entity := range map[string]string{
+		"http://portal64/api/v1/addresses/regions":        CacheRegions,
+		"http://portal64/api/v1/addresses/C?type=club":    CacheAddresses,
+		"http://portal64/api/v1/players/C0101-1014":       CachePlayers,
+		"http://portal64/api/v1/players?query=Schmidt":    CachePlayers,
+		"http://portal64/base/api/v1/clubs/C0101/profile": CacheClubs,
+		"http://portal64/api/v1/tournaments/C529-K00-HT1": CacheTournaments,
+		"http://portal64/api/v1/admin/cache":              "",
+		"http://portal64/health":                          "",
+		"http://portal64/api/v1/addresses":                "",
+	} {
+		assert.Equal(t, entity, cacheEntity(url), url)
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	var requests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`[{"code": "C", "name": "Württemberg"}]`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{
+		CacheRegions: {TTL: time.Hour, Stale: time.Hour},
+		CachePlayers: {},
+	}})
+	cache := client.ResponseCache()
+	require.NotNil(t, cache)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	regions, err := client.GetRegions(ctx)
+	require.NoError(t, err)
+	require.Len(t, regions, 1)
+	assert.Equal(t, "Württemberg", regions[0].Name)
+
+	_, err = client.GetRegions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "fresh responses are served from the cache")
+
+	// Past the TTL the stale response is served and refreshed
+	now = now.Add(90 * time.Minute)
+	regions, err = client.GetRegions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Württemberg", regions[0].Name)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&requests) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return len(cache.calls) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	// Types without a TTL are not cached
+	client.GetPlayerProfile(ctx, "C0101-1014")
+	client.GetPlayerProfile(ctx, "C0101-1014")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	stats := cache.Stats()
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.StaleHits)
+	assert.Equal(t, int64(1), stats.Misses)
+
+	// Disabled caches are bypassed, writes drop the entries of their type
+	cache.SetEnabled(func() bool { return false })
+	client.GetRegions(ctx)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+	cache.invalidate(upstream.URL + "/api/v1/addresses/regions")
+	assert.Zero(t, cache.Stats().Entries)
+	assert.Equal(t, int64(1), cache.Stats().Invalidations)
+}
+
+func TestResponseCache_StampedeProtection(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write([]byte(`[{"code": "C", "name": "Württemberg"}]`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CacheRegions: {TTL: time.Hour}}})
+
+	// A caller that gives up does not fail the fetch of the others
+	canceled, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := client.GetRegions(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetRegions(context.Background())
+			errs <- err
+		}()
+	}
+	assert.Eventually(t, func() bool { return client.ResponseCache().Stats().SharedFetches == 10 }, time.Second, 5*time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "concurrent misses share one upstream request")
+}
+
+func TestResponseCache_Evict(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "` + r.URL.Path + `"}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CachePlayers: {TTL: time.Hour}}, MaxEntries: 2})
+	cache := client.ResponseCache()
+	ctx := context.Background()
+
+	for _, id := range []string{"C0101-1", "C0101-2", "C0101-3"} {
+		_, err := client.GetPlayerProfile(ctx, id)
+		require.NoError(t, err)
+	}
+	usage := cache.MemoryUsage()
+	assert.Equal(t, 2, usage.Entries, "the least recently used response is evicted beyond max_entries")
+	assert.Positive(t, usage.Bytes)
+
+	assert.Equal(t, usage.Bytes, cache.Evict(usage.Bytes))
+	assert.Zero(t, cache.MemoryUsage().Entries)
+}
+
+func TestResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	var requests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"id": "` + r.URL.Path + `"}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CachePlayers: {TTL: time.Hour}}, MaxEntries: 2})
+	ctx := context.Background()
+
+	// C0101-1 is used again after C0101-2, so C0101-2 is evicted for C0101-3
+	for _, id := range []string{"C0101-1", "C0101-2", "C0101-1", "C0101-3", "C0101-1"} {
+		_, err := client.GetPlayerProfile(ctx, id)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	_, err := client.GetPlayerProfile(ctx, "C0101-2")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests), "the least recently used response was evicted")
+	assert.Equal(t, 2, client.ResponseCache().Stats().Entries)
+}
+
+func TestResponseCache_WarningsOfCaller(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true, "data": [{"id": "C350-C01-SMU", "name": "Stadtmeisterschaft", "sponsor": "Sparkasse"}]}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CacheTournaments: {TTL: time.Hour}}})
+
+	// The second search is served from the cache and warns its own caller
+	for i := 0; i < 2; i++ {
+		var warnings []string
+		ctx := WithWarnings(context.Background(), func(warning string) { warnings = append(warnings, warning) })
+		_, err := client.SearchTournaments(ctx, SearchParams{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Upstream response of /api/v1/tournaments: unknown field []sponsor ignored"}, warnings, "search %d", i+1)
+	}
+	assert.Equal(t, int64(1), client.ResponseCache().Stats().Hits)
+}
+
+func TestResponseCache_NotFound(t *testing.T) {
+	var requests int32
+	var exists atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !exists.Load() {
+			http.Error(w, `{"message": "Tournament not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id": "C529-K00-HT1", "name": "Herbstturnier"}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{NotFoundTTL: time.Minute})
+	cache := client.ResponseCache()
+	require.NotNil(t, cache, "negative caching alone configures the cache")
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := client.GetTournamentDetails(ctx, "C529-K00-HT1")
+	require.True(t, IsNotFound(err))
+	assert.False(t, IsCachedMiss(err), "the first 404 comes from the API")
+
+	_, err = client.GetTournamentDetails(ctx, "C529-K00-HT1")
+	require.True(t, IsNotFound(err))
+	assert.True(t, IsCachedMiss(err))
+	assert.Equal(t, "API error 404: Tournament not found", err.Error())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// Bypassing revalidates, the fresh response replaces the cached miss
+	exists.Store(true)
+	_, err = client.GetTournamentDetails(WithCacheBypass(ctx), "C529-K00-HT1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// Types without a TTL cache nothing but their misses
+	_, err = client.GetTournamentDetails(ctx, "C529-K00-HT1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Misses expire after the TTL
+	exists.Store(false)
+	client.GetTournamentDetails(ctx, "C529-K00-HT1")
+	now = now.Add(2 * time.Minute)
+	_, err = client.GetTournamentDetails(ctx, "C529-K00-HT1")
+	assert.False(t, IsCachedMiss(err))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(1), stats.NotFoundHits)
+	assert.Equal(t, int64(1), stats.Bypasses)
+}
+
+func TestResponseCache_Observer(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"code": "C", "name": "Württemberg"}]`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CacheRegions: {TTL: time.Hour, Stale: time.Hour}}})
+	cache := client.ResponseCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	var lookups []CacheLookup
+	ctx := WithCacheObserver(context.Background(), func(lookup CacheLookup) { lookups = append(lookups, lookup) })
+	for i := 0; i < 2; i++ {
+		_, err := client.GetRegions(ctx)
+		require.NoError(t, err)
+	}
+	_, err := client.GetRegions(WithCacheBypass(ctx))
+	require.NoError(t, err)
+	// Health checks are not cached and not reported
+	client.Health(ctx)
+
+	url := upstream.URL + "/api/v1/addresses/regions"
+	require.Len(t, lookups, 3)
+	assert.Equal(t, CacheLookup{URL: url, Entity: CacheRegions, Status: CacheMiss, Fetched: now, Expires: now.Add(time.Hour)}, lookups[0])
+	assert.Equal(t, CacheHit, lookups[1].Status)
+	assert.Equal(t, now.Add(time.Hour), lookups[1].Expires)
+	assert.Equal(t, CacheBypass, lookups[2].Status)
+}
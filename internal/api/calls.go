@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Call describes a request to the API made through DoRequest or
+// DoJSONRequest, including requests answered by the response cache
+type Call struct {
+	Method string
+	Path   string // Path and query relative to the base URL
+	Status int    // HTTP status, 0 if there was no response
+	Cache  string // CacheHit, CacheStale, CacheMiss or CacheBypass, empty if not cached
+	Err    error
+}
+
+type callObserverKey struct{}
+
+// WithCallObserver returns a context whose API requests report to observe
+// once they completed. Retries of a request are reported as one call.
+func WithCallObserver(ctx context.Context, observe func(Call)) context.Context {
+	return context.WithValue(ctx, callObserverKey{}, observe)
+}
+
+// observingCalls reports whether the context has a call observer
+func observingCalls(ctx context.Context) bool {
+	_, ok := ctx.Value(callObserverKey{}).(func(Call))
+	return ok
+}
+
+// observeCall reports a completed request to the observer of the context,
+// if any
+func (c *Client) observeCall(ctx context.Context, method, url string, status int, cache string, err error) {
+	observe, ok := ctx.Value(callObserverKey{}).(func(Call))
+	if !ok {
+		return
+	}
+	var apiErr *APIError
+	if status == 0 && errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	observe(Call{
+		Method: method,
+		Path:   strings.TrimPrefix(url, c.baseURL),
+		Status: status,
+		Cache:  cache,
+		Err:    err,
+	})
+}
+
+// withCacheStatus returns a context recording the status of cache lookups
+// into status, still reporting them to an observer of ctx
+func withCacheStatus(ctx context.Context, status *string) context.Context {
+	return WithCacheObserver(ctx, func(lookup CacheLookup) {
+		*status = lookup.Status
+		observeCache(ctx, lookup)
+	})
+}
+
+// responseStatus is the status code of a response, 0 if there is none
+func responseStatus(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CallObserver(t *testing.T) {
+	var attempts int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/addresses/regions":
+			w.Write([]byte(`[{"code": "C", "name": "Württemberg"}]`))
+		case "/api/v1/cache":
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.retryBackoff = time.Millisecond
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CacheRegions: {TTL: time.Hour}}})
+
+	var calls []Call
+	var lookups []CacheLookup
+	ctx := WithCacheObserver(context.Background(), func(lookup CacheLookup) { lookups = append(lookups, lookup) })
+	ctx = WithCallObserver(ctx, func(call Call) { calls = append(calls, call) })
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetRegions(ctx)
+		require.NoError(t, err)
+	}
+	_, err := client.GetPlayerProfile(ctx, "C0327-999")
+	require.Error(t, err)
+	require.NoError(t, client.DoJSONRequest(ctx, http.MethodDelete, "/api/v1/cache", nil, nil))
+
+	require.Len(t, calls, 4)
+	assert.Equal(t, Call{Method: http.MethodGet, Path: "/api/v1/addresses/regions", Status: http.StatusOK, Cache: CacheMiss}, calls[0])
+	assert.Equal(t, CacheHit, calls[1].Cache)
+	assert.Equal(t, "/api/v1/players/C0327-999", calls[2].Path)
+	assert.Equal(t, http.StatusNotFound, calls[2].Status, "the status of error responses is taken from the error")
+	assert.Empty(t, calls[2].Cache, "uncached requests have no cache status")
+	assert.Error(t, calls[2].Err)
+	assert.Equal(t, Call{Method: http.MethodDelete, Path: "/api/v1/cache", Status: http.StatusNoContent}, calls[3], "retries are reported as one call")
+	assert.Len(t, lookups, 2, "cache lookups are still reported to the cache observer")
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -15,24 +17,72 @@ import (
 
 // Client represents the Portal64 API client
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	logger     *logrus.Logger
+	baseURL      string
+	httpClient   *http.Client
+	logger       Logger
+	retryBackoff time.Duration
+	transport    *http.Transport
+	connTracker  *connTracker
+	failover     *failoverTransport
+	// onServerError are called for each 5xx response of the API
+	onServerError []func(method, url string, status int)
+	// onBreakerOpen is called when the circuit breaker of an upstream opens
+	onBreakerOpen func(url, reason string)
+	// writable allows the write operations of Writer
+	writable bool
+	// anomalies records deviations of responses from the models
+	anomalies *AnomalyLog
+	// cache holds GET responses, nil if caching is not configured
+	cache *ResponseCache
+	// maintenance switches data requests to the cache during maintenance
+	// of the API, nil if it is not configured
+	maintenance *maintenance
 }
 
-// NewClient creates a new Portal64 API client
-func NewClient(baseURL string, timeout time.Duration, logger *logrus.Logger) *Client {
+// Logger is the logging interface of the client. It is implemented by
+// *logrus.Logger and *logrus.Entry, so a logger with preset fields can be
+// passed as well.
+type Logger = logrus.FieldLogger
+
+// NewClient creates a new Portal64 API client. A nil logger discards all
+// log output.
+func NewClient(baseURL string, timeout time.Duration, logger Logger) *Client {
+	if logger == nil {
+		discard := logrus.New()
+		discard.SetOutput(io.Discard)
+		logger = discard
+	}
+
+	tracker := &connTracker{}
+	tracking, transport := newTrackingTransport(tracker)
+
 	return &Client{
 		baseURL: strings.TrimSuffix(baseURL, "/"),
 		httpClient: &http.Client{
-			Timeout: timeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   timeout,
+			Transport: tracking,
 		},
-		logger: logger,
+		logger:       logger,
+		retryBackoff: defaultRetryBackoff,
+		transport:    transport,
+		connTracker:  tracker,
+	}
+}
+
+// OnServerError adds a function called for each 5xx response of the API,
+// e.g. to report bursts of upstream failures. It must be added before the
+// client is used.
+func (c *Client) OnServerError(hook func(method, url string, status int)) {
+	c.onServerError = append(c.onServerError, hook)
+}
+
+// observeStatus passes server error responses to the OnServerError hooks
+func (c *Client) observeStatus(method, url string, status int) {
+	if status < http.StatusInternalServerError {
+		return
+	}
+	for _, hook := range c.onServerError {
+		hook(method, url, status)
 	}
 }
 
@@ -92,6 +142,12 @@ func (c *Client) addSearchParams(values *url.Values, params SearchParams) {
 	if params.Active != nil {
 		values.Set("active", strconv.FormatBool(*params.Active))
 	}
+	if params.Gender != "" {
+		values.Set("gender", ConvertGenderToAPI(NormalizeGender(params.Gender)))
+	}
+	if params.Title != "" {
+		values.Set("title", strings.ToUpper(params.Title))
+	}
 }
 // addDateRangeParams adds date range parameters to URL values
 func (c *Client) addDateRangeParams(values *url.Values, params DateRangeParams) {
@@ -100,8 +156,51 @@ func (c *Client) addDateRangeParams(values *url.Values, params DateRangeParams)
 	c.addSearchParams(values, params.SearchParams)
 }
 
-// DoRequest performs HTTP request with error handling
+// DoRequest performs HTTP request with error handling. GET requests are
+// served from the response cache if it is configured.
 func (c *Client) DoRequest(ctx context.Context, method, url string) (*http.Response, error) {
+	if !observingCalls(ctx) {
+		return c.serveRequest(ctx, method, url)
+	}
+	var cache string
+	resp, err := c.serveRequest(withCacheStatus(ctx, &cache), method, url)
+	c.observeCall(ctx, method, url, responseStatus(resp), cache, err)
+	return resp, err
+}
+
+// serveRequest answers a request of DoRequest from the response cache or
+// the API. During maintenance of the API, data requests are answered from
+// the cache only, also if the request itself detected the maintenance.
+func (c *Client) serveRequest(ctx context.Context, method, url string) (*http.Response, error) {
+	data := cacheEntity(url) != ""
+	if status := c.Maintenance(); status.Active && data {
+		return c.serveMaintenance(ctx, method, url, status)
+	}
+
+	resp, err := c.requestCached(ctx, method, url)
+	if err != nil && data && !IsNotFound(err) {
+		if status := c.Maintenance(); status.Active {
+			return c.serveMaintenance(ctx, method, url, status)
+		}
+	}
+	return resp, err
+}
+
+// requestCached answers a request from the response cache, if it is
+// configured for the URL, or the API
+func (c *Client) requestCached(ctx context.Context, method, url string) (*http.Response, error) {
+	if method == http.MethodGet {
+		if entity, policy, ok := c.cache.policy(url); ok {
+			return c.cache.get(ctx, url, entity, policy, func(ctx context.Context) (*cachedResponse, error) {
+				return c.fetchCacheable(ctx, url)
+			})
+		}
+	}
+	return c.doRequest(ctx, method, url)
+}
+
+// doRequest performs an HTTP request to the API
+func (c *Client) doRequest(ctx context.Context, method, url string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -123,6 +222,7 @@ func (c *Client) DoRequest(ctx context.Context, method, url string) (*http.Respo
 
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
+		c.observeStatus(method, url, resp.StatusCode)
 		return nil, c.handleErrorResponse(resp)
 	}
 
@@ -131,16 +231,9 @@ func (c *Client) DoRequest(ctx context.Context, method, url string) (*http.Respo
 
 // handleErrorResponse handles non-200 HTTP responses
 func (c *Client) handleErrorResponse(resp *http.Response) error {
-	var errorBody map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&errorBody); err != nil {
-		return fmt.Errorf("API error %d: failed to parse error response", resp.StatusCode)
-	}
-
-	if message, ok := errorBody["message"].(string); ok {
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, message)
-	}
-
-	return fmt.Errorf("API error %d: %v", resp.StatusCode, errorBody)
+	apiErr := decodeAPIError(resp)
+	c.maintenance.observe(apiErr)
+	return apiErr
 }
 
 // DecodeResponse decodes JSON response into provided interface
@@ -165,7 +258,7 @@ func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
 	}
 
 	var health HealthResponse
-	if err := c.DecodeResponse(resp, &health); err != nil {
+	if err := c.decodeInto(resp, &health); err != nil {
 		return nil, err
 	}
 
@@ -182,7 +275,7 @@ func (c *Client) CacheStats(ctx context.Context) (*CacheStatsResponse, error) {
 	}
 
 	var stats CacheStatsResponse
-	if err := c.DecodeResponse(resp, &stats); err != nil {
+	if err := c.decodeInto(resp, &stats); err != nil {
 		return nil, err
 	}
 
@@ -197,25 +290,7 @@ func (c *Client) SearchPlayers(ctx context.Context, params SearchParams) (*Searc
 		return nil, err
 	}
 
-	var searchResp SearchResponse
-	if err := c.DecodeResponse(resp, &searchResp); err != nil {
-		return nil, err
-	}
-
-	// Convert data to []PlayerResponse
-	if dataSlice, ok := searchResp.Data.([]interface{}); ok {
-		players := make([]PlayerResponse, len(dataSlice))
-		for i, item := range dataSlice {
-			if playerData, ok := item.(map[string]interface{}); ok {
-				// Convert map to PlayerResponse struct
-				playerBytes, _ := json.Marshal(playerData)
-				json.Unmarshal(playerBytes, &players[i])
-			}
-		}
-		searchResp.Data = players
-	}
-
-	return &searchResp, nil
+	return decodeSearchResponse[PlayerResponse](c, resp, params)
 }
 
 // GetPlayerProfile retrieves comprehensive player profile with rating history
@@ -227,20 +302,12 @@ func (c *Client) GetPlayerProfile(ctx context.Context, playerID string) (*Player
 		return nil, err
 	}
 
-	// Parse the wrapped API response
-	var apiResp struct {
-		Success bool            `json:"success"`
-		Data    PlayerResponse  `json:"data"`
-	}
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
+	var player PlayerResponse
+	if err := c.decodeInto(resp, &player); err != nil {
 		return nil, err
 	}
 
-	if !apiResp.Success {
-		return nil, fmt.Errorf("API returned unsuccessful response")
-	}
-
-	return &apiResp.Data, nil
+	return &player, nil
 }
 
 // GetPlayerRatingHistory retrieves player's DWZ rating evolution over time
@@ -252,15 +319,9 @@ func (c *Client) GetPlayerRatingHistory(ctx context.Context, playerID string) ([
 		return nil, err
 	}
 
-	// Parse the wrapped API response
-	var apiResp APIResponse
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
-		return nil, err
-	}
-
-	// Parse the rating history entries from the data field
+	// Parse the rating history entries from the (possibly wrapped) response
 	var entries []RatingHistoryEntry
-	if err := json.Unmarshal(apiResp.Data, &entries); err != nil {
+	if err := c.decodeInto(resp, &entries); err != nil {
 		return nil, err
 	}
 
@@ -313,24 +374,7 @@ func (c *Client) SearchClubs(ctx context.Context, params SearchParams) (*SearchR
 		return nil, err
 	}
 
-	var searchResp SearchResponse
-	if err := c.DecodeResponse(resp, &searchResp); err != nil {
-		return nil, err
-	}
-
-	// Convert data to []ClubResponse
-	if dataSlice, ok := searchResp.Data.([]interface{}); ok {
-		clubs := make([]ClubResponse, len(dataSlice))
-		for i, item := range dataSlice {
-			if clubData, ok := item.(map[string]interface{}); ok {
-				clubBytes, _ := json.Marshal(clubData)
-				json.Unmarshal(clubBytes, &clubs[i])
-			}
-		}
-		searchResp.Data = clubs
-	}
-
-	return &searchResp, nil
+	return decodeSearchResponse[ClubResponse](c, resp, params)
 }
 // GetClubProfile retrieves comprehensive club profile with members and statistics
 func (c *Client) GetClubProfile(ctx context.Context, clubID string) (*ClubProfileResponse, error) {
@@ -341,14 +385,8 @@ func (c *Client) GetClubProfile(ctx context.Context, clubID string) (*ClubProfil
 		return nil, err
 	}
 
-	// Parse the wrapped API response
-	var apiResp APIResponse
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
-		return nil, err
-	}
-
 	var profile ClubProfileResponse
-	if err := json.Unmarshal(apiResp.Data, &profile); err != nil {
+	if err := c.decodeInto(resp, &profile); err != nil {
 		return nil, err
 	}
 
@@ -364,24 +402,7 @@ func (c *Client) GetClubPlayers(ctx context.Context, clubID string, params Searc
 		return nil, err
 	}
 
-	var searchResp SearchResponse
-	if err := c.DecodeResponse(resp, &searchResp); err != nil {
-		return nil, err
-	}
-
-	// Convert data to []PlayerResponse
-	if dataSlice, ok := searchResp.Data.([]interface{}); ok {
-		players := make([]PlayerResponse, len(dataSlice))
-		for i, item := range dataSlice {
-			if playerData, ok := item.(map[string]interface{}); ok {
-				playerBytes, _ := json.Marshal(playerData)
-				json.Unmarshal(playerBytes, &players[i])
-			}
-		}
-		searchResp.Data = players
-	}
-
-	return &searchResp, nil
+	return decodeSearchResponse[PlayerResponse](c, resp, params)
 }
 
 // GetClubStatistics retrieves club performance statistics and member analytics
@@ -394,71 +415,59 @@ func (c *Client) GetClubStatistics(ctx context.Context, clubID string) (*ClubRat
 		return nil, err
 	}
 
-	// Parse the wrapped API response
-	var apiResp APIResponse
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
-		return nil, err
-	}
-
-	// Parse profile data to extract rating stats
-	var profileData map[string]interface{}
-	if err := json.Unmarshal(apiResp.Data, &profileData); err != nil {
+	payload, err := c.decodePayload(resp)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse profile data: %w", err)
 	}
 
-	// Extract rating_stats field
-	ratingStatsData, exists := profileData["rating_stats"]
-	if !exists {
-		return nil, fmt.Errorf("no rating statistics available for club %s", clubID)
+	// Only the rating statistics and the member list are decoded
+	var profile struct {
+		RatingStats json.RawMessage `json:"rating_stats"`
+		Players     json.RawMessage `json:"players"`
 	}
-
-	// Convert to map for easier field access
-	statsMap, ok := ratingStatsData.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid rating statistics format for club %s", clubID)
-	}
-
-	// Map API field names to MCP expected field names
-	stats := &ClubRatingStats{
-		RatingDistribution: make(map[string]int),
+	if err := json.Unmarshal(payload.Data, &profile); err != nil {
+		c.logger.WithError(err).Error("Failed to decode API response")
+		return nil, fmt.Errorf("failed to parse profile data: response parsing failed: %w", err)
 	}
-
-	// Map average_dwz -> average_rating
-	if avgDwz, exists := statsMap["average_dwz"]; exists {
-		if avgFloat, ok := avgDwz.(float64); ok {
-			stats.AverageRating = avgFloat
-		}
+	if len(profile.RatingStats) == 0 {
+		return nil, fmt.Errorf("no rating statistics available for club %s", clubID)
 	}
-
-	// Map median_dwz -> median_rating  
-	if medianDwz, exists := statsMap["median_dwz"]; exists {
-		if medianFloat, ok := medianDwz.(float64); ok {
-			stats.MedianRating = medianFloat
-		}
+	if profile.RatingStats[0] != '{' {
+		return nil, fmt.Errorf("invalid rating statistics format for club %s", clubID)
 	}
 
-	// Map highest_dwz -> highest_rating
-	if highestDwz, exists := statsMap["highest_dwz"]; exists {
-		if highestFloat, ok := highestDwz.(float64); ok {
-			stats.HighestRating = int(highestFloat)
-		}
+	// The API returns average_dwz etc., which map to the rating fields.
+	// Values of unexpected types are skipped.
+	var ratingStats struct {
+		AverageDWZ         float64            `json:"average_dwz"`
+		MedianDWZ          float64            `json:"median_dwz"`
+		HighestDWZ         float64            `json:"highest_dwz"`
+		LowestDWZ          float64            `json:"lowest_dwz"`
+		PlayersWithDWZ     float64            `json:"players_with_dwz"`
+		RatingDistribution map[string]float64 `json:"rating_distribution"`
 	}
-
-	// Map lowest_dwz -> lowest_rating
-	if lowestDwz, exists := statsMap["lowest_dwz"]; exists {
-		if lowestFloat, ok := lowestDwz.(float64); ok {
-			stats.LowestRating = int(lowestFloat)
-		}
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(profile.RatingStats, &ratingStats); err != nil && !errors.As(err, &typeErr) {
+		return nil, fmt.Errorf("invalid rating statistics format for club %s", clubID)
 	}
 
-	// Copy rating distribution as-is
-	if ratingDist, exists := statsMap["rating_distribution"]; exists {
-		if distMap, ok := ratingDist.(map[string]interface{}); ok {
-			for category, count := range distMap {
-				if countFloat, ok := count.(float64); ok {
-					stats.RatingDistribution[category] = int(countFloat)
-				}
-			}
+	stats := &ClubRatingStats{
+		AverageRating:      ratingStats.AverageDWZ,
+		MedianRating:       ratingStats.MedianDWZ,
+		HighestRating:      int(ratingStats.HighestDWZ),
+		LowestRating:       int(ratingStats.LowestDWZ),
+		PlayersWithDWZ:     int(ratingStats.PlayersWithDWZ),
+		RatingDistribution: make(map[string]int, len(ratingStats.RatingDistribution)),
+	}
+	for category, count := range ratingStats.RatingDistribution {
+		stats.RatingDistribution[category] = int(count)
+	}
+
+	// Derive gender and title breakdowns from the member list, if included
+	if len(profile.Players) > 0 {
+		var players []PlayerResponse
+		if err := json.Unmarshal(profile.Players, &players); err == nil && len(players) > 0 {
+			stats.GenderDistribution, stats.TitleDistribution = PlayerBreakdown(players)
 		}
 	}
 
@@ -480,24 +489,7 @@ func (c *Client) SearchTournaments(ctx context.Context, params SearchParams) (*S
 		return nil, err
 	}
 
-	var searchResp SearchResponse
-	if err := c.DecodeResponse(resp, &searchResp); err != nil {
-		return nil, err
-	}
-
-	// Convert data to []TournamentResponse
-	if dataSlice, ok := searchResp.Data.([]interface{}); ok {
-		tournaments := make([]TournamentResponse, len(dataSlice))
-		for i, item := range dataSlice {
-			if tournamentData, ok := item.(map[string]interface{}); ok {
-				tournamentBytes, _ := json.Marshal(tournamentData)
-				json.Unmarshal(tournamentBytes, &tournaments[i])
-			}
-		}
-		searchResp.Data = tournaments
-	}
-
-	return &searchResp, nil
+	return decodeSearchResponse[TournamentResponse](c, resp, params)
 }
 // SearchTournamentsByDate searches tournaments by date range
 func (c *Client) SearchTournamentsByDate(ctx context.Context, params DateRangeParams) (*SearchResponse, error) {
@@ -508,12 +500,7 @@ func (c *Client) SearchTournamentsByDate(ctx context.Context, params DateRangePa
 		return nil, err
 	}
 
-	var searchResp SearchResponse
-	if err := c.DecodeResponse(resp, &searchResp); err != nil {
-		return nil, err
-	}
-
-	return &searchResp, nil
+	return decodeSearchResponse[TournamentResponse](c, resp, params.SearchParams)
 }
 
 // GetRecentTournaments retrieves recent tournaments
@@ -530,24 +517,13 @@ func (c *Client) GetRecentTournaments(ctx context.Context, days, limit int) ([]T
 	}
 
 	var tournaments []TournamentResponse
-	if err := c.DecodeResponse(resp, &tournaments); err != nil {
+	if err := c.decodeInto(resp, &tournaments); err != nil {
 		return nil, err
 	}
 
 	return tournaments, nil
 }
 
-// SimpleTournament represents only the fields we need for date extraction
-type SimpleTournament struct {
-	ID           string     `json:"id"`
-	Name         string     `json:"name"`
-	StartDate    *time.Time `json:"start_date"`
-	EndDate      *time.Time `json:"end_date"`
-	FinishedOn   *time.Time `json:"finished_on"`
-	ComputedOn   *time.Time `json:"computed_on"`
-	RecomputedOn *time.Time `json:"recomputed_on"`
-}
-
 // GetTournamentDetails retrieves detailed tournament information
 func (c *Client) GetTournamentDetails(ctx context.Context, tournamentID string) (*EnhancedTournamentResponse, error) {
 	url := c.BuildURL(fmt.Sprintf("/api/v1/tournaments/%s", tournamentID), nil)
@@ -557,40 +533,24 @@ func (c *Client) GetTournamentDetails(ctx context.Context, tournamentID string)
 		return nil, err
 	}
 
-	// Parse as wrapped API response (this is what the real API returns)
-	var apiResp APIResponse
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
+	// The real API wraps the tournament, older variants return it bare
+	payload, err := c.decodePayload(resp)
+	if err != nil {
 		return nil, err
 	}
 
-	// Try to unmarshal the data as a simple tournament first (only date fields)
-	var simpleTournament SimpleTournament
-	if err := json.Unmarshal(apiResp.Data, &simpleTournament); err != nil {
-		return nil, err
+	enhanced, decodeErrs := DecodeTournamentDetails(payload.Data)
+	if enhanced == nil {
+		return nil, fmt.Errorf("response parsing failed: %v", decodeErrs[0])
 	}
 
-	// Convert to full TournamentResponse (copy the date fields we care about)
-	tournament := TournamentResponse{
-		ID: simpleTournament.ID,
-		Name: simpleTournament.Name,
-		StartDate: simpleTournament.StartDate,
-		EndDate: simpleTournament.EndDate,
-	}
-	
-	// Handle nullable time fields
-	if simpleTournament.FinishedOn != nil {
-		tournament.FinishedOn = *simpleTournament.FinishedOn
-	}
-	if simpleTournament.ComputedOn != nil {
-		tournament.ComputedOn = *simpleTournament.ComputedOn
-	}
-	if simpleTournament.RecomputedOn != nil {
-		tournament.RecomputedOn = *simpleTournament.RecomputedOn
+	// Partially decoded sections are returned as far as they could be read
+	for _, decodeErr := range decodeErrs {
+		c.logger.WithError(decodeErr).WithField("tournament_id", tournamentID).
+			Warn("Failed to fully decode tournament details")
 	}
 
-	return &EnhancedTournamentResponse{
-		Tournament: &tournament,
-	}, nil
+	return enhanced, nil
 }
 
 // GetRegions retrieves available regions for address lookups
@@ -602,15 +562,9 @@ func (c *Client) GetRegions(ctx context.Context) ([]RegionInfo, error) {
 		return nil, err
 	}
 
-	// Parse the wrapped API response
-	var apiResp APIResponse
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
-		return nil, err
-	}
-
-	// Parse the regions data from the data field
+	// Parse the regions data from the (possibly wrapped) response
 	var regionAPIResponses []RegionAPIResponse
-	if err := json.Unmarshal(apiResp.Data, &regionAPIResponses); err != nil {
+	if err := c.decodeInto(resp, &regionAPIResponses); err != nil {
 		return nil, err
 	}
 
@@ -643,7 +597,7 @@ func (c *Client) GetRegionAddresses(ctx context.Context, region, addressType str
 	}
 
 	var addresses []RegionAddressResponse
-	if err := c.DecodeResponse(resp, &addresses); err != nil {
+	if err := c.decodeInto(resp, &addresses); err != nil {
 		return nil, err
 	}
 
@@ -659,15 +613,9 @@ func (c *Client) GetTournamentDate(ctx context.Context, tournamentID string) (ti
 		return time.Time{}, err
 	}
 
-	// Parse as wrapped API response
-	var apiResp APIResponse
-	if err := c.DecodeResponse(resp, &apiResp); err != nil {
-		return time.Time{}, err
-	}
-
 	// Parse as generic map to extract date fields
 	var data map[string]interface{}
-	if err := json.Unmarshal(apiResp.Data, &data); err != nil {
+	if err := c.decodeInto(resp, &data); err != nil {
 		return time.Time{}, err
 	}
 
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"math/rand"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paramRunes are the characters of generated parameter values: German
+// letters as in club and player names, and characters with a meaning in
+// URLs
+var paramRunes = []rune("abcXYZäöüÄÖÜß019 -.,'&=+?#%/é")
+
+// randomParam returns a short, possibly empty string of paramRunes
+func randomParam(r *rand.Rand) string {
+	if r.Intn(4) == 0 {
+		return ""
+	}
+	value := make([]rune, 1+r.Intn(12))
+	for i := range value {
+		value[i] = paramRunes[r.Intn(len(paramRunes))]
+	}
+	return string(value)
+}
+
+// generatedSearchParams generates SearchParams for testing/quick
+type generatedSearchParams struct {
+	SearchParams
+}
+
+func (generatedSearchParams) Generate(r *rand.Rand, size int) reflect.Value {
+	params := SearchParams{
+		Query:       randomParam(r),
+		Limit:       r.Intn(3) * r.Intn(500),
+		Offset:      r.Intn(3) * r.Intn(500),
+		SortBy:      randomParam(r),
+		SortOrder:   randomParam(r),
+		FilterBy:    randomParam(r),
+		FilterValue: randomParam(r),
+		Gender:      []string{"", "male", "female", "divers", "w", "M", "Weiblich", randomParam(r)}[r.Intn(8)],
+		Title:       []string{"", "gm", "WIM", "fm", randomParam(r)}[r.Intn(5)],
+	}
+	if r.Intn(3) > 0 {
+		active := r.Intn(2) == 0
+		params.Active = &active
+	}
+	return reflect.ValueOf(generatedSearchParams{params})
+}
+
+// expectedQuery returns the query parameters BuildURL must send for params
+func expectedQuery(params SearchParams) url.Values {
+	want := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			want[key] = []string{value}
+		}
+	}
+	set("query", params.Query)
+	if params.Limit > 0 {
+		set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Offset > 0 {
+		set("offset", strconv.Itoa(params.Offset))
+	}
+	set("sort_by", params.SortBy)
+	set("sort_order", params.SortOrder)
+	set("filter_by", params.FilterBy)
+	set("filter_value", params.FilterValue)
+	if params.Active != nil {
+		set("active", strconv.FormatBool(*params.Active))
+	}
+	if params.Gender != "" {
+		set("gender", ConvertGenderToAPI(NormalizeGender(params.Gender)))
+	}
+	set("title", strings.ToUpper(params.Title))
+	return want
+}
+
+func TestClient_BuildURL_SearchParamsRoundTrip(t *testing.T) {
+	client := createTestClient()
+
+	property := func(generated generatedSearchParams) bool {
+		built := client.BuildURL("/api/v1/clubs", generated.SearchParams)
+		u, err := url.Parse(built)
+		if err != nil {
+			t.Logf("%s: %v", built, err)
+			return false
+		}
+		if u.Path != "/api/v1/clubs" || u.Fragment != "" || strings.ContainsAny(u.RawQuery, " #") {
+			t.Logf("%s: query not escaped", built)
+			return false
+		}
+		got, err := url.ParseQuery(u.RawQuery)
+		if err != nil || !reflect.DeepEqual(expectedQuery(generated.SearchParams), got) {
+			t.Logf("%+v: got %v from %s", generated.SearchParams, got, built)
+			return false
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 2000}))
+}
+
+func TestClient_BuildURL_DateRangeParamsRoundTrip(t *testing.T) {
+	client := createTestClient()
+
+	property := func(generated generatedSearchParams, days uint16) bool {
+		start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days%2000))
+		params := DateRangeParams{StartDate: start, EndDate: start.AddDate(0, 0, 30), SearchParams: generated.SearchParams}
+
+		u, err := url.Parse(client.BuildURL("/api/v1/tournaments/search", params))
+		if err != nil {
+			return false
+		}
+		want := expectedQuery(generated.SearchParams)
+		want.Set("start_date", start.Format("2006-01-02"))
+		want.Set("end_date", start.AddDate(0, 0, 30).Format("2006-01-02"))
+		return reflect.DeepEqual(want, u.Query())
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 500}))
+}
+
+func TestClient_BuildURL_GermanClubNames(t *testing.T) {
+	client := createTestClient()
+
+	for _, name := range []string{"Schachfreunde Nürtingen", "SG Bad Cannstatt 1877", "Schwäbisch Gmünd", "Gießen & Umgebung", "Schach 50+"} {
+		u, err := url.Parse(client.BuildURL("/api/v1/clubs", SearchParams{Query: name}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{name}, u.Query()["query"])
+	}
+}
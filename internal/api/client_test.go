@@ -33,6 +33,28 @@ func TestNewClient_TrimsTrailingSlash(t *testing.T) {
 	assert.Equal(t, "http://localhost:8080", client.baseURL)
 }
 
+func TestNewClient_NilLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	
+	client := NewClient(server.URL, 30*time.Second, nil)
+	
+	assert.NotPanics(t, func() {
+		_, err := client.GetPlayerProfile(context.Background(), "C0327-297")
+		assert.Error(t, err)
+	})
+}
+
+func TestNewClient_LoggerEntry(t *testing.T) {
+	entry := testutil.NewTestLogger().WithField("component", "api")
+	
+	client := NewClient("http://localhost:8080", 30*time.Second, entry)
+	
+	assert.Equal(t, entry, client.logger)
+}
+
 func TestClient_BuildURL(t *testing.T) {
 	client := createTestClient()
 	
@@ -60,6 +82,18 @@ func TestClient_BuildURL(t *testing.T) {
 		assert.Contains(t, url, "sort_order=asc")
 	})
 	
+	t.Run("With gender and title params", func(t *testing.T) {
+		params := SearchParams{
+			Gender: "female",
+			Title:  "wfm",
+		}
+		
+		url := client.BuildURL("/api/players", params)
+		
+		assert.Contains(t, url, "gender=w")
+		assert.Contains(t, url, "title=WFM")
+	})
+	
 	t.Run("With date range params", func(t *testing.T) {
 		startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		endDate := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
@@ -123,7 +157,7 @@ func TestClient_PublicMethods(t *testing.T) {
 		player, err := client.GetPlayerProfile(ctx, "12345")
 		require.NoError(t, err)
 		assert.NotNil(t, player)
-		assert.Equal(t, "12345", player.ID)
+		assert.Equal(t, fixtures.Player["id"], player.ID)
 	})
 	
 	t.Run("Search clubs", func(t *testing.T) {
@@ -273,3 +307,42 @@ func createTestClientWithURL(baseURL string) *Client {
 	logger := testutil.NewTestLogger()
 	return NewClient(baseURL, 30*time.Second, logger)
 }
+
+func TestClient_GetClubStatistics(t *testing.T) {
+	respond := func(profile string) *Client {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success": true, "data": ` + profile + `}`))
+		}))
+		t.Cleanup(server.Close)
+		return NewClient(server.URL, 5*time.Second, nil)
+	}
+	ctx := context.Background()
+
+	t.Run("Maps rating statistics and members", func(t *testing.T) {
+		client := respond(`{
+			"rating_stats": {"average_dwz": 1587.5, "median_dwz": "n/a", "highest_dwz": 2012.0, "lowest_dwz": 1101,
+				"players_with_dwz": 2, "rating_distribution": {"1400-1599": 9, "1600-1799": "x"}},
+			"players": [{"id": "C0327-1", "gender": "m"}, {"id": "C0327-2", "gender": "w", "title": "WFM"}]
+		}`)
+		stats, err := client.GetClubStatistics(ctx, "C0327")
+		require.NoError(t, err)
+		assert.Equal(t, 1587.5, stats.AverageRating)
+		assert.Zero(t, stats.MedianRating, "values of unexpected types are skipped")
+		assert.Equal(t, 2012, stats.HighestRating)
+		assert.Equal(t, 1101, stats.LowestRating)
+		assert.Equal(t, 2, stats.PlayersWithDWZ)
+		assert.Equal(t, 9, stats.RatingDistribution["1400-1599"])
+		assert.Equal(t, 1, stats.GenderDistribution["female"])
+		assert.Equal(t, 1, stats.TitleDistribution["WFM"])
+	})
+
+	t.Run("Missing statistics", func(t *testing.T) {
+		_, err := respond(`{"players": []}`).GetClubStatistics(ctx, "C0327")
+		assert.ErrorContains(t, err, "no rating statistics available")
+	})
+
+	t.Run("Invalid statistics", func(t *testing.T) {
+		_, err := respond(`{"rating_stats": [1, 2]}`).GetClubStatistics(ctx, "C0327")
+		assert.ErrorContains(t, err, "invalid rating statistics format")
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/test/vcr"
+)
+
+// Tests in this file replay cassettes recorded against the real Portal64 API.
+// Re-record with: VCR_MODE=record PORTAL64_API_URL=<url> go test ./internal/api -run VCR
+
+func TestClient_VCR_PlayerProfile(t *testing.T) {
+	client := createTestClientWithURL(vcr.BaseURL())
+	client.httpClient.Transport = vcr.Open(t, "player_profile", client.httpClient.Transport)
+	ctx := context.Background()
+
+	player, err := client.GetPlayerProfile(ctx, "C0327-297")
+	require.NoError(t, err)
+	assert.Equal(t, "C0327-297", player.ID)
+	assert.Equal(t, "male", player.Gender)
+	assert.Equal(t, 1643, player.CurrentDWZ)
+
+	history, err := client.GetPlayerRatingHistory(ctx, "C0327-297")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 33, history[0].DWZChange)
+	assert.Equal(t, "Ulm Open 2024", history[0].TournamentName)
+	assert.Equal(t, 2024, history[0].Date.Year())
+
+	_, err = client.GetPlayerProfile(ctx, "C0000-000")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestClient_VCR_ClubProfile(t *testing.T) {
+	client := createTestClientWithURL(vcr.BaseURL())
+	client.httpClient.Transport = vcr.Open(t, "club_profile", client.httpClient.Transport)
+	ctx := context.Background()
+
+	profile, err := client.GetClubProfile(ctx, "C0327")
+	require.NoError(t, err)
+	require.NotNil(t, profile.Club)
+	assert.Equal(t, "SC Altbach", profile.Club.Name)
+	assert.Len(t, profile.Players, 1)
+
+	stats, err := client.GetClubStatistics(ctx, "C0327")
+	require.NoError(t, err)
+	assert.Equal(t, 1587.5, stats.AverageRating)
+	assert.Equal(t, 2012, stats.HighestRating)
+	assert.Equal(t, 9, stats.RatingDistribution["1400-1599"])
+
+	regions, err := client.GetRegions(ctx)
+	require.NoError(t, err)
+	require.Len(t, regions, 2)
+	assert.Equal(t, "Württemberg", regions[0].Name)
+}
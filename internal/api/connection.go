@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// ConnectionOptions configures the connection pool of the client. Zero
+// values keep the defaults.
+type ConnectionOptions struct {
+	DisableHTTP2        bool          // Use HTTP/1.1 even if the upstream supports HTTP/2
+	DisableKeepAlives   bool          // Open a new connection for every request
+	KeepAliveInterval   time.Duration // TCP keep-alive probe interval, default 30s
+	IdleTimeout         time.Duration // Idle pooled connections are closed after this, default 90s
+	MaxIdleConnsPerHost int           // Default 10
+}
+
+// ConfigureConnection applies connection options to the client's
+// transport. Like ConfigureTLS, it must be called before the client is
+// used.
+func (c *Client) ConfigureConnection(opts ConnectionOptions) {
+	if opts.DisableHTTP2 {
+		// A non-nil empty map keeps the transport from negotiating HTTP/2
+		c.transport.ForceAttemptHTTP2 = false
+		c.transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	c.transport.DisableKeepAlives = opts.DisableKeepAlives
+	if opts.KeepAliveInterval > 0 {
+		dialer := &net.Dialer{Timeout: dialTimeout, KeepAlive: opts.KeepAliveInterval}
+		c.transport.DialContext = c.connTracker.dialer(dialer.DialContext)
+	}
+	if opts.IdleTimeout > 0 {
+		c.transport.IdleConnTimeout = opts.IdleTimeout
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		c.transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+}
+
+// http2Enabled reports whether the transport negotiates HTTP/2
+func (c *Client) http2Enabled() bool {
+	return c.transport.ForceAttemptHTTP2
+}
+
+// ConnectionDiagnosis describes a fresh connection to the Portal64 API
+type ConnectionDiagnosis struct {
+	URL            string        `json:"url"`
+	RemoteAddress  string        `json:"remote_address,omitempty"`
+	StatusCode     int           `json:"status_code,omitempty"`
+	Protocol       string        `json:"protocol,omitempty"` // Negotiated HTTP version, e.g. HTTP/2.0
+	HTTP2Enabled   bool          `json:"http2_enabled"`
+	KeepAlive      bool          `json:"keep_alive"`
+	TLS            *TLSDiagnosis `json:"tls,omitempty"` // Nil for plain HTTP
+	DNSMS          float64       `json:"dns_ms"`
+	ConnectMS      float64       `json:"connect_ms"`
+	TLSHandshakeMS float64       `json:"tls_handshake_ms"`
+	FirstByteMS    float64       `json:"first_byte_ms"`
+	TotalMS        float64       `json:"total_ms"`
+	Error          string        `json:"error,omitempty"`
+	Notes          []string      `json:"notes,omitempty"`
+}
+
+// TLSDiagnosis describes the negotiated TLS parameters of a connection
+type TLSDiagnosis struct {
+	Version            string     `json:"version"`
+	CipherSuite        string     `json:"cipher_suite"`
+	NegotiatedProtocol string     `json:"negotiated_protocol,omitempty"` // ALPN, "h2" for HTTP/2
+	ServerName         string     `json:"server_name,omitempty"`
+	CertificateExpires *time.Time `json:"certificate_expires,omitempty"`
+}
+
+// DiagnoseConnection opens a new connection to the primary base URL with
+// the settings of the client's transport and requests /health on it. The
+// connection pool and its statistics are not affected. A failed request is
+// reported in the diagnosis, together with the phases that completed.
+func (c *Client) DiagnoseConnection(ctx context.Context) *ConnectionDiagnosis {
+	// A clone of the transport would share its HTTP/2 connections
+	transport := &http.Transport{
+		Proxy:               c.transport.Proxy,
+		DialContext:         (&net.Dialer{Timeout: dialTimeout}).DialContext,
+		TLSClientConfig:     c.transport.TLSClientConfig.Clone(),
+		TLSHandshakeTimeout: c.transport.TLSHandshakeTimeout,
+		ForceAttemptHTTP2:   c.http2Enabled(),
+	}
+	if !transport.ForceAttemptHTTP2 {
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	defer transport.CloseIdleConnections()
+
+	diagnosis := &ConnectionDiagnosis{
+		URL:          c.baseURL + "/health",
+		HTTP2Enabled: c.http2Enabled(),
+		KeepAlive:    !c.transport.DisableKeepAlives,
+	}
+
+	var dnsStart, connectStart, tlsStart, gotConn time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			diagnosis.DNSMS = millisecondsSince(dnsStart)
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				diagnosis.ConnectMS = millisecondsSince(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				diagnosis.TLSHandshakeMS = millisecondsSince(tlsStart)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			gotConn = time.Now()
+			diagnosis.RemoteAddress = info.Conn.RemoteAddr().String()
+		},
+		GotFirstResponseByte: func() {
+			diagnosis.FirstByteMS = millisecondsSince(gotConn)
+		},
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, diagnosis.URL, nil)
+	if err != nil {
+		diagnosis.Error = err.Error()
+		return diagnosis
+	}
+	client := &http.Client{Transport: transport, Timeout: c.httpClient.Timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		diagnosis.TotalMS = millisecondsSince(start)
+		diagnosis.Error = err.Error()
+		return diagnosis
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	diagnosis.TotalMS = millisecondsSince(start)
+
+	diagnosis.StatusCode = resp.StatusCode
+	diagnosis.Protocol = resp.Proto
+	if resp.TLS != nil {
+		diagnosis.TLS = &TLSDiagnosis{
+			Version:            tls.VersionName(resp.TLS.Version),
+			CipherSuite:        tls.CipherSuiteName(resp.TLS.CipherSuite),
+			NegotiatedProtocol: resp.TLS.NegotiatedProtocol,
+			ServerName:         resp.TLS.ServerName,
+		}
+		if len(resp.TLS.PeerCertificates) > 0 {
+			expires := resp.TLS.PeerCertificates[0].NotAfter
+			diagnosis.TLS.CertificateExpires = &expires
+		}
+	}
+
+	switch {
+	case !diagnosis.HTTP2Enabled:
+		diagnosis.Notes = append(diagnosis.Notes, "HTTP/2 is disabled for this client")
+	case diagnosis.TLS == nil:
+		diagnosis.Notes = append(diagnosis.Notes, "HTTP/2 is only negotiated over TLS, the upstream uses plain HTTP")
+	case resp.ProtoMajor < 2:
+		diagnosis.Notes = append(diagnosis.Notes, "The upstream does not support HTTP/2")
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		diagnosis.Notes = append(diagnosis.Notes, fmt.Sprintf("The health endpoint returned status %d", resp.StatusCode))
+	}
+	return diagnosis
+}
+
+// millisecondsSince returns the time since start in milliseconds, 0 if
+// start is not set
+func millisecondsSince(start time.Time) float64 {
+	if start.IsZero() {
+		return 0
+	}
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
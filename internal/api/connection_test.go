@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSTestClient returns a client trusting the certificate of a TLS test
+// server with HTTP/2 enabled
+func newTLSTestClient(t *testing.T, opts ConnectionOptions) *Client {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "healthy"}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, 5*time.Second, nil)
+	client.transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	client.ConfigureConnection(opts)
+	return client
+}
+
+func TestClient_HTTP2(t *testing.T) {
+	client := newTLSTestClient(t, ConnectionOptions{})
+	for i := 0; i < 2; i++ {
+		_, err := client.Health(context.Background())
+		require.NoError(t, err)
+	}
+
+	stats := client.ConnectionStats()
+	assert.True(t, stats.HTTP2Enabled)
+	assert.True(t, stats.KeepAlive)
+	assert.Equal(t, map[string]int64{"HTTP/2.0": 2}, stats.Protocols)
+	assert.Equal(t, int64(1), stats.ReusedConnections)
+}
+
+func TestClient_ConfigureConnection(t *testing.T) {
+	client := newTLSTestClient(t, ConnectionOptions{
+		DisableHTTP2:        true,
+		DisableKeepAlives:   true,
+		IdleTimeout:         time.Minute,
+		MaxIdleConnsPerHost: 4,
+	})
+	assert.Equal(t, time.Minute, client.transport.IdleConnTimeout)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Health(context.Background())
+		require.NoError(t, err)
+	}
+
+	stats := client.ConnectionStats()
+	assert.False(t, stats.HTTP2Enabled)
+	assert.False(t, stats.KeepAlive)
+	assert.Equal(t, 4, stats.MaxIdleConnsPerHost)
+	assert.Equal(t, map[string]int64{"HTTP/1.1": 2}, stats.Protocols)
+	assert.Equal(t, int64(2), stats.DialedConnections)
+	assert.Zero(t, stats.ReusedConnections)
+}
+
+func TestClient_DiagnoseConnection(t *testing.T) {
+	t.Run("HTTP/2 over TLS", func(t *testing.T) {
+		client := newTLSTestClient(t, ConnectionOptions{})
+		diagnosis := client.DiagnoseConnection(context.Background())
+
+		assert.Empty(t, diagnosis.Error)
+		assert.Equal(t, http.StatusOK, diagnosis.StatusCode)
+		assert.Equal(t, "HTTP/2.0", diagnosis.Protocol)
+		require.NotNil(t, diagnosis.TLS)
+		assert.Equal(t, "h2", diagnosis.TLS.NegotiatedProtocol)
+		assert.Equal(t, "TLS 1.3", diagnosis.TLS.Version)
+		assert.NotNil(t, diagnosis.TLS.CertificateExpires)
+		assert.Positive(t, diagnosis.TLSHandshakeMS)
+		assert.NotEmpty(t, diagnosis.RemoteAddress)
+		assert.Empty(t, diagnosis.Notes)
+
+		// The diagnosis does not use the pool
+		assert.Zero(t, client.ConnectionStats().Requests)
+	})
+
+	t.Run("HTTP/2 disabled", func(t *testing.T) {
+		client := newTLSTestClient(t, ConnectionOptions{DisableHTTP2: true})
+		diagnosis := client.DiagnoseConnection(context.Background())
+
+		assert.Equal(t, "HTTP/1.1", diagnosis.Protocol)
+		assert.Equal(t, []string{"HTTP/2 is disabled for this client"}, diagnosis.Notes)
+	})
+
+	t.Run("Plain HTTP", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		diagnosis := NewClient(server.URL, 5*time.Second, nil).DiagnoseConnection(context.Background())
+		assert.Equal(t, "HTTP/1.1", diagnosis.Protocol)
+		assert.Nil(t, diagnosis.TLS)
+		assert.Len(t, diagnosis.Notes, 2)
+		assert.Contains(t, diagnosis.Notes[1], "status 503")
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		diagnosis := NewClient(server.URL, 5*time.Second, nil).DiagnoseConnection(context.Background())
+		assert.NotEmpty(t, diagnosis.Error)
+		assert.Zero(t, diagnosis.StatusCode)
+	})
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConnectionStats describes the connection pool of the client's transport
+type ConnectionStats struct {
+	OpenConnections     int64            `json:"open_connections"`
+	ActiveConnections   int64            `json:"active_connections"` // In use by a request
+	IdleConnections     int64            `json:"idle_connections"`
+	DialedConnections   int64            `json:"dialed_connections"` // Total since start
+	Requests            int64            `json:"requests"`
+	ReusedConnections   int64            `json:"reused_connections"` // Requests served on a pooled connection
+	ReuseRate           float64          `json:"reuse_rate"`
+	MaxIdleConns        int              `json:"max_idle_conns"`
+	MaxIdleConnsPerHost int              `json:"max_idle_conns_per_host"`
+	HTTP2Enabled        bool             `json:"http2_enabled"`
+	KeepAlive           bool             `json:"keep_alive"`
+	Protocols           map[string]int64 `json:"protocols,omitempty"` // Responses by HTTP version, e.g. HTTP/2.0
+	DNS                 TimingStats      `json:"dns"`
+	Connect             TimingStats      `json:"connect"`
+	TLSHandshake        TimingStats      `json:"tls_handshake"`
+	FirstByte           TimingStats      `json:"first_byte"`          // From acquiring a connection to the first response byte
+	RecentLatencyMS     float64          `json:"recent_latency_ms"`   // Moving average of the response time, decaying while idle
+	Upstreams           []UpstreamStats  `json:"upstreams,omitempty"` // Only with fallback upstreams configured
+}
+
+// TimingStats aggregates the durations of a connection phase
+type TimingStats struct {
+	Count     int64   `json:"count"`
+	AverageMS float64 `json:"average_ms"`
+	MaxMS     float64 `json:"max_ms"`
+}
+
+// timing accumulates durations of a connection phase
+type timing struct {
+	mu    sync.Mutex
+	count int64
+	total time.Duration
+	max   time.Duration
+}
+
+func (t *timing) observe(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count++
+	t.total += d
+	if d > t.max {
+		t.max = d
+	}
+}
+
+func (t *timing) stats() TimingStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := TimingStats{Count: t.count, MaxMS: float64(t.max) / float64(time.Millisecond)}
+	if t.count > 0 {
+		stats.AverageMS = float64(t.total) / float64(t.count) / float64(time.Millisecond)
+	}
+	return stats
+}
+
+const (
+	// latencyHalfLife is the idle time after which the recent latency has
+	// decayed to half, so that a slow period does not keep it up forever
+	latencyHalfLife = 10 * time.Second
+	// latencyWeight is the minimum weight of a new sample of the recent
+	// latency
+	latencyWeight = 0.2
+)
+
+// latencyAverage is an exponentially weighted moving average of response
+// times
+type latencyAverage struct {
+	mu      sync.Mutex
+	value   float64 // Nanoseconds
+	updated time.Time
+}
+
+// decayed returns the average decayed for the idle time since the last
+// sample
+func (a *latencyAverage) decayed(now time.Time) float64 {
+	if a.updated.IsZero() {
+		return 0
+	}
+	return a.value * math.Exp2(-now.Sub(a.updated).Seconds()/latencyHalfLife.Seconds())
+}
+
+func (a *latencyAverage) observe(d time.Duration, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.updated.IsZero() {
+		a.value = float64(d)
+	} else {
+		decayed := a.decayed(now)
+		weight := max(latencyWeight, 1-decayed/max(a.value, 1))
+		a.value = decayed*(1-weight) + float64(d)*weight
+	}
+	a.updated = now
+}
+
+func (a *latencyAverage) get(now time.Time) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return time.Duration(a.decayed(now))
+}
+
+// connTracker counts connections and requests and records connection phase
+// timings via httptrace
+type connTracker struct {
+	open     atomic.Int64
+	dialed   atomic.Int64
+	active   atomic.Int64
+	requests atomic.Int64
+	reused   atomic.Int64
+
+	dns       timing
+	connect   timing
+	tls       timing
+	firstByte timing
+	latency   latencyAverage
+
+	protocolsMu sync.Mutex
+	protocols   map[string]int64
+}
+
+// countProtocol counts a response by its HTTP version
+func (t *connTracker) countProtocol(proto string) {
+	t.protocolsMu.Lock()
+	defer t.protocolsMu.Unlock()
+	if t.protocols == nil {
+		t.protocols = make(map[string]int64)
+	}
+	t.protocols[proto]++
+}
+
+// protocolCounts returns a copy of the responses by HTTP version
+func (t *connTracker) protocolCounts() map[string]int64 {
+	t.protocolsMu.Lock()
+	defer t.protocolsMu.Unlock()
+	if len(t.protocols) == 0 {
+		return nil
+	}
+	counts := make(map[string]int64, len(t.protocols))
+	for proto, n := range t.protocols {
+		counts[proto] = n
+	}
+	return counts
+}
+
+// trackedConn decrements the open connection count when closed
+type trackedConn struct {
+	net.Conn
+	once    sync.Once
+	tracker *connTracker
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() { c.tracker.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// dialer wraps a dial function to count open connections
+func (t *connTracker) dialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		t.open.Add(1)
+		t.dialed.Add(1)
+		return &trackedConn{Conn: conn, tracker: t}, nil
+	}
+}
+
+// trace returns a client trace recording connection reuse and timings
+func (t *connTracker) trace() *httptrace.ClientTrace {
+	var dnsStart, connectStart, tlsStart, gotConn time.Time
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			if !dnsStart.IsZero() {
+				t.dns.observe(time.Since(dnsStart))
+			}
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil && !connectStart.IsZero() {
+				t.connect.observe(time.Since(connectStart))
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil && !tlsStart.IsZero() {
+				t.tls.observe(time.Since(tlsStart))
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			gotConn = time.Now()
+			if info.Reused {
+				t.reused.Add(1)
+			}
+		},
+		GotFirstResponseByte: func() {
+			if !gotConn.IsZero() {
+				t.firstByte.observe(time.Since(gotConn))
+			}
+		},
+	}
+}
+
+// trackingTransport instruments requests of the underlying transport
+type trackingTransport struct {
+	base    *http.Transport
+	tracker *connTracker
+}
+
+// RoundTrip implements http.RoundTripper. A connection counts as active
+// until the response body is closed.
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.tracker.requests.Add(1)
+	t.tracker.active.Add(1)
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), t.tracker.trace()))
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	t.tracker.latency.observe(time.Since(start), time.Now())
+	if err != nil {
+		t.tracker.active.Add(-1)
+		return nil, err
+	}
+	t.tracker.countProtocol(resp.Proto)
+
+	resp.Body = &trackedBody{ReadCloser: resp.Body, done: func() { t.tracker.active.Add(-1) }}
+	return resp, nil
+}
+
+// trackedBody calls done once when the body is closed
+type trackedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(b.done)
+	return b.ReadCloser.Close()
+}
+
+// dialTimeout bounds establishing a connection to the API
+const dialTimeout = 30 * time.Second
+
+// newTrackingTransport creates the instrumented transport of a client.
+// HTTP/2 has to be enabled explicitly since the transport uses a custom
+// dialer.
+func newTrackingTransport(tracker *connTracker) (*trackingTransport, *http.Transport) {
+	dialer := &net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}
+	base := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         tracker.dialer(dialer.DialContext),
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	return &trackingTransport{base: base, tracker: tracker}, base
+}
+
+// ConnectionStats returns statistics of the client's connection pool
+func (c *Client) ConnectionStats() ConnectionStats {
+	t := c.connTracker
+	stats := ConnectionStats{
+		OpenConnections:     t.open.Load(),
+		ActiveConnections:   t.active.Load(),
+		DialedConnections:   t.dialed.Load(),
+		Requests:            t.requests.Load(),
+		ReusedConnections:   t.reused.Load(),
+		MaxIdleConns:        c.transport.MaxIdleConns,
+		MaxIdleConnsPerHost: c.transport.MaxIdleConnsPerHost,
+		HTTP2Enabled:        c.http2Enabled(),
+		KeepAlive:           !c.transport.DisableKeepAlives,
+		Protocols:           t.protocolCounts(),
+		DNS:                 t.dns.stats(),
+		Connect:             t.connect.stats(),
+		TLSHandshake:        t.tls.stats(),
+		FirstByte:           t.firstByte.stats(),
+		RecentLatencyMS:     float64(c.RecentLatency()) / float64(time.Millisecond),
+		Upstreams:           c.UpstreamStats(),
+	}
+
+	stats.IdleConnections = max(stats.OpenConnections-stats.ActiveConnections, 0)
+	if stats.Requests > 0 {
+		stats.ReuseRate = float64(stats.ReusedConnections) / float64(stats.Requests)
+	}
+	return stats
+}
+
+// RecentLatency returns the moving average of the response time of the API,
+// which decays towards zero while no requests are made
+func (c *Client) RecentLatency() time.Duration {
+	return c.connTracker.latency.get(time.Now())
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ConnectionStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "healthy"}`))
+	}))
+	defer server.Close()
+
+	client := createTestClientWithURL(server.URL)
+	ctx := context.Background()
+
+	stats := client.ConnectionStats()
+	assert.Zero(t, stats.Requests)
+	assert.Zero(t, stats.ReuseRate)
+	assert.Equal(t, 100, stats.MaxIdleConns)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Health(ctx)
+		require.NoError(t, err)
+	}
+
+	stats = client.ConnectionStats()
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(1), stats.DialedConnections)
+	assert.Equal(t, int64(2), stats.ReusedConnections)
+	assert.InDelta(t, 2.0/3.0, stats.ReuseRate, 0.001)
+	assert.Equal(t, int64(1), stats.OpenConnections)
+	assert.Equal(t, int64(0), stats.ActiveConnections)
+	assert.Equal(t, int64(1), stats.IdleConnections)
+	assert.Equal(t, int64(1), stats.Connect.Count)
+	assert.Equal(t, int64(3), stats.FirstByte.Count)
+	assert.Zero(t, stats.TLSHandshake.Count)
+	assert.Positive(t, stats.RecentLatencyMS)
+
+	client.transport.CloseIdleConnections()
+	assert.Eventually(t, func() bool {
+		return client.ConnectionStats().OpenConnections == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClient_ConnectionStats_ActiveUntilBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := createTestClientWithURL(server.URL)
+
+	resp, err := client.DoRequest(context.Background(), "GET", server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), client.ConnectionStats().ActiveConnections)
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, int64(0), client.ConnectionStats().ActiveConnections)
+}
+
+func TestClient_ConnectionStats_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "healthy"}`))
+	}))
+	defer server.Close()
+
+	client := createTestClientWithURL(server.URL)
+	require.NoError(t, client.ConfigureTLS(TLSOptions{CAFile: writeServerCA(t, t.TempDir(), server)}))
+
+	_, err := client.Health(context.Background())
+	require.NoError(t, err)
+
+	stats := client.ConnectionStats()
+	assert.Equal(t, int64(1), stats.TLSHandshake.Count)
+	assert.Greater(t, stats.TLSHandshake.MaxMS, 0.0)
+}
+
+func TestLatencyAverage(t *testing.T) {
+	var a latencyAverage
+	now := time.Now()
+	assert.Zero(t, a.get(now))
+
+	a.observe(100*time.Millisecond, now)
+	assert.Equal(t, 100*time.Millisecond, a.get(now))
+
+	// Back-to-back samples move the average by the minimum weight
+	a.observe(600*time.Millisecond, now)
+	assert.Equal(t, 200*time.Millisecond, a.get(now))
+
+	// While idle the average decays by half every half-life
+	assert.Equal(t, 100*time.Millisecond, a.get(now.Add(latencyHalfLife)))
+
+	// After a long idle time a new sample dominates
+	later := now.Add(10 * latencyHalfLife)
+	a.observe(50*time.Millisecond, later)
+	assert.InDelta(t, float64(50*time.Millisecond), float64(a.get(later)), float64(time.Millisecond))
+}
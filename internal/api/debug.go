@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// redactedHeaders are response headers whose values debug requests do not
+// return
+var redactedHeaders = map[string]bool{"Set-Cookie": true, "Www-Authenticate": true}
+
+// DebugResult is the outcome of a GET request to the API made to diagnose
+// connectivity
+type DebugResult struct {
+	URL              string            `json:"url"`
+	StatusCode       int               `json:"status_code,omitempty"`
+	Status           string            `json:"status,omitempty"`
+	Protocol         string            `json:"protocol,omitempty"`
+	TLSVersion       string            `json:"tls_version,omitempty"`
+	RemoteAddress    string            `json:"remote_address,omitempty"`
+	ReusedConnection bool              `json:"reused_connection"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	// Phases of the request in milliseconds. Phases of new connections are
+	// 0 for reused ones.
+	DNSMS          float64 `json:"dns_ms"`
+	ConnectMS      float64 `json:"connect_ms"`
+	TLSHandshakeMS float64 `json:"tls_handshake_ms"`
+	FirstByteMS    float64 `json:"first_byte_ms"`
+	TotalMS        float64 `json:"total_ms"`
+	BodyBytes      int64   `json:"body_bytes"`
+	Body           string  `json:"body,omitempty"` // Up to the requested size
+	BodyTruncated  bool    `json:"body_truncated,omitempty"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// DebugGet sends a GET request for an API path through the client's
+// transport, with its authentication, signing and failover but without the
+// response cache, and reports the response with the timing of each phase
+// and up to maxBody bytes of the body. A failed request is reported in the
+// result, together with the phases that completed.
+func (c *Client) DebugGet(ctx context.Context, apiPath string, query url.Values, maxBody int) (*DebugResult, error) {
+	if !strings.HasPrefix(apiPath, "/") || path.Clean(apiPath) != apiPath || strings.ContainsAny(apiPath, "?#") {
+		return nil, fmt.Errorf("invalid API path %q, expected an absolute path without . or .. segments, query or fragment", apiPath)
+	}
+	result := &DebugResult{URL: c.baseURL + apiPath}
+	if len(query) > 0 {
+		result.URL += "?" + query.Encode()
+	}
+
+	var dnsStart, connectStart, tlsStart, gotConn time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			result.DNSMS = millisecondsSince(dnsStart)
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				result.ConnectMS = millisecondsSince(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				result.TLSHandshakeMS = millisecondsSince(tlsStart)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			gotConn = time.Now()
+			result.ReusedConnection = info.Reused
+			result.RemoteAddress = info.Conn.RemoteAddr().String()
+		},
+		GotFirstResponseByte: func() {
+			result.FirstByteMS = millisecondsSince(gotConn)
+		},
+	}
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, result.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		result.TotalMS = millisecondsSince(start)
+		result.Error = err.Error()
+		return result, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(max(maxBody, 0))))
+	rest, restErr := io.Copy(io.Discard, resp.Body)
+	result.TotalMS = millisecondsSince(start)
+	if err == nil {
+		err = restErr
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("reading the body failed: %v", err)
+	}
+	result.BodyBytes = int64(len(body)) + rest
+	result.Body = strings.ToValidUTF8(string(body), "�")
+	result.BodyTruncated = rest > 0
+
+	result.StatusCode = resp.StatusCode
+	result.Status = resp.Status
+	result.Protocol = resp.Proto
+	if resp.TLS != nil {
+		result.TLSVersion = tls.VersionName(resp.TLS.Version)
+	}
+	result.Headers = make(map[string]string, len(resp.Header))
+	for name, values := range resp.Header {
+		if redactedHeaders[name] {
+			result.Headers[name] = "<redacted>"
+			continue
+		}
+		result.Headers[name] = strings.Join(values, ", ")
+	}
+	return result, nil
+}
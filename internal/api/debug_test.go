@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DebugGet(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.Write([]byte(`{"status": "ok"}`))
+			return
+		}
+		assert.Equal(t, "/api/v1/clubs", r.URL.Path)
+		assert.Equal(t, "5", r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`[{"id": "C0327", "name": "` + strings.Repeat("x", 100) + `"}]`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	result, err := client.DebugGet(context.Background(), "/api/v1/clubs", url.Values{"limit": {"5"}}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, upstream.URL+"/api/v1/clubs?limit=5", result.URL)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "HTTP/1.1", result.Protocol)
+	assert.Equal(t, `[{"id": "C`, result.Body)
+	assert.True(t, result.BodyTruncated)
+	assert.Equal(t, int64(129), result.BodyBytes)
+	assert.Equal(t, "application/json", result.Headers["Content-Type"])
+	assert.Equal(t, "<redacted>", result.Headers["Set-Cookie"])
+	assert.False(t, result.ReusedConnection)
+	assert.Greater(t, result.TotalMS, 0.0)
+	assert.Empty(t, result.Error)
+
+	result, err = client.DebugGet(context.Background(), "/health", nil, 4096)
+	require.NoError(t, err)
+	assert.True(t, result.ReusedConnection)
+
+	for _, path := range []string{"health", "/api/v1/clubs/../admin", "/api/v1/clubs/", "/health?x=1"} {
+		_, err := client.DebugGet(context.Background(), path, nil, 10)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestClient_DebugGetUnreachable(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	client := NewClient(upstream.URL, time.Second, nil)
+	result, err := client.DebugGet(context.Background(), "/health", nil, 10)
+	require.NoError(t, err, "failed requests are reported in the result")
+	assert.Contains(t, result.Error, "connection refused")
+	assert.Zero(t, result.StatusCode)
+}
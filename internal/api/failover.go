@@ -0,0 +1,320 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultFailureThreshold is the number of consecutive failures that
+	// opens the circuit breaker of an upstream
+	defaultFailureThreshold = 3
+	// defaultBreakerCooldown is how long an open breaker skips its upstream
+	// before a trial request is allowed
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// FailoverOptions configures fallback upstreams of the client
+type FailoverOptions struct {
+	FallbackURLs     []string      // Tried in order when the primary is unhealthy
+	FailureThreshold int           // Consecutive failures that open a breaker (default 3)
+	Cooldown         time.Duration // Time an open breaker skips its upstream (default 30s)
+}
+
+// UpstreamStats describes an upstream and its circuit breaker
+type UpstreamStats struct {
+	URL                 string     `json:"url"`
+	Primary             bool       `json:"primary"`
+	State               string     `json:"state"`
+	Requests            int64      `json:"requests"` // Requests served, including error responses
+	Failures            int64      `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+}
+
+// upstream is a Portal64 API base URL with its circuit breaker
+type upstream struct {
+	baseURL string
+
+	mu          sync.Mutex
+	requests    int64
+	failures    int64
+	consecutive int
+	openedAt    time.Time
+	lastError   string
+	lastFailure time.Time
+}
+
+// breaker tracks upstream health. An upstream is skipped for the cooldown
+// after threshold consecutive failures; afterwards a trial request decides
+// whether the breaker closes again or stays open.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func (b *breaker) state(u *upstream) string {
+	if u.consecutive < b.threshold {
+		return BreakerClosed
+	}
+	if b.now().Sub(u.openedAt) < b.cooldown {
+		return BreakerOpen
+	}
+	return BreakerHalfOpen
+}
+
+func (b *breaker) available(u *upstream) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return b.state(u) != BreakerOpen
+}
+
+func (b *breaker) success(u *upstream) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.requests++
+	u.consecutive = 0
+}
+
+// failure records a failed request and reports whether it opened the
+// breaker, which a failed trial request does not
+func (b *breaker) failure(u *upstream, served bool, reason string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if served {
+		u.requests++
+	}
+	u.failures++
+	u.consecutive++
+	u.lastError = reason
+	u.lastFailure = b.now()
+	if u.consecutive >= b.threshold {
+		// (Re)open the breaker, also after a failed trial request
+		u.openedAt = u.lastFailure
+	}
+	return u.consecutive == b.threshold
+}
+
+func (b *breaker) stats(u *upstream, primary bool) UpstreamStats {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	stats := UpstreamStats{
+		URL:                 u.baseURL,
+		Primary:             primary,
+		State:               b.state(u),
+		Requests:            u.requests,
+		Failures:            u.failures,
+		ConsecutiveFailures: u.consecutive,
+		LastError:           u.lastError,
+	}
+	if !u.lastFailure.IsZero() {
+		lastFailure := u.lastFailure
+		stats.LastFailure = &lastFailure
+	}
+	return stats
+}
+
+// failoverTransport sends requests for the primary base URL to the first
+// healthy upstream and fails over to the next one on network errors and
+// 5xx responses
+type failoverTransport struct {
+	base      http.RoundTripper
+	primary   string
+	upstreams []*upstream
+	breaker   *breaker
+	logger    Logger
+	// onOpen is called when the breaker of an upstream opens
+	onOpen func(url, reason string)
+}
+
+// candidates returns the upstreams whose breaker allows a request. When all
+// breakers are open every upstream is tried rather than failing outright.
+func (t *failoverTransport) candidates() []*upstream {
+	var available []*upstream
+	for _, u := range t.upstreams {
+		if t.breaker.available(u) {
+			available = append(available, u)
+		}
+	}
+	if len(available) == 0 {
+		return t.upstreams
+	}
+	return available
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	original := req.URL.String()
+	if !strings.HasPrefix(original, t.primary) {
+		return t.base.RoundTrip(req)
+	}
+	path := strings.TrimPrefix(original, t.primary)
+
+	candidates := t.candidates()
+	var lastErr error
+	for i, u := range candidates {
+		attempt, err := t.rewrite(req, u.baseURL+path, i > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attempt)
+		last := i == len(candidates)-1
+
+		switch {
+		case err != nil && req.Context().Err() != nil:
+			// Cancelled by the caller, not an upstream failure
+			return nil, err
+		case err != nil:
+			t.failure(u, false, err.Error())
+			lastErr = err
+		case resp.StatusCode >= http.StatusInternalServerError:
+			t.failure(u, true, resp.Status)
+			if last || !canReplay(req) {
+				t.logServed(req, u)
+				return resp, nil
+			}
+			resp.Body.Close()
+			lastErr = fmt.Errorf("upstream returned %s", resp.Status)
+		default:
+			t.breaker.success(u)
+			t.logServed(req, u)
+			return resp, nil
+		}
+
+		if last || !canReplay(req) {
+			break
+		}
+		t.logger.WithFields(logrus.Fields{
+			"upstream": u.baseURL,
+			"next":     candidates[i+1].baseURL,
+		}).WithError(lastErr).Warn("Portal64 upstream failed, failing over")
+	}
+
+	return nil, lastErr
+}
+
+// failure records a failed request to an upstream and reports its breaker
+// opening
+func (t *failoverTransport) failure(u *upstream, served bool, reason string) {
+	if !t.breaker.failure(u, served, reason) {
+		return
+	}
+	t.logger.WithFields(logrus.Fields{
+		"upstream": u.baseURL,
+		"reason":   reason,
+	}).Warn("Circuit breaker of Portal64 upstream opened")
+	if t.onOpen != nil {
+		t.onOpen(u.baseURL, reason)
+	}
+}
+
+// rewrite returns a copy of req targeting target. Retried requests get a
+// fresh body.
+func (t *failoverTransport) rewrite(req *http.Request, target string, retry bool) (*http.Request, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream URL %s: %w", target, err)
+	}
+
+	attempt := req.Clone(req.Context())
+	attempt.URL = u
+	attempt.Host = u.Host
+	if retry && req.GetBody != nil {
+		if attempt.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return attempt, nil
+}
+
+// canReplay reports whether a request can be sent to another upstream
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (t *failoverTransport) logServed(req *http.Request, u *upstream) {
+	t.logger.WithFields(logrus.Fields{
+		"method":   req.Method,
+		"path":     req.URL.Path,
+		"upstream": u.baseURL,
+	}).Debug("API request served")
+}
+
+// ConfigureFailover adds fallback upstreams to the client. Requests go to
+// the first upstream whose circuit breaker is closed, starting with the
+// primary base URL. It is meant to be called once at startup.
+func (c *Client) ConfigureFailover(opts FailoverOptions) error {
+	if len(opts.FallbackURLs) == 0 {
+		return nil
+	}
+
+	b := &breaker{threshold: opts.FailureThreshold, cooldown: opts.Cooldown, now: time.Now}
+	if b.threshold <= 0 {
+		b.threshold = defaultFailureThreshold
+	}
+	if b.cooldown <= 0 {
+		b.cooldown = defaultBreakerCooldown
+	}
+
+	upstreams := []*upstream{{baseURL: c.baseURL}}
+	for _, fallback := range opts.FallbackURLs {
+		fallback = strings.TrimSuffix(strings.TrimSpace(fallback), "/")
+		if fallback == "" {
+			continue
+		}
+		if u, err := url.Parse(fallback); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid fallback URL %q", fallback)
+		}
+		upstreams = append(upstreams, &upstream{baseURL: fallback})
+	}
+
+	c.failover = &failoverTransport{
+		base:      c.httpClient.Transport,
+		primary:   c.baseURL,
+		upstreams: upstreams,
+		breaker:   b,
+		logger:    c.logger,
+		onOpen:    c.onBreakerOpen,
+	}
+	c.httpClient.Transport = c.failover
+	return nil
+}
+
+// OnBreakerOpen sets a function called when the circuit breaker of an
+// upstream opens, with the upstream URL and the last failure. It must be
+// set before the client is used.
+func (c *Client) OnBreakerOpen(hook func(url, reason string)) {
+	c.onBreakerOpen = hook
+	if c.failover != nil {
+		c.failover.onOpen = hook
+	}
+}
+
+// UpstreamStats returns the state of all configured upstreams, or nil when
+// no fallbacks are configured
+func (c *Client) UpstreamStats() []UpstreamStats {
+	if c.failover == nil {
+		return nil
+	}
+	stats := make([]UpstreamStats, len(c.failover.upstreams))
+	for i, u := range c.failover.upstreams {
+		stats[i] = c.failover.breaker.stats(u, i == 0)
+	}
+	return stats
+}
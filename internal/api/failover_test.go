@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFailoverTestClient(t *testing.T, primary, fallback *httptest.Server) *Client {
+	client := createTestClientWithURL(primary.URL)
+	require.NoError(t, client.ConfigureFailover(FailoverOptions{
+		FallbackURLs:     []string{fallback.URL + "/"},
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+	}))
+	return client
+}
+
+func TestClient_Failover(t *testing.T) {
+	var primaryCalls, fallbackCalls int32
+	var primaryDown atomic.Bool
+	primaryDown.Store(true)
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status": "primary"}`))
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fallbackCalls, 1)
+		assert.Equal(t, "/health", r.URL.Path)
+		w.Write([]byte(`{"status": "fallback"}`))
+	}))
+	defer fallback.Close()
+
+	client := newFailoverTestClient(t, primary, fallback)
+	now := time.Now()
+	client.failover.breaker.now = func() time.Time { return now }
+	var opened []string
+	client.OnBreakerOpen(func(url, reason string) { opened = append(opened, url+" "+reason) })
+	ctx := context.Background()
+
+	// Failures below the threshold keep trying the primary first
+	for i := 0; i < 2; i++ {
+		health, err := client.Health(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "fallback", health.Status)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&primaryCalls))
+
+	// The open breaker skips the primary
+	health, err := client.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", health.Status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&primaryCalls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fallbackCalls))
+
+	stats := client.UpstreamStats()
+	require.Len(t, stats, 2)
+	assert.True(t, stats[0].Primary)
+	assert.Equal(t, BreakerOpen, stats[0].State)
+	assert.Equal(t, int64(2), stats[0].Failures)
+	assert.Equal(t, "503 Service Unavailable", stats[0].LastError)
+	assert.Equal(t, fallback.URL, stats[1].URL)
+	assert.Equal(t, BreakerClosed, stats[1].State)
+	assert.Equal(t, int64(3), stats[1].Requests)
+	assert.Equal(t, []string{primary.URL + " 503 Service Unavailable"}, opened)
+
+	// After the cooldown a successful trial request closes the breaker
+	primaryDown.Store(false)
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, BreakerHalfOpen, client.UpstreamStats()[0].State)
+
+	health, err = client.Health(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", health.Status)
+	assert.Equal(t, BreakerClosed, client.UpstreamStats()[0].State)
+	assert.Equal(t, client.UpstreamStats(), client.ConnectionStats().Upstreams)
+}
+
+func TestClient_FailoverReplaysBody(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		json.NewEncoder(w).Encode(body["ids"])
+	}))
+	defer fallback.Close()
+
+	client := newFailoverTestClient(t, primary, fallback)
+
+	var ids []string
+	err := client.DoJSONRequest(context.Background(), http.MethodPost, "/api/v1/players/batch",
+		map[string][]string{"ids": {"C0327-297"}}, &ids)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C0327-297"}, ids)
+}
+
+func TestClient_FailoverAllUnhealthy(t *testing.T) {
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	client := newFailoverTestClient(t, unhealthy, unhealthy)
+
+	_, err := client.Health(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestClient_ConfigureFailover(t *testing.T) {
+	client := createTestClient()
+	require.NoError(t, client.ConfigureFailover(FailoverOptions{}))
+	assert.Nil(t, client.UpstreamStats())
+
+	err := client.ConfigureFailover(FailoverOptions{FallbackURLs: []string{"mirror.example.org"}})
+	assert.EqualError(t, err, `invalid fallback URL "mirror.example.org"`)
+
+	require.NoError(t, client.ConfigureFailover(FailoverOptions{FallbackURLs: []string{"https://mirror.example.org"}}))
+	assert.Equal(t, defaultFailureThreshold, client.failover.breaker.threshold)
+	assert.Equal(t, defaultBreakerCooldown, client.failover.breaker.cooldown)
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IDKind identifies the type of a Portal64 identifier
+type IDKind string
+
+const (
+	IDKindPlayer     IDKind = "player"
+	IDKindClub       IDKind = "club"
+	IDKindTournament IDKind = "tournament"
+	IDKindUnknown    IDKind = "unknown"
+)
+
+var (
+	// idSeparators are characters commonly used instead of "-"
+	idSeparators = regexp.MustCompile(`[\s/_.:]+`)
+
+	clubIDPattern       = regexp.MustCompile(`^([A-Z0-9])(\d{1,4})$`)
+	playerIDPattern     = regexp.MustCompile(`^([A-Z0-9])(\d{1,4})-(\d{1,5})$`)
+	tournamentIDPattern = regexp.MustCompile(`^([A-Z0-9]{3,4})-([A-Z0-9]{3})-([A-Z0-9]{3})$`)
+	legacyTournamentID  = regexp.MustCompile(`^T(\d+)$`)
+)
+
+// cleanID uppercases an ID and unifies separators
+func cleanID(raw string) string {
+	id := strings.ToUpper(strings.TrimSpace(raw))
+	id = idSeparators.ReplaceAllString(id, "-")
+	return strings.Trim(id, "-")
+}
+
+// validClubNumber reports whether a club number can be zero-padded. Only
+// letter prefixes are padded, since a short number after a digit more likely
+// means the prefix is missing (e.g. "0327" for "C0327").
+func validClubNumber(prefix, number string) bool {
+	return len(number) == 4 || (prefix[0] >= 'A' && prefix[0] <= 'Z')
+}
+
+// NormalizeClubID normalizes club ID variants such as "c327" or " C0327 "
+// to the canonical "C0327" format
+func NormalizeClubID(raw string) (string, error) {
+	m := clubIDPattern.FindStringSubmatch(cleanID(raw))
+	if m == nil || !validClubNumber(m[1], m[2]) {
+		return "", fmt.Errorf("invalid club ID %q, expected format C0101", raw)
+	}
+	number, _ := strconv.Atoi(m[2])
+	return fmt.Sprintf("%s%04d", m[1], number), nil
+}
+
+// NormalizePlayerID normalizes player ID variants such as "c0327-297",
+// "C0327/297" or "C0327 0297" to the canonical "C0327-297" format
+func NormalizePlayerID(raw string) (string, error) {
+	m := playerIDPattern.FindStringSubmatch(cleanID(raw))
+	if m == nil || !validClubNumber(m[1], m[2]) {
+		return "", fmt.Errorf("invalid player ID %q, expected format C0101-123", raw)
+	}
+	club, _ := strconv.Atoi(m[2])
+	member, _ := strconv.Atoi(m[3])
+	return fmt.Sprintf("%s%04d-%d", m[1], club, member), nil
+}
+
+// NormalizeTournamentID normalizes tournament ID variants such as
+// "c350 c01 smu" to the canonical "C350-C01-SMU" format
+func NormalizeTournamentID(raw string) (string, error) {
+	id := cleanID(raw)
+	if tournamentIDPattern.MatchString(id) || legacyTournamentID.MatchString(id) {
+		return id, nil
+	}
+	return "", fmt.Errorf("invalid tournament ID %q, expected format C350-C01-SMU", raw)
+}
+
+// DetectIDKind returns the kind and canonical form of an ID. Player IDs are
+// checked before tournament IDs since both contain separators.
+func DetectIDKind(raw string) (IDKind, string) {
+	if id, err := NormalizePlayerID(raw); err == nil {
+		return IDKindPlayer, id
+	}
+	if id, err := NormalizeClubID(raw); err == nil {
+		return IDKindClub, id
+	}
+	if id, err := NormalizeTournamentID(raw); err == nil {
+		return IDKindTournament, id
+	}
+	return IDKindUnknown, cleanID(raw)
+}
+
+// NormalizeID normalizes an ID of a known kind
+func NormalizeID(kind IDKind, raw string) (string, error) {
+	switch kind {
+	case IDKindPlayer:
+		return NormalizePlayerID(raw)
+	case IDKindClub:
+		return NormalizeClubID(raw)
+	case IDKindTournament:
+		return NormalizeTournamentID(raw)
+	default:
+		return "", fmt.Errorf("unknown ID kind %q", kind)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePlayerID(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"C0327-297", "C0327-297", true},
+		{"c0327-297", "C0327-297", true},
+		{"C0327/297", "C0327-297", true},
+		{"C0327 0297", "C0327-297", true},
+		{" c327_297 ", "C0327-297", true},
+		{"C0327", "", false},
+		{"0327-297", "", false},
+		{"Tran", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			id, err := NormalizePlayerID(tc.input)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, id)
+		})
+	}
+}
+
+func TestNormalizeClubID(t *testing.T) {
+	id, err := NormalizeClubID("c327")
+	assert.NoError(t, err)
+	assert.Equal(t, "C0327", id)
+
+	_, err = NormalizeClubID("C0327-297")
+	assert.Error(t, err)
+}
+
+func TestDetectIDKind(t *testing.T) {
+	testCases := []struct {
+		input    string
+		kind     IDKind
+		expected string
+	}{
+		{"c0505-1043", IDKindPlayer, "C0505-1043"},
+		{"C0505", IDKindClub, "C0505"},
+		{"c350 c01 smu", IDKindTournament, "C350-C01-SMU"},
+		{"t96887", IDKindTournament, "T96887"},
+		{"hello world", IDKindUnknown, "HELLO-WORLD"},
+	}
+
+	for _, tc := range testCases {
+		kind, id := DetectIDKind(tc.input)
+		assert.Equal(t, tc.kind, kind, tc.input)
+		assert.Equal(t, tc.expected, id, tc.input)
+	}
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Europe/Berlin on systems without a zoneinfo database
+)
+
+// Reasons of an active MaintenanceStatus
+const (
+	MaintenanceScheduled = "scheduled" // Within a configured maintenance window
+	MaintenanceDetected  = "detected"  // After a 503 response matching the pattern
+)
+
+// maintenanceCode is the code of the errors of requests that are not
+// answered during maintenance
+const maintenanceCode = "MAINTENANCE"
+
+// portalLocation is the time zone of maintenance windows unless configured
+var portalLocation = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// weekdays maps the abbreviated and full English day names to weekdays
+var weekdays = func() map[string]time.Weekday {
+	days := make(map[string]time.Weekday, 14)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		days[name], days[name[:3]] = day, day
+	}
+	return days
+}()
+
+// MaintenanceWindow is a recurring time of a weekday, or of every day, at
+// which the Portal64 API is known to be unavailable
+type MaintenanceWindow struct {
+	Daily bool
+	Day   time.Weekday  // Unless Daily
+	Start time.Duration // Since midnight
+	End   time.Duration // Since midnight; up to Start for windows past midnight
+}
+
+// ParseMaintenanceWindow parses a window such as "Sun 02:00-04:00" or
+// "daily 23:30-00:30"
+func ParseMaintenanceWindow(s string) (MaintenanceWindow, error) {
+	var w MaintenanceWindow
+	day, span, ok := strings.Cut(strings.TrimSpace(s), " ")
+	start, end, ok2 := strings.Cut(strings.TrimSpace(span), "-")
+	if !ok || !ok2 {
+		return w, fmt.Errorf("invalid maintenance window %q, expected <day> HH:MM-HH:MM", s)
+	}
+
+	if strings.EqualFold(day, "daily") {
+		w.Daily = true
+	} else if w.Day, ok = weekdays[strings.ToLower(day)]; !ok {
+		return w, fmt.Errorf("invalid maintenance window %q: unknown day %q, expected Mon to Sun or daily", s, day)
+	}
+
+	var err error
+	if w.Start, err = parseClock(start); err != nil {
+		return w, fmt.Errorf("invalid maintenance window %q: %w", s, err)
+	}
+	if w.End, err = parseClock(end); err != nil {
+		return w, fmt.Errorf("invalid maintenance window %q: %w", s, err)
+	}
+	if w.Start == w.End {
+		return w, fmt.Errorf("invalid maintenance window %q: the window is empty", s)
+	}
+	return w, nil
+}
+
+// parseClock parses a time of day as HH:MM
+func parseClock(s string) (time.Duration, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(s), ":")
+	h, err := strconv.Atoi(hours)
+	if !ok || err != nil || h < 0 || h > 23 || len(minutes) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// String returns the window in the format of ParseMaintenanceWindow
+func (w MaintenanceWindow) String() string {
+	day := "daily"
+	if !w.Daily {
+		day = w.Day.String()[:3]
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%s %s-%s", day, clock(w.Start), clock(w.End))
+}
+
+// end returns the end of the occurrence of the window that t is in, false
+// if t is outside the window. t must be in the time zone of the window.
+func (w MaintenanceWindow) end(t time.Time) (time.Time, bool) {
+	length := w.End - w.Start
+	if length <= 0 {
+		length += 24 * time.Hour
+	}
+	// Windows past midnight may have started the day before
+	for _, offset := range []int{0, -1} {
+		day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+		if !w.Daily && day.Weekday() != w.Day {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, int(w.Start/time.Minute), 0, 0, t.Location())
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, int((w.Start+length)/time.Minute), 0, 0, t.Location())
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// MaintenanceOptions configures the maintenance mode of the client
+type MaintenanceOptions struct {
+	Windows  []MaintenanceWindow // Known maintenance of the API
+	Location *time.Location      // Time zone of the windows, default Europe/Berlin
+	// Pattern is matched against 503 responses to detect unscheduled
+	// maintenance; nil disables detection
+	Pattern *regexp.Regexp
+	// Hold is how long a detected maintenance lasts, or the Retry-After of
+	// the response if longer
+	Hold time.Duration
+}
+
+// MaintenanceStatus describes whether the API is under maintenance
+type MaintenanceStatus struct {
+	Active bool       `json:"active"`
+	Reason string     `json:"reason,omitempty"` // MaintenanceScheduled or MaintenanceDetected
+	Until  *time.Time `json:"until,omitempty"`
+	Window string     `json:"window,omitempty"` // The scheduled window
+}
+
+// maintenance tracks the maintenance windows of the API and maintenance
+// detected from its responses. A nil maintenance is never active.
+type maintenance struct {
+	windows  []MaintenanceWindow
+	location *time.Location
+	pattern  *regexp.Regexp
+	hold     time.Duration
+	now      func() time.Time
+	logger   Logger
+
+	mu       sync.Mutex
+	detected time.Time // End of the detected maintenance
+}
+
+// ConfigureMaintenance enables the maintenance mode of the client. During
+// a maintenance window, or after a 503 response matching the pattern,
+// GET requests of Portal64 data are answered from the response cache only,
+// regardless of the age of the cached responses, and other data requests
+// fail at once. Like ConfigureCache, it must be called before the client is
+// used.
+func (c *Client) ConfigureMaintenance(opts MaintenanceOptions) {
+	if len(opts.Windows) == 0 && opts.Pattern == nil {
+		return
+	}
+	c.maintenance = &maintenance{
+		windows:  opts.Windows,
+		location: opts.Location,
+		pattern:  opts.Pattern,
+		hold:     max(opts.Hold, 0),
+		now:      time.Now,
+		logger:   c.logger,
+	}
+	if c.maintenance.location == nil {
+		c.maintenance.location = portalLocation
+	}
+}
+
+// Maintenance returns whether the API is under maintenance
+func (c *Client) Maintenance() MaintenanceStatus {
+	return c.maintenance.status()
+}
+
+// status returns the current maintenance status. Scheduled maintenance
+// takes precedence over detected maintenance.
+func (m *maintenance) status() MaintenanceStatus {
+	if m == nil {
+		return MaintenanceStatus{}
+	}
+	now := m.now()
+	for _, w := range m.windows {
+		if end, ok := w.end(now.In(m.location)); ok {
+			return MaintenanceStatus{Active: true, Reason: MaintenanceScheduled, Until: &end, Window: w.String()}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Before(m.detected) {
+		until := m.detected
+		return MaintenanceStatus{Active: true, Reason: MaintenanceDetected, Until: &until}
+	}
+	return MaintenanceStatus{}
+}
+
+// observe detects maintenance from an error response of the API and
+// reports whether it indicates maintenance
+func (m *maintenance) observe(apiErr *APIError) bool {
+	if m == nil || m.pattern == nil || apiErr.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	if !m.pattern.MatchString(apiErr.Code + "\n" + apiErr.Message + "\n" + apiErr.body) {
+		return false
+	}
+
+	now := m.now()
+	until := now.Add(max(m.hold, apiErr.RetryAfter))
+	m.mu.Lock()
+	started := !now.Before(m.detected)
+	if until.After(m.detected) {
+		m.detected = until
+	}
+	m.mu.Unlock()
+	if started {
+		m.logger.WithField("until", until.Format(time.RFC3339)).Warn("Portal64 API is under maintenance, serving cached responses only")
+	}
+	return true
+}
+
+// maintenanceError returns the error of a request that is not answered
+// during maintenance
+func (m *maintenance) maintenanceError(status MaintenanceStatus) *APIError {
+	apiErr := &APIError{
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       maintenanceCode,
+		Message:    "Portal64 API is under maintenance and the response is not cached",
+	}
+	if status.Until != nil {
+		apiErr.Message = fmt.Sprintf("Portal64 API is under maintenance until %s and the response is not cached", status.Until.Format(time.RFC3339))
+		apiErr.RetryAfter = max(status.Until.Sub(m.now()), 0)
+	}
+	return apiErr
+}
+
+// IsMaintenance reports whether err is the error of a request that was
+// not answered because the API is under maintenance
+func IsMaintenance(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == maintenanceCode
+}
+
+// serveMaintenance answers a data request during maintenance: GET requests
+// from the response cache regardless of the age of the response, all other
+// requests with the maintenance error
+func (c *Client) serveMaintenance(ctx context.Context, method, url string, status MaintenanceStatus) (*http.Response, error) {
+	if method == http.MethodGet {
+		if entry, ok := c.cache.lookupAny(ctx, url); ok {
+			if entry.notFound != nil {
+				return nil, entry.miss()
+			}
+			return entry.response(ctx), nil
+		}
+	}
+	return nil, c.maintenance.maintenanceError(status)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	for s, want := range map[string]string{
+		"Sun 02:00-04:00":      "Sun 02:00-04:00",
+		"saturday 23:00-01:00": "Sat 23:00-01:00",
+		" Daily 3:00-03:15 ":   "daily 03:00-03:15",
+	} {
+		w, err := ParseMaintenanceWindow(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, w.String(), s)
+	}
+	for _, s := range []string{"", "Sun", "Sun 02:00", "Someday 02:00-04:00", "Sun 24:00-01:00", "Sun 02:60-03:00", "Sun 2-4", "daily 02:00-02:00"} {
+		_, err := ParseMaintenanceWindow(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMaintenanceWindow_End(t *testing.T) {
+	berlin := portalLocation
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, berlin) }
+
+	sunday, _ := ParseMaintenanceWindow("Sun 02:00-04:00")
+	end, ok := sunday.end(at(1, 2, 0)) // Sunday
+	assert.True(t, ok)
+	assert.Equal(t, at(1, 4, 0), end)
+	_, ok = sunday.end(at(1, 4, 0))
+	assert.False(t, ok, "the end is outside the window")
+	_, ok = sunday.end(at(2, 3, 0))
+	assert.False(t, ok, "other days are outside the window")
+
+	overnight, _ := ParseMaintenanceWindow("Sat 23:00-01:00")
+	end, ok = overnight.end(at(1, 0, 30)) // Sunday, started on Saturday
+	assert.True(t, ok)
+	assert.Equal(t, at(1, 1, 0), end)
+	_, ok = overnight.end(at(1, 23, 30))
+	assert.False(t, ok)
+
+	daily, _ := ParseMaintenanceWindow("daily 01:00-04:00")
+	end, ok = daily.end(at(29, 3, 30)) // The clocks go forward at 02:00
+	assert.True(t, ok)
+	assert.Equal(t, at(29, 4, 0), end)
+}
+
+func TestClient_MaintenanceScheduled(t *testing.T) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"id": "C0327-297", "name": "Müller, Hans"}`))
+	}))
+	defer upstream.Close()
+
+	window, err := ParseMaintenanceWindow("Sun 02:00-04:00")
+	require.NoError(t, err)
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CachePlayers: {TTL: time.Minute}}})
+	client.ConfigureMaintenance(MaintenanceOptions{Windows: []MaintenanceWindow{window}})
+	now := time.Date(2026, 3, 1, 1, 0, 0, 0, portalLocation)
+	client.maintenance.now = func() time.Time { return now }
+
+	_, err = client.GetPlayerProfile(context.Background(), "C0327-297")
+	require.NoError(t, err)
+	assert.False(t, client.Maintenance().Active)
+
+	// Within the window cached responses are served regardless of their age
+	now = now.Add(90 * time.Minute)
+	client.cache.now = func() time.Time { return time.Now().Add(time.Hour) }
+	status := client.Maintenance()
+	require.True(t, status.Active)
+	assert.Equal(t, MaintenanceScheduled, status.Reason)
+	assert.Equal(t, "Sun 02:00-04:00", status.Window)
+	assert.Equal(t, time.Date(2026, 3, 1, 4, 0, 0, 0, portalLocation), *status.Until)
+
+	var lookups []CacheLookup
+	ctx := WithCacheObserver(context.Background(), func(lookup CacheLookup) { lookups = append(lookups, lookup) })
+	player, err := client.GetPlayerProfile(ctx, "C0327-297")
+	require.NoError(t, err)
+	assert.Equal(t, "Müller, Hans", player.Name)
+	require.Len(t, lookups, 1)
+	assert.Equal(t, CacheStale, lookups[0].Status)
+	assert.Equal(t, int64(1), client.cache.Stats().MaintenanceHits)
+
+	_, err = client.GetPlayerProfile(WithCacheBypass(context.Background()), "C0327-298")
+	assert.True(t, IsMaintenance(err))
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 90*time.Minute, apiErr.RetryAfter)
+	assert.True(t, IsMaintenance(client.DoJSONRequest(context.Background(), http.MethodGet, "/api/v1/players/C0327-297", nil, nil)))
+
+	_, err = client.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "only requests other than data requests reach the API")
+}
+
+func TestClient_MaintenanceDetected(t *testing.T) {
+	var down atomic.Bool
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if down.Load() {
+			w.Header().Set("Retry-After", "1800")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<html><body><h1>Wartungsarbeiten</h1></body></html>`))
+			return
+		}
+		w.Write([]byte(`[{"code": "C", "name": "Württemberg"}]`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.retryBackoff = time.Millisecond
+	client.ConfigureCache(CacheOptions{Policies: map[string]CachePolicy{CacheRegions: {TTL: time.Hour}}})
+	client.ConfigureMaintenance(MaintenanceOptions{Pattern: regexp.MustCompile(`(?i)maintenance|wartung`), Hold: 10 * time.Minute})
+
+	_, err := client.GetRegions(context.Background())
+	require.NoError(t, err)
+
+	// The request detecting the maintenance is answered from the cache
+	down.Store(true)
+	start := time.Now()
+	regions, err := client.GetRegions(WithCacheBypass(context.Background()))
+	require.NoError(t, err)
+	assert.Equal(t, "Württemberg", regions[0].Name)
+	status := client.Maintenance()
+	require.True(t, status.Active)
+	assert.Equal(t, MaintenanceDetected, status.Reason)
+	assert.WithinDuration(t, start.Add(30*time.Minute), *status.Until, time.Minute, "a longer Retry-After extends the hold")
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Further data requests do not reach the API
+	_, err = client.GetRegions(WithCacheBypass(context.Background()))
+	require.NoError(t, err)
+	err = client.DoJSONRequest(context.Background(), http.MethodPost, "/api/v1/addresses/C", map[string]string{"name": "x"}, nil)
+	assert.True(t, IsMaintenance(err))
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Maintenance detected by DoJSONRequest is not retried
+	client.maintenance.detected = time.Time{}
+	err = client.DoJSONRequest(context.Background(), http.MethodGet, "/api/v1/addresses/regions", nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	assert.True(t, client.Maintenance().Active)
+}
+
+func TestClient_MaintenanceNotDetected(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "database overloaded"}`, http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.retryBackoff = time.Millisecond
+	_, err := client.GetRegions(context.Background())
+	require.Error(t, err)
+	assert.False(t, client.Maintenance().Active, "maintenance mode is not configured")
+
+	client.ConfigureMaintenance(MaintenanceOptions{Pattern: regexp.MustCompile(`(?i)maintenance|wartung`)})
+	_, err = client.GetRegions(context.Background())
+	require.Error(t, err)
+	assert.False(t, IsMaintenance(err))
+	assert.False(t, client.Maintenance().Active, "other 503 responses are no maintenance")
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -32,30 +34,47 @@ func ConvertGenderToAPI(displayGender string) string {
 	}
 }
 
+// NormalizeGender maps common gender spellings to the display format
+// (male/female/divers); unknown values are returned unchanged
+func NormalizeGender(gender string) string {
+	switch strings.ToLower(strings.TrimSpace(gender)) {
+	case "m", "male", "man", "männlich":
+		return "male"
+	case "w", "f", "female", "woman", "weiblich":
+		return "female"
+	case "d", "divers", "diverse":
+		return "divers"
+	default:
+		return gender
+	}
+}
+
 // CustomDate handles date parsing for API responses that return dates in YYYY-MM-DD format
 type CustomDate struct {
 	time.Time
 }
 
-// UnmarshalJSON implements json.Unmarshaler for CustomDate
+// UnmarshalJSON implements json.Unmarshaler for CustomDate. The date must
+// be a JSON string as YYYY-MM-DD or RFC 3339; null leaves it unchanged.
 func (cd *CustomDate) UnmarshalJSON(data []byte) error {
-	// Remove quotes from JSON string
-	dateStr := string(data[1 : len(data)-1])
-	
-	// Try parsing as date-only format first
-	if t, err := time.Parse("2006-01-02", dateStr); err == nil {
-		cd.Time = t
+	if string(data) == "null" {
 		return nil
 	}
-	
-	// If that fails, try RFC3339 format
-	if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
-		cd.Time = t
-		return nil
+
+	var dateStr string
+	if err := json.Unmarshal(data, &dateStr); err != nil {
+		return fmt.Errorf("date must be a string: %w", err)
 	}
-	
-	// If both fail, try parsing as time.Time would normally
-	return cd.Time.UnmarshalJSON(data)
+
+	// Try parsing as date-only format first, then as RFC 3339 with optional
+	// fractional seconds
+	for _, layout := range []string{"2006-01-02", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, dateStr); err == nil {
+			cd.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", dateStr)
 }
 
 // MarshalJSON implements json.Marshaler for CustomDate
@@ -74,8 +93,36 @@ type PaginationMetadata struct {
 	Total  int `json:"total"`
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
+	Count  int `json:"count"` // Number of items on this page
 	Pages  int `json:"pages"`
-	Page   int `json:"page"`
+	Page   int `json:"page"`  // 1-based
+}
+
+// UnmarshalJSON implements json.Unmarshaler for PaginationMetadata to accept
+// the field name variants of the mock and real APIs
+func (p *PaginationMetadata) UnmarshalJSON(data []byte) error {
+	type Alias PaginationMetadata
+	aux := &struct {
+		*Alias
+		TotalCount  int `json:"total_count"`
+		TotalItems  int `json:"total_items"`
+		PerPage     int `json:"per_page"`
+		PageSize    int `json:"page_size"`
+		TotalPages  int `json:"total_pages"`
+		CurrentPage int `json:"current_page"`
+	}{
+		Alias: (*Alias)(p),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	p.Total = max(p.Total, aux.TotalCount, aux.TotalItems)
+	p.Limit = max(p.Limit, aux.PerPage, aux.PageSize)
+	p.Pages = max(p.Pages, aux.TotalPages)
+	p.Page = max(p.Page, aux.CurrentPage)
+	return nil
 }
 
 // PlayerResponse represents a player in the system
@@ -93,6 +140,7 @@ type PlayerResponse struct {
 	Nation      string `json:"nation"`
 	Status      string `json:"status"`
 	FideID      int    `json:"fide_id"`
+	Title       string `json:"title,omitempty"` // FIDE title if provided by the API
 }
 
 // UnmarshalJSON implements json.Unmarshaler for PlayerResponse to handle gender conversion
@@ -174,11 +222,20 @@ type ClubContact struct {
 }
 // ClubTeam represents a club team
 type ClubTeam struct {
-	ID       string `json:"id"`
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	League   string       `json:"league"`
+	Division string       `json:"division"`
+	Season   string       `json:"season"`
+	Players  []TeamPlayer `json:"players,omitempty"`
+}
+
+// TeamPlayer represents a player assigned to a board of a team
+type TeamPlayer struct {
+	Board    int    `json:"board"`
+	PlayerID string `json:"player_id"`
 	Name     string `json:"name"`
-	League   string `json:"league"`
-	Division string `json:"division"`
-	Season   string `json:"season"`
+	DWZ      int    `json:"dwz,omitempty"`
 }
 
 // ClubRatingStats represents club rating statistics
@@ -189,6 +246,8 @@ type ClubRatingStats struct {
 	LowestRating      int     `json:"lowest_dwz"`       // API returns lowest_dwz
 	PlayersWithDWZ    int     `json:"players_with_dwz"` // API returns players_with_dwz
 	RatingDistribution map[string]int `json:"rating_distribution"`
+	GenderDistribution map[string]int `json:"gender_distribution,omitempty"`
+	TitleDistribution  map[string]int `json:"title_distribution,omitempty"`
 }
 
 // TournamentResponse represents a chess tournament
@@ -262,6 +321,7 @@ type TournamentStatistics struct {
 	NationDistribution map[string]int   `json:"nation_distribution"`
 	AgeDistribution    map[string]int   `json:"age_distribution"`
 	GenderDistribution map[string]int   `json:"gender_distribution"`
+	TitleDistribution  map[string]int   `json:"title_distribution,omitempty"`
 }
 
 // RatingRange represents rating range statistics
@@ -299,6 +359,21 @@ type RegionAddressResponse struct {
 	Country     string `json:"country"`
 }
 
+// AddressCorrection is a proposed change of a field of an official's address
+type AddressCorrection struct {
+	AddressID string `json:"address_id"`
+	Field     string `json:"field"` // "name", "email", "phone", "address", "city" or "postal_code"
+	Value     string `json:"value"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// AddressCorrectionResult is the receipt of a submitted address correction
+type AddressCorrectionResult struct {
+	ID        string `json:"id"`
+	AddressID string `json:"address_id"`
+	Status    string `json:"status"` // e.g. "pending"
+}
+
 // HealthResponse represents API health status
 type HealthResponse struct {
 	Status       string                 `json:"status"`        // "healthy", "degraded", "unhealthy"
@@ -360,6 +435,8 @@ type SearchParams struct {
 	FilterBy    string `json:"filter_by,omitempty"`
 	FilterValue string `json:"filter_value,omitempty"`
 	Active      *bool  `json:"active,omitempty"`
+	Gender      string `json:"gender,omitempty"` // male/female/divers, sent upstream as m/w/d
+	Title       string `json:"title,omitempty"`  // FIDE title such as GM, IM, WFM
 }
 
 // DateRangeParams represents date range search parameters
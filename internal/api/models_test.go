@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomDate_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  time.Time
+		err   bool
+	}{
+		{"Date only", `"2024-03-15"`, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"RFC 3339", `"2024-03-15T10:30:00Z"`, time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC), false},
+		{"Fractional seconds", `"2024-03-15T10:30:00.5Z"`, time.Date(2024, 3, 15, 10, 30, 0, 5e8, time.UTC), false},
+		{"Escaped string", `"2024-03-15"`, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), false},
+		{"Null", `null`, time.Time{}, false},
+		{"Number", `1`, time.Time{}, true},
+		{"Unquoted date", `2024-03-15`, time.Time{}, true},
+		{"Empty string", `""`, time.Time{}, true},
+		{"German date", `"15.03.2024"`, time.Time{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var date CustomDate
+			err := date.UnmarshalJSON([]byte(tc.input))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(date.Time), "got %v", date.Time)
+		})
+	}
+}
+
+func FuzzCustomDate_UnmarshalJSON(f *testing.F) {
+	for _, seed := range []string{`"2024-03-15"`, `"2024-03-15T10:30:00+02:00"`, `null`, `""`, `1`, `"`, `{}`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var date CustomDate
+		if err := date.UnmarshalJSON(data); err != nil {
+			return
+		}
+
+		// Accepted dates survive a round trip, truncated to the day
+		encoded, err := json.Marshal(date)
+		require.NoError(t, err)
+		var decoded CustomDate
+		require.NoError(t, json.Unmarshal(encoded, &decoded), "%s", encoded)
+		assert.Equal(t, date.Format("2006-01-02"), decoded.Format("2006-01-02"))
+	})
+}
@@ -0,0 +1,50 @@
+package api
+
+// NormalizePagination completes pagination metadata. The mock API reports
+// total/limit/offset/count while the real API reports pages/page; missing
+// fields are derived from the ones present, the request parameters and the
+// number of items returned.
+func NormalizePagination(meta PaginationMetadata, params SearchParams, count int) PaginationMetadata {
+	if meta.Count == 0 {
+		meta.Count = count
+	}
+	if meta.Limit == 0 {
+		meta.Limit = params.Limit
+	}
+	if meta.Limit == 0 {
+		// Fall back to the observed page size
+		meta.Limit = meta.Count
+	}
+
+	if meta.Offset == 0 {
+		if meta.Page > 1 && meta.Limit > 0 {
+			meta.Offset = (meta.Page - 1) * meta.Limit
+		} else {
+			meta.Offset = params.Offset
+		}
+	}
+
+	if meta.Total == 0 {
+		switch {
+		case meta.Pages > 0 && meta.Limit > 0 && meta.Page >= meta.Pages:
+			// Last page: the exact total is known
+			meta.Total = (meta.Pages-1)*meta.Limit + meta.Count
+		case meta.Pages > 0 && meta.Limit > 0:
+			meta.Total = meta.Pages * meta.Limit
+		default:
+			// Without any hint this is a lower bound
+			meta.Total = meta.Offset + meta.Count
+		}
+	}
+
+	if meta.Limit > 0 {
+		if meta.Pages == 0 {
+			meta.Pages = (meta.Total + meta.Limit - 1) / meta.Limit
+		}
+		if meta.Page == 0 {
+			meta.Page = meta.Offset/meta.Limit + 1
+		}
+	}
+
+	return meta
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginationMetadata_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected PaginationMetadata
+	}{
+		{
+			name:     "Mock API fields",
+			data:     `{"total": 120, "limit": 20, "offset": 40, "count": 20}`,
+			expected: PaginationMetadata{Total: 120, Limit: 20, Offset: 40, Count: 20},
+		},
+		{
+			name:     "Real API fields",
+			data:     `{"pages": 6, "page": 3}`,
+			expected: PaginationMetadata{Pages: 6, Page: 3},
+		},
+		{
+			name:     "Alternative field names",
+			data:     `{"total_count": 120, "per_page": 20, "total_pages": 6, "current_page": 3}`,
+			expected: PaginationMetadata{Total: 120, Limit: 20, Pages: 6, Page: 3},
+		},
+		{
+			name:     "Page size alias",
+			data:     `{"total_items": 7, "page_size": 5}`,
+			expected: PaginationMetadata{Total: 7, Limit: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta PaginationMetadata
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &meta))
+			assert.Equal(t, tt.expected, meta)
+		})
+	}
+}
+
+func TestNormalizePagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     PaginationMetadata
+		params   SearchParams
+		count    int
+		expected PaginationMetadata
+	}{
+		{
+			name:     "Total, limit and offset",
+			meta:     PaginationMetadata{Total: 120, Limit: 20, Offset: 40, Count: 20},
+			count:    20,
+			expected: PaginationMetadata{Total: 120, Limit: 20, Offset: 40, Count: 20, Pages: 6, Page: 3},
+		},
+		{
+			name:     "Pages and page with request limit",
+			meta:     PaginationMetadata{Pages: 6, Page: 3},
+			params:   SearchParams{Limit: 20},
+			count:    20,
+			expected: PaginationMetadata{Total: 120, Limit: 20, Offset: 40, Count: 20, Pages: 6, Page: 3},
+		},
+		{
+			name:     "Last page yields the exact total",
+			meta:     PaginationMetadata{Pages: 6, Page: 6, Limit: 20},
+			count:    7,
+			expected: PaginationMetadata{Total: 107, Limit: 20, Offset: 100, Count: 7, Pages: 6, Page: 6},
+		},
+		{
+			name:     "No metadata uses request parameters",
+			params:   SearchParams{Limit: 10, Offset: 30},
+			count:    4,
+			expected: PaginationMetadata{Total: 34, Limit: 10, Offset: 30, Count: 4, Pages: 4, Page: 4},
+		},
+		{
+			name:     "No metadata and no parameters",
+			count:    3,
+			expected: PaginationMetadata{Total: 3, Limit: 3, Count: 3, Pages: 1, Page: 1},
+		},
+		{
+			name:     "Empty result",
+			params:   SearchParams{Limit: 20},
+			expected: PaginationMetadata{Limit: 20, Pages: 0, Page: 1},
+		},
+		{
+			name:     "Total without limit",
+			meta:     PaginationMetadata{Total: 50},
+			count:    25,
+			expected: PaginationMetadata{Total: 50, Limit: 25, Count: 25, Pages: 2, Page: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizePagination(tt.meta, tt.params, tt.count))
+		})
+	}
+}
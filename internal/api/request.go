@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultMaxRetries is the number of retries of idempotent requests
+	defaultMaxRetries = 2
+	// defaultRetryBackoff is the delay before the first retry, doubled on
+	// every further attempt
+	defaultRetryBackoff = 200 * time.Millisecond
+	// maxRetryDelay caps backoff and Retry-After delays
+	maxRetryDelay = 5 * time.Second
+	// maxErrorBodyMatch is the length of error bodies kept for detecting
+	// maintenance of the API
+	maxErrorBodyMatch = 4 << 10
+)
+
+// APIError is an error response of the Portal64 API
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+	// CachedMiss is set on 404s served from the response cache, which may
+	// be out of date for up to the negative caching TTL
+	CachedMiss bool
+	// body is the start of the response body, matched against the pattern
+	// of maintenance pages, which are often not JSON
+	body string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API error %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed when repeated
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNotFound reports whether err is an API 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsCachedMiss reports whether err is an API 404 response served from the
+// response cache instead of the API
+func IsCachedMiss(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.CachedMiss
+}
+
+// decodeAPIError builds an APIError from an error response. The body may be
+// {"message", "code"}, {"error": "..."} or a wrapper with an error object.
+func decodeAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+		return apiErr
+	}
+	apiErr.body = string(body[:min(len(body), maxErrorBodyMatch)])
+
+	var errorBody struct {
+		Message string          `json:"message"`
+		Code    string          `json:"code"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorBody); err != nil {
+		apiErr.Message = "failed to parse error response"
+		return apiErr
+	}
+	apiErr.Message, apiErr.Code = errorBody.Message, errorBody.Code
+
+	var nested struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	}
+	var text string
+	if json.Unmarshal(errorBody.Error, &text) == nil && apiErr.Message == "" {
+		apiErr.Message = text
+	} else if json.Unmarshal(errorBody.Error, &nested) == nil {
+		apiErr.Message = firstNonEmpty(apiErr.Message, nested.Message)
+		apiErr.Code = firstNonEmpty(apiErr.Code, nested.Code)
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = string(bytes.TrimSpace(body))
+	}
+	return apiErr
+}
+
+// requestOptions holds per-call settings of DoJSONRequest
+type requestOptions struct {
+	timeout    time.Duration
+	maxRetries int
+}
+
+// RequestOption customizes a single DoJSONRequest call
+type RequestOption func(*requestOptions)
+
+// WithTimeout overrides the client timeout for each attempt of the call
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetries sets the number of retries. Without it, idempotent methods
+// are retried twice and other methods are not retried.
+func WithRetries(retries int) RequestOption {
+	return func(o *requestOptions) {
+		o.maxRetries = retries
+	}
+}
+
+// isIdempotent reports whether a request with the method may be repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// DoJSONRequest sends a request to an API path with body marshaled as JSON
+// and decodes the (possibly wrapped) response into out. body and out may be
+// nil. Network errors and 429/502/503/504 responses are retried with
+// exponential backoff; error responses are returned as *APIError. Requests
+// of Portal64 data fail at once while the API is under maintenance.
+func (c *Client) DoJSONRequest(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) error {
+	status, err := c.doJSONRequest(ctx, method, path, body, out, opts...)
+	c.observeCall(ctx, method, c.BuildURL(path, nil), status, "", err)
+	return err
+}
+
+// doJSONRequest sends the request of DoJSONRequest with its retries and
+// returns the status code of the last response, 0 if there was none
+func (c *Client) doJSONRequest(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) (int, error) {
+	options := requestOptions{timeout: c.httpClient.Timeout}
+	if isIdempotent(method) {
+		options.maxRetries = defaultMaxRetries
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return 0, fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+
+	httpClient := c.httpClient
+	if options.timeout != c.httpClient.Timeout {
+		override := *c.httpClient
+		override.Timeout = options.timeout
+		httpClient = &override
+	}
+
+	url := c.BuildURL(path, nil)
+	if status := c.Maintenance(); status.Active && cacheEntity(url) != "" {
+		return 0, c.maintenance.maintenanceError(status)
+	}
+
+	var status int
+	var lastErr error
+	for attempt := 0; attempt <= options.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.retryDelay(attempt, lastErr)
+			c.logger.WithFields(logrus.Fields{
+				"method":  method,
+				"url":     url,
+				"attempt": attempt + 1,
+				"delay":   delay,
+			}).WithError(lastErr).Debug("Retrying API request")
+
+			select {
+			case <-ctx.Done():
+				return status, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		var retry bool
+		var err error
+		status, retry, err = c.doJSONAttempt(ctx, httpClient, method, url, payload, out)
+		if err == nil {
+			if method != http.MethodGet {
+				c.cache.invalidate(url)
+			}
+			return status, nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			return status, err
+		}
+	}
+
+	return status, lastErr
+}
+
+// doJSONAttempt performs a single attempt of DoJSONRequest and returns the
+// status code of the response and whether a failure is worth retrying
+func (c *Client) doJSONAttempt(ctx context.Context, httpClient *http.Client, method, url string, payload []byte, out interface{}) (int, bool, error) {
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	c.logger.WithFields(logrus.Fields{
+		"method": method,
+		"url":    url,
+	}).Debug("Making API request")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		c.logger.WithError(err).Error("API request failed")
+		return 0, true, fmt.Errorf("API request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		c.observeStatus(method, url, resp.StatusCode)
+		apiErr := decodeAPIError(resp)
+		// Maintenance is not over by the next retry
+		maintenance := c.maintenance.observe(apiErr)
+		return resp.StatusCode, apiErr.Retryable() && !maintenance, apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		resp.Body.Close()
+		return resp.StatusCode, false, nil
+	}
+	return resp.StatusCode, false, c.decodeInto(resp, out)
+}
+
+// retryDelay returns the delay before a retry, honoring Retry-After
+func (c *Client) retryDelay(attempt int, lastErr error) time.Duration {
+	delay := c.retryBackoff << (attempt - 1)
+
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestTestClient(handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client := createTestClientWithURL(server.URL)
+	client.retryBackoff = time.Millisecond
+	return client, server
+}
+
+func TestClient_DoJSONRequest(t *testing.T) {
+	t.Run("Sends body and decodes wrapped response", func(t *testing.T) {
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/api/v1/players/batch", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var body map[string][]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []string{"C0327-297"}, body["ids"])
+
+			w.Write([]byte(`{"success": true, "data": [{"id": "C0327-297"}]}`))
+		})
+		defer server.Close()
+
+		var players []PlayerResponse
+		err := client.DoJSONRequest(context.Background(), http.MethodPost, "/api/v1/players/batch",
+			map[string][]string{"ids": {"C0327-297"}}, &players)
+		require.NoError(t, err)
+		require.Len(t, players, 1)
+		assert.Equal(t, "C0327-297", players[0].ID)
+	})
+
+	t.Run("No body and no output", func(t *testing.T) {
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, int64(0), r.ContentLength)
+			w.WriteHeader(http.StatusNoContent)
+		})
+		defer server.Close()
+
+		err := client.DoJSONRequest(context.Background(), http.MethodDelete, "/api/v1/cache", nil, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Typed error", func(t *testing.T) {
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success": false, "error": {"code": "PLAYER_NOT_FOUND", "message": "Player not found"}}`))
+		})
+		defer server.Close()
+
+		err := client.DoJSONRequest(context.Background(), http.MethodGet, "/api/v1/players/X", nil, nil)
+		var apiErr *APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "PLAYER_NOT_FOUND", apiErr.Code)
+		assert.Equal(t, "API error 404: Player not found", err.Error())
+		assert.True(t, IsNotFound(err))
+	})
+
+	t.Run("Retries idempotent requests", func(t *testing.T) {
+		var calls int32
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"status": "healthy"}`))
+		})
+		defer server.Close()
+
+		var health HealthResponse
+		err := client.DoJSONRequest(context.Background(), http.MethodGet, "/health", nil, &health)
+		require.NoError(t, err)
+		assert.Equal(t, "healthy", health.Status)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Gives up after retries", func(t *testing.T) {
+		var calls int32
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+		defer server.Close()
+
+		err := client.DoJSONRequest(context.Background(), http.MethodGet, "/health", nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "502")
+		assert.Equal(t, int32(defaultMaxRetries+1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("POST is not retried by default", func(t *testing.T) {
+		var calls int32
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		err := client.DoJSONRequest(context.Background(), http.MethodPost, "/api/v1/players/batch", []string{}, nil)
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		err = client.DoJSONRequest(context.Background(), http.MethodPost, "/api/v1/players/batch", []string{}, nil, WithRetries(1))
+		assert.Error(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Client errors are not retried", func(t *testing.T) {
+		var calls int32
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "invalid limit"}`))
+		})
+		defer server.Close()
+
+		err := client.DoJSONRequest(context.Background(), http.MethodGet, "/api/v1/players", nil, nil)
+		assert.EqualError(t, err, "API error 400: invalid limit")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Timeout override", func(t *testing.T) {
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		})
+		defer server.Close()
+
+		err := client.DoJSONRequest(context.Background(), http.MethodGet, "/slow", nil, nil,
+			WithTimeout(10*time.Millisecond), WithRetries(0))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "API request failed")
+
+		err = client.DoJSONRequest(context.Background(), http.MethodGet, "/slow", nil, nil, WithTimeout(time.Second))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Context cancellation stops retries", func(t *testing.T) {
+		client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+		client.retryBackoff = time.Second
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := client.DoJSONRequest(ctx, http.MethodGet, "/health", nil, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestAPIError_RetryAfter(t *testing.T) {
+	client := createTestClient()
+	client.retryBackoff = time.Millisecond
+
+	err := &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second}
+	assert.True(t, err.Retryable())
+	assert.Equal(t, 2*time.Second, client.retryDelay(1, err))
+	assert.Equal(t, maxRetryDelay, client.retryDelay(1, &APIError{StatusCode: 429, RetryAfter: time.Minute}))
+	assert.Equal(t, 4*time.Millisecond, client.retryDelay(3, errors.New("connection refused")))
+}
+
+func TestClient_OnServerError(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusNotFound, http.StatusInternalServerError}
+	var calls int32
+	client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+		i := atomic.AddInt32(&calls, 1) - 1
+		w.WriteHeader(statuses[int(i)%len(statuses)])
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	var observed []int
+	client.OnServerError(func(method, url string, status int) {
+		assert.Equal(t, http.MethodGet, method)
+		observed = append(observed, status)
+	})
+
+	// The 503 is retried and succeeds
+	require.NoError(t, client.DoJSONRequest(context.Background(), http.MethodGet, "/health", nil, nil))
+	assert.Error(t, client.DoJSONRequest(context.Background(), http.MethodGet, "/health", nil, nil, WithRetries(0)))
+	_, err := client.DoRequest(context.Background(), http.MethodGet, server.URL+"/health")
+	assert.Error(t, err)
+
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusInternalServerError}, observed)
+}
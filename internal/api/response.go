@@ -0,0 +1,368 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// Payload is an upstream response body with any API wrapper removed
+type Payload struct {
+	Data       json.RawMessage
+	Pagination *PaginationMetadata
+	Wrapped    bool
+}
+
+// wrapperKeys are the top-level keys that may appear in a wrapped response.
+// An object with a "data" key and only these keys is treated as a wrapper.
+var wrapperKeys = map[string]bool{
+	"success":    true,
+	"data":       true,
+	"error":      true,
+	"message":    true,
+	"pagination": true,
+	"meta":       true,
+}
+
+// UnwrapPayload detects whether a response body uses the {success, data}
+// wrapper or is a bare payload and returns the inner data. Paginated data
+// nested as {data, meta} or {data, pagination} inside the wrapper is
+// unwrapped as well. An unsuccessful wrapped response is returned as error.
+func UnwrapPayload(body []byte) (*Payload, error) {
+	payload := &Payload{Data: json.RawMessage(bytes.TrimSpace(body))}
+	if len(payload.Data) == 0 {
+		return nil, fmt.Errorf("empty response body")
+	}
+
+	// At most two levels: the success wrapper and the pagination wrapper
+	for i := 0; i < 2; i++ {
+		fields, ok := wrapperFields(payload.Data)
+		if !ok {
+			break
+		}
+
+		if raw, exists := fields["success"]; exists {
+			var success bool
+			if err := json.Unmarshal(raw, &success); err == nil && !success {
+				return nil, unsuccessfulResponseError(fields)
+			}
+		}
+
+		if pagination := decodePagination(fields); pagination != nil {
+			payload.Pagination = pagination
+		}
+
+		payload.Data = fields["data"]
+		payload.Wrapped = true
+	}
+
+	if len(payload.Data) == 0 {
+		payload.Data = json.RawMessage("null")
+	}
+	return payload, nil
+}
+
+// wrapperFields returns the top-level fields of data if it is a wrapper
+// object. Objects without a "data" key, or with keys other than the known
+// wrapper keys, are bare payloads.
+func wrapperFields(data json.RawMessage) (map[string]json.RawMessage, bool) {
+	if len(data) == 0 || data[0] != '{' {
+		return nil, false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, false
+	}
+
+	if _, ok := fields["data"]; !ok {
+		// {"success": false, "error": ...} carries no data at all
+		if _, ok := fields["success"]; !ok {
+			return nil, false
+		}
+	}
+	for key := range fields {
+		if !wrapperKeys[key] {
+			return nil, false
+		}
+	}
+	return fields, true
+}
+
+// decodePagination reads pagination metadata from the "pagination" or
+// "meta" field of a wrapper
+func decodePagination(fields map[string]json.RawMessage) *PaginationMetadata {
+	for _, key := range []string{"pagination", "meta"} {
+		raw, ok := fields[key]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var pagination PaginationMetadata
+		if err := json.Unmarshal(raw, &pagination); err == nil {
+			return &pagination
+		}
+	}
+	return nil
+}
+
+// unsuccessfulResponseError builds an error from a wrapper with success=false
+func unsuccessfulResponseError(fields map[string]json.RawMessage) error {
+	for _, key := range []string{"error", "message"} {
+		var message string
+		if err := json.Unmarshal(fields[key], &message); err == nil && message != "" {
+			return fmt.Errorf("API returned unsuccessful response: %s", message)
+		}
+	}
+	return fmt.Errorf("API returned unsuccessful response")
+}
+
+// decodePayload reads and unwraps a response body
+func (c *Client) decodePayload(resp *http.Response) (*Payload, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to read API response")
+		return nil, fmt.Errorf("response parsing failed: %w", err)
+	}
+
+	payload, err := UnwrapPayload(body)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to decode API response")
+		return nil, err
+	}
+	return payload, nil
+}
+
+// decodeInto reads a response body, removes any wrapper and decodes the
+// inner data into v. Fields that do not fit their model are left empty and
+// reported as warnings instead of failing the request.
+func (c *Client) decodeInto(resp *http.Response, v interface{}) error {
+	payload, err := c.decodePayload(resp)
+	if err != nil {
+		return err
+	}
+
+	problems, err := decodeTolerant(payload.Data, v, "")
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to decode API response")
+		return fmt.Errorf("response parsing failed: %w", err)
+	}
+	c.checkAnomalies(resp, payload.Data, reflect.TypeOf(v), problems)
+	return nil
+}
+
+// decodeList decodes a JSON array into a typed slice. If the array does not
+// decode as a whole, it is decoded again item by item: fields of an item that
+// do not fit the item type are left empty, items that are not of the item
+// type at all are skipped, so one malformed entry does not fail a search.
+// The problems found are returned.
+func decodeList[T any](data json.RawMessage) ([]T, []string, error) {
+	if string(data) == "null" {
+		return []T{}, nil, nil
+	}
+
+	var list []T
+	if err := json.Unmarshal(data, &list); err == nil {
+		if list == nil {
+			list = []T{}
+		}
+		return list, nil, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, nil, fmt.Errorf("response parsing failed: expected a list: %w", err)
+	}
+
+	list = make([]T, 0, len(items))
+	var problems []string
+	for i, item := range items {
+		var value T
+		failed, err := decodeTolerant(item, &value, "[]")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("list item %d skipped: %v", i, err))
+			continue
+		}
+		problems = append(problems, failed...)
+		list = append(list, value)
+	}
+	return list, dedupeStrings(problems), nil
+}
+
+// decodeSearchResponse reads a paginated list response into a SearchResponse
+// with Data set to a typed slice and complete pagination metadata
+func decodeSearchResponse[T any](c *Client, resp *http.Response, params SearchParams) (*SearchResponse, error) {
+	payload, err := c.decodePayload(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	list, problems, err := decodeList[T](payload.Data)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to decode API response")
+		return nil, err
+	}
+	c.checkAnomalies(resp, payload.Data, reflect.TypeOf(list), problems)
+
+	var pagination PaginationMetadata
+	if payload.Pagination != nil {
+		pagination = *payload.Pagination
+	}
+
+	return &SearchResponse{
+		Data:       list,
+		Pagination: NormalizePagination(pagination, params, len(list)),
+	}, nil
+}
+
+// decodeTolerant decodes data into v as far as possible. Values of the wrong
+// JSON type are left empty, as are values whose decoding fails, e.g. dates
+// in an unknown format; their fields are returned as problems. Only invalid
+// JSON and data of the wrong type as a whole are errors. Field paths start
+// with path, such as "[]" for list items.
+func decodeTolerant(data json.RawMessage, v interface{}, path string) ([]string, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JSON")
+	}
+	t := reflect.TypeOf(v).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if expected, actual := expectedKind(t), rawKind(data); expected != "" && actual != "null" && actual != expected {
+		return nil, fmt.Errorf("expected %s, got %s", expected, actual)
+	}
+	problems := dedupeStrings(decodeValue(data, reflect.ValueOf(v).Elem(), path))
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// decodeValue decodes data into rv. Where decoding fails, structs, lists
+// and maps are decoded element by element and values are left empty.
+// Values of the wrong JSON type are reported by the anomaly check instead.
+func decodeValue(data json.RawMessage, rv reflect.Value, path string) []string {
+	err := json.Unmarshal(data, rv.Addr().Interface())
+	if err == nil {
+		return nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	t := rv.Type()
+
+	switch {
+	case reflect.PointerTo(t).Implements(unmarshalerType):
+		// Custom decoders of structs decode what fits, like encoding/json
+		if errors.As(err, &typeErr) && t.Kind() == reflect.Struct {
+			return nil
+		}
+		rv.Set(reflect.Zero(t))
+		if errors.As(err, &typeErr) {
+			return nil
+		}
+		return []string{problemSubject(path) + " cannot be decoded, left empty"}
+	case t.Kind() == reflect.Ptr:
+		elem := reflect.New(t.Elem())
+		problems := decodeValue(data, elem.Elem(), path)
+		if elem.Elem().IsZero() {
+			// Nothing could be decoded
+			elem = reflect.Zero(t)
+		}
+		rv.Set(elem)
+		return problems
+	case t.Kind() == reflect.Struct:
+		var object map[string]json.RawMessage
+		rv.Set(reflect.Zero(t))
+		if json.Unmarshal(data, &object) != nil {
+			return nil
+		}
+		var problems []string
+		for name, index := range fieldIndexes(t) {
+			if raw, ok := object[name]; ok {
+				problems = append(problems, decodeValue(raw, rv.FieldByIndex(index), joinFieldPath(path, name))...)
+			}
+		}
+		return problems
+	case t.Kind() == reflect.Slice:
+		var items []json.RawMessage
+		rv.Set(reflect.Zero(t))
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		list := reflect.MakeSlice(t, len(items), len(items))
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, decodeValue(item, list.Index(i), path+"[]")...)
+		}
+		rv.Set(list)
+		return problems
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		var object map[string]json.RawMessage
+		rv.Set(reflect.Zero(t))
+		if json.Unmarshal(data, &object) != nil {
+			return nil
+		}
+		entries := reflect.MakeMapWithSize(t, len(object))
+		var problems []string
+		for key, raw := range object {
+			value := reflect.New(t.Elem()).Elem()
+			problems = append(problems, decodeValue(raw, value, joinFieldPath(path, key))...)
+			entries.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), value)
+		}
+		rv.Set(entries)
+		return problems
+	default:
+		rv.Set(reflect.Zero(t))
+		return nil
+	}
+}
+
+// problemSubject names the value at a field path in problems
+func problemSubject(path string) string {
+	switch path {
+	case "":
+		return "the response"
+	case "[]":
+		return "a list item"
+	default:
+		return "field " + path
+	}
+}
+
+// rawKind returns the JSON type of raw JSON data
+func rawKind(data json.RawMessage) string {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return "null"
+	}
+	switch data[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+// dedupeStrings removes repeated strings, keeping the first occurrence
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
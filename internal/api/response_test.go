@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnwrapPayload(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		data       string
+		wrapped    bool
+		pagination *PaginationMetadata
+	}{
+		{
+			name:    "Wrapped object",
+			body:    `{"success": true, "data": {"id": "C0327-297"}}`,
+			data:    `{"id": "C0327-297"}`,
+			wrapped: true,
+		},
+		{
+			name: "Bare object",
+			body: `{"id": "C0327-297", "name": "Tran"}`,
+			data: `{"id": "C0327-297", "name": "Tran"}`,
+		},
+		{
+			name:    "Wrapped list",
+			body:    `{"success": true, "data": [{"id": "C0327"}]}`,
+			data:    `[{"id": "C0327"}]`,
+			wrapped: true,
+		},
+		{
+			name: "Bare list",
+			body: `[{"id": "C0327"}]`,
+			data: `[{"id": "C0327"}]`,
+		},
+		{
+			name:       "Search response with pagination",
+			body:       `{"data": [{"id": "C0327"}], "pagination": {"total": 1, "limit": 20}}`,
+			data:       `[{"id": "C0327"}]`,
+			wrapped:    true,
+			pagination: &PaginationMetadata{Total: 1, Limit: 20},
+		},
+		{
+			name:       "Wrapped search response with meta",
+			body:       `{"success": true, "data": {"data": [{"id": "C0327"}], "meta": {"total": 42, "limit": 10, "offset": 20}}}`,
+			data:       `[{"id": "C0327"}]`,
+			wrapped:    true,
+			pagination: &PaginationMetadata{Total: 42, Limit: 10, Offset: 20},
+		},
+		{
+			name:    "Wrapped object with nested data field",
+			body:    `{"success": true, "data": {"data": "raw", "id": "T1"}}`,
+			data:    `{"data": "raw", "id": "T1"}`,
+			wrapped: true,
+		},
+		{
+			name: "Bare object with data field",
+			body: `{"id": "T1", "data": {"rounds": 9}}`,
+			data: `{"id": "T1", "data": {"rounds": 9}}`,
+		},
+		{
+			name:    "Wrapped null data",
+			body:    `{"success": true, "data": null}`,
+			data:    `null`,
+			wrapped: true,
+		},
+		{
+			name:    "Wrapped without data",
+			body:    `{"success": true}`,
+			data:    `null`,
+			wrapped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := UnwrapPayload([]byte(tt.body))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.data, string(payload.Data))
+			assert.Equal(t, tt.wrapped, payload.Wrapped)
+			assert.Equal(t, tt.pagination, payload.Pagination)
+		})
+	}
+}
+
+func TestUnwrapPayload_Errors(t *testing.T) {
+	t.Run("Unsuccessful with error message", func(t *testing.T) {
+		_, err := UnwrapPayload([]byte(`{"success": false, "error": "player not found"}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "player not found")
+	})
+
+	t.Run("Unsuccessful with message", func(t *testing.T) {
+		_, err := UnwrapPayload([]byte(`{"success": false, "data": null, "message": "maintenance"}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maintenance")
+	})
+
+	t.Run("Unsuccessful without message", func(t *testing.T) {
+		_, err := UnwrapPayload([]byte(`{"success": false}`))
+		require.Error(t, err)
+		assert.Equal(t, "API returned unsuccessful response", err.Error())
+	})
+
+	t.Run("Empty body", func(t *testing.T) {
+		_, err := UnwrapPayload([]byte("  "))
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeList(t *testing.T) {
+	t.Run("Tolerates malformed items", func(t *testing.T) {
+		players, problems, err := decodeList[PlayerResponse]([]byte(`[{"id": "C0327-297"}, {"id": 5, "name": "Tran"}, 7, {"id": "C0327-298"}]`))
+		require.NoError(t, err)
+		require.Len(t, players, 3)
+		assert.Equal(t, "C0327-297", players[0].ID)
+		assert.Equal(t, "Tran", players[1].Name, "fields that fit are kept")
+		assert.Equal(t, "C0327-298", players[2].ID)
+		assert.Equal(t, []string{"list item 2 skipped: expected object, got number"}, problems)
+	})
+
+	t.Run("Null is an empty list", func(t *testing.T) {
+		players, _, err := decodeList[PlayerResponse]([]byte(`null`))
+		require.NoError(t, err)
+		assert.Empty(t, players)
+	})
+
+	t.Run("Object is an error", func(t *testing.T) {
+		_, _, err := decodeList[PlayerResponse]([]byte(`{"id": "C0327-297"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Empty list", func(t *testing.T) {
+		players, _, err := decodeList[PlayerResponse]([]byte(`[]`))
+		require.NoError(t, err)
+		assert.NotNil(t, players)
+		assert.Empty(t, players)
+	})
+}
+
+func TestDecodeTolerant(t *testing.T) {
+	var tournament TournamentResponse
+	problems, err := decodeTolerant([]byte(`{"id": "C350-C01-SMU", "name": "Stadtmeisterschaft", "start_date": "next week", "end_date": 20240301, "participants": "many"}`), &tournament, "")
+	require.NoError(t, err)
+	assert.Equal(t, "C350-C01-SMU", tournament.ID)
+	assert.Equal(t, "Stadtmeisterschaft", tournament.Name)
+	assert.Nil(t, tournament.StartDate)
+	assert.Nil(t, tournament.EndDate)
+	assert.Equal(t, []string{"field start_date cannot be decoded, left empty"}, problems,
+		"values of the wrong type are reported by the anomaly check")
+
+	var profile ClubProfileResponse
+	problems, err = decodeTolerant([]byte(`{"club": {"id": "C0327", "name": "SF Ulm"}, "players": [{"id": "C0327-297", "current_dwz": "1850"}, {"id": "C0327-298", "current_dwz": 1700}]}`), &profile, "")
+	require.NoError(t, err)
+	assert.Equal(t, "SF Ulm", profile.Club.Name)
+	require.Len(t, profile.Players, 2, "a malformed player does not fail the profile")
+	assert.Equal(t, "C0327-297", profile.Players[0].ID)
+	assert.Equal(t, 1700, profile.Players[1].CurrentDWZ)
+	assert.Empty(t, problems)
+
+	_, err = decodeTolerant([]byte(`[]`), &profile, "")
+	assert.EqualError(t, err, "expected object, got array")
+	_, err = decodeTolerant([]byte(`{"club":`), &profile, "")
+	assert.Error(t, err)
+}
+
+func TestClient_ResponseShapes(t *testing.T) {
+	shapes := map[string]struct {
+		player  string
+		players string
+		regions string
+	}{
+		"wrapped": {
+			player:  `{"success": true, "data": {"id": "C0327-297", "name": "Tran", "gender": "m"}}`,
+			players: `{"success": true, "data": {"data": [{"id": "C0327-297"}, {"id": "C0327-298"}], "meta": {"total": 2, "limit": 20}}}`,
+			regions: `{"success": true, "data": [{"code": "C", "name": "Württemberg"}]}`,
+		},
+		"bare": {
+			player:  `{"id": "C0327-297", "name": "Tran", "gender": "m"}`,
+			players: `{"data": [{"id": "C0327-297"}, {"id": "C0327-298"}], "pagination": {"total": 2, "limit": 20}}`,
+			regions: `[{"code": "C", "name": "Württemberg"}]`,
+		},
+	}
+
+	for name, shape := range shapes {
+		t.Run(name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/players/C0327-297", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(shape.player))
+			})
+			mux.HandleFunc("/api/v1/players", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(shape.players))
+			})
+			mux.HandleFunc("/api/v1/addresses/regions", func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(shape.regions))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			client := createTestClientWithURL(server.URL)
+			ctx := context.Background()
+
+			player, err := client.GetPlayerProfile(ctx, "C0327-297")
+			require.NoError(t, err)
+			assert.Equal(t, "C0327-297", player.ID)
+			assert.Equal(t, "male", player.Gender)
+
+			result, err := client.SearchPlayers(ctx, SearchParams{Limit: 20})
+			require.NoError(t, err)
+			players, ok := result.Data.([]PlayerResponse)
+			require.True(t, ok)
+			assert.Len(t, players, 2)
+			assert.Equal(t, 2, result.Pagination.Total)
+
+			regions, err := client.GetRegions(ctx)
+			require.NoError(t, err)
+			require.Len(t, regions, 1)
+			assert.Equal(t, "C", regions[0].Code)
+		})
+	}
+}
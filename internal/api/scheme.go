@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemeProbe is the result of requesting /health on one scheme and port
+type SchemeProbe struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	// WrongScheme is set if the port answered in the other protocol, such
+	// as plain HTTP to an HTTPS request
+	WrongScheme bool   `json:"wrong_scheme,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Answers reports whether the Portal64 API answered with the probed scheme.
+// Any HTTP status counts, an unhealthy API is still the right address.
+func (p SchemeProbe) Answers() bool {
+	return p.StatusCode != 0 && !p.WrongScheme
+}
+
+// SchemeDetection is the result of DetectScheme
+type SchemeDetection struct {
+	Configured string        `json:"configured"`
+	Detected   string        `json:"detected,omitempty"` // Base URL that answers, empty if none does
+	Probes     []SchemeProbe `json:"probes"`             // The configured base URL first
+}
+
+// Mismatch reports whether another base URL answers than the configured one
+func (d *SchemeDetection) Mismatch() bool {
+	return d.Detected != "" && d.Detected != d.Configured
+}
+
+// Diagnostic describes the outcome of the probes for the log or an error
+func (d *SchemeDetection) Diagnostic() string {
+	configured := d.Probes[0]
+	var problem string
+	switch {
+	case configured.WrongScheme:
+		problem = fmt.Sprintf("%s does not speak %s: %s", d.Configured, strings.ToUpper(schemeOf(d.Configured)), configured.Error)
+	case configured.Error != "":
+		problem = fmt.Sprintf("%s does not answer: %s", d.Configured, configured.Error)
+	default:
+		return fmt.Sprintf("%s answers with status %d", d.Configured, configured.StatusCode)
+	}
+
+	if d.Detected != "" {
+		return fmt.Sprintf("%s; %s answers, set api.base_url to it", problem, d.Detected)
+	}
+	var tried []string
+	for _, probe := range d.Probes[1:] {
+		tried = append(tried, fmt.Sprintf("%s (%s)", probe.URL, probe.Error))
+	}
+	if len(tried) == 0 {
+		return problem
+	}
+	return fmt.Sprintf("%s; no other scheme or port answers either: %s", problem, strings.Join(tried, ", "))
+}
+
+// DetectScheme checks that the base URL answers with its scheme. If it does
+// not, the same host is probed with the other scheme on the same port and
+// with the candidates, "scheme:port" pairs such as "https:8443", in this
+// order of preference. The probes run concurrently with the TLS settings of
+// the client, each limited by timeout. The client itself is not changed.
+func (c *Client) DetectScheme(ctx context.Context, candidates []string, timeout time.Duration) (*SchemeDetection, error) {
+	base, err := url.Parse(c.baseURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", c.baseURL)
+	}
+
+	transport := &http.Transport{
+		Proxy:               c.transport.Proxy,
+		DialContext:         (&net.Dialer{Timeout: timeout}).DialContext,
+		TLSClientConfig:     c.transport.TLSClientConfig.Clone(),
+		TLSHandshakeTimeout: timeout,
+		DisableKeepAlives:   true,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport, Timeout: timeout}
+
+	detection := &SchemeDetection{Configured: c.baseURL}
+	urls := []string{c.baseURL}
+	seen := map[string]bool{c.baseURL: true}
+	add := func(scheme, port string) {
+		u := *base
+		u.Scheme = scheme
+		u.Host = net.JoinHostPort(base.Hostname(), port)
+		candidate := strings.TrimSuffix(u.String(), "/")
+		if !seen[candidate] {
+			seen[candidate] = true
+			urls = append(urls, candidate)
+		}
+	}
+	add(otherScheme(base.Scheme), portOf(base))
+	for _, candidate := range candidates {
+		scheme, port, err := ParseSchemeCandidate(candidate)
+		if err != nil {
+			return nil, err
+		}
+		add(scheme, port)
+	}
+
+	detection.Probes = make([]SchemeProbe, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			detection.Probes[i] = probeScheme(ctx, client, u)
+		}(i, u)
+	}
+	wg.Wait()
+
+	for _, probe := range detection.Probes {
+		if probe.Answers() {
+			detection.Detected = probe.URL
+			break
+		}
+	}
+	return detection, nil
+}
+
+// ParseSchemeCandidate splits a "scheme:port" candidate of DetectScheme
+func ParseSchemeCandidate(candidate string) (scheme, port string, err error) {
+	scheme, port, ok := strings.Cut(strings.TrimSpace(candidate), ":")
+	scheme = strings.ToLower(scheme)
+	if !ok || (scheme != "http" && scheme != "https") {
+		return "", "", fmt.Errorf("invalid scheme candidate %q, expected http:<port> or https:<port>", candidate)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("invalid port in scheme candidate %q", candidate)
+	}
+	return scheme, port, nil
+}
+
+// probeScheme requests /health below a base URL and tells a port speaking
+// the other protocol apart from one that does not answer at all
+func probeScheme(ctx context.Context, client *http.Client, baseURL string) SchemeProbe {
+	probe := SchemeProbe{URL: baseURL}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		probe.Error = err.Error()
+		// net/http reports an HTTPS request to a plain HTTP port, and the
+		// TLS alert of an HTTPS port to a plain request, only as text
+		probe.WrongScheme = strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") ||
+			strings.Contains(err.Error(), "malformed HTTP response")
+		return probe
+	}
+	defer resp.Body.Close()
+	probe.StatusCode = resp.StatusCode
+
+	// HTTPS servers such as Go's and nginx answer plain requests with a
+	// 400 mentioning HTTPS
+	if resp.StatusCode == http.StatusBadRequest && resp.TLS == nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if strings.Contains(string(body), "HTTPS") {
+			probe.WrongScheme = true
+			probe.Error = strings.TrimSpace(firstLine(string(body)))
+		}
+	}
+	return probe
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+func otherScheme(scheme string) string {
+	if scheme == "https" {
+		return "http"
+	}
+	return "https"
+}
+
+// portOf returns the explicit or the default port of a URL
+func portOf(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+func schemeOf(rawURL string) string {
+	scheme, _, _ := strings.Cut(rawURL, "://")
+	return scheme
+}
+
+// SetBaseURL replaces the primary base URL, e.g. with the one found by
+// DetectScheme. Like ConfigureTLS, it must be called before
+// ConfigureFailover and before the client is used.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchemeCandidate(t *testing.T) {
+	scheme, port, err := ParseSchemeCandidate(" HTTPS:8443")
+	require.NoError(t, err)
+	assert.Equal(t, "https", scheme)
+	assert.Equal(t, "8443", port)
+
+	for _, candidate := range []string{"8443", "ftp:21", "http:", "http:0", "https:70000"} {
+		_, _, err := ParseSchemeCandidate(candidate)
+		assert.Error(t, err, candidate)
+	}
+}
+
+func TestDetectScheme(t *testing.T) {
+	health := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok"}`))
+	})
+	plain := httptest.NewServer(health)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(health)
+	defer secure.Close()
+
+	_, plainPort, _ := net.SplitHostPort(plain.Listener.Addr().String())
+	_, securePort, _ := net.SplitHostPort(secure.Listener.Addr().String())
+	newClient := func(baseURL string) *Client {
+		client := NewClient(baseURL, 5*time.Second, nil)
+		client.transport.TLSClientConfig = secure.Client().Transport.(*http.Transport).TLSClientConfig
+		return client
+	}
+	ctx := context.Background()
+
+	t.Run("Configured scheme answers", func(t *testing.T) {
+		detection, err := newClient(secure.URL).DetectScheme(ctx, nil, time.Second)
+		require.NoError(t, err)
+		assert.False(t, detection.Mismatch())
+		assert.Equal(t, secure.URL, detection.Detected)
+		assert.Equal(t, secure.URL+" answers with status 200", detection.Diagnostic())
+	})
+
+	t.Run("HTTPS to a plain port", func(t *testing.T) {
+		configured := "https://127.0.0.1:" + plainPort
+		detection, err := newClient(configured).DetectScheme(ctx, nil, time.Second)
+		require.NoError(t, err)
+		assert.True(t, detection.Mismatch())
+		assert.True(t, detection.Probes[0].WrongScheme)
+		assert.Equal(t, plain.URL, detection.Detected)
+		assert.Contains(t, detection.Diagnostic(), configured+" does not speak HTTPS")
+		assert.True(t, strings.HasSuffix(detection.Diagnostic(), plain.URL+" answers, set api.base_url to it"))
+	})
+
+	t.Run("HTTP to a TLS port", func(t *testing.T) {
+		detection, err := newClient("http://127.0.0.1:"+securePort).DetectScheme(ctx, nil, time.Second)
+		require.NoError(t, err)
+		assert.True(t, detection.Probes[0].WrongScheme, detection.Probes[0].Error)
+		assert.Equal(t, secure.URL, detection.Detected)
+	})
+
+	t.Run("Candidate port", func(t *testing.T) {
+		closed := httptest.NewServer(health)
+		closed.Close()
+		detection, err := newClient(closed.URL).DetectScheme(ctx, []string{"http:" + plainPort}, time.Second)
+		require.NoError(t, err)
+		require.Len(t, detection.Probes, 3)
+		assert.False(t, detection.Probes[0].WrongScheme)
+		assert.Equal(t, plain.URL, detection.Detected)
+		assert.Contains(t, detection.Diagnostic(), closed.URL+" does not answer")
+	})
+
+	t.Run("Nothing answers", func(t *testing.T) {
+		closed := httptest.NewServer(health)
+		closed.Close()
+		detection, err := newClient(closed.URL).DetectScheme(ctx, nil, time.Second)
+		require.NoError(t, err)
+		assert.False(t, detection.Mismatch())
+		assert.Empty(t, detection.Detected)
+		assert.Contains(t, detection.Diagnostic(), "no other scheme or port answers either: https://")
+
+		_, err = newClient(closed.URL).DetectScheme(ctx, []string{"https"}, time.Second)
+		assert.Error(t, err)
+	})
+}
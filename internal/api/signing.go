@@ -0,0 +1,193 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Headers of signed requests
+const (
+	// DefaultSignatureHeader carries the request signatures unless another
+	// header is configured
+	DefaultSignatureHeader = "X-Portal64-Signature"
+	// TimestampHeader carries the Unix time in seconds the request was
+	// signed at
+	TimestampHeader = "X-Portal64-Timestamp"
+	// ContentDigestHeader carries the hex SHA-256 digest of the request body
+	ContentDigestHeader = "X-Portal64-Content-SHA256"
+)
+
+// RequestSigningOptions configures HMAC-SHA256 signatures of requests to
+// the Portal64 API. During a key rotation requests are signed with the
+// current and the previous key, so the upstream may switch keys at any time.
+type RequestSigningOptions struct {
+	Key           string // HMAC secret
+	KeyID         string // Sent with the signature, optional
+	PreviousKey   string // Also signs requests while the upstream still expects it
+	PreviousKeyID string
+	Header        string // Default X-Portal64-Signature
+	// ClockSkew is the tolerated difference between the local clock and
+	// the Date header of the upstream. Beyond it, timestamps follow the
+	// upstream clock.
+	ClockSkew time.Duration
+}
+
+// signingKey is an HMAC key of request signatures
+type signingKey struct {
+	id     string
+	secret []byte
+}
+
+// ConfigureRequestSigning signs every request of the client with the
+// timestamp, the path with query and the digest of the body:
+//
+//	<unix seconds>\n<path?query>\n<hex SHA-256 of the body>
+//
+// Like ConfigureTLS, it must be called before ConfigureFailover and before
+// the client is used.
+func (c *Client) ConfigureRequestSigning(opts RequestSigningOptions) error {
+	if opts.Key == "" {
+		return fmt.Errorf("request signing requires a key")
+	}
+	if opts.ClockSkew < 0 {
+		return fmt.Errorf("the clock skew tolerance must not be negative")
+	}
+	signer := &signingTransport{
+		base:      c.httpClient.Transport,
+		keys:      []signingKey{{id: opts.KeyID, secret: []byte(opts.Key)}},
+		header:    opts.Header,
+		tolerance: opts.ClockSkew,
+		now:       time.Now,
+		logger:    c.logger,
+	}
+	if opts.PreviousKey != "" {
+		signer.keys = append(signer.keys, signingKey{id: opts.PreviousKeyID, secret: []byte(opts.PreviousKey)})
+	}
+	if signer.header == "" {
+		signer.header = DefaultSignatureHeader
+	}
+	c.httpClient.Transport = signer
+	return nil
+}
+
+// signingTransport signs requests with HMAC-SHA256
+type signingTransport struct {
+	base      http.RoundTripper
+	keys      []signingKey // Current key first
+	header    string
+	tolerance time.Duration
+	now       func() time.Time
+	logger    Logger
+	// offset is added to the local clock in seconds, nonzero while the
+	// upstream clock differs by more than the tolerance
+	offset atomic.Int64
+}
+
+// RoundTrip implements http.RoundTripper. A request rejected with 401 or
+// 403 is repeated once if the Date header of the rejection shows that the
+// clocks drifted apart.
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+	}
+
+	resp, skewed, err := t.send(req, body)
+	if err != nil || !skewed || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return resp, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	resp, _, err = t.send(req, body)
+	return resp, err
+}
+
+// send signs and sends a copy of the request and reports whether the
+// response changed the clock offset
+func (t *signingTransport) send(req *http.Request, body []byte) (*http.Response, bool, error) {
+	signed := req.Clone(req.Context())
+	switch {
+	case req.Body == nil:
+	case len(body) == 0:
+		// An empty reader would be sent chunked, as of unknown length
+		signed.Body = http.NoBody
+		signed.ContentLength = 0
+	default:
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.ContentLength = int64(len(body))
+	}
+
+	timestamp := strconv.FormatInt(t.now().Unix()+t.offset.Load(), 10)
+	digest := sha256.Sum256(body)
+	signed.Header.Set(TimestampHeader, timestamp)
+	signed.Header.Set(ContentDigestHeader, hex.EncodeToString(digest[:]))
+	signed.Header.Set(t.header, t.sign(canonicalRequest(timestamp, signed, digest[:])))
+
+	resp, err := t.base.RoundTrip(signed)
+	if err != nil {
+		return nil, false, err
+	}
+	return resp, t.observeClock(resp), nil
+}
+
+// canonicalRequest returns the signed form of a request
+func canonicalRequest(timestamp string, req *http.Request, digest []byte) []byte {
+	return []byte(timestamp + "\n" + req.URL.RequestURI() + "\n" + hex.EncodeToString(digest))
+}
+
+// sign returns the signature header value, one signature per key separated
+// by "; ", each alg=hmac-sha256[,keyid=<key ID>],sig=<base64 signature>
+func (t *signingTransport) sign(canonical []byte) string {
+	signatures := make([]string, len(t.keys))
+	for i, key := range t.keys {
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write(canonical)
+		value := "alg=hmac-sha256"
+		if key.id != "" {
+			value += ",keyid=" + key.id
+		}
+		signatures[i] = value + ",sig=" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	return strings.Join(signatures, "; ")
+}
+
+// observeClock compares the Date header of a response with the local clock
+// and updates the offset of timestamps. It reports whether the offset
+// changed.
+func (t *signingTransport) observeClock(resp *http.Response) bool {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return false
+	}
+	skew := date.Sub(t.now())
+	var offset int64
+	if skew > t.tolerance || -skew > t.tolerance {
+		offset = int64(skew.Round(time.Second) / time.Second)
+	}
+	// Date headers have a resolution of one second, so differences of a
+	// second do not move the offset
+	previous := t.offset.Load()
+	if previous == offset || (previous != 0 && offset != 0 && offset-previous <= 1 && previous-offset <= 1) {
+		return false
+	}
+	t.offset.Store(offset)
+	if offset != 0 {
+		t.logger.WithField("skew", skew.Round(time.Second).String()).Warn("Portal64 API clock differs from the local clock, request timestamps follow the upstream clock")
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectedSignature computes the signature of a request as the upstream
+// would
+func expectedSignature(secret, timestamp, path string, body []byte) string {
+	digest := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + path + "\n" + hex.EncodeToString(digest[:])))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestConfigureRequestSigning(t *testing.T) {
+	type signedRequest struct {
+		header http.Header
+		path   string
+		body   []byte
+	}
+	var last signedRequest
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		last = signedRequest{header: r.Header.Clone(), path: r.URL.RequestURI(), body: body}
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	client.SetReadOnly(false)
+	require.NoError(t, client.ConfigureRequestSigning(RequestSigningOptions{Key: "current", KeyID: "k2", PreviousKey: "previous", PreviousKeyID: "k1", ClockSkew: 30 * time.Second}))
+
+	body := []byte(`{"name": "Open"}`)
+	resp, err := client.httpClient.Post(upstream.URL+"/api/v1/tournaments?dry_run=true", "application/json", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, body, last.body, "the body is sent unchanged")
+	timestamp := last.header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Unix(), seconds, 2)
+	digest := sha256.Sum256(body)
+	assert.Equal(t, hex.EncodeToString(digest[:]), last.header.Get(ContentDigestHeader))
+	assert.Equal(t, "alg=hmac-sha256,keyid=k2,sig="+expectedSignature("current", timestamp, "/api/v1/tournaments?dry_run=true", body)+
+		"; alg=hmac-sha256,keyid=k1,sig="+expectedSignature("previous", timestamp, "/api/v1/tournaments?dry_run=true", body),
+		last.header.Get(DefaultSignatureHeader))
+
+	assert.Error(t, NewClient(upstream.URL, time.Second, nil).ConfigureRequestSigning(RequestSigningOptions{}))
+}
+
+func TestConfigureRequestSigning_ClockSkew(t *testing.T) {
+	// The upstream clock is five minutes ahead and rejects timestamps that
+	// are off by more than a minute
+	upstreamNow := func() time.Time { return time.Now().Add(5 * time.Minute) }
+	var rejected int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", upstreamNow().UTC().Format(http.TimeFormat))
+		seconds, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if d := upstreamNow().Unix() - seconds; d > 60 || d < -60 {
+			atomic.AddInt32(&rejected, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer upstream.Close()
+
+	client := NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, client.ConfigureRequestSigning(RequestSigningOptions{Key: "secret", ClockSkew: 30 * time.Second}))
+
+	_, err := client.Health(context.Background())
+	require.NoError(t, err, "the rejected request is repeated with the upstream time")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&rejected))
+
+	_, err = client.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&rejected), "later requests use the upstream time")
+}
@@ -0,0 +1,56 @@
+package api
+
+import "strings"
+
+// PlayerBreakdown counts players per gender and per FIDE title. Players
+// without a title are counted as "none".
+func PlayerBreakdown(players []PlayerResponse) (gender, title map[string]int) {
+	gender = make(map[string]int)
+	title = make(map[string]int)
+
+	for _, p := range players {
+		if p.Gender != "" {
+			gender[NormalizeGender(p.Gender)]++
+		}
+		if p.Title != "" {
+			title[strings.ToUpper(p.Title)]++
+		} else {
+			title["none"]++
+		}
+	}
+
+	return gender, title
+}
+
+// ParticipantStatistics computes tournament statistics from its participants
+func ParticipantStatistics(players []PlayerResponse) *TournamentStatistics {
+	stats := &TournamentStatistics{
+		NationDistribution: make(map[string]int),
+		AgeDistribution:    make(map[string]int),
+	}
+	stats.GenderDistribution, stats.TitleDistribution = PlayerBreakdown(players)
+
+	sum, rated := 0, 0
+	for _, p := range players {
+		if p.Nation != "" {
+			stats.NationDistribution[p.Nation]++
+		}
+		if p.CurrentDWZ <= 0 {
+			continue
+		}
+		rated++
+		sum += p.CurrentDWZ
+		if stats.RatingRange.Min == 0 || p.CurrentDWZ < stats.RatingRange.Min {
+			stats.RatingRange.Min = p.CurrentDWZ
+		}
+		if p.CurrentDWZ > stats.RatingRange.Max {
+			stats.RatingRange.Max = p.CurrentDWZ
+		}
+	}
+
+	if rated > 0 {
+		stats.AverageRating = float64(sum) / float64(rated)
+	}
+
+	return stats
+}
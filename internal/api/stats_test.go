@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeGender(t *testing.T) {
+	assert.Equal(t, "male", NormalizeGender("m"))
+	assert.Equal(t, "female", NormalizeGender("W"))
+	assert.Equal(t, "female", NormalizeGender("f"))
+	assert.Equal(t, "divers", NormalizeGender("d"))
+	assert.Equal(t, "other", NormalizeGender("other"))
+}
+
+func TestParticipantStatistics(t *testing.T) {
+	players := []PlayerResponse{
+		{Gender: "male", Nation: "GER", CurrentDWZ: 2400, Title: "IM"},
+		{Gender: "female", Nation: "GER", CurrentDWZ: 2100, Title: "wfm"},
+		{Gender: "male", Nation: "AUT", CurrentDWZ: 0},
+	}
+
+	stats := ParticipantStatistics(players)
+
+	assert.Equal(t, map[string]int{"male": 2, "female": 1}, stats.GenderDistribution)
+	assert.Equal(t, map[string]int{"IM": 1, "WFM": 1, "none": 1}, stats.TitleDistribution)
+	assert.Equal(t, map[string]int{"GER": 2, "AUT": 1}, stats.NationDistribution)
+	assert.Equal(t, 2100, stats.RatingRange.Min)
+	assert.Equal(t, 2400, stats.RatingRange.Max)
+	assert.InDelta(t, 2250.0, stats.AverageRating, 0.001)
+}
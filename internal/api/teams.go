@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GetClubTeams retrieves the league teams of a club. Older API versions
+// without a teams endpoint are served from the club profile.
+func (c *Client) GetClubTeams(ctx context.Context, clubID string) ([]ClubTeam, error) {
+	url := c.BuildURL(fmt.Sprintf("/api/v1/clubs/%s/teams", clubID), nil)
+
+	resp, err := c.DoRequest(ctx, "GET", url)
+	if IsNotFound(err) {
+		c.logger.WithField("club_id", clubID).Debug("No teams endpoint, using club profile")
+		profile, err := c.GetClubProfile(ctx, clubID)
+		if err != nil {
+			return nil, err
+		}
+		return profile.Teams, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []ClubTeam
+	if err := c.decodeInto(resp, &teams); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// GetTeamRoster retrieves a team of a club with its assigned players. team
+// is matched against the team ID or, case-insensitively, the team name.
+func (c *Client) GetTeamRoster(ctx context.Context, clubID, team string) (*ClubTeam, error) {
+	path := fmt.Sprintf("/api/v1/clubs/%s/teams/%s", clubID, url.PathEscape(team))
+	url := c.BuildURL(path, nil)
+
+	resp, err := c.DoRequest(ctx, "GET", url)
+	if err == nil {
+		var roster ClubTeam
+		if err := c.decodeInto(resp, &roster); err != nil {
+			return nil, err
+		}
+		return &roster, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+
+	// No roster endpoint or the team is addressed by name
+	teams, err := c.GetClubTeams(ctx, clubID)
+	if err != nil {
+		return nil, err
+	}
+	if found := FindTeam(teams, team); found != nil {
+		return found, nil
+	}
+	return nil, &APIError{StatusCode: http.StatusNotFound, Code: "TEAM_NOT_FOUND", Message: fmt.Sprintf("team %s not found in club %s", team, clubID)}
+}
+
+// FindTeam returns the team with the given ID or name, or nil
+func FindTeam(teams []ClubTeam, team string) *ClubTeam {
+	for i := range teams {
+		if teams[i].ID != "" && teams[i].ID == team {
+			return &teams[i]
+		}
+	}
+	for i := range teams {
+		if strings.EqualFold(strings.TrimSpace(teams[i].Name), strings.TrimSpace(team)) {
+			return &teams[i]
+		}
+	}
+	return nil
+}
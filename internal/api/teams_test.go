@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetClubTeams(t *testing.T) {
+	t.Run("Teams endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/clubs/C0327/teams", r.URL.Path)
+			w.Write([]byte(`{"success": true, "data": [{"id": "T1", "name": "1. Mannschaft", "league": "Oberliga", "season": "2023/24"}]}`))
+		}))
+		defer server.Close()
+
+		teams, err := createTestClientWithURL(server.URL).GetClubTeams(context.Background(), "C0327")
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		assert.Equal(t, "Oberliga", teams[0].League)
+	})
+
+	t.Run("Falls back to the club profile", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/clubs/C0327/profile":
+				w.Write([]byte(`{"club": {"id": "C0327"}, "teams": [{"name": "1. Mannschaft", "league": "Verbandsliga"}, {"name": "2. Mannschaft", "league": "Bezirksklasse A"}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		client := createTestClientWithURL(server.URL)
+		teams, err := client.GetClubTeams(context.Background(), "C0327")
+		require.NoError(t, err)
+		assert.Len(t, teams, 2)
+
+		roster, err := client.GetTeamRoster(context.Background(), "C0327", "2. mannschaft")
+		require.NoError(t, err)
+		assert.Equal(t, "Bezirksklasse A", roster.League)
+
+		_, err = client.GetTeamRoster(context.Background(), "C0327", "3. Mannschaft")
+		assert.True(t, IsNotFound(err))
+		assert.EqualError(t, err, "API error 404: team 3. Mannschaft not found in club C0327")
+	})
+}
+
+func TestClient_GetTeamRoster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/clubs/C0327/teams/T1", r.URL.Path)
+		w.Write([]byte(`{"id": "T1", "name": "1. Mannschaft", "players": [{"board": 1, "player_id": "C0327-297", "name": "Müller, Max", "dwz": 2105}]}`))
+	}))
+	defer server.Close()
+
+	roster, err := createTestClientWithURL(server.URL).GetTeamRoster(context.Background(), "C0327", "T1")
+	require.NoError(t, err)
+	require.Len(t, roster.Players, 1)
+	assert.Equal(t, TeamPlayer{Board: 1, PlayerID: "C0327-297", Name: "Müller, Max", DWZ: 2105}, roster.Players[0])
+}
+
+func TestFindTeam(t *testing.T) {
+	teams := []ClubTeam{{ID: "2", Name: "1. Mannschaft"}, {ID: "1", Name: "2. Mannschaft"}}
+
+	assert.Equal(t, "2. Mannschaft", FindTeam(teams, "1").Name, "IDs take precedence over names")
+	assert.Equal(t, "2", FindTeam(teams, " 1. MANNSCHAFT ").ID)
+	assert.Nil(t, FindTeam(teams, "Jugend"))
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSOptions configures TLS for connections to the Portal64 API
+type TLSOptions struct {
+	CAFile             string // PEM file with additional root CAs
+	ClientCert         string // PEM client certificate for mutual TLS
+	ClientKey          string // PEM private key of the client certificate
+	InsecureSkipVerify bool   // Disables server certificate verification
+}
+
+// IsZero reports whether no TLS option is set
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// BuildTLSConfig builds a TLS configuration from the options. Custom CAs are
+// added to the system pool, so public endpoints keep working.
+func BuildTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s contains no valid PEM certificates", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (opts.ClientCert == "") != (opts.ClientKey == "") {
+		return nil, fmt.Errorf("client certificate and client key must be set together")
+	}
+	if opts.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client key pair (cert %s, key %s): %w", opts.ClientCert, opts.ClientKey, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// ConfigureTLS applies TLS options to the client's transport. It is meant to
+// be called once at startup, before the client is used.
+func (c *Client) ConfigureTLS(opts TLSOptions) error {
+	if opts.IsZero() {
+		return nil
+	}
+
+	tlsConfig, err := BuildTLSConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	c.transport.TLSClientConfig = tlsConfig
+
+	if opts.InsecureSkipVerify {
+		c.logger.Warn("TLS certificate verification of the Portal64 API is disabled")
+	}
+	return nil
+}
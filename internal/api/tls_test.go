@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair writes a self-signed certificate and its key as PEM files
+func writeKeyPair(t *testing.T, dir, name string) (certFile, keyFile string, cert *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".pem")
+	keyFile = filepath.Join(dir, name+"-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile, cert
+}
+
+// writeServerCA writes the certificate of a TLS test server as CA file
+func writeServerCA(t *testing.T, dir string, server *httptest.Server) string {
+	caFile := filepath.Join(dir, "ca.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, pemData, 0600))
+	return caFile
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("Empty options", func(t *testing.T) {
+		tlsConfig, err := BuildTLSConfig(TLSOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, tlsConfig.RootCAs)
+		assert.Empty(t, tlsConfig.Certificates)
+		assert.False(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("Client key pair", func(t *testing.T) {
+		certFile, keyFile, _ := writeKeyPair(t, dir, "client")
+		tlsConfig, err := BuildTLSConfig(TLSOptions{ClientCert: certFile, ClientKey: keyFile})
+		require.NoError(t, err)
+		assert.Len(t, tlsConfig.Certificates, 1)
+	})
+
+	t.Run("Mismatched key pair", func(t *testing.T) {
+		certFile, _, _ := writeKeyPair(t, dir, "first")
+		_, otherKey, _ := writeKeyPair(t, dir, "second")
+		_, err := BuildTLSConfig(TLSOptions{ClientCert: certFile, ClientKey: otherKey})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid client key pair")
+	})
+
+	t.Run("Certificate without key", func(t *testing.T) {
+		certFile, _, _ := writeKeyPair(t, dir, "lonely")
+		_, err := BuildTLSConfig(TLSOptions{ClientCert: certFile})
+		assert.EqualError(t, err, "client certificate and client key must be set together")
+	})
+
+	t.Run("Missing CA file", func(t *testing.T) {
+		_, err := BuildTLSConfig(TLSOptions{CAFile: filepath.Join(dir, "missing.pem")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read CA file")
+	})
+
+	t.Run("CA file without certificates", func(t *testing.T) {
+		caFile := filepath.Join(dir, "garbage.pem")
+		require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0600))
+		_, err := BuildTLSConfig(TLSOptions{CAFile: caFile})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "contains no valid PEM certificates")
+	})
+
+	t.Run("Insecure skip verify", func(t *testing.T) {
+		tlsConfig, err := BuildTLSConfig(TLSOptions{InsecureSkipVerify: true})
+		require.NoError(t, err)
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+	})
+}
+
+func TestClient_ConfigureTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, clientKey, clientX509 := writeKeyPair(t, dir, "client")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientX509)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "healthy"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writeServerCA(t, dir, server)
+	ctx := context.Background()
+
+	t.Run("Untrusted server is rejected", func(t *testing.T) {
+		client := createTestClientWithURL(server.URL)
+		_, err := client.Health(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("Custom CA without client certificate", func(t *testing.T) {
+		client := createTestClientWithURL(server.URL)
+		require.NoError(t, client.ConfigureTLS(TLSOptions{CAFile: caFile}))
+		_, err := client.Health(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("Mutual TLS", func(t *testing.T) {
+		client := createTestClientWithURL(server.URL)
+		require.NoError(t, client.ConfigureTLS(TLSOptions{CAFile: caFile, ClientCert: clientCert, ClientKey: clientKey}))
+		health, err := client.Health(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "healthy", health.Status)
+	})
+
+	t.Run("Insecure skip verify", func(t *testing.T) {
+		client := createTestClientWithURL(server.URL)
+		require.NoError(t, client.ConfigureTLS(TLSOptions{InsecureSkipVerify: true, ClientCert: clientCert, ClientKey: clientKey}))
+		_, err := client.Health(ctx)
+		assert.NoError(t, err)
+	})
+}
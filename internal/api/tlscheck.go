@@ -0,0 +1,369 @@
+package api
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Severities of TLS findings
+const (
+	SeverityOK      = "ok"
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// certificateExpiryWarning is how long before expiry a certificate is
+// reported
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// TLSFinding is a problem found in the TLS configuration
+type TLSFinding struct {
+	Severity string `json:"severity"` // "info", "warning" or "error"
+	File     string `json:"file,omitempty"`
+	Message  string `json:"message"`
+	Action   string `json:"action,omitempty"` // How to fix it
+}
+
+// CertificateInfo describes a certificate of a PEM file
+type CertificateInfo struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	NotBefore          time.Time `json:"not_before"`
+	NotAfter           time.Time `json:"not_after"`
+	DaysLeft           int       `json:"days_left"`
+	KeyAlgorithm       string    `json:"key_algorithm"` // e.g. "RSA 2048" or "ECDSA P-256"
+	SignatureAlgorithm string    `json:"signature_algorithm"`
+	IsCA               bool      `json:"is_ca"`
+}
+
+// TLSCheck is the result of checking the TLS files of the configuration
+type TLSCheck struct {
+	// Status is the most severe finding, "ok" without findings
+	Status             string            `json:"status"`
+	ClientCertificate  []CertificateInfo `json:"client_certificate,omitempty"` // The chain, leaf first
+	CABundle           []CertificateInfo `json:"ca_bundle,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify"`
+	Findings           []TLSFinding      `json:"findings"`
+}
+
+// add records a finding
+func (c *TLSCheck) add(severity, file, action, format string, args ...interface{}) {
+	c.Findings = append(c.Findings, TLSFinding{Severity: severity, File: file, Message: fmt.Sprintf(format, args...), Action: action})
+}
+
+// CheckTLS checks the files of the TLS options: that the client certificate
+// matches its key, is complete, valid at now and fit for client
+// authentication, that the CA bundle holds valid CA certificates, that no
+// weak algorithms are used and that keys are not readable by others. Unlike
+// BuildTLSConfig it reports every problem found instead of the first.
+func CheckTLS(opts TLSOptions, now time.Time) *TLSCheck {
+	check := &TLSCheck{InsecureSkipVerify: opts.InsecureSkipVerify, Findings: []TLSFinding{}}
+
+	if opts.InsecureSkipVerify {
+		check.add(SeverityWarning, "", "remove api.ssl.insecure_skip_verify and set api.ssl.ca_file to the CA of the server",
+			"certificate verification of the Portal64 API is disabled")
+	}
+
+	var caCerts []*x509.Certificate
+	if opts.CAFile != "" {
+		caCerts = check.checkCABundle(opts.CAFile, now)
+	}
+
+	switch {
+	case opts.ClientCert != "" && opts.ClientKey != "":
+		check.checkClientKeyPair(opts.ClientCert, opts.ClientKey, caCerts, now)
+	case opts.ClientCert != "" || opts.ClientKey != "":
+		check.add(SeverityError, "", "set both api.ssl.client_cert and api.ssl.client_key, or neither",
+			"client certificate and client key must be set together")
+	}
+
+	if opts.IsZero() {
+		check.add(SeverityInfo, "", "", "no TLS files are configured, the system root CAs verify the Portal64 API")
+	}
+
+	check.Status = SeverityOK
+	for _, f := range check.Findings {
+		if severityRank(f.Severity) > severityRank(check.Status) {
+			check.Status = f.Severity
+		}
+	}
+	return check
+}
+
+// severityRank orders severities from ok to error
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityError:
+		return 3
+	}
+	return 0
+}
+
+// readPEMCertificates reads the certificates of a PEM file. Blocks of
+// other types are counted.
+func readPEMCertificates(data []byte) (certs []*x509.Certificate, other int, err error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, other, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			other++
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, other, fmt.Errorf("certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// checkCABundle checks the CA file and returns its certificates
+func (c *TLSCheck) checkCABundle(file string, now time.Time) []*x509.Certificate {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		c.add(SeverityError, file, "check api.ssl.ca_file and the permissions of the file", "the CA file cannot be read: %v", err)
+		return nil
+	}
+	c.checkPermissions(file, false)
+
+	certs, other, err := readPEMCertificates(data)
+	switch {
+	case err != nil:
+		c.add(SeverityError, file, "replace the file with the PEM certificates of the CA", "the CA file contains an invalid certificate: %v", err)
+		return nil
+	case len(certs) == 0:
+		c.add(SeverityError, file, "replace the file with the PEM certificates of the CA", "the CA file contains no PEM certificates")
+		return nil
+	case other > 0:
+		c.add(SeverityWarning, file, "remove keys and other blocks from the CA file", "the CA file contains %d PEM blocks that are not certificates", other)
+	}
+
+	for _, cert := range certs {
+		c.CABundle = append(c.CABundle, certificateInfo(cert, now))
+		name := cert.Subject.String()
+		if !cert.IsCA {
+			c.add(SeverityWarning, file, "add the certificate of the issuing CA instead",
+				"%s is not a CA certificate and only trusts itself", name)
+		}
+		c.checkValidity(file, name, cert, now, "replace it with the current CA certificate")
+		c.checkAlgorithms(file, name, cert)
+	}
+	return certs
+}
+
+// checkClientKeyPair checks the client certificate chain and its key
+func (c *TLSCheck) checkClientKeyPair(certFile, keyFile string, caCerts []*x509.Certificate, now time.Time) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		c.add(SeverityError, certFile, "check api.ssl.client_cert and the permissions of the file", "the client certificate cannot be read: %v", err)
+		return
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		c.add(SeverityError, keyFile, "check api.ssl.client_key and the permissions of the file", "the client key cannot be read: %v", err)
+		return
+	}
+	c.checkPermissions(certFile, false)
+	c.checkPermissions(keyFile, true)
+
+	chain, _, err := readPEMCertificates(certPEM)
+	switch {
+	case err != nil:
+		c.add(SeverityError, certFile, "replace the file with the PEM certificate chain", "the client certificate file contains an invalid certificate: %v", err)
+		return
+	case len(chain) == 0:
+		c.add(SeverityError, certFile, "replace the file with the PEM certificate chain", "the client certificate file contains no PEM certificates")
+		return
+	}
+	for _, cert := range chain {
+		c.ClientCertificate = append(c.ClientCertificate, certificateInfo(cert, now))
+	}
+
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		c.add(SeverityError, keyFile, "configure the private key the certificate was issued for",
+			"the client certificate and key do not match: %v", err)
+	}
+
+	leaf := chain[0]
+	if len(leaf.ExtKeyUsage) > 0 && !hasExtKeyUsage(leaf, x509.ExtKeyUsageClientAuth) && !hasExtKeyUsage(leaf, x509.ExtKeyUsageAny) {
+		c.add(SeverityError, certFile, "request a certificate with the client authentication extended key usage",
+			"the client certificate is not valid for client authentication")
+	}
+	for i, cert := range chain {
+		name := cert.Subject.String()
+		action := "renew the client certificate"
+		if i > 0 {
+			action = "replace the intermediate certificate with its current version"
+		}
+		c.checkValidity(certFile, name, cert, now, action)
+		c.checkAlgorithms(certFile, name, cert)
+	}
+	c.checkChain(certFile, chain, caCerts, now)
+}
+
+// checkChain checks that each certificate of a chain names the next one as
+// its issuer and that the last one is issued by a known CA
+func (c *TLSCheck) checkChain(file string, chain []*x509.Certificate, caCerts []*x509.Certificate, now time.Time) {
+	for i := 0; i+1 < len(chain); i++ {
+		if !bytes.Equal(chain[i].RawIssuer, chain[i+1].RawSubject) {
+			c.add(SeverityError, file, "order the file leaf first, each certificate followed by its issuer",
+				"%s is not issued by the next certificate of the file, %s", chain[i].Subject, chain[i+1].Subject)
+			return
+		}
+	}
+
+	last := chain[len(chain)-1]
+	if selfSigned(last) {
+		return
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	for _, ca := range caCerts {
+		roots.AddCert(ca)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	var unknown x509.UnknownAuthorityError
+	if errors.As(err, &unknown) {
+		c.add(SeverityWarning, file, "append the intermediate certificates to the client certificate file",
+			"the chain ends with %s, whose issuer %s is neither in the file nor a known CA; servers that do not know it reject the certificate",
+			last.Subject, last.Issuer)
+	}
+}
+
+// checkValidity reports certificates that are expired, expire soon or are
+// not yet valid
+func (c *TLSCheck) checkValidity(file, name string, cert *x509.Certificate, now time.Time, action string) {
+	switch {
+	case now.After(cert.NotAfter):
+		c.add(SeverityError, file, action, "%s expired on %s", name, cert.NotAfter.Format("2006-01-02"))
+	case now.Before(cert.NotBefore):
+		c.add(SeverityError, file, "check the system clock or wait until the certificate is valid", "%s is not valid before %s", name, cert.NotBefore.Format("2006-01-02"))
+	case cert.NotAfter.Sub(now) < certificateExpiryWarning:
+		c.add(SeverityWarning, file, action, "%s expires on %s, in %d days", name, cert.NotAfter.Format("2006-01-02"), daysLeft(cert, now))
+	}
+}
+
+// checkAlgorithms reports weak keys and signature algorithms. Signatures of
+// self-signed certificates are not checked, as they are trusted directly.
+func (c *TLSCheck) checkAlgorithms(file, name string, cert *x509.Certificate) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if bits := key.N.BitLen(); bits < 2048 {
+			c.add(SeverityError, file, "reissue the certificate with an RSA key of at least 2048 bits or an ECDSA key", "%s has a weak %d bit RSA key", name, bits)
+		}
+	case *ecdsa.PublicKey:
+		if key.Curve.Params().BitSize < 256 {
+			c.add(SeverityWarning, file, "reissue the certificate with a P-256 or larger key", "%s has a weak %s key", name, key.Curve.Params().Name)
+		}
+	}
+	if cert.PublicKeyAlgorithm == x509.DSA {
+		c.add(SeverityError, file, "reissue the certificate with an RSA or ECDSA key", "%s has a DSA key, which Go does not support for TLS", name)
+	}
+
+	if selfSigned(cert) {
+		return
+	}
+	switch cert.SignatureAlgorithm {
+	case x509.MD2WithRSA, x509.MD5WithRSA:
+		c.add(SeverityError, file, "reissue the certificate with a SHA-256 signature", "%s is signed with the broken %s algorithm", name, cert.SignatureAlgorithm)
+	case x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		c.add(SeverityWarning, file, "reissue the certificate with a SHA-256 signature", "%s is signed with the weak %s algorithm", name, cert.SignatureAlgorithm)
+	}
+}
+
+// checkPermissions reports files writable by others and keys readable by
+// others. Permissions are not checked on Windows.
+func (c *TLSCheck) checkPermissions(file string, private bool) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return
+	}
+	mode := info.Mode().Perm()
+	switch {
+	case private && mode&0o077 != 0:
+		c.add(SeverityWarning, file, fmt.Sprintf("chmod 600 %s", file), "the private key is accessible by other users (mode %04o)", mode)
+	case mode&0o022 != 0:
+		c.add(SeverityWarning, file, fmt.Sprintf("chmod go-w %s", file), "the file is writable by other users (mode %04o)", mode)
+	}
+}
+
+// certificateInfo describes a certificate
+func certificateInfo(cert *x509.Certificate, now time.Time) CertificateInfo {
+	return CertificateInfo{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		DaysLeft:           daysLeft(cert, now),
+		KeyAlgorithm:       keyAlgorithm(cert),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		IsCA:               cert.IsCA,
+	}
+}
+
+// daysLeft returns the whole days until a certificate expires, negative
+// once it expired
+func daysLeft(cert *x509.Certificate, now time.Time) int {
+	return int(cert.NotAfter.Sub(now).Hours() / 24)
+}
+
+// keyAlgorithm names the public key algorithm and size of a certificate
+func keyAlgorithm(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return strings.ToUpper(cert.PublicKeyAlgorithm.String())
+}
+
+// selfSigned reports whether a certificate is its own issuer
+func selfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject)
+}
+
+// hasExtKeyUsage reports whether a certificate has an extended key usage
+func hasExtKeyUsage(cert *x509.Certificate, usage x509.ExtKeyUsage) bool {
+	for _, u := range cert.ExtKeyUsage {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// issueCertificate creates a certificate signed by parent, self-signed if
+// parent is nil
+func issueCertificate(t *testing.T, template *x509.Certificate, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-time.Hour)
+	}
+	if template.NotAfter.IsZero() {
+		template.NotAfter = time.Now().Add(365 * 24 * time.Hour)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// writePEM writes certificates and an optional key to a PEM file
+func writePEM(t *testing.T, file string, mode os.FileMode, key crypto.Signer, certs ...*x509.Certificate) string {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	if key != nil {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})...)
+	}
+	require.NoError(t, os.WriteFile(file, data, mode))
+	require.NoError(t, os.Chmod(file, mode))
+	return file
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func findingMessages(check *TLSCheck) []string {
+	var messages []string
+	for _, f := range check.Findings {
+		messages = append(messages, f.Severity+": "+f.Message)
+	}
+	return messages
+}
+
+func TestCheckTLS(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	rootKey, intermediateKey, clientKey := newECKey(t), newECKey(t), newECKey(t)
+	root := issueCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Root"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}, rootKey, nil, nil)
+	intermediate := issueCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Intermediate"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}, intermediateKey, root, rootKey)
+	client := issueCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "Client"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, clientKey, intermediate, intermediateKey)
+
+	caFile := writePEM(t, filepath.Join(dir, "ca.pem"), 0644, nil, root)
+	keyFile := writePEM(t, filepath.Join(dir, "client-key.pem"), 0600, clientKey)
+
+	t.Run("Valid configuration", func(t *testing.T) {
+		certFile := writePEM(t, filepath.Join(dir, "chain.pem"), 0644, nil, client, intermediate)
+		check := CheckTLS(TLSOptions{CAFile: caFile, ClientCert: certFile, ClientKey: keyFile}, now)
+		assert.Equal(t, SeverityOK, check.Status, findingMessages(check))
+		require.Len(t, check.ClientCertificate, 2)
+		assert.Equal(t, "CN=Client", check.ClientCertificate[0].Subject)
+		assert.Equal(t, "ECDSA P-256", check.ClientCertificate[0].KeyAlgorithm)
+		require.Len(t, check.CABundle, 1)
+		assert.True(t, check.CABundle[0].IsCA)
+	})
+
+	t.Run("Nothing configured", func(t *testing.T) {
+		check := CheckTLS(TLSOptions{}, now)
+		assert.Equal(t, SeverityInfo, check.Status)
+		assert.Len(t, check.Findings, 1)
+	})
+
+	t.Run("Incomplete chain", func(t *testing.T) {
+		certFile := writePEM(t, filepath.Join(dir, "leaf.pem"), 0644, nil, client)
+		check := CheckTLS(TLSOptions{CAFile: caFile, ClientCert: certFile, ClientKey: keyFile}, now)
+		assert.Equal(t, SeverityWarning, check.Status)
+		require.Len(t, check.Findings, 1)
+		assert.Contains(t, check.Findings[0].Message, "whose issuer CN=Intermediate is neither in the file nor a known CA")
+		assert.Equal(t, "append the intermediate certificates to the client certificate file", check.Findings[0].Action)
+	})
+
+	t.Run("Wrong order", func(t *testing.T) {
+		certFile := writePEM(t, filepath.Join(dir, "reversed.pem"), 0644, nil, client, root)
+		check := CheckTLS(TLSOptions{ClientCert: certFile, ClientKey: keyFile}, now)
+		assert.Equal(t, SeverityError, check.Status)
+		assert.Contains(t, findingMessages(check), "error: CN=Client is not issued by the next certificate of the file, CN=Root")
+	})
+
+	t.Run("Mismatched key and open permissions", func(t *testing.T) {
+		certFile := writePEM(t, filepath.Join(dir, "chain2.pem"), 0644, nil, client, intermediate)
+		otherKey := writePEM(t, filepath.Join(dir, "other-key.pem"), 0644, newECKey(t))
+		check := CheckTLS(TLSOptions{CAFile: caFile, ClientCert: certFile, ClientKey: otherKey}, now)
+		assert.Equal(t, SeverityError, check.Status)
+		messages := findingMessages(check)
+		assert.Contains(t, messages, "warning: the private key is accessible by other users (mode 0644)")
+		assert.Contains(t, messages, "error: the client certificate and key do not match: tls: private key does not match public key")
+	})
+
+	t.Run("Expiry, usage and weak keys", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		server := issueCertificate(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "Server"},
+			NotAfter:    now.Add(10 * 24 * time.Hour),
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}, rsaKey, root, rootKey)
+		certFile := writePEM(t, filepath.Join(dir, "server.pem"), 0644, nil, server)
+		serverKey := writePEM(t, filepath.Join(dir, "server-key.pem"), 0600, rsaKey)
+
+		check := CheckTLS(TLSOptions{CAFile: caFile, ClientCert: certFile, ClientKey: serverKey, InsecureSkipVerify: true}, now)
+		assert.Equal(t, SeverityError, check.Status)
+		messages := findingMessages(check)
+		assert.Contains(t, messages, "warning: certificate verification of the Portal64 API is disabled")
+		assert.Contains(t, messages, "error: the client certificate is not valid for client authentication")
+		assert.Contains(t, messages, "error: CN=Server has a weak 1024 bit RSA key")
+		assert.Contains(t, messages, "warning: CN=Server expires on "+server.NotAfter.Format("2006-01-02")+", in 9 days")
+
+		expired := CheckTLS(TLSOptions{ClientCert: certFile, ClientKey: serverKey}, now.Add(30*24*time.Hour))
+		assert.Contains(t, findingMessages(expired), "error: CN=Server expired on "+server.NotAfter.Format("2006-01-02"))
+	})
+
+	t.Run("Unreadable and invalid files", func(t *testing.T) {
+		notPEM := filepath.Join(dir, "not.pem")
+		require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0644))
+
+		check := CheckTLS(TLSOptions{CAFile: notPEM, ClientCert: filepath.Join(dir, "missing.pem"), ClientKey: keyFile}, now)
+		messages := findingMessages(check)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "error: the CA file contains no PEM certificates", messages[0])
+		assert.Contains(t, messages[1], "error: the client certificate cannot be read")
+
+		check = CheckTLS(TLSOptions{ClientCert: notPEM}, now)
+		assert.Equal(t, []string{"error: client certificate and client key must be set together"}, findingMessages(check))
+	})
+}
@@ -0,0 +1,403 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Upstream keys of the tournament detail sections. The first key present
+// wins, the others are variants seen in different API versions.
+var (
+	participantKeys = []string{"participants", "players", "results"}
+	gameKeys        = []string{"games", "pairings"}
+	evaluationKeys  = []string{"evaluations"}
+	statisticsKeys  = []string{"statistics", "stats"}
+)
+
+// tournamentDateFields are the tournament fields holding timestamps
+var tournamentDateFields = []string{"start_date", "end_date", "finished_on", "computed_on", "recomputed_on"}
+
+// dateLayouts are the timestamp formats accepted from the API
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02", "02.01.2006"}
+
+// parseFlexibleDate parses a timestamp in any of the accepted formats
+func parseFlexibleDate(value string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DecodeTournamentDetails decodes a tournament detail payload into an
+// EnhancedTournamentResponse. Decoding is tolerant: a section that fails to
+// decode is left empty and reported in the returned errors instead of
+// failing the whole response, and single malformed entries are skipped.
+// Statistics are computed from the participants when the API omits them.
+func DecodeTournamentDetails(data json.RawMessage) (*EnhancedTournamentResponse, []error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, []error{fmt.Errorf("tournament: %w", err)}
+	}
+
+	var errs []error
+	enhanced := &EnhancedTournamentResponse{}
+
+	tournament, err := decodeTournament(fields)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("tournament: %w", err))
+	}
+	enhanced.Tournament = tournament
+
+	if key, raw := firstSection(fields, participantKeys); raw != nil {
+		participants, err := decodeParticipants(key, raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+		enhanced.Participants = participants
+	}
+
+	if key, raw := firstSection(fields, gameKeys); raw != nil {
+		games, err := decodeSection(raw, func(item json.RawMessage) (GameResult, error) {
+			return decodeGame(item, tournament.ID)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+		enhanced.Games = games
+	}
+
+	if key, raw := firstSection(fields, evaluationKeys); raw != nil {
+		evaluations, err := decodeSection(raw, func(item json.RawMessage) (Evaluation, error) {
+			return decodeEvaluation(item, tournament.ID)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+		enhanced.Evaluations = evaluations
+	}
+
+	if key, raw := firstSection(fields, statisticsKeys); raw != nil {
+		var stats TournamentStatistics
+		if err := json.Unmarshal(raw, &stats); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		} else {
+			enhanced.Statistics = &stats
+		}
+	}
+
+	if len(enhanced.Participants) > 0 {
+		enhanced.Statistics = mergeStatistics(enhanced.Statistics, ParticipantStatistics(enhanced.Participants))
+		if tournament.Participants == 0 {
+			tournament.Participants = len(enhanced.Participants)
+		}
+	}
+
+	return enhanced, errs
+}
+
+// firstSection returns the first present, non-null section of the payload.
+// Numbers are skipped since "participants" may also be a count.
+func firstSection(fields map[string]json.RawMessage, keys []string) (string, json.RawMessage) {
+	for _, key := range keys {
+		raw, ok := fields[key]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var count json.Number
+		if json.Unmarshal(raw, &count) == nil {
+			continue
+		}
+		return key, raw
+	}
+	return "", nil
+}
+
+// decodeTournament decodes the tournament itself from the top-level fields
+// or a nested "tournament" object
+func decodeTournament(fields map[string]json.RawMessage) (*TournamentResponse, error) {
+	source := fields
+	if raw, ok := fields["tournament"]; ok {
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &nested); err == nil {
+			source = nested
+		}
+	}
+
+	tournamentFields := make(map[string]json.RawMessage, len(source))
+	for k, v := range source {
+		tournamentFields[k] = v
+	}
+
+	// "participants" is a count on the tournament but a list as section
+	if raw, ok := tournamentFields["participants"]; ok && len(raw) > 0 && raw[0] == '[' {
+		delete(tournamentFields, "participants")
+	}
+
+	// Dates come in several formats, normalize them to RFC 3339
+	for _, key := range tournamentDateFields {
+		var value string
+		if err := json.Unmarshal(tournamentFields[key], &value); err != nil {
+			continue
+		}
+		if t, ok := parseFlexibleDate(value); ok {
+			tournamentFields[key], _ = json.Marshal(t)
+		} else {
+			delete(tournamentFields, key)
+		}
+	}
+
+	// The location may be an object with venue, city and state
+	var location struct {
+		Venue   string `json:"venue"`
+		City    string `json:"city"`
+		State   string `json:"state"`
+		Country string `json:"country"`
+	}
+	if raw, ok := tournamentFields["location"]; ok && len(raw) > 0 && raw[0] == '{' {
+		delete(tournamentFields, "location")
+		_ = json.Unmarshal(raw, &location)
+	}
+
+	normalized, _ := json.Marshal(tournamentFields)
+	tournament := &TournamentResponse{}
+	err := json.Unmarshal(normalized, tournament)
+
+	// Mismatched field types only skip the affected fields
+	var typeErr *json.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		tournament = &TournamentResponse{}
+	}
+
+	if tournament.Location == "" {
+		tournament.Location = location.Venue
+	}
+	if tournament.City == "" {
+		tournament.City = location.City
+	}
+	if tournament.State == "" {
+		tournament.State = location.State
+	}
+	if tournament.Country == "" {
+		tournament.Country = location.Country
+	}
+
+	return tournament, err
+}
+
+// decodeSection decodes a list section item by item, skipping items that
+// fail to decode. An error is returned if the section is not a list or if
+// any item was skipped.
+func decodeSection[T any](raw json.RawMessage, decode func(json.RawMessage) (T, error)) ([]T, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("expected a list: %w", err)
+	}
+
+	list := make([]T, 0, len(items))
+	skipped := 0
+	for _, item := range items {
+		value, err := decode(item)
+		if err != nil {
+			skipped++
+			continue
+		}
+		list = append(list, value)
+	}
+
+	if skipped > 0 {
+		return list, fmt.Errorf("skipped %d of %d malformed entries", skipped, len(items))
+	}
+	return list, nil
+}
+
+// decodeParticipants decodes participants. Result tables only carry the
+// player ID, name and club, which is mapped to the participant.
+func decodeParticipants(key string, raw json.RawMessage) ([]PlayerResponse, error) {
+	return decodeSection(raw, func(item json.RawMessage) (PlayerResponse, error) {
+		var player PlayerResponse
+		if err := json.Unmarshal(item, &player); err != nil {
+			return player, err
+		}
+
+		if key == "results" {
+			var row struct {
+				PlayerID   string `json:"player_id"`
+				PlayerName string `json:"player_name"`
+			}
+			_ = json.Unmarshal(item, &row)
+			if player.ID == "" {
+				player.ID = row.PlayerID
+			}
+			if player.Name == "" {
+				player.Name = row.PlayerName
+			}
+		}
+
+		if player.ID == "" && player.Name == "" {
+			return player, fmt.Errorf("participant without ID and name")
+		}
+		return player, nil
+	})
+}
+
+// flexibleID is an ID that the API returns as either string or number
+type flexibleID string
+
+// UnmarshalJSON implements json.Unmarshaler for flexibleID
+func (id *flexibleID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = flexibleID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = flexibleID(n.String())
+	return nil
+}
+
+// decodeGame decodes a game or pairing entry
+func decodeGame(item json.RawMessage, tournamentID string) (GameResult, error) {
+	var g struct {
+		ID           flexibleID `json:"id"`
+		TournamentID string     `json:"tournament_id"`
+		Round        int        `json:"round"`
+		WhitePlayer  string     `json:"white_player"`
+		White        string     `json:"white"`
+		WhiteID      string     `json:"white_id"`
+		BlackPlayer  string     `json:"black_player"`
+		Black        string     `json:"black"`
+		BlackID      string     `json:"black_id"`
+		Result       string     `json:"result"`
+		Date         string     `json:"date"`
+		PGN          string     `json:"pgn"`
+	}
+	if err := json.Unmarshal(item, &g); err != nil {
+		return GameResult{}, err
+	}
+
+	game := GameResult{
+		ID:           string(g.ID),
+		TournamentID: firstNonEmpty(g.TournamentID, tournamentID),
+		Round:        g.Round,
+		WhitePlayer:  firstNonEmpty(g.WhitePlayer, g.White, g.WhiteID),
+		BlackPlayer:  firstNonEmpty(g.BlackPlayer, g.Black, g.BlackID),
+		Result:       NormalizeGameResult(g.Result),
+		PGN:          g.PGN,
+	}
+	if t, ok := parseFlexibleDate(g.Date); ok {
+		game.Date = t
+	}
+
+	if game.WhitePlayer == "" || game.BlackPlayer == "" {
+		return game, fmt.Errorf("game without players")
+	}
+	return game, nil
+}
+
+// NormalizeGameResult converts result notations such as "1:0", "½-½" or
+// "0.5:0.5" to "1-0", "0-1" or "1/2-1/2". Forfeits ("+:-", "-:+") are kept
+// as "+-" and "-+". Unknown notations are returned unchanged.
+func NormalizeGameResult(result string) string {
+	r := strings.ReplaceAll(strings.TrimSpace(result), " ", "")
+	r = strings.NewReplacer(":", "-", "½", "1/2", "0.5", "1/2", "0,5", "1/2").Replace(r)
+
+	switch r {
+	case "1-0", "0-1", "1/2-1/2":
+		return r
+	case "+--", "+-":
+		return "+-"
+	case "-+", "--+":
+		return "-+"
+	default:
+		return result
+	}
+}
+
+// decodeEvaluation decodes a DWZ evaluation, accepting both the evaluation
+// field names and those of the rating history endpoint
+func decodeEvaluation(item json.RawMessage, tournamentID string) (Evaluation, error) {
+	var e struct {
+		ID           flexibleID `json:"id"`
+		PlayerID     string     `json:"player_id"`
+		TournamentID string     `json:"tournament_id"`
+		OldDWZ       int        `json:"old_dwz"`
+		DWZOld       int        `json:"dwz_old"`
+		NewDWZ       int        `json:"new_dwz"`
+		DWZNew       int        `json:"dwz_new"`
+		Performance  int        `json:"performance"`
+		Achievement  int        `json:"achievement"`
+		Games        int        `json:"games"`
+		Points       float64    `json:"points"`
+		Date         string     `json:"date"`
+		Type         string     `json:"type"`
+	}
+	if err := json.Unmarshal(item, &e); err != nil {
+		return Evaluation{}, err
+	}
+
+	evaluation := Evaluation{
+		ID:           string(e.ID),
+		PlayerID:     e.PlayerID,
+		TournamentID: firstNonEmpty(e.TournamentID, tournamentID),
+		OldDWZ:       max(e.OldDWZ, e.DWZOld),
+		NewDWZ:       max(e.NewDWZ, e.DWZNew),
+		Performance:  max(e.Performance, e.Achievement),
+		Games:        e.Games,
+		Points:       e.Points,
+		Type:         firstNonEmpty(e.Type, "tournament"),
+	}
+	evaluation.DWZChange = evaluation.NewDWZ - evaluation.OldDWZ
+	if t, ok := parseFlexibleDate(e.Date); ok {
+		evaluation.Date = t
+	}
+
+	if evaluation.PlayerID == "" {
+		return evaluation, fmt.Errorf("evaluation without player")
+	}
+	return evaluation, nil
+}
+
+// mergeStatistics fills fields missing from the API statistics with the
+// statistics computed from the participants
+func mergeStatistics(api, computed *TournamentStatistics) *TournamentStatistics {
+	if api == nil {
+		return computed
+	}
+	if api.AverageRating == 0 {
+		api.AverageRating = computed.AverageRating
+	}
+	if api.RatingRange == (RatingRange{}) {
+		api.RatingRange = computed.RatingRange
+	}
+	if len(api.NationDistribution) == 0 {
+		api.NationDistribution = computed.NationDistribution
+	}
+	if len(api.AgeDistribution) == 0 {
+		api.AgeDistribution = computed.AgeDistribution
+	}
+	if len(api.GenderDistribution) == 0 {
+		api.GenderDistribution = computed.GenderDistribution
+	}
+	if len(api.TitleDistribution) == 0 {
+		api.TitleDistribution = computed.TitleDistribution
+	}
+	return api
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTournamentDetails(t *testing.T) {
+	t.Run("Full payload", func(t *testing.T) {
+		data := []byte(`{
+			"id": "C350-C01-SMU",
+			"name": "Ulm Open 2024",
+			"start_date": "2024-03-15",
+			"finished_on": "2024-03-17T18:00:00Z",
+			"rounds": 9,
+			"participants": [
+				{"id": "C0327-297", "name": "Tran", "current_dwz": 2150, "gender": "m", "title": "FM", "nation": "GER"},
+				{"id": "C0350-123", "name": "Mueller", "current_dwz": 1950, "gender": "w", "nation": "GER"}
+			],
+			"games": [
+				{"id": 17, "round": 1, "white_player": "C0327-297", "black_player": "C0350-123", "result": "1:0", "date": "2024-03-15"}
+			],
+			"evaluations": [
+				{"id": 1, "player_id": "C0327-297", "dwz_old": 2140, "dwz_new": 2150, "achievement": 2300, "games": 9, "points": 7.5}
+			]
+		}`)
+
+		enhanced, errs := DecodeTournamentDetails(data)
+		require.Empty(t, errs)
+
+		assert.Equal(t, "C350-C01-SMU", enhanced.Tournament.ID)
+		assert.Equal(t, 9, enhanced.Tournament.Rounds)
+		assert.Equal(t, 2, enhanced.Tournament.Participants)
+		require.NotNil(t, enhanced.Tournament.StartDate)
+		assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), *enhanced.Tournament.StartDate)
+		assert.Equal(t, 2024, enhanced.Tournament.FinishedOn.Year())
+
+		require.Len(t, enhanced.Participants, 2)
+		assert.Equal(t, "female", enhanced.Participants[1].Gender)
+
+		require.Len(t, enhanced.Games, 1)
+		assert.Equal(t, "17", enhanced.Games[0].ID)
+		assert.Equal(t, "C350-C01-SMU", enhanced.Games[0].TournamentID)
+		assert.Equal(t, "1-0", enhanced.Games[0].Result)
+
+		require.Len(t, enhanced.Evaluations, 1)
+		assert.Equal(t, 10, enhanced.Evaluations[0].DWZChange)
+		assert.Equal(t, 2300, enhanced.Evaluations[0].Performance)
+		assert.Equal(t, "tournament", enhanced.Evaluations[0].Type)
+
+		require.NotNil(t, enhanced.Statistics)
+		assert.Equal(t, 2050.0, enhanced.Statistics.AverageRating)
+		assert.Equal(t, RatingRange{Min: 1950, Max: 2150}, enhanced.Statistics.RatingRange)
+		assert.Equal(t, 2, enhanced.Statistics.NationDistribution["GER"])
+	})
+
+	t.Run("Tournament only", func(t *testing.T) {
+		enhanced, errs := DecodeTournamentDetails([]byte(`{"id": "C350-C01-SMU", "name": "Ulm Open 2024", "participants": 156}`))
+		require.Empty(t, errs)
+		assert.Equal(t, 156, enhanced.Tournament.Participants)
+		assert.Nil(t, enhanced.Participants)
+		assert.Nil(t, enhanced.Games)
+		assert.Nil(t, enhanced.Statistics)
+	})
+
+	t.Run("Fixture variant with results and pairings", func(t *testing.T) {
+		data := []byte(`{
+			"id": "C350-C01-SMU",
+			"location": {"venue": "Stadthaus Ulm", "city": "Ulm", "state": "Baden-Württemberg"},
+			"participants": 156,
+			"results": [{"rank": 1, "player_id": "C0327-297", "player_name": "Minh Cuong Tran", "club": "SK Altbach 1920", "points": 7.5}],
+			"pairings": [{"round": 1, "board": 2, "white": "C0350-123", "black": "C0351-045", "result": "½-½"}]
+		}`)
+
+		enhanced, errs := DecodeTournamentDetails(data)
+		require.Empty(t, errs)
+		assert.Equal(t, "Stadthaus Ulm", enhanced.Tournament.Location)
+		assert.Equal(t, "Ulm", enhanced.Tournament.City)
+		assert.Equal(t, 156, enhanced.Tournament.Participants)
+
+		require.Len(t, enhanced.Participants, 1)
+		assert.Equal(t, "C0327-297", enhanced.Participants[0].ID)
+		assert.Equal(t, "Minh Cuong Tran", enhanced.Participants[0].Name)
+		assert.Equal(t, "SK Altbach 1920", enhanced.Participants[0].Club)
+
+		require.Len(t, enhanced.Games, 1)
+		assert.Equal(t, "C0350-123", enhanced.Games[0].WhitePlayer)
+		assert.Equal(t, "1/2-1/2", enhanced.Games[0].Result)
+	})
+
+	t.Run("Partial decoding", func(t *testing.T) {
+		data := []byte(`{
+			"id": "C350-C01-SMU",
+			"rounds": "nine",
+			"start_date": "sometime",
+			"participants": [{"id": "C0327-297"}, {"current_dwz": 1800}, "garbage"],
+			"games": {"round": 1},
+			"evaluations": [{"player_id": "C0327-297", "new_dwz": 2000, "old_dwz": 1990}],
+			"statistics": {"average_rating": 2100, "rating_range": {"min": 1500, "max": 2400}}
+		}`)
+
+		enhanced, errs := DecodeTournamentDetails(data)
+		require.NotNil(t, enhanced)
+		assert.Len(t, errs, 3) // rounds, participants, games
+
+		assert.Equal(t, "C350-C01-SMU", enhanced.Tournament.ID)
+		assert.Nil(t, enhanced.Tournament.StartDate)
+		require.Len(t, enhanced.Participants, 1)
+		assert.Nil(t, enhanced.Games)
+		require.Len(t, enhanced.Evaluations, 1)
+		assert.Equal(t, 10, enhanced.Evaluations[0].DWZChange)
+
+		// API statistics win, missing distributions are computed
+		assert.Equal(t, 2100.0, enhanced.Statistics.AverageRating)
+		assert.Equal(t, RatingRange{Min: 1500, Max: 2400}, enhanced.Statistics.RatingRange)
+		assert.NotNil(t, enhanced.Statistics.TitleDistribution)
+	})
+
+	t.Run("Not an object", func(t *testing.T) {
+		enhanced, errs := DecodeTournamentDetails([]byte(`[1, 2]`))
+		assert.Nil(t, enhanced)
+		assert.Len(t, errs, 1)
+	})
+}
+
+func TestNormalizeGameResult(t *testing.T) {
+	tests := map[string]string{
+		"1-0":     "1-0",
+		"1:0":     "1-0",
+		"0 : 1":   "0-1",
+		"½-½":     "1/2-1/2",
+		"0.5:0.5": "1/2-1/2",
+		"1/2-1/2": "1/2-1/2",
+		"+:-":     "+-",
+		"-:+":     "-+",
+		"*":       "*",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, NormalizeGameResult(input), input)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for write operations of a read-only client
+var ErrReadOnly = errors.New("write operations are disabled, the client is read-only")
+
+// SetReadOnly allows or forbids write operations. Clients are read-only
+// until writes are allowed explicitly. It must be set before the client is
+// used.
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.writable = !readOnly
+}
+
+// ReadOnly reports whether write operations are disabled
+func (c *Client) ReadOnly() bool {
+	return !c.writable
+}
+
+// Writer groups the operations that change data of the Portal64 API. It is
+// only handed out by clients that allow writes, so every write goes through
+// the read-only check of Client.Writer.
+type Writer struct {
+	c *Client
+}
+
+// Writer returns the write operations of the client, or ErrReadOnly if the
+// client is read-only
+func (c *Client) Writer() (*Writer, error) {
+	if !c.writable {
+		return nil, ErrReadOnly
+	}
+	return &Writer{c: c}, nil
+}
+
+// SubmitAddressCorrection submits a correction of an official's address for
+// review by the federation. Corrections are not applied directly, and
+// submitting one twice files two corrections.
+func (w *Writer) SubmitAddressCorrection(ctx context.Context, correction AddressCorrection) (*AddressCorrectionResult, error) {
+	if correction.AddressID == "" || correction.Field == "" {
+		return nil, fmt.Errorf("address_id and field are required")
+	}
+
+	var result AddressCorrectionResult
+	if err := w.c.DoJSONRequest(ctx, http.MethodPost, "/api/v1/addresses/corrections", correction, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Writer_ReadOnly(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1", 0, nil)
+	assert.True(t, client.ReadOnly())
+
+	writer, err := client.Writer()
+	assert.Nil(t, writer)
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
+
+func TestWriter_SubmitAddressCorrection(t *testing.T) {
+	var requests int
+	client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/addresses/corrections", r.URL.Path)
+
+		var correction AddressCorrection
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&correction))
+		assert.Equal(t, AddressCorrection{AddressID: "addr-1", Field: "email", Value: "praesident@example.org"}, correction)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success": true, "data": {"id": "corr-7", "address_id": "addr-1", "status": "pending"}}`))
+	})
+	defer server.Close()
+	client.SetReadOnly(false)
+
+	writer, err := client.Writer()
+	require.NoError(t, err)
+	result, err := writer.SubmitAddressCorrection(context.Background(), AddressCorrection{AddressID: "addr-1", Field: "email", Value: "praesident@example.org"})
+	require.NoError(t, err)
+	assert.Equal(t, &AddressCorrectionResult{ID: "corr-7", AddressID: "addr-1", Status: "pending"}, result)
+
+	_, err = writer.SubmitAddressCorrection(context.Background(), AddressCorrection{Field: "email"})
+	assert.EqualError(t, err, "address_id and field are required")
+	assert.Equal(t, 1, requests)
+}
+
+func TestWriter_SubmitAddressCorrection_NotRetried(t *testing.T) {
+	var requests int
+	client, server := newRequestTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+	client.SetReadOnly(false)
+
+	writer, err := client.Writer()
+	require.NoError(t, err)
+	_, err = writer.SubmitAddressCorrection(context.Background(), AddressCorrection{AddressID: "addr-1", Field: "city", Value: "Ulm"})
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,231 @@
+// Package chart renders line charts and histograms as SVG and PNG images
+// without external dependencies, e.g. rating histories and distributions for
+// chat clients that display images but no data
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+)
+
+// Size limits of charts in pixels
+const (
+	MinWidth  = 200
+	MaxWidth  = 2000
+	MinHeight = 150
+	MaxHeight = 1500
+)
+
+// Margins of the plot area, leaving room for the title and tick labels
+const (
+	marginLeft   = 56
+	marginRight  = 20
+	marginTop    = 36
+	marginBottom = 32
+)
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	grid       = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	axis       = color.RGBA{0x55, 0x55, 0x55, 0xff}
+	series     = color.RGBA{0x1f, 0x77, 0xb4, 0xff}
+)
+
+// tick is a grid line at a pixel offset with its label
+type tick struct {
+	pos   float64
+	label string
+}
+
+// frame is the pixel geometry of a chart: its size, the plot area and the
+// grid lines of both axes. Line charts and histograms draw into a frame.
+type frame struct {
+	width, height int
+	left, right   float64
+	top, bottom   float64
+	xTicks        []tick
+	yTicks        []tick
+	// xGrid draws vertical grid lines at the x ticks
+	xGrid bool
+}
+
+// newFrame checks the size of a chart and returns its frame without ticks
+func newFrame(width, height int) (*frame, error) {
+	if width < MinWidth || width > MaxWidth || height < MinHeight || height > MaxHeight {
+		return nil, fmt.Errorf("chart size %dx%d out of range %dx%d to %dx%d", width, height, MinWidth, MinHeight, MaxWidth, MaxHeight)
+	}
+	return &frame{
+		width:  width,
+		height: height,
+		left:   marginLeft,
+		right:  float64(width - marginRight),
+		top:    marginTop,
+		bottom: float64(height - marginBottom),
+	}, nil
+}
+
+// valueAxis sets the y ticks for values from low to high rounded to a nice
+// step and returns the function mapping values to pixel rows
+func (f *frame) valueAxis(low, high, minStep float64) func(float64) float64 {
+	step := math.Max(minStep, niceStep(high-low))
+	low, high = math.Floor(low/step)*step, math.Ceil(high/step)*step
+	if low == high {
+		low, high = low-step, high+step
+	}
+	y := func(v float64) float64 {
+		return f.bottom - (f.bottom-f.top)*(v-low)/(high-low)
+	}
+	for v := low; v <= high+step/2; v += step {
+		f.yTicks = append(f.yTicks, tick{y(v), strconv.FormatFloat(v, 'f', -1, 64)})
+	}
+	return y
+}
+
+// niceStep returns a grid step of 1, 2 or 5 times a power of ten dividing a
+// range into about five intervals
+func niceStep(span float64) float64 {
+	if span <= 0 {
+		return 10
+	}
+	raw := span / 5
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// svg starts an SVG document with the title, grid, tick labels and axes of
+// the frame. The caller adds the data and closes the document.
+func (f *frame) svg(b *bytes.Buffer, title string) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", f.width, f.height, f.width, f.height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(background))
+	if title != "" {
+		fmt.Fprintf(b, `<text x="%d" y="22" text-anchor="middle" font-size="14">`, f.width/2)
+		_ = xml.EscapeText(b, []byte(title))
+		b.WriteString("</text>\n")
+	}
+	for _, t := range f.yTicks {
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n", f.left, t.pos, f.right, t.pos, hex(grid))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n", f.left-6, t.pos, t.label)
+	}
+	for _, t := range f.xTicks {
+		if f.xGrid {
+			fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n", t.pos, f.top, t.pos, f.bottom, hex(grid))
+		}
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", t.pos, f.bottom+18, t.label)
+	}
+	fmt.Fprintf(b, `<polyline points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="none" stroke="%s"/>`+"\n", f.left, f.top, f.left, f.bottom, f.right, f.bottom, hex(axis))
+}
+
+// image returns an image with the grid and tick labels of the frame. Tick
+// labels are drawn with a built-in digit font; there is no title, as there
+// is no font for text. The caller draws the data and then the axes.
+func (f *frame) image() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, f.width, f.height))
+	fillRect(img, img.Bounds(), background)
+	for _, t := range f.yTicks {
+		y := int(math.Round(t.pos))
+		fillRect(img, image.Rect(int(f.left), y, int(f.right), y+1), grid)
+		drawDigits(img, int(f.left)-6-digitsWidth(t.label), y-digitHeight/2, t.label, axis)
+	}
+	for _, t := range f.xTicks {
+		x := int(math.Round(t.pos))
+		if f.xGrid {
+			fillRect(img, image.Rect(x, int(f.top), x+1, int(f.bottom)), grid)
+		}
+		drawDigits(img, x-digitsWidth(t.label)/2, int(f.bottom)+8, t.label, axis)
+	}
+	return img
+}
+
+// encodePNG draws the axes of the frame over the data and encodes the image
+func (f *frame) encodePNG(img *image.RGBA) ([]byte, error) {
+	fillRect(img, image.Rect(int(f.left), int(f.top), int(f.left)+1, int(f.bottom)+1), axis)
+	fillRect(img, image.Rect(int(f.left), int(f.bottom), int(f.right)+1, int(f.bottom)+1), axis)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawLine draws a line two pixels wide between two points
+func drawLine(img *image.RGBA, from, to [2]float64, c color.RGBA) {
+	steps := int(math.Ceil(math.Max(math.Abs(to[0]-from[0]), math.Abs(to[1]-from[1]))))
+	for i := 0; i <= steps; i++ {
+		f := 0.0
+		if steps > 0 {
+			f = float64(i) / float64(steps)
+		}
+		x := int(math.Round(from[0] + (to[0]-from[0])*f))
+		y := int(math.Round(from[1] + (to[1]-from[1])*f))
+		fillRect(img, image.Rect(x, y, x+2, y+2), c)
+	}
+}
+
+// digitFont has glyphs of 3x5 cells for digits and the minus sign, a row
+// per byte with the leftmost cell in bit 2. Cells are drawn as squares of
+// digitScale pixels.
+var digitFont = map[rune][5]byte{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'-': {0, 0, 7, 0, 0},
+	'.': {0, 0, 0, 0, 2},
+}
+
+const (
+	digitScale   = 2
+	digitAdvance = 4 * digitScale
+	digitHeight  = 5 * digitScale
+)
+
+func digitsWidth(s string) int {
+	return len(s)*digitAdvance - digitScale
+}
+
+// drawDigits draws a label with its top left corner at x, y. Characters
+// without a glyph are left blank.
+func drawDigits(img *image.RGBA, x, y int, s string, c color.RGBA) {
+	for i, r := range s {
+		glyph := digitFont[r]
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) != 0 {
+					px, py := x+i*digitAdvance+col*digitScale, y+row*digitScale
+					fillRect(img, image.Rect(px, py, px+digitScale, py+digitScale), c)
+				}
+			}
+		}
+	}
+}
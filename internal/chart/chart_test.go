@@ -0,0 +1,14 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNiceStep(t *testing.T) {
+	assert.Equal(t, 20.0, niceStep(90))
+	assert.Equal(t, 50.0, niceStep(230))
+	assert.Equal(t, 100.0, niceStep(480))
+	assert.Equal(t, 10.0, niceStep(0))
+}
@@ -0,0 +1,94 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+)
+
+// Bar is a bucket of a histogram. Labels are drawn below the bars; PNG
+// images only show digits and the minus sign.
+type Bar struct {
+	Label string
+	Count int
+}
+
+// Histogram is a chart of counts per bucket, drawn as bars in order
+type Histogram struct {
+	Title  string
+	Width  int
+	Height int
+	Bars   []Bar
+}
+
+// histogramLayout is a frame with the bars as rectangles of its plot area
+type histogramLayout struct {
+	*frame
+	bars []image.Rectangle
+}
+
+// layout checks the histogram and places its bars into the plot area
+func (h *Histogram) layout() (*histogramLayout, error) {
+	f, err := newFrame(h.Width, h.Height)
+	if err != nil {
+		return nil, err
+	}
+	if len(h.Bars) == 0 {
+		return nil, errors.New("histogram has no bars")
+	}
+	l := &histogramLayout{frame: f}
+
+	highest, widest := 0, 0
+	for _, bar := range h.Bars {
+		highest, widest = max(highest, bar.Count), max(widest, digitsWidth(bar.Label))
+	}
+	y := l.valueAxis(0, float64(highest), 1)
+
+	// Label every bar, or every 2nd, 3rd, ... bar keeping labels apart
+	slot := (l.right - l.left) / float64(len(h.Bars))
+	every := max(1, int(math.Ceil(float64(widest+12)/slot)))
+	for i, bar := range h.Bars {
+		x0 := l.left + slot*float64(i)
+		l.bars = append(l.bars, image.Rect(
+			int(math.Round(x0+slot*0.1)), int(math.Round(y(float64(bar.Count)))),
+			int(math.Round(x0+slot*0.9)), int(math.Round(l.bottom)),
+		))
+		if i%every == 0 {
+			l.xTicks = append(l.xTicks, tick{x0 + slot/2, bar.Label})
+		}
+	}
+	return l, nil
+}
+
+// SVG renders the histogram as an SVG document
+func (h *Histogram) SVG() ([]byte, error) {
+	l, err := h.layout()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	l.svg(&b, h.Title)
+	for i, bar := range l.bars {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%d</title></rect>`+"\n",
+			bar.Min.X, bar.Min.Y, bar.Dx(), bar.Dy(), hex(series), h.Bars[i].Count)
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes(), nil
+}
+
+// PNG renders the histogram as a PNG image without its title
+func (h *Histogram) PNG() ([]byte, error) {
+	l, err := h.layout()
+	if err != nil {
+		return nil, err
+	}
+
+	img := l.image()
+	for _, bar := range l.bars {
+		fillRect(img, bar, series)
+	}
+	return l.encodePNG(img)
+}
@@ -0,0 +1,57 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHistogram() *Histogram {
+	h := &Histogram{Title: "DWZ C0327", Width: 400, Height: 200}
+	for i, count := range []int{2, 5, 11, 7, 0, 1, 3, 4, 6, 2, 1, 1} {
+		h.Bars = append(h.Bars, Bar{Label: strconv.Itoa(1000 + 100*i), Count: count})
+	}
+	return h
+}
+
+func TestHistogramLayout(t *testing.T) {
+	l, err := testHistogram().layout()
+	require.NoError(t, err)
+
+	require.Len(t, l.bars, 12)
+	assert.Equal(t, "0", l.yTicks[0].label, "counts start at zero")
+	assert.Equal(t, "15", l.yTicks[len(l.yTicks)-1].label)
+	assert.Equal(t, 0, l.bars[4].Dy(), "empty buckets have no height")
+	assert.Greater(t, l.bars[2].Dy(), l.bars[3].Dy())
+	assert.Less(t, l.bars[0].Max.X, l.bars[1].Min.X, "bars are apart")
+
+	var labels []string
+	for _, tick := range l.xTicks {
+		labels = append(labels, tick.label)
+	}
+	assert.Equal(t, []string{"1000", "1200", "1400", "1600", "1800", "2000"}, labels, "every other bar is labeled at 27 pixels per bar")
+
+	_, err = (&Histogram{Width: 400, Height: 200}).layout()
+	assert.Error(t, err)
+}
+
+func TestHistogramImages(t *testing.T) {
+	svg, err := testHistogram().SVG()
+	require.NoError(t, err)
+	assert.Equal(t, 12, strings.Count(string(svg), "<title>"))
+
+	data, err := testHistogram().PNG()
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	l, _ := testHistogram().layout()
+	center := l.bars[2].Min.Add(l.bars[2].Size().Div(2))
+	r, g, b, _ := img.At(center.X, center.Y).RGBA()
+	assert.Equal(t, [3]uint32{0x1f1f, 0x7777, 0xb4b4}, [3]uint32{r, g, b}, "bars are filled")
+}
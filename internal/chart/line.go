@@ -0,0 +1,125 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Point is a value of a series at a time
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// LineChart is a chart of a single series. Points are sorted by time when
+// rendered.
+type LineChart struct {
+	Title  string
+	Width  int
+	Height int
+	Points []Point
+}
+
+// lineLayout is a frame with the points scaled into its plot area
+type lineLayout struct {
+	*frame
+	points [][2]float64
+}
+
+// layout checks the chart and scales its points into the plot area
+func (c *LineChart) layout() (*lineLayout, error) {
+	f, err := newFrame(c.Width, c.Height)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Points) == 0 {
+		return nil, errors.New("chart has no points")
+	}
+	points := append([]Point(nil), c.Points...)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	l := &lineLayout{frame: f}
+	l.xGrid = true
+
+	from, to := points[0].Time, points[len(points)-1].Time
+	if !to.After(from) {
+		from, to = from.AddDate(0, -6, 0), to.AddDate(0, 6, 0)
+	}
+	low, high := points[0].Value, points[0].Value
+	for _, p := range points {
+		low, high = math.Min(low, p.Value), math.Max(high, p.Value)
+	}
+
+	x := func(t time.Time) float64 {
+		return l.left + (l.right-l.left)*float64(t.Sub(from))/float64(to.Sub(from))
+	}
+	y := l.valueAxis(low, high, 0)
+	for _, p := range points {
+		l.points = append(l.points, [2]float64{x(p.Time), y(p.Value)})
+	}
+	// Label every year, or every 2nd, 5th, ... year keeping labels 60
+	// pixels apart
+	labels := max(1, int((l.right-l.left)/60))
+	every := 1
+	for _, every = range []int{1, 2, 5, 10, 20, 50} {
+		if (to.Year()-from.Year())/every <= labels {
+			break
+		}
+	}
+	for year := from.Year() + 1; year <= to.Year(); year++ {
+		if year%every == 0 {
+			l.xTicks = append(l.xTicks, tick{x(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)), strconv.Itoa(year)})
+		}
+	}
+	if len(l.xTicks) == 0 {
+		l.xTicks = append(l.xTicks, tick{x(from), strconv.Itoa(from.Year())})
+	}
+	return l, nil
+}
+
+// SVG renders the chart as an SVG document
+func (c *LineChart) SVG() ([]byte, error) {
+	l, err := c.layout()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	l.svg(&b, c.Title)
+	b.WriteString(`<polyline points="`)
+	for i, p := range l.points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%.1f,%.1f", p[0], p[1])
+	}
+	fmt.Fprintf(&b, `" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`+"\n", hex(series))
+	for _, p := range l.points {
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`+"\n", p[0], p[1], hex(series))
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes(), nil
+}
+
+// PNG renders the chart as a PNG image without its title
+func (c *LineChart) PNG() ([]byte, error) {
+	l, err := c.layout()
+	if err != nil {
+		return nil, err
+	}
+
+	img := l.image()
+	for i := 1; i < len(l.points); i++ {
+		drawLine(img, l.points[i-1], l.points[i], series)
+	}
+	for _, p := range l.points {
+		x, y := int(math.Round(p[0])), int(math.Round(p[1]))
+		fillRect(img, image.Rect(x-2, y-2, x+3, y+3), series)
+	}
+	return l.encodePNG(img)
+}
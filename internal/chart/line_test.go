@@ -0,0 +1,90 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testChart() *LineChart {
+	date := func(year int, month time.Month) time.Time { return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC) }
+	return &LineChart{
+		Title:  "DWZ <C0327-297>",
+		Width:  600,
+		Height: 300,
+		Points: []Point{
+			{date(2021, 9), 1712},
+			{date(2019, 3), 1580},
+			{date(2020, 1), 1633},
+			{date(2023, 5), 1695},
+		},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	l, err := testChart().layout()
+	require.NoError(t, err)
+
+	var labels []string
+	for _, tick := range l.yTicks {
+		labels = append(labels, tick.label)
+	}
+	assert.Equal(t, []string{"1550", "1600", "1650", "1700", "1750"}, labels)
+	labels = nil
+	for _, tick := range l.xTicks {
+		labels = append(labels, tick.label)
+	}
+	assert.Equal(t, []string{"2020", "2021", "2022", "2023"}, labels)
+
+	require.Len(t, l.points, 4)
+	assert.Equal(t, l.left, l.points[0][0], "points are sorted by time")
+	assert.Equal(t, l.right, l.points[3][0])
+	assert.Less(t, l.points[2][1], l.points[3][1], "higher values are drawn further up")
+
+	single := &LineChart{Width: 400, Height: 200, Points: []Point{{time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 1500}}}
+	l, err = single.layout()
+	require.NoError(t, err)
+	assert.Equal(t, (l.left+l.right)/2, l.points[0][0], "a single point is centered")
+	assert.Greater(t, len(l.yTicks), 1)
+
+	_, err = (&LineChart{Width: 400, Height: 200}).layout()
+	assert.Error(t, err)
+	_, err = (&LineChart{Width: 50, Height: 200, Points: single.Points}).layout()
+	assert.Error(t, err)
+}
+
+func TestSVG(t *testing.T) {
+	data, err := testChart().SVG()
+	require.NoError(t, err)
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			require.Equal(t, "EOF", err.Error(), "the SVG is well-formed")
+			break
+		}
+	}
+	assert.Contains(t, string(data), "DWZ &lt;C0327-297&gt;")
+	assert.Equal(t, 4, strings.Count(string(data), "<circle"))
+}
+
+func TestPNG(t *testing.T) {
+	data, err := testChart().PNG()
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 600, img.Bounds().Dx())
+	assert.Equal(t, 300, img.Bounds().Dy())
+
+	l, _ := testChart().layout()
+	r, g, b, _ := img.At(int(l.points[1][0]), int(l.points[1][1])).RGBA()
+	assert.Equal(t, [3]uint32{0x1f1f, 0x7777, 0xb4b4}, [3]uint32{r, g, b}, "points are drawn in the line color")
+}
@@ -1,30 +1,567 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/features"
+	"github.com/svw-info/portal64gomcp/internal/render"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
 )
 
 // Config holds all configuration for the MCP server
 type Config struct {
-	API    APIConfig    `mapstructure:"api"`
-	MCP    MCPConfig    `mapstructure:"mcp"`
-	Logger LoggerConfig `mapstructure:"logging"`
+	API      APIConfig       `mapstructure:"api"`
+	MCP      MCPConfig       `mapstructure:"mcp"`
+	Logger   LoggerConfig    `mapstructure:"logging"`
+	Geocoder GeocoderConfig  `mapstructure:"geocoder"`
+	Features map[string]bool `mapstructure:"features"` // Initial feature flag states
+	Store    StoreConfig     `mapstructure:"store"`
+	// Distributions configures the rating distributions used for percentiles
+	Distributions DistributionsConfig `mapstructure:"distributions"`
+	Rosters       RostersConfig       `mapstructure:"rosters"`
+	ReferenceData ReferenceDataConfig `mapstructure:"reference_data"`
+	ClubAliases   ClubAliasesConfig   `mapstructure:"club_aliases"`
+	Memory        MemoryConfig        `mapstructure:"memory"`
+	Export        ExportConfig        `mapstructure:"export"`
+	Links         LinksConfig         `mapstructure:"links"`
+	Render        RenderConfig        `mapstructure:"render"`
+	Telemetry     TelemetryConfig     `mapstructure:"telemetry"`
+	Mail          MailConfig          `mapstructure:"mail"`
+	// File is the config file that was read, empty if the configuration
+	// comes from defaults and environment variables only
+	File string `mapstructure:"-"`
+	// sources records where each config key was set, see EffectiveConfig
+	sources map[string]string
 }
 
 // APIConfig holds Portal64 API configuration
 type APIConfig struct {
-	BaseURL string        `mapstructure:"base_url"`
-	Timeout time.Duration `mapstructure:"timeout"`
+	BaseURL      string            `mapstructure:"base_url"`
+	FallbackURLs []string          `mapstructure:"fallback_urls"` // Tried in order when the base URL is unhealthy
+	Timeout      time.Duration     `mapstructure:"timeout"`
+	SSL          APISSLConfig      `mapstructure:"ssl"`
+	Failover     APIFailoverConfig `mapstructure:"failover"`
+	// ReadOnly disables tools and client operations that change data of
+	// the Portal64 API
+	ReadOnly bool `mapstructure:"read_only"`
+	// Anomalies records responses deviating from the expected schema
+	Anomalies APIAnomaliesConfig `mapstructure:"anomalies"`
+	// Connection tunes the connection pool of the upstream client
+	Connection APIConnectionConfig `mapstructure:"connection"`
+	// SchemeDetection probes the scheme and port of the base URL at startup
+	SchemeDetection APISchemeDetectionConfig `mapstructure:"scheme_detection"`
+	// Auth holds the credentials sent with every upstream request
+	Auth APIAuthConfig `mapstructure:"auth"`
+	// Signing signs upstream requests for deployments that require it
+	Signing APIRequestSigningConfig `mapstructure:"signing"`
+	// Cache holds upstream responses in memory while the caching feature
+	// flag is enabled
+	Cache APICacheConfig `mapstructure:"cache"`
+	// Maintenance serves data from the cache only during maintenance of
+	// the DWZ system
+	Maintenance APIMaintenanceConfig `mapstructure:"maintenance"`
+	// Debug restricts the requests of the debug_upstream_request tool
+	Debug APIDebugConfig `mapstructure:"debug"`
+	// Profiles are additional named Portal64 instances, such as a test
+	// federation, selectable per request. They share the SSL, auth,
+	// signing, cache, maintenance, failover, connection and read-only
+	// settings.
+	Profiles map[string]APIProfileConfig `mapstructure:"profiles"`
+}
+
+// APIProfileConfig holds the upstream of a named API profile
+type APIProfileConfig struct {
+	BaseURL      string        `mapstructure:"base_url"`
+	FallbackURLs []string      `mapstructure:"fallback_urls"`
+	Timeout      time.Duration `mapstructure:"timeout"` // Defaults to api.timeout
+}
+
+// Profile returns the API configuration of a named profile
+func (c APIConfig) Profile(name string) (APIConfig, bool) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return APIConfig{}, false
+	}
+	profileConfig := APIConfig{
+		BaseURL:      profile.BaseURL,
+		FallbackURLs: profile.FallbackURLs,
+		Timeout:      profile.Timeout,
+		SSL:          c.SSL,
+		Auth:         c.Auth,
+		Signing:      c.Signing,
+		Cache:        c.Cache,
+		Maintenance:  c.Maintenance,
+		Failover:     c.Failover,
+		ReadOnly:     c.ReadOnly,
+		Connection:   c.Connection,
+		// Detection probes the base URL of the profile
+		SchemeDetection: c.SchemeDetection,
+	}
+	if profileConfig.Timeout == 0 {
+		profileConfig.Timeout = c.Timeout
+	}
+	return profileConfig, true
+}
+
+// DefaultProfile names the upstream configured by api.base_url
+const DefaultProfile = "default"
+
+// profileNamePattern matches valid API profile names
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// schemeCandidatePattern matches scheme detection candidates such as
+// "https:8443"
+var schemeCandidatePattern = regexp.MustCompile(`^https?:([1-9][0-9]{0,3}|[1-5][0-9]{4}|6[0-4][0-9]{3}|65[0-4][0-9]{2}|655[0-2][0-9]|6553[0-5])$`)
+
+// APIFailoverConfig holds the circuit breaker settings used for failover
+type APIFailoverConfig struct {
+	FailureThreshold int           `mapstructure:"failure_threshold"` // Consecutive failures before an upstream is skipped
+	Cooldown         time.Duration `mapstructure:"cooldown"`          // Time an unhealthy upstream is skipped
+}
+
+// APIAnomaliesConfig holds the detection of upstream responses that
+// deviate from the expected schema, such as unknown or missing fields
+type APIAnomaliesConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	LogFile string `mapstructure:"log_file"` // Appends each new anomaly as a JSON line
+}
+
+// APIDebugConfig holds the upstream paths debug_upstream_request may
+// request. Entries are exact paths or prefixes ending in *.
+type APIDebugConfig struct {
+	AllowedPaths []string `mapstructure:"allowed_paths"`
+	MaxBodyBytes int      `mapstructure:"max_body_bytes"` // Longest body returned
+}
+
+// Allows reports whether debug requests may request an API path
+func (c APIDebugConfig) Allows(apiPath string) bool {
+	for _, allowed := range c.AllowedPaths {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); (ok && strings.HasPrefix(apiPath, prefix)) || apiPath == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// APIConnectionConfig holds the keep-alive and protocol settings of
+// connections to the Portal64 API
+type APIConnectionConfig struct {
+	HTTP2               bool          `mapstructure:"http2"`                   // Negotiate HTTP/2 with TLS upstreams
+	KeepAlive           bool          `mapstructure:"keep_alive"`              // Reuse connections across requests
+	KeepAliveInterval   time.Duration `mapstructure:"keep_alive_interval"`     // TCP keep-alive probe interval
+	IdleTimeout         time.Duration `mapstructure:"idle_timeout"`            // Idle pooled connections are closed after this
+	MaxIdleConnsPerHost int           `mapstructure:"max_idle_conns_per_host"` // Idle connections kept per upstream host
+}
+
+// APISchemeDetectionConfig holds the startup probe that detects a base URL
+// with the wrong scheme or port, such as https on a plain HTTP port
+type APISchemeDetectionConfig struct {
+	Mode       string        `mapstructure:"mode"`       // "correct" uses the detected base URL, "fail" aborts the start, "off"
+	Candidates []string      `mapstructure:"candidates"` // Scheme and port pairs also tried on the host, e.g. "https:8443"
+	Timeout    time.Duration `mapstructure:"timeout"`    // Per probe
+}
+
+// APIAuthConfig holds the authentication of requests to the Portal64 API
+type APIAuthConfig struct {
+	Type     string          `mapstructure:"type"`   // "none", "api_key", "basic" or "oauth2"
+	Header   string          `mapstructure:"header"` // Header carrying the API key
+	APIKey   string          `mapstructure:"api_key" secret:"true"`
+	Username string          `mapstructure:"username"` // Basic authentication
+	Password string          `mapstructure:"password" secret:"true"`
+	OAuth2   APIOAuth2Config `mapstructure:"oauth2"`
+}
+
+// APIOAuth2Config holds the OAuth2 client credentials grant used to
+// acquire access tokens for the Portal64 API
+type APIOAuth2Config struct {
+	TokenURL     string   `mapstructure:"token_url"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret" secret:"true"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// APIRequestSigningConfig holds the HMAC key that signs requests to the
+// Portal64 API. Key rotation signs with the previous key as well until the
+// upstream has switched.
+type APIRequestSigningConfig struct {
+	Algorithm     string        `mapstructure:"algorithm"`                  // "hmac-sha256", empty to disable
+	Key           string        `mapstructure:"key" secret:"true"`          // HMAC secret
+	KeyID         string        `mapstructure:"key_id"`                     // Sent with the signature
+	PreviousKey   string        `mapstructure:"previous_key" secret:"true"` // Signs as well during a key rotation
+	PreviousKeyID string        `mapstructure:"previous_key_id"`
+	Header        string        `mapstructure:"header"`     // Header carrying the signatures
+	ClockSkew     time.Duration `mapstructure:"clock_skew"` // Tolerated difference to the upstream clock
+}
+
+// APICacheConfig holds the cache of upstream responses
+type APICacheConfig struct {
+	MaxEntries int                    `mapstructure:"max_entries"` // Least recently used responses are evicted beyond this
+	Policies   APICachePoliciesConfig `mapstructure:"policies"`
+	// NotFoundTTL caches 404s, such as lookups of unknown player IDs, for
+	// this long; 0 disables negative caching
+	NotFoundTTL time.Duration `mapstructure:"not_found_ttl"`
+}
+
+// APIMaintenanceConfig holds the known maintenance windows of the Portal64
+// API and the detection of unscheduled maintenance
+type APIMaintenanceConfig struct {
+	// Windows are recurring maintenance times in Europe/Berlin time, such
+	// as "Sun 02:00-04:00" or "daily 03:00-03:15"
+	Windows []string `mapstructure:"windows"`
+	// Pattern detects maintenance from the body of 503 responses; empty
+	// disables detection
+	Pattern string        `mapstructure:"pattern"`
+	Hold    time.Duration `mapstructure:"hold"` // How long a detected maintenance lasts, unless Retry-After is longer
+}
+
+// APICachePoliciesConfig holds the cache policy of each entity type
+type APICachePoliciesConfig struct {
+	Regions     APICachePolicyConfig `mapstructure:"regions"`
+	Addresses   APICachePolicyConfig `mapstructure:"addresses"`
+	Players     APICachePolicyConfig `mapstructure:"players"`
+	Clubs       APICachePolicyConfig `mapstructure:"clubs"`
+	Tournaments APICachePolicyConfig `mapstructure:"tournaments"`
+}
+
+// APICachePolicyConfig holds how long responses of an entity type are cached
+type APICachePolicyConfig struct {
+	TTL   time.Duration `mapstructure:"ttl"`   // Served from the cache, 0 disables caching of the type
+	Stale time.Duration `mapstructure:"stale"` // Served beyond the TTL while refreshed in the background
+}
+
+// APISSLConfig holds TLS configuration for connections to the Portal64 API
+type APISSLConfig struct {
+	CAFile             string `mapstructure:"ca_file"`     // Additional root CAs (PEM)
+	ClientCert         string `mapstructure:"client_cert"` // Client certificate for mutual TLS (PEM)
+	ClientKey          string `mapstructure:"client_key"`  // Client private key (PEM)
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
 }
 
 // MCPConfig holds MCP server configuration
 type MCPConfig struct {
-	Port     int    `mapstructure:"port"`
-	Mode     string `mapstructure:"mode"`     // "stdio", "http", or "both"
-	HTTPPort int    `mapstructure:"http_port"`
+	Port     int           `mapstructure:"port"`
+	Mode     string        `mapstructure:"mode"` // "stdio", "http", or "both"
+	HTTPPort int           `mapstructure:"http_port"`
+	Sessions SessionConfig `mapstructure:"sessions"`
+	HTTP     HTTPConfig    `mapstructure:"http"` // HTTP bridge server tuning
+	// OutputFormat selects "envelope" (versioned tool results) or "legacy"
+	OutputFormat string      `mapstructure:"output_format"`
+	Tools        ToolsConfig `mapstructure:"tools"`
+	// LoadShedding rejects tool calls early while the server is overloaded
+	LoadShedding LoadSheddingConfig `mapstructure:"load_shedding"`
+	// Scheduling bounds concurrent tool calls and assigns tools to priority
+	// classes
+	Scheduling SchedulingConfig `mapstructure:"scheduling"`
+	// Aggregates holds the partial failure handling of tools that combine
+	// many upstream requests
+	Aggregates AggregatesConfig `mapstructure:"aggregates"`
+}
+
+// AggregatesConfig holds the partial failure handling of aggregate tools.
+// Up to MaxFailureRatio of the sub-requests, such as the rating histories
+// of club members, may fail; the result then lists them as failed_items.
+// Beyond it the tool fails.
+type AggregatesConfig struct {
+	MaxFailureRatio float64 `mapstructure:"max_failure_ratio"` // 0 fails on any failed sub-request
+}
+
+// SchedulingConfig holds the concurrency limit of tool calls and the batch
+// priority class. Beyond MaxConcurrent running calls, further calls wait in
+// a queue where interactive calls go before batch calls. Load shedding
+// also rejects batch calls first. Administrative tools are never queued.
+type SchedulingConfig struct {
+	MaxConcurrent int           `mapstructure:"max_concurrent"` // 0 = unlimited
+	QueueTimeout  time.Duration `mapstructure:"queue_timeout"`  // Longest wait for a slot
+	// BatchTools are added to the built-in batch tools, InteractiveTools
+	// removed from them
+	BatchTools       []string `mapstructure:"batch_tools"`
+	InteractiveTools []string `mapstructure:"interactive_tools"`
+}
+
+// LoadSheddingConfig holds the overload thresholds of load shedding. When
+// the tool calls in flight or the average upstream latency exceed their
+// threshold, low priority tool calls are rejected; at twice the threshold
+// all but administrative tool calls are. Zero disables a threshold.
+type LoadSheddingConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	MaxInFlight int           `mapstructure:"max_in_flight"`
+	MaxLatency  time.Duration `mapstructure:"max_latency"`
+	RetryAfter  time.Duration `mapstructure:"retry_after"` // Suggested to rejected clients
+}
+
+// ToolsConfig restricts the tools exposed per transport
+type ToolsConfig struct {
+	Stdio TransportToolsConfig `mapstructure:"stdio"`
+	HTTP  TransportToolsConfig `mapstructure:"http"` // Including the REST endpoints of the HTTP bridge
+}
+
+// TransportToolsConfig lists the tools hidden on a transport. Entries are
+// tool names or "@admin" for all administrative tools.
+type TransportToolsConfig struct {
+	Hidden []string `mapstructure:"hidden"`
+}
+
+// HTTPConfig holds tuning of the HTTP bridge server. Zero timeouts disable
+// the respective limit.
+type HTTPConfig struct {
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`        // Reading the whole request
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"` // Reading the request headers
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`       // Writing the response, must cover the slowest tool
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`        // Keep-alive connections without requests
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+	MaxConnections    int           `mapstructure:"max_connections"` // Simultaneous connections, 0 for no limit
+	ReusePort         bool          `mapstructure:"reuse_port"`      // Set SO_REUSEPORT so that another process can listen on the port
+	DrainTimeout      time.Duration `mapstructure:"drain_timeout"`   // Waiting for in-flight requests on shutdown, 0 to wait without limit
+	Debug             DebugConfig   `mapstructure:"debug"`
+	Admin             AdminConfig   `mapstructure:"admin"`
+	Signing           SigningConfig `mapstructure:"signing"`
+	UI                UIConfig      `mapstructure:"ui"`
+}
+
+// UIConfig holds configuration of the operator dashboard served at /ui
+type UIConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// Response signing algorithms of the HTTP bridge
+const (
+	SigningHMACSHA256 = "hmac-sha256"
+	SigningEd25519    = "ed25519"
+)
+
+// SigningConfig holds the key used to sign the responses of the HTTP bridge,
+// so that consumers relaying the data can verify it
+type SigningConfig struct {
+	Algorithm string `mapstructure:"algorithm"`         // "hmac-sha256" or "ed25519", empty to disable
+	Key       string `mapstructure:"key" secret:"true"` // HMAC secret, or base64 Ed25519 seed or private key
+	KeyID     string `mapstructure:"key_id"`            // Sent with every signature to allow key rotation
+}
+
+// DebugConfig holds configuration of the /debug/pprof and /debug/vars
+// endpoints of the HTTP bridge
+type DebugConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Token   string `mapstructure:"token" secret:"true"` // Bearer token required by the endpoints
+}
+
+// AdminConfig holds configuration of the admin tools of the HTTP bridge
+// that change the server or reveal its setup. They are not served over
+// HTTP unless enabled.
+type AdminConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Token   string `mapstructure:"token" secret:"true"` // Bearer token required by the tools
+}
+
+// SessionConfig holds HTTP session configuration
+type SessionConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"` // Idle time after which a session expires
+}
+
+// GeocoderConfig holds configuration of the geocoder used for location searches
+type GeocoderConfig struct {
+	Provider  string        `mapstructure:"provider"` // "nominatim" or "none"
+	BaseURL   string        `mapstructure:"base_url"`
+	UserAgent string        `mapstructure:"user_agent"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	CacheTTL  time.Duration `mapstructure:"cache_ttl"`
+}
+
+// StoreConfig holds configuration of the persistent history store
+type StoreConfig struct {
+	Path string `mapstructure:"path"` // Store file; empty disables persistence
+}
+
+// DistributionsConfig holds configuration of the rating distribution
+// snapshots maintained by the background aggregation job
+type DistributionsConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 0 disables the job, snapshots are then rebuilt on demand
+	National        bool          `mapstructure:"national"`         // Maintain a national distribution of all players
+}
+
+// RostersConfig holds configuration of the club roster cache. A roster
+// older than TTL is still served while it is refreshed in the background;
+// one older than MaxAge is fetched again before it is served.
+type RostersConfig struct {
+	TTL      time.Duration `mapstructure:"ttl"`       // 0 disables the cache
+	MaxAge   time.Duration `mapstructure:"max_age"`   // Oldest roster served while refreshing
+	MaxClubs int           `mapstructure:"max_clubs"` // Rosters kept, least recently used are evicted
+}
+
+// ReferenceDataConfig holds configuration of the warm standby of reference
+// data that rarely changes: the regions and the addresses of their
+// officials. They are loaded at startup, refreshed every interval and
+// served from memory, also while the API is unavailable.
+type ReferenceDataConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 0 disables the standby, the data is then read from the API on demand
+}
+
+// ClubAliasesConfig holds the sources of club aliases, which map the IDs
+// of merged or renamed clubs to their current IDs. Aliases of the file
+// replace those of the URL for the same old ID.
+type ClubAliasesConfig struct {
+	File            string        `mapstructure:"file"`             // JSON list of aliases, empty for none
+	URL             string        `mapstructure:"url"`              // JSON list of aliases fetched periodically, empty for none
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // How often the URL is fetched again
+}
+
+// MemoryConfig holds the memory budget of the in-memory caches and
+// snapshots. When they exceed the limit, their least recently used entries
+// are evicted.
+type MemoryConfig struct {
+	LimitMB       int           `mapstructure:"limit_mb"`       // 0 disables eviction, usage is still reported
+	CheckInterval time.Duration `mapstructure:"check_interval"` // How often usage is checked against the limit
+}
+
+// ExportConfig holds configuration of the signed club export download URLs
+type ExportConfig struct {
+	SigningKey string        `mapstructure:"signing_key" secret:"true"` // HMAC key; a random key is used if empty
+	URLTTL     time.Duration `mapstructure:"url_ttl"`                   // Validity of a download URL
+	BaseURL    string        `mapstructure:"base_url"`                  // Public URL of the HTTP bridge (default: http://localhost:<http_port>)
+}
+
+// LinksConfig holds the URL templates of the public web pages of players,
+// clubs and tournaments, linked from tool results. "{id}" is replaced by the
+// ID; an empty template disables the links of the entity type.
+type LinksConfig struct {
+	PlayerURL     string `mapstructure:"player_url"`
+	ClubURL       string `mapstructure:"club_url"`
+	TournamentURL string `mapstructure:"tournament_url"`
+}
+
+// RenderConfig holds the formatting of human-readable output, such as the
+// dates and numbers of email digests
+type RenderConfig struct {
+	Locale string `mapstructure:"locale"` // "de" (DD.MM.YYYY, 1.234,5) or "en" (YYYY-MM-DD, 1,234.5)
+}
+
+// MailConfig holds the SMTP server used to send correction requests to the
+// federation and email digests. Sending is disabled without an SMTP host.
+type MailConfig struct {
+	SMTPHost string        `mapstructure:"smtp_host"`
+	SMTPPort int           `mapstructure:"smtp_port"`
+	Username string        `mapstructure:"username"` // Empty to send without authentication
+	Password string        `mapstructure:"password" secret:"true"`
+	From     string        `mapstructure:"from"`
+	To       string        `mapstructure:"to"` // Recipient of correction requests
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// Digests batch the notifications of the watched players and regions
+	// into one message per digest and DigestInterval
+	DigestInterval time.Duration `mapstructure:"digest_interval"`
+	CheckInterval  time.Duration `mapstructure:"check_interval"` // Polling of the watched players and regions
+	MaxPerHour     int           `mapstructure:"max_per_hour"`   // Digest messages per hour, 0 for no limit
+	Template       string        `mapstructure:"template"`       // File defining "subject" and "body" templates, empty for the built-in one
+	// Digests are named email digests, configurable in the config file only
+	Digests map[string]DigestConfig `mapstructure:"digests"`
+}
+
+// DigestConfig holds the recipients of an email digest and what it watches
+type DigestConfig struct {
+	To      []string `mapstructure:"to"`
+	Players []string `mapstructure:"players"` // Player IDs whose rating changes are reported
+	Regions []string `mapstructure:"regions"` // Regions whose new tournaments are reported
+}
+
+// TelemetryConfig holds configuration of error reporting, system metrics,
+// service level objectives and alerts
+type TelemetryConfig struct {
+	Errors ErrorTrackingConfig `mapstructure:"errors"`
+	System SystemMetricsConfig `mapstructure:"system"`
+	SLO    SLOConfig           `mapstructure:"slo"`
+	Alerts AlertsConfig        `mapstructure:"alerts"`
+}
+
+// AlertsConfig holds configuration of the alerts sent to Slack or a generic
+// webhook on open circuit breakers, expiring certificates, sustained
+// upstream server errors and SLO burn rate alerts. Alerting is disabled
+// unless a webhook URL is set.
+type AlertsConfig struct {
+	SlackWebhookURL string        `mapstructure:"slack_webhook_url" secret:"true"`
+	WebhookURL      string        `mapstructure:"webhook_url" secret:"true"` // Generic endpoint receiving alerts as JSON
+	Template        string        `mapstructure:"template"`                  // text/template of the message, built-in if empty
+	Events          []string      `mapstructure:"events"`                    // Kinds of alerts sent, all if empty
+	Cooldown        time.Duration `mapstructure:"cooldown"`                  // Repeats of an alert within it are suppressed
+	Timeout         time.Duration `mapstructure:"timeout"`
+	// CertificateExpiryDays is how many days before expiry the certificates
+	// of api.ssl raise an alert, checked every CertificateCheckInterval
+	CertificateExpiryDays    int           `mapstructure:"certificate_expiry_days"`
+	CertificateCheckInterval time.Duration `mapstructure:"certificate_check_interval"`
+	// UpstreamErrorThreshold 5xx responses of the Portal64 API within
+	// UpstreamErrorWindow raise an alert
+	UpstreamErrorThreshold int           `mapstructure:"upstream_error_threshold"`
+	UpstreamErrorWindow    time.Duration `mapstructure:"upstream_error_window"`
+}
+
+// Enabled reports whether alerts are sent
+func (c AlertsConfig) Enabled() bool {
+	return c.SlackWebhookURL != "" || c.WebhookURL != ""
+}
+
+// SLOConfig holds the availability and latency objectives of the tools and
+// the burn rate alerts of their error budgets
+type SLOConfig struct {
+	Window        time.Duration `mapstructure:"window"`       // Rolling window of the error budgets, 0 disables tracking
+	Availability  float64       `mapstructure:"availability"` // Share of calls that must not fail, 0 disables the objective
+	Latency       time.Duration `mapstructure:"latency"`      // Calls slower than this count against the latency objective
+	LatencyTarget float64       `mapstructure:"latency_target"`
+	// Tools overrides the objectives of single tools; unset fields default
+	// to the objectives above
+	Tools             map[string]SLOTargetConfig `mapstructure:"tools"`
+	AlertWindow       time.Duration              `mapstructure:"alert_window"`        // Recent part of the window checked for alerts
+	BurnRateThreshold float64                    `mapstructure:"burn_rate_threshold"` // 0 disables alerts
+	AlertMinCalls     int                        `mapstructure:"alert_min_calls"`     // Calls in the alert window below which no alert is raised
+	Export            bool                       `mapstructure:"export"`              // Serve the error budgets at /metrics of the HTTP bridge
+}
+
+// SLOTargetConfig holds the objectives of a tool
+type SLOTargetConfig struct {
+	Availability  float64       `mapstructure:"availability"`
+	Latency       time.Duration `mapstructure:"latency"`
+	LatencyTarget float64       `mapstructure:"latency_target"`
+}
+
+// SystemMetricsConfig holds configuration of the system metrics sampler and
+// the health thresholds checked against its samples. Zero disables a
+// threshold.
+type SystemMetricsConfig struct {
+	Interval      time.Duration `mapstructure:"interval"` // 0 disables sampling
+	LogDir        string        `mapstructure:"log_dir"`  // Directory whose disk usage is sampled
+	Export        bool          `mapstructure:"export"`   // Serve the samples at /metrics of the HTTP bridge
+	MaxHeapMB     int           `mapstructure:"max_heap_mb"`
+	MaxGoroutines int           `mapstructure:"max_goroutines"`
+	MaxCPUPercent int           `mapstructure:"max_cpu_percent"` // 100 per fully used core
+	MaxLogDirMB   int           `mapstructure:"max_log_dir_mb"`
+}
+
+// ErrorTrackingConfig holds configuration of the error tracker receiving
+// panics, tool failures and bursts of upstream server errors. Reporting is
+// disabled unless a DSN or endpoint is set.
+type ErrorTrackingConfig struct {
+	DSN         string        `mapstructure:"dsn" secret:"true"` // Sentry DSN
+	Endpoint    string        `mapstructure:"endpoint"`          // Generic endpoint receiving events as JSON, used without DSN
+	Environment string        `mapstructure:"environment"`
+	Release     string        `mapstructure:"release"` // Defaults to the version of the build
+	Timeout     time.Duration `mapstructure:"timeout"`
+	// UpstreamBurstThreshold 5xx responses of the Portal64 API within
+	// UpstreamBurstWindow are reported as one event
+	UpstreamBurstThreshold int           `mapstructure:"upstream_burst_threshold"`
+	UpstreamBurstWindow    time.Duration `mapstructure:"upstream_burst_window"`
+}
+
+// Enabled reports whether errors are sent to an error tracker
+func (c ErrorTrackingConfig) Enabled() bool {
+	return c.DSN != "" || c.Endpoint != ""
 }
 
 // LoggerConfig holds logging configuration
@@ -33,8 +570,31 @@ type LoggerConfig struct {
 	Format string `mapstructure:"format"`
 }
 
+// LoadOptions controls how the configuration is loaded
+type LoadOptions struct {
+	// Strict rejects config file keys that do not map to a config field,
+	// instead of silently ignoring them
+	Strict bool
+}
+
 // Load loads configuration from environment variables and config files
 func Load(configPath string) (*Config, error) {
+	return LoadWithOptions(configPath, LoadOptions{})
+}
+
+// loadMu serializes loads of the global viper
+var loadMu sync.Mutex
+
+// LoadWithOptions loads configuration from environment variables and config files
+func LoadWithOptions(configPath string, opts LoadOptions) (*Config, error) {
+	// The global viper is shared, the configuration may be loaded again
+	// while the server runs
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	// Start from a clean state, settings of a previous Load must not leak
+	viper.Reset()
+
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(".")
@@ -46,39 +606,193 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	// Set defaults
-	viper.SetDefault("api.base_url", "http://localhost:8080")
-	viper.SetDefault("api.timeout", "30s")
-	viper.SetDefault("mcp.port", 3000)
-	viper.SetDefault("mcp.mode", "stdio")
-	viper.SetDefault("mcp.http_port", 8888)
-	viper.SetDefault("logging.level", "info")
-	viper.SetDefault("logging.format", "json")
+	setDefaults(viper.GetViper())
 
 	// Bind environment variables
 	viper.SetEnvPrefix("PORTAL64")
 	viper.AutomaticEnv()
-	viper.BindEnv("api.base_url", "PORTAL64_API_URL")
-	viper.BindEnv("mcp.port", "MCP_SERVER_PORT")
-	viper.BindEnv("mcp.mode", "MCP_SERVER_MODE")
-	viper.BindEnv("mcp.http_port", "MCP_HTTP_PORT")
-	viper.BindEnv("logging.level", "LOG_LEVEL")
-	viper.BindEnv("api.timeout", "API_TIMEOUT")
+	bindEnv(viper.GetViper())
 
 	// Read config file if it exists
+	fileFound := true
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
+		fileFound = false
+	}
+
+	var decoderOptions []viper.DecoderConfigOption
+	if opts.Strict {
+		decoderOptions = append(decoderOptions, func(dc *mapstructure.DecoderConfig) {
+			dc.ErrorUnused = true
+		})
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := viper.Unmarshal(&config, decoderOptions...); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	if fileFound {
+		config.File = viper.ConfigFileUsed()
+	}
+	config.sources = settingSources(viper.GetViper())
+
+	if err := resolveSecrets(&config); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
 
+// setDefaults sets the default values of all config keys
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("api.base_url", "http://localhost:8080")
+	v.SetDefault("api.timeout", "30s")
+	v.SetDefault("api.ssl.insecure_skip_verify", false)
+	v.SetDefault("api.failover.failure_threshold", 3)
+	v.SetDefault("api.failover.cooldown", "30s")
+	v.SetDefault("api.read_only", true)
+	v.SetDefault("api.anomalies.enabled", true)
+	v.SetDefault("api.anomalies.log_file", "")
+	v.SetDefault("api.connection.http2", true)
+	v.SetDefault("api.connection.keep_alive", true)
+	v.SetDefault("api.connection.keep_alive_interval", "30s")
+	v.SetDefault("api.connection.idle_timeout", "90s")
+	v.SetDefault("api.connection.max_idle_conns_per_host", 10)
+	v.SetDefault("api.debug.allowed_paths", []string{"/health", "/api/v1/players*", "/api/v1/clubs*", "/api/v1/tournaments*", "/api/v1/addresses*"})
+	v.SetDefault("api.debug.max_body_bytes", 4096)
+	v.SetDefault("api.scheme_detection.mode", "correct")
+	v.SetDefault("api.scheme_detection.candidates", []string{"http:8080", "https:8443"})
+	v.SetDefault("api.scheme_detection.timeout", "3s")
+	v.SetDefault("api.auth.type", "none")
+	v.SetDefault("api.auth.header", "X-API-Key")
+	v.SetDefault("api.auth.api_key", "")
+	v.SetDefault("api.auth.username", "")
+	v.SetDefault("api.auth.password", "")
+	v.SetDefault("api.auth.oauth2.token_url", "")
+	v.SetDefault("api.auth.oauth2.client_id", "")
+	v.SetDefault("api.auth.oauth2.client_secret", "")
+	v.SetDefault("api.auth.oauth2.scopes", []string{})
+	v.SetDefault("api.signing.algorithm", "")
+	v.SetDefault("api.signing.key", "")
+	v.SetDefault("api.signing.key_id", "")
+	v.SetDefault("api.signing.previous_key", "")
+	v.SetDefault("api.signing.previous_key_id", "")
+	v.SetDefault("api.signing.header", "X-Portal64-Signature")
+	v.SetDefault("api.signing.clock_skew", "30s")
+	v.SetDefault("api.maintenance.windows", []string{})
+	v.SetDefault("api.maintenance.pattern", "(?i)maintenance|wartung")
+	v.SetDefault("api.maintenance.hold", "10m")
+	v.SetDefault("api.cache.max_entries", 10000)
+	v.SetDefault("api.cache.not_found_ttl", "1m")
+	v.SetDefault("api.cache.policies.regions.ttl", "24h")
+	v.SetDefault("api.cache.policies.regions.stale", "168h")
+	v.SetDefault("api.cache.policies.addresses.ttl", "6h")
+	v.SetDefault("api.cache.policies.addresses.stale", "24h")
+	v.SetDefault("api.cache.policies.players.ttl", "5m")
+	v.SetDefault("api.cache.policies.players.stale", "1h")
+	v.SetDefault("api.cache.policies.clubs.ttl", "15m")
+	v.SetDefault("api.cache.policies.clubs.stale", "1h")
+	v.SetDefault("api.cache.policies.tournaments.ttl", "10m")
+	v.SetDefault("api.cache.policies.tournaments.stale", "1h")
+	v.SetDefault("mcp.port", 3000)
+	v.SetDefault("mcp.mode", "stdio")
+	v.SetDefault("mcp.http_port", 8888)
+	v.SetDefault("mcp.output_format", "envelope")
+	v.SetDefault("mcp.sessions.enabled", false)
+	v.SetDefault("mcp.sessions.ttl", "30m")
+	v.SetDefault("mcp.http.read_timeout", "30s")
+	v.SetDefault("mcp.http.read_header_timeout", "10s")
+	v.SetDefault("mcp.http.write_timeout", "5m")
+	v.SetDefault("mcp.http.idle_timeout", "120s")
+	v.SetDefault("mcp.http.max_header_bytes", 1<<20)
+	v.SetDefault("mcp.http.max_connections", 1024)
+	v.SetDefault("mcp.http.reuse_port", false)
+	v.SetDefault("mcp.http.drain_timeout", "30s")
+	v.SetDefault("mcp.http.debug.enabled", false)
+	v.SetDefault("mcp.http.debug.token", "")
+	v.SetDefault("mcp.http.admin.enabled", false)
+	v.SetDefault("mcp.http.admin.token", "")
+	v.SetDefault("mcp.http.signing.algorithm", "")
+	v.SetDefault("mcp.http.signing.key", "")
+	v.SetDefault("mcp.http.signing.key_id", "")
+	v.SetDefault("mcp.http.ui.enabled", false)
+	v.SetDefault("mcp.load_shedding.enabled", false)
+	v.SetDefault("mcp.load_shedding.max_in_flight", 64)
+	v.SetDefault("mcp.load_shedding.max_latency", "5s")
+	v.SetDefault("mcp.load_shedding.retry_after", "5s")
+	v.SetDefault("mcp.aggregates.max_failure_ratio", 0.5)
+	v.SetDefault("mcp.scheduling.max_concurrent", 0)
+	v.SetDefault("mcp.scheduling.queue_timeout", "30s")
+	v.SetDefault("geocoder.provider", "nominatim")
+	v.SetDefault("geocoder.base_url", "https://nominatim.openstreetmap.org")
+	v.SetDefault("geocoder.user_agent", "portal64gomcp/1.0")
+	v.SetDefault("geocoder.timeout", "10s")
+	v.SetDefault("geocoder.cache_ttl", "168h")
+	v.SetDefault("distributions.refresh_interval", "24h")
+	v.SetDefault("distributions.national", false)
+	v.SetDefault("rosters.ttl", "1h")
+	v.SetDefault("rosters.max_age", "24h")
+	v.SetDefault("rosters.max_clubs", 1000)
+	v.SetDefault("reference_data.refresh_interval", "6h")
+	v.SetDefault("club_aliases.file", "")
+	v.SetDefault("club_aliases.url", "")
+	v.SetDefault("club_aliases.refresh_interval", "24h")
+	v.SetDefault("memory.limit_mb", 256)
+	v.SetDefault("memory.check_interval", "30s")
+	v.SetDefault("export.url_ttl", "15m")
+	v.SetDefault("links.player_url", "https://www.schachbund.de/spieler/{id}.html")
+	v.SetDefault("links.club_url", "https://www.schachbund.de/verein/{id}.html")
+	v.SetDefault("links.tournament_url", "https://www.schachbund.de/turnier/{id}.html")
+	v.SetDefault("render.locale", string(render.DefaultLocale))
+	v.SetDefault("mail.smtp_host", "")
+	v.SetDefault("mail.smtp_port", 587)
+	v.SetDefault("mail.username", "")
+	v.SetDefault("mail.password", "")
+	v.SetDefault("mail.from", "")
+	v.SetDefault("mail.to", "")
+	v.SetDefault("mail.timeout", "30s")
+	v.SetDefault("mail.digest_interval", "24h")
+	v.SetDefault("mail.check_interval", "1h")
+	v.SetDefault("mail.max_per_hour", 20)
+	v.SetDefault("mail.template", "")
+	v.SetDefault("telemetry.errors.timeout", "5s")
+	v.SetDefault("telemetry.errors.upstream_burst_threshold", 5)
+	v.SetDefault("telemetry.errors.upstream_burst_window", "1m")
+	v.SetDefault("telemetry.system.interval", "30s")
+	v.SetDefault("telemetry.system.log_dir", "")
+	v.SetDefault("telemetry.system.export", false)
+	v.SetDefault("telemetry.system.max_heap_mb", 0)
+	v.SetDefault("telemetry.system.max_goroutines", 0)
+	v.SetDefault("telemetry.system.max_cpu_percent", 0)
+	v.SetDefault("telemetry.system.max_log_dir_mb", 0)
+	v.SetDefault("telemetry.slo.window", "24h")
+	v.SetDefault("telemetry.slo.availability", 0.99)
+	v.SetDefault("telemetry.slo.latency", "5s")
+	v.SetDefault("telemetry.slo.latency_target", 0.95)
+	v.SetDefault("telemetry.slo.tools", map[string]interface{}{})
+	v.SetDefault("telemetry.slo.alert_window", "1h")
+	v.SetDefault("telemetry.slo.burn_rate_threshold", 6)
+	v.SetDefault("telemetry.slo.alert_min_calls", 20)
+	v.SetDefault("telemetry.slo.export", false)
+	v.SetDefault("telemetry.alerts.slack_webhook_url", "")
+	v.SetDefault("telemetry.alerts.webhook_url", "")
+	v.SetDefault("telemetry.alerts.template", "")
+	v.SetDefault("telemetry.alerts.events", []string{})
+	v.SetDefault("telemetry.alerts.cooldown", "1h")
+	v.SetDefault("telemetry.alerts.timeout", "5s")
+	v.SetDefault("telemetry.alerts.certificate_expiry_days", 14)
+	v.SetDefault("telemetry.alerts.certificate_check_interval", "12h")
+	v.SetDefault("telemetry.alerts.upstream_error_threshold", 10)
+	v.SetDefault("telemetry.alerts.upstream_error_window", "5m")
+	for _, name := range features.Names() {
+		v.SetDefault("features."+name, false)
+	}
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.format", "json")
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.API.BaseURL == "" {
@@ -102,5 +816,467 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("api.timeout must be positive")
 	}
 
+	for _, fallback := range c.API.FallbackURLs {
+		if strings.TrimSpace(fallback) == "" {
+			return fmt.Errorf("api.fallback_urls must not contain empty URLs")
+		}
+	}
+
+	if len(c.API.FallbackURLs) > 0 && (c.API.Failover.FailureThreshold <= 0 || c.API.Failover.Cooldown <= 0) {
+		return fmt.Errorf("api.failover.failure_threshold and api.failover.cooldown must be positive")
+	}
+
+	for _, name := range sortedKeys(c.API.Profiles) {
+		profile := c.API.Profiles[name]
+		switch {
+		case !profileNamePattern.MatchString(name):
+			return fmt.Errorf("api.profiles.%s: profile names must consist of lowercase letters, digits, '-' and '_'", name)
+		case name == DefaultProfile:
+			return fmt.Errorf("api.profiles.%s: the profile name is reserved for api.base_url", name)
+		case profile.BaseURL == "":
+			return fmt.Errorf("api.profiles.%s.base_url is required", name)
+		case profile.Timeout < 0:
+			return fmt.Errorf("api.profiles.%s.timeout must not be negative", name)
+		}
+		for _, fallback := range profile.FallbackURLs {
+			if strings.TrimSpace(fallback) == "" {
+				return fmt.Errorf("api.profiles.%s.fallback_urls must not contain empty URLs", name)
+			}
+		}
+		if len(profile.FallbackURLs) > 0 && (c.API.Failover.FailureThreshold <= 0 || c.API.Failover.Cooldown <= 0) {
+			return fmt.Errorf("api.failover.failure_threshold and api.failover.cooldown must be positive")
+		}
+	}
+
+	if c.API.Connection.KeepAliveInterval < 0 || c.API.Connection.IdleTimeout < 0 || c.API.Connection.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("api.connection.keep_alive_interval, idle_timeout and max_idle_conns_per_host must not be negative")
+	}
+	if c.API.Debug.MaxBodyBytes < 0 {
+		return fmt.Errorf("api.debug.max_body_bytes must not be negative")
+	}
+	for _, allowed := range c.API.Debug.AllowedPaths {
+		if !strings.HasPrefix(allowed, "/") {
+			return fmt.Errorf("api.debug.allowed_paths: %q must start with /", allowed)
+		}
+	}
+
+	switch c.API.SchemeDetection.Mode {
+	case "", "off":
+	case "correct", "fail":
+		if c.API.SchemeDetection.Timeout <= 0 {
+			return fmt.Errorf("api.scheme_detection.timeout must be positive")
+		}
+		for _, candidate := range c.API.SchemeDetection.Candidates {
+			if !schemeCandidatePattern.MatchString(candidate) {
+				return fmt.Errorf("api.scheme_detection.candidates: invalid candidate %q, expected http:<port> or https:<port>", candidate)
+			}
+		}
+	default:
+		return fmt.Errorf("api.scheme_detection.mode must be one of: correct, fail, off")
+	}
+
+	if err := c.API.Auth.validate(); err != nil {
+		return err
+	}
+
+	if err := c.API.Signing.validate(); err != nil {
+		return err
+	}
+
+	if err := c.API.Cache.validate(); err != nil {
+		return err
+	}
+
+	if err := c.API.Maintenance.validate(); err != nil {
+		return err
+	}
+
+	if (c.API.SSL.ClientCert == "") != (c.API.SSL.ClientKey == "") {
+		return fmt.Errorf("api.ssl.client_cert and api.ssl.client_key must be set together")
+	}
+
+	if c.MCP.OutputFormat != "" && c.MCP.OutputFormat != "envelope" && c.MCP.OutputFormat != "legacy" {
+		return fmt.Errorf("mcp.output_format must be one of: envelope, legacy")
+	}
+
+	if c.Geocoder.Provider != "" && c.Geocoder.Provider != "none" && c.Geocoder.Provider != "nominatim" {
+		return fmt.Errorf("geocoder.provider must be one of: nominatim, none")
+	}
+
+	for name := range c.Features {
+		if !features.IsKnown(name) {
+			return fmt.Errorf("unknown feature flag features.%s, expected one of: %s", name, strings.Join(features.Names(), ", "))
+		}
+	}
+
+	httpCfg := c.MCP.HTTP
+	if httpCfg.ReadTimeout < 0 || httpCfg.ReadHeaderTimeout < 0 || httpCfg.WriteTimeout < 0 || httpCfg.IdleTimeout < 0 || httpCfg.DrainTimeout < 0 {
+		return fmt.Errorf("mcp.http timeouts must not be negative")
+	}
+
+	if httpCfg.MaxHeaderBytes < 0 || httpCfg.MaxConnections < 0 {
+		return fmt.Errorf("mcp.http.max_header_bytes and mcp.http.max_connections must not be negative")
+	}
+
+	if httpCfg.Debug.Enabled && httpCfg.Debug.Token == "" {
+		return fmt.Errorf("mcp.http.debug.token is required when mcp.http.debug.enabled is set")
+	}
+
+	if httpCfg.Admin.Enabled && httpCfg.Admin.Token == "" {
+		return fmt.Errorf("mcp.http.admin.token is required when mcp.http.admin.enabled is set")
+	}
+
+	if err := httpCfg.Signing.validate(); err != nil {
+		return err
+	}
+
+	shedding := c.MCP.LoadShedding
+	if shedding.MaxInFlight < 0 || shedding.MaxLatency < 0 || shedding.RetryAfter < 0 {
+		return fmt.Errorf("mcp.load_shedding thresholds must not be negative")
+	}
+
+	if shedding.Enabled && shedding.MaxInFlight == 0 && shedding.MaxLatency == 0 {
+		return fmt.Errorf("mcp.load_shedding needs max_in_flight or max_latency when enabled")
+	}
+
+	if c.MCP.Scheduling.MaxConcurrent < 0 || c.MCP.Scheduling.QueueTimeout < 0 {
+		return fmt.Errorf("mcp.scheduling.max_concurrent and queue_timeout must not be negative")
+	}
+
+	if ratio := c.MCP.Aggregates.MaxFailureRatio; ratio < 0 || ratio > 1 {
+		return fmt.Errorf("mcp.aggregates.max_failure_ratio must be between 0 and 1")
+	}
+
+	if c.Distributions.RefreshInterval < 0 {
+		return fmt.Errorf("distributions.refresh_interval must not be negative")
+	}
+
+	if c.Rosters.TTL < 0 || c.Rosters.MaxClubs < 0 {
+		return fmt.Errorf("rosters.ttl and rosters.max_clubs must not be negative")
+	}
+
+	if c.Rosters.TTL > 0 && c.Rosters.MaxAge < c.Rosters.TTL {
+		return fmt.Errorf("rosters.max_age must not be less than rosters.ttl")
+	}
+
+	if c.ReferenceData.RefreshInterval < 0 {
+		return fmt.Errorf("reference_data.refresh_interval must not be negative")
+	}
+
+	if aliases := c.ClubAliases; aliases.URL != "" {
+		if u, err := url.Parse(aliases.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("club_aliases.url must be an http or https URL")
+		}
+		if aliases.RefreshInterval <= 0 {
+			return fmt.Errorf("club_aliases.refresh_interval must be positive when club_aliases.url is set")
+		}
+	}
+
+	if c.Memory.LimitMB < 0 {
+		return fmt.Errorf("memory.limit_mb must not be negative")
+	}
+
+	if c.Memory.LimitMB > 0 && c.Memory.CheckInterval <= 0 {
+		return fmt.Errorf("memory.check_interval must be positive when memory.limit_mb is set")
+	}
+
+	if c.Export.URLTTL < 0 {
+		return fmt.Errorf("export.url_ttl must not be negative")
+	}
+
+	if err := c.Links.validate(); err != nil {
+		return err
+	}
+
+	if _, err := render.ParseLocale(c.Render.Locale); err != nil {
+		return fmt.Errorf("render.locale: %w", err)
+	}
+
+	if mail := c.Mail; mail.SMTPHost != "" {
+		if mail.SMTPPort < 1 || mail.SMTPPort > 65535 {
+			return fmt.Errorf("invalid mail.smtp_port: %d (must be 1-65535)", mail.SMTPPort)
+		}
+		if mail.From == "" || mail.To == "" {
+			return fmt.Errorf("mail.from and mail.to are required when mail.smtp_host is set")
+		}
+		if mail.Timeout < 0 {
+			return fmt.Errorf("mail.timeout must not be negative")
+		}
+	}
+	if err := c.Mail.validateDigests(); err != nil {
+		return err
+	}
+
+	errorTracking := c.Telemetry.Errors
+	if errorTracking.Timeout < 0 || errorTracking.UpstreamBurstThreshold < 0 || errorTracking.UpstreamBurstWindow < 0 {
+		return fmt.Errorf("telemetry.errors.timeout, upstream_burst_threshold and upstream_burst_window must not be negative")
+	}
+
+	if errorTracking.DSN != "" {
+		if u, err := url.Parse(errorTracking.DSN); err != nil || u.User == nil || u.Host == "" {
+			return fmt.Errorf("telemetry.errors.dsn must be a Sentry DSN of the form https://<key>@<host>/<project>")
+		}
+	}
+
+	system := c.Telemetry.System
+	if system.Interval < 0 || system.MaxHeapMB < 0 || system.MaxGoroutines < 0 || system.MaxCPUPercent < 0 || system.MaxLogDirMB < 0 {
+		return fmt.Errorf("telemetry.system.interval and thresholds must not be negative")
+	}
+
+	if err := c.Telemetry.SLO.validate(); err != nil {
+		return err
+	}
+	if err := c.Telemetry.Alerts.validate(); err != nil {
+		return err
+	}
+
+	for _, hidden := range [][]string{c.MCP.Tools.Stdio.Hidden, c.MCP.Tools.HTTP.Hidden} {
+		for _, name := range hidden {
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("mcp.tools hidden lists must not contain empty tool names")
+			}
+		}
+	}
+
+	if c.MCP.Sessions.Enabled && c.MCP.Sessions.TTL <= 0 {
+		return fmt.Errorf("mcp.sessions.ttl must be positive when sessions are enabled")
+	}
+
+	return nil
+}
+
+// validate checks the algorithm and key of response signing
+func (c SigningConfig) validate() error {
+	switch c.Algorithm {
+	case "":
+		return nil
+	case SigningHMACSHA256, SigningEd25519:
+	default:
+		return fmt.Errorf("invalid mcp.http.signing.algorithm: %s (must be %s or %s)", c.Algorithm, SigningHMACSHA256, SigningEd25519)
+	}
+	if c.Key == "" {
+		return fmt.Errorf("mcp.http.signing.key is required when mcp.http.signing.algorithm is set")
+	}
+	if c.Algorithm == SigningEd25519 {
+		if _, err := ParseEd25519Key(c.Key); err != nil {
+			return fmt.Errorf("invalid mcp.http.signing.key: %w", err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the credentials of the authentication type are set
+func (c APIAuthConfig) validate() error {
+	switch c.Type {
+	case "", "none":
+	case "api_key":
+		if c.APIKey == "" || strings.TrimSpace(c.Header) == "" {
+			return fmt.Errorf("api.auth.api_key and api.auth.header are required for api_key authentication")
+		}
+	case "basic":
+		if c.Username == "" {
+			return fmt.Errorf("api.auth.username is required for basic authentication")
+		}
+	case "oauth2":
+		if u, err := url.Parse(c.OAuth2.TokenURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("api.auth.oauth2.token_url must be an absolute URL for oauth2 authentication")
+		}
+		if c.OAuth2.ClientID == "" {
+			return fmt.Errorf("api.auth.oauth2.client_id is required for oauth2 authentication")
+		}
+	default:
+		return fmt.Errorf("api.auth.type must be one of: none, api_key, basic, oauth2")
+	}
+	return nil
+}
+
+// validate checks the key and header of request signing
+func (c APIRequestSigningConfig) validate() error {
+	switch c.Algorithm {
+	case "":
+		return nil
+	case SigningHMACSHA256:
+	default:
+		return fmt.Errorf("invalid api.signing.algorithm: %s (must be %s)", c.Algorithm, SigningHMACSHA256)
+	}
+	if c.Key == "" {
+		return fmt.Errorf("api.signing.key is required when api.signing.algorithm is set")
+	}
+	if strings.TrimSpace(c.Header) == "" {
+		return fmt.Errorf("api.signing.header must not be empty")
+	}
+	if c.ClockSkew < 0 {
+		return fmt.Errorf("api.signing.clock_skew must not be negative")
+	}
+	return nil
+}
+
+// validate checks that the limits and durations of the cache are not
+// negative
+func (c APICacheConfig) validate() error {
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("api.cache.max_entries must not be negative")
+	}
+	if c.NotFoundTTL < 0 {
+		return fmt.Errorf("api.cache.not_found_ttl must not be negative")
+	}
+	for entity, policy := range c.Policies.ByEntity() {
+		if policy.TTL < 0 || policy.Stale < 0 {
+			return fmt.Errorf("api.cache.policies.%s.ttl and stale must not be negative", entity)
+		}
+	}
+	return nil
+}
+
+func (c SLOConfig) validate() error {
+	if c.Window < 0 || c.AlertWindow < 0 || c.BurnRateThreshold < 0 || c.AlertMinCalls < 0 {
+		return fmt.Errorf("telemetry.slo.window, alert_window, burn_rate_threshold and alert_min_calls must not be negative")
+	}
+	if c.Window > 0 && c.AlertWindow > c.Window {
+		return fmt.Errorf("telemetry.slo.alert_window must not exceed telemetry.slo.window")
+	}
+	targets := map[string]SLOTargetConfig{"": {Availability: c.Availability, Latency: c.Latency, LatencyTarget: c.LatencyTarget}}
+	for tool, target := range c.Tools {
+		targets["tools."+tool+"."] = target
+	}
+	for _, prefix := range sortedKeys(targets) {
+		target := targets[prefix]
+		if target.Availability < 0 || target.Availability >= 1 || target.LatencyTarget < 0 || target.LatencyTarget >= 1 {
+			return fmt.Errorf("telemetry.slo.%savailability and latency_target must be at least 0 and below 1", prefix)
+		}
+		if target.Latency < 0 {
+			return fmt.Errorf("telemetry.slo.%slatency must not be negative", prefix)
+		}
+	}
+	return nil
+}
+
+func (c AlertsConfig) validate() error {
+	if c.Cooldown < 0 || c.Timeout < 0 || c.CertificateExpiryDays < 0 || c.CertificateCheckInterval < 0 ||
+		c.UpstreamErrorThreshold < 0 || c.UpstreamErrorWindow < 0 {
+		return fmt.Errorf("telemetry.alerts durations and thresholds must not be negative")
+	}
+	for key, value := range map[string]string{"slack_webhook_url": c.SlackWebhookURL, "webhook_url": c.WebhookURL} {
+		if value == "" {
+			continue
+		}
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("telemetry.alerts.%s must be an http or https URL", key)
+		}
+	}
+	for _, event := range c.Events {
+		known := false
+		for _, kind := range telemetry.AlertKinds {
+			known = known || event == kind
+		}
+		if !known {
+			return fmt.Errorf("telemetry.alerts.events: unknown event %q, expected one of %s", event, strings.Join(telemetry.AlertKinds, ", "))
+		}
+	}
+	if c.Template != "" {
+		if _, err := template.New("alert").Parse(c.Template); err != nil {
+			return fmt.Errorf("telemetry.alerts.template: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c APIMaintenanceConfig) validate() error {
+	if _, err := c.ParseWindows(); err != nil {
+		return fmt.Errorf("api.maintenance.windows: %w", err)
+	}
+	if _, err := regexp.Compile(c.Pattern); err != nil {
+		return fmt.Errorf("api.maintenance.pattern is not a valid regular expression: %w", err)
+	}
+	if c.Hold < 0 {
+		return fmt.Errorf("api.maintenance.hold must not be negative")
+	}
+	return nil
+}
+
+// ParseWindows returns the maintenance windows
+func (c APIMaintenanceConfig) ParseWindows() ([]api.MaintenanceWindow, error) {
+	windows := make([]api.MaintenanceWindow, 0, len(c.Windows))
+	for _, s := range c.Windows {
+		window, err := api.ParseMaintenanceWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// ByEntity returns the policies by entity type, as named in the config
+func (c APICachePoliciesConfig) ByEntity() map[string]APICachePolicyConfig {
+	return map[string]APICachePolicyConfig{
+		"regions":     c.Regions,
+		"addresses":   c.Addresses,
+		"players":     c.Players,
+		"clubs":       c.Clubs,
+		"tournaments": c.Tournaments,
+	}
+}
+
+// ParseEd25519Key decodes a base64 Ed25519 seed or private key
+func ParseEd25519Key(key string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, fmt.Errorf("not base64: %w", err)
+	}
+	switch len(data) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(data), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(data), nil
+	default:
+		return nil, fmt.Errorf("expected a %d byte seed or %d byte private key, got %d bytes", ed25519.SeedSize, ed25519.PrivateKeySize, len(data))
+	}
+}
+
+// validateDigests checks the email digests
+func (c MailConfig) validateDigests() error {
+	if len(c.Digests) == 0 {
+		return nil
+	}
+	if c.SMTPHost == "" {
+		return fmt.Errorf("mail.digests need mail.smtp_host")
+	}
+	if c.DigestInterval <= 0 || c.CheckInterval <= 0 {
+		return fmt.Errorf("mail.digest_interval and check_interval must be positive when mail.digests are set")
+	}
+	if c.MaxPerHour < 0 {
+		return fmt.Errorf("mail.max_per_hour must not be negative")
+	}
+	for _, name := range sortedKeys(c.Digests) {
+		digest := c.Digests[name]
+		switch {
+		case !profileNamePattern.MatchString(name):
+			return fmt.Errorf("mail.digests.%s: digest names must consist of lowercase letters, digits, '-' and '_'", name)
+		case len(digest.To) == 0:
+			return fmt.Errorf("mail.digests.%s.to is required", name)
+		case len(digest.Players) == 0 && len(digest.Regions) == 0:
+			return fmt.Errorf("mail.digests.%s needs players or regions to watch", name)
+		}
+	}
+	return nil
+}
+
+// validate checks that the link templates are absolute HTTP(S) URLs with
+// an {id} placeholder
+func (c LinksConfig) validate() error {
+	for key, template := range map[string]string{
+		"player_url":     c.PlayerURL,
+		"club_url":       c.ClubURL,
+		"tournament_url": c.TournamentURL,
+	} {
+		if template == "" {
+			continue
+		}
+		u, err := url.Parse(strings.ReplaceAll(template, "{id}", "C0101"))
+		if !strings.Contains(template, "{id}") || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("links.%s must be an absolute http(s) URL containing {id}", key)
+		}
+	}
 	return nil
 }
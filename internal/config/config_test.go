@@ -129,7 +129,9 @@ func TestValidate_ValidConfiguration(t *testing.T) {
 			Timeout: 30 * time.Second,
 		},
 		MCP: MCPConfig{
-			Port: 3000,
+			Port:     3000,
+			Mode:     "stdio",
+			HTTPPort: 8888,
 		},
 		Logger: LoggerConfig{
 			Level:  "info",
@@ -148,7 +150,9 @@ func TestValidate_EmptyBaseURL(t *testing.T) {
 			Timeout: 30 * time.Second,
 		},
 		MCP: MCPConfig{
-			Port: 3000,
+			Port:     3000,
+			Mode:     "stdio",
+			HTTPPort: 8888,
 		},
 	}
 	
@@ -193,7 +197,9 @@ func TestValidate_InvalidTimeout(t *testing.T) {
 			Timeout: -1 * time.Second,
 		},
 		MCP: MCPConfig{
-			Port: 3000,
+			Port:     3000,
+			Mode:     "stdio",
+			HTTPPort: 8888,
 		},
 	}
 	
@@ -202,6 +208,770 @@ func TestValidate_InvalidTimeout(t *testing.T) {
 	assert.Contains(t, err.Error(), "api.timeout must be positive")
 }
 
+func TestValidate_SSLKeyPair(t *testing.T) {
+	config := &Config{
+		API: APIConfig{
+			BaseURL: "https://portal64.example.org",
+			Timeout: 30 * time.Second,
+			SSL: APISSLConfig{
+				ClientCert: "/etc/ssl/client.pem",
+			},
+		},
+		MCP: MCPConfig{
+			Port:     3000,
+			Mode:     "stdio",
+			HTTPPort: 8888,
+		},
+	}
+	
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "api.ssl.client_cert and api.ssl.client_key must be set together")
+	
+	config.API.SSL.ClientKey = "/etc/ssl/client-key.pem"
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoad_FallbackURLsFromEnvironment(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_API_FALLBACK_URLS", "https://a.example.org,https://b.example.org")
+	
+	config, err := Load("")
+	require.NoError(t, err)
+	
+	assert.Equal(t, []string{"https://a.example.org", "https://b.example.org"}, config.API.FallbackURLs)
+	assert.Equal(t, 3, config.API.Failover.FailureThreshold)
+	assert.Equal(t, 30*time.Second, config.API.Failover.Cooldown)
+	assert.True(t, config.API.ReadOnly)
+	assert.True(t, config.API.Anomalies.Enabled)
+	assert.NoError(t, config.Validate())
+}
+
+func TestValidate_Failover(t *testing.T) {
+	config := &Config{
+		API: APIConfig{
+			BaseURL:      "https://portal64.example.org",
+			Timeout:      30 * time.Second,
+			FallbackURLs: []string{"https://mirror.example.org"},
+		},
+		MCP: MCPConfig{
+			Port:     3000,
+			Mode:     "stdio",
+			HTTPPort: 8888,
+		},
+	}
+	
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "api.failover.failure_threshold and api.failover.cooldown must be positive")
+	
+	config.API.Failover = APIFailoverConfig{FailureThreshold: 3, Cooldown: 30 * time.Second}
+	assert.NoError(t, config.Validate())
+	
+	config.API.FallbackURLs = append(config.API.FallbackURLs, " ")
+	assert.Error(t, config.Validate())
+}
+
+func TestLoad_Distributions(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.Distributions.RefreshInterval)
+	assert.False(t, config.Distributions.National)
+
+	setEnvVar(t, "DISTRIBUTIONS_REFRESH_INTERVAL", "6h")
+	setEnvVar(t, "DISTRIBUTIONS_NATIONAL", "true")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, config.Distributions.RefreshInterval)
+	assert.True(t, config.Distributions.National)
+
+	config.Distributions.RefreshInterval = -time.Hour
+	assert.EqualError(t, config.Validate(), "distributions.refresh_interval must not be negative")
+}
+
+func TestLoad_ReferenceData(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, config.ReferenceData.RefreshInterval)
+
+	setEnvVar(t, "PORTAL64_REFERENCE_DATA_REFRESH_INTERVAL", "0")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.Zero(t, config.ReferenceData.RefreshInterval)
+
+	config.ReferenceData.RefreshInterval = -time.Hour
+	assert.EqualError(t, config.Validate(), "reference_data.refresh_interval must not be negative")
+}
+
+func TestLoad_Rosters(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, RostersConfig{TTL: time.Hour, MaxAge: 24 * time.Hour, MaxClubs: 1000}, config.Rosters)
+
+	setEnvVar(t, "PORTAL64_ROSTERS_TTL", "0")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.Zero(t, config.Rosters.TTL)
+
+	config.Rosters = RostersConfig{TTL: 2 * time.Hour, MaxAge: time.Hour}
+	assert.EqualError(t, config.Validate(), "rosters.max_age must not be less than rosters.ttl")
+
+	config.Rosters = RostersConfig{TTL: time.Hour, MaxAge: time.Hour, MaxClubs: -1}
+	assert.EqualError(t, config.Validate(), "rosters.ttl and rosters.max_clubs must not be negative")
+}
+
+func TestLoad_ClubAliases(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, ClubAliasesConfig{RefreshInterval: 24 * time.Hour}, config.ClubAliases)
+	assert.NoError(t, config.Validate())
+
+	config.ClubAliases.URL = "ftp://example.org/aliases.json"
+	assert.EqualError(t, config.Validate(), "club_aliases.url must be an http or https URL")
+
+	config.ClubAliases = ClubAliasesConfig{URL: "https://example.org/aliases.json"}
+	assert.EqualError(t, config.Validate(), "club_aliases.refresh_interval must be positive when club_aliases.url is set")
+}
+
+func TestLoad_Memory(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, MemoryConfig{LimitMB: 256, CheckInterval: 30 * time.Second}, config.Memory)
+
+	config.Memory.LimitMB = -1
+	assert.EqualError(t, config.Validate(), "memory.limit_mb must not be negative")
+
+	config.Memory = MemoryConfig{LimitMB: 64}
+	assert.EqualError(t, config.Validate(), "memory.check_interval must be positive when memory.limit_mb is set")
+
+	config.Memory = MemoryConfig{}
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoad_Export(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "EXPORT_SIGNING_KEY", "secret")
+	setEnvVar(t, "EXPORT_BASE_URL", "https://mcp.example.org")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", config.Export.SigningKey)
+	assert.Equal(t, "https://mcp.example.org", config.Export.BaseURL)
+	assert.Equal(t, 15*time.Minute, config.Export.URLTTL)
+
+	config.Export.URLTTL = -time.Minute
+	assert.EqualError(t, config.Validate(), "export.url_ttl must not be negative")
+}
+
+func TestLoad_Links(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_LINKS_CLUB_URL", "https://www.svw.info/vereine/{id}")
+	configFile := testutil.CreateTempConfigFile(t, `
+api:
+  base_url: "http://localhost:8080"
+links:
+  tournament_url: ""
+`)
+
+	config, err := Load(configFile)
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, LinksConfig{
+		PlayerURL: "https://www.schachbund.de/spieler/{id}.html",
+		ClubURL:   "https://www.svw.info/vereine/{id}",
+	}, config.Links)
+
+	for _, template := range []string{"https://www.schachbund.de/spieler.html", "/spieler/{id}.html", "ftp://example.org/{id}"} {
+		config.Links.PlayerURL = template
+		assert.EqualError(t, config.Validate(), "links.player_url must be an absolute http(s) URL containing {id}", template)
+	}
+}
+
+func TestLoad_Render(t *testing.T) {
+	clearEnvVars(t)
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "de", config.Render.Locale)
+
+	setEnvVar(t, "PORTAL64_RENDER_LOCALE", "en-GB")
+	config, err = Load("")
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, "en-GB", config.Render.Locale)
+
+	config.Render.Locale = "fr"
+	assert.EqualError(t, config.Validate(), `render.locale: unsupported locale "fr", expected de or en`)
+}
+
+func TestLoad_HTTPTuning(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "MCP_HTTP_WRITE_TIMEOUT", "10m")
+	setEnvVar(t, "MCP_HTTP_MAX_CONNECTIONS", "64")
+	setEnvVar(t, "PORTAL64_MCP_HTTP_REUSE_PORT", "true")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, HTTPConfig{
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      10 * time.Minute,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    1 << 20,
+		MaxConnections:    64,
+		ReusePort:         true,
+		DrainTimeout:      30 * time.Second,
+	}, config.MCP.HTTP)
+	require.NoError(t, config.Validate())
+
+	config.MCP.HTTP.DrainTimeout = -time.Second
+	assert.EqualError(t, config.Validate(), "mcp.http timeouts must not be negative")
+	config.MCP.HTTP.DrainTimeout = 0
+
+	config.MCP.HTTP.IdleTimeout = -time.Second
+	assert.EqualError(t, config.Validate(), "mcp.http timeouts must not be negative")
+
+	config.MCP.HTTP.IdleTimeout = 0
+	config.MCP.HTTP.MaxConnections = -1
+	assert.EqualError(t, config.Validate(), "mcp.http.max_header_bytes and mcp.http.max_connections must not be negative")
+}
+
+func TestLoad_LoadShedding(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MCP_LOAD_SHEDDING_ENABLED", "true")
+	setEnvVar(t, "PORTAL64_MCP_LOAD_SHEDDING_MAX_IN_FLIGHT", "16")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, LoadSheddingConfig{
+		Enabled:     true,
+		MaxInFlight: 16,
+		MaxLatency:  5 * time.Second,
+		RetryAfter:  5 * time.Second,
+	}, config.MCP.LoadShedding)
+	require.NoError(t, config.Validate())
+
+	config.MCP.LoadShedding.MaxInFlight = 0
+	config.MCP.LoadShedding.MaxLatency = 0
+	assert.EqualError(t, config.Validate(), "mcp.load_shedding needs max_in_flight or max_latency when enabled")
+
+	config.MCP.LoadShedding.RetryAfter = -time.Second
+	assert.EqualError(t, config.Validate(), "mcp.load_shedding thresholds must not be negative")
+}
+
+func TestLoad_AggregatesFailureRatio(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, config.MCP.Aggregates.MaxFailureRatio)
+
+	setEnvVar(t, "PORTAL64_MCP_AGGREGATES_MAX_FAILURE_RATIO", "0.2")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, config.MCP.Aggregates.MaxFailureRatio)
+	require.NoError(t, config.Validate())
+
+	config.MCP.Aggregates.MaxFailureRatio = 1.5
+	assert.EqualError(t, config.Validate(), "mcp.aggregates.max_failure_ratio must be between 0 and 1")
+}
+
+func TestLoad_APIConnection(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.True(t, config.API.Connection.HTTP2)
+	assert.True(t, config.API.Connection.KeepAlive)
+	assert.Equal(t, 30*time.Second, config.API.Connection.KeepAliveInterval)
+	assert.Equal(t, 90*time.Second, config.API.Connection.IdleTimeout)
+	assert.Equal(t, 10, config.API.Connection.MaxIdleConnsPerHost)
+
+	setEnvVar(t, "PORTAL64_API_CONNECTION_HTTP2", "false")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.False(t, config.API.Connection.HTTP2)
+	require.NoError(t, config.Validate())
+
+	config.API.Connection.IdleTimeout = -time.Second
+	assert.EqualError(t, config.Validate(), "api.connection.keep_alive_interval, idle_timeout and max_idle_conns_per_host must not be negative")
+}
+
+func TestLoad_Mail(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MAIL_SMTP_HOST", "smtp.example.org")
+	setEnvVar(t, "PORTAL64_MAIL_FROM", "mcp@example.org")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 587, config.Mail.SMTPPort)
+	assert.Equal(t, 30*time.Second, config.Mail.Timeout)
+	assert.EqualError(t, config.Validate(), "mail.from and mail.to are required when mail.smtp_host is set")
+
+	config.Mail.To = "dwz@example.org"
+	require.NoError(t, config.Validate())
+
+	config.Mail.SMTPPort = 0
+	assert.EqualError(t, config.Validate(), "invalid mail.smtp_port: 0 (must be 1-65535)")
+}
+
+func TestLoad_MailDigests(t *testing.T) {
+	clearEnvVars(t)
+
+	configFile := testutil.CreateTempConfigFile(t, `
+mail:
+  smtp_host: "smtp.example.org"
+  from: "mcp@example.org"
+  to: "dwz@example.org"
+  digests:
+    club-c0327:
+      to: ["board@example.org"]
+      players: ["C0327-297"]
+      regions: ["C"]
+`)
+
+	config, err := LoadWithOptions(configFile, LoadOptions{Strict: true})
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, 24*time.Hour, config.Mail.DigestInterval)
+	assert.Equal(t, time.Hour, config.Mail.CheckInterval)
+	assert.Equal(t, 20, config.Mail.MaxPerHour)
+	assert.Equal(t, DigestConfig{To: []string{"board@example.org"}, Players: []string{"C0327-297"}, Regions: []string{"C"}}, config.Mail.Digests["club-c0327"])
+
+	config.Mail.Digests["club-c0327"] = DigestConfig{To: []string{"board@example.org"}}
+	assert.EqualError(t, config.Validate(), "mail.digests.club-c0327 needs players or regions to watch")
+
+	config.Mail.Digests["club-c0327"] = DigestConfig{Regions: []string{"C"}}
+	assert.EqualError(t, config.Validate(), "mail.digests.club-c0327.to is required")
+
+	config.Mail.SMTPHost = ""
+	assert.EqualError(t, config.Validate(), "mail.digests need mail.smtp_host")
+}
+
+func TestLoad_Signing(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MCP_HTTP_SIGNING_ALGORITHM", "ed25519")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.EqualError(t, config.Validate(), "mcp.http.signing.key is required when mcp.http.signing.algorithm is set")
+
+	config.MCP.HTTP.Signing.Key = "c2hvcnQ="
+	assert.EqualError(t, config.Validate(), "invalid mcp.http.signing.key: expected a 32 byte seed or 64 byte private key, got 5 bytes")
+
+	config.MCP.HTTP.Signing.Key = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	require.NoError(t, config.Validate())
+	key, err := ParseEd25519Key(config.MCP.HTTP.Signing.Key)
+	require.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	config.MCP.HTTP.Signing = SigningConfig{Algorithm: "rsa", Key: "secret"}
+	assert.EqualError(t, config.Validate(), "invalid mcp.http.signing.algorithm: rsa (must be hmac-sha256 or ed25519)")
+
+	config.MCP.HTTP.Signing.Algorithm = SigningHMACSHA256
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoad_Scheduling(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MCP_SCHEDULING_MAX_CONCURRENT", "8")
+	setEnvVar(t, "PORTAL64_MCP_SCHEDULING_BATCH_TOOLS", "get_club_statistics,get_team_roster")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, 8, config.MCP.Scheduling.MaxConcurrent)
+	assert.Equal(t, 30*time.Second, config.MCP.Scheduling.QueueTimeout)
+	assert.Equal(t, []string{"get_club_statistics", "get_team_roster"}, config.MCP.Scheduling.BatchTools)
+	assert.Empty(t, config.MCP.Scheduling.InteractiveTools)
+	require.NoError(t, config.Validate())
+
+	config.MCP.Scheduling.QueueTimeout = -time.Second
+	assert.EqualError(t, config.Validate(), "mcp.scheduling.max_concurrent and queue_timeout must not be negative")
+}
+
+func TestLoad_Debug(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MCP_HTTP_DEBUG_ENABLED", "true")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, DebugConfig{Enabled: true}, config.MCP.HTTP.Debug)
+	assert.EqualError(t, config.Validate(), "mcp.http.debug.token is required when mcp.http.debug.enabled is set")
+
+	config.MCP.HTTP.Debug.Token = "secret"
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoad_Admin(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MCP_HTTP_ADMIN_ENABLED", "true")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, AdminConfig{Enabled: true}, config.MCP.HTTP.Admin)
+	assert.EqualError(t, config.Validate(), "mcp.http.admin.token is required when mcp.http.admin.enabled is set")
+
+	config.MCP.HTTP.Admin.Token = "secret"
+	assert.NoError(t, config.Validate())
+}
+
+func TestLoad_ErrorTracking(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.False(t, config.Telemetry.Errors.Enabled())
+	assert.Equal(t, 5, config.Telemetry.Errors.UpstreamBurstThreshold)
+	assert.Equal(t, time.Minute, config.Telemetry.Errors.UpstreamBurstWindow)
+
+	setEnvVar(t, "SENTRY_DSN", "https://public@sentry.example.org/42")
+	setEnvVar(t, "ERROR_TRACKER_ENVIRONMENT", "production")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.True(t, config.Telemetry.Errors.Enabled())
+	assert.Equal(t, "production", config.Telemetry.Errors.Environment)
+	require.NoError(t, config.Validate())
+
+	config.Telemetry.Errors.DSN = "sentry.example.org/42"
+	assert.EqualError(t, config.Validate(), "telemetry.errors.dsn must be a Sentry DSN of the form https://<key>@<host>/<project>")
+
+	config.Telemetry.Errors.DSN = ""
+	config.Telemetry.Errors.UpstreamBurstWindow = -time.Minute
+	assert.Error(t, config.Validate())
+}
+
+func TestLoad_SystemMetrics(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_TELEMETRY_SYSTEM_LOG_DIR", "/var/log/portal64")
+	setEnvVar(t, "PORTAL64_TELEMETRY_SYSTEM_MAX_HEAP_MB", "512")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, SystemMetricsConfig{
+		Interval:  30 * time.Second,
+		LogDir:    "/var/log/portal64",
+		MaxHeapMB: 512,
+	}, config.Telemetry.System)
+	require.NoError(t, config.Validate())
+
+	config.Telemetry.System.MaxGoroutines = -1
+	assert.EqualError(t, config.Validate(), "telemetry.system.interval and thresholds must not be negative")
+}
+
+func TestLoad_SLO(t *testing.T) {
+	clearEnvVars(t)
+
+	configFile := testutil.CreateTempConfigFile(t, `
+api:
+  base_url: "http://localhost:8080"
+telemetry:
+  slo:
+    tools:
+      search_players: {availability: 0.999, latency: "2s"}
+`)
+	config, err := LoadWithOptions(configFile, LoadOptions{Strict: true})
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	slo := config.Telemetry.SLO
+	assert.Equal(t, 24*time.Hour, slo.Window)
+	assert.Equal(t, 0.99, slo.Availability)
+	assert.Equal(t, 5*time.Second, slo.Latency)
+	assert.Equal(t, time.Hour, slo.AlertWindow)
+	assert.Equal(t, 6.0, slo.BurnRateThreshold)
+	assert.Equal(t, SLOTargetConfig{Availability: 0.999, Latency: 2 * time.Second}, slo.Tools["search_players"])
+
+	config.Telemetry.SLO.Tools["search_players"] = SLOTargetConfig{Availability: 1}
+	assert.EqualError(t, config.Validate(), "telemetry.slo.tools.search_players.availability and latency_target must be at least 0 and below 1")
+
+	config.Telemetry.SLO.Tools = nil
+	config.Telemetry.SLO.AlertWindow = 48 * time.Hour
+	assert.EqualError(t, config.Validate(), "telemetry.slo.alert_window must not exceed telemetry.slo.window")
+}
+
+func TestLoad_Alerts(t *testing.T) {
+	clearEnvVars(t)
+
+	setEnvVar(t, "PORTAL64_TELEMETRY_ALERTS_WEBHOOK_URL", "https://alerts.example.org/hook")
+	setEnvVar(t, "PORTAL64_TELEMETRY_ALERTS_EVENTS", "breaker_open,slo_burn")
+	config, err := Load("")
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	alerts := config.Telemetry.Alerts
+	assert.True(t, alerts.Enabled())
+	assert.Equal(t, []string{"breaker_open", "slo_burn"}, alerts.Events)
+	assert.Equal(t, time.Hour, alerts.Cooldown)
+	assert.Equal(t, 14, alerts.CertificateExpiryDays)
+	assert.Equal(t, 10, alerts.UpstreamErrorThreshold)
+	assert.Equal(t, 5*time.Minute, alerts.UpstreamErrorWindow)
+
+	config.Telemetry.Alerts.Events = []string{"disk_full"}
+	assert.ErrorContains(t, config.Validate(), `telemetry.alerts.events: unknown event "disk_full"`)
+
+	config.Telemetry.Alerts.Events = nil
+	config.Telemetry.Alerts.SlackWebhookURL = "hooks.slack.com/services/T0/B0/x"
+	assert.EqualError(t, config.Validate(), "telemetry.alerts.slack_webhook_url must be an http or https URL")
+
+	config.Telemetry.Alerts.SlackWebhookURL = ""
+	config.Telemetry.Alerts.Template = "{{.Summary"
+	assert.ErrorContains(t, config.Validate(), "telemetry.alerts.template")
+}
+
+func TestAPIDebugConfig_Allows(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	debug := config.API.Debug
+	assert.Equal(t, 4096, debug.MaxBodyBytes)
+	assert.True(t, debug.Allows("/health"))
+	assert.True(t, debug.Allows("/api/v1/clubs/C0327/profile"))
+	assert.True(t, debug.Allows("/api/v1/players"))
+	assert.False(t, debug.Allows("/health/details"), "entries without * are exact")
+	assert.False(t, debug.Allows("/api/v1/admin/cache"))
+
+	config.API.Debug.AllowedPaths = []string{"api/v1/*"}
+	assert.EqualError(t, config.Validate(), `api.debug.allowed_paths: "api/v1/*" must start with /`)
+}
+
+func TestLoad_SchemeDetection(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "correct", config.API.SchemeDetection.Mode)
+	assert.Equal(t, []string{"http:8080", "https:8443"}, config.API.SchemeDetection.Candidates)
+	assert.Equal(t, 3*time.Second, config.API.SchemeDetection.Timeout)
+
+	setEnvVar(t, "PORTAL64_API_SCHEME_DETECTION_MODE", "fail")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "fail", config.API.SchemeDetection.Mode)
+	require.NoError(t, config.Validate())
+
+	profile := config.API
+	profile.Profiles = map[string]APIProfileConfig{"test": {BaseURL: "https://test.portal64.example.org"}}
+	test, _ := profile.Profile("test")
+	assert.Equal(t, "fail", test.SchemeDetection.Mode)
+
+	config.API.SchemeDetection.Candidates = []string{"https:8443", "https:65536"}
+	assert.EqualError(t, config.Validate(), `api.scheme_detection.candidates: invalid candidate "https:65536", expected http:<port> or https:<port>`)
+
+	config.API.SchemeDetection.Mode = "warn"
+	assert.EqualError(t, config.Validate(), "api.scheme_detection.mode must be one of: correct, fail, off")
+}
+
+func TestLoad_Auth(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "none", config.API.Auth.Type)
+	assert.Equal(t, "X-API-Key", config.API.Auth.Header)
+
+	setEnvVar(t, "PORTAL64_API_AUTH_TYPE", "oauth2")
+	setEnvVar(t, "PORTAL64_API_AUTH_OAUTH2_TOKEN_URL", "https://auth.example.org/token")
+	setEnvVar(t, "PORTAL64_API_AUTH_OAUTH2_CLIENT_ID", "portal64-mcp")
+	setEnvVar(t, "PORTAL64_API_AUTH_OAUTH2_CLIENT_SECRET", "s3cret")
+	setEnvVar(t, "PORTAL64_API_AUTH_OAUTH2_SCOPES", "read:players,read:clubs")
+	config, err = Load("")
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, []string{"read:players", "read:clubs"}, config.API.Auth.OAuth2.Scopes)
+	assert.Equal(t, maskedValue, config.EffectiveSettings()["api.auth.oauth2.client_secret"])
+
+	config.API.Auth.OAuth2.TokenURL = "/token"
+	assert.EqualError(t, config.Validate(), "api.auth.oauth2.token_url must be an absolute URL for oauth2 authentication")
+
+	config.API.Auth.Type = "basic"
+	assert.EqualError(t, config.Validate(), "api.auth.username is required for basic authentication")
+
+	config.API.Auth.Type = "api_key"
+	config.API.Auth.APIKey = "key"
+	require.NoError(t, config.Validate())
+
+	config.API.Auth.Type = "digest"
+	assert.EqualError(t, config.Validate(), "api.auth.type must be one of: none, api_key, basic, oauth2")
+}
+
+func TestLoad_RequestSigning(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Empty(t, config.API.Signing.Algorithm)
+	assert.Equal(t, "X-Portal64-Signature", config.API.Signing.Header)
+	assert.Equal(t, 30*time.Second, config.API.Signing.ClockSkew)
+
+	setEnvVar(t, "PORTAL64_API_SIGNING_ALGORITHM", "hmac-sha256")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.EqualError(t, config.Validate(), "api.signing.key is required when api.signing.algorithm is set")
+
+	config.API.Signing.Key = "secret"
+	require.NoError(t, config.Validate())
+	assert.Equal(t, maskedValue, config.EffectiveSettings()["api.signing.key"])
+
+	config.API.Signing.Algorithm = "ed25519"
+	assert.EqualError(t, config.Validate(), "invalid api.signing.algorithm: ed25519 (must be hmac-sha256)")
+}
+
+func TestLoad_Cache(t *testing.T) {
+	clearEnvVars(t)
+
+	configFile := testutil.CreateTempConfigFile(t, `
+api:
+  base_url: "http://localhost:8080"
+  cache:
+    policies:
+      players: {ttl: "1m", stale: "0s"}
+`)
+	config, err := LoadWithOptions(configFile, LoadOptions{Strict: true})
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, 10000, config.API.Cache.MaxEntries)
+	assert.Equal(t, APICachePolicyConfig{TTL: time.Minute}, config.API.Cache.Policies.Players)
+	assert.Equal(t, APICachePolicyConfig{TTL: 24 * time.Hour, Stale: 168 * time.Hour}, config.API.Cache.Policies.Regions, "unset policies keep their defaults")
+	assert.Len(t, config.API.Cache.Policies.ByEntity(), 5)
+	assert.Equal(t, time.Minute, config.API.Cache.NotFoundTTL)
+
+	setEnvVar(t, "PORTAL64_API_CACHE_POLICIES_CLUBS_TTL", "0")
+	config, err = Load("")
+	require.NoError(t, err)
+	assert.Zero(t, config.API.Cache.Policies.Clubs.TTL)
+
+	config.API.Cache.Policies.Tournaments.Stale = -time.Minute
+	assert.EqualError(t, config.Validate(), "api.cache.policies.tournaments.ttl and stale must not be negative")
+
+	config.API.Cache.Policies.Tournaments.Stale = 0
+	config.API.Cache.NotFoundTTL = -time.Second
+	assert.EqualError(t, config.Validate(), "api.cache.not_found_ttl must not be negative")
+}
+
+func TestLoad_Maintenance(t *testing.T) {
+	clearEnvVars(t)
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Empty(t, config.API.Maintenance.Windows)
+	assert.Equal(t, "(?i)maintenance|wartung", config.API.Maintenance.Pattern)
+	assert.Equal(t, 10*time.Minute, config.API.Maintenance.Hold)
+
+	setEnvVar(t, "PORTAL64_API_MAINTENANCE_WINDOWS", "Sun 02:00-04:00,daily 23:30-00:15")
+	config, err = Load("")
+	require.NoError(t, err)
+	windows, err := config.API.Maintenance.ParseWindows()
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	assert.Equal(t, "daily 23:30-00:15", windows[1].String())
+
+	config.API.Maintenance.Windows = []string{"Sun 02:00"}
+	assert.ErrorContains(t, config.Validate(), "api.maintenance.windows: invalid maintenance window")
+
+	config.API.Maintenance.Windows = nil
+	config.API.Maintenance.Pattern = "(maintenance"
+	assert.ErrorContains(t, config.Validate(), "api.maintenance.pattern is not a valid regular expression")
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	clearEnvVars(t)
+
+	configFile := testutil.CreateTempConfigFile(t, `
+api:
+  base_url: "https://portal64.example.org"
+  timeout: "20s"
+  ssl:
+    ca_file: "/etc/ssl/portal64.pem"
+  profiles:
+    test:
+      base_url: "https://test.portal64.example.org"
+      fallback_urls: ["https://test-mirror.portal64.example.org"]
+    slow:
+      base_url: "https://slow.portal64.example.org"
+      timeout: "1m"
+`)
+
+	config, err := LoadWithOptions(configFile, LoadOptions{Strict: true})
+	require.NoError(t, err)
+	require.Len(t, config.API.Profiles, 2)
+	require.NoError(t, config.Validate())
+
+	test, ok := config.API.Profile("test")
+	require.True(t, ok)
+	assert.Equal(t, "https://test.portal64.example.org", test.BaseURL)
+	assert.Equal(t, []string{"https://test-mirror.portal64.example.org"}, test.FallbackURLs)
+	assert.Equal(t, 20*time.Second, test.Timeout, "the timeout defaults to api.timeout")
+	assert.Equal(t, "/etc/ssl/portal64.pem", test.SSL.CAFile)
+	assert.Empty(t, test.Profiles)
+
+	slow, _ := config.API.Profile("slow")
+	assert.Equal(t, time.Minute, slow.Timeout)
+
+	_, ok = config.API.Profile("staging")
+	assert.False(t, ok)
+
+	settings := config.EffectiveSettings()
+	assert.Equal(t, "https://test.portal64.example.org", settings["api.profiles.test.base_url"])
+	assert.Equal(t, "1m0s", settings["api.profiles.slow.timeout"])
+}
+
+func TestValidate_Profiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		profiles map[string]APIProfileConfig
+		expected string
+	}{
+		{"reserved name", map[string]APIProfileConfig{"default": {BaseURL: "https://test.example.org"}}, "api.profiles.default: the profile name is reserved for api.base_url"},
+		{"invalid name", map[string]APIProfileConfig{"Test Federation": {BaseURL: "https://test.example.org"}}, "profile names must consist of lowercase letters"},
+		{"missing base URL", map[string]APIProfileConfig{"test": {}}, "api.profiles.test.base_url is required"},
+		{"negative timeout", map[string]APIProfileConfig{"test": {BaseURL: "https://test.example.org", Timeout: -time.Second}}, "api.profiles.test.timeout must not be negative"},
+		{"empty fallback", map[string]APIProfileConfig{"test": {BaseURL: "https://test.example.org", FallbackURLs: []string{""}}}, "api.profiles.test.fallback_urls must not contain empty URLs"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{
+				API: APIConfig{
+					BaseURL:  "https://portal64.example.org",
+					Timeout:  30 * time.Second,
+					Profiles: tc.profiles,
+				},
+				MCP: MCPConfig{
+					Port:     3000,
+					Mode:     "stdio",
+					HTTPPort: 8888,
+				},
+			}
+
+			err := config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestLoad_HiddenTools(t *testing.T) {
+	clearEnvVars(t)
+	setEnvVar(t, "PORTAL64_MCP_TOOLS_HTTP_HIDDEN", "@admin,export_club_data")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@admin", "export_club_data"}, config.MCP.Tools.HTTP.Hidden)
+	assert.Empty(t, config.MCP.Tools.Stdio.Hidden)
+	require.NoError(t, config.Validate())
+
+	config.MCP.Tools.Stdio.Hidden = []string{" "}
+	assert.EqualError(t, config.Validate(), "mcp.tools hidden lists must not contain empty tool names")
+}
+
 func TestLoad_InvalidTimeout(t *testing.T) {
 	clearEnvVars(t)
 	
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Sources of config values
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// Setting is a config key of the effective configuration
+type Setting struct {
+	Key       string      `json:"key"`
+	Value     interface{} `json:"value"`
+	IsDefault bool        `json:"is_default"`
+	// Default is the default value, set if the value differs from it
+	Default interface{} `json:"default,omitempty"`
+	// Source is where the value was set, one of SourceDefault, SourceFile
+	// and SourceEnv. It is empty if the configuration was not loaded.
+	Source string `json:"source,omitempty"`
+}
+
+// SettingChange is a config key whose value differs between two
+// configurations. Values of keys missing in a configuration are nil.
+type SettingChange struct {
+	Key string      `json:"key"`
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// settingSources records whether each config key was set by an environment
+// variable, the config file or defaults. Environment variables take
+// precedence over the config file, as in viper.
+func settingSources(v *viper.Viper) map[string]string {
+	sources := make(map[string]string)
+	for _, field := range configFields() {
+		sources[field.key] = SourceDefault
+		if v.InConfig(field.key) {
+			sources[field.key] = SourceFile
+		}
+		for _, name := range append(append([]string(nil), envAliasesOf(field.key)...), envName(field.key)) {
+			if os.Getenv(name) != "" {
+				sources[field.key] = SourceEnv
+				break
+			}
+		}
+	}
+	return sources
+}
+
+// defaultSettings returns the default configuration as flat config keys and
+// values
+func defaultSettings() map[string]interface{} {
+	v := viper.New()
+	setDefaults(v)
+	var defaults Config
+	if err := v.Unmarshal(&defaults); err != nil {
+		return nil
+	}
+	return defaults.EffectiveSettings()
+}
+
+// EffectiveConfig returns the configuration as config keys sorted by name,
+// with secrets masked as in EffectiveSettings, annotated with their default
+// values and where they were set
+func (c *Config) EffectiveConfig() []Setting {
+	defaults := defaultSettings()
+	settings := make([]Setting, 0, len(defaults))
+	for key, value := range c.EffectiveSettings() {
+		setting := Setting{Key: key, Value: value, Source: c.sources[key]}
+		if c.sources != nil && strings.HasPrefix(key, "api.profiles.") {
+			// Profiles are only configurable in the config file
+			setting.Source = SourceFile
+		}
+		def, ok := defaults[key]
+		setting.IsDefault = ok && reflect.DeepEqual(value, def)
+		if ok && !setting.IsDefault {
+			setting.Default = def
+		}
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// Diff returns the config keys whose values differ in other, sorted by
+// name. Secrets are compared in full but masked in the changes.
+func (c *Config) Diff(other *Config) []SettingChange {
+	oldRaw, newRaw := c.settings(false), other.settings(false)
+	oldMasked, newMasked := c.EffectiveSettings(), other.EffectiveSettings()
+
+	var changes []SettingChange
+	for key := range oldRaw {
+		if newValue, ok := newRaw[key]; !ok || !reflect.DeepEqual(oldRaw[key], newValue) {
+			changes = append(changes, SettingChange{Key: key, Old: oldMasked[key], New: newMasked[key]})
+		}
+	}
+	for key := range newRaw {
+		if _, ok := oldRaw[key]; !ok {
+			changes = append(changes, SettingChange{Key: key, New: newMasked[key]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
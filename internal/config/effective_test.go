@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveConfig_DefaultsAndSources(t *testing.T) {
+	clearEnvVars(t)
+	path := t.TempDir() + "/config.yaml"
+	require.NoError(t, os.WriteFile(path, []byte(`
+api:
+  timeout: "30s"
+  auth:
+    type: api_key
+    api_key: "secret-key"
+  profiles:
+    test:
+      base_url: "https://test.example.org"
+mcp:
+  port: 4000
+`), 0o644))
+	setEnvVar(t, "LOG_LEVEL", "debug")
+
+	config, err := Load(path)
+	require.NoError(t, err)
+
+	settings := make(map[string]Setting)
+	for _, setting := range config.EffectiveConfig() {
+		settings[setting.Key] = setting
+	}
+	assert.Equal(t, Setting{Key: "mcp.port", Value: 4000, Default: 3000, Source: SourceFile}, settings["mcp.port"])
+	assert.Equal(t, Setting{Key: "api.timeout", Value: "30s", IsDefault: true, Source: SourceFile}, settings["api.timeout"],
+		"values set to their default are annotated as default")
+	assert.Equal(t, Setting{Key: "logging.level", Value: "debug", Default: "info", Source: SourceEnv}, settings["logging.level"])
+	assert.Equal(t, Setting{Key: "api.base_url", Value: "http://localhost:8080", IsDefault: true, Source: SourceDefault}, settings["api.base_url"])
+	assert.Equal(t, maskedValue, settings["api.auth.api_key"].Value)
+	assert.Equal(t, SourceFile, settings["api.profiles.test.base_url"].Source)
+
+	assert.Empty(t, (&Config{}).EffectiveConfig()[0].Source, "sources are unknown if the configuration was not loaded")
+}
+
+func TestConfig_Diff(t *testing.T) {
+	running := &Config{
+		API:    APIConfig{BaseURL: "https://portal64.example.org", Timeout: 30 * time.Second, Auth: APIAuthConfig{APIKey: "old-key"}},
+		Export: ExportConfig{SigningKey: "signing-secret"},
+	}
+	loaded := &Config{
+		API: APIConfig{
+			BaseURL:  "https://portal64.example.org",
+			Timeout:  time.Minute,
+			Auth:     APIAuthConfig{APIKey: "new-key"},
+			Profiles: map[string]APIProfileConfig{"test": {BaseURL: "https://test.example.org"}},
+		},
+		Export: ExportConfig{SigningKey: "signing-secret"},
+	}
+
+	changes := running.Diff(loaded)
+	assert.Equal(t, []SettingChange{
+		{Key: "api.auth.api_key", Old: maskedValue, New: maskedValue},
+		{Key: "api.profiles.test.base_url", New: "https://test.example.org"},
+		{Key: "api.profiles.test.fallback_urls", New: ""},
+		{Key: "api.profiles.test.timeout", New: "0s"},
+		{Key: "api.timeout", Old: "30s", New: "1m0s"},
+	}, changes, "changed secrets are reported masked")
+	assert.Empty(t, running.Diff(running))
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/svw-info/portal64gomcp/internal/features"
+)
+
+// envPrefix prefixes the environment variable names derived from config keys
+const envPrefix = "PORTAL64_"
+
+// maskedValue replaces secrets in the effective configuration
+const maskedValue = "****"
+
+// envAliases are the established environment variable names of config
+// keys. They take precedence over the derived PORTAL64_ names.
+var envAliases = map[string][]string{
+	"api.base_url":                   {"PORTAL64_API_URL"},
+	"api.timeout":                    {"API_TIMEOUT"},
+	"api.ssl.ca_file":                {"API_CA_FILE"},
+	"api.ssl.client_cert":            {"API_CLIENT_CERT"},
+	"api.ssl.client_key":             {"API_CLIENT_KEY"},
+	"api.ssl.insecure_skip_verify":   {"API_INSECURE_SKIP_VERIFY"},
+	"mcp.port":                       {"MCP_SERVER_PORT"},
+	"mcp.mode":                       {"MCP_SERVER_MODE"},
+	"mcp.http_port":                  {"MCP_HTTP_PORT"},
+	"mcp.output_format":              {"MCP_OUTPUT_FORMAT"},
+	"mcp.sessions.enabled":           {"MCP_SESSIONS_ENABLED"},
+	"mcp.sessions.ttl":               {"MCP_SESSION_TTL"},
+	"mcp.http.read_timeout":          {"MCP_HTTP_READ_TIMEOUT"},
+	"mcp.http.write_timeout":         {"MCP_HTTP_WRITE_TIMEOUT"},
+	"mcp.http.idle_timeout":          {"MCP_HTTP_IDLE_TIMEOUT"},
+	"mcp.http.max_connections":       {"MCP_HTTP_MAX_CONNECTIONS"},
+	"geocoder.provider":              {"GEOCODER_PROVIDER"},
+	"geocoder.base_url":              {"GEOCODER_URL"},
+	"store.path":                     {"STORE_PATH"},
+	"distributions.refresh_interval": {"DISTRIBUTIONS_REFRESH_INTERVAL"},
+	"distributions.national":         {"DISTRIBUTIONS_NATIONAL"},
+	"export.signing_key":             {"EXPORT_SIGNING_KEY"},
+	"export.url_ttl":                 {"EXPORT_URL_TTL"},
+	"export.base_url":                {"EXPORT_BASE_URL"},
+	"telemetry.errors.dsn":           {"SENTRY_DSN"},
+	"telemetry.errors.endpoint":      {"ERROR_TRACKER_ENDPOINT"},
+	"telemetry.errors.environment":   {"ERROR_TRACKER_ENVIRONMENT"},
+	"telemetry.errors.release":       {"ERROR_TRACKER_RELEASE"},
+	"logging.level":                  {"LOG_LEVEL"},
+}
+
+// EnvVar describes the environment variables of a config key
+type EnvVar struct {
+	Key     string   // Config key, e.g. "mcp.http.read_timeout"
+	Name    string   // Derived name, e.g. "PORTAL64_MCP_HTTP_READ_TIMEOUT"
+	Aliases []string // Established names, taking precedence over Name
+	Type    string
+	Default string
+	Secret  bool // Masked in the logged configuration
+}
+
+// configField is a leaf of the config struct
+type configField struct {
+	key    string
+	index  []int
+	typ    reflect.Type
+	secret bool
+	// mapKey is set for entries of map fields such as features
+	mapKey string
+}
+
+// configFields returns the leaves of the config struct in declaration order.
+// Map fields are expanded to their known keys. Maps of named sections such
+// as api.profiles have no fixed keys and are only configurable in the
+// config file, they are left out.
+func configFields() []configField {
+	var fields []configField
+	var walk func(t reflect.Type, prefix string, index []int)
+	walk = func(t reflect.Type, prefix string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("mapstructure")
+			if tag == "" || tag == "-" {
+				continue
+			}
+			key := prefix + tag
+			fieldIndex := append(append([]int(nil), index...), i)
+
+			switch {
+			case field.Type.Kind() == reflect.Struct:
+				walk(field.Type, key+".", fieldIndex)
+			case field.Type.Kind() == reflect.Map && field.Type.Elem().Kind() == reflect.Struct:
+				continue
+			case field.Type.Kind() == reflect.Map && key == "features":
+				for _, name := range features.Names() {
+					fields = append(fields, configField{key: key + "." + name, index: fieldIndex, typ: field.Type.Elem(), mapKey: name})
+				}
+			default:
+				fields = append(fields, configField{
+					key:    key,
+					index:  fieldIndex,
+					typ:    field.Type,
+					secret: field.Tag.Get("secret") == "true",
+				})
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "", nil)
+	return fields
+}
+
+// envName derives the environment variable name of a config key
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// envAliasesOf returns the established environment variable names of a key
+func envAliasesOf(key string) []string {
+	if name, ok := strings.CutPrefix(key, "features."); ok {
+		return []string{"FEATURE_" + strings.ToUpper(name)}
+	}
+	return envAliases[key]
+}
+
+// bindEnv binds every config key to its environment variables, so that the
+// whole configuration can be given without a config file
+func bindEnv(v *viper.Viper) {
+	for _, field := range configFields() {
+		names := append(append([]string(nil), envAliasesOf(field.key)...), envName(field.key))
+		v.BindEnv(append([]string{field.key}, names...)...)
+	}
+}
+
+// typeName describes a config value type for the environment reference
+func typeName(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		return "duration"
+	case t.Kind() == reflect.Slice:
+		return "comma-separated list"
+	case t.Kind() == reflect.Float64:
+		return "float"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// EnvVars returns the environment variables of all config keys
+func EnvVars() []EnvVar {
+	defaults := viper.New()
+	setDefaults(defaults)
+
+	var vars []EnvVar
+	for _, field := range configFields() {
+		def := ""
+		if value := defaults.Get(field.key); value != nil {
+			def = fmt.Sprint(value)
+		}
+		vars = append(vars, EnvVar{
+			Key:     field.key,
+			Name:    envName(field.key),
+			Aliases: envAliasesOf(field.key),
+			Type:    typeName(field.typ),
+			Default: def,
+			Secret:  field.secret,
+		})
+	}
+	return vars
+}
+
+// WriteEnvReference writes the environment variable reference as a
+// markdown table
+func WriteEnvReference(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Variable | Alias | Config key | Type | Default |\n")
+	b.WriteString("|----------|-------|------------|------|---------|\n")
+	for _, v := range EnvVars() {
+		aliases := make([]string, len(v.Aliases))
+		for i, alias := range v.Aliases {
+			aliases[i] = "`" + alias + "`"
+		}
+		def := ""
+		if v.Default != "" {
+			// Pipes end table cells, also within code spans
+			def = "`" + strings.ReplaceAll(v.Default, "|", `\|`) + "`"
+		}
+		typ := v.Type
+		if v.Secret {
+			typ += " (secret)"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | `%s` | %s | %s |\n", v.Name, strings.Join(aliases, ", "), v.Key, typ, def)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// EffectiveSettings returns the configuration as flat config keys and
+// values for logging. Secrets and passwords in URLs are masked.
+func (c *Config) EffectiveSettings() map[string]interface{} {
+	return c.settings(true)
+}
+
+// settings returns the configuration as flat config keys and values,
+// optionally with secrets masked
+func (c *Config) settings(mask bool) map[string]interface{} {
+	settings := make(map[string]interface{})
+	root := reflect.ValueOf(c).Elem()
+	for _, field := range configFields() {
+		value := root.FieldByIndex(field.index)
+		if field.mapKey != "" {
+			entry := value.MapIndex(reflect.ValueOf(field.mapKey))
+			if !entry.IsValid() {
+				entry = reflect.Zero(field.typ)
+			}
+			value = entry
+		}
+		settings[field.key] = settingValue(value, field.secret, mask)
+	}
+	for name, profile := range c.API.Profiles {
+		prefix := "api.profiles." + name + "."
+		settings[prefix+"base_url"] = settingValue(reflect.ValueOf(profile.BaseURL), false, mask)
+		settings[prefix+"fallback_urls"] = settingValue(reflect.ValueOf(profile.FallbackURLs), false, mask)
+		settings[prefix+"timeout"] = profile.Timeout.String()
+	}
+	return settings
+}
+
+// settingValue formats a config value for logging, masking secrets if mask
+// is set
+func settingValue(value reflect.Value, secret, mask bool) interface{} {
+	maskString := func(s string) string {
+		if mask {
+			return maskURLPassword(s)
+		}
+		return s
+	}
+	switch v := value.Interface().(type) {
+	case string:
+		if v != "" && secret && mask {
+			return maskedValue
+		}
+		return maskString(v)
+	case []string:
+		masked := make([]string, len(v))
+		for i, s := range v {
+			masked[i] = maskString(s)
+		}
+		return strings.Join(masked, ",")
+	case time.Duration:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// maskURLPassword masks the password of a URL with credentials
+func maskURLPassword(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
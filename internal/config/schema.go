@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaEnums lists the allowed values of config keys with a fixed set of values
+var schemaEnums = map[string][]string{
+	"api.auth.type":             {"none", "api_key", "basic", "oauth2"},
+	"api.scheme_detection.mode": {"correct", "fail", "off"},
+	"mcp.mode":                  {"stdio", "http", "both"},
+	"mcp.output_format":         {"envelope", "legacy"},
+	"geocoder.provider":         {"nominatim", "none"},
+	"logging.level":             {"trace", "debug", "info", "warn", "warning", "error", "fatal", "panic"},
+	"logging.format":            {"json", "text"},
+}
+
+// durationPattern matches Go duration strings such as "30s" or "1h30m"
+const durationPattern = `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// Schema is a JSON schema of the configuration, restricted to the
+// keywords the configuration needs
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
+
+// JSONSchema returns the JSON schema of the configuration file. Objects do
+// not allow additional properties, so unknown keys are rejected.
+func JSONSchema() *Schema {
+	defaults := viper.New()
+	setDefaults(defaults)
+
+	root := newObjectSchema()
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	root.Title = "Portal64 MCP server configuration"
+
+	for _, field := range configFields() {
+		parts := strings.Split(field.key, ".")
+		parent := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := parent.Properties[part]
+			if !ok {
+				child = newObjectSchema()
+				parent.Properties[part] = child
+			}
+			parent = child
+		}
+
+		leaf := valueSchema(field.typ)
+		leaf.Description = "Environment: " + strings.Join(append(envAliasesOf(field.key), envName(field.key)), ", ")
+		leaf.Enum = schemaEnums[field.key]
+		if value := defaults.Get(field.key); value != nil {
+			leaf.Default = value
+		}
+		parent.Properties[parts[len(parts)-1]] = leaf
+	}
+
+	root.Properties["api"].Properties["profiles"] = sectionsSchema(reflect.TypeOf(APIProfileConfig{}),
+		"Named Portal64 upstreams selectable per request, configurable in the config file only")
+	root.Properties["mail"].Properties["digests"] = sectionsSchema(reflect.TypeOf(DigestConfig{}),
+		"Named email digests of rating changes and new tournaments, configurable in the config file only")
+	root.Properties["telemetry"].Properties["slo"].Properties["tools"] = sectionsSchema(reflect.TypeOf(SLOTargetConfig{}),
+		"Objectives per tool name overriding the defaults, configurable in the config file only")
+	return root
+}
+
+// sectionsSchema returns the schema of a map of named sections of type t
+func sectionsSchema(t reflect.Type, description string) *Schema {
+	section := newObjectSchema()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		section.Properties[field.Tag.Get("mapstructure")] = valueSchema(field.Type)
+	}
+	sections := newObjectSchema()
+	sections.Description = description
+	sections.PatternProperties = map[string]*Schema{profileNamePattern.String(): section}
+	return sections
+}
+
+func newObjectSchema() *Schema {
+	closed := false
+	return &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: &closed}
+}
+
+// valueSchema returns the schema of a config value type
+func valueSchema(t reflect.Type) *Schema {
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		return &Schema{Type: "string", Pattern: durationPattern}
+	case t.Kind() == reflect.Slice:
+		return &Schema{Type: "array", Items: valueSchema(t.Elem())}
+	case t.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// Check validates a decoded YAML or JSON value against the schema and
+// returns all violations, prefixed with the config key
+func (s *Schema) Check(value interface{}) []error {
+	return s.check(value, "")
+}
+
+func (s *Schema) check(value interface{}, key string) []error {
+	name := key
+	if name == "" {
+		name = "configuration"
+	}
+	if value == nil {
+		return nil
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s must be a mapping", name)}
+		}
+		var errs []error
+		for _, k := range sortedKeys(object) {
+			child := k
+			if key != "" {
+				child = key + "." + k
+			}
+			property, ok := s.Properties[k]
+			if !ok {
+				property, ok = s.patternProperty(k)
+			}
+			if !ok {
+				switch {
+				case len(s.Properties) == 0 && len(s.PatternProperties) > 0:
+					errs = append(errs, fmt.Errorf("invalid name %s, names must match %s", child, strings.Join(sortedKeys(s.PatternProperties), ", ")))
+				case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+					errs = append(errs, fmt.Errorf("unknown key %s, expected one of: %s", child, strings.Join(sortedKeys(s.Properties), ", ")))
+				}
+				continue
+			}
+			errs = append(errs, property.check(object[k], child)...)
+		}
+		return errs
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s must be a list", name)}
+		}
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, s.Items.check(item, fmt.Sprintf("%s[%d]", name, i))...)
+		}
+		return errs
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []error{fmt.Errorf("%s must be a boolean", name)}
+		}
+	case "integer":
+		switch v := value.(type) {
+		case int, int64, uint64:
+		case float64:
+			if v != float64(int64(v)) {
+				return []error{fmt.Errorf("%s must be an integer", name)}
+			}
+		default:
+			return []error{fmt.Errorf("%s must be an integer", name)}
+		}
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			return []error{fmt.Errorf("%s must be a number", name)}
+		}
+	case "string":
+		str, ok := value.(string)
+		if s.Pattern == durationPattern {
+			if _, err := time.ParseDuration(str); !ok || err != nil {
+				return []error{fmt.Errorf("%s must be a duration such as \"30s\" or \"5m\"", name)}
+			}
+		}
+		if !ok {
+			return []error{fmt.Errorf("%s must be a string", name)}
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, str) {
+			return []error{fmt.Errorf("%s must be one of: %s", name, strings.Join(s.Enum, ", "))}
+		}
+	}
+	return nil
+}
+
+// patternProperty returns the schema of the first pattern property matching key
+func (s *Schema) patternProperty(key string) (*Schema, bool) {
+	for _, pattern := range sortedKeys(s.PatternProperties) {
+		if regexp.MustCompile(pattern).MatchString(key) {
+			return s.PatternProperties[pattern], true
+		}
+	}
+	return nil, false
+}
+
+// CheckFile validates a YAML config file against the configuration schema
+func CheckFile(path string) ([]error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+	return JSONSchema().Check(document), nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
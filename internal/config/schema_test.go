@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema_CoversAllKeys(t *testing.T) {
+	schema := JSONSchema()
+
+	for _, v := range EnvVars() {
+		node := schema
+		for _, part := range strings.Split(v.Key, ".") {
+			require.NotNil(t, node.Properties[part], v.Key)
+			node = node.Properties[part]
+		}
+		assert.NotEqual(t, "object", node.Type, v.Key)
+	}
+
+	mcp := schema.Properties["mcp"]
+	assert.Equal(t, []string{"stdio", "http", "both"}, mcp.Properties["mode"].Enum)
+	assert.Equal(t, "integer", mcp.Properties["http_port"].Type)
+	assert.Equal(t, durationPattern, mcp.Properties["http"].Properties["read_timeout"].Pattern)
+	assert.Equal(t, "array", schema.Properties["api"].Properties["fallback_urls"].Type)
+	assert.False(t, *schema.Properties["features"].AdditionalProperties)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"additionalProperties":false`)
+}
+
+func TestCheckFile_RepositoryConfig(t *testing.T) {
+	errs, err := CheckFile("../../config.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCheckFile_Problems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+api:
+  base_url: "http://localhost:8080"
+  timout: "30s"
+  fallback_urls: ["https://a.example.org", 5]
+  profiles:
+    test:
+      base_url: "https://test.example.org"
+      timeout: 30
+    Test Federation:
+      base_url: "https://test.example.org"
+mcp:
+  mode: "htp"
+  http_port: "8888"
+  http:
+    read_timeout: 30
+features:
+  privacy_mod: true
+`), 0o644))
+
+	errs, err := CheckFile(path)
+	require.NoError(t, err)
+
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	assert.ElementsMatch(t, []string{
+		"unknown key api.timout, expected one of: anomalies, auth, base_url, cache, connection, debug, failover, fallback_urls, maintenance, profiles, read_only, scheme_detection, signing, ssl, timeout",
+		"api.fallback_urls[1] must be a string",
+		`api.profiles.test.timeout must be a duration such as "30s" or "5m"`,
+		"invalid name api.profiles.Test Federation, names must match ^[a-z0-9][a-z0-9_-]*$",
+		"mcp.mode must be one of: stdio, http, both",
+		"mcp.http_port must be an integer",
+		`mcp.http.read_timeout must be a duration such as "30s" or "5m"`,
+		"unknown key features.privacy_mod, expected one of: caching, markdown_rendering, privacy_mode, structured_content",
+	}, messages)
+}
+
+func TestLoadWithOptions_Strict(t *testing.T) {
+	clearEnvVars(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("mcp:\n  prot: 4000\n"), 0o644))
+
+	_, err := Load(path)
+	assert.NoError(t, err, "unknown keys are ignored by default")
+
+	_, err = LoadWithOptions(path, LoadOptions{Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prot")
+
+	// Keys bound to environment variables are always known
+	setEnvVar(t, "PORTAL64_MCP_HTTP_MAX_HEADER_BYTES", "4096")
+	require.NoError(t, os.WriteFile(path, []byte("mcp:\n  port: 4000\n"), 0o644))
+	config, err := LoadWithOptions(path, LoadOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, 4000, config.MCP.Port)
+}
+
+func TestJSONSchema_UpToDate(t *testing.T) {
+	expected, err := json.MarshalIndent(JSONSchema(), "", "  ")
+	require.NoError(t, err)
+
+	doc, err := os.ReadFile("../../docs/config.schema.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(doc),
+		"docs/config.schema.json is outdated, regenerate it with: go run ./cmd/server -config-schema > docs/config.schema.json")
+}
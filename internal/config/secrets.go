@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretResolveTimeout bounds resolving all secret references of a config
+const secretResolveTimeout = 10 * time.Second
+
+// SecretResolver resolves references to secrets kept outside the
+// configuration, such as "file:///run/secrets/key" or "env://SIGNING_KEY".
+// The reference is passed without the scheme.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to a SecretResolver
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// ResolveSecret implements SecretResolver
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	secretResolversMu sync.RWMutex
+	secretResolvers   = map[string]SecretResolver{
+		"file": SecretResolverFunc(resolveFileSecret),
+		"env":  SecretResolverFunc(resolveEnvSecret),
+	}
+)
+
+// RegisterSecretResolver makes references with the scheme resolvable in
+// secret config values, e.g. for a secret store such as AWS Secrets Manager.
+// It must be called before the configuration is loaded.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMu.Lock()
+	defer secretResolversMu.Unlock()
+	secretResolvers[scheme] = resolver
+}
+
+// secretResolver returns the resolver of a scheme. Vault is available
+// without registration when VAULT_ADDR is set.
+func secretResolver(scheme string) (SecretResolver, bool) {
+	secretResolversMu.RLock()
+	resolver, ok := secretResolvers[scheme]
+	secretResolversMu.RUnlock()
+	if ok {
+		return resolver, true
+	}
+	if scheme == "vault" && os.Getenv("VAULT_ADDR") != "" {
+		return NewVaultResolver(os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN")), true
+	}
+	return nil, false
+}
+
+// resolveFileSecret reads a secret from a file, e.g. a Docker or Kubernetes
+// secret mount. A trailing newline is removed.
+func resolveFileSecret(ctx context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveEnvSecret reads a secret from an environment variable
+func resolveEnvSecret(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// resolveSecrets replaces secret references in all config fields tagged
+// as secret by the values they refer to. Values that do not start with the
+// scheme of a known resolver are used literally.
+func resolveSecrets(c *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	root := reflect.ValueOf(c).Elem()
+	for _, field := range configFields() {
+		if !field.secret || field.typ.Kind() != reflect.String {
+			continue
+		}
+		value := root.FieldByIndex(field.index)
+		scheme, ref, ok := strings.Cut(value.String(), "://")
+		if !ok {
+			continue
+		}
+		resolver, ok := secretResolver(scheme)
+		if !ok {
+			continue
+		}
+		secret, err := resolver.ResolveSecret(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s reference of %s: %w", scheme, field.key, err)
+		}
+		value.SetString(secret)
+	}
+	return nil
+}
+
+// VaultResolver resolves "vault://<path>#<field>" references by reading
+// the secret at path from a HashiCorp Vault KV engine, e.g.
+// "vault://secret/data/portal64#signing_key" for KV version 2.
+type VaultResolver struct {
+	addr       string
+	token      string
+	httpClient *http.Client
+}
+
+// NewVaultResolver creates a resolver for the Vault server at addr
+func NewVaultResolver(addr, token string) *VaultResolver {
+	return &VaultResolver{
+		addr:       strings.TrimSuffix(addr, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: secretResolveTimeout},
+	}
+}
+
+// ResolveSecret implements SecretResolver
+func (v *VaultResolver) ResolveSecret(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault reference must have the form vault://<path>#<field>")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	// KV version 2 nests the secret data under data.data
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no field %s", path, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_SecretReferences(t *testing.T) {
+	clearEnvVars(t)
+	keyFile := filepath.Join(t.TempDir(), "signing_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("from-file\n"), 0o600))
+
+	setEnvVar(t, "EXPORT_SIGNING_KEY", "file://"+keyFile)
+	setEnvVar(t, "SENTRY_DSN", "env://TEST_SENTRY_DSN")
+	setEnvVar(t, "TEST_SENTRY_DSN", "https://key@sentry.example.org/1")
+	setEnvVar(t, "EXPORT_BASE_URL", "env://NOT_A_SECRET")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", config.Export.SigningKey)
+	assert.Equal(t, "https://key@sentry.example.org/1", config.Telemetry.Errors.DSN)
+	assert.Equal(t, "env://NOT_A_SECRET", config.Export.BaseURL, "only secret fields are resolved")
+}
+
+func TestLoad_SecretReferenceErrors(t *testing.T) {
+	clearEnvVars(t)
+
+	setEnvVar(t, "EXPORT_SIGNING_KEY", "env://TEST_MISSING_SECRET")
+	_, err := Load("")
+	require.Error(t, err)
+	assert.EqualError(t, err, "failed to resolve env reference of export.signing_key: environment variable TEST_MISSING_SECRET is not set")
+
+	setEnvVar(t, "EXPORT_SIGNING_KEY", "file:///nonexistent/signing_key")
+	_, err = Load("")
+	assert.ErrorContains(t, err, "failed to resolve file reference of export.signing_key")
+
+	// Unknown schemes are literal values
+	setEnvVar(t, "EXPORT_SIGNING_KEY", "s3cr3t://value")
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t://value", config.Export.SigningKey)
+}
+
+func TestRegisterSecretResolver(t *testing.T) {
+	clearEnvVars(t)
+	RegisterSecretResolver("test", SecretResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		return "resolved-" + ref, nil
+	}))
+	t.Cleanup(func() {
+		secretResolversMu.Lock()
+		delete(secretResolvers, "test")
+		secretResolversMu.Unlock()
+	})
+
+	setEnvVar(t, "EXPORT_SIGNING_KEY", "test://portal64/signing")
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "resolved-portal64/signing", config.Export.SigningKey)
+}
+
+func TestVaultResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/portal64":
+			w.Write([]byte(`{"data":{"data":{"signing_key":"kv2-secret"},"metadata":{"version":3}}}`))
+		case "/v1/kv/portal64":
+			w.Write([]byte(`{"data":{"signing_key":"kv1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewVaultResolver(server.URL, "token")
+	ctx := context.Background()
+
+	value, err := resolver.ResolveSecret(ctx, "secret/data/portal64#signing_key")
+	require.NoError(t, err)
+	assert.Equal(t, "kv2-secret", value)
+
+	value, err = resolver.ResolveSecret(ctx, "kv/portal64#signing_key")
+	require.NoError(t, err)
+	assert.Equal(t, "kv1-secret", value)
+
+	_, err = resolver.ResolveSecret(ctx, "secret/data/portal64#missing")
+	assert.EqualError(t, err, "vault secret secret/data/portal64 has no field missing")
+
+	_, err = resolver.ResolveSecret(ctx, "secret/data/other#signing_key")
+	assert.EqualError(t, err, "vault returned status 404 for secret/data/other")
+
+	_, err = resolver.ResolveSecret(ctx, "secret/data/portal64")
+	assert.Error(t, err)
+
+	_, err = NewVaultResolver(server.URL, "wrong").ResolveSecret(ctx, "kv/portal64#signing_key")
+	assert.EqualError(t, err, "vault returned status 403 for kv/portal64")
+}
+
+func TestLoad_VaultReferenceFromEnvironment(t *testing.T) {
+	clearEnvVars(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"data":{"dsn":"https://key@sentry.example.org/9"}}}`))
+	}))
+	defer server.Close()
+
+	setEnvVar(t, "VAULT_ADDR", server.URL)
+	setEnvVar(t, "VAULT_TOKEN", "token")
+	setEnvVar(t, "SENTRY_DSN", "vault://secret/data/portal64#dsn")
+
+	config, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://key@sentry.example.org/9", config.Telemetry.Errors.DSN)
+}
@@ -0,0 +1,122 @@
+package dwz
+
+import (
+	"fmt"
+	"math"
+)
+
+// Rating systems
+const (
+	SystemDWZ = "dwz"
+	SystemElo = "elo"
+)
+
+// MethodHeuristic marks conversions as heuristic estimates
+const MethodHeuristic = "heuristic_estimate"
+
+// There is no official conversion between DWZ and Elo. Both use the same
+// logistic scale and agree closely for strong players, while at club level
+// the Elo of a player is usually higher than the DWZ: Elo has a rating floor
+// and fewer rated games of weaker players, and the FIDE compression of 2024
+// raised ratings below 2000. The conversion below is a heuristic estimate
+// that is exact above alignmentRating and lets the Elo exceed the DWZ by
+// a quarter of the distance to it below. Its parameters are chosen by hand,
+// they are neither fitted to rating data nor taken from a published source,
+// so results are labelled with MethodHeuristic.
+const (
+	// alignmentRating is the rating above which DWZ and Elo are treated as equal
+	alignmentRating = 2200
+	// eloSlope is the Elo change per DWZ point below alignmentRating
+	eloSlope = 0.75
+	// eloFloor is the lowest FIDE rating
+	eloFloor = 1400
+	// minRating and maxRating bound the accepted input ratings
+	minRating = 100
+	maxRating = 3000
+)
+
+// ConversionFormulas describe the heuristic of Convert, for
+// explanations of its results
+var ConversionFormulas = []string{
+	fmt.Sprintf("Elo = DWZ from %d, below Elo = %d - %.2f * (%d - DWZ)", alignmentRating, alignmentRating, eloSlope, alignmentRating),
+	fmt.Sprintf("DWZ = Elo from %d, below DWZ = %d - (%d - Elo) / %.2f, at least %d", alignmentRating, alignmentRating, alignmentRating, eloSlope, minRating),
+	fmt.Sprintf("uncertainty = 50 + (%d - rating) / 10 below %d, else 50, with the DWZ as rating", alignmentRating, alignmentRating),
+}
+
+// Caveats of every rating conversion
+var conversionCaveats = []string{
+	"DWZ and Elo are separate rating systems without an official conversion; the result is a heuristic estimate, not fitted to rating data",
+	"Ratings of the same player differ in both systems depending on which tournaments are rated",
+	"Below 2200 the heuristic assumes Elo is higher than DWZ, which does not hold for every player",
+}
+
+// Conversion is a heuristic estimate of a rating in the other system
+type Conversion struct {
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Rating      int      `json:"rating"`
+	Converted   int      `json:"converted"`
+	Method      string   `json:"method"`      // Always MethodHeuristic
+	Uncertainty int      `json:"uncertainty"` // Heuristic margin of the estimate, not measured
+	Caveats     []string `json:"caveats"`
+}
+
+// DWZToElo estimates the Elo of a player rated dwz
+func DWZToElo(dwz int) int {
+	if dwz >= alignmentRating {
+		return dwz
+	}
+	return int(math.Round(alignmentRating - eloSlope*float64(alignmentRating-dwz)))
+}
+
+// EloToDWZ estimates the DWZ of a player rated elo. Results are not
+// lower than minRating.
+func EloToDWZ(elo int) int {
+	if elo >= alignmentRating {
+		return elo
+	}
+	return max(int(math.Round(alignmentRating-float64(alignmentRating-elo)/eloSlope)), minRating)
+}
+
+// conversionUncertainty grows with the distance below alignmentRating
+func conversionUncertainty(rating int) int {
+	if rating >= alignmentRating {
+		return 50
+	}
+	return 50 + (alignmentRating-rating)/10
+}
+
+// Convert estimates a rating in the other system
+func Convert(rating int, from string) (*Conversion, error) {
+	if rating < minRating || rating > maxRating {
+		return nil, fmt.Errorf("rating must be between %d and %d", minRating, maxRating)
+	}
+
+	conversion := &Conversion{
+		From:    from,
+		Rating:  rating,
+		Method:  MethodHeuristic,
+		Caveats: append([]string(nil), conversionCaveats...),
+	}
+	switch from {
+	case SystemDWZ:
+		conversion.To = SystemElo
+		conversion.Converted = DWZToElo(rating)
+		conversion.Uncertainty = conversionUncertainty(rating)
+		if conversion.Converted < eloFloor {
+			conversion.Caveats = append(conversion.Caveats,
+				fmt.Sprintf("The result is below the FIDE rating floor of %d, such a player would not have an Elo", eloFloor))
+		}
+	case SystemElo:
+		conversion.To = SystemDWZ
+		conversion.Converted = EloToDWZ(rating)
+		conversion.Uncertainty = conversionUncertainty(conversion.Converted)
+		if rating < eloFloor {
+			conversion.Caveats = append(conversion.Caveats,
+				fmt.Sprintf("The rating is below the FIDE rating floor of %d", eloFloor))
+		}
+	default:
+		return nil, fmt.Errorf("unknown rating system %q, expected %s or %s", from, SystemDWZ, SystemElo)
+	}
+	return conversion, nil
+}
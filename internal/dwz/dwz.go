@@ -0,0 +1,235 @@
+// Package dwz implements the rating calculation of the German chess
+// federation (DWZ Wertungsordnung) for offline verification of results.
+package dwz
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Player is a participant of a tournament evaluation
+type Player struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	DWZ   int    `json:"dwz"`             // Rating before the tournament, 0 if unrated
+	Index int    `json:"index,omitempty"` // Number of previous evaluations
+	Age   int    `json:"age,omitempty"`   // Age in the evaluation year, 0 if unknown
+}
+
+// Game is a single rated game
+type Game struct {
+	White  string `json:"white"`
+	Black  string `json:"black"`
+	Result string `json:"result"` // "1-0", "0-1", "½-½"; forfeits ("+-", "-+", "--") are not rated
+}
+
+// PlayerResult is the rating change of a single player
+type PlayerResult struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name,omitempty"`
+	OldDWZ           int     `json:"old_dwz"`
+	NewDWZ           int     `json:"new_dwz"`
+	Change           int     `json:"change"`
+	Games            int     `json:"games"`
+	Score            float64 `json:"score"`
+	ExpectedScore    float64 `json:"expected_score"`
+	AverageOpponent  float64 `json:"average_opponent"`
+	Coefficient      float64 `json:"development_coefficient"`
+	AccelerationFact float64 `json:"acceleration_factor"`
+	BrakingValue     float64 `json:"braking_value"`
+	Note             string  `json:"note,omitempty"`
+}
+
+// Result is the evaluation of a whole tournament
+type Result struct {
+	Players      []PlayerResult `json:"players"`
+	RatedGames   int            `json:"rated_games"`
+	IgnoredGames []string       `json:"ignored_games,omitempty"`
+}
+
+// Formulas describe the calculation of Calculate in the notation of the
+// Wertungsordnung, for explanations of its results
+var Formulas = []string{
+	"W_e = sum over the rated games of Φ((R_o - R_opponent) / (200 * √2)), with Φ the standard normal distribution function",
+	"E_0 = (R_o / 1000)^4 + J with J = 5 up to age 20, 10 up to age 25 and 15 otherwise",
+	"E = E_0 * f_B + B, limited to 5..30 (5..150 with a braking value) and to 5 * index; f_B = R_o / 2000 within 0.5..1 for players up to age 20 scoring above W_e, else 1; B = e^((1300 - R_o) / 150) - 1 for R_o < 1300 scoring below W_e, else 0",
+	"R_n = R_o + 800 * (W - W_e) / (E + n), with the score W of n rated games",
+}
+
+// ExpectedScore returns the winning expectancy of a player rated r against
+// an opponent rated opp. The Wertungsordnung takes ratings to be normally
+// distributed with a standard deviation of 200, so the difference of two
+// ratings has one of 200 * √2; its probability table rounds this
+// distribution function to two decimals.
+func ExpectedScore(r, opp int) float64 {
+	// Φ(d / (200√2)) = (1 + erf(d / (200√2 * √2))) / 2
+	return (1 + math.Erf(float64(r-opp)/400)) / 2
+}
+
+// ageSummand returns the age dependent summand J of the base coefficient
+func ageSummand(age int) float64 {
+	switch {
+	case age > 0 && age <= 20:
+		return 5
+	case age > 20 && age <= 25:
+		return 10
+	default:
+		return 15
+	}
+}
+
+// Coefficient returns the development coefficient E and the acceleration
+// factor and braking value it was derived from
+func Coefficient(p Player, score, expected float64) (e, fB, b float64) {
+	r0 := float64(p.DWZ)
+	e0 := math.Pow(r0/1000, 4) + ageSummand(p.Age)
+
+	fB = 1
+	if p.Age > 0 && p.Age <= 20 && score > expected {
+		fB = math.Min(1, math.Max(0.5, r0/2000))
+	}
+
+	if r0 < 1300 && score < expected {
+		b = math.Exp((1300-r0)/150) - 1
+	}
+
+	e = e0*fB + b
+
+	upper := 30.0
+	if b > 0 {
+		upper = 150
+	}
+	if p.Index > 0 {
+		upper = math.Min(upper, 5*float64(p.Index))
+	}
+	e = math.Max(5, math.Min(e, upper))
+
+	return e, fB, b
+}
+
+// parseResult returns the score of white and whether the game is rated
+func parseResult(result string) (float64, bool, error) {
+	switch strings.ReplaceAll(strings.TrimSpace(result), " ", "") {
+	case "1-0", "1:0":
+		return 1, true, nil
+	case "0-1", "0:1":
+		return 0, true, nil
+	case "½-½", "0.5-0.5", "1/2-1/2", "½:½", "remis", "draw":
+		return 0.5, true, nil
+	case "+-", "-+", "--", "+:-", "-:+", "-:-":
+		return 0, false, nil
+	default:
+		return 0, false, fmt.Errorf("unknown result %q", result)
+	}
+}
+
+// Calculate evaluates a tournament. Players without a rating and games
+// against unrated opponents are not evaluated, as the first rating of a
+// player is determined by the rating office from a separate procedure.
+func Calculate(players []Player, games []Game) (*Result, error) {
+	byID := make(map[string]Player, len(players))
+	for _, p := range players {
+		if p.ID == "" {
+			return nil, fmt.Errorf("player id is required")
+		}
+		if _, dup := byID[p.ID]; dup {
+			return nil, fmt.Errorf("duplicate player %s", p.ID)
+		}
+		byID[p.ID] = p
+	}
+
+	type tally struct {
+		games     int
+		score     float64
+		expected  float64
+		opponents int
+	}
+	tallies := make(map[string]*tally, len(players))
+	for _, p := range players {
+		tallies[p.ID] = &tally{}
+	}
+
+	result := &Result{}
+	for i, g := range games {
+		white, ok := byID[g.White]
+		if !ok {
+			return nil, fmt.Errorf("game %d: unknown player %s", i+1, g.White)
+		}
+		black, ok := byID[g.Black]
+		if !ok {
+			return nil, fmt.Errorf("game %d: unknown player %s", i+1, g.Black)
+		}
+		if g.White == g.Black {
+			return nil, fmt.Errorf("game %d: player %s cannot play against themselves", i+1, g.White)
+		}
+
+		whiteScore, rated, err := parseResult(g.Result)
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		if !rated {
+			result.IgnoredGames = append(result.IgnoredGames, fmt.Sprintf("%s-%s: forfeit", g.White, g.Black))
+			continue
+		}
+		if white.DWZ <= 0 || black.DWZ <= 0 {
+			result.IgnoredGames = append(result.IgnoredGames, fmt.Sprintf("%s-%s: unrated player", g.White, g.Black))
+			continue
+		}
+
+		result.RatedGames++
+		for _, side := range []struct {
+			p, opp Player
+			score  float64
+		}{{white, black, whiteScore}, {black, white, 1 - whiteScore}} {
+			t := tallies[side.p.ID]
+			t.games++
+			t.score += side.score
+			t.expected += ExpectedScore(side.p.DWZ, side.opp.DWZ)
+			t.opponents += side.opp.DWZ
+		}
+	}
+
+	for _, p := range players {
+		t := tallies[p.ID]
+		pr := PlayerResult{
+			ID:            p.ID,
+			Name:          p.Name,
+			OldDWZ:        p.DWZ,
+			NewDWZ:        p.DWZ,
+			Games:         t.games,
+			Score:         t.score,
+			ExpectedScore: round(t.expected, 3),
+		}
+
+		switch {
+		case p.DWZ <= 0:
+			pr.Note = "unrated player, first rating is determined by the rating office"
+		case t.games == 0:
+			pr.Note = "no rated games"
+		default:
+			pr.AverageOpponent = round(float64(t.opponents)/float64(t.games), 1)
+			e, fB, b := Coefficient(p, t.score, t.expected)
+			pr.Coefficient = round(e, 2)
+			pr.AccelerationFact = round(fB, 3)
+			pr.BrakingValue = round(b, 2)
+			pr.NewDWZ = int(math.Round(float64(p.DWZ) + 800*(t.score-t.expected)/(e+float64(t.games))))
+			pr.Change = pr.NewDWZ - pr.OldDWZ
+		}
+
+		result.Players = append(result.Players, pr)
+	}
+
+	sort.SliceStable(result.Players, func(i, j int) bool {
+		return result.Players[i].Change > result.Players[j].Change
+	})
+
+	return result, nil
+}
+
+// round rounds v to the given number of decimals
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
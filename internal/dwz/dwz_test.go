@@ -0,0 +1,164 @@
+package dwz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedScore(t *testing.T) {
+	assert.InDelta(t, 0.5, ExpectedScore(1600, 1600), 0.0001)
+	assert.InDelta(t, 1.0, ExpectedScore(1600, 1800)+ExpectedScore(1800, 1600), 0.0001)
+
+	// Winning expectancies by rating difference as in the probability table
+	// of the Wertungsordnung, which rounds to two decimals. The logistic Elo
+	// curve differs from it by up to 0.02, e.g. 0.91 at 400 points.
+	table := map[int]float64{
+		0:   0.50,
+		50:  0.57,
+		100: 0.64,
+		150: 0.70,
+		200: 0.76,
+		300: 0.86,
+		400: 0.92,
+		500: 0.96,
+		600: 0.98,
+	}
+	for difference, expected := range table {
+		assert.InDelta(t, expected, ExpectedScore(1500+difference, 1500), 0.005, "difference %d", difference)
+		assert.InDelta(t, 1-expected, ExpectedScore(1500, 1500+difference), 0.005, "difference -%d", difference)
+	}
+}
+
+func TestCoefficient(t *testing.T) {
+	testCases := []struct {
+		name     string
+		player   Player
+		score    float64
+		expected float64
+		wantE    float64
+		wantFB   float64
+		wantB    float64
+	}{
+		{
+			name:     "adult",
+			player:   Player{DWZ: 1600, Age: 30, Index: 10},
+			score:    1,
+			expected: 0.5,
+			wantE:    21.5536,
+			wantFB:   1,
+		},
+		{
+			name:     "youth overperforming is accelerated",
+			player:   Player{DWZ: 1400, Age: 15, Index: 10},
+			score:    1,
+			expected: 0.5,
+			wantE:    (3.8416 + 5) * 0.7,
+			wantFB:   0.7,
+		},
+		{
+			name:     "low rating underperforming is braked",
+			player:   Player{DWZ: 1000, Age: 40, Index: 10},
+			score:    0,
+			expected: 0.5,
+			wantE:    1 + 15 + 6.389056,
+			wantFB:   1,
+			wantB:    6.389056,
+		},
+		{
+			name:     "few evaluations cap the coefficient",
+			player:   Player{DWZ: 2400, Age: 30, Index: 2},
+			score:    1,
+			expected: 0.5,
+			wantE:    10,
+			wantFB:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, fB, b := Coefficient(tc.player, tc.score, tc.expected)
+			assert.InDelta(t, tc.wantE, e, 0.001)
+			assert.InDelta(t, tc.wantFB, fB, 0.001)
+			assert.InDelta(t, tc.wantB, b, 0.001)
+		})
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	players := []Player{
+		{ID: "A", DWZ: 1600, Age: 30, Index: 10},
+		{ID: "B", DWZ: 1600, Age: 30, Index: 10},
+		{ID: "C", DWZ: 0},
+	}
+	games := []Game{
+		{White: "A", Black: "B", Result: "1-0"},
+		{White: "A", Black: "C", Result: "0-1"},
+		{White: "B", Black: "C", Result: "+-"},
+	}
+
+	result, err := Calculate(players, games)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.RatedGames)
+	assert.Len(t, result.IgnoredGames, 2)
+	require.Len(t, result.Players, 3)
+
+	byID := map[string]PlayerResult{}
+	for _, p := range result.Players {
+		byID[p.ID] = p
+	}
+	assert.Equal(t, 1618, byID["A"].NewDWZ)
+	assert.Equal(t, 18, byID["A"].Change)
+	assert.Equal(t, 1582, byID["B"].NewDWZ)
+	assert.Equal(t, 0, byID["C"].NewDWZ)
+	assert.NotEmpty(t, byID["C"].Note)
+}
+
+func TestCalculate_Errors(t *testing.T) {
+	_, err := Calculate([]Player{{ID: "A", DWZ: 1500}}, []Game{{White: "A", Black: "X", Result: "1-0"}})
+	assert.ErrorContains(t, err, "unknown player X")
+
+	_, err = Calculate([]Player{{ID: "A", DWZ: 1500}, {ID: "B", DWZ: 1500}}, []Game{{White: "A", Black: "B", Result: "2-0"}})
+	assert.ErrorContains(t, err, "unknown result")
+
+	_, err = Calculate([]Player{{ID: "A"}, {ID: "A"}}, nil)
+	assert.ErrorContains(t, err, "duplicate player")
+
+	_, err = Calculate([]Player{{ID: "A", DWZ: 1500}, {ID: "B", DWZ: 1500}}, []Game{{White: "A", Black: "B", Result: "1-0"}, {White: "A", Black: "A", Result: "1-0"}})
+	assert.EqualError(t, err, "game 2: player A cannot play against themselves")
+}
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		rating    int
+		from      string
+		converted int
+	}{
+		{2400, SystemDWZ, 2400},
+		{1600, SystemDWZ, 1750},
+		{1000, SystemDWZ, 1300},
+		{1750, SystemElo, 1600},
+		{2200, SystemElo, 2200},
+		{600, SystemElo, minRating},
+	}
+
+	for _, tc := range testCases {
+		conversion, err := Convert(tc.rating, tc.from)
+		require.NoError(t, err)
+		assert.Equal(t, tc.converted, conversion.Converted, "%s %d", tc.from, tc.rating)
+		assert.NotEqual(t, tc.from, conversion.To)
+		assert.Equal(t, MethodHeuristic, conversion.Method)
+	}
+
+	conversion, err := Convert(1000, SystemDWZ)
+	require.NoError(t, err)
+	assert.Equal(t, 170, conversion.Uncertainty)
+	assert.Len(t, conversion.Caveats, len(conversionCaveats)+1)
+
+	_, err = Convert(1600, "fide")
+	assert.EqualError(t, err, `unknown rating system "fide", expected dwz or elo`)
+	_, err = Convert(5000, SystemDWZ)
+	assert.EqualError(t, err, "rating must be between 100 and 3000")
+}
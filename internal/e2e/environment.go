@@ -0,0 +1,85 @@
+// Package e2e runs the end-to-end test suite against a mock Portal64 API
+// and an MCP server started on ephemeral ports, and analyzes the results.
+package e2e
+
+import (
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/mcp"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+// startTimeout bounds the wait for the MCP server to listen
+const startTimeout = 10 * time.Second
+
+// Environment is a mock Portal64 API and an MCP server using it
+type Environment struct {
+	MockURL   string // Base URL of the mock Portal64 API
+	ServerURL string // Base URL of the MCP server's HTTP bridge
+
+	mock   *httptest.Server
+	server *mcp.Server
+	done   chan error
+}
+
+// Start starts a mock Portal64 API on an ephemeral local port and an MCP
+// server in HTTP mode on the given port, 0 for an ephemeral one. The
+// configuration is changed to use the mock without fallbacks, profiles,
+// persistence or mail, so that the suite does not depend on or affect
+// anything outside the environment.
+func Start(cfg *config.Config, logger *logrus.Logger, port int) (*Environment, error) {
+	env := &Environment{
+		mock: testserver.NewMockPortal64Server(testserver.Config{}).Start(),
+		done: make(chan error, 1),
+	}
+	env.MockURL = env.mock.URL
+
+	cfg.API.BaseURL = env.MockURL
+	cfg.API.FallbackURLs = nil
+	cfg.API.Profiles = nil
+	cfg.MCP.Mode = "http"
+	cfg.MCP.HTTPPort = port
+	cfg.MCP.HTTP.ReusePort = false
+	cfg.Store.Path = ""
+	cfg.Mail.SMTPHost = ""
+
+	apiClient := api.NewClient(cfg.API.BaseURL, cfg.API.Timeout, logger)
+	apiClient.SetReadOnly(cfg.API.ReadOnly)
+	env.server = mcp.NewServer(cfg, logger, apiClient)
+	go func() {
+		env.done <- env.server.Start()
+	}()
+
+	deadline := time.NewTimer(startTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if addr, ok := env.server.HTTPAddr().(*net.TCPAddr); ok {
+			env.ServerURL = fmt.Sprintf("http://127.0.0.1:%d", addr.Port)
+			return env, nil
+		}
+		select {
+		case err := <-env.done:
+			env.mock.Close()
+			return nil, fmt.Errorf("failed to start MCP server: %w", err)
+		case <-deadline.C:
+			env.Close()
+			return nil, fmt.Errorf("MCP server did not listen within %v", startTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the MCP server and the mock API
+func (e *Environment) Close() {
+	e.server.Stop()
+	<-e.done
+	e.mock.Close()
+}
@@ -0,0 +1,224 @@
+package e2e
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TestResultAnalyzer analyzes test results and provides insights
+type TestResultAnalyzer struct {
+	Results []TestResult `json:"results"`
+}
+
+// TestResult represents a single test result
+type TestResult struct {
+	TestName     string        `json:"test_name"`
+	Category     string        `json:"category"`
+	Status       string        `json:"status"` // "PASS", "FAIL", "SKIP"
+	Duration     time.Duration `json:"duration"`
+	ResponseTime time.Duration `json:"response_time,omitempty"`
+	ErrorMessage string        `json:"error_message,omitempty"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+// AnalyzeResults analyzes test results and generates insights
+func (a *TestResultAnalyzer) AnalyzeResults() TestAnalysis {
+	analysis := TestAnalysis{
+		TotalTests:      len(a.Results),
+		Categories:      make(map[string]CategoryStats),
+		SlowTests:       []TestResult{},
+		FailedTestsList: []TestResult{},
+		Recommendations: []string{},
+	}
+
+	var totalResponseTime time.Duration
+	responseTimeCount := 0
+
+	for _, result := range a.Results {
+		// Count by status
+		switch result.Status {
+		case "PASS":
+			analysis.PassedTests++
+		case "FAIL":
+			analysis.FailedTests++
+			analysis.FailedTestsList = append(analysis.FailedTestsList, result)
+		case "SKIP":
+			analysis.SkippedTests++
+		}
+
+		// Category stats
+		stats := analysis.Categories[result.Category]
+		stats.Total++
+		if result.Status == "PASS" {
+			stats.Passed++
+		} else if result.Status == "FAIL" {
+			stats.Failed++
+		}
+		analysis.Categories[result.Category] = stats
+
+		// Response time analysis
+		if result.ResponseTime > 0 {
+			totalResponseTime += result.ResponseTime
+			responseTimeCount++
+
+			// Mark slow tests (> 5 seconds as per strategy)
+			if result.ResponseTime > 5*time.Second {
+				analysis.SlowTests = append(analysis.SlowTests, result)
+			}
+		}
+	}
+
+	// Calculate success rate
+	if analysis.TotalTests > 0 {
+		analysis.SuccessRate = (float64(analysis.PassedTests) / float64(analysis.TotalTests)) * 100
+	}
+
+	// Calculate average response time
+	if responseTimeCount > 0 {
+		analysis.AvgResponseTime = totalResponseTime / time.Duration(responseTimeCount)
+	}
+
+	// Generate recommendations
+	analysis.Recommendations = a.generateRecommendations(analysis)
+
+	return analysis
+}
+
+// TestAnalysis contains the analysis results
+type TestAnalysis struct {
+	TotalTests      int                      `json:"total_tests"`
+	PassedTests     int                      `json:"passed_tests"`
+	FailedTests     int                      `json:"failed_tests"`
+	SkippedTests    int                      `json:"skipped_tests"`
+	SuccessRate     float64                  `json:"success_rate"`
+	AvgResponseTime time.Duration            `json:"avg_response_time"`
+	Categories      map[string]CategoryStats `json:"categories"`
+	SlowTests       []TestResult             `json:"slow_tests"`
+	FailedTestsList []TestResult             `json:"failed_tests_list"`
+	Recommendations []string                 `json:"recommendations"`
+}
+
+// CategoryStats contains statistics for a test category
+type CategoryStats struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// sortedCategories returns the category names in order
+func (a *TestAnalysis) sortedCategories() []string {
+	categories := make([]string, 0, len(a.Categories))
+	for category := range a.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// generateRecommendations generates recommendations based on test results
+func (a *TestResultAnalyzer) generateRecommendations(analysis TestAnalysis) []string {
+	recommendations := []string{}
+
+	// Success rate recommendations
+	if analysis.TotalTests > 0 && analysis.SuccessRate < 95.0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Success rate is %.1f%% - investigate failed tests to improve reliability", analysis.SuccessRate))
+	}
+
+	// Performance recommendations
+	if analysis.AvgResponseTime > 3*time.Second {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Average response time is %v - consider performance optimization", analysis.AvgResponseTime))
+	}
+
+	if len(analysis.SlowTests) > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("%d tests exceeded 5-second response time limit - review performance", len(analysis.SlowTests)))
+	}
+
+	// Category-specific recommendations
+	for _, category := range analysis.sortedCategories() {
+		stats := analysis.Categories[category]
+		if stats.Failed > 0 {
+			failureRate := (float64(stats.Failed) / float64(stats.Total)) * 100
+			if failureRate > 10.0 {
+				recommendations = append(recommendations,
+					fmt.Sprintf("Category '%s' has %.1f%% failure rate - needs attention", category, failureRate))
+			}
+		}
+	}
+
+	// General recommendations
+	if len(analysis.FailedTestsList) > 0 {
+		recommendations = append(recommendations, "Review failed test logs and fix underlying issues")
+	}
+
+	if analysis.SkippedTests > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("%d tests were skipped - ensure test environment is properly configured", analysis.SkippedTests))
+	}
+
+	return recommendations
+}
+
+// percent returns part of total in percent, 0 for no total
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// GenerateDetailedReport generates a detailed markdown report
+func (a *TestAnalysis) GenerateDetailedReport() string {
+	report := fmt.Sprintf(`# Portal64 MCP Server E2E Test Analysis Report
+
+## Summary
+
+- **Total Tests**: %d
+- **Passed**: %d (%.1f%%)
+- **Failed**: %d (%.1f%%)
+- **Skipped**: %d (%.1f%%)
+- **Success Rate**: %.1f%%
+- **Average Response Time**: %v
+
+`, a.TotalTests, a.PassedTests, percent(a.PassedTests, a.TotalTests),
+		a.FailedTests, percent(a.FailedTests, a.TotalTests),
+		a.SkippedTests, percent(a.SkippedTests, a.TotalTests),
+		a.SuccessRate, a.AvgResponseTime)
+
+	// Category breakdown
+	report += "## Category Breakdown\n\n"
+	for _, category := range a.sortedCategories() {
+		stats := a.Categories[category]
+		report += fmt.Sprintf("- **%s**: %d tests, %d passed (%.1f%%)\n",
+			category, stats.Total, stats.Passed, percent(stats.Passed, stats.Total))
+	}
+
+	// Failed tests
+	if len(a.FailedTestsList) > 0 {
+		report += "\n## Failed Tests\n\n"
+		for _, test := range a.FailedTestsList {
+			report += fmt.Sprintf("- **%s**: %s\n", test.TestName, test.ErrorMessage)
+		}
+	}
+
+	// Slow tests
+	if len(a.SlowTests) > 0 {
+		report += "\n## Slow Tests (>5s)\n\n"
+		for _, test := range a.SlowTests {
+			report += fmt.Sprintf("- **%s**: %v\n", test.TestName, test.ResponseTime)
+		}
+	}
+
+	// Recommendations
+	if len(a.Recommendations) > 0 {
+		report += "\n## Recommendations\n\n"
+		for _, rec := range a.Recommendations {
+			report += fmt.Sprintf("- %s\n", rec)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,176 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// BaseURLEnv names the environment variable with the MCP server URL the
+// integration tests run against
+const BaseURLEnv = "PORTAL64_E2E_BASE_URL"
+
+// DefaultPackage is the package of the integration suite, relative to the
+// module root
+const DefaultPackage = "./test/integration"
+
+// Options configures a suite run
+type Options struct {
+	Package  string        // Test package, default DefaultPackage
+	Run      string        // Only run tests matching this pattern (go test -run)
+	Timeout  time.Duration // Timeout of the test binary, default 10m
+	GoBinary string        // Default "go"
+	Output   io.Writer     // Receives the output of the tests, if set
+}
+
+// testEvent is an event of go test -json, see go doc test2json
+type testEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// RunSuite runs the integration suite with go test against the MCP server
+// at baseURL and returns the results of the individual tests. Failing tests
+// are not an error; an error means the suite could not run.
+func RunSuite(ctx context.Context, baseURL string, opts Options) ([]TestResult, error) {
+	if opts.Package == "" {
+		opts.Package = DefaultPackage
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+	if opts.GoBinary == "" {
+		opts.GoBinary = "go"
+	}
+
+	args := []string{"test", "-json", "-count=1", "-timeout", opts.Timeout.String()}
+	if opts.Run != "" {
+		args = append(args, "-run", opts.Run)
+	}
+	args = append(args, opts.Package)
+
+	cmd := exec.CommandContext(ctx, opts.GoBinary, args...)
+	cmd.Env = append(os.Environ(), BaseURLEnv+"="+baseURL)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", opts.GoBinary, err)
+	}
+
+	results, parseErr := parseEvents(stdout, opts.Output)
+	err = cmd.Wait()
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to read test output: %w", parseErr)
+	}
+
+	// go test exits with 1 for failed tests, which the results report
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(results) > 0) {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("go test failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("go test failed: %w", err)
+	}
+	return results, nil
+}
+
+// parseEvents reads go test -json output and returns a result for each
+// test without subtests. The test output is copied to out if set.
+func parseEvents(r io.Reader, out io.Writer) ([]TestResult, error) {
+	var results []TestResult
+	output := make(map[string]*strings.Builder)
+	parents := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// go test prints build errors as plain text
+			if out != nil {
+				fmt.Fprintln(out, scanner.Text())
+			}
+			continue
+		}
+		if event.Action == "output" && out != nil {
+			io.WriteString(out, event.Output)
+		}
+		if event.Test == "" {
+			continue
+		}
+
+		switch event.Action {
+		case "run":
+			if i := strings.LastIndex(event.Test, "/"); i >= 0 {
+				parents[event.Test[:i]] = true
+			}
+		case "output":
+			b, ok := output[event.Test]
+			if !ok {
+				b = &strings.Builder{}
+				output[event.Test] = b
+			}
+			b.WriteString(event.Output)
+		case "pass", "fail", "skip":
+			if parents[event.Test] {
+				// Reported through its subtests
+				continue
+			}
+			elapsed := time.Duration(event.Elapsed * float64(time.Second))
+			result := TestResult{
+				TestName:     event.Test,
+				Category:     category(event.Test),
+				Status:       strings.ToUpper(event.Action),
+				Duration:     elapsed,
+				ResponseTime: elapsed,
+				Timestamp:    event.Time,
+			}
+			if event.Action == "fail" {
+				result.ErrorMessage = failureMessage(output[event.Test])
+			}
+			results = append(results, result)
+		}
+	}
+	return results, scanner.Err()
+}
+
+// category derives the category of a test from its name: the first subtest
+// level for nested tests, such as "Player Tools" of
+// TestE2E_MCPTools/Player_Tools/search_players, the test itself otherwise
+func category(test string) string {
+	parts := strings.Split(test, "/")
+	if len(parts) > 2 {
+		return strings.ReplaceAll(parts[1], "_", " ")
+	}
+	return parts[0]
+}
+
+// failureMessage extracts the messages of a failed test from its output
+func failureMessage(output *strings.Builder) string {
+	if output == nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(output.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" || strings.HasPrefix(line, "=== ") || strings.HasPrefix(line, "--- ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOutput = `# github.com/example/unused
+{"Action":"start","Package":"example.com/integration"}
+{"Action":"run","Package":"example.com/integration","Test":"TestE2E_MCPTools"}
+{"Action":"run","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools"}
+{"Action":"run","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/search_players"}
+{"Action":"pass","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/search_players","Elapsed":0.25}
+{"Action":"run","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/get_player_profile"}
+{"Action":"output","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/get_player_profile","Output":"    e2e_mcp_tools_test.go:42: \n"}
+{"Action":"output","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/get_player_profile","Output":"        \tError:      \tShould be true\n"}
+{"Action":"output","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/get_player_profile","Output":"--- FAIL: TestE2E_MCPTools/Player_Tools/get_player_profile (0.10s)\n"}
+{"Action":"fail","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools/get_player_profile","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/integration","Test":"TestE2E_MCPTools/Player_Tools","Elapsed":0.35}
+{"Action":"fail","Package":"example.com/integration","Test":"TestE2E_MCPTools","Elapsed":0.35}
+{"Action":"run","Package":"example.com/integration","Test":"TestE2E_Performance"}
+{"Action":"skip","Package":"example.com/integration","Test":"TestE2E_Performance","Elapsed":0}
+{"Action":"fail","Package":"example.com/integration","Elapsed":0.4}
+`
+
+func TestParseEvents(t *testing.T) {
+	var out strings.Builder
+	results, err := parseEvents(strings.NewReader(testOutput), &out)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "TestE2E_MCPTools/Player_Tools/search_players", results[0].TestName)
+	assert.Equal(t, "Player Tools", results[0].Category)
+	assert.Equal(t, "PASS", results[0].Status)
+	assert.Equal(t, 250*time.Millisecond, results[0].Duration)
+
+	assert.Equal(t, "FAIL", results[1].Status)
+	assert.Equal(t, "e2e_mcp_tools_test.go:42: Error: Should be true", results[1].ErrorMessage)
+
+	assert.Equal(t, "TestE2E_Performance", results[2].Category)
+	assert.Equal(t, "SKIP", results[2].Status)
+
+	assert.Contains(t, out.String(), "# github.com/example/unused")
+	assert.Contains(t, out.String(), "--- FAIL: TestE2E_MCPTools/Player_Tools/get_player_profile")
+}
+
+func TestAnalyzeResults(t *testing.T) {
+	results, err := parseEvents(strings.NewReader(testOutput), nil)
+	require.NoError(t, err)
+
+	analyzer := &TestResultAnalyzer{Results: results}
+	analysis := analyzer.AnalyzeResults()
+	assert.Equal(t, 3, analysis.TotalTests)
+	assert.Equal(t, 1, analysis.PassedTests)
+	assert.Equal(t, 1, analysis.FailedTests)
+	assert.Equal(t, 1, analysis.SkippedTests)
+	assert.Equal(t, CategoryStats{Total: 2, Passed: 1, Failed: 1}, analysis.Categories["Player Tools"])
+
+	report := analysis.GenerateDetailedReport()
+	assert.Contains(t, report, "- **Total Tests**: 3")
+	assert.Contains(t, report, "- **Player Tools**: 2 tests, 1 passed (50.0%)")
+	assert.Contains(t, report, "## Failed Tests")
+
+	empty := (&TestResultAnalyzer{}).AnalyzeResults()
+	assert.Contains(t, empty.GenerateDetailedReport(), "- **Passed**: 0 (0.0%)")
+}
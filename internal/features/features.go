@@ -0,0 +1,115 @@
+// Package features implements runtime feature flags for experimental
+// behaviors that can be switched per deployment without code changes.
+package features
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Feature flag names
+const (
+	MarkdownRendering = "markdown_rendering"
+	StructuredContent = "structured_content"
+	Caching           = "caching"
+	PrivacyMode       = "privacy_mode"
+)
+
+// descriptions lists the known flags
+var descriptions = map[string]string{
+	MarkdownRendering: "Render tool results as markdown instead of raw JSON",
+	StructuredContent: "Return structured content alongside text tool results",
+	Caching:           "Cache upstream API responses in memory",
+	PrivacyMode:       "Redact personal contact details (phone numbers, street addresses) from tool results",
+}
+
+// Names returns the names of all known flags in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(descriptions))
+	for name := range descriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsKnown reports whether name is a known flag
+func IsKnown(name string) bool {
+	_, ok := descriptions[name]
+	return ok
+}
+
+// Description returns the description of a flag
+func Description(name string) string {
+	return descriptions[name]
+}
+
+// Flag is the state of a single flag
+type Flag struct {
+	Name        string `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description"`
+}
+
+// Flags holds the flag states of a server. It is safe for concurrent use.
+type Flags struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+// New creates flags with the given initial states. Unknown names are
+// ignored, flags without a state are disabled.
+func New(initial map[string]bool) *Flags {
+	f := &Flags{flags: make(map[string]bool, len(descriptions))}
+	for name := range descriptions {
+		f.flags[name] = initial[name]
+	}
+	return f
+}
+
+// Enabled reports whether a flag is enabled. A nil receiver has all flags
+// disabled.
+func (f *Flags) Enabled(name string) bool {
+	if f == nil {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.flags[name]
+}
+
+// Set changes the state of a flag
+func (f *Flags) Set(name string, enabled bool) error {
+	if !IsKnown(name) {
+		return fmt.Errorf("unknown feature flag %q", name)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags[name] = enabled
+	return nil
+}
+
+// States returns the enabled state of every flag
+func (f *Flags) States() map[string]bool {
+	if f == nil {
+		return New(nil).States()
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	states := make(map[string]bool, len(f.flags))
+	for name, enabled := range f.flags {
+		states[name] = enabled
+	}
+	return states
+}
+
+// List returns all flags with their descriptions in alphabetical order
+func (f *Flags) List() []Flag {
+	states := f.States()
+	list := make([]Flag, 0, len(states))
+	for _, name := range Names() {
+		list = append(list, Flag{Name: name, Enabled: states[name], Description: descriptions[name]})
+	}
+	return list
+}
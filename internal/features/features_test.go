@@ -0,0 +1,33 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags(t *testing.T) {
+	flags := New(map[string]bool{PrivacyMode: true, "unknown": true})
+
+	assert.True(t, flags.Enabled(PrivacyMode))
+	assert.False(t, flags.Enabled(Caching))
+	assert.NotContains(t, flags.States(), "unknown")
+
+	require.NoError(t, flags.Set(Caching, true))
+	assert.True(t, flags.Enabled(Caching))
+
+	err := flags.Set("unknown", true)
+	assert.EqualError(t, err, `unknown feature flag "unknown"`)
+
+	list := flags.List()
+	require.Len(t, list, len(Names()))
+	assert.Equal(t, Caching, list[0].Name)
+	assert.True(t, list[0].Enabled)
+	assert.NotEmpty(t, list[0].Description)
+}
+
+func TestFlags_Nil(t *testing.T) {
+	var flags *Flags
+	assert.False(t, flags.Enabled(PrivacyMode))
+}
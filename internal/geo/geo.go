@@ -0,0 +1,136 @@
+// Package geo provides geocoding of place names and distance calculation
+// for location based club searches.
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+// ErrNotFound is returned when a place cannot be geocoded
+var ErrNotFound = errors.New("location not found")
+
+// Location is a geocoded place
+type Location struct {
+	Lat         float64 `json:"lat"`
+	Lon         float64 `json:"lon"`
+	DisplayName string  `json:"display_name,omitempty"`
+	State       string  `json:"state,omitempty"`
+}
+
+// Geocoder resolves a free-form place name or postal code to a location
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*Location, error)
+}
+
+// earthRadiusKm is the mean earth radius used for distance calculation
+const earthRadiusKm = 6371.0
+
+// Distance returns the great-circle distance between two locations in km
+func Distance(a, b Location) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// cacheEntry is a cached geocoding result; a nil location caches a miss
+type cacheEntry struct {
+	location *Location
+	size     int64
+	expires  time.Time
+}
+
+// CachingGeocoder caches results of another geocoder in memory
+type CachingGeocoder struct {
+	next  Geocoder
+	ttl   time.Duration
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+	now   func() time.Time
+}
+
+// NewCachingGeocoder wraps a geocoder with an in-memory cache. Unknown
+// places are cached as well so repeated misses do not hit the provider.
+func NewCachingGeocoder(next Geocoder, ttl time.Duration) *CachingGeocoder {
+	return &CachingGeocoder{
+		next:  next,
+		ttl:   ttl,
+		cache: make(map[string]cacheEntry),
+		now:   time.Now,
+	}
+}
+
+// Geocode implements Geocoder
+func (c *CachingGeocoder) Geocode(ctx context.Context, query string) (*Location, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		if entry.location == nil {
+			return nil, ErrNotFound
+		}
+		return entry.location, nil
+	}
+
+	location, err := c.next.Geocode(ctx, query)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cacheEntry{location: location, size: int64(len(key)) + memory.EstimateSize(location), expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	if location == nil {
+		return nil, ErrNotFound
+	}
+	return location, nil
+}
+
+// MemoryUsage implements memory.Store
+func (c *CachingGeocoder) MemoryUsage() memory.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	usage := memory.Usage{Entries: len(c.cache)}
+	for _, entry := range c.cache {
+		usage.Bytes += entry.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store. Entries expiring first are removed first,
+// which are the ones cached longest ago.
+func (c *CachingGeocoder) Evict(bytes int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.cache))
+	for key := range c.cache {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return c.cache[keys[i]].expires.Before(c.cache[keys[j]].expires) })
+
+	var freed int64
+	for _, key := range keys {
+		if freed >= bytes {
+			break
+		}
+		freed += c.cache[key].size
+		delete(c.cache, key)
+	}
+	return freed
+}
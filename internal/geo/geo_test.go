@@ -0,0 +1,108 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	stuttgart := Location{Lat: 48.7758, Lon: 9.1829}
+	munich := Location{Lat: 48.1351, Lon: 11.5820}
+
+	assert.InDelta(t, 190, Distance(stuttgart, munich), 5)
+	assert.InDelta(t, 0, Distance(stuttgart, stuttgart), 0.001)
+}
+
+type countingGeocoder struct {
+	calls int
+}
+
+func (g *countingGeocoder) Geocode(ctx context.Context, query string) (*Location, error) {
+	g.calls++
+	if query == "Nowhere" {
+		return nil, ErrNotFound
+	}
+	return &Location{Lat: 48.7, Lon: 9.1, DisplayName: query}, nil
+}
+
+func TestCachingGeocoder(t *testing.T) {
+	next := &countingGeocoder{}
+	cache := NewCachingGeocoder(next, time.Hour)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.Geocode(context.Background(), "Stuttgart")
+	require.NoError(t, err)
+	_, err = cache.Geocode(context.Background(), " stuttgart ")
+	require.NoError(t, err)
+	assert.Equal(t, 1, next.calls)
+
+	_, err = cache.Geocode(context.Background(), "Nowhere")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = cache.Geocode(context.Background(), "Nowhere")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 2, next.calls, "misses are cached")
+
+	now = now.Add(2 * time.Hour)
+	_, err = cache.Geocode(context.Background(), "Stuttgart")
+	require.NoError(t, err)
+	assert.Equal(t, 3, next.calls, "expired entries are refreshed")
+}
+
+func TestCachingGeocoder_Evict(t *testing.T) {
+	next := &countingGeocoder{}
+	cache := NewCachingGeocoder(next, time.Hour)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for _, place := range []string{"Stuttgart", "Ulm", "Nowhere"} {
+		cache.Geocode(context.Background(), place)
+		now = now.Add(time.Minute)
+	}
+	usage := cache.MemoryUsage()
+	assert.Equal(t, 3, usage.Entries)
+	assert.Positive(t, usage.Bytes)
+
+	// The entry cached first goes first
+	freed := cache.Evict(1)
+	assert.Positive(t, freed)
+	assert.Equal(t, 2, cache.MemoryUsage().Entries)
+	assert.Equal(t, usage.Bytes-freed, cache.MemoryUsage().Bytes)
+	cache.Geocode(context.Background(), "Ulm")
+	assert.Equal(t, 3, next.calls)
+	cache.Geocode(context.Background(), "Stuttgart")
+	assert.Equal(t, 4, next.calls)
+}
+
+func TestNominatimGeocoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search", r.URL.Path)
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		assert.Equal(t, "de", r.URL.Query().Get("countrycodes"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("q") == "Nowhere" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat":"48.7758","lon":"9.1829","display_name":"Stuttgart, Baden-Württemberg","address":{"state":"Baden-Württemberg"}}]`))
+	}))
+	defer server.Close()
+
+	geocoder := NewNominatimGeocoder(server.URL, "test-agent", 5*time.Second)
+
+	loc, err := geocoder.Geocode(context.Background(), "Stuttgart")
+	require.NoError(t, err)
+	assert.InDelta(t, 48.7758, loc.Lat, 0.0001)
+	assert.InDelta(t, 9.1829, loc.Lon, 0.0001)
+	assert.Equal(t, "Baden-Württemberg", loc.State)
+
+	_, err = geocoder.Geocode(context.Background(), "Nowhere")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// nominatimInterval is the minimum time between requests required by the
+// public Nominatim usage policy
+const nominatimInterval = time.Second
+
+// NominatimGeocoder geocodes places using an OpenStreetMap Nominatim server
+type NominatimGeocoder struct {
+	baseURL     string
+	userAgent   string
+	countryCode string
+	httpClient  *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewNominatimGeocoder creates a Nominatim geocoder restricted to Germany
+func NewNominatimGeocoder(baseURL, userAgent string, timeout time.Duration) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		userAgent:   userAgent,
+		countryCode: "de",
+		httpClient:  &http.Client{Timeout: timeout},
+	}
+}
+
+// nominatimResult is a single search result of the Nominatim API
+type nominatimResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Address     struct {
+		State string `json:"state"`
+	} `json:"address"`
+}
+
+// Geocode implements Geocoder
+func (n *NominatimGeocoder) Geocode(ctx context.Context, query string) (*Location, error) {
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Set("q", query)
+	values.Set("format", "json")
+	values.Set("limit", "1")
+	values.Set("addressdetails", "1")
+	if n.countryCode != "" {
+		values.Set("countrycodes", n.countryCode)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+"/search?"+values.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create geocoding request: %w", err)
+	}
+	req.Header.Set("User-Agent", n.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding request failed with status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q: %w", results[0].Lat, err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q: %w", results[0].Lon, err)
+	}
+
+	return &Location{
+		Lat:         lat,
+		Lon:         lon,
+		DisplayName: results[0].DisplayName,
+		State:       results[0].Address.State,
+	}, nil
+}
+
+// wait enforces the minimum interval between requests
+func (n *NominatimGeocoder) wait(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if delay := nominatimInterval - time.Since(n.last); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	n.last = time.Now()
+	return nil
+}
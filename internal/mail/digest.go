@@ -0,0 +1,271 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/render"
+)
+
+// Kinds of digest notifications
+const (
+	RatingChange  = "rating_change"
+	NewTournament = "new_tournament"
+)
+
+// maxDigestItems bounds the notifications of a single digest message. The
+// rest stays queued for the next digest.
+const maxDigestItems = 100
+
+// Notification is an event reported in a digest
+type Notification struct {
+	Kind   string
+	Title  string // e.g. the player or tournament name
+	Detail string
+	Time   time.Time
+}
+
+// DigestData is passed to the digest templates
+type DigestData struct {
+	Name           string // Name of the digest
+	Since          time.Time
+	Notifications  []Notification
+	RatingChanges  []Notification
+	NewTournaments []Notification
+}
+
+// defaultDigestTemplate renders digests unless a template file is configured
+const defaultDigestTemplate = `{{define "subject"}}Portal64 digest {{.Name}}: {{len .Notifications}} update(s){{end}}
+{{define "body"}}Hello,
+
+these are the updates since {{datetime .Since}}.
+{{with .RatingChanges}}
+Rating changes:
+{{range .}}- {{.Title}}: {{.Detail}}
+{{end}}{{end}}{{with .NewTournaments}}
+New tournaments:
+{{range .}}- {{.Title}}: {{.Detail}}
+{{end}}{{end}}{{end}}`
+
+// ParseDigestTemplate parses the digest template file at path, or the
+// default template if path is empty. The template must define "subject" and
+// "body", executed with DigestData. The formatting functions of the locale
+// (date, datetime, int, decimal, change) are available in the template.
+func ParseDigestTemplate(path string, locale render.Locale) (*template.Template, error) {
+	var tmpl *template.Template
+	var err error
+	if path == "" {
+		tmpl, err = template.New("digest").Funcs(locale.FuncMap()).Parse(defaultDigestTemplate)
+	} else {
+		tmpl, err = template.New(filepath.Base(path)).Funcs(locale.FuncMap()).ParseFiles(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.Lookup("subject") == nil || tmpl.Lookup("body") == nil {
+		return nil, fmt.Errorf("digest template %s must define subject and body", path)
+	}
+	return tmpl, nil
+}
+
+// pendingDigest holds the queued notifications of a digest
+type pendingDigest struct {
+	to    []string
+	since time.Time
+	items []Notification
+}
+
+// Digester batches notifications into digest messages, one per digest and
+// flush. At most maxPerHour messages are sent per hour; digests over the
+// limit stay queued.
+type Digester struct {
+	mailer     Mailer
+	from       string
+	tmpl       *template.Template
+	maxPerHour int
+	logger     *logrus.Logger
+	now        func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*pendingDigest
+	sent    []time.Time // Send times within the last hour
+}
+
+// NewDigester creates a digester sending through mailer. A maxPerHour of 0
+// does not limit the messages.
+func NewDigester(mailer Mailer, from string, tmpl *template.Template, maxPerHour int, logger *logrus.Logger) *Digester {
+	return &Digester{
+		mailer:     mailer,
+		from:       from,
+		tmpl:       tmpl,
+		maxPerHour: maxPerHour,
+		logger:     logger,
+		now:        time.Now,
+		pending:    make(map[string]*pendingDigest),
+	}
+}
+
+// Add queues notifications for the digest name sent to the recipients to
+func (d *Digester) Add(name string, to []string, items ...Notification) {
+	if len(items) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	digest, ok := d.pending[name]
+	if !ok {
+		digest = &pendingDigest{since: d.now()}
+		d.pending[name] = digest
+	}
+	digest.to = to
+	digest.items = append(digest.items, items...)
+}
+
+// Pending returns the number of queued notifications
+func (d *Digester) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, digest := range d.pending {
+		n += len(digest.items)
+	}
+	return n
+}
+
+// digestBatch is a digest taken from the queue for sending
+type digestBatch struct {
+	name string
+	to   []string
+	data DigestData
+}
+
+// take removes the digests that may be sent now from the queue, in the
+// order of their names
+func (d *Digester) take() []digestBatch {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	recent := d.sent[:0]
+	for _, sent := range d.sent {
+		if now.Sub(sent) < time.Hour {
+			recent = append(recent, sent)
+		}
+	}
+	d.sent = recent
+
+	names := make([]string, 0, len(d.pending))
+	for name := range d.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var batches []digestBatch
+	for _, name := range names {
+		if d.maxPerHour > 0 && len(d.sent) >= d.maxPerHour {
+			break
+		}
+		digest := d.pending[name]
+		n := min(len(digest.items), maxDigestItems)
+		batch := digestBatch{name: name, to: digest.to, data: DigestData{Name: name, Since: digest.since}}
+		batch.data.Notifications = append([]Notification(nil), digest.items[:n]...)
+		for _, item := range batch.data.Notifications {
+			switch item.Kind {
+			case RatingChange:
+				batch.data.RatingChanges = append(batch.data.RatingChanges, item)
+			case NewTournament:
+				batch.data.NewTournaments = append(batch.data.NewTournaments, item)
+			}
+		}
+		batches = append(batches, batch)
+		d.sent = append(d.sent, now)
+
+		if n == len(digest.items) {
+			delete(d.pending, name)
+		} else {
+			digest.items = digest.items[n:]
+			digest.since = now
+		}
+	}
+	return batches
+}
+
+// requeue puts the notifications of a batch that failed to send back in
+// front of the queue
+func (d *Digester) requeue(batch digestBatch) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	digest, ok := d.pending[batch.name]
+	if !ok {
+		d.pending[batch.name] = &pendingDigest{to: batch.to, since: batch.data.Since, items: batch.data.Notifications}
+		return
+	}
+	digest.since = batch.data.Since
+	digest.items = append(append([]Notification(nil), batch.data.Notifications...), digest.items...)
+}
+
+// render executes the templates of a digest
+func (d *Digester) render(data DigestData) (subject, body string, err error) {
+	var buf bytes.Buffer
+	if err := d.tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+	buf.Reset()
+	if err := d.tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
+
+// Flush sends the queued digests the rate limit allows and returns the
+// number of messages sent. Digests that fail to send are queued again.
+func (d *Digester) Flush(ctx context.Context) (int, error) {
+	sent := 0
+	var errs []error
+	for _, batch := range d.take() {
+		subject, body, err := d.render(batch.data)
+		if err != nil {
+			// A broken template fails again, so the notifications are dropped
+			errs = append(errs, fmt.Errorf("rendering digest %s: %w", batch.name, err))
+			continue
+		}
+		err = d.mailer.Send(ctx, Message{From: d.from, To: batch.to, Subject: subject, Body: body})
+		if err != nil {
+			d.requeue(batch)
+			errs = append(errs, fmt.Errorf("sending digest %s: %w", batch.name, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// Run flushes the queued digests every interval until ctx is done
+func (d *Digester) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := d.Flush(ctx)
+			if err != nil {
+				d.logger.WithError(err).Warn("Failed to send email digests")
+			}
+			if sent > 0 {
+				d.logger.WithField("messages", sent).Info("Sent email digests")
+			}
+		}
+	}
+}
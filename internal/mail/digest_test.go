@@ -0,0 +1,135 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/render"
+)
+
+// fakeMailer records the messages sent and fails while err is set
+type fakeMailer struct {
+	messages []Message
+	err      error
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func newTestDigester(t *testing.T, mailer Mailer, maxPerHour int) *Digester {
+	tmpl, err := ParseDigestTemplate("", render.German)
+	require.NoError(t, err)
+	return NewDigester(mailer, "mcp@example.org", tmpl, maxPerHour, logrus.New())
+}
+
+func TestDigester_Batches(t *testing.T) {
+	mailer := &fakeMailer{}
+	d := newTestDigester(t, mailer, 0)
+
+	d.Add("club", []string{"board@example.org"}, Notification{Kind: RatingChange, Title: "Alt, Anna", Detail: "DWZ 1850 → 1872"})
+	d.Add("club", []string{"board@example.org"}, Notification{Kind: NewTournament, Title: "Ulmer Open", Detail: "2026-11-01, Ulm"})
+	d.Add("club", []string{"board@example.org"})
+	assert.Equal(t, 2, d.Pending())
+
+	sent, err := d.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Zero(t, d.Pending())
+
+	require.Len(t, mailer.messages, 1)
+	msg := mailer.messages[0]
+	assert.Equal(t, "mcp@example.org", msg.From)
+	assert.Equal(t, "Portal64 digest club: 2 update(s)", msg.Subject)
+	assert.Contains(t, msg.Body, "Rating changes:\n- Alt, Anna: DWZ 1850 → 1872\n")
+	assert.Contains(t, msg.Body, "New tournaments:\n- Ulmer Open: 2026-11-01, Ulm\n")
+
+	sent, err = d.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+func TestDigester_SplitsLargeDigests(t *testing.T) {
+	mailer := &fakeMailer{}
+	d := newTestDigester(t, mailer, 0)
+	for i := 0; i < maxDigestItems+5; i++ {
+		d.Add("club", []string{"board@example.org"}, Notification{Kind: NewTournament, Title: fmt.Sprintf("Open %d", i)})
+	}
+
+	sent, err := d.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 5, d.Pending())
+}
+
+func TestDigester_RateLimit(t *testing.T) {
+	mailer := &fakeMailer{}
+	d := newTestDigester(t, mailer, 2)
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	for _, name := range []string{"a", "b", "c"} {
+		d.Add(name, []string{name + "@example.org"}, Notification{Kind: RatingChange, Title: name})
+	}
+	sent, err := d.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, 1, d.Pending())
+
+	now = now.Add(30 * time.Minute)
+	sent, _ = d.Flush(context.Background())
+	assert.Zero(t, sent)
+
+	now = now.Add(31 * time.Minute)
+	sent, _ = d.Flush(context.Background())
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"c@example.org"}, mailer.messages[2].To)
+}
+
+func TestDigester_RequeuesFailedDigests(t *testing.T) {
+	mailer := &fakeMailer{err: errors.New("connection refused")}
+	d := newTestDigester(t, mailer, 0)
+	d.Add("club", []string{"board@example.org"}, Notification{Kind: RatingChange, Title: "first"})
+
+	sent, err := d.Flush(context.Background())
+	assert.EqualError(t, err, "sending digest club: connection refused")
+	assert.Zero(t, sent)
+
+	d.Add("club", []string{"board@example.org"}, Notification{Kind: RatingChange, Title: "second"})
+	mailer.err = nil
+	sent, err = d.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Regexp(t, `(?s)first.*second`, mailer.messages[0].Body)
+}
+
+func TestParseDigestTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "digest.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{define "subject"}}Updates{{end}}`), 0o600))
+	_, err := ParseDigestTemplate(path, render.German)
+	assert.EqualError(t, err, "digest template "+path+" must define subject and body")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{{define "subject"}}{{.Name}}{{end}}{{define "body"}}{{range .Notifications}}{{.Title}}{{end}}{{end}}`), 0o600))
+	tmpl, err := ParseDigestTemplate(path, render.German)
+	require.NoError(t, err)
+
+	mailer := &fakeMailer{}
+	d := NewDigester(mailer, "mcp@example.org", tmpl, 0, logrus.New())
+	d.Add("club", []string{"board@example.org"}, Notification{Title: "Ulmer Open"})
+	_, err = d.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Message{From: "mcp@example.org", To: []string{"board@example.org"}, Subject: "club", Body: "Ulmer Open"}, mailer.messages[0])
+}
@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts one message and passes the commands and the data
+// it received to the returned channel
+func fakeSMTPServer(t *testing.T) (string, int, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		var lines []string
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch command := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); command {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	mailer := NewSMTPMailer(host, port, "", "", 5*time.Second)
+
+	err := mailer.Send(context.Background(), Message{
+		From:    "mcp@example.org",
+		To:      []string{"dwz@example.org"},
+		Subject: "Korrektur für SK Ulm",
+		Body:    "Präsident: Erika Muster\n",
+	})
+	require.NoError(t, err)
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<mcp@example.org>")
+	assert.Contains(t, lines, "RCPT TO:<dwz@example.org>")
+	assert.Contains(t, lines, "Subject: =?utf-8?q?Korrektur_f=C3=BCr_SK_Ulm?=")
+	assert.Contains(t, lines, "Content-Transfer-Encoding: quoted-printable")
+
+	body := lines[len(lines)-2]
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	require.NoError(t, err)
+	assert.Equal(t, "Präsident: Erika Muster", string(decoded))
+}
+
+func TestMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := Message{From: "a@example.org", To: []string{"b@example.org\r\nBcc: c@example.org"}}.bytes(time.Now())
+	assert.EqualError(t, err, "line break in message header")
+
+	err = NewSMTPMailer("127.0.0.1", 1, "", "", time.Second).Send(context.Background(), Message{From: "a@example.org"})
+	assert.EqualError(t, err, "no recipients")
+}
@@ -0,0 +1,281 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+const (
+	// activityCacheTTL is how long the tournaments of a month are cached
+	activityCacheTTL = 6 * time.Hour
+	// defaultActivityMonths is the number of months up to the current one
+	// covered without a date range
+	defaultActivityMonths = 12
+	// maxActivityMonths bounds the months of a date range
+	maxActivityMonths = 60
+)
+
+// MonthActivity is the tournament activity of a region in one month
+type MonthActivity struct {
+	Month        string `json:"month"` // YYYY-MM
+	Tournaments  int    `json:"tournaments"`
+	Participants int    `json:"participants"`
+}
+
+// RegionActivity is the result of get_region_activity
+type RegionActivity struct {
+	Region       string          `json:"region"`
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Months       []MonthActivity `json:"months"`
+	Tournaments  int             `json:"tournaments"`
+	Participants int             `json:"participants"`
+	BusiestMonth string          `json:"busiest_month,omitempty"` // Month with the most participants
+	// FailedItems are the months whose tournaments failed to load
+	FailedItems []FailedItem `json:"failed_items,omitempty"`
+}
+
+// activityCache caches the tournaments of all regions per month
+type activityCache struct {
+	mu      sync.Mutex
+	entries map[string]activityCacheEntry
+}
+
+type activityCacheEntry struct {
+	tournaments []api.TournamentResponse
+	size        int64
+	expires     time.Time
+}
+
+func (c *activityCache) get(month string) ([]api.TournamentResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[month]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.tournaments, true
+}
+
+func (c *activityCache) put(month string, tournaments []api.TournamentResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]activityCacheEntry)
+	}
+	c.entries[month] = activityCacheEntry{tournaments: tournaments, size: memory.EstimateSize(tournaments), expires: time.Now().Add(activityCacheTTL)}
+}
+
+// activityMonths returns the calendar months from the month of start to the
+// month of end as date range chunks
+func activityMonths(start, end time.Time) []dateChunk {
+	var months []dateChunk
+	first := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, portalLocation)
+	for ; !first.After(end); first = first.AddDate(0, 1, 0) {
+		months = append(months, dateChunk{start: first, end: first.AddDate(0, 1, -1)})
+	}
+	return months
+}
+
+// tournamentParticipants returns the participant count of a tournament
+// from either field of the API
+func tournamentParticipants(t api.TournamentResponse) int {
+	if t.Participants == 0 {
+		return t.ParticipantCount
+	}
+	return t.Participants
+}
+
+// countRegionActivity counts the tournaments of a region and their
+// participants per month of their start. Tournaments without dates, starting
+// outside the months or found in several months are counted once at most.
+func countRegionActivity(months []dateChunk, tournaments []api.TournamentResponse, region string) []MonthActivity {
+	activity := make([]MonthActivity, len(months))
+	index := make(map[string]int, len(months))
+	for i, month := range months {
+		activity[i].Month = month.start.Format("2006-01")
+		index[activity[i].Month] = i
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range tournaments {
+		start := tournamentStart(t)
+		if start.IsZero() || seen[t.ID] || !matchesTournamentRegion(t, region) {
+			continue
+		}
+		i, ok := index[start.In(portalLocation).Format("2006-01")]
+		if !ok {
+			continue
+		}
+		seen[t.ID] = true
+		activity[i].Tournaments++
+		activity[i].Participants += tournamentParticipants(t)
+	}
+	return activity
+}
+
+// searchActivityMonths searches the tournaments of each month concurrently,
+// from the cache where possible. Months whose search fails are left out and
+// listed as failed items, unless more than the allowed share of them fails.
+func (s *Server) searchActivityMonths(ctx context.Context, months []dateChunk) ([]api.TournamentResponse, []FailedItem, error) {
+	results := make([][]api.TournamentResponse, len(months))
+	var failed failedItems
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, dateChunkConcurrency)
+	for i, month := range months {
+		wg.Add(1)
+		go func(i int, month dateChunk) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			key := month.start.Format("2006-01")
+			tournaments, ok := s.activity.get(key)
+			if !ok {
+				var err error
+				if tournaments, err = s.searchDateChunk(ctx, month, api.SearchParams{}); err != nil {
+					failed.add(key, err)
+				} else {
+					s.activity.put(key, tournaments)
+				}
+			}
+			results[i] = tournaments
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, done, len(months), fmt.Sprintf("Searched %d of %d months", done, len(months)), nil)
+			mu.Unlock()
+		}(i, month)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	failedMonths := failed.list()
+	if err := s.checkFailures(ctx, failedMonths, len(months), "monthly tournament searches"); err != nil {
+		return nil, nil, err
+	}
+
+	var tournaments []api.TournamentResponse
+	for _, result := range results {
+		tournaments = append(tournaments, result...)
+	}
+	return tournaments, failedMonths, nil
+}
+
+// regionActivity counts the tournaments and participants of a region per
+// month from the month of start to the month of end
+func (s *Server) regionActivity(ctx context.Context, region string, start, end time.Time) (*RegionActivity, error) {
+	months := activityMonths(start, end)
+	tournaments, failed, err := s.searchActivityMonths(ctx, months)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RegionActivity{
+		Region:      region,
+		From:        months[0].start.Format("2006-01-02"),
+		To:          months[len(months)-1].end.Format("2006-01-02"),
+		Months:      countRegionActivity(months, tournaments, region),
+		FailedItems: failed,
+	}
+	busiest := 0
+	for _, month := range result.Months {
+		result.Tournaments += month.Tournaments
+		result.Participants += month.Participants
+		if month.Participants > busiest {
+			busiest = month.Participants
+			result.BusiestMonth = month.Month
+		}
+	}
+	return result, nil
+}
+
+// activityRangeArgs resolves the date range of a tool searching whole
+// months: the period or start_date and end_date arguments as in
+// dateRangeArgs, by default the last defaultActivityMonths months up to the
+// current one. Ranges of more than maxActivityMonths are shortened to their
+// end. Notes on the range are added as warnings.
+func activityRangeArgs(ctx context.Context, args map[string]interface{}, now time.Time) (start, end time.Time, err error) {
+	today := portalDay(now)
+	start = time.Date(today.Year(), today.Month()-defaultActivityMonths+1, 1, 0, 0, 0, 0, portalLocation)
+	end = today
+	period, _ := args["period"].(string)
+	startArg, _ := args["start_date"].(string)
+	endArg, _ := args["end_date"].(string)
+	if strings.TrimSpace(period+startArg+endArg) != "" {
+		var notes []string
+		if start, end, notes, err = dateRangeArgs(args, now); err != nil {
+			return start, end, err
+		}
+		for _, note := range notes {
+			addWarning(ctx, note)
+		}
+	}
+	if earliest := time.Date(end.Year(), end.Month()-maxActivityMonths+1, 1, 0, 0, 0, 0, portalLocation); start.Before(earliest) {
+		addWarning(ctx, fmt.Sprintf("the range exceeds %d months and was shortened to start on %s", maxActivityMonths, earliest.Format("2006-01-02")))
+		start = earliest
+	}
+	return start, end, nil
+}
+
+// handleGetRegionActivity handles requests for the monthly tournament
+// activity of a region
+func (s *Server) handleGetRegionActivity(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	region, _ := args["region"].(string)
+	region = strings.TrimSpace(region)
+	if region == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: region is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	start, end, err := activityRangeArgs(ctx, args, time.Now())
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result, err := s.regionActivity(ctx, region, start, end)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting region activity: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	explain(ctx,
+		"tournaments and participants are counted in the month of the tournament start",
+		"busiest_month = the month with the most participants",
+	)
+	addCaveat(ctx, "Tournaments without dates are not counted, tournaments found in several months are counted once")
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestActivityMonths(t *testing.T) {
+	start := time.Date(2023, 11, 15, 0, 0, 0, 0, portalLocation)
+	end := time.Date(2024, 2, 3, 0, 0, 0, 0, portalLocation)
+
+	months := activityMonths(start, end)
+	require.Len(t, months, 4)
+	assert.Equal(t, "2023-11-01", months[0].start.Format("2006-01-02"))
+	assert.Equal(t, "2023-11-30", months[0].end.Format("2006-01-02"))
+	assert.Equal(t, "2024-02-01", months[3].start.Format("2006-01-02"))
+	assert.Equal(t, "2024-02-29", months[3].end.Format("2006-01-02"))
+}
+
+func TestCountRegionActivity(t *testing.T) {
+	date := func(s string) *time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return &d
+	}
+	months := activityMonths(time.Date(2024, 1, 1, 0, 0, 0, 0, portalLocation), time.Date(2024, 2, 1, 0, 0, 0, 0, portalLocation))
+	tournaments := []api.TournamentResponse{
+		{ID: "C1", State: "Württemberg", StartDate: date("2024-01-05"), Participants: 40},
+		{ID: "C2", State: "Württemberg", StartDate: date("2024-01-20"), ParticipantCount: 12},
+		// Found again in the search of the next month
+		{ID: "C2", State: "Württemberg", StartDate: date("2024-01-20"), ParticipantCount: 12},
+		{ID: "C3", State: "Württemberg", StartDate: date("2024-02-10"), Participants: 8},
+		{ID: "C4", State: "Württemberg", StartDate: date("2023-12-30"), Participants: 100},
+		{ID: "B1", State: "Bayern", StartDate: date("2024-01-10"), Participants: 30},
+		{ID: "C5", State: "Württemberg"},
+	}
+
+	activity := countRegionActivity(months, tournaments, "württemberg")
+	assert.Equal(t, []MonthActivity{
+		{Month: "2024-01", Tournaments: 2, Participants: 52},
+		{Month: "2024-02", Tournaments: 1, Participants: 8},
+	}, activity)
+}
+
+func TestGetRegionActivity(t *testing.T) {
+	var requests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Query().Get("start_date") {
+		case "2024-01-01":
+			w.Write([]byte(`{"data": [
+				{"id": "C1", "state": "Württemberg", "start_date": "2024-01-05T00:00:00Z", "participants": 40},
+				{"id": "B1", "state": "Bayern", "start_date": "2024-01-06T00:00:00Z", "participants": 30}
+			], "meta": {"total": 2}}`))
+		case "2024-03-01":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.Write([]byte(`{"data": [], "meta": {"total": 0}}`))
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	args := map[string]interface{}{"region": "Württemberg", "start_date": "2024-01-15", "end_date": "2024-03"}
+	result, err := s.handleGetRegionActivity(s.ctx, args)
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var activity RegionActivity
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &activity))
+	assert.Equal(t, "2024-01-01", activity.From)
+	assert.Equal(t, "2024-03-31", activity.To)
+	require.Len(t, activity.Months, 3)
+	assert.Equal(t, 1, activity.Tournaments)
+	assert.Equal(t, 40, activity.Participants)
+	assert.Equal(t, "2024-01", activity.BusiestMonth)
+	require.Len(t, activity.FailedItems, 1)
+	assert.Equal(t, "2024-03", activity.FailedItems[0].ID)
+
+	// Months found before are served from the cache, failed ones searched again
+	searched := atomic.LoadInt32(&requests)
+	_, err = s.handleGetRegionActivity(s.ctx, map[string]interface{}{"region": "B", "period": "2024-Q1"})
+	require.NoError(t, err)
+	assert.Equal(t, searched+1, atomic.LoadInt32(&requests))
+
+	result, err = s.handleGetRegionActivity(s.ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Error: region is required", result.Content[0].Text)
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+// addressBookTTL is how long the address data of all regions is used
+// before it is loaded again on demand. A warm standby is refreshed by its
+// job instead.
+const addressBookTTL = 6 * time.Hour
+
+// addressBook holds the regions and the addresses of their officials in
+// memory. As a warm standby (reference_data.refresh_interval) it is loaded
+// at startup and refreshed periodically, and get_regions,
+// get_region_addresses and search_officials are answered from it without
+// the API; otherwise only search_officials loads it on first use.
+type addressBook struct {
+	mu        sync.Mutex
+	regions   []api.RegionInfo
+	addresses map[string][]api.RegionAddressResponse // By region code
+	officials []Official
+	fetched   time.Time
+	// standby is set while the book is maintained by the refresh job. A
+	// standby is not evicted and does not expire.
+	standby bool
+	// loading serializes loads of the book, which are not done under mu so
+	// that readers are served the previous data meanwhile
+	loading sync.Mutex
+	// size is the estimated size of the book, readable while it is being
+	// loaded
+	size atomic.Int64
+}
+
+// refreshAddressBook loads the regions and the addresses of all regions
+// into the address book. Regions whose addresses fail to load keep those
+// loaded before, if any; the book is left unchanged if the regions fail to
+// load.
+func (s *Server) refreshAddressBook(ctx context.Context) error {
+	s.addresses.loading.Lock()
+	defer s.addresses.loading.Unlock()
+
+	regions, err := s.apiClient.GetRegions(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.addresses.mu.Lock()
+	previous := s.addresses.addresses
+	s.addresses.mu.Unlock()
+
+	addresses := make(map[string][]api.RegionAddressResponse, len(regions))
+	officials := []Official{}
+	for i, region := range regions {
+		records, err := s.apiClient.GetRegionAddresses(ctx, region.Code, "")
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logger.WithError(err).WithField("region", region.Code).Warn("Failed to load region addresses")
+			var ok bool
+			if records, ok = previous[region.Code]; !ok {
+				continue
+			}
+		}
+		addresses[region.Code] = records
+		for _, a := range records {
+			if a.Region == "" {
+				a.Region = region.Code
+			}
+			officials = append(officials, Official{RegionAddressResponse: a, RegionName: region.Name})
+		}
+		reportProgress(ctx, i+1, len(regions), fmt.Sprintf("Loaded addresses of %s", region.Name), nil)
+	}
+
+	s.addresses.mu.Lock()
+	s.addresses.regions, s.addresses.addresses, s.addresses.officials = regions, addresses, officials
+	s.addresses.fetched = time.Now()
+	s.addresses.mu.Unlock()
+	s.addresses.size.Store(memory.EstimateSize(regions) + memory.EstimateSize(addresses) + memory.EstimateSize(officials))
+	return nil
+}
+
+// runAddressBookJob keeps the address book as a warm standby: it loads the
+// book now and then refreshes it from the API, bypassing the response
+// cache, at every interval until the context is done
+func (s *Server) runAddressBookJob(ctx context.Context, interval time.Duration) {
+	s.addresses.mu.Lock()
+	s.addresses.standby = true
+	s.addresses.mu.Unlock()
+
+	refresh := func() {
+		if err := s.refreshAddressBook(api.WithCacheBypass(ctx)); err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Warn("Failed to refresh regions and addresses, serving the data loaded before")
+		}
+	}
+	refresh()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// loadOfficials returns the addresses of all regions from the address
+// book, loading it if it is empty or, unless it is a standby, older than
+// addressBookTTL. The previous data is returned if loading fails.
+func (s *Server) loadOfficials(ctx context.Context) ([]Official, error) {
+	s.addresses.mu.Lock()
+	officials := s.addresses.officials
+	current := officials != nil && (s.addresses.standby || time.Since(s.addresses.fetched) < addressBookTTL)
+	s.addresses.mu.Unlock()
+	if current {
+		return officials, nil
+	}
+
+	if err := s.refreshAddressBook(ctx); err != nil {
+		if officials == nil || ctx.Err() != nil {
+			return nil, err
+		}
+		addWarning(ctx, fmt.Sprintf("address data could not be refreshed, using data loaded at %s: %v", s.addresses.loadedAt().Format(time.RFC3339), err))
+		return officials, nil
+	}
+
+	s.addresses.mu.Lock()
+	defer s.addresses.mu.Unlock()
+	return s.addresses.officials, nil
+}
+
+// loadedAt returns when the address book was loaded
+func (b *addressBook) loadedAt() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.fetched
+}
+
+// standbyRegions returns the regions of a loaded standby, nil otherwise
+func (b *addressBook) standbyRegions() []api.RegionInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.standby || b.regions == nil {
+		return nil
+	}
+	return append([]api.RegionInfo(nil), b.regions...)
+}
+
+// standbyAddresses returns the addresses of a region of a loaded standby
+// by its code, reporting whether they are known
+func (b *addressBook) standbyAddresses(region string) ([]api.RegionAddressResponse, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.standby {
+		return nil, false
+	}
+	for code, records := range b.addresses {
+		if strings.EqualFold(code, region) {
+			return append([]api.RegionAddressResponse(nil), records...), true
+		}
+	}
+	return nil, false
+}
+
+// regions returns the regions from the standby address book, or from the
+// API if there is none
+func (s *Server) regions(ctx context.Context) ([]api.RegionInfo, error) {
+	if regions := s.addresses.standbyRegions(); regions != nil {
+		return regions, nil
+	}
+	return s.apiClient.GetRegions(ctx)
+}
+
+// regionAddresses returns the addresses of a region from the standby
+// address book, or from the API if there is none, the region is not in it
+// or a type is given, as types are filtered by the API
+func (s *Server) regionAddresses(ctx context.Context, region, addressType string) ([]api.RegionAddressResponse, error) {
+	if addressType == "" {
+		if addresses, ok := s.addresses.standbyAddresses(region); ok {
+			return addresses, nil
+		}
+	}
+	return s.apiClient.GetRegionAddresses(ctx, region, addressType)
+}
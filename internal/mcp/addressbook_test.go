@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestAddressBook_Standby(t *testing.T) {
+	var down atomic.Bool
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/addresses/regions":
+			w.Write([]byte(`[{"code":"C","name":"Württemberg"}]`))
+		case "/api/v1/addresses/C":
+			w.Write([]byte(`[{"id":"a1","name":"Maria Schmidt","type":"youth","position":"Jugendwart","email":"jugend@svw.info"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.runAddressBookJob(ctx, time.Hour)
+	require.Eventually(t, func() bool { return s.addresses.standbyRegions() != nil }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// The standby answers while the API is unavailable
+	down.Store(true)
+	for _, call := range []struct {
+		handler ToolHandler
+		args    map[string]interface{}
+		want    string
+	}{
+		{s.handleGetRegions, map[string]interface{}{}, "Württemberg"},
+		{s.handleGetRegionAddresses, map[string]interface{}{"region": "c"}, "Maria Schmidt"},
+		{s.handleSearchOfficials, map[string]interface{}{"role": "youth"}, "jugend@svw.info"},
+	} {
+		result, err := call.handler(context.Background(), call.args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].Text)
+		assert.Contains(t, result.Content[0].Text, call.want)
+	}
+	assert.Equal(t, int32(2), requests.Load(), "no requests reach the API")
+
+	// Address types are filtered by the API
+	result, err := s.handleGetRegionAddresses(context.Background(), map[string]interface{}{"region": "C", "type": "club"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	// A failed refresh keeps the data, which is not evicted either
+	assert.Error(t, s.refreshAddressBook(context.Background()))
+	assert.Zero(t, s.addresses.Evict(1))
+	assert.Len(t, s.addresses.standbyRegions(), 1)
+}
+
+func TestLoadOfficials_StaleOnError(t *testing.T) {
+	var down atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/addresses/regions":
+			w.Write([]byte(`[{"code":"C","name":"Württemberg"}]`))
+		case "/api/v1/addresses/C":
+			w.Write([]byte(`[{"id":"a1","name":"Maria Schmidt"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	officials, err := s.loadOfficials(context.Background())
+	require.NoError(t, err)
+	require.Len(t, officials, 1)
+	assert.Nil(t, s.addresses.standbyRegions(), "books loaded on demand are no standby")
+
+	// Expired data is used with a warning while the API is unavailable
+	down.Store(true)
+	s.addresses.fetched = time.Now().Add(-addressBookTTL)
+	ctx, collector := withWarnings(context.Background())
+	officials, err = s.loadOfficials(ctx)
+	require.NoError(t, err)
+	assert.Len(t, officials, 1)
+	require.Len(t, collector.warnings, 1)
+	assert.Contains(t, collector.warnings[0], "address data could not be refreshed")
+}
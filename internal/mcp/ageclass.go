@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// youthAgeClasses are the youth age classes in ascending order. A player
+// belongs to class U<n> if they are younger than n in the reference year.
+var youthAgeClasses = []int{8, 10, 12, 14, 16, 18, 20}
+
+// seniorAgeClasses are the senior age classes in descending order. A player
+// belongs to class S<n> if they turn n or older in the reference year.
+var seniorAgeClasses = []int{65, 50}
+
+// ageClassNames lists all valid age class filter values
+var ageClassNames = []string{"U8", "U10", "U12", "U14", "U16", "U18", "U20", "S50", "S65", "seniors"}
+
+// ageInYear returns the age a player reaches in the given year
+func ageInYear(birthYear, year int) int {
+	return year - birthYear
+}
+
+// AgeClassOf returns the narrowest age class of a player, "adult" for players
+// in neither a youth nor a senior class and "unknown" without a birth year
+func AgeClassOf(birthYear, year int) string {
+	if birthYear <= 0 {
+		return "unknown"
+	}
+
+	age := ageInYear(birthYear, year)
+	for _, limit := range youthAgeClasses {
+		if age < limit {
+			return fmt.Sprintf("U%d", limit)
+		}
+	}
+	for _, limit := range seniorAgeClasses {
+		if age >= limit {
+			return fmt.Sprintf("S%d", limit)
+		}
+	}
+	return "adult"
+}
+
+// parseAgeClass validates an age class filter and returns its kind and limit
+func parseAgeClass(class string) (senior bool, limit int, err error) {
+	normalized := strings.ToUpper(strings.TrimSpace(class))
+	if normalized == "SENIORS" {
+		return true, 50, nil
+	}
+
+	if len(normalized) < 2 || (normalized[0] != 'U' && normalized[0] != 'S') {
+		return false, 0, fmt.Errorf("invalid age class %q, expected one of %s", class, strings.Join(ageClassNames, ", "))
+	}
+
+	limit, err = strconv.Atoi(normalized[1:])
+	if err != nil || limit <= 0 {
+		return false, 0, fmt.Errorf("invalid age class %q, expected one of %s", class, strings.Join(ageClassNames, ", "))
+	}
+
+	return normalized[0] == 'S', limit, nil
+}
+
+// matchesAgeClass reports whether a player falls into an age class. Youth
+// classes include all younger players, e.g. a U10 player also plays U12.
+func matchesAgeClass(senior bool, limit, birthYear, year int) bool {
+	if birthYear <= 0 {
+		return false
+	}
+
+	age := ageInYear(birthYear, year)
+	if senior {
+		return age >= limit
+	}
+	return age < limit
+}
+
+// filterPlayersByAgeClass returns the players belonging to an age class
+func filterPlayersByAgeClass(players []api.PlayerResponse, class string, year int) ([]api.PlayerResponse, error) {
+	senior, limit, err := parseAgeClass(class)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]api.PlayerResponse, 0, len(players))
+	for _, p := range players {
+		if matchesAgeClass(senior, limit, p.BirthYear, year) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+// referenceYear returns the year used for age classes, taken from the
+// optional "year" argument and defaulting to the current year
+func referenceYear(args map[string]interface{}) int {
+	if year, ok := args["year"].(float64); ok && year > 0 {
+		return int(year)
+	}
+	return time.Now().Year()
+}
+
+// AgeClassStatistics summarizes the members of one age class
+type AgeClassStatistics struct {
+	Count      int     `json:"count"`
+	Rated      int     `json:"rated"`
+	AverageDWZ float64 `json:"average_dwz"`
+	HighestDWZ int     `json:"highest_dwz"`
+}
+
+// ClubYouthStatistics summarizes the youth members of a club
+type ClubYouthStatistics struct {
+	ClubID       string                         `json:"club_id"`
+	Year         int                            `json:"year"`
+	MemberCount  int                            `json:"member_count"`
+	YouthCount   int                            `json:"youth_count"`
+	YouthShare   float64                        `json:"youth_share"`
+	AgeClasses   map[string]*AgeClassStatistics `json:"age_classes"`
+	UnknownCount int                            `json:"unknown_birth_year"`
+}
+
+// computeClubYouthStatistics groups club members by their narrowest age class
+func computeClubYouthStatistics(clubID string, players []api.PlayerResponse, year int) *ClubYouthStatistics {
+	stats := &ClubYouthStatistics{
+		ClubID:      clubID,
+		Year:        year,
+		MemberCount: len(players),
+		AgeClasses:  make(map[string]*AgeClassStatistics),
+	}
+
+	sums := make(map[string]int)
+	for _, p := range players {
+		class := AgeClassOf(p.BirthYear, year)
+		if class == "unknown" {
+			stats.UnknownCount++
+			continue
+		}
+		if !strings.HasPrefix(class, "U") {
+			continue
+		}
+
+		stats.YouthCount++
+		cs, ok := stats.AgeClasses[class]
+		if !ok {
+			cs = &AgeClassStatistics{}
+			stats.AgeClasses[class] = cs
+		}
+		cs.Count++
+		if p.CurrentDWZ > 0 {
+			cs.Rated++
+			sums[class] += p.CurrentDWZ
+			if p.CurrentDWZ > cs.HighestDWZ {
+				cs.HighestDWZ = p.CurrentDWZ
+			}
+		}
+	}
+
+	for class, cs := range stats.AgeClasses {
+		if cs.Rated > 0 {
+			cs.AverageDWZ = float64(sums[class]) / float64(cs.Rated)
+		}
+	}
+	if stats.MemberCount > 0 {
+		stats.YouthShare = float64(stats.YouthCount) / float64(stats.MemberCount)
+	}
+
+	return stats
+}
+
+// handleGetClubYouthStatistics handles club youth statistics requests
+func (s *Server) handleGetClubYouthStatistics(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, ok := args["club_id"].(string)
+	if !ok || clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	players, _, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club players: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result := computeClubYouthStatistics(clubID, players, referenceYear(args))
+	explain(ctx,
+		"age = year - birth year; a member is in the narrowest class U<n> with age < n",
+		"youth_share = youth_count / member_count",
+		"average_dwz of a class = sum of the DWZ of its members with a DWZ / rated",
+	)
+	addCaveat(ctx, "Members without a birth year are counted as unknown_birth_year and belong to no age class")
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// getClubPlayersByAgeClass returns a page of the club members in an age
+// class that match the filter, if any. All members are fetched since the
+// upstream API cannot filter by age.
+func (s *Server) getClubPlayersByAgeClass(ctx context.Context, clubID string, params api.SearchParams, ageClass string, year int, filter *filterExpr) (*CallToolResponse, error) {
+	if _, _, err := parseAgeClass(ageClass); err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	players, _, err := s.fetchAllClubPlayers(ctx, clubID, params)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club players: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	filtered, _ := filterPlayersByAgeClass(players, ageClass, year)
+	if filter != nil {
+		filtered = filterSlice(filter, filtered)
+	}
+
+	total := len(filtered)
+	start := params.Offset
+	if start > total {
+		start = total
+	}
+	end := start + params.Limit
+	if params.Limit <= 0 || end > total {
+		end = total
+	}
+
+	result := &api.SearchResponse{
+		Data: filtered[start:end],
+		Pagination: api.PaginationMetadata{
+			Total:  total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestAgeClassOf(t *testing.T) {
+	testCases := []struct {
+		birthYear int
+		expected  string
+	}{
+		{2018, "U8"},
+		{2017, "U8"},
+		{2016, "U10"},
+		{2011, "U14"},
+		{2005, "U20"},
+		{2004, "adult"},
+		{1974, "S50"},
+		{1959, "S65"},
+		{0, "unknown"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, AgeClassOf(tc.birthYear, 2024), "birth year %d", tc.birthYear)
+	}
+}
+
+func TestFilterPlayersByAgeClass(t *testing.T) {
+	players := []api.PlayerResponse{
+		{ID: "a", BirthYear: 2016},
+		{ID: "b", BirthYear: 2013},
+		{ID: "c", BirthYear: 1990},
+		{ID: "d", BirthYear: 1970},
+		{ID: "e"},
+	}
+
+	u12, err := filterPlayersByAgeClass(players, "u12", 2024)
+	require.NoError(t, err)
+	assert.Len(t, u12, 2, "youth classes include younger players")
+
+	seniors, err := filterPlayersByAgeClass(players, "seniors", 2024)
+	require.NoError(t, err)
+	require.Len(t, seniors, 1)
+	assert.Equal(t, "d", seniors[0].ID)
+
+	_, err = filterPlayersByAgeClass(players, "junior", 2024)
+	assert.Error(t, err)
+}
+
+func TestComputeClubYouthStatistics(t *testing.T) {
+	players := []api.PlayerResponse{
+		{BirthYear: 2015, CurrentDWZ: 1100},
+		{BirthYear: 2015, CurrentDWZ: 1300},
+		{BirthYear: 2009, CurrentDWZ: 0},
+		{BirthYear: 1980, CurrentDWZ: 1900},
+	}
+
+	stats := computeClubYouthStatistics("C0327", players, 2024)
+
+	assert.Equal(t, 4, stats.MemberCount)
+	assert.Equal(t, 3, stats.YouthCount)
+	assert.InDelta(t, 0.75, stats.YouthShare, 0.001)
+	require.Contains(t, stats.AgeClasses, "U10")
+	assert.Equal(t, 2, stats.AgeClasses["U10"].Count)
+	assert.InDelta(t, 1200.0, stats.AgeClasses["U10"].AverageDWZ, 0.001)
+	assert.Equal(t, 1, stats.AgeClasses["U16"].Count)
+	assert.Equal(t, 0, stats.AgeClasses["U16"].Rated)
+}
@@ -0,0 +1,292 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// aggregatePageSize is the page size used when walking all pages of a search
+const aggregatePageSize = 100
+
+// maxAggregatePages bounds the number of pages fetched by aggregate tools
+const maxAggregatePages = 50
+
+// ClubMemberStatistics summarizes all members of a club
+type ClubMemberStatistics struct {
+	ClubID             string         `json:"club_id"`
+	MemberCount        int            `json:"member_count"`
+	ActiveCount        int            `json:"active_count"`
+	PlayersWithDWZ     int            `json:"players_with_dwz"`
+	AverageDWZ         float64        `json:"average_dwz"`
+	HighestDWZ         int            `json:"highest_dwz"`
+	LowestDWZ          int            `json:"lowest_dwz"`
+	GenderDistribution map[string]int `json:"gender_distribution"`
+	RatingDistribution map[string]int `json:"rating_distribution"`
+	PagesProcessed     int            `json:"pages_processed"`
+	Truncated          bool           `json:"truncated,omitempty"` // More members than maxAggregatePages hold
+}
+
+// RegionStatistics summarizes all clubs of a region
+type RegionStatistics struct {
+	Region          string             `json:"region"`
+	ClubCount       int                `json:"club_count"`
+	MemberCount     int                `json:"member_count"`
+	ActiveCount     int                `json:"active_count"`
+	AverageClubSize float64            `json:"average_club_size"`
+	ClubsByCity     map[string]int     `json:"clubs_by_city"`
+	LargestClubs    []api.ClubResponse `json:"largest_clubs"`
+	PagesProcessed  int                `json:"pages_processed"`
+	Truncated       bool               `json:"truncated,omitempty"` // More clubs than maxAggregatePages hold
+}
+
+// pageCount returns the number of pages needed for total items, or 0 if unknown
+func pageCount(total, pageSize int) int {
+	if total <= 0 {
+		return 0
+	}
+	return (total + pageSize - 1) / pageSize
+}
+
+// fetchAllClubPlayers returns all members of a club matching params, and
+// whether they were truncated to maxAggregatePages pages, which adds a
+// warning to the result. Limit and offset of params are ignored.
+// Unfiltered member lists are served from the roster cache.
+func (s *Server) fetchAllClubPlayers(ctx context.Context, clubID string, params api.SearchParams) ([]api.PlayerResponse, bool, error) {
+	params.Limit, params.Offset = 0, 0
+	var players []api.PlayerResponse
+	var truncated bool
+	var err error
+	if params == (api.SearchParams{}) {
+		players, truncated, err = s.clubRoster(ctx, clubID)
+	} else {
+		players, truncated, err = s.walkClubPlayers(ctx, clubID, params)
+	}
+	if truncated {
+		addWarning(ctx, fmt.Sprintf("Club %s has more than %d members, only the first %d are included",
+			clubID, len(players), len(players)))
+	}
+	return players, truncated, err
+}
+
+// walkClubPlayers walks all pages of a club's member list, reporting
+// progress with the aggregate computed so far after each page. It stops
+// after maxAggregatePages pages and reports whether members were left.
+func (s *Server) walkClubPlayers(ctx context.Context, clubID string, params api.SearchParams) ([]api.PlayerResponse, bool, error) {
+	var players []api.PlayerResponse
+
+	for page := 0; page < maxAggregatePages; page++ {
+		params.Limit = aggregatePageSize
+		params.Offset = page * aggregatePageSize
+		result, err := s.apiClient.GetClubPlayers(ctx, clubID, params)
+		if err != nil {
+			return nil, false, err
+		}
+
+		pagePlayers, _ := result.Data.([]api.PlayerResponse)
+		players = append(players, pagePlayers...)
+
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		reportProgress(ctx, page+1, totalPages,
+			fmt.Sprintf("Processed %d members of club %s", len(players), clubID),
+			computeClubMemberStatistics(clubID, players, page+1))
+
+		if len(pagePlayers) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			return players, false, nil
+		}
+	}
+
+	return players, true, nil
+}
+
+// computeClubMemberStatistics aggregates member statistics from a player list
+func computeClubMemberStatistics(clubID string, players []api.PlayerResponse, pages int) *ClubMemberStatistics {
+	stats := &ClubMemberStatistics{
+		ClubID:             clubID,
+		MemberCount:        len(players),
+		GenderDistribution: make(map[string]int),
+		RatingDistribution: make(map[string]int),
+		PagesProcessed:     pages,
+	}
+
+	sum := 0
+	for _, p := range players {
+		if p.Status == "active" || p.Status == "" {
+			stats.ActiveCount++
+		}
+		if p.Gender != "" {
+			stats.GenderDistribution[p.Gender]++
+		}
+		stats.RatingDistribution[ratingBucket(p.CurrentDWZ)]++
+
+		if p.CurrentDWZ <= 0 {
+			continue
+		}
+		stats.PlayersWithDWZ++
+		sum += p.CurrentDWZ
+		if p.CurrentDWZ > stats.HighestDWZ {
+			stats.HighestDWZ = p.CurrentDWZ
+		}
+		if stats.LowestDWZ == 0 || p.CurrentDWZ < stats.LowestDWZ {
+			stats.LowestDWZ = p.CurrentDWZ
+		}
+	}
+
+	if stats.PlayersWithDWZ > 0 {
+		stats.AverageDWZ = float64(sum) / float64(stats.PlayersWithDWZ)
+	}
+
+	return stats
+}
+
+// explainClubMemberStatistics explains the computation of
+// computeClubMemberStatistics for the tool result
+func explainClubMemberStatistics(ctx context.Context) {
+	explain(ctx,
+		"average_dwz = sum of the DWZ of the members with a DWZ / players_with_dwz",
+		"rating_distribution counts the members per 200-point DWZ range from under_1000 to 2200_plus, members without a DWZ as unrated",
+	)
+	addCaveat(ctx, "Members without a status are counted as active")
+}
+
+// ratingBucket returns the 200-point DWZ bucket label for a rating
+func ratingBucket(dwz int) string {
+	switch {
+	case dwz <= 0:
+		return "unrated"
+	case dwz < 1000:
+		return "under_1000"
+	case dwz >= 2200:
+		return "2200_plus"
+	default:
+		low := dwz / 200 * 200
+		return fmt.Sprintf("%d_%d", low, low+199)
+	}
+}
+
+// fetchAllClubs walks all pages of a club search. onPage, if set, is called
+// with the clubs fetched so far after each page. Limit and offset of params
+// are ignored. It stops after maxAggregatePages pages and reports whether
+// clubs were left, which adds a warning to the result.
+func (s *Server) fetchAllClubs(ctx context.Context, params api.SearchParams, onPage func(clubs []api.ClubResponse, page, totalPages int)) ([]api.ClubResponse, bool, error) {
+	var clubs []api.ClubResponse
+
+	for page := 0; page < maxAggregatePages; page++ {
+		params.Limit = aggregatePageSize
+		params.Offset = page * aggregatePageSize
+		result, err := s.apiClient.SearchClubs(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+
+		pageClubs, _ := result.Data.([]api.ClubResponse)
+		clubs = append(clubs, pageClubs...)
+
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		if onPage != nil {
+			onPage(clubs, page+1, totalPages)
+		}
+
+		if len(pageClubs) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			return clubs, false, nil
+		}
+	}
+
+	addWarning(ctx, fmt.Sprintf("More than %d clubs match, only the first %d are included", len(clubs), len(clubs)))
+	return clubs, true, nil
+}
+
+// fetchRegionStatistics walks all clubs of a region and aggregates them
+func (s *Server) fetchRegionStatistics(ctx context.Context, region string) (*RegionStatistics, error) {
+	stats := &RegionStatistics{Region: region}
+
+	params := api.SearchParams{FilterBy: "region", FilterValue: region}
+	_, truncated, err := s.fetchAllClubs(ctx, params, func(clubs []api.ClubResponse, page, totalPages int) {
+		stats = computeRegionStatistics(region, clubs, page)
+		reportProgress(ctx, page, totalPages,
+			fmt.Sprintf("Processed %d clubs of region %s", len(clubs), region), stats)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Truncated = truncated
+	return stats, nil
+}
+
+// computeRegionStatistics aggregates region statistics from a club list
+func computeRegionStatistics(region string, clubs []api.ClubResponse, pages int) *RegionStatistics {
+	stats := &RegionStatistics{
+		Region:         region,
+		ClubCount:      len(clubs),
+		ClubsByCity:    make(map[string]int),
+		PagesProcessed: pages,
+	}
+
+	for _, c := range clubs {
+		stats.MemberCount += c.MemberCount
+		stats.ActiveCount += c.ActiveCount
+		if c.City != "" {
+			stats.ClubsByCity[c.City]++
+		}
+	}
+
+	if stats.ClubCount > 0 {
+		stats.AverageClubSize = float64(stats.MemberCount) / float64(stats.ClubCount)
+	}
+
+	largest := append([]api.ClubResponse(nil), clubs...)
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].MemberCount > largest[j].MemberCount
+	})
+	if len(largest) > 5 {
+		largest = largest[:5]
+	}
+	stats.LargestClubs = largest
+
+	return stats
+}
+
+// handleGetRegionStatistics handles region statistics requests
+func (s *Server) handleGetRegionStatistics(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	region, ok := args["region"].(string)
+	if !ok || region == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: region is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result, err := s.fetchRegionStatistics(ctx, region)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting region statistics: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	explain(ctx,
+		"member_count and active_count = sums of the counts of the club records",
+		"average_club_size = member_count / club_count",
+		"largest_clubs = the 5 clubs with the highest member_count",
+	)
+	addCaveat(ctx, "Member counts are those reported by the API for each club, not recounted from member lists")
+	addCaveat(ctx, fmt.Sprintf("At most %d pages of %d clubs are read", maxAggregatePages, aggregatePageSize))
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestComputeClubMemberStatistics(t *testing.T) {
+	players := []api.PlayerResponse{
+		{ID: "C0327-1", Gender: "male", CurrentDWZ: 1850, Status: "active"},
+		{ID: "C0327-2", Gender: "female", CurrentDWZ: 1420, Status: "active"},
+		{ID: "C0327-3", Gender: "male", CurrentDWZ: 0, Status: "inactive"},
+	}
+
+	stats := computeClubMemberStatistics("C0327", players, 1)
+
+	assert.Equal(t, 3, stats.MemberCount)
+	assert.Equal(t, 2, stats.ActiveCount)
+	assert.Equal(t, 2, stats.PlayersWithDWZ)
+	assert.Equal(t, 1850, stats.HighestDWZ)
+	assert.Equal(t, 1420, stats.LowestDWZ)
+	assert.InDelta(t, 1635.0, stats.AverageDWZ, 0.001)
+	assert.Equal(t, 2, stats.GenderDistribution["male"])
+	assert.Equal(t, 1, stats.RatingDistribution["1800_1999"])
+	assert.Equal(t, 1, stats.RatingDistribution["1400_1599"])
+	assert.Equal(t, 1, stats.RatingDistribution["unrated"])
+}
+
+func TestComputeRegionStatistics(t *testing.T) {
+	clubs := []api.ClubResponse{
+		{ID: "C0327", City: "Altbach", MemberCount: 45, ActiveCount: 38},
+		{ID: "C0505", City: "Böblingen", MemberCount: 62, ActiveCount: 51},
+	}
+
+	stats := computeRegionStatistics("Württemberg", clubs, 1)
+
+	assert.Equal(t, 2, stats.ClubCount)
+	assert.Equal(t, 107, stats.MemberCount)
+	assert.Equal(t, 89, stats.ActiveCount)
+	assert.InDelta(t, 53.5, stats.AverageClubSize, 0.001)
+	require.Len(t, stats.LargestClubs, 2)
+	assert.Equal(t, "C0505", stats.LargestClubs[0].ID)
+}
+
+func TestAggregate_Truncated(t *testing.T) {
+	// Every page is full and more are reported, so only the page cap ends
+	// the walk
+	var requests int32
+	page := make([]string, aggregatePageSize)
+	for i := range page {
+		page[i] = fmt.Sprintf(`{"id": "C%04d", "name": "SV %d", "member_count": 10}`, i, i)
+	}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprintf(w, `{"data": [%s], "pagination": {"total": 100000}}`, strings.Join(page, ","))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.config = &config.Config{Rosters: config.RostersConfig{TTL: time.Hour, MaxAge: time.Hour}}
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, collector := withWarnings(context.Background())
+	stats, err := s.fetchRegionStatistics(ctx, "Württemberg")
+	require.NoError(t, err)
+	assert.True(t, stats.Truncated)
+	assert.Equal(t, maxAggregatePages*aggregatePageSize, stats.ClubCount)
+	assert.EqualValues(t, maxAggregatePages, atomic.LoadInt32(&requests))
+	assert.Equal(t, []string{"More than 5000 clubs match, only the first 5000 are included"}, collector.warnings)
+
+	// A truncated roster stays truncated when served from the cache
+	for i := 0; i < 2; i++ {
+		ctx, collector := withWarnings(context.Background())
+		players, truncated, err := s.fetchAllClubPlayers(ctx, "C0327", api.SearchParams{})
+		require.NoError(t, err)
+		assert.True(t, truncated)
+		assert.Len(t, players, maxAggregatePages*aggregatePageSize)
+		assert.Contains(t, collector.warnings, "Club C0327 has more than 5000 members, only the first 5000 are included")
+	}
+	assert.EqualValues(t, 2*maxAggregatePages, atomic.LoadInt32(&requests))
+}
+
+func TestAggregate_NotTruncated(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"id": "C0327", "name": "SV Altbach", "member_count": 45}], "pagination": {"total": 1}}`)
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, collector := withWarnings(context.Background())
+	stats, err := s.fetchRegionStatistics(ctx, "Württemberg")
+	require.NoError(t, err)
+	assert.False(t, stats.Truncated)
+	assert.Empty(t, collector.warnings)
+
+	data, err := json.Marshal(stats)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "truncated")
+}
+
+func TestProgressReporter_WritesNotification(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Server{sharedState: &sharedState{out: &buf, logger: logrus.New()}}
+
+	ctx := withProgress(context.Background(), s.progressReporter("tok-1"))
+	reportProgress(ctx, 1, 3, "Processed page 1", map[string]int{"clubs": 100})
+
+	var msg struct {
+		Method string               `json:"method"`
+		Params ProgressNotification `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &msg))
+	assert.Equal(t, "notifications/progress", msg.Method)
+	assert.Equal(t, "tok-1", msg.Params.ProgressToken)
+	assert.Equal(t, float64(1), msg.Params.Progress)
+	assert.Equal(t, float64(3), msg.Params.Total)
+	assert.Equal(t, "Processed page 1", msg.Params.Message)
+}
+
+func TestReportProgress_NoReporter(t *testing.T) {
+	assert.NotPanics(t, func() {
+		reportProgress(context.Background(), 1, 1, "done", nil)
+	})
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+// SetAlerter sets the alerter notified of open circuit breakers, sustained
+// server errors of the API clients of all profiles and SLO burn rate
+// alerts. Expiring certificates of api.ssl are checked once the server
+// starts. Like AddProfile, it must be called before Start.
+func (s *Server) SetAlerter(alerter *telemetry.Alerter) {
+	s.alerter = alerter
+	s.watchUpstream(config.DefaultProfile)
+	for name, profile := range s.profiles {
+		profile.alerter = alerter
+		profile.watchUpstream(name)
+	}
+
+	if s.slo != nil {
+		s.slo.OnAlert(func(tool string, status telemetry.ObjectiveStatus) {
+			alerter.Send(telemetry.Alert{
+				Kind:    telemetry.AlertSLOBurn,
+				Key:     tool + "/" + status.Objective,
+				Summary: fmt.Sprintf("Error budget of the %s objective of %s burning %.1f times faster than sustainable", status.Objective, tool, status.BurnRate),
+				Fields: map[string]string{
+					"tool":             tool,
+					"objective":        status.Objective,
+					"target":           fmt.Sprint(status.Target),
+					"burn_rate":        fmt.Sprintf("%.2f", status.BurnRate),
+					"budget_remaining": fmt.Sprintf("%.3f", status.BudgetRemaining),
+				},
+			})
+		})
+	}
+}
+
+// watchUpstream sends alerts on open circuit breakers and sustained server
+// errors of the API client of the profile
+func (s *Server) watchUpstream(profile string) {
+	if s.apiClient == nil {
+		return
+	}
+	alerter := s.alerter
+	s.apiClient.OnServerError(alerter.UpstreamError)
+	s.apiClient.OnBreakerOpen(func(url, reason string) {
+		alerter.Send(telemetry.Alert{
+			Kind:     telemetry.AlertBreakerOpen,
+			Key:      url,
+			Severity: telemetry.LevelError,
+			Summary:  fmt.Sprintf("Circuit breaker of Portal64 upstream %s opened", url),
+			Fields:   map[string]string{"upstream": url, "profile": profile, "last_error": reason},
+		})
+	})
+}
+
+// runCertificateAlerts checks the certificates of api.ssl for expiry at
+// start and then every interval until the context is done
+func (s *Server) runCertificateAlerts(ctx context.Context, interval time.Duration) {
+	s.checkCertificates(time.Now())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.checkCertificates(now)
+		}
+	}
+}
+
+// checkCertificates sends an alert for every certificate of the client
+// certificate chain and CA bundle of api.ssl that expires within the
+// configured number of days, or has expired
+func (s *Server) checkCertificates(now time.Time) {
+	ssl := s.config.API.SSL
+	check := api.CheckTLS(api.TLSOptions{CAFile: ssl.CAFile, ClientCert: ssl.ClientCert, ClientKey: ssl.ClientKey}, now)
+	days := s.config.Telemetry.Alerts.CertificateExpiryDays
+
+	for _, file := range []struct {
+		name  string
+		certs []api.CertificateInfo
+	}{{ssl.ClientCert, check.ClientCertificate}, {ssl.CAFile, check.CABundle}} {
+		for _, cert := range file.certs {
+			if cert.DaysLeft >= days && cert.NotAfter.After(now) {
+				continue
+			}
+			alert := telemetry.Alert{
+				Kind:    telemetry.AlertCertificateExpiry,
+				Key:     file.name + "/" + cert.Subject,
+				Summary: fmt.Sprintf("Certificate %s of %s expires on %s, in %d days", cert.Subject, file.name, cert.NotAfter.Format("2006-01-02"), cert.DaysLeft),
+				Fields: map[string]string{
+					"file":      file.name,
+					"subject":   cert.Subject,
+					"issuer":    cert.Issuer,
+					"not_after": cert.NotAfter.Format(time.RFC3339),
+				},
+			}
+			if !cert.NotAfter.After(now) {
+				alert.Severity = telemetry.LevelError
+				alert.Summary = fmt.Sprintf("Certificate %s of %s expired on %s", cert.Subject, file.name, cert.NotAfter.Format("2006-01-02"))
+			}
+			s.alerter.Send(alert)
+		}
+	}
+}
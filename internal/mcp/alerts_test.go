@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+func TestSetAlerter(t *testing.T) {
+	var mu sync.Mutex
+	var alerts []telemetry.Alert
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert telemetry.Alert
+		json.NewDecoder(r.Body).Decode(&alert)
+		mu.Lock()
+		alerts = append(alerts, alert)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	// A client certificate expiring in 3 days
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "portal64-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(3*24*time.Hour + time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.config = &config.Config{}
+	s.config.API.SSL.ClientCert, s.config.API.SSL.ClientKey = certFile, keyFile
+	s.config.Telemetry.Alerts.CertificateExpiryDays = 14
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	require.NoError(t, s.apiClient.ConfigureFailover(api.FailoverOptions{FallbackURLs: []string{upstream.URL + "/"}, FailureThreshold: 1}))
+	s.slo = telemetry.NewSLOTracker(telemetry.SLOOptions{
+		Window:            time.Hour,
+		Default:           telemetry.SLOTarget{Availability: 0.99},
+		AlertWindow:       5 * time.Minute,
+		BurnRateThreshold: 10,
+	}, nil)
+
+	alerter, err := telemetry.NewAlerter(telemetry.AlertOptions{WebhookURL: receiver.URL, UpstreamErrorThreshold: 100}, nil)
+	require.NoError(t, err)
+	s.SetAlerter(alerter)
+
+	_, err = s.apiClient.Health(context.Background())
+	require.Error(t, err)
+	s.slo.Record("search_players", time.Millisecond, true)
+	s.slo.Check()
+	s.checkCertificates(time.Now())
+	require.NoError(t, alerter.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	kinds := make(map[string]telemetry.Alert)
+	for _, alert := range alerts {
+		kinds[alert.Kind] = alert
+	}
+	require.Len(t, kinds, 3, "%+v", alerts)
+	assert.Equal(t, upstream.URL, kinds[telemetry.AlertBreakerOpen].Fields["upstream"])
+	assert.Equal(t, config.DefaultProfile, kinds[telemetry.AlertBreakerOpen].Fields["profile"])
+	assert.Equal(t, "search_players/availability", kinds[telemetry.AlertSLOBurn].Key)
+	certificate := kinds[telemetry.AlertCertificateExpiry]
+	assert.Equal(t, telemetry.LevelWarning, certificate.Severity)
+	assert.Contains(t, certificate.Summary, "CN=portal64-client")
+	assert.Contains(t, certificate.Summary, "in 3 days")
+}
@@ -0,0 +1,107 @@
+package mcp
+
+// toolTitles are the human-readable titles of the tools
+var toolTitles = map[string]string{
+	"search_players":               "Search Players",
+	"get_player_by_pkz":            "Get Player by PKZ",
+	"search_clubs":                 "Search Clubs",
+	"search_tournaments":           "Search Tournaments",
+	"get_recent_tournaments":       "Recent Tournaments",
+	"get_upcoming_tournaments":     "Upcoming Tournaments",
+	"get_tournament_changes":       "Tournament Changes",
+	"search_tournaments_by_date":   "Search Tournaments by Date",
+	"get_tournament_series":        "Tournament Series",
+	"get_organizer_tournaments":    "Organizer Tournaments",
+	"resolve_id":                   "Resolve ID",
+	"search_all":                   "Search All",
+	"get_player_profile":           "Player Profile",
+	"get_club_profile":             "Club Profile",
+	"get_tournament_details":       "Tournament Details",
+	"get_club_players":             "Club Players",
+	"export_club_data":             "Export Club Data",
+	"get_qr_code":                  "QR Code",
+	"get_player_rating_history":    "Player Rating History",
+	"render_rating_chart":          "Rating Chart",
+	"render_club_distribution":     "Club Rating Distribution",
+	"get_player_rating_at_date":    "Player Rating at Date",
+	"get_player_form":              "Player Form",
+	"get_player_percentile":        "Player Percentile",
+	"get_club_statistics":          "Club Statistics",
+	"get_club_teams":               "Club Teams",
+	"get_club_officials":           "Club Officials",
+	"get_reactivation_candidates":  "Reactivation Candidates",
+	"get_team_roster":              "Team Roster",
+	"get_region_statistics":        "Region Statistics",
+	"get_region_activity":          "Region Activity",
+	"calculate_tournament_dwz":     "Calculate Tournament DWZ",
+	"convert_rating":               "Convert Rating",
+	"get_club_youth_statistics":    "Club Youth Statistics",
+	"find_clubs_near":              "Find Clubs Nearby",
+	"check_api_health":             "Check API Health",
+	"get_cache_stats":              "Cache Statistics",
+	"get_connection_stats":         "Connection Statistics",
+	"diagnose_upstream_connection": "Diagnose Upstream Connection",
+	"debug_upstream_request":       "Debug Upstream Request",
+	"check_ssl_config":             "Check SSL Configuration",
+	"get_runtime_stats":            "Runtime Statistics",
+	"get_slo_status":               "Service Level Objectives",
+	"get_effective_config":         "Effective Configuration",
+	"get_regions":                  "Regions",
+	"get_region_addresses":         "Region Addresses",
+	"search_officials":             "Search Officials",
+	"find_possible_duplicates":     "Find Possible Duplicates",
+	"get_feature_flags":            "Feature Flags",
+	"set_feature_flag":             "Set Feature Flag",
+	"submit_address_correction":    "Submit Address Correction",
+	"draft_contact_correction":     "Draft Contact Correction",
+}
+
+// mutatingTools change the state of the server or, like the write tools,
+// data of the Portal64 API, or send messages. All other tools only read
+// data.
+var mutatingTools = map[string]bool{
+	"set_feature_flag":          true,
+	"submit_address_correction": true,
+	"draft_contact_correction":  true,
+}
+
+// repeatedEffectTools have an effect on every call, such as filing a
+// submission or sending an email
+var repeatedEffectTools = map[string]bool{
+	"submit_address_correction": true,
+	"draft_contact_correction":  true,
+}
+
+// closedWorldTools work on local data only and do not query the Portal64
+// API or other external services
+var closedWorldTools = map[string]bool{
+	"calculate_tournament_dwz": true,
+	"convert_rating":           true,
+	"get_connection_stats":     true,
+	"get_runtime_stats":        true,
+	"get_slo_status":           true,
+	"get_effective_config":     true,
+	"get_feature_flags":        true,
+	"set_feature_flag":         true,
+	"get_qr_code":              true,
+}
+
+// toolAnnotations returns the annotations of a tool. No tool deletes or
+// overwrites data. Repeating a call has no further effect, except for the
+// repeated effect tools.
+func toolAnnotations(name string) *ToolAnnotations {
+	title, ok := toolTitles[name]
+	if !ok {
+		return nil
+	}
+	readOnly := !mutatingTools[name]
+	openWorld := !closedWorldTools[name]
+	destructive, idempotent := false, !repeatedEffectTools[name]
+	return &ToolAnnotations{
+		Title:           title,
+		ReadOnlyHint:    &readOnly,
+		DestructiveHint: &destructive,
+		IdempotentHint:  &idempotent,
+		OpenWorldHint:   &openWorld,
+	}
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolAnnotations_AllTools(t *testing.T) {
+	s := newTestServer()
+	s.registerTools()
+
+	for name := range s.tools {
+		annotations := s.GetToolDefinition(name).Annotations
+		require.NotNil(t, annotations, name)
+		assert.NotEmpty(t, annotations.Title, name)
+		assert.False(t, *annotations.DestructiveHint, name)
+		assert.Equal(t, !mutatingTools[name], *annotations.ReadOnlyHint, name)
+		assert.Equal(t, !repeatedEffectTools[name], *annotations.IdempotentHint, name)
+	}
+	for name := range toolTitles {
+		assert.Contains(t, s.tools, name, "title of unknown tool")
+	}
+
+	assert.False(t, *s.GetToolDefinition("convert_rating").Annotations.OpenWorldHint)
+	assert.True(t, *s.GetToolDefinition("search_players").Annotations.OpenWorldHint)
+}
+
+func TestListTools_Annotations(t *testing.T) {
+	s := newTestServer()
+	s.registerTools()
+
+	response, err := s.handleMessage([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	require.NoError(t, err)
+	data, err := json.Marshal(response.Result)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"annotations":{"title":"Search Players","readOnlyHint":true,"destructiveHint":false,"idempotentHint":true,"openWorldHint":true}`)
+
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/list", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var list ListToolsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.NotEmpty(t, list.Tools)
+	for _, tool := range list.Tools {
+		require.NotNil(t, tool.Annotations, tool.Name)
+		assert.Equal(t, toolTitles[tool.Name], tool.Annotations.Title)
+	}
+}
@@ -0,0 +1,210 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// asOfConcurrency limits parallel rating history requests for club members
+const asOfConcurrency = 8
+
+// Methods of reconstructing a historical rating
+const (
+	ratingFromPreviousEvaluation = "previous_evaluation" // New DWZ of the last evaluation on or before the date
+	ratingFromNextEvaluation     = "next_evaluation"     // Old DWZ of the first evaluation after the date
+)
+
+// parseAsOfDate parses a date expression as accepted by
+// parseDateExpression. Periods resolve to their last day, so "2022-01" is
+// the rating at the end of January 2022. The day is returned at midnight
+// UTC, as evaluation dates are.
+func parseAsOfDate(value string, now time.Time) (time.Time, error) {
+	_, last, err := parseDateExpression(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// RatingAtDate is a DWZ reconstructed for a historical date
+type RatingAtDate struct {
+	PlayerID   string          `json:"player_id"`
+	Date       string          `json:"date"`
+	DWZ        int             `json:"dwz"` // 0 if the player was unrated
+	Rated      bool            `json:"rated"`
+	Method     string          `json:"method,omitempty"`
+	Evaluation *api.Evaluation `json:"evaluation,omitempty"` // The evaluation the rating was derived from
+}
+
+// ratingAtDateFormula explains the reconstruction of ratingAtDate
+const ratingAtDateFormula = "DWZ at a date = new DWZ of the last evaluation up to the date (previous_evaluation), before the first evaluation the old DWZ of the next one (next_evaluation)"
+
+// ratingAtDate reconstructs the DWZ at the end of date from a rating
+// history. A rating only changes with an evaluation, so it is the new DWZ
+// of the last evaluation up to the date or, before the first evaluation, the
+// old DWZ of the next one. Evaluations without a date are ignored.
+func ratingAtDate(playerID string, history []api.Evaluation, date time.Time) RatingAtDate {
+	result := RatingAtDate{PlayerID: playerID, Date: date.Format("2006-01-02")}
+	end := date.AddDate(0, 0, 1)
+
+	dated := make([]api.Evaluation, 0, len(history))
+	for _, evaluation := range history {
+		if !evaluation.Date.IsZero() {
+			dated = append(dated, evaluation)
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool { return dated[i].Date.Before(dated[j].Date) })
+
+	next := sort.Search(len(dated), func(i int) bool { return !dated[i].Date.Before(end) })
+	switch {
+	case next > 0:
+		previous := dated[next-1]
+		result.DWZ = previous.NewDWZ
+		result.Method = ratingFromPreviousEvaluation
+		result.Evaluation = &previous
+	case next < len(dated):
+		following := dated[next]
+		result.DWZ = following.OldDWZ
+		result.Method = ratingFromNextEvaluation
+		result.Evaluation = &following
+	}
+	result.Rated = result.DWZ > 0
+	return result
+}
+
+// handleGetPlayerRatingAtDate handles historical rating requests
+func (s *Server) handleGetPlayerRatingAtDate(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	playerID, _ := args["player_id"].(string)
+	dateArg, _ := args["date"].(string)
+	if playerID == "" || dateArg == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: player_id and date are required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	date, err := parseAsOfDate(dateArg, time.Now())
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	history, err := s.ratingHistory(ctx, playerID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting player rating history: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result := ratingAtDate(playerID, history, date)
+	if result.Evaluation == nil {
+		addWarning(ctx, "No dated evaluations in the rating history, the rating cannot be reconstructed")
+	}
+	explain(ctx, ratingAtDateFormula)
+	addCaveat(ctx, "Evaluations without a date are ignored")
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// ClubStatisticsAsOf is the member rating statistics of a club at a
+// historical date
+type ClubStatisticsAsOf struct {
+	AsOf                  string                `json:"as_of"`
+	MemberStatistics      *ClubMemberStatistics `json:"member_statistics"`
+	MembersReconstructed  int                   `json:"members_reconstructed"`
+	MembersWithoutHistory []string              `json:"members_without_history,omitempty"`
+	// FailedItems are the members whose rating history failed to load
+	FailedItems []FailedItem `json:"failed_items,omitempty"`
+}
+
+// clubStatisticsAsOf computes member statistics with every current member's
+// DWZ reconstructed for date. Membership itself is not historical: players
+// who joined later are included and players who left are missing. Members
+// whose history fails to load are left out and listed as failed items,
+// unless more than the allowed share of them fails.
+func (s *Server) clubStatisticsAsOf(ctx context.Context, clubID string, date time.Time) (*ClubStatisticsAsOf, error) {
+	players, truncated, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make([]*RatingAtDate, len(players))
+	missing := make([]bool, len(players))
+	var failed failedItems
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, asOfConcurrency)
+	for i, player := range players {
+		wg.Add(1)
+		go func(i int, player api.PlayerResponse) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			history, err := s.ratingHistory(ctx, player.ID)
+			switch {
+			case err == nil:
+				rating := ratingAtDate(player.ID, history, date)
+				ratings[i] = &rating
+			case api.IsNotFound(err):
+				missing[i] = true
+			default:
+				failed.add(player.ID, err)
+			}
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, done, len(players), fmt.Sprintf("Reconstructed ratings of %d of %d members", done, len(players)), nil)
+			mu.Unlock()
+		}(i, player)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &ClubStatisticsAsOf{AsOf: date.Format("2006-01-02"), FailedItems: failed.list()}
+	if err := s.checkFailures(ctx, result.FailedItems, len(players), "member rating histories"); err != nil {
+		return nil, err
+	}
+	historical := make([]api.PlayerResponse, 0, len(players))
+	for i, player := range players {
+		if missing[i] {
+			result.MembersWithoutHistory = append(result.MembersWithoutHistory, player.ID)
+		}
+		if ratings[i] == nil {
+			continue
+		}
+		player.CurrentDWZ = ratings[i].DWZ
+		historical = append(historical, player)
+	}
+	result.MembersReconstructed = len(historical)
+	result.MemberStatistics = computeClubMemberStatistics(clubID, historical, pageCount(len(players), aggregatePageSize))
+	result.MemberStatistics.Truncated = truncated
+	return result, nil
+}
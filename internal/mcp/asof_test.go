@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestParseAsOfDate(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, portalLocation)
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"2022-01-15", "2022-01-15"},
+		{"15.01.2022", "2022-01-15"},
+		{"2022-01", "2022-01-31"},
+		{"2024-02", "2024-02-29"},
+		{" 2021 ", "2021-12-31"},
+		{"2022-Q1", "2022-03-31"},
+		{"2022-01-15T23:30:00Z", "2022-01-16"},
+		{"yesterday", "2024-03-14"},
+		{"last month", "2024-02-29"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			date, err := parseAsOfDate(tc.input, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, date.Format("2006-01-02"))
+			assert.Equal(t, time.UTC, date.Location())
+		})
+	}
+
+	_, err := parseAsOfDate("January 2022", now)
+	assert.ErrorContains(t, err, dateExpressionHelp)
+}
+
+func TestRatingAtDate(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	history := []api.Evaluation{
+		{TournamentID: "T3", Date: day("2023-05-01"), OldDWZ: 1610, NewDWZ: 1625},
+		{TournamentID: "T1", Date: day("2021-03-10"), OldDWZ: 0, NewDWZ: 1550},
+		{TournamentID: "T2", Date: day("2022-01-20"), OldDWZ: 1550, NewDWZ: 1610},
+		{TournamentID: "undated", OldDWZ: 1, NewDWZ: 2},
+	}
+
+	testCases := []struct {
+		name       string
+		date       string
+		dwz        int
+		method     string
+		tournament string
+	}{
+		{"before first evaluation", "2020-06-01", 0, ratingFromNextEvaluation, "T1"},
+		{"between evaluations", "2022-01-19", 1550, ratingFromPreviousEvaluation, "T1"},
+		{"on evaluation date", "2022-01-20", 1610, ratingFromPreviousEvaluation, "T2"},
+		{"after last evaluation", "2024-01-01", 1625, ratingFromPreviousEvaluation, "T3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ratingAtDate("C0327-297", history, day(tc.date))
+			assert.Equal(t, tc.dwz, result.DWZ)
+			assert.Equal(t, tc.dwz > 0, result.Rated)
+			assert.Equal(t, tc.method, result.Method)
+			require.NotNil(t, result.Evaluation)
+			assert.Equal(t, tc.tournament, result.Evaluation.TournamentID)
+		})
+	}
+
+	result := ratingAtDate("C0327-297", nil, day("2022-01-01"))
+	assert.Nil(t, result.Evaluation)
+	assert.False(t, result.Rated)
+}
+
+func TestClubStatisticsAsOf(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/clubs/C0327/players":
+			w.Write([]byte(`{"data": [
+				{"id": "C0327-1", "current_dwz": 1800},
+				{"id": "C0327-2", "current_dwz": 1500},
+				{"id": "C0327-3", "current_dwz": 1200}
+			], "meta": {"total": 3}}`))
+		case r.URL.Path == "/api/v1/players/C0327-1/rating-history":
+			w.Write([]byte(`[{"id": 1, "tournament_id": "T1", "tournament_date": "2021-06-01T00:00:00Z", "dwz_old": 1700, "dwz_new": 1720},
+				{"id": 2, "tournament_id": "T2", "tournament_date": "2023-06-01T00:00:00Z", "dwz_old": 1720, "dwz_new": 1800}]`))
+		case r.URL.Path == "/api/v1/players/C0327-2/rating-history":
+			w.Write([]byte(`[{"id": 3, "tournament_id": "T3", "tournament_date": "2023-01-01T00:00:00Z", "dwz_old": 0, "dwz_new": 1500}]`))
+		case strings.HasSuffix(r.URL.Path, "/rating-history"):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	date, _ := parseAsOfDate("2022", time.Now())
+	result, err := s.clubStatisticsAsOf(context.Background(), "C0327", date)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2022-12-31", result.AsOf)
+	assert.Equal(t, 2, result.MembersReconstructed)
+	assert.Equal(t, []string{"C0327-3"}, result.MembersWithoutHistory)
+	assert.Equal(t, 1, result.MemberStatistics.PlayersWithDWZ)
+	assert.Equal(t, 1720.0, result.MemberStatistics.AverageDWZ)
+	assert.Equal(t, 1, result.MemberStatistics.RatingDistribution["unrated"])
+
+	response, err := s.handleGetPlayerRatingAtDate(context.Background(), map[string]interface{}{"player_id": "C0327-1", "date": "2022-01"})
+	require.NoError(t, err)
+	require.False(t, response.IsError)
+	var rating RatingAtDate
+	require.NoError(t, json.Unmarshal([]byte(response.Content[0].Text), &rating))
+	assert.Equal(t, 1720, rating.DWZ)
+	assert.Equal(t, "2022-01-31", rating.Date)
+}
+
+func TestClubStatisticsAsOf_PartialFailures(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clubs/C0327/players":
+			w.Write([]byte(`{"data": [
+				{"id": "C0327-1", "current_dwz": 1800},
+				{"id": "C0327-2", "current_dwz": 1500},
+				{"id": "C0327-3", "current_dwz": 1200}
+			], "meta": {"total": 3}}`))
+		case "/api/v1/players/C0327-1/rating-history":
+			w.Write([]byte(`[{"id": 1, "tournament_id": "T1", "tournament_date": "2021-06-01T00:00:00Z", "dwz_old": 1700, "dwz_new": 1720}]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.config = &config.Config{MCP: config.MCPConfig{Aggregates: config.AggregatesConfig{MaxFailureRatio: 0.7}}}
+	date, _ := parseAsOfDate("2022", time.Now())
+
+	result, err := s.clubStatisticsAsOf(context.Background(), "C0327", date)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.MembersReconstructed)
+	assert.Empty(t, result.MembersWithoutHistory)
+	require.Len(t, result.FailedItems, 2)
+	assert.Equal(t, "C0327-2", result.FailedItems[0].ID)
+	assert.Equal(t, "C0327-3", result.FailedItems[1].ID)
+	assert.NotEmpty(t, result.FailedItems[0].Error)
+
+	s.config.MCP.Aggregates.MaxFailureRatio = 0.5
+	_, err = s.clubStatisticsAsOf(context.Background(), "C0327", date)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 of 3 member rating histories failed, more than the allowed 50%")
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+// newBenchServer returns a server with all tools registered against a mock
+// upstream serving a generated data set
+func newBenchServer(b *testing.B) (*Server, *testserver.Dataset) {
+	dataset := testserver.GenerateDataset(testserver.GenerateOptions{Seed: 1, Players: 2000, Clubs: 40, Tournaments: 100})
+	upstream := testserver.NewMockPortal64Server(testserver.Config{Dataset: dataset}).Start()
+	b.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.registerTools()
+	return s, dataset
+}
+
+// BenchmarkHandleCallTool covers a complete tools/call: parsing the
+// request, normalizing arguments, the upstream request, decoding and
+// conversion, and marshaling the enveloped result
+func BenchmarkHandleCallTool(b *testing.B) {
+	s, dataset := newBenchServer(b)
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"SearchPlayers", `{"name": "search_players", "arguments": {"query": "müller", "limit": 50}}`},
+		{"SearchClubs", `{"name": "search_clubs", "arguments": {"query": "sc", "limit": 50}}`},
+		{"GetClubPlayers", `{"name": "get_club_players", "arguments": {"club_id": "` + strings.ToLower(dataset.Clubs[0].ID) + `"}}`},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			msg := &Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(tt.params)}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				response, err := s.handleCallTool(msg)
+				if err != nil || response.Error != nil {
+					b.Fatalf("tool call failed: %v %+v", err, response.Error)
+				}
+				if result := response.Result.(*CallToolResponse); result.IsError {
+					b.Fatal(result.Content[0].Text)
+				}
+				if _, err := json.Marshal(response); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNormalizeIDArgs(b *testing.B) {
+	handler := normalizeIDArgs(func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		return nil, nil
+	})
+	args := map[string]interface{}{"player_id": "c0327/297", "club_id": "c327", "query": "Tran", "limit": 20.0}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		handler(ctx, args)
+	}
+}
+
+func BenchmarkWrapResult(b *testing.B) {
+	s := newTestServer()
+	players := testserver.GenerateDataset(testserver.GenerateOptions{Seed: 1, Players: 100, Clubs: 5}).Players
+	data, err := json.MarshalIndent(players, "", "  ")
+	if err != nil {
+		b.Fatal(err)
+	}
+	result := &CallToolResponse{Content: []ToolContent{{Type: "text", Text: string(data)}}}
+	_, collector := withWarnings(context.Background())
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		wrapped := s.wrapResult(result, collector)
+		if !strings.HasPrefix(wrapped.Content[0].Text, "{") {
+			b.Fatal("result not wrapped")
+		}
+	}
+}
+
+// BenchmarkWriteMCPToolResponse covers writing a large tool result to an
+// HTTP response
+func BenchmarkWriteMCPToolResponse(b *testing.B) {
+	s := newTestServer()
+	bridge := NewHTTPBridge(s, s.logger)
+	players := testserver.GenerateDataset(testserver.GenerateOptions{Seed: 1, Players: 1000, Clubs: 20}).Players
+	data, err := json.MarshalIndent(players, "", "  ")
+	if err != nil {
+		b.Fatal(err)
+	}
+	result := &CallToolResponse{Content: []ToolContent{{Type: "text", Text: string(data)}}}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		bridge.writeMCPToolResponse(rec, result)
+		if rec.Code != http.StatusOK {
+			b.Fatalf("status %d", rec.Code)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pageArguments are the arguments checked against the minimum and maximum
+// declared in the input schema of a tool
+var pageArguments = []string{"limit", "offset"}
+
+// argumentBounds are the declared bounds of an integer argument
+type argumentBounds struct {
+	min, max       float64
+	hasMin, hasMax bool
+}
+
+// schemaBounds returns the declared bounds of the page arguments of a tool
+func schemaBounds(tool Tool) map[string]argumentBounds {
+	bounds := make(map[string]argumentBounds)
+	for _, name := range pageArguments {
+		property, ok := tool.InputSchema.Properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var b argumentBounds
+		b.min, b.hasMin = schemaNumber(property["minimum"])
+		b.max, b.hasMax = schemaNumber(property["maximum"])
+		bounds[name] = b
+	}
+	return bounds
+}
+
+// schemaNumber converts a number of a schema definition to float64
+func schemaNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// integerArg converts an integer argument as sent by MCP clients (a JSON
+// number), the HTTP bridge (an int) or agents quoting numbers (a string) to
+// float64, the type handlers expect
+func integerArg(v interface{}) (float64, bool) {
+	var n float64
+	switch value := v.(type) {
+	case float64:
+		n = value
+	case int:
+		n = float64(value)
+	case int64:
+		n = float64(value)
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, false
+		}
+		n = float64(i)
+	default:
+		return 0, false
+	}
+	return n, n == math.Trunc(n) && !math.IsInf(n, 0)
+}
+
+// checkBounds wraps a tool handler so that limit and offset are checked
+// against the bounds of the tool's input schema. Values that are not
+// integers are rejected; values out of range are clamped to the nearest
+// bound with a warning explaining the adjustment.
+func (s *Server) checkBounds(name string, handler ToolHandler) ToolHandler {
+	bounds := schemaBounds(s.GetToolDefinition(name))
+	if len(bounds) == 0 {
+		return handler
+	}
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		checked, copied := args, false
+		for _, arg := range pageArguments {
+			b, ok := bounds[arg]
+			raw, present := args[arg]
+			if !ok || !present || raw == nil {
+				continue
+			}
+			value, ok := integerArg(raw)
+			if !ok {
+				return &CallToolResponse{
+					Content: []ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Error: %s must be an integer, got %v", arg, raw),
+					}},
+					IsError: true,
+				}, nil
+			}
+
+			switch {
+			case b.hasMin && value < b.min:
+				addWarning(ctx, fmt.Sprintf("%s %.0f is below the minimum of %.0f and was raised to %.0f", arg, value, b.min, b.min))
+				value = b.min
+			case b.hasMax && value > b.max:
+				addWarning(ctx, fmt.Sprintf("%s %.0f exceeds the maximum of %.0f and was lowered to %.0f", arg, value, b.max, b.max))
+				value = b.max
+			}
+			if value == raw {
+				continue
+			}
+			if !copied {
+				checked = make(map[string]interface{}, len(args))
+				for k, v := range args {
+					checked[k] = v
+				}
+				copied = true
+			}
+			checked[arg] = value
+		}
+		return handler(ctx, checked)
+	}
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBounds(t *testing.T) {
+	s := newTestServer()
+
+	testCases := []struct {
+		name     string
+		args     map[string]interface{}
+		limit    interface{}
+		offset   interface{}
+		warnings []string
+		err      string
+	}{
+		{
+			name:   "In range",
+			args:   map[string]interface{}{"limit": 20.0, "offset": 40.0},
+			limit:  20.0,
+			offset: 40.0,
+		},
+		{
+			name: "Absent",
+			args: map[string]interface{}{"query": "Müller"},
+		},
+		{
+			name:     "Limit above maximum",
+			args:     map[string]interface{}{"limit": 500.0},
+			limit:    200.0,
+			warnings: []string{"limit 500 exceeds the maximum of 200 and was lowered to 200"},
+		},
+		{
+			name:     "Negative offset",
+			args:     map[string]interface{}{"limit": 0.0, "offset": -5.0},
+			limit:    1.0,
+			offset:   0.0,
+			warnings: []string{"limit 0 is below the minimum of 1 and was raised to 1", "offset -5 is below the minimum of 0 and was raised to 0"},
+		},
+		{
+			name:   "Integers of the HTTP bridge",
+			args:   map[string]interface{}{"limit": 25, "offset": 5},
+			limit:  25.0,
+			offset: 5.0,
+		},
+		{
+			name:  "Quoted number",
+			args:  map[string]interface{}{"limit": " 30 "},
+			limit: 30.0,
+		},
+		{
+			name: "Fraction",
+			args: map[string]interface{}{"limit": 2.5},
+			err:  "Error: limit must be an integer, got 2.5",
+		},
+		{
+			name: "Not a number",
+			args: map[string]interface{}{"offset": "many"},
+			err:  "Error: offset must be an integer, got many",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received map[string]interface{}
+			handler := s.checkBounds("search_players", func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+				received = args
+				return &CallToolResponse{}, nil
+			})
+
+			ctx, collector := withWarnings(context.Background())
+			result, err := handler(ctx, tc.args)
+			require.NoError(t, err)
+			if tc.err != "" {
+				assert.True(t, result.IsError)
+				assert.Equal(t, tc.err, result.Content[0].Text)
+				assert.Nil(t, received)
+				return
+			}
+			assert.Equal(t, tc.limit, received["limit"])
+			assert.Equal(t, tc.offset, received["offset"])
+			assert.Equal(t, tc.warnings, collector.warnings)
+		})
+	}
+}
+
+func TestCheckBounds_SchemaWithoutMaximum(t *testing.T) {
+	s := newTestServer()
+
+	var received map[string]interface{}
+	handler := s.checkBounds("get_upcoming_tournaments", func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		received = args
+		return &CallToolResponse{}, nil
+	})
+	_, err := handler(context.Background(), map[string]interface{}{"limit": 1000.0})
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, received["limit"])
+
+	// Tools without page arguments are not wrapped
+	args := map[string]interface{}{"limit": "any"}
+	_, err = s.checkBounds("get_regions", func(ctx context.Context, got map[string]interface{}) (*CallToolResponse, error) {
+		received = got
+		return &CallToolResponse{}, nil
+	})(context.Background(), args)
+	require.NoError(t, err)
+	assert.Equal(t, "any", received["limit"])
+}
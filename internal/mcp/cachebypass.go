@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// bypassCacheArgument makes a data tool skip the response cache
+const bypassCacheArgument = "bypass_cache"
+
+// dataTool reports whether a tool reads Portal64 data, which may be served
+// from the response cache
+func dataTool(name string) bool {
+	return !adminTools[name] && !closedWorldTools[name] && !mutatingTools[name]
+}
+
+// withBypassCacheArgument adds the bypass_cache argument to the definition
+// of a data tool
+func withBypassCacheArgument(tool Tool) Tool {
+	if !dataTool(tool.Name) {
+		return tool
+	}
+	properties := make(map[string]interface{}, len(tool.InputSchema.Properties)+1)
+	for k, v := range tool.InputSchema.Properties {
+		properties[k] = v
+	}
+	properties[bypassCacheArgument] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Skip cached responses and cached not-found results and ask the Portal64 API again",
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// bypassCache runs a tool with a context skipping the response cache if
+// the bypass_cache argument is set
+func bypassCache(handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		if bypass, _ := args[bypassCacheArgument].(bool); bypass {
+			ctx = api.WithCacheBypass(ctx)
+		}
+		return handler(ctx, args)
+	}
+}
+
+// cachedMissHint returns the hint appended to errors of lookups answered
+// with a cached 404, so clients know the ID may have been created since
+func cachedMissHint(err error) string {
+	if !api.IsCachedMiss(err) {
+		return ""
+	}
+	return " (is_cached_miss: true, pass bypass_cache to ask the Portal64 API again)"
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestGetPlayerProfile_CachedMiss(t *testing.T) {
+	var requests int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "Player not found", http.StatusNotFound)
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.apiClient.ConfigureCache(api.CacheOptions{NotFoundTTL: time.Minute})
+
+	result, err := s.handleGetPlayerProfile(s.ctx, map[string]interface{}{"player_id": "C0101-999"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.NotContains(t, result.Content[0].Text, "is_cached_miss")
+
+	result, err = s.handleGetPlayerProfile(s.ctx, map[string]interface{}{"player_id": "C0101-999"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "is_cached_miss: true")
+	assert.Equal(t, 1, requests, "the second lookup is answered by the cache")
+
+	result, err = bypassCache(s.handleGetPlayerProfile)(s.ctx, map[string]interface{}{"player_id": "C0101-999", "bypass_cache": true})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Content[0].Text, "is_cached_miss")
+	assert.Equal(t, 2, requests, "bypass_cache asks the API again")
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/dwz"
+)
+
+// handleCalculateTournamentDWZ computes rating changes for a set of results
+// without contacting the Portal64 API
+func (s *Server) handleCalculateTournamentDWZ(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	var input struct {
+		Players []dwz.Player `json:"players"`
+		Games   []dwz.Game   `json:"games"`
+	}
+
+	raw, _ := json.Marshal(args)
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: invalid arguments: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	if len(input.Players) == 0 || len(input.Games) == 0 {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: players and games are required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result, err := dwz.Calculate(input.Players, input.Games)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error calculating DWZ: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	explain(ctx, dwz.Formulas...)
+	addCaveat(ctx, "The result is an estimate from the given ratings and results; the official evaluation by the rating office may differ")
+	addCaveat(ctx, "Forfeits and games against unrated players are not rated, unrated players get no rating")
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// handleConvertRating converts a rating between DWZ and Elo without
+// contacting the Portal64 API
+func (s *Server) handleConvertRating(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	rating, ok := args["rating"].(float64)
+	if !ok {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: rating is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	from := dwz.SystemDWZ
+	if f, ok := args["from"].(string); ok && f != "" {
+		from = strings.ToLower(f)
+	}
+
+	conversion, err := dwz.Convert(int(rating), from)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	explain(ctx, dwz.ConversionFormulas...)
+
+	data, _ := json.MarshalIndent(conversion, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// withApproximateElo adds the heuristic Elo estimate of a player's DWZ to the
+// player's JSON representation. Unrated players are returned unchanged.
+func withApproximateElo(player *api.PlayerResponse) (interface{}, error) {
+	if player.CurrentDWZ <= 0 {
+		return player, nil
+	}
+	conversion, err := dwz.Convert(player.CurrentDWZ, dwz.SystemDWZ)
+	if err != nil {
+		return player, nil
+	}
+
+	raw, err := json.Marshal(player)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	fields["approximate_elo"] = conversion
+	return fields, nil
+}
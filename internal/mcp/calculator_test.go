@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/dwz"
+)
+
+func TestHandleConvertRating(t *testing.T) {
+	s := newTestServer()
+
+	result, err := s.handleConvertRating(context.Background(), map[string]interface{}{"rating": float64(1750), "from": "Elo"})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var conversion dwz.Conversion
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &conversion))
+	assert.Equal(t, dwz.SystemDWZ, conversion.To)
+	assert.Equal(t, 1600, conversion.Converted)
+	assert.Equal(t, "heuristic_estimate", conversion.Method)
+	assert.NotEmpty(t, conversion.Caveats)
+
+	result, err = s.handleConvertRating(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestWithApproximateElo(t *testing.T) {
+	player := &api.PlayerResponse{ID: "C0327-297", Name: "Tran", CurrentDWZ: 1600, Gender: "male"}
+
+	profile, err := withApproximateElo(player)
+	require.NoError(t, err)
+	data, err := json.Marshal(profile)
+	require.NoError(t, err)
+
+	var decoded struct {
+		ID             string         `json:"id"`
+		CurrentDWZ     int            `json:"current_dwz"`
+		ApproximateElo dwz.Conversion `json:"approximate_elo"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "C0327-297", decoded.ID)
+	assert.Equal(t, 1600, decoded.CurrentDWZ)
+	assert.Equal(t, 1750, decoded.ApproximateElo.Converted)
+
+	unrated := &api.PlayerResponse{ID: "C0327-298"}
+	profile, err = withApproximateElo(unrated)
+	require.NoError(t, err)
+	assert.Same(t, unrated, profile)
+}
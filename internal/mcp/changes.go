@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+const (
+	// defaultChangesDays is the default window of recent tournaments
+	// compared by get_tournament_changes
+	defaultChangesDays = 30
+	// maxChangesDays bounds the window of get_tournament_changes
+	maxChangesDays = 365
+	// maxChangesTournaments bounds the recent tournaments compared per call
+	maxChangesTournaments = 200
+	// syncTokenTTL is how long a sync token stays valid
+	syncTokenTTL = 7 * 24 * time.Hour
+	// maxSyncTokens bounds the sync tokens kept; the oldest are dropped first
+	maxSyncTokens = 1000
+)
+
+// syncSnapshot is the state of the tournaments a sync token was issued for
+type syncSnapshot struct {
+	owner  string // Session of the caller, empty for stdio
+	issued time.Time
+	// fingerprints are the hashes of the tournaments seen by the caller, by
+	// tournament ID
+	fingerprints map[string]string
+	size         int64
+}
+
+// syncTokens keeps the snapshots of issued sync tokens
+type syncTokens struct {
+	mu        sync.Mutex
+	snapshots map[string]*syncSnapshot
+}
+
+// get returns the snapshot of a token issued to owner and not expired
+func (t *syncTokens) get(token, owner string, now time.Time) (*syncSnapshot, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snapshot, ok := t.snapshots[token]
+	if !ok || snapshot.owner != owner {
+		return nil, false
+	}
+	if now.Sub(snapshot.issued) > syncTokenTTL {
+		delete(t.snapshots, token)
+		return nil, false
+	}
+	return snapshot, true
+}
+
+// issue stores a snapshot under a new token. Expired tokens and, beyond
+// maxSyncTokens, the oldest tokens are dropped.
+func (t *syncTokens) issue(snapshot *syncSnapshot) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create sync token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+	snapshot.size = memory.EstimateSize(snapshot.fingerprints)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.snapshots == nil {
+		t.snapshots = make(map[string]*syncSnapshot)
+	}
+	for key, s := range t.snapshots {
+		if snapshot.issued.Sub(s.issued) > syncTokenTTL {
+			delete(t.snapshots, key)
+		}
+	}
+	if len(t.snapshots) >= maxSyncTokens {
+		t.evictOldest(len(t.snapshots) - maxSyncTokens + 1)
+	}
+	t.snapshots[token] = snapshot
+	return token, nil
+}
+
+// evictOldest removes the n tokens issued longest ago. The caller holds mu.
+func (t *syncTokens) evictOldest(n int) int64 {
+	tokens := make([]string, 0, len(t.snapshots))
+	for token := range t.snapshots {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return t.snapshots[tokens[i]].issued.Before(t.snapshots[tokens[j]].issued) })
+
+	var freed int64
+	for _, token := range tokens[:min(n, len(tokens))] {
+		freed += t.snapshots[token].size
+		delete(t.snapshots, token)
+	}
+	return freed
+}
+
+// MemoryUsage implements memory.Store
+func (t *syncTokens) MemoryUsage() memory.Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	usage := memory.Usage{Entries: len(t.snapshots)}
+	for _, snapshot := range t.snapshots {
+		usage.Bytes += snapshot.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the tokens issued longest ago
+func (t *syncTokens) Evict(bytes int64) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var freed int64
+	for freed < bytes && len(t.snapshots) > 0 {
+		freed += t.evictOldest(1)
+	}
+	return freed
+}
+
+// tournamentFingerprint hashes a tournament so that changes of any of its
+// fields are detected
+func tournamentFingerprint(t api.TournamentResponse) string {
+	data, _ := json.Marshal(t)
+	h := fnv.New64a()
+	h.Write(data)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// TournamentChanges is the result of get_tournament_changes
+type TournamentChanges struct {
+	SyncToken string `json:"sync_token"`
+	// Since is the time the changes_since token was issued, empty for a full
+	// sync
+	Since     string                   `json:"since,omitempty"`
+	Full      bool                     `json:"full"` // All tournaments are listed as added
+	Days      int                      `json:"days"`
+	Added     []api.TournamentResponse `json:"added"`
+	Updated   []api.TournamentResponse `json:"updated"`
+	Unchanged int                      `json:"unchanged"`
+}
+
+// tournamentChanges compares the recent tournaments with the snapshot of a
+// sync token, or lists all of them without one, and issues a new token
+// covering the tournaments of the window. Fingerprints of tournaments that
+// left the window are dropped, so a snapshot never grows beyond
+// maxChangesTournaments; a tournament coming back into a later, wider
+// window is listed as added again.
+func (s *Server) tournamentChanges(ctx context.Context, token string, days int, now time.Time) (*TournamentChanges, error) {
+	owner := ""
+	if session, ok := SessionFromContext(ctx); ok {
+		owner = session.ID
+	}
+
+	var previous *syncSnapshot
+	if token != "" {
+		var ok bool
+		if previous, ok = s.syncTokens.get(token, owner, now); !ok {
+			return nil, errUnknownSyncToken
+		}
+	}
+
+	tournaments, err := s.apiClient.GetRecentTournaments(ctx, days, maxChangesTournaments)
+	if err != nil {
+		return nil, err
+	}
+	if len(tournaments) >= maxChangesTournaments {
+		addWarning(ctx, fmt.Sprintf("only the %d most recent tournaments were compared; use a shorter days window", maxChangesTournaments))
+	}
+
+	changes := &TournamentChanges{
+		Full:    previous == nil,
+		Days:    days,
+		Added:   []api.TournamentResponse{},
+		Updated: []api.TournamentResponse{},
+	}
+	snapshot := &syncSnapshot{owner: owner, issued: now, fingerprints: make(map[string]string, len(tournaments))}
+	var seenBefore map[string]string
+	if previous != nil {
+		changes.Since = previous.issued.UTC().Format(time.RFC3339)
+		seenBefore = previous.fingerprints
+	}
+	for _, t := range tournaments {
+		fingerprint := tournamentFingerprint(t)
+		seen, known := seenBefore[t.ID]
+		switch {
+		case !known:
+			changes.Added = append(changes.Added, t)
+		case seen != fingerprint:
+			changes.Updated = append(changes.Updated, t)
+		default:
+			changes.Unchanged++
+		}
+		snapshot.fingerprints[t.ID] = fingerprint
+	}
+
+	if changes.SyncToken, err = s.syncTokens.issue(snapshot); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// errUnknownSyncToken rejects sync tokens that were not issued to the
+// caller or have expired
+var errUnknownSyncToken = errors.New("unknown or expired sync token; call without changes_since for a full sync")
+
+// handleGetTournamentChanges handles incremental tournament sync requests
+func (s *Server) handleGetTournamentChanges(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	token, _ := args["changes_since"].(string)
+	days := defaultChangesDays
+	if d, ok := args["days"].(float64); ok && d > 0 {
+		days = min(int(d), maxChangesDays)
+	}
+
+	changes, err := s.tournamentChanges(ctx, token, days, time.Now())
+	if errors.Is(err, errUnknownSyncToken) {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting tournament changes: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	data, _ := json.MarshalIndent(changes, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
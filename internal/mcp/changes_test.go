@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+func TestGetTournamentChanges(t *testing.T) {
+	dataset := &testserver.Dataset{
+		Tournaments: []testserver.Tournament{
+			{ID: "C2405-100-AAA", Name: "Mai Open", StartDate: "2024-05-10", EndDate: "2024-05-12", Participants: 40},
+			{ID: "C2405-100-BBB", Name: "Blitz", StartDate: "2024-05-11", EndDate: "2024-05-11", Participants: 20},
+		},
+	}
+	upstream := testserver.NewMockPortal64Server(testserver.Config{Dataset: dataset}).Start()
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	call := func(ctx context.Context, args map[string]interface{}) *TournamentChanges {
+		result, err := s.handleGetTournamentChanges(ctx, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].Text)
+		var changes TournamentChanges
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &changes))
+		return &changes
+	}
+
+	full := call(context.Background(), map[string]interface{}{})
+	assert.True(t, full.Full)
+	assert.Len(t, full.Added, 2)
+	assert.NotEmpty(t, full.SyncToken)
+
+	unchanged := call(context.Background(), map[string]interface{}{"changes_since": full.SyncToken})
+	assert.False(t, unchanged.Full)
+	assert.NotEmpty(t, unchanged.Since)
+	assert.Empty(t, unchanged.Added)
+	assert.Empty(t, unchanged.Updated)
+	assert.Equal(t, 2, unchanged.Unchanged)
+
+	dataset.Tournaments[1].Participants = 24
+	dataset.Tournaments = append(dataset.Tournaments, testserver.Tournament{ID: "C2405-100-CCC", Name: "Schnellschach", StartDate: "2024-05-18", EndDate: "2024-05-18"})
+	changed := call(context.Background(), map[string]interface{}{"changes_since": unchanged.SyncToken})
+	require.Len(t, changed.Added, 1)
+	assert.Equal(t, "C2405-100-CCC", changed.Added[0].ID)
+	require.Len(t, changed.Updated, 1)
+	assert.Equal(t, "C2405-100-BBB", changed.Updated[0].ID)
+	assert.Equal(t, 1, changed.Unchanged)
+
+	// Earlier tokens stay valid, so a lost response can be fetched again
+	again := call(context.Background(), map[string]interface{}{"changes_since": unchanged.SyncToken})
+	assert.Len(t, again.Added, 1)
+	assert.Len(t, again.Updated, 1)
+
+	// Fingerprints of tournaments that left the window are not kept
+	dataset.Tournaments = dataset.Tournaments[1:]
+	pruned := call(context.Background(), map[string]interface{}{"changes_since": changed.SyncToken})
+	assert.Equal(t, 2, pruned.Unchanged)
+	assert.Len(t, s.syncTokens.snapshots[pruned.SyncToken].fingerprints, 2)
+	assert.NotContains(t, s.syncTokens.snapshots[pruned.SyncToken].fingerprints, "C2405-100-AAA")
+
+	// Tokens belong to the caller that received them
+	session := &Session{ID: "other", values: make(map[string]interface{})}
+	result, err := s.handleGetTournamentChanges(withSession(context.Background(), session), map[string]interface{}{"changes_since": full.SyncToken})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Error: unknown or expired sync token; call without changes_since for a full sync", result.Content[0].Text)
+}
+
+func TestSyncTokens_Expiry(t *testing.T) {
+	var tokens syncTokens
+	issued := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	token, err := tokens.issue(&syncSnapshot{issued: issued, fingerprints: map[string]string{"C2405-100-AAA": "1"}})
+	require.NoError(t, err)
+
+	_, ok := tokens.get(token, "", issued.Add(syncTokenTTL))
+	assert.True(t, ok)
+	_, ok = tokens.get(token, "", issued.Add(syncTokenTTL+time.Second))
+	assert.False(t, ok)
+	assert.Zero(t, tokens.MemoryUsage().Entries)
+
+	for i := 0; i < maxSyncTokens+5; i++ {
+		_, err := tokens.issue(&syncSnapshot{issued: issued.Add(time.Duration(i) * time.Second)})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, maxSyncTokens, tokens.MemoryUsage().Entries)
+	assert.Positive(t, tokens.Evict(1))
+	assert.Equal(t, maxSyncTokens-1, tokens.MemoryUsage().Entries)
+}
@@ -0,0 +1,329 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/chart"
+)
+
+// Charts are rendered at defaultChartWidth x defaultChartHeight pixels
+// unless another size within the bounds of the chart package is requested
+const (
+	defaultChartWidth  = 800
+	defaultChartHeight = 400
+)
+
+// chartFormats are the MIME types of the formats of the chart tools
+var chartFormats = map[string]string{
+	"png": "image/png",
+	"svg": "image/svg+xml",
+}
+
+// distributionBucketSizes are the DWZ ranges of the bars of
+// render_club_distribution
+var distributionBucketSizes = map[int]bool{50: true, 100: true, 200: true}
+
+const defaultDistributionBucketSize = 100
+
+// chartImage is a chart of the chart package
+type chartImage interface {
+	SVG() ([]byte, error)
+	PNG() ([]byte, error)
+}
+
+// chartOptions are the format and size arguments shared by the chart tools
+type chartOptions struct {
+	format   string
+	mimeType string
+	width    int
+	height   int
+}
+
+// parseChartOptions reads format, width and height of a chart tool call
+func parseChartOptions(args map[string]interface{}) (chartOptions, error) {
+	options := chartOptions{format: "png", width: defaultChartWidth, height: defaultChartHeight}
+	if value, ok := args["format"].(string); ok && value != "" {
+		options.format = strings.ToLower(value)
+	}
+	mimeType, ok := chartFormats[options.format]
+	if !ok {
+		return options, fmt.Errorf("invalid format %q, expected png or svg", options.format)
+	}
+	options.mimeType = mimeType
+	if value, ok := args["width"].(float64); ok {
+		options.width = int(value)
+	}
+	if value, ok := args["height"].(float64); ok {
+		options.height = int(value)
+	}
+	if options.width < chart.MinWidth || options.width > chart.MaxWidth || options.height < chart.MinHeight || options.height > chart.MaxHeight {
+		return options, fmt.Errorf("width must be between %d and %d, height between %d and %d", chart.MinWidth, chart.MaxWidth, chart.MinHeight, chart.MaxHeight)
+	}
+	return options, nil
+}
+
+// chartSchema adds the format and size arguments to the properties of a
+// chart tool
+func chartSchema(properties map[string]interface{}) map[string]interface{} {
+	properties["format"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Image format (default: png)",
+		"enum":        []string{"png", "svg"},
+	}
+	properties["width"] = map[string]interface{}{
+		"type":        "integer",
+		"description": "Width in pixels (default: 800)",
+		"minimum":     chart.MinWidth,
+		"maximum":     chart.MaxWidth,
+	}
+	properties["height"] = map[string]interface{}{
+		"type":        "integer",
+		"description": "Height in pixels (default: 400)",
+		"minimum":     chart.MinHeight,
+		"maximum":     chart.MaxHeight,
+	}
+	return properties
+}
+
+// chartResult renders a chart as image content followed by a summary of
+// the plotted data
+func chartResult(c chartImage, options chartOptions, summary interface{}) *CallToolResponse {
+	var image []byte
+	var err error
+	if options.format == "svg" {
+		image, err = c.SVG()
+	} else {
+		image, err = c.PNG()
+	}
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error rendering chart: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	data, _ := json.MarshalIndent(summary, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{
+			{
+				Type:     "image",
+				Data:     base64.StdEncoding.EncodeToString(image),
+				MimeType: options.mimeType,
+			},
+			{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+	}
+}
+
+// RatingChart describes a rendered rating history
+type RatingChart struct {
+	PlayerID    string `json:"player_id"`
+	Format      string `json:"format"`
+	Evaluations int    `json:"evaluations"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	MinDWZ      int    `json:"min_dwz"`
+	MaxDWZ      int    `json:"max_dwz"`
+	CurrentDWZ  int    `json:"current_dwz"`
+}
+
+// handleRenderRatingChart renders the DWZ history of a player as a line
+// chart, returned as image content followed by a summary of the plotted
+// evaluations
+func (s *Server) handleRenderRatingChart(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	playerID, ok := args["player_id"].(string)
+	if !ok || playerID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: player_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	options, err := parseChartOptions(args)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	history, err := s.ratingHistory(ctx, playerID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting player rating history: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	c := &chart.LineChart{
+		Title:  fmt.Sprintf("DWZ history of %s", playerID),
+		Width:  options.width,
+		Height: options.height,
+	}
+	summary := RatingChart{PlayerID: playerID, Format: options.format}
+	for _, evaluation := range history {
+		if evaluation.NewDWZ <= 0 || evaluation.Date.IsZero() {
+			continue
+		}
+		c.Points = append(c.Points, chart.Point{Time: evaluation.Date, Value: float64(evaluation.NewDWZ)})
+		if summary.Evaluations == 0 || evaluation.NewDWZ < summary.MinDWZ {
+			summary.MinDWZ = evaluation.NewDWZ
+		}
+		summary.MaxDWZ = max(summary.MaxDWZ, evaluation.NewDWZ)
+		date := evaluation.Date.Format("2006-01-02")
+		if summary.Evaluations == 0 || date < summary.From {
+			summary.From = date
+		}
+		if date >= summary.To {
+			summary.To, summary.CurrentDWZ = date, evaluation.NewDWZ
+		}
+		summary.Evaluations++
+	}
+	if summary.Evaluations == 0 {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: player %s has no rated evaluations to chart", playerID),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	return chartResult(c, options, summary), nil
+}
+
+// ClubDistribution describes a rendered rating distribution of a club
+type ClubDistribution struct {
+	ClubID     string               `json:"club_id"`
+	Format     string               `json:"format"`
+	Members    int                  `json:"members"`
+	Rated      int                  `json:"rated"`
+	BucketSize int                  `json:"bucket_size"`
+	Buckets    []DistributionBucket `json:"buckets"`
+}
+
+// DistributionBucket is the number of members with a DWZ from From to To
+type DistributionBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// clubDistribution counts the rated members of a club by DWZ buckets of a
+// size, from the bucket of the lowest to the one of the highest rating
+func clubDistribution(clubID string, players []api.PlayerResponse, bucketSize int) ClubDistribution {
+	result := ClubDistribution{ClubID: clubID, Members: len(players), BucketSize: bucketSize, Buckets: []DistributionBucket{}}
+	low, high := 0, 0
+	for _, p := range players {
+		if p.CurrentDWZ <= 0 {
+			continue
+		}
+		bucket := p.CurrentDWZ / bucketSize * bucketSize
+		if result.Rated == 0 || bucket < low {
+			low = bucket
+		}
+		high = max(high, bucket)
+		result.Rated++
+	}
+	if result.Rated == 0 {
+		return result
+	}
+
+	for from := low; from <= high; from += bucketSize {
+		result.Buckets = append(result.Buckets, DistributionBucket{From: from, To: from + bucketSize - 1})
+	}
+	for _, p := range players {
+		if p.CurrentDWZ > 0 {
+			result.Buckets[(p.CurrentDWZ/bucketSize*bucketSize-low)/bucketSize].Count++
+		}
+	}
+	return result
+}
+
+// handleRenderClubDistribution renders the DWZ distribution of the members
+// of a club as a histogram, returned as image content followed by the
+// counts of the buckets
+func (s *Server) handleRenderClubDistribution(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, ok := args["club_id"].(string)
+	if !ok || clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	options, err := parseChartOptions(args)
+	bucketSize := defaultDistributionBucketSize
+	if value, ok := args["bucket_size"].(float64); ok {
+		bucketSize = int(value)
+	}
+	if err == nil && !distributionBucketSizes[bucketSize] {
+		err = fmt.Errorf("bucket_size must be 50, 100 or 200")
+	}
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	players, _, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club members: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	summary := clubDistribution(clubID, players, bucketSize)
+	summary.Format = options.format
+	if summary.Rated == 0 {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: club %s has no rated members to chart", clubID),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	h := &chart.Histogram{
+		Title:  fmt.Sprintf("DWZ distribution of %s", clubID),
+		Width:  options.width,
+		Height: options.height,
+	}
+	for _, bucket := range summary.Buckets {
+		h.Bars = append(h.Bars, chart.Bar{Label: strconv.Itoa(bucket.From), Count: bucket.Count})
+	}
+	return chartResult(h, options, summary), nil
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+func newChartTestServer(t *testing.T) *Server {
+	dataset := testserver.DefaultDataset()
+	date := func(year int, month time.Month) *time.Time {
+		d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	dataset.Histories = map[string][]testserver.HistoryEntry{
+		"C0327-297": {
+			{ID: 1, TournamentID: "C350-C01-SMU", TournamentDate: date(2021, 3), DWZOld: 1580, DWZNew: 1612},
+			{ID: 2, TournamentID: "C350-C02-SMU", TournamentDate: date(2022, 5), DWZOld: 1612, DWZNew: 1655},
+			{ID: 3, TournamentID: "C350-C03-SMU", TournamentDate: date(2023, 9), DWZOld: 1655, DWZNew: 1641},
+		},
+	}
+	upstream := testserver.NewMockPortal64Server(testserver.Config{Dataset: dataset}).Start()
+	t.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.registerTools()
+	return s
+}
+
+func TestHandleRenderRatingChart(t *testing.T) {
+	s := newChartTestServer(t)
+
+	result, err := s.handleRenderRatingChart(context.Background(), map[string]interface{}{"player_id": "C0327-297", "width": float64(400), "height": float64(200)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, "image/png", result.Content[0].MimeType)
+	data, err := base64.StdEncoding.DecodeString(result.Content[0].Data.(string))
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 400, img.Bounds().Dx())
+
+	var summary RatingChart
+	require.NoError(t, json.Unmarshal([]byte(result.Content[1].Text), &summary))
+	assert.Equal(t, RatingChart{
+		PlayerID: "C0327-297", Format: "png", Evaluations: 3,
+		From: "2021-03-01", To: "2023-09-01", MinDWZ: 1612, MaxDWZ: 1655, CurrentDWZ: 1641,
+	}, summary)
+
+	result, err = s.handleRenderRatingChart(context.Background(), map[string]interface{}{"player_id": "C0327-297", "format": "SVG"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	assert.Equal(t, "image/svg+xml", result.Content[0].MimeType)
+
+	for _, tc := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"missing player", map[string]interface{}{}},
+		{"format", map[string]interface{}{"player_id": "C0327-297", "format": "gif"}},
+		{"size", map[string]interface{}{"player_id": "C0327-297", "width": float64(10000)}},
+		{"empty history", map[string]interface{}{"player_id": "C0327-1"}},
+	} {
+		result, err := s.handleRenderRatingChart(context.Background(), tc.args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, tc.name)
+	}
+}
+
+func TestHTTPBridge_RatingChart(t *testing.T) {
+	s := newChartTestServer(t)
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/players/C0327-297/chart?format=svg&width=600", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "<svg"))
+	assert.Contains(t, rec.Body.String(), `width="600"`)
+}
+
+func TestClubDistribution(t *testing.T) {
+	players := []api.PlayerResponse{{CurrentDWZ: 1420}, {CurrentDWZ: 1499}, {CurrentDWZ: 1730}, {CurrentDWZ: 0}, {CurrentDWZ: 1500}}
+
+	result := clubDistribution("C0327", players, 100)
+	assert.Equal(t, 5, result.Members)
+	assert.Equal(t, 4, result.Rated)
+	assert.Equal(t, []DistributionBucket{
+		{From: 1400, To: 1499, Count: 2},
+		{From: 1500, To: 1599, Count: 1},
+		{From: 1600, To: 1699, Count: 0},
+		{From: 1700, To: 1799, Count: 1},
+	}, result.Buckets, "empty buckets between the lowest and highest rating are kept")
+
+	result = clubDistribution("C0327", players, 200)
+	assert.Equal(t, []DistributionBucket{{From: 1400, To: 1599, Count: 3}, {From: 1600, To: 1799, Count: 1}}, result.Buckets)
+
+	result = clubDistribution("C0327", nil, 100)
+	assert.Zero(t, result.Rated)
+	assert.Empty(t, result.Buckets)
+}
+
+func TestHandleRenderClubDistribution(t *testing.T) {
+	s := newChartTestServer(t)
+
+	result, err := s.handleRenderClubDistribution(context.Background(), map[string]interface{}{"club_id": "C0327", "format": "svg"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	assert.Equal(t, "image/svg+xml", result.Content[0].MimeType)
+	var summary ClubDistribution
+	require.NoError(t, json.Unmarshal([]byte(result.Content[1].Text), &summary))
+	assert.Equal(t, 100, summary.BucketSize)
+	assert.Positive(t, summary.Rated)
+
+	result, err = s.handleRenderClubDistribution(context.Background(), map[string]interface{}{"club_id": "C0327", "bucket_size": float64(75)})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "bucket_size")
+
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/clubs/C0327/distribution?bucket_size=200", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/aliases"
+)
+
+// clubAliases holds the aliases of merged and renamed clubs
+type clubAliases struct {
+	mu sync.RWMutex
+	// file holds the aliases of club_aliases.file, which replace those
+	// fetched from club_aliases.url
+	file    *aliases.Map
+	current *aliases.Map
+}
+
+// resolve follows the aliases of a club ID
+func (c *clubAliases) resolve(id string) (string, []aliases.Alias) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current.Resolve(id)
+}
+
+// set replaces the aliases in effect
+func (c *clubAliases) set(m *aliases.Map) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = m
+}
+
+// loadClubAliases reads the aliases of club_aliases.file. Without a valid
+// file no aliases are followed until the URL, if any, is fetched.
+func (s *Server) loadClubAliases() {
+	path := s.config.ClubAliases.File
+	if path == "" {
+		return
+	}
+	m, err := aliases.LoadFile(path)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to load club aliases")
+		return
+	}
+	s.clubAliases.file = m
+	s.clubAliases.set(m)
+	s.logger.WithField("aliases", m.Len()).Info("Loaded club aliases")
+}
+
+// refreshClubAliases fetches the aliases of club_aliases.url and merges
+// them with those of the file. The aliases in effect are kept if the fetch
+// fails.
+func (s *Server) refreshClubAliases(ctx context.Context) {
+	client := &http.Client{Timeout: s.config.API.Timeout}
+	fetched, err := aliases.Fetch(ctx, client, s.config.ClubAliases.URL)
+	if err == nil {
+		fetched, err = aliases.Merge(fetched, s.clubAliases.file)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.WithError(err).Warn("Failed to refresh club aliases")
+		}
+		return
+	}
+	s.clubAliases.set(fetched)
+	s.logger.WithField("aliases", fetched.Len()).Debug("Refreshed club aliases")
+}
+
+// runClubAliasJob fetches the aliases of club_aliases.url now and then at
+// every interval until the context is done
+func (s *Server) runClubAliasJob(ctx context.Context, interval time.Duration) {
+	s.refreshClubAliases(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshClubAliases(ctx)
+		}
+	}
+}
+
+// describeAliases explains the aliases followed from a club ID, e.g.
+// "C0100 was merged into C0200 on 2019-07-01"
+func describeAliases(chain []aliases.Alias) string {
+	steps := make([]string, len(chain))
+	for i, alias := range chain {
+		verb := "was replaced by"
+		switch alias.Reason {
+		case "merger":
+			verb = "was merged into"
+		case "renaming":
+			verb = "was renamed to"
+		}
+		step := alias.From
+		if alias.Name != "" {
+			step += " (" + alias.Name + ")"
+		}
+		step += " " + verb + " " + alias.To
+		if alias.Date != "" {
+			step += " on " + alias.Date
+		}
+		steps[i] = step
+	}
+	return strings.Join(steps, ", ")
+}
+
+// followClubAliases wraps a tool handler so that a historic club_id is
+// replaced by the ID of the club it was merged into or renamed to, with a
+// warning naming the aliases followed
+func (s *Server) followClubAliases(handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		id, _ := args["club_id"].(string)
+		if id == "" {
+			return handler(ctx, args)
+		}
+		current, chain := s.clubAliases.resolve(id)
+		if len(chain) == 0 {
+			return handler(ctx, args)
+		}
+
+		resolved := make(map[string]interface{}, len(args))
+		for k, v := range args {
+			resolved[k] = v
+		}
+		resolved["club_id"] = current
+		addWarning(ctx, fmt.Sprintf("club_id %s is historic, the result is for %s: %s", id, current, describeAliases(chain)))
+		return handler(ctx, resolved)
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/aliases"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestFollowClubAliases(t *testing.T) {
+	s := newTestServer()
+	m, err := aliases.New([]aliases.Alias{
+		{From: "C0100", To: "C0200", Date: "2010-01-01", Reason: "merger", Name: "SK Alt"},
+		{From: "C0200", To: "C0300", Reason: "renaming"},
+	})
+	require.NoError(t, err)
+	s.clubAliases.set(m)
+
+	var got map[string]interface{}
+	handler := normalizeIDArgs(s.followClubAliases(func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		got = args
+		return &CallToolResponse{}, nil
+	}))
+
+	ctx, collector := withWarnings(context.Background())
+	args := map[string]interface{}{"club_id": "c100", "season": "2023/24"}
+	_, err = handler(ctx, args)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"club_id": "C0300", "season": "2023/24"}, got)
+	assert.Equal(t, "c100", args["club_id"])
+	assert.Equal(t, []string{"club_id C0100 is historic, the result is for C0300: C0100 (SK Alt) was merged into C0200 on 2010-01-01, C0200 was renamed to C0300"}, collector.warnings)
+
+	ctx, collector = withWarnings(context.Background())
+	_, err = handler(ctx, map[string]interface{}{"club_id": "C0300"})
+	require.NoError(t, err)
+	assert.Equal(t, "C0300", got["club_id"])
+	assert.Empty(t, collector.warnings)
+}
+
+func TestFollowClubAliases_Profile(t *testing.T) {
+	s := newProfileTestServer(t)
+	m, err := aliases.New([]aliases.Alias{{From: "C0100", To: "C0327", Reason: "renaming"}})
+	require.NoError(t, err)
+	s.clubAliases.set(m)
+
+	response, err := s.handleMessage([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_club_profile","arguments":{"club_id":"C0100","profile":"test"}}}`))
+	require.NoError(t, err)
+	require.Nil(t, response.Error)
+	result := string(mustMarshal(t, response.Result))
+	assert.Contains(t, result, "Test")
+	assert.Contains(t, result, "club_id C0100 is historic, the result is for C0327")
+}
+
+func TestRefreshClubAliases(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"from":"C0100","to":"C0200"},{"from":"C0101","to":"C0201"}]`))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"from":"C0100","to":"C0300"}]`), 0o600))
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.config = &config.Config{
+		API:         config.APIConfig{Timeout: 5 * time.Second},
+		ClubAliases: config.ClubAliasesConfig{File: path, URL: upstream.URL},
+	}
+
+	s.loadClubAliases()
+	current, _ := s.clubAliases.resolve("C0101")
+	assert.Equal(t, "C0101", current)
+
+	// Aliases of the file take precedence over those of the URL
+	s.refreshClubAliases(context.Background())
+	current, _ = s.clubAliases.resolve("C0100")
+	assert.Equal(t, "C0300", current)
+	current, _ = s.clubAliases.resolve("C0101")
+	assert.Equal(t, "C0201", current)
+
+	// A failed refresh keeps the aliases in effect
+	upstream.Close()
+	s.refreshClubAliases(context.Background())
+	current, _ = s.clubAliases.resolve("C0101")
+	assert.Equal(t, "C0201", current)
+}
+
+func TestResolveID_ClubAlias(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/clubs/C0200/profile" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"club":{"id":"C0200","name":"SF Neu"}}`))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	m, err := aliases.New([]aliases.Alias{{From: "C0100", To: "C0200", Reason: "merger"}})
+	require.NoError(t, err)
+	s.clubAliases.set(m)
+
+	result, err := s.handleResolveID(context.Background(), map[string]interface{}{"id": "C0100", "kind": "club"})
+	require.NoError(t, err)
+
+	var resolution IDResolution
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &resolution))
+	assert.True(t, resolution.Exists)
+	assert.Equal(t, "C0100", resolution.Normalized)
+	assert.Equal(t, "C0200", resolution.CurrentID)
+	require.Len(t, resolution.Aliases, 1)
+	assert.Equal(t, "merger", resolution.Aliases[0].Reason)
+}
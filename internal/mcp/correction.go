@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/features"
+	"github.com/svw-info/portal64gomcp/internal/mail"
+)
+
+// contactField is a field of the club contact details
+type contactField struct {
+	name  string
+	label string
+	value func(c *api.ClubContact) string
+}
+
+// contactFields are the club contact fields a correction may change, in
+// the order of the correction request
+var contactFields = []contactField{
+	{"president", "President", func(c *api.ClubContact) string { return c.President }},
+	{"vice_president", "Vice president", func(c *api.ClubContact) string { return c.VicePresident }},
+	{"secretary", "Secretary", func(c *api.ClubContact) string { return c.Secretary }},
+	{"treasurer", "Treasurer", func(c *api.ClubContact) string { return c.Treasurer }},
+	{"coach", "Coach", func(c *api.ClubContact) string { return c.Coach }},
+	{"email", "Email", func(c *api.ClubContact) string { return c.Email }},
+	{"phone", "Phone", func(c *api.ClubContact) string { return c.Phone }},
+	{"website", "Website", func(c *api.ClubContact) string { return c.Website }},
+	{"address", "Address", func(c *api.ClubContact) string { return c.Address }},
+}
+
+// ContactChange is a field of the club contact details with its current
+// and proposed value
+type ContactChange struct {
+	Field    string `json:"field"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+}
+
+// ContactCorrectionDraft is a correction request of club contact details
+type ContactCorrectionDraft struct {
+	ClubID   string          `json:"club_id"`
+	ClubName string          `json:"club_name"`
+	To       string          `json:"to,omitempty"` // Configured recipient
+	Subject  string          `json:"subject"`
+	Body     string          `json:"body"`
+	Changes  []ContactChange `json:"changes"`
+	Sent     bool            `json:"sent"`
+}
+
+// contactChanges compares proposed values with the current contact details.
+// Fields that are not proposed or equal to the current value are skipped.
+func contactChanges(current *api.ClubContact, args map[string]interface{}) []ContactChange {
+	if current == nil {
+		current = &api.ClubContact{}
+	}
+	var changes []ContactChange
+	for _, field := range contactFields {
+		proposed, ok := args[field.name].(string)
+		if !ok {
+			continue
+		}
+		proposed = strings.TrimSpace(proposed)
+		if value := field.value(current); proposed != strings.TrimSpace(value) {
+			changes = append(changes, ContactChange{Field: field.name, Current: value, Proposed: proposed})
+		}
+	}
+	return changes
+}
+
+// contactFieldLabel returns the label of a contact field in the request
+func contactFieldLabel(name string) string {
+	for _, field := range contactFields {
+		if field.name == name {
+			return field.label
+		}
+	}
+	return name
+}
+
+// formatContactCorrection writes the body of a correction request: the
+// changes as a table of current and proposed values, followed by the reason
+// and the submitter if given
+func formatContactCorrection(clubName, clubID string, changes []ContactChange, reason, submitter string) string {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "Hello,\n\nplease update the contact details of %s (%s) in the DWZ database:\n\n", clubName, clubID)
+
+	table := tabwriter.NewWriter(&body, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Field\tCurrent\tProposed")
+	for _, change := range changes {
+		current, proposed := change.Current, change.Proposed
+		if current == "" {
+			current = "(none)"
+		}
+		if proposed == "" {
+			proposed = "(remove)"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\n", contactFieldLabel(change.Field), current, proposed)
+	}
+	table.Flush()
+
+	if reason != "" {
+		fmt.Fprintf(&body, "\nReason: %s\n", reason)
+	}
+	if submitter != "" {
+		fmt.Fprintf(&body, "\nSubmitted by: %s\n", submitter)
+	}
+	body.WriteString("\nThank you.\n")
+	return body.String()
+}
+
+// handleDraftContactCorrection handles club contact correction requests
+func (s *Server) handleDraftContactCorrection(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, _ := args["club_id"].(string)
+	if clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	send, _ := args["send"].(bool)
+	if send && s.mailer == nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: sending is not configured (mail.smtp_host), draft the request without send",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	profile, err := s.apiClient.GetClubProfile(ctx, clubID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club profile: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	changes := contactChanges(profile.Contact, args)
+	if len(changes) == 0 {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: no proposed value differs from the current contact details",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if s.features.Enabled(features.PrivacyMode) {
+		// The current values are only needed by the recipient
+		for i := range changes {
+			if changes[i].Field == "phone" || changes[i].Field == "address" {
+				changes[i].Current = ""
+			}
+		}
+	}
+
+	clubName := clubID
+	if profile.Club != nil && profile.Club.Name != "" {
+		clubName = profile.Club.Name
+	}
+	reason, _ := args["reason"].(string)
+	submitter, _ := args["submitter"].(string)
+	draft := ContactCorrectionDraft{
+		ClubID:   clubID,
+		ClubName: clubName,
+		Subject:  fmt.Sprintf("Contact details correction for %s (%s)", clubName, clubID),
+		Body:     formatContactCorrection(clubName, clubID, changes, strings.TrimSpace(reason), strings.TrimSpace(submitter)),
+		Changes:  changes,
+	}
+	if s.config != nil {
+		draft.To = s.config.Mail.To
+	}
+
+	if send {
+		err := s.mailer.Send(ctx, mail.Message{
+			From:    s.config.Mail.From,
+			To:      []string{draft.To},
+			Subject: draft.Subject,
+			Body:    draft.Body,
+		})
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error sending correction request: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		draft.Sent = true
+		s.logger.WithField("club_id", clubID).WithField("to", draft.To).Info("Sent contact correction request")
+	}
+
+	data, _ := json.MarshalIndent(draft, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/mail"
+)
+
+// recordingMailer records the messages sent
+type recordingMailer struct {
+	messages []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// newCorrectionTestServer returns a test server whose upstream serves the
+// profile of club C0327
+func newCorrectionTestServer(t *testing.T) *Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/clubs/C0327/profile", r.URL.Path)
+		w.Write([]byte(`{"club":{"id":"C0327","name":"SF Ulm"},"contact":{"president":"Anna Alt","email":"info@sf-ulm.de","website":""}}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.config = &config.Config{Mail: config.MailConfig{From: "mcp@example.org", To: "dwz@example.org"}}
+	s.registerTools()
+	return s
+}
+
+func TestDraftContactCorrection_Draft(t *testing.T) {
+	s := newCorrectionTestServer(t)
+
+	result, err := s.handleDraftContactCorrection(s.ctx, map[string]interface{}{
+		"club_id":   "C0327",
+		"president": "Bernd Neu",
+		"email":     "info@sf-ulm.de",
+		"website":   "https://sf-ulm.de",
+		"reason":    "Annual meeting on 2026-03-01",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var draft ContactCorrectionDraft
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &draft))
+	assert.Equal(t, "SF Ulm", draft.ClubName)
+	assert.Equal(t, "dwz@example.org", draft.To)
+	assert.Equal(t, "Contact details correction for SF Ulm (C0327)", draft.Subject)
+	assert.False(t, draft.Sent)
+	assert.Equal(t, []ContactChange{
+		{Field: "president", Current: "Anna Alt", Proposed: "Bernd Neu"},
+		{Field: "website", Current: "", Proposed: "https://sf-ulm.de"},
+	}, draft.Changes)
+	assert.Contains(t, draft.Body, "President  Anna Alt  Bernd Neu")
+	assert.Contains(t, draft.Body, "Website    (none)    https://sf-ulm.de")
+	assert.Contains(t, draft.Body, "Reason: Annual meeting on 2026-03-01")
+	assert.NotContains(t, draft.Body, "Email")
+}
+
+func TestDraftContactCorrection_Send(t *testing.T) {
+	s := newCorrectionTestServer(t)
+	mailer := &recordingMailer{}
+	s.mailer = mailer
+
+	result, err := s.handleDraftContactCorrection(s.ctx, map[string]interface{}{"club_id": "C0327", "president": "Bernd Neu", "send": true})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	assert.Contains(t, result.Content[0].Text, `"sent": true`)
+
+	require.Len(t, mailer.messages, 1)
+	assert.Equal(t, "mcp@example.org", mailer.messages[0].From)
+	assert.Equal(t, []string{"dwz@example.org"}, mailer.messages[0].To)
+	assert.Contains(t, mailer.messages[0].Body, "Bernd Neu")
+}
+
+func TestDraftContactCorrection_Errors(t *testing.T) {
+	s := newCorrectionTestServer(t)
+
+	testCases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing club", map[string]interface{}{"president": "Bernd Neu"}, "Error: club_id is required"},
+		{"no changes", map[string]interface{}{"club_id": "C0327", "president": " Anna Alt "}, "Error: no proposed value differs from the current contact details"},
+		{"send not configured", map[string]interface{}{"club_id": "C0327", "president": "Bernd Neu", "send": true}, "Error: sending is not configured (mail.smtp_host), draft the request without send"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := s.handleDraftContactCorrection(s.ctx, tc.args)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Equal(t, tc.want, result.Content[0].Text)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// dateRangeChunkDays is the longest date range searched with a single
+	// upstream query; longer ranges are split into chunks of this length
+	dateRangeChunkDays = 366
+	// dateChunkConcurrency limits parallel searches of date range chunks
+	dateChunkConcurrency = 4
+	// maxDateChunkPages bounds the search pages fetched per chunk
+	maxDateChunkPages = 10
+)
+
+// dateChunk is a part of a date range, both days inclusive
+type dateChunk struct {
+	start, end time.Time
+}
+
+// splitDateRange splits a date range into consecutive chunks of at most the
+// given number of days
+func splitDateRange(start, end time.Time, days int) []dateChunk {
+	var chunks []dateChunk
+	for chunkStart := start; !chunkStart.After(end); chunkStart = chunkStart.AddDate(0, 0, days) {
+		chunkEnd := chunkStart.AddDate(0, 0, days-1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		chunks = append(chunks, dateChunk{start: chunkStart, end: chunkEnd})
+	}
+	return chunks
+}
+
+// dateRangeSearch returns a tournament search over a date range. Ranges of
+// up to dateRangeChunkDays are searched with a single upstream query. Longer
+// ranges are split into chunks searched concurrently, so that no upstream
+// query runs into the timeout; their tournaments are merged without
+// duplicates, ordered by start date and paged locally. Merged results are
+// kept for the call, so that filters and retries page through them without
+// further requests. A chunk that fails is left out with a warning; the
+// search fails only if all chunks fail.
+func (s *Server) dateRangeSearch(ctx context.Context, start, end time.Time) func(api.SearchParams) (*api.SearchResponse, error) {
+	chunks := splitDateRange(start, end, dateRangeChunkDays)
+	if len(chunks) == 1 {
+		return func(params api.SearchParams) (*api.SearchResponse, error) {
+			return s.apiClient.SearchTournamentsByDate(ctx, api.DateRangeParams{StartDate: start, EndDate: end, SearchParams: params})
+		}
+	}
+
+	merged := make(map[api.SearchParams][]api.TournamentResponse)
+	return func(params api.SearchParams) (*api.SearchResponse, error) {
+		key := params
+		key.Limit, key.Offset = 0, 0
+		tournaments, ok := merged[key]
+		if !ok {
+			var err error
+			if tournaments, err = s.searchDateChunks(ctx, chunks, key); err != nil {
+				return nil, err
+			}
+			merged[key] = tournaments
+		}
+
+		from := min(params.Offset, len(tournaments))
+		to := len(tournaments)
+		if params.Limit > 0 {
+			to = min(from+params.Limit, to)
+		}
+		pagination := api.PaginationMetadata{
+			Total:  len(tournaments),
+			Limit:  params.Limit,
+			Offset: params.Offset,
+			Count:  to - from,
+		}
+		if params.Limit > 0 {
+			pagination.Pages = pageCount(len(tournaments), params.Limit)
+			pagination.Page = params.Offset/params.Limit + 1
+		}
+		return &api.SearchResponse{Data: tournaments[from:to], Pagination: pagination}, nil
+	}
+}
+
+// searchDateChunks searches the chunks of a date range concurrently and
+// merges their tournaments
+func (s *Server) searchDateChunks(ctx context.Context, chunks []dateChunk, params api.SearchParams) ([]api.TournamentResponse, error) {
+	results := make([][]api.TournamentResponse, len(chunks))
+	errs := make([]error, len(chunks))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, dateChunkConcurrency)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk dateChunk) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i], errs[i] = s.searchDateChunk(ctx, chunk, params)
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, done, len(chunks), fmt.Sprintf("Searched %d of %d date ranges", done, len(chunks)), nil)
+			mu.Unlock()
+		}(i, chunk)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			addWarning(ctx, fmt.Sprintf("tournaments from %s to %s are missing: %v", chunks[i].start.Format("2006-01-02"), chunks[i].end.Format("2006-01-02"), err))
+		}
+	}
+	if failed == len(chunks) {
+		return nil, errs[0]
+	}
+
+	// Tournaments spanning the border of two chunks are found in both
+	seen := make(map[string]bool)
+	tournaments := []api.TournamentResponse{}
+	for _, chunk := range results {
+		for _, t := range chunk {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				tournaments = append(tournaments, t)
+			}
+		}
+	}
+	sort.SliceStable(tournaments, func(i, j int) bool {
+		return tournamentStart(tournaments[i]).Before(tournamentStart(tournaments[j]))
+	})
+	return tournaments, nil
+}
+
+// searchDateChunk fetches the search pages of a chunk of a date range, at
+// most maxDateChunkPages pages
+func (s *Server) searchDateChunk(ctx context.Context, chunk dateChunk, params api.SearchParams) ([]api.TournamentResponse, error) {
+	var tournaments []api.TournamentResponse
+	for page := 0; page < maxDateChunkPages; page++ {
+		pageParams := params
+		pageParams.Limit = aggregatePageSize
+		pageParams.Offset = page * aggregatePageSize
+		result, err := s.apiClient.SearchTournamentsByDate(ctx, api.DateRangeParams{StartDate: chunk.start, EndDate: chunk.end, SearchParams: pageParams})
+		if err != nil {
+			return nil, err
+		}
+
+		pageTournaments, _ := result.Data.([]api.TournamentResponse)
+		tournaments = append(tournaments, pageTournaments...)
+
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		if len(pageTournaments) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			return tournaments, nil
+		}
+	}
+	addWarning(ctx, fmt.Sprintf("only the first %d tournaments from %s to %s were searched", maxDateChunkPages*aggregatePageSize,
+		chunk.start.Format("2006-01-02"), chunk.end.Format("2006-01-02")))
+	return tournaments, nil
+}
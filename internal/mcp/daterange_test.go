@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+func TestSplitDateRange(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", s, portalLocation)
+		return d
+	}
+
+	chunks := splitDateRange(date("2020-01-01"), date("2020-01-10"), 4)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, dateChunk{date("2020-01-01"), date("2020-01-04")}, chunks[0])
+	assert.Equal(t, dateChunk{date("2020-01-05"), date("2020-01-08")}, chunks[1])
+	assert.Equal(t, dateChunk{date("2020-01-09"), date("2020-01-10")}, chunks[2])
+
+	assert.Len(t, splitDateRange(date("2020-01-01"), date("2020-01-01"), 4), 1)
+	assert.Len(t, splitDateRange(date("2020-01-01"), date("2020-12-31"), dateRangeChunkDays), 1)
+	assert.Len(t, splitDateRange(date("2015-01-01"), date("2024-12-31"), dateRangeChunkDays), 10)
+}
+
+func TestDateRangeSearch_Chunks(t *testing.T) {
+	mock := testserver.NewMockPortal64Server(testserver.Config{Dataset: &testserver.Dataset{
+		Tournaments: []testserver.Tournament{
+			{ID: "C2201-100-AAA", Name: "Winter Open", StartDate: "2022-01-10", EndDate: "2022-01-12"},
+			{ID: "C1912-100-BBB", Name: "Silvester Open", StartDate: "2019-12-30", EndDate: "2020-01-02"},
+			{ID: "C2006-100-CCC", Name: "Sommer Open", StartDate: "2020-06-01", EndDate: "2020-06-03"},
+			{ID: "C1801-100-OLD", Name: "Old Open", StartDate: "2018-01-01", EndDate: "2018-01-02"},
+		},
+	}})
+	upstream := mock.Start()
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, collector := withWarnings(context.Background())
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, portalLocation)
+	end := time.Date(2022, 12, 31, 0, 0, 0, 0, portalLocation)
+	search := s.dateRangeSearch(ctx, start, end)
+
+	result, err := search(api.SearchParams{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, mock.RequestCount("/api/v1/tournaments/search"))
+	assert.Equal(t, 3, result.Pagination.Total)
+	assert.Equal(t, 2, result.Pagination.Pages)
+	tournaments := result.Data.([]api.TournamentResponse)
+	require.Len(t, tournaments, 2)
+	assert.Equal(t, "C1912-100-BBB", tournaments[0].ID)
+	assert.Equal(t, "C2006-100-CCC", tournaments[1].ID)
+
+	// Further pages are served from the merged results
+	result, err = search(api.SearchParams{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, mock.RequestCount("/api/v1/tournaments/search"))
+	assert.Equal(t, 2, result.Pagination.Page)
+	tournaments = result.Data.([]api.TournamentResponse)
+	require.Len(t, tournaments, 1)
+	assert.Equal(t, "C2201-100-AAA", tournaments[0].ID)
+	assert.Empty(t, collector.warnings)
+}
+
+func TestDateRangeSearch_PartialFailure(t *testing.T) {
+	mock := testserver.NewMockPortal64Server(testserver.Config{
+		Errors: []testserver.ErrorScenario{{PathPrefix: "/api/v1/tournaments/search", StatusCode: http.StatusInternalServerError, Message: "boom", Times: 1}},
+	})
+	upstream := mock.Start()
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, collector := withWarnings(context.Background())
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, portalLocation)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, portalLocation)
+
+	_, err := s.dateRangeSearch(ctx, start, end)(api.SearchParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, collector.warnings, 1)
+	assert.Contains(t, collector.warnings[0], "are missing")
+}
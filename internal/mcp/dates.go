@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Europe/Berlin on systems without a zoneinfo database
+)
+
+// portalLocation is the time zone of Portal64. Dates of date-range tools are
+// calendar days in this zone, and relative expressions count from its
+// current day.
+var portalLocation = mustLoadLocation("Europe/Berlin")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("time zone %s: %v", name, err))
+	}
+	return loc
+}
+
+// maxDateRangeYears bounds the length of date ranges
+const maxDateRangeYears = 10
+
+// dateExpressionHelp lists the accepted date expressions for error messages
+// and schema descriptions
+const dateExpressionHelp = "YYYY-MM-DD, DD.MM.YYYY, an ISO date-time, YYYY-MM, YYYY, YYYY-Qn, today, yesterday, " +
+	"this/last/next week, month or year, or last/next N days, weeks, months or years"
+
+var (
+	quarterPattern  = regexp.MustCompile(`^(\d{4})-?q([1-4])$|^q([1-4])[ /-]?(\d{4})$`)
+	relativePattern = regexp.MustCompile(`^(last|past|next) (\d{1,4}) (day|week|month|year)s?$`)
+	periodPattern   = regexp.MustCompile(`^(this|last|next) (week|month|year)$`)
+)
+
+// portalDay returns the start of the calendar day of t in the Portal64 time zone
+func portalDay(t time.Time) time.Time {
+	y, m, d := t.In(portalLocation).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, portalLocation)
+}
+
+// parseDateExpression resolves a date expression to the first and last day
+// of the period it denotes. Single dates are periods of one day; date-times
+// are converted to the Portal64 time zone before their day is taken, and
+// date-times without offset are taken to be in that zone.
+func parseDateExpression(value string, now time.Time) (first, last time.Time, err error) {
+	expr := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	today := portalDay(now)
+
+	for _, layout := range []string{"2006-01-02", "2.1.2006"} {
+		if t, err := time.ParseInLocation(layout, expr, portalLocation); err == nil {
+			return t, t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, strings.ToUpper(expr)); err == nil {
+		return portalDay(t), portalDay(t), nil
+	}
+	for _, layout := range []string{"2006-01-02t15:04:05", "2006-01-02t15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, expr, portalLocation); err == nil {
+			return portalDay(t), portalDay(t), nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01", expr, portalLocation); err == nil {
+		return t, t.AddDate(0, 1, -1), nil
+	}
+	if t, err := time.ParseInLocation("2006", expr, portalLocation); err == nil {
+		return t, t.AddDate(1, 0, -1), nil
+	}
+	if m := quarterPattern.FindStringSubmatch(expr); m != nil {
+		year, quarter := m[1]+m[4], m[2]+m[3]
+		y, _ := strconv.Atoi(year)
+		q, _ := strconv.Atoi(quarter)
+		first = time.Date(y, time.Month(3*q-2), 1, 0, 0, 0, 0, portalLocation)
+		return first, first.AddDate(0, 3, -1), nil
+	}
+
+	switch expr {
+	case "today":
+		return today, today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 1), nil
+	}
+	if m := relativePattern.FindStringSubmatch(expr); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		offset := func(sign int) time.Time {
+			switch m[3] {
+			case "week":
+				return today.AddDate(0, 0, sign*7*n)
+			case "month":
+				return today.AddDate(0, sign*n, 0)
+			case "year":
+				return today.AddDate(sign*n, 0, 0)
+			}
+			return today.AddDate(0, 0, sign*n)
+		}
+		if m[1] == "next" {
+			return today, offset(1), nil
+		}
+		return offset(-1), today, nil
+	}
+	if m := periodPattern.FindStringSubmatch(expr); m != nil {
+		shift := map[string]int{"this": 0, "last": -1, "next": 1}[m[1]]
+		switch m[2] {
+		case "week":
+			monday := today.AddDate(0, 0, -((int(today.Weekday())+6)%7)+7*shift)
+			return monday, monday.AddDate(0, 0, 6), nil
+		case "month":
+			first = time.Date(today.Year(), today.Month()+time.Month(shift), 1, 0, 0, 0, 0, portalLocation)
+			return first, first.AddDate(0, 1, -1), nil
+		default:
+			first = time.Date(today.Year()+shift, 1, 1, 0, 0, 0, 0, portalLocation)
+			return first, first.AddDate(1, 0, -1), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q, expected %s", value, dateExpressionHelp)
+}
+
+// dateRangeArgs resolves the period or the start_date and end_date
+// arguments of a date-range tool. A period such as "2024-Q1" as start_date
+// starts the range with its first day, as end_date ends it with its last
+// day. Reversed ranges are swapped and ranges of more than
+// maxDateRangeYears shortened to their end. Expressions other than
+// YYYY-MM-DD and corrections of the range are explained with a warning.
+func dateRangeArgs(args map[string]interface{}, now time.Time) (start, end time.Time, notes []string, err error) {
+	period, _ := args["period"].(string)
+	startArg, _ := args["start_date"].(string)
+	endArg, _ := args["end_date"].(string)
+	period, startArg, endArg = strings.TrimSpace(period), strings.TrimSpace(startArg), strings.TrimSpace(endArg)
+
+	note := func(name, value string, first, last time.Time) {
+		if _, err := time.Parse("2006-01-02", value); err == nil {
+			return
+		}
+		if first.Equal(last) {
+			notes = append(notes, fmt.Sprintf("%s %q was read as %s (Europe/Berlin)", name, value, first.Format("2006-01-02")))
+			return
+		}
+		notes = append(notes, fmt.Sprintf("%s %q was read as %s to %s (Europe/Berlin)", name, value, first.Format("2006-01-02"), last.Format("2006-01-02")))
+	}
+
+	switch {
+	case period != "" && (startArg != "" || endArg != ""):
+		return start, end, nil, fmt.Errorf("use either period or start_date and end_date")
+	case period != "":
+		if start, end, err = parseDateExpression(period, now); err != nil {
+			return start, end, nil, fmt.Errorf("period: %w", err)
+		}
+		note("period", period, start, end)
+	case startArg == "" || endArg == "":
+		return start, end, nil, fmt.Errorf("start_date and end_date or period are required (format: YYYY-MM-DD)")
+	default:
+		first, last, err := parseDateExpression(startArg, now)
+		if err != nil {
+			return start, end, nil, fmt.Errorf("start_date: %w", err)
+		}
+		note("start_date", startArg, first, last)
+		start = first
+		if first, last, err = parseDateExpression(endArg, now); err != nil {
+			return start, end, nil, fmt.Errorf("end_date: %w", err)
+		}
+		note("end_date", endArg, first, last)
+		end = last
+	}
+
+	if start.After(end) {
+		notes = append(notes, fmt.Sprintf("start_date %s was after end_date %s, the dates were swapped", start.Format("2006-01-02"), end.Format("2006-01-02")))
+		start, end = end, start
+	}
+	if earliest := end.AddDate(-maxDateRangeYears, 0, 1); start.Before(earliest) {
+		notes = append(notes, fmt.Sprintf("the range from %s exceeds %d years and was shortened to start on %s", start.Format("2006-01-02"), maxDateRangeYears, earliest.Format("2006-01-02")))
+		start = earliest
+	}
+	return start, end, notes, nil
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateExpression(t *testing.T) {
+	// Wednesday, 23:30 in UTC is already Thursday in Berlin
+	now := time.Date(2024, 5, 15, 22, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		input string
+		first string
+		last  string
+	}{
+		{"2024-03-15", "2024-03-15", "2024-03-15"},
+		{"15.03.2024", "2024-03-15", "2024-03-15"},
+		{"5.3.2024", "2024-03-05", "2024-03-05"},
+		{"2024-03-15T23:30:00Z", "2024-03-16", "2024-03-16"},
+		{"2024-03-15T23:30:00-05:00", "2024-03-16", "2024-03-16"},
+		{"2024-03-15T10:00", "2024-03-15", "2024-03-15"},
+		{"2024-02", "2024-02-01", "2024-02-29"},
+		{"2023", "2023-01-01", "2023-12-31"},
+		{"2024-Q1", "2024-01-01", "2024-03-31"},
+		{"Q4 2023", "2023-10-01", "2023-12-31"},
+		{"today", "2024-05-16", "2024-05-16"},
+		{"Yesterday", "2024-05-15", "2024-05-15"},
+		{"last 90 days", "2024-02-16", "2024-05-16"},
+		{"past  2 weeks", "2024-05-02", "2024-05-16"},
+		{"next 1 month", "2024-05-16", "2024-06-16"},
+		{"this week", "2024-05-13", "2024-05-19"},
+		{"last month", "2024-04-01", "2024-04-30"},
+		{"next year", "2025-01-01", "2025-12-31"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			first, last, err := parseDateExpression(tc.input, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.first, first.Format("2006-01-02"))
+			assert.Equal(t, tc.last, last.Format("2006-01-02"))
+			assert.Equal(t, portalLocation, first.Location())
+		})
+	}
+
+	for _, input := range []string{"", "2024-13-01", "31.02.2024", "2024-Q5", "last days", "soon"} {
+		_, _, err := parseDateExpression(input, now)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDateRangeArgs(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+
+	start, end, notes, err := dateRangeArgs(map[string]interface{}{"start_date": "2024-01-01", "end_date": "2024-03-31"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2024-03-31", end.Format("2006-01-02"))
+	assert.Empty(t, notes)
+
+	start, end, notes, err = dateRangeArgs(map[string]interface{}{"start_date": "2024-Q1", "end_date": "2024-Q2"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2024-06-30", end.Format("2006-01-02"))
+	assert.Len(t, notes, 2)
+
+	start, end, notes, err = dateRangeArgs(map[string]interface{}{"period": "last 90 days", "start_date": ""}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02-15", start.Format("2006-01-02"))
+	assert.Equal(t, "2024-05-15", end.Format("2006-01-02"))
+	assert.Equal(t, []string{`period "last 90 days" was read as 2024-02-15 to 2024-05-15 (Europe/Berlin)`}, notes)
+
+	_, _, _, err = dateRangeArgs(map[string]interface{}{"period": "2024", "end_date": "2024-12-31"}, now)
+	assert.EqualError(t, err, "use either period or start_date and end_date")
+	_, _, _, err = dateRangeArgs(map[string]interface{}{"start_date": "2024-01-01"}, now)
+	assert.Error(t, err)
+	start, end, notes, err = dateRangeArgs(map[string]interface{}{"start_date": "2024-05-01", "end_date": "2024-04-01"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-04-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2024-05-01", end.Format("2006-01-02"))
+	assert.Equal(t, []string{"start_date 2024-05-01 was after end_date 2024-04-01, the dates were swapped"}, notes)
+
+	start, end, notes, err = dateRangeArgs(map[string]interface{}{"start_date": "1990", "end_date": "2024-12-31"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2015-01-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2024-12-31", end.Format("2006-01-02"))
+	assert.Contains(t, notes, "the range from 1990-01-01 exceeds 10 years and was shortened to start on 2015-01-01")
+	_, _, _, err = dateRangeArgs(map[string]interface{}{"start_date": "soon", "end_date": "2024-04-01"}, now)
+	assert.ErrorContains(t, err, `start_date: invalid date "soon"`)
+}
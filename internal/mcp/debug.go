@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// recentGCPauses is the number of GC pauses listed in the runtime stats
+const recentGCPauses = 10
+
+// RuntimeStats describes the Go runtime of the server process
+type RuntimeStats struct {
+	GoVersion     string    `json:"go_version"`
+	CPUs          int       `json:"cpus"`
+	Goroutines    int       `json:"goroutines"`
+	UptimeSeconds float64   `json:"uptime_seconds,omitempty"`
+	Heap          HeapStats `json:"heap"`
+	GC            GCStats   `json:"gc"`
+	// LoadShedding is set if load shedding is enabled
+	LoadShedding *LoadSheddingStats `json:"load_shedding,omitempty"`
+	// Scheduling is set if tool calls are limited
+	Scheduling *SchedulingStats `json:"scheduling,omitempty"`
+	// Tools are the statistics of the tools called since the start
+	Tools []ToolStats `json:"tools"`
+}
+
+// HeapStats describes the heap of the server process
+type HeapStats struct {
+	AllocBytes    uint64 `json:"alloc_bytes"`    // Allocated heap objects
+	InuseBytes    uint64 `json:"inuse_bytes"`    // In-use spans
+	IdleBytes     uint64 `json:"idle_bytes"`     // Idle spans, may be returned to the OS
+	ReleasedBytes uint64 `json:"released_bytes"` // Returned to the OS
+	SysBytes      uint64 `json:"sys_bytes"`      // Obtained from the OS for the heap
+	Objects       uint64 `json:"objects"`
+	NextGCBytes   uint64 `json:"next_gc_bytes"` // Heap size of the next GC cycle
+}
+
+// GCStats describes the garbage collector of the server process
+type GCStats struct {
+	Cycles        uint32     `json:"cycles"`
+	PauseTotalMS  float64    `json:"pause_total_ms"`
+	RecentPauseMS []float64  `json:"recent_pause_ms"` // Most recent first
+	CPUFraction   float64    `json:"cpu_fraction"`    // Share of CPU time used by the GC since start
+	LastGC        *time.Time `json:"last_gc,omitempty"`
+}
+
+// runtimeStats reads the runtime statistics of the process
+func runtimeStats(started time.Time) RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	stats := RuntimeStats{
+		GoVersion:  runtime.Version(),
+		CPUs:       runtime.NumCPU(),
+		Goroutines: runtime.NumGoroutine(),
+		Heap: HeapStats{
+			AllocBytes:    m.HeapAlloc,
+			InuseBytes:    m.HeapInuse,
+			IdleBytes:     m.HeapIdle,
+			ReleasedBytes: m.HeapReleased,
+			SysBytes:      m.HeapSys,
+			Objects:       m.HeapObjects,
+			NextGCBytes:   m.NextGC,
+		},
+		GC: GCStats{
+			Cycles:        m.NumGC,
+			PauseTotalMS:  float64(m.PauseTotalNs) / float64(time.Millisecond),
+			RecentPauseMS: []float64{},
+			CPUFraction:   m.GCCPUFraction,
+		},
+	}
+	if !started.IsZero() {
+		stats.UptimeSeconds = time.Since(started).Seconds()
+	}
+	if m.LastGC > 0 {
+		lastGC := time.Unix(0, int64(m.LastGC))
+		stats.GC.LastGC = &lastGC
+	}
+	// PauseNs is a circular buffer, the most recent pause is at (NumGC+255)%256
+	for i := uint32(0); i < min(m.NumGC, recentGCPauses); i++ {
+		pause := m.PauseNs[(m.NumGC-1-i)%uint32(len(m.PauseNs))]
+		stats.GC.RecentPauseMS = append(stats.GC.RecentPauseMS, float64(pause)/float64(time.Millisecond))
+	}
+	return stats
+}
+
+// handleGetRuntimeStats handles runtime statistics requests
+func (s *Server) handleGetRuntimeStats(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	stats := runtimeStats(s.started)
+	if s.shedder != nil {
+		shedding := s.shedder.stats(s.apiClient.RecentLatency())
+		stats.LoadShedding = &shedding
+	}
+	if s.limiter != nil {
+		scheduling := s.limiter.stats()
+		stats.Scheduling = &scheduling
+	}
+	stats.Tools = s.toolMetrics.stats()
+	data, _ := json.MarshalIndent(stats, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// debugRoutes registers the pprof and expvar endpoints if they are enabled.
+// They require the configured bearer token.
+func (h *HTTPBridge) debugRoutes(r *mux.Router) {
+	cfg := h.server.config
+	if cfg == nil || !cfg.MCP.HTTP.Debug.Enabled {
+		return
+	}
+	debug := r.PathPrefix("/debug").Subrouter()
+	debug.Use(h.debugAuthMiddleware(cfg.MCP.HTTP.Debug.Token))
+	debug.Handle("/vars", expvar.Handler()).Methods("GET")
+	debug.HandleFunc("/pprof/cmdline", pprof.Cmdline).Methods("GET")
+	debug.HandleFunc("/pprof/profile", pprof.Profile).Methods("GET")
+	debug.HandleFunc("/pprof/symbol", pprof.Symbol).Methods("GET", "POST")
+	debug.HandleFunc("/pprof/trace", pprof.Trace).Methods("GET")
+	// The index serves the named profiles (heap, goroutine, ...) as well
+	debug.PathPrefix("/pprof/").HandlerFunc(pprof.Index).Methods("GET")
+}
+
+// debugAuthMiddleware rejects requests without the bearer token
+func (h *HTTPBridge) debugAuthMiddleware(token string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasBearerToken(r, token) {
+				h.writeUnauthorized(w, "debug")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasBearerToken reports whether a request carries the bearer token. An
+// empty token authorizes no request.
+func hasBearerToken(r *http.Request, token string) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// writeUnauthorized answers a request without the bearer token of a realm
+func (h *HTTPBridge) writeUnauthorized(w http.ResponseWriter, realm string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="`+realm+`"`)
+	h.writeErrorResponse(w, http.StatusUnauthorized, "Missing or invalid "+realm+" token", "UNAUTHORIZED")
+}
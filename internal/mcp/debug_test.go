@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	runtime.GC()
+	stats := runtimeStats(time.Now().Add(-time.Minute))
+
+	assert.Equal(t, runtime.Version(), stats.GoVersion)
+	assert.Positive(t, stats.Goroutines)
+	assert.GreaterOrEqual(t, stats.UptimeSeconds, 60.0)
+	assert.Positive(t, stats.Heap.SysBytes)
+	assert.Positive(t, stats.GC.Cycles)
+	assert.NotEmpty(t, stats.GC.RecentPauseMS)
+	assert.LessOrEqual(t, len(stats.GC.RecentPauseMS), recentGCPauses)
+	require.NotNil(t, stats.GC.LastGC)
+	assert.WithinDuration(t, time.Now(), *stats.GC.LastGC, time.Minute)
+
+	assert.Zero(t, runtimeStats(time.Time{}).UptimeSeconds)
+}
+
+func TestHandleGetRuntimeStats(t *testing.T) {
+	s := newTestServer()
+	result, err := s.handleGetRuntimeStats(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var stats RuntimeStats
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &stats))
+	assert.Positive(t, stats.Goroutines)
+	assert.NotNil(t, stats.GC.RecentPauseMS)
+}
+
+func TestDebugRoutes(t *testing.T) {
+	newRouter := func(debug config.DebugConfig) http.Handler {
+		s := newTestServer()
+		s.config = &config.Config{MCP: config.MCPConfig{HTTP: config.HTTPConfig{Debug: debug}}}
+		return NewHTTPBridge(s, s.logger).SetupRoutes()
+	}
+	get := func(router http.Handler, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	router := newRouter(config.DebugConfig{Enabled: true, Token: "secret"})
+
+	rec := get(router, "/debug/vars", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "UNAUTHORIZED")
+
+	rec = get(router, "/debug/vars", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = get(router, "/debug/vars", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"memstats"`)
+
+	rec = get(router, "/debug/pprof/", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine")
+
+	rec = get(router, "/debug/pprof/heap?debug=1", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "heap profile")
+
+	rec = get(router, "/debug/pprof/heap", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	disabled := newRouter(config.DebugConfig{Token: "secret"})
+	assert.Equal(t, http.StatusNotFound, get(disabled, "/debug/vars", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, get(disabled, "/debug/pprof/", "secret").Code)
+}
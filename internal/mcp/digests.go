@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/mail"
+)
+
+// digestTournamentLimit bounds the tournaments of a region checked per poll
+const digestTournamentLimit = 1000
+
+// digestWatch remembers the last seen state of the players and regions
+// watched by the email digests. The first poll only records the state, so
+// that a restart does not report everything as new.
+type digestWatch struct {
+	mu          sync.Mutex
+	ratings     map[string]int             // Player ID to DWZ
+	tournaments map[string]map[string]bool // Region to tournament IDs seen
+}
+
+// runDigestWatch polls the watched players and regions every interval
+// until ctx is done
+func (s *Server) runDigestWatch(ctx context.Context, interval time.Duration) {
+	s.checkDigests(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkDigests(ctx)
+		}
+	}
+}
+
+// checkDigests polls the watched players and regions once and queues the
+// rating changes and new tournaments with the digests watching them
+func (s *Server) checkDigests(ctx context.Context) {
+	players := make(map[string][]mail.Notification)
+	regions := make(map[string][]mail.Notification)
+	for _, digest := range s.config.Mail.Digests {
+		for _, playerID := range digest.Players {
+			players[playerID] = nil
+		}
+		for _, region := range digest.Regions {
+			regions[region] = nil
+		}
+	}
+
+	for playerID := range players {
+		notification, err := s.checkPlayerRating(ctx, playerID)
+		if err != nil {
+			s.logger.WithError(err).WithField("player_id", playerID).Warn("Failed to check watched player")
+			continue
+		}
+		if notification != nil {
+			players[playerID] = []mail.Notification{*notification}
+		}
+	}
+	for region := range regions {
+		notifications, err := s.checkRegionTournaments(ctx, region)
+		if err != nil {
+			s.logger.WithError(err).WithField("region", region).Warn("Failed to check watched region")
+			continue
+		}
+		regions[region] = notifications
+	}
+
+	for name, digest := range s.config.Mail.Digests {
+		var notifications []mail.Notification
+		for _, playerID := range digest.Players {
+			notifications = append(notifications, players[playerID]...)
+		}
+		for _, region := range digest.Regions {
+			notifications = append(notifications, regions[region]...)
+		}
+		s.digester.Add(name, digest.To, notifications...)
+	}
+}
+
+// checkPlayerRating returns a notification if the DWZ of a player changed
+// since the last poll
+func (s *Server) checkPlayerRating(ctx context.Context, playerID string) (*mail.Notification, error) {
+	player, err := s.apiClient.GetPlayerProfile(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.watch.mu.Lock()
+	defer s.watch.mu.Unlock()
+	if s.watch.ratings == nil {
+		s.watch.ratings = make(map[string]int)
+	}
+	previous, seen := s.watch.ratings[playerID]
+	s.watch.ratings[playerID] = player.CurrentDWZ
+	if !seen || previous == player.CurrentDWZ {
+		return nil, nil
+	}
+	locale := s.locale()
+	return &mail.Notification{
+		Kind:   mail.RatingChange,
+		Title:  fmt.Sprintf("%s, %s (%s)", player.Name, player.Firstname, playerID),
+		Detail: fmt.Sprintf("DWZ %s → %s (%s)", locale.Int(previous), locale.Int(player.CurrentDWZ), locale.Change(player.CurrentDWZ-previous)),
+		Time:   time.Now(),
+	}, nil
+}
+
+// checkRegionTournaments returns notifications of the upcoming tournaments
+// of a region not seen in earlier polls, ordered by start date
+func (s *Server) checkRegionTournaments(ctx context.Context, region string) ([]mail.Notification, error) {
+	upcoming, err := s.upcomingTournaments(ctx, maxUpcomingDays, digestTournamentLimit, region, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.watch.mu.Lock()
+	defer s.watch.mu.Unlock()
+	if s.watch.tournaments == nil {
+		s.watch.tournaments = make(map[string]map[string]bool)
+	}
+	seen, polled := s.watch.tournaments[region]
+	if !polled {
+		seen = make(map[string]bool)
+		s.watch.tournaments[region] = seen
+	}
+
+	var notifications []mail.Notification
+	for _, t := range upcoming.Tournaments {
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		if !polled {
+			continue
+		}
+		detail := s.locale().Date(tournamentStart(t).In(portalLocation))
+		if t.City != "" {
+			detail += ", " + t.City
+		}
+		notifications = append(notifications, mail.Notification{
+			Kind:   mail.NewTournament,
+			Title:  fmt.Sprintf("%s (%s)", t.Name, t.ID),
+			Detail: detail,
+			Time:   time.Now(),
+		})
+	}
+	return notifications, nil
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/mail"
+)
+
+func TestCheckDigests(t *testing.T) {
+	var dwz, tournaments atomic.Int32
+	dwz.Store(1850)
+	tournaments.Store(1)
+	start := time.Now().UTC().AddDate(0, 0, 10).Format(time.RFC3339)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/players/C0327-297":
+			fmt.Fprintf(w, `{"id":"C0327-297","name":"Alt","firstname":"Anna","current_dwz":%d}`, dwz.Load())
+		case "/api/v1/tournaments/search":
+			var items []string
+			for i := 1; i <= int(tournaments.Load()); i++ {
+				items = append(items, fmt.Sprintf(`{"id":"C%03d-000-000","name":"Open %d","city":"Ulm","start_date":%q}`, i, i, start))
+			}
+			fmt.Fprintf(w, `{"data":[%s],"pagination":{"total":%d}}`, strings.Join(items, ","), len(items))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.config = &config.Config{Mail: config.MailConfig{
+		From: "mcp@example.org",
+		Digests: map[string]config.DigestConfig{
+			"club": {To: []string{"board@example.org"}, Players: []string{"C0327-297"}, Regions: []string{"C"}},
+		},
+	}}
+	mailer := &recordingMailer{}
+	tmpl, err := mail.ParseDigestTemplate("", s.locale())
+	require.NoError(t, err)
+	s.digester = mail.NewDigester(mailer, s.config.Mail.From, tmpl, 0, s.logger)
+
+	// The first poll only records the current state
+	s.checkDigests(s.ctx)
+	assert.Zero(t, s.digester.Pending())
+
+	dwz.Store(1872)
+	tournaments.Store(2)
+	s.checkDigests(s.ctx)
+	assert.Equal(t, 2, s.digester.Pending())
+
+	sent, err := s.digester.Flush(s.ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, mailer.messages, 1)
+	assert.Equal(t, []string{"board@example.org"}, mailer.messages[0].To)
+	assert.Equal(t, "Portal64 digest club: 2 update(s)", mailer.messages[0].Subject)
+	assert.Contains(t, mailer.messages[0].Body, "- Alt, Anna (C0327-297): DWZ 1850 → 1872 (+22)")
+	startDate, err := time.Parse(time.RFC3339, start)
+	require.NoError(t, err)
+	assert.Contains(t, mailer.messages[0].Body, "- Open 2 (C002-000-000): "+startDate.In(portalLocation).Format("02.01.2006")+", Ulm")
+	assert.NotContains(t, mailer.messages[0].Body, "Open 1")
+}
@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// maxDuplicatePages bounds the search pages walked per name
+	maxDuplicatePages = 10
+	// maxDuplicateNames bounds the surnames searched for the members of a club
+	maxDuplicateNames = 100
+	// defaultDuplicateMinScore is the lowest score of a reported pair
+	defaultDuplicateMinScore = 0.5
+)
+
+// DuplicatePair is a pair of player records that may belong to the same
+// person
+type DuplicatePair struct {
+	Players [2]api.PlayerResponse `json:"players"`
+	// Score is the likelihood of a duplicate from 0 to 1
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// DuplicateReport is the result of find_possible_duplicates
+type DuplicateReport struct {
+	Query          string          `json:"query,omitempty"`
+	ClubID         string          `json:"club_id,omitempty"`
+	PlayersChecked int             `json:"players_checked"`
+	Pairs          []DuplicatePair `json:"pairs"`
+	Count          int             `json:"count"`
+	FailedItems    []FailedItem    `json:"failed_items,omitempty"`
+}
+
+// duplicateKey is the identity of a player compared for duplicates: the
+// words of the full name in any order and spelling, and the birth year. It
+// is empty without a name or birth year.
+func duplicateKey(p api.PlayerResponse) string {
+	words := matchTokens(p.Firstname + " " + p.Name)
+	if len(words) == 0 || p.BirthYear == 0 {
+		return ""
+	}
+	sort.Strings(words)
+	return strings.Join(words, " ") + "|" + strconv.Itoa(p.BirthYear)
+}
+
+// scoreDuplicate scores two records with the same duplicateKey. It returns
+// false for records with the same PKZ, which are memberships of one person
+// in several clubs rather than duplicates.
+func scoreDuplicate(a, b api.PlayerResponse) (float64, []string, bool) {
+	if a.PKZ != "" && a.PKZ == b.PKZ {
+		return 0, nil, false
+	}
+
+	score := 0.5
+	reasons := []string{"identical name and birth year"}
+	switch {
+	case a.FideID != 0 && a.FideID == b.FideID:
+		score += 0.4
+		reasons = append(reasons, "same FIDE ID")
+	case a.FideID != 0 && b.FideID != 0:
+		score -= 0.4
+		reasons = append(reasons, "different FIDE IDs")
+	}
+	if a.Gender != "" && b.Gender != "" {
+		if a.Gender == b.Gender {
+			score += 0.05
+		} else {
+			score -= 0.3
+			reasons = append(reasons, "different gender")
+		}
+	}
+	if a.Nation != "" && b.Nation != "" && a.Nation != b.Nation {
+		score -= 0.1
+		reasons = append(reasons, "different nation")
+	}
+	if a.CurrentDWZ > 0 && b.CurrentDWZ > 0 && a.CurrentDWZ-b.CurrentDWZ <= 100 && b.CurrentDWZ-a.CurrentDWZ <= 100 {
+		score += 0.1
+		reasons = append(reasons, "DWZ within 100 points")
+	}
+	if a.ClubID != "" && a.ClubID == b.ClubID {
+		score += 0.1
+		reasons = append(reasons, "same club")
+	}
+	if (a.Status == "active") != (b.Status == "active") {
+		score += 0.05
+		reasons = append(reasons, "only one record is active")
+	}
+
+	score = math.Round(math.Max(0, math.Min(1, score))*100) / 100
+	return score, reasons, true
+}
+
+// uniquePlayers removes repeated records of the same player ID
+func uniquePlayers(players []api.PlayerResponse) []api.PlayerResponse {
+	seen := make(map[string]bool, len(players))
+	unique := make([]api.PlayerResponse, 0, len(players))
+	for _, p := range players {
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
+
+// findDuplicates pairs the records with the same duplicateKey and returns the
+// pairs scoring at least minScore, most likely first. If include is set, only
+// pairs with a record it accepts are returned.
+func findDuplicates(players []api.PlayerResponse, minScore float64, include func(api.PlayerResponse) bool) []DuplicatePair {
+	groups := make(map[string][]api.PlayerResponse)
+	for _, p := range players {
+		if key := duplicateKey(p); key != "" {
+			groups[key] = append(groups[key], p)
+		}
+	}
+
+	pairs := []DuplicatePair{}
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				a, b := group[i], group[j]
+				if include != nil && !include(a) && !include(b) {
+					continue
+				}
+				score, reasons, ok := scoreDuplicate(a, b)
+				if !ok || score < minScore {
+					continue
+				}
+				if b.ID < a.ID {
+					a, b = b, a
+				}
+				pairs = append(pairs, DuplicatePair{Players: [2]api.PlayerResponse{a, b}, Score: score, Reasons: reasons})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		return pairs[i].Players[0].ID < pairs[j].Players[0].ID
+	})
+	return pairs
+}
+
+// searchAllPlayers walks the pages of a player search, at most
+// maxDuplicatePages
+func (s *Server) searchAllPlayers(ctx context.Context, query string) ([]api.PlayerResponse, error) {
+	var players []api.PlayerResponse
+	for page := 0; page < maxDuplicatePages; page++ {
+		params := api.SearchParams{Query: query, Limit: aggregatePageSize, Offset: page * aggregatePageSize}
+		result, err := s.apiClient.SearchPlayers(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		pagePlayers, _ := result.Data.([]api.PlayerResponse)
+		players = append(players, pagePlayers...)
+
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		if len(pagePlayers) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			return players, nil
+		}
+	}
+	addWarning(ctx, fmt.Sprintf("only the first %d players named %q were compared", maxDuplicatePages*aggregatePageSize, query))
+	return players, nil
+}
+
+// clubDuplicateCandidates returns the members of a club together with all
+// players sharing a surname with one of them. Surnames whose search fails
+// are listed as failed items, unless more than the allowed share fails.
+func (s *Server) clubDuplicateCandidates(ctx context.Context, clubID string) ([]api.PlayerResponse, []FailedItem, error) {
+	members, _, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range members {
+		name := normalizeQuery(m.Name)
+		if name != "" && m.BirthYear != 0 && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) > maxDuplicateNames {
+		addWarning(ctx, fmt.Sprintf("only the first %d of %d surnames of the club were searched", maxDuplicateNames, len(names)))
+		names = names[:maxDuplicateNames]
+	}
+
+	found := make([][]api.PlayerResponse, len(names))
+	var failed failedItems
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, asOfConcurrency)
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			players, err := s.searchAllPlayers(ctx, name)
+			if err != nil {
+				failed.add(name, err)
+			}
+			found[i] = players
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, done, len(names), fmt.Sprintf("Searched %d of %d surnames", done, len(names)), nil)
+			mu.Unlock()
+		}(i, name)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	failedNames := failed.list()
+	if err := s.checkFailures(ctx, failedNames, len(names), "surname searches"); err != nil {
+		return nil, nil, err
+	}
+
+	candidates := append([]api.PlayerResponse(nil), members...)
+	for _, players := range found {
+		candidates = append(candidates, players...)
+	}
+	return candidates, failedNames, nil
+}
+
+// handleFindPossibleDuplicates handles duplicate player detection requests
+func (s *Server) handleFindPossibleDuplicates(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	query, _ := args["query"].(string)
+	clubID, _ := args["club_id"].(string)
+	query = strings.TrimSpace(query)
+	if (query == "") == (clubID == "") {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: either query or club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	minScore := defaultDuplicateMinScore
+	if m, ok := args["min_score"].(float64); ok {
+		minScore = m
+	}
+	limit := 20
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	report := DuplicateReport{Query: query, ClubID: clubID}
+	var players []api.PlayerResponse
+	var include func(api.PlayerResponse) bool
+	var err error
+	if clubID != "" {
+		players, report.FailedItems, err = s.clubDuplicateCandidates(ctx, clubID)
+		include = func(p api.PlayerResponse) bool { return p.ClubID == clubID }
+	} else {
+		players, err = s.searchAllPlayers(ctx, normalizeQuery(query))
+	}
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error searching players: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	players = uniquePlayers(players)
+	pairs := findDuplicates(players, minScore, include)
+	report.Count = len(pairs)
+	if len(pairs) > limit {
+		addWarning(ctx, fmt.Sprintf("%d possible duplicates were found, only the %d most likely are listed", len(pairs), limit))
+		pairs = pairs[:limit]
+	}
+	report.Pairs = pairs
+	report.PlayersChecked = len(players)
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
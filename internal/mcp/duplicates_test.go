@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	players := []api.PlayerResponse{
+		{ID: "C0327-10", PKZ: "1", Name: "Müller", Firstname: "Hans", BirthYear: 1970, Gender: "male", FideID: 123, CurrentDWZ: 1800, ClubID: "C0327", Status: "active"},
+		{ID: "C0505-20", PKZ: "2", Name: "Mueller", Firstname: "Hans", BirthYear: 1970, Gender: "male", FideID: 123, CurrentDWZ: 1750, ClubID: "C0505", Status: "passive"},
+		// Second membership of the same person
+		{ID: "C0101-30", PKZ: "1", Name: "Müller", Firstname: "Hans", BirthYear: 1970, ClubID: "C0101"},
+		// Namesakes with their own FIDE IDs
+		{ID: "C0101-40", PKZ: "4", Name: "Schmidt", Firstname: "Anna", BirthYear: 1990, FideID: 1, ClubID: "C0101"},
+		{ID: "C0202-50", PKZ: "5", Name: "Schmidt", Firstname: "Anna", BirthYear: 1990, FideID: 2, ClubID: "C0202"},
+		{ID: "C0202-60", PKZ: "6", Name: "Schmidt", Firstname: "Anna", BirthYear: 1991, ClubID: "C0202"},
+		{ID: "C0202-70", PKZ: "7", Name: "Schmidt", Firstname: "Anna"},
+	}
+
+	pairs := findDuplicates(players, 0, nil)
+	require.Len(t, pairs, 3)
+	assert.Equal(t, "C0327-10", pairs[0].Players[0].ID)
+	assert.Equal(t, "C0505-20", pairs[0].Players[1].ID)
+	assert.Equal(t, 1.0, pairs[0].Score)
+	assert.Equal(t, []string{"identical name and birth year", "same FIDE ID", "DWZ within 100 points", "only one record is active"}, pairs[0].Reasons)
+	assert.Equal(t, 0.5, pairs[1].Score)
+	assert.Equal(t, "C0101-30", pairs[1].Players[0].ID)
+	assert.Equal(t, 0.1, pairs[2].Score)
+	assert.Contains(t, pairs[2].Reasons, "different FIDE IDs")
+
+	assert.Len(t, findDuplicates(players, 0.5, nil), 2)
+
+	inClub := findDuplicates(players, 0, func(p api.PlayerResponse) bool { return p.ClubID == "C0202" })
+	require.Len(t, inClub, 1)
+	assert.Equal(t, "C0202-50", inClub[0].Players[1].ID)
+}
+
+func TestFindPossibleDuplicates(t *testing.T) {
+	upstream := testserver.NewMockPortal64Server(testserver.Config{Dataset: &testserver.Dataset{
+		Players: []testserver.Player{
+			{ID: "C0327-10", PKZ: "1", Name: "Mueller", Firstname: "Hans", BirthYear: 1970, Gender: "m", ClubID: "C0327", Status: "active"},
+			{ID: "C0505-20", PKZ: "2", Name: "Mueller", Firstname: "Hans", BirthYear: 1970, Gender: "m", ClubID: "C0505", Status: "active"},
+			{ID: "C0505-21", PKZ: "3", Name: "Mueller", Firstname: "Eva", BirthYear: 1970, Gender: "w", ClubID: "C0505", Status: "active"},
+		},
+		Clubs: []testserver.Club{{ID: "C0327", Name: "SC Altbach"}},
+	}}).Start()
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	for _, args := range []map[string]interface{}{{"query": "Müller"}, {"club_id": "C0327"}} {
+		result, err := s.handleFindPossibleDuplicates(s.ctx, args)
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content[0].Text)
+
+		var report DuplicateReport
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &report))
+		assert.Equal(t, 3, report.PlayersChecked, args)
+		require.Len(t, report.Pairs, 1, args)
+		assert.Equal(t, "C0327-10", report.Pairs[0].Players[0].ID)
+		assert.Equal(t, "C0505-20", report.Pairs[0].Players[1].ID)
+		assert.Equal(t, 0.55, report.Pairs[0].Score)
+	}
+
+	for _, args := range []map[string]interface{}{{}, {"query": "Müller", "club_id": "C0327"}} {
+		result, err := s.handleFindPossibleDuplicates(s.ctx, args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Error: either query or club_id is required", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+// effectiveConfigReport is the result of get_effective_config
+type effectiveConfigReport struct {
+	File     string           `json:"file,omitempty"`
+	Settings []config.Setting `json:"settings"`
+	// PendingChanges are the differences of the config file and
+	// environment from the running configuration, applied on restart
+	PendingChanges []config.SettingChange `json:"pending_changes"`
+	PendingError   string                 `json:"pending_error,omitempty"`
+}
+
+// handleGetEffectiveConfig reports the running configuration with secrets
+// masked and the changes loading the configuration again would apply
+func (s *Server) handleGetEffectiveConfig(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	if s.config == nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: the server has no configuration",
+			}},
+			IsError: true,
+		}, nil
+	}
+	prefix, _ := args["prefix"].(string)
+	prefix = strings.TrimSpace(prefix)
+
+	report := effectiveConfigReport{File: s.config.File, Settings: []config.Setting{}, PendingChanges: []config.SettingChange{}}
+	for _, setting := range s.config.EffectiveConfig() {
+		if strings.HasPrefix(setting.Key, prefix) {
+			report.Settings = append(report.Settings, setting)
+		}
+	}
+
+	loaded, err := config.Load(s.config.File)
+	if err != nil {
+		report.PendingError = err.Error()
+	} else {
+		for _, change := range s.config.Diff(loaded) {
+			if strings.HasPrefix(change.Key, prefix) {
+				report.PendingChanges = append(report.PendingChanges, change)
+			}
+		}
+	}
+	if prefix != "" && len(report.Settings) == 0 {
+		addWarning(ctx, "no config keys start with "+prefix)
+	}
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestGetEffectiveConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("api:\n  failover:\n    failure_threshold: 5\n  auth:\n    api_key: old-key\n"), 0o600))
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	s := newTestServer()
+	s.config = cfg
+
+	// Changes of the file are reported as pending until a restart
+	require.NoError(t, os.WriteFile(path, []byte("api:\n  failover:\n    failure_threshold: 5\n    cooldown: 1m\n  auth:\n    api_key: new-key\n"), 0o600))
+	result, err := s.handleGetEffectiveConfig(context.Background(), map[string]interface{}{"prefix": "api.failover"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var report effectiveConfigReport
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &report))
+	assert.Equal(t, path, report.File)
+	require.Len(t, report.Settings, 2)
+	assert.Equal(t, config.Setting{Key: "api.failover.cooldown", Value: "30s", IsDefault: true, Source: config.SourceDefault}, report.Settings[0])
+	assert.Equal(t, config.Setting{Key: "api.failover.failure_threshold", Value: float64(5), Default: float64(3), Source: config.SourceFile}, report.Settings[1])
+	assert.Equal(t, []config.SettingChange{{Key: "api.failover.cooldown", Old: "30s", New: "1m0s"}}, report.PendingChanges)
+
+	// Secrets are masked, also in the changes
+	s.config.MCP.HTTP.Admin = config.AdminConfig{Enabled: true, Token: "secret"}
+	s.registerTools()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/config?prefix=api.auth.api_key", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"old": "****"`)
+	assert.NotContains(t, rec.Body.String(), "-key")
+
+	// A configuration that cannot be loaded is reported
+	require.NoError(t, os.WriteFile(path, []byte("api: [\n"), 0o600))
+	result, err = s.handleGetEffectiveConfig(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &report))
+	assert.Contains(t, report.PendingError, "error reading config file")
+	assert.Empty(t, report.PendingChanges)
+}
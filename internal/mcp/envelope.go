@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// ResultSchemaVersion is the version of the tool result envelope. Bump it
+// whenever the shape of tool output changes in an incompatible way.
+const ResultSchemaVersion = "1.0"
+
+// Output formats of tool results
+const (
+	OutputFormatEnvelope = "envelope"
+	OutputFormatLegacy   = "legacy"
+)
+
+// ToolResultEnvelope wraps the JSON output of a tool call
+type ToolResultEnvelope struct {
+	SchemaVersion string      `json:"schema_version"`
+	Data          interface{} `json:"data"`
+	Warnings      []string    `json:"warnings,omitempty"`
+	Meta          *ResultMeta `json:"meta,omitempty"`
+}
+
+// ResultMeta describes how the data of a tool result was obtained
+type ResultMeta struct {
+	Cache       *CacheMeta   `json:"cache,omitempty"`
+	Explanation *Explanation `json:"explanation,omitempty"`
+}
+
+// Cache statuses of a tool call, summarizing its upstream requests
+const (
+	CacheStatusHit     = "hit"     // All served fresh from the response cache
+	CacheStatusStale   = "stale"   // Served from the cache, some beyond their TTL
+	CacheStatusMiss    = "miss"    // All fetched from the API
+	CacheStatusPartial = "partial" // Partly served from the cache
+	CacheStatusBypass  = "bypass"  // The cache was skipped with bypass_cache
+)
+
+// CacheMeta summarizes how the response cache answered the upstream
+// requests of a tool call. Requests of types without a cache policy are not
+// counted.
+type CacheMeta struct {
+	Status     string     `json:"status"`
+	Requests   int        `json:"requests"`
+	Hits       int        `json:"hits"`
+	StaleHits  int        `json:"stale_hits"`
+	Misses     int        `json:"misses"`
+	AgeSeconds int64      `json:"age_seconds"`          // Of the oldest response used
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // Earliest end of a TTL, absent if nothing was cached
+}
+
+// maxExplainedCalls is the number of upstream calls listed in an
+// explanation. Aggregations over a region make hundreds of calls, which are
+// only counted beyond it.
+const maxExplainedCalls = 20
+
+// Explanation describes how a derived result, such as a statistic or an
+// estimation, was computed: the API requests it is based on, the formulas
+// applied to their data and the limitations of that data. Clients use it
+// to describe the provenance of a result faithfully.
+type Explanation struct {
+	UpstreamCalls []UpstreamCall `json:"upstream_calls"`
+	OmittedCalls  int            `json:"omitted_calls,omitempty"` // Calls beyond maxExplainedCalls
+	Formulas      []string       `json:"formulas,omitempty"`
+	Caveats       []string       `json:"caveats,omitempty"`
+}
+
+// UpstreamCall is an API request of a tool call
+type UpstreamCall struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Status int    `json:"status,omitempty"`
+	Cache  string `json:"cache,omitempty"` // hit, stale, miss or bypass if the response cache handled it
+	Error  string `json:"error,omitempty"`
+}
+
+// warningCollector gathers the warnings, cache lookups, API requests and
+// explanations of a tool call
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+	lookups  []api.CacheLookup
+	calls    []api.Call
+	formulas []string
+	caveats  []string
+}
+
+type warningsKey struct{}
+
+// withWarnings attaches a warning collector to the context, which also
+// records the API requests of the call and how the response cache answered
+// them. Deviations of upstream responses from the expected schema are
+// collected as well; a tool fetching the same endpoint repeatedly reports
+// each of them once.
+func withWarnings(ctx context.Context) (context.Context, *warningCollector) {
+	collector := &warningCollector{}
+	ctx = api.WithCacheObserver(ctx, collector.observeCache)
+	ctx = api.WithCallObserver(ctx, collector.observeCall)
+	ctx = api.WithWarnings(ctx, func(warning string) {
+		collector.mu.Lock()
+		if !slices.Contains(collector.warnings, warning) {
+			collector.warnings = append(collector.warnings, warning)
+		}
+		collector.mu.Unlock()
+	})
+	return context.WithValue(ctx, warningsKey{}, collector), collector
+}
+
+// observeCall records an API request
+func (c *warningCollector) observeCall(call api.Call) {
+	c.mu.Lock()
+	c.calls = append(c.calls, call)
+	c.mu.Unlock()
+}
+
+// observeCache records a lookup of the response cache
+func (c *warningCollector) observeCache(lookup api.CacheLookup) {
+	c.mu.Lock()
+	c.lookups = append(c.lookups, lookup)
+	c.mu.Unlock()
+}
+
+// cacheMeta summarizes the recorded cache lookups, nil if there were none
+func (c *warningCollector) cacheMeta(now time.Time) *CacheMeta {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.lookups) == 0 {
+		return nil
+	}
+
+	meta := &CacheMeta{Requests: len(c.lookups)}
+	bypassed := false
+	for _, lookup := range c.lookups {
+		switch lookup.Status {
+		case api.CacheHit:
+			meta.Hits++
+		case api.CacheStale:
+			meta.StaleHits++
+		case api.CacheBypass:
+			bypassed = true
+			meta.Misses++
+		default:
+			meta.Misses++
+		}
+		if !lookup.Fetched.IsZero() {
+			meta.AgeSeconds = max(meta.AgeSeconds, int64(now.Sub(lookup.Fetched)/time.Second))
+		}
+		if !lookup.Expires.IsZero() && (meta.ExpiresAt == nil || lookup.Expires.Before(*meta.ExpiresAt)) {
+			expires := lookup.Expires.UTC()
+			meta.ExpiresAt = &expires
+		}
+	}
+
+	switch {
+	case bypassed:
+		meta.Status = CacheStatusBypass
+	case meta.Misses == meta.Requests:
+		meta.Status = CacheStatusMiss
+	case meta.Misses > 0:
+		meta.Status = CacheStatusPartial
+	case meta.StaleHits > 0:
+		meta.Status = CacheStatusStale
+	default:
+		meta.Status = CacheStatusHit
+	}
+	return meta
+}
+
+// explanation returns the explanation of a tool call, nil unless the
+// handler explained a formula or caveat. Upstream calls are listed in the
+// order of their paths, as concurrent requests complete in any order.
+func (c *warningCollector) explanation() *Explanation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.formulas) == 0 && len(c.caveats) == 0 {
+		return nil
+	}
+
+	calls := make([]UpstreamCall, 0, len(c.calls))
+	for _, call := range c.calls {
+		upstream := UpstreamCall{Method: call.Method, Path: call.Path, Status: call.Status, Cache: call.Cache}
+		if call.Err != nil {
+			upstream.Error = call.Err.Error()
+		}
+		calls = append(calls, upstream)
+	}
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].Path != calls[j].Path {
+			return calls[i].Path < calls[j].Path
+		}
+		return calls[i].Method < calls[j].Method
+	})
+
+	explanation := &Explanation{
+		UpstreamCalls: calls,
+		Formulas:      append([]string(nil), c.formulas...),
+		Caveats:       append([]string(nil), c.caveats...),
+	}
+	if len(calls) > maxExplainedCalls {
+		explanation.UpstreamCalls, explanation.OmittedCalls = calls[:maxExplainedCalls], len(calls)-maxExplainedCalls
+	}
+	return explanation
+}
+
+// explain records the formulas applied to compute the tool result, which
+// adds an explanation to its meta. Repeated formulas are recorded once.
+func explain(ctx context.Context, formulas ...string) {
+	if collector, ok := ctx.Value(warningsKey{}).(*warningCollector); ok {
+		collector.mu.Lock()
+		for _, formula := range formulas {
+			collector.formulas = appendOnce(collector.formulas, formula)
+		}
+		collector.mu.Unlock()
+	}
+}
+
+// addCaveat records a limitation of the data a tool result is computed
+// from. Unlike warnings, caveats apply to every result of the tool, not to
+// a problem of this call.
+func addCaveat(ctx context.Context, caveat string) {
+	if collector, ok := ctx.Value(warningsKey{}).(*warningCollector); ok {
+		collector.mu.Lock()
+		collector.caveats = appendOnce(collector.caveats, caveat)
+		collector.mu.Unlock()
+	}
+}
+
+func appendOnce(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// addWarning records a warning for the tool result. Warnings are dropped in
+// legacy output mode and when called outside a tool call.
+func addWarning(ctx context.Context, warning string) {
+	if collector, ok := ctx.Value(warningsKey{}).(*warningCollector); ok {
+		collector.mu.Lock()
+		collector.warnings = append(collector.warnings, warning)
+		collector.mu.Unlock()
+	}
+}
+
+// outputFormat returns the configured tool output format
+func (s *Server) outputFormat() string {
+	if s.config == nil || s.config.MCP.OutputFormat == "" {
+		return OutputFormatEnvelope
+	}
+	return s.config.MCP.OutputFormat
+}
+
+// wrapResult wraps the text content of a successful tool result in the
+// versioned envelope unless legacy output is configured. Non-JSON text is
+// carried as a string.
+func (s *Server) wrapResult(result *CallToolResponse, collector *warningCollector) *CallToolResponse {
+	if result == nil || result.IsError || s.outputFormat() == OutputFormatLegacy {
+		return result
+	}
+
+	var warnings []string
+	var meta *ResultMeta
+	if collector != nil {
+		collector.mu.Lock()
+		warnings = append(warnings, collector.warnings...)
+		collector.mu.Unlock()
+		cache, explanation := collector.cacheMeta(time.Now()), collector.explanation()
+		if cache != nil || explanation != nil {
+			meta = &ResultMeta{Cache: cache, Explanation: explanation}
+		}
+	}
+
+	wrapped := &CallToolResponse{IsError: result.IsError, Content: make([]ToolContent, len(result.Content))}
+	for i, content := range result.Content {
+		wrapped.Content[i] = content
+		if content.Type != "text" {
+			continue
+		}
+
+		envelope := ToolResultEnvelope{SchemaVersion: ResultSchemaVersion, Warnings: warnings, Meta: meta}
+		if validJSON(content.Text) {
+			envelope.Data = json.RawMessage(content.Text)
+		} else {
+			envelope.Data = content.Text
+		}
+
+		data, err := json.MarshalIndent(envelope, "", "  ")
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to wrap tool result")
+			continue
+		}
+		wrapped.Content[i].Text = string(data)
+	}
+
+	return wrapped
+}
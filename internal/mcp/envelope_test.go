@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestWrapResult_Envelope(t *testing.T) {
+	s := newTestServer()
+	ctx, warnings := withWarnings(context.Background())
+	addWarning(ctx, "partial data")
+
+	result := s.wrapResult(&CallToolResponse{
+		Content: []ToolContent{{Type: "text", Text: `{"id":"C0327"}`}},
+	}, warnings)
+
+	var envelope struct {
+		SchemaVersion string            `json:"schema_version"`
+		Data          map[string]string `json:"data"`
+		Warnings      []string          `json:"warnings"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &envelope))
+	assert.Equal(t, ResultSchemaVersion, envelope.SchemaVersion)
+	assert.Equal(t, "C0327", envelope.Data["id"])
+	assert.Equal(t, []string{"partial data"}, envelope.Warnings)
+}
+
+func TestWrapResult_PlainText(t *testing.T) {
+	s := newTestServer()
+
+	result := s.wrapResult(&CallToolResponse{
+		Content: []ToolContent{{Type: "text", Text: "no results"}},
+	}, nil)
+
+	var envelope ToolResultEnvelope
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &envelope))
+	assert.Equal(t, "no results", envelope.Data)
+}
+
+func TestWrapResult_LegacyAndErrors(t *testing.T) {
+	s := newTestServer()
+	original := &CallToolResponse{Content: []ToolContent{{Type: "text", Text: "Error: boom"}}, IsError: true}
+	assert.Same(t, original, s.wrapResult(original, nil), "errors are not wrapped")
+
+	s.config = &config.Config{MCP: config.MCPConfig{OutputFormat: OutputFormatLegacy}}
+	raw := &CallToolResponse{Content: []ToolContent{{Type: "text", Text: `{"id":"C0327"}`}}}
+	assert.Equal(t, `{"id":"C0327"}`, s.wrapResult(raw, nil).Content[0].Text)
+}
+
+func TestWrapResult_UpstreamSchemaWarnings(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "C0327-297", "name": "Tran", "current_dwz": "1850", "fide_title": "FM"}`))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	ctx, warnings := withWarnings(context.Background())
+
+	_, err := s.handleGetPlayerProfile(ctx, map[string]interface{}{"player_id": "C0327-297"})
+	require.NoError(t, err)
+	_, err = s.handleGetPlayerProfile(ctx, map[string]interface{}{"player_id": "C0327-297"})
+	require.NoError(t, err)
+
+	result := s.wrapResult(&CallToolResponse{Content: []ToolContent{{Type: "text", Text: `{}`}}}, warnings)
+	var envelope ToolResultEnvelope
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &envelope))
+	assert.ElementsMatch(t, []string{
+		"Upstream response of /api/v1/players/{id}: unknown field fide_title ignored",
+		"Upstream response of /api/v1/players/{id}: field current_dwz is string instead of number, left empty",
+	}, envelope.Warnings, "warnings of repeated requests are reported once")
+}
+
+func TestWrapResult_CacheMeta(t *testing.T) {
+	s := newTestServer()
+	now := time.Now()
+	_, collector := withWarnings(context.Background())
+	result := &CallToolResponse{Content: []ToolContent{{Type: "text", Text: `{"id":"C0327"}`}}}
+
+	var envelope ToolResultEnvelope
+	require.NoError(t, json.Unmarshal([]byte(s.wrapResult(result, collector).Content[0].Text), &envelope))
+	assert.Nil(t, envelope.Meta, "calls without cached requests have no cache metadata")
+
+	collector.observeCache(api.CacheLookup{Status: api.CacheHit, Fetched: now.Add(-2 * time.Minute), Expires: now.Add(3 * time.Minute)})
+	collector.observeCache(api.CacheLookup{Status: api.CacheMiss, Fetched: now, Expires: now.Add(time.Minute)})
+	meta := collector.cacheMeta(now)
+	require.NotNil(t, meta)
+	assert.Equal(t, CacheStatusPartial, meta.Status)
+	assert.Equal(t, 2, meta.Requests)
+	assert.Equal(t, 1, meta.Hits)
+	assert.Equal(t, 1, meta.Misses)
+	assert.Equal(t, int64(120), meta.AgeSeconds)
+	require.NotNil(t, meta.ExpiresAt)
+	assert.True(t, now.Add(time.Minute).Equal(*meta.ExpiresAt), "the earliest expiry is reported")
+
+	require.NoError(t, json.Unmarshal([]byte(s.wrapResult(result, collector).Content[0].Text), &envelope))
+	require.NotNil(t, envelope.Meta)
+	assert.Equal(t, CacheStatusPartial, envelope.Meta.Cache.Status)
+
+	for _, tc := range []struct {
+		lookups []string
+		status  string
+	}{
+		{[]string{api.CacheHit, api.CacheHit}, CacheStatusHit},
+		{[]string{api.CacheHit, api.CacheStale}, CacheStatusStale},
+		{[]string{api.CacheMiss, api.CacheMiss}, CacheStatusMiss},
+		{[]string{api.CacheBypass, api.CacheMiss}, CacheStatusBypass},
+	} {
+		_, collector := withWarnings(context.Background())
+		for _, lookup := range tc.lookups {
+			collector.observeCache(api.CacheLookup{Status: lookup})
+		}
+		assert.Equal(t, tc.status, collector.cacheMeta(now).Status, tc.lookups)
+	}
+}
+
+func TestWrapResult_Explanation(t *testing.T) {
+	s := newTestServer()
+	ctx, collector := withWarnings(context.Background())
+	result := &CallToolResponse{Content: []ToolContent{{Type: "text", Text: `{"average_dwz":1750}`}}}
+
+	collector.observeCall(api.Call{Method: "GET", Path: "/api/v1/clubs/C0327/players?limit=100", Status: 200, Cache: api.CacheMiss})
+	collector.observeCall(api.Call{Method: "GET", Path: "/api/v1/clubs/C0327", Status: 404, Err: &api.APIError{StatusCode: 404, Message: "not found"}})
+
+	var envelope ToolResultEnvelope
+	require.NoError(t, json.Unmarshal([]byte(s.wrapResult(result, collector).Content[0].Text), &envelope))
+	assert.Nil(t, envelope.Meta, "results without formulas or caveats are not explained")
+
+	explain(ctx, "average = sum / count", "average = sum / count")
+	addCaveat(ctx, "Members without DWZ are left out")
+	require.NoError(t, json.Unmarshal([]byte(s.wrapResult(result, collector).Content[0].Text), &envelope))
+	require.NotNil(t, envelope.Meta)
+	explanation := envelope.Meta.Explanation
+	require.NotNil(t, explanation)
+	assert.Equal(t, []string{"average = sum / count"}, explanation.Formulas, "repeated formulas are listed once")
+	assert.Equal(t, []string{"Members without DWZ are left out"}, explanation.Caveats)
+	assert.Equal(t, []UpstreamCall{
+		{Method: "GET", Path: "/api/v1/clubs/C0327", Status: 404, Error: "API error 404: not found"},
+		{Method: "GET", Path: "/api/v1/clubs/C0327/players?limit=100", Status: 200, Cache: CacheStatusMiss},
+	}, explanation.UpstreamCalls, "calls are listed in the order of their paths")
+
+	for i := 0; i < maxExplainedCalls; i++ {
+		collector.observeCall(api.Call{Method: "GET", Path: "/api/v1/clubs"})
+	}
+	explanation = collector.explanation()
+	assert.Len(t, explanation.UpstreamCalls, maxExplainedCalls)
+	assert.Equal(t, 2, explanation.OmittedCalls)
+}
@@ -0,0 +1,293 @@
+package mcp
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// defaultExportURLTTL is how long a signed export URL stays valid
+	defaultExportURLTTL = 15 * time.Minute
+	// exportPath is the HTTP path of club exports, followed by the club ID
+	exportPath = "/api/v1/exports/clubs/"
+)
+
+// Files of a club export archive
+const (
+	exportMembersFile     = "members.csv"
+	exportStatisticsFile  = "statistics.json"
+	exportTournamentsFile = "tournaments.csv"
+)
+
+// ClubExport describes a signed download URL of a club export
+type ClubExport struct {
+	ClubID    string    `json:"club_id"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Files     []string  `json:"files"`
+}
+
+// newExportKey returns the key used to sign export URLs. Without a
+// configured key a random one is generated, so URLs do not survive a restart.
+func newExportKey(configured string) []byte {
+	if configured != "" {
+		return []byte(configured)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate export signing key: %v", err))
+	}
+	return key
+}
+
+// exportSignature signs a profile, club ID and expiry time. Each field is
+// prefixed with its length, so different fields never sign alike.
+func exportSignature(key []byte, profile, clubID string, expires int64) string {
+	mac := hmac.New(sha256.New, key)
+	for _, field := range []string{profile, clubID, strconv.FormatInt(expires, 10)} {
+		fmt.Fprintf(mac, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validExportID reports whether a club ID or profile name may be part of an
+// export URL
+func validExportID(id string) bool {
+	return !strings.Contains(id, "|")
+}
+
+// verifyExportSignature checks the signature and expiry of an export URL
+func verifyExportSignature(key []byte, profile, clubID, expiresParam, signature string, now time.Time) error {
+	if !validExportID(profile) || !validExportID(clubID) {
+		return fmt.Errorf("invalid club ID")
+	}
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expiry")
+	}
+	expected := exportSignature(key, profile, clubID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid signature")
+	}
+	if now.Unix() > expires {
+		return fmt.Errorf("download link expired")
+	}
+	return nil
+}
+
+// exportURLTTL returns the configured validity of export URLs
+func (s *Server) exportURLTTL() time.Duration {
+	if s.config != nil && s.config.Export.URLTTL > 0 {
+		return s.config.Export.URLTTL
+	}
+	return defaultExportURLTTL
+}
+
+// exportBaseURL returns the public base URL of the HTTP bridge
+func (s *Server) exportBaseURL() string {
+	if s.config == nil {
+		return ""
+	}
+	if s.config.Export.BaseURL != "" {
+		return strings.TrimSuffix(s.config.Export.BaseURL, "/")
+	}
+	return fmt.Sprintf("http://localhost:%d", s.config.MCP.HTTPPort)
+}
+
+// signedExportURL returns a download URL for a club export valid until expiresAt
+func (s *Server) signedExportURL(clubID string, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if s.profile != "" {
+		query.Set("profile", s.profile)
+	}
+	query.Set("signature", exportSignature(s.exportKey, s.profile, clubID, expires))
+	return s.exportBaseURL() + exportPath + url.PathEscape(clubID) + "?" + query.Encode()
+}
+
+// clubExportData is the data of a club export archive
+type clubExportData struct {
+	clubID    string
+	players   []api.PlayerResponse
+	truncated bool // More members than were fetched
+	profile   *api.ClubProfileResponse
+}
+
+// loadClubExport fetches all data of a club export, so that API errors can
+// be reported before the archive is streamed
+func (s *Server) loadClubExport(ctx context.Context, clubID string) (*clubExportData, error) {
+	players, truncated, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get club members: %w", err)
+	}
+	profile, err := s.apiClient.GetClubProfile(ctx, clubID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get club profile: %w", err)
+	}
+	return &clubExportData{clubID: clubID, players: players, truncated: truncated, profile: profile}, nil
+}
+
+// writeClubExport writes the ZIP archive of a club's members, member
+// statistics and recent tournaments to w
+func writeClubExport(w io.Writer, data *clubExportData) error {
+	archive := zip.NewWriter(w)
+
+	members, err := archive.Create(exportMembersFile)
+	if err != nil {
+		return err
+	}
+	if err := writeMembersCSV(members, data.players); err != nil {
+		return err
+	}
+
+	statistics, err := archive.Create(exportStatisticsFile)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(statistics)
+	encoder.SetIndent("", "  ")
+	stats := computeClubMemberStatistics(data.clubID, data.players, pageCount(len(data.players), aggregatePageSize))
+	stats.Truncated = data.truncated
+	if err := encoder.Encode(map[string]interface{}{
+		"club":              data.profile.Club,
+		"member_statistics": stats,
+		"rating_stats":      data.profile.RatingStats,
+		"exported_at":       time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return err
+	}
+
+	tournaments, err := archive.Create(exportTournamentsFile)
+	if err != nil {
+		return err
+	}
+	if err := writeTournamentsCSV(tournaments, data.profile.RecentTournaments); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// writeMembersCSV writes club members as CSV
+func writeMembersCSV(w io.Writer, players []api.PlayerResponse) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"id", "pkz", "name", "firstname", "birth_year", "gender", "nation", "dwz", "dwz_index", "fide_id", "status"})
+	for _, p := range players {
+		out.Write([]string{
+			p.ID, p.PKZ, p.Name, p.Firstname,
+			optionalInt(p.BirthYear), p.Gender, p.Nation,
+			optionalInt(p.CurrentDWZ), optionalInt(p.DWZIndex), optionalInt(p.FideID),
+			p.Status,
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeTournamentsCSV writes tournaments as CSV
+func writeTournamentsCSV(w io.Writer, tournaments []api.TournamentResponse) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"id", "name", "start_date", "end_date", "location", "participants", "status"})
+	for _, t := range tournaments {
+		participants := t.Participants
+		if participants == 0 {
+			participants = t.ParticipantCount
+		}
+		location := t.Location
+		if location == "" {
+			location = t.City
+		}
+		out.Write([]string{
+			t.ID, t.Name, optionalDate(t.StartDate), optionalDate(t.EndDate),
+			location, optionalInt(participants), t.Status,
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// optionalInt formats a number, leaving zero values empty
+func optionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+// optionalDate formats a nullable date as YYYY-MM-DD
+func optionalDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// handleExportClubData handles club export requests by returning a signed
+// download URL served by the HTTP bridge
+func (s *Server) handleExportClubData(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, ok := args["club_id"].(string)
+	if !ok || clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	if !validExportID(clubID) {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id must not contain '|'",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	// Fail early for unknown clubs instead of handing out a broken link
+	if _, err := s.apiClient.GetClubProfile(ctx, clubID); err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club profile: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	if s.config != nil && s.config.MCP.Mode == "stdio" {
+		addWarning(ctx, "The download URL is served by the HTTP bridge, which is not running in stdio mode")
+	}
+
+	expiresAt := time.Now().Add(s.exportURLTTL()).Truncate(time.Second)
+	export := ClubExport{
+		ClubID:    clubID,
+		URL:       s.signedExportURL(clubID, expiresAt),
+		ExpiresAt: expiresAt,
+		Files:     []string{exportMembersFile, exportStatisticsFile, exportTournamentsFile},
+	}
+
+	data, _ := json.MarshalIndent(export, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
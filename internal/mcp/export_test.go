@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestVerifyExportSignature(t *testing.T) {
+	key := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	expires := now.Add(time.Minute).Unix()
+	signature := exportSignature(key, "", "C0327", expires)
+
+	assert.NoError(t, verifyExportSignature(key, "", "C0327", "1700000060", signature, now))
+	assert.EqualError(t, verifyExportSignature(key, "", "C0505", "1700000060", signature, now), "invalid signature")
+	assert.EqualError(t, verifyExportSignature(key, "", "C0327", "1700000061", signature, now), "invalid signature")
+	assert.EqualError(t, verifyExportSignature([]byte("other"), "", "C0327", "1700000060", signature, now), "invalid signature")
+	assert.EqualError(t, verifyExportSignature(key, "", "C0327", "1700000060", signature, now.Add(2*time.Minute)), "download link expired")
+	assert.EqualError(t, verifyExportSignature(key, "", "C0327", "soon", signature, now), "invalid expiry")
+
+	// A link of one profile cannot be used for another
+	assert.EqualError(t, verifyExportSignature(key, "test", "C0327", "1700000060", signature, now), "invalid signature")
+	profileSignature := exportSignature(key, "test", "C0327", expires)
+	assert.NoError(t, verifyExportSignature(key, "test", "C0327", "1700000060", profileSignature, now))
+
+	// Fields cannot be shifted into one another
+	assert.NotEqual(t, exportSignature(key, "", "test|C0327", expires), profileSignature)
+	assert.NotEqual(t, exportSignature(key, "test", "C0327", expires), exportSignature(key, "testC", "0327", expires))
+	assert.EqualError(t, verifyExportSignature(key, "", "test|C0327", "1700000060", profileSignature, now), "invalid club ID")
+}
+
+func TestClubExportDownload(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clubs/C0327/profile":
+			w.Write([]byte(`{"club": {"id": "C0327", "name": "SC Altbach"}, "recent_tournaments": [
+				{"id": "C350-C01-SMU", "name": "Ulm Open, 2024", "start_date": "2024-03-15T00:00:00Z", "location": "Ulm", "participants": 84}
+			]}`))
+		case "/api/v1/clubs/C0327/players":
+			w.Write([]byte(`[{"id": "C0327-297", "name": "Tran", "firstname": "Minh Cuong", "current_dwz": 1700, "status": "active"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.exportKey = []byte("secret")
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	result, err := s.handleExportClubData(context.Background(), map[string]interface{}{"club_id": "C0327"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var export ClubExport
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &export))
+	assert.WithinDuration(t, time.Now().Add(defaultExportURLTTL), export.ExpiresAt, time.Minute)
+	link, err := url.Parse(export.URL)
+	require.NoError(t, err)
+	assert.Equal(t, exportPath+"C0327", link.Path)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, link.RequestURI(), nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `filename="club-C0327-`)
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+	}
+	assert.Len(t, files, 3)
+
+	members, err := csv.NewReader(bytes.NewReader(files[exportMembersFile])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, []string{"C0327-297", "", "Tran", "Minh Cuong", "", "", "", "1700", "", "", "active"}, members[1])
+
+	tournaments, err := csv.NewReader(bytes.NewReader(files[exportTournamentsFile])).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C350-C01-SMU", "Ulm Open, 2024", "2024-03-15", "", "Ulm", "84", ""}, tournaments[1])
+
+	var statistics struct {
+		MemberStatistics ClubMemberStatistics `json:"member_statistics"`
+	}
+	require.NoError(t, json.Unmarshal(files[exportStatisticsFile], &statistics))
+	assert.Equal(t, 1, statistics.MemberStatistics.MemberCount)
+
+	// A tampered link is rejected
+	query := link.Query()
+	query.Set("expires", "9999999999")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, link.Path+"?"+query.Encode(), nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Transports tools can be exposed on
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+)
+
+// adminToolsGroup hides all administrative tools in a hidden tools list
+const adminToolsGroup = "@admin"
+
+// adminTools are the administrative tools. Tools that change the state of
+// the server belong here, so that "@admin" hides them as well.
+var adminTools = map[string]bool{
+	"check_api_health":             true,
+	"get_cache_stats":              true,
+	"get_connection_stats":         true,
+	"diagnose_upstream_connection": true,
+	"debug_upstream_request":       true,
+	"check_ssl_config":             true,
+	"get_runtime_stats":            true,
+	"get_slo_status":               true,
+	"get_effective_config":         true,
+	"get_feature_flags":            true,
+	"set_feature_flag":             true,
+}
+
+// authenticatedTools are the admin tools that change the state of the
+// server or reveal its setup. The HTTP bridge serves them only with
+// mcp.http.admin enabled, and only to requests with its bearer token.
+var authenticatedTools = map[string]bool{
+	"set_feature_flag":       true,
+	"debug_upstream_request": true,
+	"get_effective_config":   true,
+	"check_ssl_config":       true,
+}
+
+// adminResourceTools are the tools whose data the admin resources expose.
+// A resource is hidden together with its tool.
+var adminResourceTools = map[string]string{
+	"health":    "check_api_health",
+	"cache":     "get_cache_stats",
+	"anomalies": "check_api_health",
+}
+
+// hiddenTools returns the hidden tools list of a transport
+func (s *Server) hiddenTools(transport string) []string {
+	if s.config == nil {
+		return nil
+	}
+	switch transport {
+	case TransportStdio:
+		return s.config.MCP.Tools.Stdio.Hidden
+	case TransportHTTP:
+		return s.config.MCP.Tools.HTTP.Hidden
+	default:
+		return nil
+	}
+}
+
+// adminEnabled reports whether the authenticated tools are served over HTTP
+func (s *Server) adminEnabled() bool {
+	return s.config != nil && s.config.MCP.HTTP.Admin.Enabled
+}
+
+// toolExposed reports whether a tool is exposed on a transport
+func (s *Server) toolExposed(transport, name string) bool {
+	if transport == TransportHTTP && authenticatedTools[name] && !s.adminEnabled() {
+		return false
+	}
+	for _, hidden := range s.hiddenTools(transport) {
+		if hidden == name || (hidden == adminToolsGroup && adminTools[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupTool returns the handler of a tool exposed on a transport. Hidden
+// tools are reported as not existing.
+func (s *Server) lookupTool(transport, name string) (ToolHandler, bool) {
+	if !s.toolExposed(transport, name) {
+		return nil, false
+	}
+	handler, ok := s.tools[name]
+	return handler, ok
+}
+
+// toolDefinitions returns the definitions of the tools exposed on a
+// transport, ordered by name
+func (s *Server) toolDefinitions(transport string) []Tool {
+	names := make([]string, 0, len(s.tools))
+	for name := range s.tools {
+		if s.toolExposed(transport, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	tools := make([]Tool, len(names))
+	for i, name := range names {
+		tools[i] = s.GetToolDefinition(name)
+	}
+	return tools
+}
+
+// resourceExposed reports whether a resource URI is exposed on a transport
+func (s *Server) resourceExposed(transport, uri string) bool {
+	path, ok := strings.CutPrefix(uri, "admin://")
+	if !ok {
+		return true
+	}
+	tool, ok := adminResourceTools[strings.TrimPrefix(path, "/")]
+	return !ok || s.toolExposed(transport, tool)
+}
+
+// exposedResources filters a resource list to the resources exposed on a
+// transport
+func (s *Server) exposedResources(transport string, resources []Resource) []Resource {
+	exposed := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		if s.resourceExposed(transport, resource.URI) {
+			exposed = append(exposed, resource)
+		}
+	}
+	return exposed
+}
+
+// checkHiddenTools warns about entries of the hidden tools lists that name
+// no tool, e.g. because of a typo
+func (s *Server) checkHiddenTools() {
+	for _, transport := range []string{TransportStdio, TransportHTTP} {
+		for _, name := range s.hiddenTools(transport) {
+			if _, ok := s.tools[name]; !ok && name != adminToolsGroup {
+				s.logger.WithField("transport", transport).WithField("tool", name).Warn("Unknown tool in hidden tools list")
+			}
+		}
+	}
+}
+
+// toolRoute registers a REST route of the HTTP bridge backed by a tool. When
+// the tool is hidden on the HTTP transport, the route answers 404. Routes of
+// authenticated tools answer 401 without the admin token. Requests are
+// scheduled like tool calls and may be answered with 503 while the server
+// is overloaded.
+func (h *HTTPBridge) toolRoute(r *mux.Router, path, tool string, handler http.HandlerFunc) *mux.Route {
+	if !h.server.toolExposed(TransportHTTP, tool) {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			h.writeErrorResponse(w, http.StatusNotFound, "Tool not available: "+tool, "TOOL_NOT_AVAILABLE")
+		}
+		return r.HandleFunc(path, handler)
+	}
+	if h.server.shedder != nil || h.server.limiter != nil {
+		handler = h.shedRoute(tool, handler)
+	}
+	if authenticatedTools[tool] {
+		handler = h.adminAuth(handler)
+	}
+	return r.HandleFunc(path, handler)
+}
+
+// adminAuth rejects requests without the admin token
+func (h *HTTPBridge) adminAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.adminAuthorized(r) {
+			h.writeUnauthorized(w, "admin")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// adminAuthorized reports whether a request carries the admin token
+func (h *HTTPBridge) adminAuthorized(r *http.Request) bool {
+	return h.server.adminEnabled() && hasBearerToken(r, h.server.config.MCP.HTTP.Admin.Token)
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func newExposureTestServer() *Server {
+	s := newTestServer()
+	s.config = &config.Config{MCP: config.MCPConfig{Tools: config.ToolsConfig{
+		Stdio: config.TransportToolsConfig{Hidden: []string{"convert_rating"}},
+		HTTP:  config.TransportToolsConfig{Hidden: []string{"@admin"}},
+	}}}
+	s.registerTools()
+	s.registerResources()
+	return s
+}
+
+func TestToolExposed(t *testing.T) {
+	s := newExposureTestServer()
+
+	assert.False(t, s.toolExposed(TransportStdio, "convert_rating"))
+	assert.True(t, s.toolExposed(TransportStdio, "get_cache_stats"))
+	assert.True(t, s.toolExposed(TransportHTTP, "convert_rating"))
+	for name := range adminTools {
+		assert.False(t, s.toolExposed(TransportHTTP, name), name)
+	}
+
+	_, ok := s.lookupTool(TransportHTTP, "set_feature_flag")
+	assert.False(t, ok)
+	_, ok = s.lookupTool(TransportStdio, "set_feature_flag")
+	assert.True(t, ok)
+
+	assert.False(t, s.resourceExposed(TransportHTTP, "admin://cache"))
+	assert.True(t, s.resourceExposed(TransportStdio, "admin://cache"))
+	assert.True(t, s.resourceExposed(TransportHTTP, "clubs://C0327"))
+
+	// Without configuration every tool is exposed, except the authenticated
+	// tools on HTTP
+	assert.True(t, newTestServer().toolExposed(TransportHTTP, "get_cache_stats"))
+	assert.True(t, newTestServer().toolExposed(TransportStdio, "set_feature_flag"))
+	for name := range authenticatedTools {
+		assert.False(t, newTestServer().toolExposed(TransportHTTP, name), name)
+	}
+}
+
+func TestHiddenTools_Stdio(t *testing.T) {
+	s := newExposureTestServer()
+
+	response, err := s.handleMessage([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	require.NoError(t, err)
+	list := response.Result.(ListToolsResponse)
+	assert.Len(t, list.Tools, len(s.tools)-1)
+	for _, tool := range list.Tools {
+		assert.NotEqual(t, "convert_rating", tool.Name)
+	}
+
+	response, err = s.handleMessage([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"convert_rating","arguments":{"rating":1600,"from":"dwz"}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, MethodNotFound, response.Error.Code)
+}
+
+func TestHiddenTools_HTTP(t *testing.T) {
+	s := newExposureTestServer()
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/list", nil))
+	var list ListToolsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list.Tools, len(s.tools)-len(adminTools))
+	for _, tool := range list.Tools {
+		assert.False(t, adminTools[tool.Name], tool.Name)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "TOOL_NOT_AVAILABLE")
+
+	body, _ := json.Marshal(CallToolRequest{Name: "get_cache_stats"})
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "tool not found: get_cache_stats")
+
+	body, _ = json.Marshal(ReadResourceRequest{URI: "admin://cache"})
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/resources/read", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Tools hidden on stdio only stay available over HTTP
+	body, _ = json.Marshal(CallToolRequest{Name: "convert_rating", Arguments: map[string]interface{}{"rating": 1600, "from": "dwz"}})
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestAuthenticatedTools_HTTP(t *testing.T) {
+	routes := map[string]string{ // REST endpoints by tool
+		"set_feature_flag":       "PUT /api/v1/admin/features/caching",
+		"debug_upstream_request": "GET /api/v1/admin/upstream/debug?path=/health",
+		"get_effective_config":   "GET /api/v1/admin/config",
+		"check_ssl_config":       "GET /api/v1/admin/ssl",
+	}
+	require.Len(t, routes, len(authenticatedTools))
+	newRouter := func(admin config.AdminConfig) http.Handler {
+		s := newTestServer()
+		s.config = &config.Config{MCP: config.MCPConfig{HTTP: config.HTTPConfig{Admin: admin}}}
+		s.registerTools()
+		return NewHTTPBridge(s, s.logger).SetupRoutes()
+	}
+	enabled := newRouter(config.AdminConfig{Enabled: true, Token: "secret"})
+	disabled := newRouter(config.AdminConfig{})
+
+	for tool, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		rec := httptest.NewRecorder()
+		enabled.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, tool)
+
+		body, _ := json.Marshal(CallToolRequest{Name: tool})
+		rec = httptest.NewRecorder()
+		enabled.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, tool)
+
+		rec = httptest.NewRecorder()
+		disabled.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, tool)
+	}
+}
+
+func TestAdminTools_Documented(t *testing.T) {
+	readme, err := os.ReadFile("../../README.md")
+	require.NoError(t, err)
+	list := regexp.MustCompile("`@admin` for all administrative tools \\(([^)]*)\\)").FindSubmatch(readme)
+	require.NotNil(t, list, "README.md lists the tools of @admin")
+
+	documented := map[string]bool{}
+	for _, name := range strings.Split(string(list[1]), ",") {
+		documented[strings.Trim(strings.TrimSpace(name), "`")] = true
+	}
+	assert.Equal(t, adminTools, documented, "the @admin list in README.md is outdated")
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/features"
+)
+
+// handleGetFeatureFlags lists the runtime feature flags and their states
+func (s *Server) handleGetFeatureFlags(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"flags": s.features.List(),
+	}, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// handleSetFeatureFlag enables or disables a feature flag at runtime. The
+// change is not persisted and is lost on restart.
+func (s *Server) handleSetFeatureFlag(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	name, _ := args["name"].(string)
+	enabled, ok := args["enabled"].(bool)
+	if name == "" || !ok {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: name and enabled are required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	if err := s.features.Set(name, enabled); err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	s.logger.WithField("flag", name).WithField("enabled", enabled).Info("Feature flag changed")
+
+	data, _ := json.MarshalIndent(features.Flag{
+		Name:        name,
+		Enabled:     enabled,
+		Description: features.Description(name),
+	}, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// redactContact removes phone numbers and street addresses from an address
+// entry when privacy mode is enabled. Names, roles and email stay visible.
+func (s *Server) redactContact(a api.RegionAddressResponse) api.RegionAddressResponse {
+	if !s.features.Enabled(features.PrivacyMode) {
+		return a
+	}
+	a.Phone = ""
+	a.Address = ""
+	a.PostalCode = ""
+	return a
+}
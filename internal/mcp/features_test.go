@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/features"
+)
+
+func TestHTTPBridge_FeatureFlags(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{MCP: config.MCPConfig{HTTP: config.HTTPConfig{Admin: config.AdminConfig{Enabled: true, Token: "secret"}}}}
+	s.features = features.New(map[string]bool{features.Caching: true})
+	s.tools["get_feature_flags"] = s.handleGetFeatureFlags
+	s.tools["set_feature_flag"] = s.handleSetFeatureFlag
+	s.bridge = NewHTTPBridge(s, s.logger)
+	router := s.bridge.SetupRoutes()
+	put := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/features", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed struct {
+		Flags []features.Flag `json:"flags"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Flags, len(features.Names()))
+	assert.Equal(t, features.Flag{Name: "caching", Enabled: true, Description: features.Description("caching")}, listed.Flags[0])
+
+	rec = put("/api/v1/admin/features/privacy_mode", `{"enabled": true}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, s.features.Enabled(features.PrivacyMode))
+
+	rec = put("/api/v1/admin/features/unknown", `{"enabled": true}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = put("/api/v1/admin/features/caching", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.True(t, s.features.Enabled(features.Caching))
+}
+
+func TestHTTPBridge_FeatureFlagsRequireAdminToken(t *testing.T) {
+	newRouter := func(admin config.AdminConfig) (*Server, http.Handler) {
+		s := newTestServer()
+		s.config = &config.Config{MCP: config.MCPConfig{HTTP: config.HTTPConfig{Admin: admin}}}
+		s.features = features.New(nil)
+		s.tools["set_feature_flag"] = s.handleSetFeatureFlag
+		return s, NewHTTPBridge(s, s.logger).SetupRoutes()
+	}
+	request := func(router http.Handler, method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	const call = `{"name": "set_feature_flag", "arguments": {"name": "privacy_mode", "enabled": false}}`
+
+	s, router := newRouter(config.AdminConfig{Enabled: true, Token: "secret"})
+	require.NoError(t, s.features.Set(features.PrivacyMode, true))
+	for _, token := range []string{"", "wrong"} {
+		rec := request(router, http.MethodPut, "/api/v1/admin/features/privacy_mode", `{"enabled": false}`, token)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `Bearer realm="admin"`, rec.Header().Get("WWW-Authenticate"))
+
+		rec = request(router, http.MethodPost, "/tools/call", call, token)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+	assert.True(t, s.features.Enabled(features.PrivacyMode))
+
+	rec := request(router, http.MethodPost, "/tools/call", call, "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, s.features.Enabled(features.PrivacyMode))
+
+	// Without mcp.http.admin the tool is not served over HTTP at all
+	_, router = newRouter(config.AdminConfig{})
+	rec = request(router, http.MethodPut, "/api/v1/admin/features/privacy_mode", `{"enabled": false}`, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = request(router, http.MethodGet, "/tools/list", "", "")
+	assert.NotContains(t, rec.Body.String(), "set_feature_flag")
+}
+
+func TestHandleSetFeatureFlag(t *testing.T) {
+	s := newTestServer()
+	s.features = features.New(nil)
+
+	result, err := s.handleSetFeatureFlag(context.Background(), map[string]interface{}{"name": "markdown_rendering", "enabled": true})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.True(t, s.features.Enabled(features.MarkdownRendering))
+
+	var flag features.Flag
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &flag))
+	assert.Equal(t, features.MarkdownRendering, flag.Name)
+	assert.NotEmpty(t, flag.Description)
+
+	result, _ = s.handleSetFeatureFlag(context.Background(), map[string]interface{}{"name": "nope", "enabled": true})
+	assert.True(t, result.IsError)
+
+	result, _ = s.handleSetFeatureFlag(context.Background(), map[string]interface{}{"name": "caching"})
+	assert.True(t, result.IsError)
+}
+
+func TestRedactContact(t *testing.T) {
+	s := newTestServer()
+	address := api.RegionAddressResponse{
+		Name:       "Max Mustermann",
+		Email:      "max@example.org",
+		Phone:      "0711 123456",
+		Address:    "Hauptstr. 1",
+		PostalCode: "70173",
+		City:       "Stuttgart",
+	}
+
+	assert.Equal(t, address, s.redactContact(address), "flags are disabled without a flag set")
+
+	s.features = features.New(map[string]bool{features.PrivacyMode: true})
+	redacted := s.redactContact(address)
+	assert.Empty(t, redacted.Phone)
+	assert.Empty(t, redacted.Address)
+	assert.Empty(t, redacted.PostalCode)
+	assert.Equal(t, "Max Mustermann", redacted.Name)
+	assert.Equal(t, "max@example.org", redacted.Email)
+	assert.Equal(t, "Stuttgart", redacted.City)
+}
+
+func TestCachingFlag_SwitchesResponseCache(t *testing.T) {
+	var requests int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"code": "C", "name": "Württemberg"}]`))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.features = features.New(nil)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.apiClient.ConfigureCache(api.CacheOptions{Policies: map[string]api.CachePolicy{api.CacheRegions: {TTL: time.Hour}}})
+	s.attachResponseCache()
+
+	for i := 0; i < 2; i++ {
+		_, err := s.apiClient.GetRegions(s.ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests, "responses are not cached while the flag is off")
+
+	require.NoError(t, s.features.Set(features.Caching, true))
+	for i := 0; i < 2; i++ {
+		_, err := s.apiClient.GetRegions(s.ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, requests)
+}
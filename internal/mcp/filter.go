@@ -0,0 +1,360 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// maxFilterPages bounds the number of pages scanned for a filtered search
+	maxFilterPages = 10
+	// maxFilterConditions bounds the number of conditions of a filter
+	maxFilterConditions = 50
+)
+
+// filterOps are the comparison operators of filter conditions
+var filterOps = map[string]bool{
+	"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"contains": true, "starts_with": true, "in": true,
+}
+
+// filterSchema is the JSON schema of the filter argument
+var filterSchema = map[string]interface{}{
+	"type": "object",
+	"description": `Filter evaluated on the results, e.g. {"and":[{"field":"current_dwz","op":">=","value":1800},{"field":"birth_year","op":"<","value":2005}]}. ` +
+		`Combine conditions with "and", "or" and "not". Operators: =, !=, <, <=, >, >=, contains, starts_with, in. ` +
+		`Fields are the result fields; "a.b" selects nested fields.`,
+}
+
+// filterExpr is a filter expression. Exactly one of And, Or, Not or Field
+// is set.
+type filterExpr struct {
+	And   []*filterExpr `json:"and,omitempty"`
+	Or    []*filterExpr `json:"or,omitempty"`
+	Not   *filterExpr   `json:"not,omitempty"`
+	Field string        `json:"field,omitempty"`
+	Op    string        `json:"op,omitempty"`
+	Value interface{}   `json:"value,omitempty"`
+}
+
+// parseFilterArg parses the filter argument of a tool call, given as an
+// object or as JSON text. Fields are checked against the JSON fields of the
+// filtered type. It returns nil if there is no filter.
+func parseFilterArg(args map[string]interface{}, item interface{}) (*filterExpr, error) {
+	raw, ok := args["filter"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	var data []byte
+	if text, isString := raw.(string); isString {
+		if strings.TrimSpace(text) == "" {
+			return nil, nil
+		}
+		data = []byte(text)
+	} else {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+	var expr filterExpr
+	if err := decoder.Decode(&expr); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	conditions := 0
+	if err := expr.validate(jsonFields(reflect.TypeOf(item)), &conditions); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return &expr, nil
+}
+
+// validate checks the structure, operators and fields of an expression
+func (e *filterExpr) validate(fields map[string]bool, conditions *int) error {
+	if e == nil {
+		return fmt.Errorf("empty expression")
+	}
+	set := 0
+	for _, present := range []bool{e.And != nil, e.Or != nil, e.Not != nil, e.Field != ""} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("an expression needs exactly one of and, or, not or field")
+	}
+
+	switch {
+	case e.And != nil || e.Or != nil:
+		subs := e.And
+		if e.Or != nil {
+			subs = e.Or
+		}
+		for _, sub := range subs {
+			if err := sub.validate(fields, conditions); err != nil {
+				return err
+			}
+		}
+		return nil
+	case e.Not != nil:
+		return e.Not.validate(fields, conditions)
+	}
+
+	if *conditions++; *conditions > maxFilterConditions {
+		return fmt.Errorf("too many conditions (max %d)", maxFilterConditions)
+	}
+	if !fields[strings.SplitN(e.Field, ".", 2)[0]] {
+		return fmt.Errorf("unknown field %q", e.Field)
+	}
+	if !filterOps[e.Op] {
+		return fmt.Errorf("unknown operator %q for field %q", e.Op, e.Field)
+	}
+	if _, isList := e.Value.([]interface{}); isList != (e.Op == "in") {
+		if e.Op == "in" {
+			return fmt.Errorf("operator in needs a list value for field %q", e.Field)
+		}
+		return fmt.Errorf("operator %s needs a single value for field %q", e.Op, e.Field)
+	}
+	return nil
+}
+
+// jsonFields returns the JSON field names of a struct type
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+// matches evaluates the expression on an item in its JSON form
+func (e *filterExpr) matches(item map[string]interface{}) bool {
+	switch {
+	case e.And != nil:
+		for _, sub := range e.And {
+			if !sub.matches(item) {
+				return false
+			}
+		}
+		return true
+	case e.Or != nil:
+		for _, sub := range e.Or {
+			if sub.matches(item) {
+				return true
+			}
+		}
+		return false
+	case e.Not != nil:
+		return !e.Not.matches(item)
+	}
+
+	var value interface{} = item
+	for _, part := range strings.Split(e.Field, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			value = nil
+			break
+		}
+		value = object[part]
+	}
+	return e.compare(value)
+}
+
+// compare applies the operator of a condition to a field value
+func (e *filterExpr) compare(value interface{}) bool {
+	switch e.Op {
+	case "=":
+		return e.equal(value, e.Value)
+	case "!=":
+		return !e.equal(value, e.Value)
+	case "in":
+		for _, candidate := range e.Value.([]interface{}) {
+			if e.equal(value, candidate) {
+				return true
+			}
+		}
+		return false
+	case "contains":
+		return strings.Contains(normalizeQuery(fmt.Sprint(value)), normalizeQuery(fmt.Sprint(e.Value)))
+	case "starts_with":
+		return strings.HasPrefix(normalizeQuery(fmt.Sprint(value)), normalizeQuery(fmt.Sprint(e.Value)))
+	}
+
+	order, ok := compareValues(value, e.Value)
+	if !ok {
+		return false
+	}
+	switch e.Op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+// equal compares a field value with a filter value. Strings compare case-
+// and umlaut-insensitively, genders in any spelling.
+func (e *filterExpr) equal(value, want interface{}) bool {
+	if value == nil || want == nil {
+		return value == nil && want == nil
+	}
+	if e.Field == "gender" {
+		return api.NormalizeGender(fmt.Sprint(value)) == api.NormalizeGender(fmt.Sprint(want))
+	}
+	if order, ok := compareValues(value, want); ok {
+		return order == 0
+	}
+	return fmt.Sprint(value) == fmt.Sprint(want)
+}
+
+// compareValues orders a field value against a filter value, numerically if
+// both are numbers, else as normalized strings
+func compareValues(value, want interface{}) (int, bool) {
+	if value == nil || want == nil {
+		return 0, false
+	}
+	a, aNumber := filterNumber(value)
+	b, bNumber := filterNumber(want)
+	if aNumber && bNumber {
+		switch {
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	as, aString := value.(string)
+	bs, bString := want.(string)
+	if !aString || !bString {
+		return 0, false
+	}
+	return strings.Compare(normalizeQuery(as), normalizeQuery(bs)), true
+}
+
+// filterNumber returns a JSON value as a number. Numeric strings count as
+// numbers, so that PKZs compare numerically.
+func filterNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// filterItems returns the items of a slice that match the expression
+func (e *filterExpr) filterItems(items reflect.Value) reflect.Value {
+	matched := reflect.MakeSlice(items.Type(), 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		data, err := json.Marshal(items.Index(i).Interface())
+		if err != nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if json.Unmarshal(data, &fields) == nil && e.matches(fields) {
+			matched = reflect.Append(matched, items.Index(i))
+		}
+	}
+	return matched
+}
+
+// filterSlice returns the items of a slice that match the expression
+func filterSlice[T any](e *filterExpr, items []T) []T {
+	return e.filterItems(reflect.ValueOf(items)).Interface().([]T)
+}
+
+// filteredSearch wraps a search so that the filter is applied to its
+// results. Upstream pages are scanned until offset and limit of the search
+// are covered by matches, at most maxFilterPages pages. The total of the
+// response counts the matches found by the scan; a warning is added if the
+// scan stopped at the page limit.
+func filteredSearch(ctx context.Context, filter *filterExpr, search func(api.SearchParams) (*api.SearchResponse, error)) func(api.SearchParams) (*api.SearchResponse, error) {
+	return func(params api.SearchParams) (*api.SearchResponse, error) {
+		var matched reflect.Value
+		var result *api.SearchResponse
+		capped := true
+
+		for page := 0; page < maxFilterPages; page++ {
+			pageParams := params
+			pageParams.Limit = aggregatePageSize
+			pageParams.Offset = page * aggregatePageSize
+			var err error
+			if result, err = search(pageParams); err != nil {
+				return nil, err
+			}
+
+			items := reflect.ValueOf(result.Data)
+			if items.Kind() != reflect.Slice {
+				return result, nil
+			}
+			if !matched.IsValid() {
+				matched = reflect.MakeSlice(items.Type(), 0, 0)
+			}
+			matched = reflect.AppendSlice(matched, filter.filterItems(items))
+
+			totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+			if items.Len() < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) || matched.Len() >= params.Offset+params.Limit {
+				capped = false
+				break
+			}
+		}
+		if capped {
+			addWarning(ctx, fmt.Sprintf("filter was evaluated on the first %d results only", maxFilterPages*aggregatePageSize))
+		}
+
+		start := min(params.Offset, matched.Len())
+		end := min(start+params.Limit, matched.Len())
+		return &api.SearchResponse{
+			Data: matched.Slice(start, end).Interface(),
+			Pagination: api.PaginationMetadata{
+				Total:  matched.Len(),
+				Limit:  params.Limit,
+				Offset: params.Offset,
+				Count:  end - start,
+			},
+		}, nil
+	}
+}
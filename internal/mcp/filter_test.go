@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestParseFilterArg(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter interface{}
+		errMsg string
+	}{
+		{"no filter", nil, ""},
+		{"object", map[string]interface{}{"field": "current_dwz", "op": ">=", "value": 1800.0}, ""},
+		{"json text", `{"and":[{"field":"current_dwz","op":">=","value":1800},{"field":"birth_year","op":"<","value":2005}]}`, ""},
+		{"nested not and in", `{"not":{"or":[{"field":"gender","op":"in","value":["w","d"]}]}}`, ""},
+		{"unknown field", `{"field":"elo","op":">","value":2000}`, `unknown field "elo"`},
+		{"unknown operator", `{"field":"name","op":"like","value":"M%"}`, `unknown operator "like"`},
+		{"in without list", `{"field":"name","op":"in","value":"Müller"}`, "needs a list value"},
+		{"list without in", `{"field":"name","op":"=","value":["Müller"]}`, "needs a single value"},
+		{"two kinds", `{"field":"name","op":"=","value":"a","not":{"field":"name","op":"=","value":"b"}}`, "exactly one of"},
+		{"unknown key", `{"fields":"name"}`, "unknown field \"fields\""},
+		{"malformed", `{"and":`, "invalid filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFilterArg(map[string]interface{}{"filter": tt.filter}, api.PlayerResponse{})
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestFilterExpr_Matches(t *testing.T) {
+	players := []api.PlayerResponse{
+		{ID: "C0327-1", PKZ: "100", Name: "Müller", Firstname: "Anna", CurrentDWZ: 1950, BirthYear: 2008, Gender: "female"},
+		{ID: "C0327-2", PKZ: "200", Name: "Schmidt", Firstname: "Hans", CurrentDWZ: 1750, BirthYear: 2001, Gender: "male"},
+		{ID: "C0327-3", PKZ: "300", Name: "Mueller", Firstname: "Otto", CurrentDWZ: 2100, BirthYear: 1970, Gender: "male"},
+	}
+
+	ids := func(filter string) []string {
+		expr, err := parseFilterArg(map[string]interface{}{"filter": filter}, api.PlayerResponse{})
+		require.NoError(t, err)
+		var matched []string
+		for _, p := range filterSlice(expr, players) {
+			matched = append(matched, p.ID)
+		}
+		return matched
+	}
+
+	assert.Equal(t, []string{"C0327-1"}, ids(`{"and":[{"field":"current_dwz","op":">=","value":1800},{"field":"birth_year","op":">=","value":2005}]}`))
+	assert.Equal(t, []string{"C0327-1", "C0327-3"}, ids(`{"field":"name","op":"=","value":"MUELLER"}`))
+	assert.Equal(t, []string{"C0327-1"}, ids(`{"field":"gender","op":"=","value":"female"}`))
+	assert.Equal(t, []string{"C0327-2", "C0327-3"}, ids(`{"not":{"field":"gender","op":"in","value":["w","d"]}}`))
+	assert.Equal(t, []string{"C0327-2"}, ids(`{"or":[{"field":"firstname","op":"starts_with","value":"ha"},{"field":"pkz","op":">","value":1000}]}`))
+	assert.Equal(t, []string{"C0327-3"}, ids(`{"field":"pkz","op":">","value":"250"}`))
+	assert.Empty(t, ids(`{"field":"title","op":"=","value":"GM"}`))
+}
+
+// newPagedPlayersUpstream serves n players page by page; player i has a
+// DWZ of 1000+i
+func newPagedPlayersUpstream(t *testing.T, n int, requests *int32) *httptest.Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var players []string
+		for i := offset; i < min(offset+limit, n); i++ {
+			players = append(players, fmt.Sprintf(`{"id": "C0327-%d", "name": "Spieler", "current_dwz": %d}`, i, 1000+i))
+		}
+		fmt.Fprintf(w, `{"data": [%s], "pagination": {"total": %d}}`, strings.Join(players, ","), n)
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+func TestHandleGetClubPlayers_Filter(t *testing.T) {
+	var requests int32
+	s := newTestServer()
+	s.apiClient = api.NewClient(newPagedPlayersUpstream(t, 250, &requests).URL, 5*time.Second, nil)
+
+	var response api.SearchResponse
+	players := func(text string) []api.PlayerResponse {
+		var data []api.PlayerResponse
+		require.NoError(t, json.Unmarshal([]byte(text), &struct {
+			Data       *[]api.PlayerResponse   `json:"data"`
+			Pagination *api.PaginationMetadata `json:"pagination"`
+		}{&data, &response.Pagination}))
+		return data
+	}
+
+	// Matches on the third page only: all pages are scanned
+	result, err := s.handleGetClubPlayers(context.Background(), map[string]interface{}{
+		"club_id": "C0327",
+		"limit":   10.0,
+		"filter":  map[string]interface{}{"field": "current_dwz", "op": ">=", "value": 1245.0},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	matched := players(result.Content[0].Text)
+	require.Len(t, matched, 5)
+	assert.Equal(t, "C0327-245", matched[0].ID)
+	assert.Equal(t, 5, response.Pagination.Total)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+
+	// The scan stops once the page is filled
+	atomic.StoreInt32(&requests, 0)
+	result, err = s.handleGetClubPlayers(context.Background(), map[string]interface{}{
+		"club_id": "C0327",
+		"limit":   5.0,
+		"offset":  2.0,
+		"filter":  `{"field":"current_dwz","op":"<","value":1200}`,
+	})
+	require.NoError(t, err)
+	matched = players(result.Content[0].Text)
+	require.Len(t, matched, 5)
+	assert.Equal(t, "C0327-2", matched[0].ID)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+
+	// Invalid filters are rejected before calling the API
+	atomic.StoreInt32(&requests, 0)
+	result, err = s.handleGetClubPlayers(context.Background(), map[string]interface{}{
+		"club_id": "C0327",
+		"filter":  `{"field":"elo","op":">","value":2000}`,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, `Error: invalid filter: unknown field "elo"`)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&requests))
+}
+
+func TestFilteredSearch_PageLimit(t *testing.T) {
+	var requests int32
+	s := newTestServer()
+	s.apiClient = api.NewClient(newPagedPlayersUpstream(t, 5000, &requests).URL, 5*time.Second, nil)
+
+	ctx, collector := withWarnings(context.Background())
+	result, err := s.handleSearchPlayers(ctx, map[string]interface{}{
+		"filter": `{"field":"current_dwz","op":">","value":5500}`,
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	assert.EqualValues(t, maxFilterPages, atomic.LoadInt32(&requests))
+	assert.Equal(t, []string{"filter was evaluated on the first 1000 results only"}, collector.warnings)
+}
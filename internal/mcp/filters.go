@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// filterPlayersByGenderAndTitle returns the players matching gender and
+// title. Empty criteria match all players; the title filter is skipped if
+// no player carries a title, since the API may not provide titles at all.
+func filterPlayersByGenderAndTitle(players []api.PlayerResponse, gender, title string) []api.PlayerResponse {
+	gender = api.NormalizeGender(gender)
+
+	titled := hasTitles(players)
+
+	filtered := make([]api.PlayerResponse, 0, len(players))
+	for _, p := range players {
+		if gender != "" && api.NormalizeGender(p.Gender) != gender {
+			continue
+		}
+		if title != "" && titled && !strings.EqualFold(p.Title, title) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+// hasTitles reports whether any player carries a title
+func hasTitles(players []api.PlayerResponse) bool {
+	for _, p := range players {
+		if p.Title != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// defaultFormWindow is the number of recent evaluations considered
+	defaultFormWindow = 5
+	maxFormWindow     = 50
+	// formStableThreshold is the average DWZ change per evaluation within
+	// which the form counts as stable
+	formStableThreshold = 5.0
+)
+
+// Form trends
+const (
+	trendImproving = "improving"
+	trendStable    = "stable"
+	trendDeclining = "declining"
+)
+
+// Streak is a run of consecutive evaluations with DWZ changes of one sign
+type Streak struct {
+	Type   string `json:"type"` // "gain", "loss" or "none"
+	Length int    `json:"length"`
+	Change int    `json:"change"` // Total DWZ change of the streak
+}
+
+// PlayerForm summarizes a player's recent rating development
+type PlayerForm struct {
+	PlayerID           string  `json:"player_id"`
+	CurrentDWZ         int     `json:"current_dwz"`
+	Trend              string  `json:"trend"`
+	Window             int     `json:"window"` // Evaluations considered
+	AverageChange      float64 `json:"average_change"`
+	TotalChange        int     `json:"total_change"`
+	ScorePercentage    float64 `json:"score_percentage"` // Points per game in the window
+	AveragePerformance int     `json:"average_performance,omitempty"`
+	LastEvaluation     string  `json:"last_evaluation,omitempty"`
+	CurrentStreak      Streak  `json:"current_streak"`
+	LongestGainStreak  Streak  `json:"longest_gain_streak"`
+	LongestLossStreak  Streak  `json:"longest_loss_streak"`
+	RecentChanges      []int   `json:"recent_changes"` // Oldest first
+	TotalEvaluations   int     `json:"total_evaluations"`
+}
+
+// computePlayerForm computes the form over the last window evaluations.
+// Streaks cover the whole history; unchanged ratings end a streak.
+func computePlayerForm(playerID string, history []api.Evaluation, window int) *PlayerForm {
+	evaluations := append([]api.Evaluation(nil), history...)
+	sort.SliceStable(evaluations, func(i, j int) bool { return evaluations[i].Date.Before(evaluations[j].Date) })
+
+	form := &PlayerForm{
+		PlayerID:         playerID,
+		Trend:            trendStable,
+		TotalEvaluations: len(evaluations),
+		RecentChanges:    []int{},
+		CurrentStreak:    Streak{Type: "none"},
+	}
+	if len(evaluations) == 0 {
+		return form
+	}
+
+	last := evaluations[len(evaluations)-1]
+	form.CurrentDWZ = last.NewDWZ
+	if !last.Date.IsZero() {
+		form.LastEvaluation = last.Date.Format("2006-01-02")
+	}
+
+	recent := evaluations[max(len(evaluations)-window, 0):]
+	form.Window = len(recent)
+	games, points, performanceSum, performances := 0, 0.0, 0, 0
+	for _, evaluation := range recent {
+		change := evaluationChange(evaluation)
+		form.RecentChanges = append(form.RecentChanges, change)
+		form.TotalChange += change
+		games += evaluation.Games
+		points += evaluation.Points
+		if evaluation.Performance > 0 {
+			performanceSum += evaluation.Performance
+			performances++
+		}
+	}
+
+	form.AverageChange = math.Round(float64(form.TotalChange)/float64(form.Window)*10) / 10
+	switch {
+	case form.AverageChange > formStableThreshold:
+		form.Trend = trendImproving
+	case form.AverageChange < -formStableThreshold:
+		form.Trend = trendDeclining
+	}
+	if games > 0 {
+		form.ScorePercentage = math.Round(points/float64(games)*1000) / 10
+	}
+	if performances > 0 {
+		form.AveragePerformance = performanceSum / performances
+	}
+
+	var current Streak
+	for _, evaluation := range evaluations {
+		change := evaluationChange(evaluation)
+		streakType := "none"
+		if change > 0 {
+			streakType = "gain"
+		} else if change < 0 {
+			streakType = "loss"
+		}
+
+		if streakType != current.Type {
+			current = Streak{Type: streakType}
+		}
+		current.Length++
+		current.Change += change
+
+		if current.Type == "gain" && current.Length > form.LongestGainStreak.Length {
+			form.LongestGainStreak = current
+		}
+		if current.Type == "loss" && current.Length > form.LongestLossStreak.Length {
+			form.LongestLossStreak = current
+		}
+	}
+	form.CurrentStreak = current
+	if form.LongestGainStreak.Type == "" {
+		form.LongestGainStreak.Type = "gain"
+	}
+	if form.LongestLossStreak.Type == "" {
+		form.LongestLossStreak.Type = "loss"
+	}
+
+	return form
+}
+
+// evaluationChange returns the DWZ change of an evaluation. A first
+// evaluation of an unrated player counts as no change.
+func evaluationChange(evaluation api.Evaluation) int {
+	if evaluation.OldDWZ <= 0 {
+		return 0
+	}
+	return evaluation.NewDWZ - evaluation.OldDWZ
+}
+
+// handleGetPlayerForm handles player form requests
+func (s *Server) handleGetPlayerForm(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	playerID, ok := args["player_id"].(string)
+	if !ok || playerID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: player_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	window := defaultFormWindow
+	if n, ok := args["evaluations"].(float64); ok && n > 0 {
+		window = min(int(n), maxFormWindow)
+	}
+
+	history, err := s.ratingHistory(ctx, playerID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting player rating history: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	form := computePlayerForm(playerID, history, window)
+	if form.TotalEvaluations == 0 {
+		addWarning(ctx, "The player has no evaluations, no form can be computed")
+	}
+	explain(ctx,
+		"change of an evaluation = new DWZ - old DWZ, 0 for the first evaluation of an unrated player",
+		fmt.Sprintf("average_change = total_change / window, rounded to 0.1; trend improving above +%.0f, declining below -%.0f, else stable", formStableThreshold, formStableThreshold),
+		"score_percentage = points / games of the window * 100",
+		"average_performance = mean performance of the evaluations of the window that have one",
+	)
+	addCaveat(ctx, "Streaks cover the whole history, evaluations without a change end them")
+
+	data, _ := json.MarshalIndent(form, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
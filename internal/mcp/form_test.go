@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func formHistory(changes ...int) []api.Evaluation {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	dwz := 1500
+	history := make([]api.Evaluation, len(changes))
+	for i, change := range changes {
+		history[i] = api.Evaluation{
+			TournamentID: string(rune('A' + i)),
+			Date:         start.AddDate(0, i, 0),
+			OldDWZ:       dwz,
+			NewDWZ:       dwz + change,
+			Games:        5,
+			Points:       2.5,
+			Performance:  dwz + change*2,
+		}
+		dwz += change
+	}
+	// Newest first, as the upstream may return it
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history
+}
+
+func TestComputePlayerForm(t *testing.T) {
+	testCases := []struct {
+		name    string
+		changes []int
+		window  int
+		trend   string
+		average float64
+	}{
+		{"improving", []int{-20, 10, 15, 20}, 3, trendImproving, 15},
+		{"declining", []int{30, -8, -12, -4}, 3, trendDeclining, -8},
+		{"stable", []int{12, -3, 4, 0}, 3, trendStable, 0.3},
+		{"window larger than history", []int{10, 10}, 5, trendImproving, 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			form := computePlayerForm("C0327-297", formHistory(tc.changes...), tc.window)
+			assert.Equal(t, tc.trend, form.Trend)
+			assert.Equal(t, tc.average, form.AverageChange)
+			assert.Equal(t, min(tc.window, len(tc.changes)), form.Window)
+			assert.Equal(t, tc.changes[len(tc.changes)-form.Window:], form.RecentChanges)
+		})
+	}
+}
+
+func TestComputePlayerForm_Streaks(t *testing.T) {
+	form := computePlayerForm("C0327-297", formHistory(5, 7, 9, -4, 0, -6, -2), 5)
+
+	assert.Equal(t, 1509, form.CurrentDWZ)
+	assert.Equal(t, "2020-07-01", form.LastEvaluation)
+	assert.Equal(t, 7, form.TotalEvaluations)
+	assert.Equal(t, 50.0, form.ScorePercentage)
+	assert.Equal(t, Streak{Type: "loss", Length: 2, Change: -8}, form.CurrentStreak)
+	assert.Equal(t, Streak{Type: "gain", Length: 3, Change: 21}, form.LongestGainStreak)
+	assert.Equal(t, Streak{Type: "loss", Length: 2, Change: -8}, form.LongestLossStreak)
+}
+
+func TestComputePlayerForm_NoHistory(t *testing.T) {
+	form := computePlayerForm("C0327-297", nil, 5)
+	assert.Equal(t, trendStable, form.Trend)
+	assert.Zero(t, form.Window)
+	assert.Equal(t, []int{}, form.RecentChanges)
+	assert.Equal(t, "none", form.CurrentStreak.Type)
+
+	// A first evaluation of an unrated player is not a rating gain
+	first := []api.Evaluation{{OldDWZ: 0, NewDWZ: 1400, Date: time.Now()}}
+	form = computePlayerForm("C0327-297", first, 5)
+	require.Equal(t, []int{0}, form.RecentChanges)
+	assert.Equal(t, 1400, form.CurrentDWZ)
+	assert.Equal(t, trendStable, form.Trend)
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/testserver"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files of tool results in testdata/golden")
+
+// goldenCalls are tool calls against the default data set of the mock API.
+// Their results are compared with testdata/golden/<name>.golden so that
+// changes of the output format show up in review.
+var goldenCalls = []struct {
+	name string
+	tool string
+	args string
+}{
+	{"search_players", "search_players", `{"query": "Minh Cuong"}`},
+	{"search_players_no_results", "search_players", `{"query": "Nobody"}`},
+	{"search_clubs", "search_clubs", `{"query": "Altbach"}`},
+	{"search_tournaments", "search_tournaments", `{"query": "Ulm"}`},
+	{"search_tournaments_by_date", "search_tournaments_by_date", `{"start_date": "2024-01-01", "end_date": "2024-12-31"}`},
+	{"get_recent_tournaments", "get_recent_tournaments", `{"limit": 2}`},
+	{"get_player_profile", "get_player_profile", `{"player_id": "C0327-297"}`},
+	{"get_player_profile_invalid_id", "get_player_profile", `{"player_id": "invalid"}`},
+	{"get_player_rating_history", "get_player_rating_history", `{"player_id": "C0327-297"}`},
+	{"get_club_profile", "get_club_profile", `{"club_id": "C0327"}`},
+	{"get_club_players", "get_club_players", `{"club_id": "C0327"}`},
+	{"get_club_statistics", "get_club_statistics", `{"club_id": "C0327"}`},
+	{"get_tournament_details", "get_tournament_details", `{"tournament_id": "C350-C01-SMU"}`},
+	{"get_regions", "get_regions", `{}`},
+	{"convert_rating", "convert_rating", `{"rating": 1650}`},
+	{"calculate_tournament_dwz", "calculate_tournament_dwz", `{
+		"players": [{"id": "a", "dwz": 1650, "index": 20, "age": 30}, {"id": "b", "dwz": 1500, "index": 5, "age": 17}],
+		"games": [{"white": "a", "black": "b", "result": "0-1"}]
+	}`},
+}
+
+// goldenText returns the text content of a tool result, indented if it is
+// JSON
+func goldenText(t *testing.T, result *CallToolResponse) string {
+	var text bytes.Buffer
+	for _, content := range result.Content {
+		if content.Type != "text" {
+			continue
+		}
+		if json.Valid([]byte(content.Text)) {
+			require.NoError(t, json.Indent(&text, []byte(content.Text), "", "  "))
+			text.WriteByte('\n')
+			continue
+		}
+		text.WriteString(content.Text)
+		text.WriteByte('\n')
+	}
+	if result.IsError {
+		return "isError: true\n" + text.String()
+	}
+	return text.String()
+}
+
+func TestToolResults_Golden(t *testing.T) {
+	upstream := testserver.NewMockPortal64Server(testserver.Config{}).Start()
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.config = &config.Config{Links: config.LinksConfig{
+		PlayerURL:     "https://www.schachbund.de/spieler/{id}.html",
+		ClubURL:       "https://www.schachbund.de/verein/{id}.html",
+		TournamentURL: "https://www.schachbund.de/turnier/{id}.html",
+	}}
+	s.registerTools()
+
+	for _, call := range goldenCalls {
+		t.Run(call.name, func(t *testing.T) {
+			params := fmt.Sprintf(`{"name": %q, "arguments": %s}`, call.tool, call.args)
+			response, err := s.handleCallTool(&Message{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(params)})
+			require.NoError(t, err)
+			require.Nil(t, response.Error)
+			got := goldenText(t, response.Result.(*CallToolResponse))
+
+			path := filepath.Join("testdata", "golden", call.name+".golden")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+				return
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "create the golden file with go test ./internal/mcp -run TestToolResults_Golden -update")
+			assert.Equal(t, string(want), got, "tool output changed; if intended, update the golden files with -update and review the diff")
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// handoverTimeout bounds the time a new process may take to start serving
+// before a handover is given up
+const handoverTimeout = 30 * time.Second
+
+// filer is a listener whose socket can be duplicated as a file
+type filer interface {
+	File() (*os.File, error)
+}
+
+// Handover starts a new server process that takes over the listening
+// socket of the HTTP bridge, e.g. to apply a changed configuration or an
+// upgraded binary. The new process is started with the arguments of this
+// one and reports back once it serves requests; until then both processes
+// accept connections on the socket. On success the caller stops this
+// server, which drains the requests in flight. On failure this server keeps
+// serving.
+func (s *Server) Handover() error {
+	if s.config == nil || s.config.MCP.Mode != "http" {
+		return fmt.Errorf("handover needs mcp.mode http")
+	}
+	s.listenerMu.Lock()
+	l := s.httpListener
+	s.listenerMu.Unlock()
+	socket, ok := l.(filer)
+	if !ok {
+		return fmt.Errorf("HTTP bridge is not listening")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	file, err := socket.File()
+	if err != nil {
+		return fmt.Errorf("failed to duplicate listening socket: %w", err)
+	}
+	defer file.Close()
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create readiness pipe: %w", err)
+	}
+	defer ready.Close()
+
+	// ExtraFiles start at file descriptor 3
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{file, readyWriter}
+	cmd.Env = append(os.Environ(),
+		handoverFDEnv+"=3",
+		handoverReadyEnv+"=4",
+	)
+	err = cmd.Start()
+	// Only the new process holds the write end now, so that the read below
+	// ends when it exits
+	readyWriter.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start new process: %w", err)
+	}
+
+	s.logger.WithField("pid", cmd.Process.Pid).Info("Started new server process, waiting for it to take over")
+	if err := waitHandoverReady(ready, handoverTimeout); err != nil {
+		cmd.Process.Kill()
+		go cmd.Wait()
+		return err
+	}
+	// The new process outlives this one
+	cmd.Process.Release()
+
+	s.logger.WithField("pid", cmd.Process.Pid).Info("New server process took over, draining")
+	return nil
+}
+
+// waitHandoverReady waits until the new process reports that it serves
+// requests. It fails if the process exits or the timeout passes first.
+func waitHandoverReady(ready *os.File, timeout time.Duration) error {
+	if err := ready.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("failed to wait for new process: %w", err)
+	}
+	buf := make([]byte, 1)
+	if n, err := ready.Read(buf); n == 0 {
+		if os.IsTimeout(err) {
+			return fmt.Errorf("new process did not take over within %s", timeout)
+		}
+		return fmt.Errorf("new process exited before taking over")
+	}
+	return nil
+}
+
+// notifyHandoverReady tells the predecessor of a handover that this process
+// serves requests on the inherited socket. It does nothing if the process
+// was not started by a handover.
+func notifyHandoverReady(logger *logrus.Logger, l net.Listener) {
+	fd, ok := envFD(handoverReadyEnv)
+	if !ok {
+		return
+	}
+	os.Unsetenv(handoverReadyEnv)
+	ready := os.NewFile(fd, handoverReadyName)
+	if ready == nil {
+		return
+	}
+	defer ready.Close()
+	if _, err := ready.Write([]byte{1}); err != nil {
+		logger.WithError(err).Warn("Failed to notify the previous server process")
+		return
+	}
+	logger.WithField("addr", l.Addr().String()).WithField("ppid", os.Getppid()).Info("Took over listening socket from previous server process")
+}
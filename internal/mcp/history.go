@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// storeKey returns the store key of a player or tournament ID. Keys of
+// upstream profiles are prefixed with the profile name, so histories of
+// different Portal64 instances are kept apart.
+func (s *Server) storeKey(id string) string {
+	if s.profile == "" {
+		return id
+	}
+	return s.profile + "/" + id
+}
+
+// ratingHistory returns the rating history of a player. With a store
+// configured, fetched evaluations are persisted and merged with evaluations
+// the upstream no longer returns; when the upstream fails, the stored
+// history is returned with a warning.
+func (s *Server) ratingHistory(ctx context.Context, playerID string) ([]api.Evaluation, error) {
+	history, err := s.apiClient.GetPlayerRatingHistory(ctx, playerID)
+	if s.store == nil {
+		return history, err
+	}
+
+	if err != nil {
+		stored, storeErr := s.store.Evaluations(s.storeKey(playerID))
+		if storeErr != nil {
+			return nil, err
+		}
+		addWarning(ctx, fmt.Sprintf("Portal64 API request failed (%v), returning stored rating history", err))
+		return stored, nil
+	}
+
+	merged, err := s.store.SaveEvaluations(s.storeKey(playerID), history)
+	if err != nil {
+		s.logger.WithError(err).WithField("player_id", playerID).Warn("Failed to persist rating history")
+		return history, nil
+	}
+	return merged, nil
+}
+
+// tournamentDetails returns the details of a tournament, persisting them
+// and falling back to stored details like ratingHistory
+func (s *Server) tournamentDetails(ctx context.Context, tournamentID string) (*api.EnhancedTournamentResponse, error) {
+	details, err := s.apiClient.GetTournamentDetails(ctx, tournamentID)
+	if s.store == nil {
+		return details, err
+	}
+
+	if err != nil {
+		stored, storeErr := s.store.Tournament(s.storeKey(tournamentID))
+		if storeErr != nil {
+			return nil, err
+		}
+		addWarning(ctx, fmt.Sprintf("Portal64 API request failed (%v), returning stored tournament details", err))
+		return stored, nil
+	}
+
+	if err := s.store.SaveTournament(s.storeKey(tournamentID), details); err != nil {
+		s.logger.WithError(err).WithField("tournament_id", tournamentID).Warn("Failed to persist tournament details")
+	}
+	return details, nil
+}
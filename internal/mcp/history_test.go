@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/store"
+)
+
+func TestRatingHistory_ReadThroughStore(t *testing.T) {
+	var pruned, down atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if pruned.Load() {
+			w.Write([]byte(`[{"id": 2, "tournament_id": "T2", "tournament_date": "2023-05-01T00:00:00Z", "dwz_old": 1610, "dwz_new": 1625}]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"id": 1, "tournament_id": "T1", "tournament_date": "2022-01-15T00:00:00Z", "dwz_old": 1600, "dwz_new": 1610},
+			{"id": 2, "tournament_id": "T2", "tournament_date": "2023-05-01T00:00:00Z", "dwz_old": 1610, "dwz_new": 1625}
+		]`))
+	}))
+	defer upstream.Close()
+
+	st, err := store.OpenBoltStore(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer st.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.store = st
+
+	history, err := s.ratingHistory(context.Background(), "C0327-297")
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	pruned.Store(true)
+	history, err = s.ratingHistory(context.Background(), "C0327-297")
+	require.NoError(t, err)
+	require.Len(t, history, 2, "pruned evaluations are kept")
+	assert.Equal(t, "T1", history[0].TournamentID)
+
+	down.Store(true)
+	ctx, warnings := withWarnings(context.Background())
+	history, err = s.ratingHistory(ctx, "C0327-297")
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+	require.Len(t, warnings.warnings, 1)
+	assert.Contains(t, warnings.warnings[0], "returning stored rating history")
+
+	_, err = s.ratingHistory(ctx, "C0101-001")
+	assert.Error(t, err, "nothing stored for the player")
+}
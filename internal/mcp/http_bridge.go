@@ -2,29 +2,44 @@ package mcp
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"unsafe"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/features"
 )
 
 // HTTPBridge provides HTTP access to MCP functionality
 type HTTPBridge struct {
 	server *Server
 	logger *logrus.Logger
+	// signer signs responses, nil if signing is disabled
+	signer *responseSigner
 }
 
 // NewHTTPBridge creates a new HTTP bridge for MCP server
 func NewHTTPBridge(server *Server, logger *logrus.Logger) *HTTPBridge {
-	return &HTTPBridge{
+	bridge := &HTTPBridge{
 		server: server,
 		logger: logger,
 	}
+	if server.config != nil {
+		signer, err := newResponseSigner(server.config.MCP.HTTP.Signing)
+		if err != nil {
+			logger.WithError(err).Error("Invalid response signing key, responses are not signed")
+		}
+		bridge.signer = signer
+	}
+	return bridge
 }
 
 // SetupRoutes configures HTTP routes for MCP functionality
@@ -32,15 +47,42 @@ func (h *HTTPBridge) SetupRoutes() *mux.Router {
 	r := mux.NewRouter()
 
 	// Add CORS middleware
+	r.Use(h.recoveryMiddleware)
 	r.Use(h.corsMiddleware)
+	r.Use(h.signingMiddleware)
 	r.Use(h.loggingMiddleware)
+	r.Use(h.sessionMiddleware)
+	r.Use(h.profileMiddleware)
 
 	// Health endpoints
-	r.HandleFunc("/health", h.handleHealth).Methods("GET")
-	r.HandleFunc("/api/v1/health", h.handleHealth).Methods("GET")
+	h.toolRoute(r, "/health", "check_api_health", h.handleHealth).Methods("GET")
+	h.toolRoute(r, "/api/v1/health", "check_api_health", h.handleHealth).Methods("GET")
 	
 	// Admin endpoints
-	r.HandleFunc("/api/v1/admin/cache", h.handleCacheStats).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/cache", "get_cache_stats", h.handleCacheStats).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/connections", "get_connection_stats", h.handleConnectionStats).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/connections/diagnose", "diagnose_upstream_connection", h.handleDiagnoseConnection).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/upstream/debug", "debug_upstream_request", h.handleDebugUpstreamRequest).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/ssl", "check_ssl_config", h.handleCheckSSLConfig).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/runtime", "get_runtime_stats", h.handleRuntimeStats).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/slo", "get_slo_status", h.handleSLOStatus).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/config", "get_effective_config", h.handleEffectiveConfig).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/features", "get_feature_flags", h.handleGetFeatureFlags).Methods("GET")
+	h.toolRoute(r, "/api/v1/admin/features/{name}", "set_feature_flag", h.handleSetFeatureFlag).Methods("PUT", "POST")
+
+	// Profiling endpoints and metrics export, only if enabled
+	h.debugRoutes(r)
+	h.metricsRoute(r)
+
+	// Public key of response signatures
+	h.signingKeyRoute(r)
+
+	// Operator dashboard, only if enabled
+	h.uiRoutes(r)
+
+	// Session endpoints
+	r.HandleFunc("/sessions", h.handleCreateSession).Methods("POST")
+	r.HandleFunc("/sessions", h.handleDeleteSession).Methods("DELETE")
 
 	// MCP protocol endpoints
 	r.HandleFunc("/tools/list", h.handleListTools).Methods("POST", "GET")
@@ -49,32 +91,52 @@ func (h *HTTPBridge) SetupRoutes() *mux.Router {
 	r.HandleFunc("/resources/read", h.handleReadResource).Methods("POST")
 
 	// Player endpoints (both versioned and non-versioned)
-	r.HandleFunc("/api/v1/players", h.handleSearchPlayers).Methods("GET")
-	r.HandleFunc("/api/players/", h.handleSearchPlayers).Methods("GET")
-	r.HandleFunc("/api/v1/players/{id}", h.handleGetPlayerProfile).Methods("GET")
-	r.HandleFunc("/api/players/{id}", h.handleGetPlayerProfile).Methods("GET")
-	r.HandleFunc("/api/v1/players/{id}/history", h.handleGetPlayerRatingHistory).Methods("GET")
+	h.toolRoute(r, "/api/v1/players", "search_players", h.handleSearchPlayers).Methods("GET")
+	h.toolRoute(r, "/api/players/", "search_players", h.handleSearchPlayers).Methods("GET")
+	h.toolRoute(r, "/api/v1/players/{id}", "get_player_profile", h.handleGetPlayerProfile).Methods("GET")
+	h.toolRoute(r, "/api/players/{id}", "get_player_profile", h.handleGetPlayerProfile).Methods("GET")
+	h.toolRoute(r, "/api/v1/players/{id}/history", "get_player_rating_history", h.handleGetPlayerRatingHistory).Methods("GET")
+	h.toolRoute(r, "/api/v1/players/{id}/chart", "render_rating_chart", h.handleRenderRatingChart).Methods("GET")
+	h.toolRoute(r, "/api/v1/players/{id}/rating", "get_player_rating_at_date", h.handleGetPlayerRatingAtDate).Methods("GET")
+	h.toolRoute(r, "/api/v1/players/{id}/form", "get_player_form", h.handleGetPlayerForm).Methods("GET")
+	h.toolRoute(r, "/api/v1/players/{id}/percentile", "get_player_percentile", h.handleGetPlayerPercentile).Methods("GET")
 
 	// Club endpoints (both versioned and non-versioned)
-	r.HandleFunc("/api/v1/clubs", h.handleSearchClubs).Methods("GET")
-	r.HandleFunc("/api/clubs/", h.handleSearchClubs).Methods("GET")
-	r.HandleFunc("/api/v1/clubs/{id}", h.handleGetClubProfile).Methods("GET")
-	r.HandleFunc("/api/clubs/{id}", h.handleGetClubProfile).Methods("GET")
-	r.HandleFunc("/api/v1/clubs/{id}/profile", h.handleGetClubProfile).Methods("GET")
-	r.HandleFunc("/api/v1/clubs/{id}/players", h.handleGetClubPlayers).Methods("GET")
-	r.HandleFunc("/api/v1/clubs/{id}/statistics", h.handleGetClubStatistics).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs", "search_clubs", h.handleSearchClubs).Methods("GET")
+	h.toolRoute(r, "/api/clubs/", "search_clubs", h.handleSearchClubs).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}", "get_club_profile", h.handleGetClubProfile).Methods("GET")
+	h.toolRoute(r, "/api/clubs/{id}", "get_club_profile", h.handleGetClubProfile).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/profile", "get_club_profile", h.handleGetClubProfile).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/players", "get_club_players", h.handleGetClubPlayers).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/statistics", "get_club_statistics", h.handleGetClubStatistics).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/distribution", "render_club_distribution", h.handleRenderClubDistribution).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/teams", "get_club_teams", h.handleGetClubTeams).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/officials", "get_club_officials", h.handleGetClubOfficials).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/reactivation", "get_reactivation_candidates", h.handleGetReactivationCandidates).Methods("GET")
+	h.toolRoute(r, "/api/v1/clubs/{id}/teams/{team}", "get_team_roster", h.handleGetTeamRoster).Methods("GET")
+
+	// Export downloads, authorized by the signature of the URL
+	h.toolRoute(r, exportPath+"{id}", "export_club_data", h.handleDownloadClubExport).Methods("GET")
+
+	// QR codes of the deep links of players, clubs and tournaments
+	h.toolRoute(r, "/api/v1/qr/{id}", "get_qr_code", h.handleGetQRCode).Methods("GET")
 
 	// Tournament endpoints (both versioned and non-versioned)
-	r.HandleFunc("/api/v1/tournaments", h.handleSearchTournaments).Methods("GET")
-	r.HandleFunc("/api/tournaments/", h.handleSearchTournaments).Methods("GET")
-	r.HandleFunc("/api/v1/tournaments/search", h.handleSearchTournamentsByDate).Methods("GET")
-	r.HandleFunc("/api/v1/tournaments/recent", h.handleGetRecentTournaments).Methods("GET")
-	r.HandleFunc("/api/v1/tournaments/{id}", h.handleGetTournamentDetails).Methods("GET")
-	r.HandleFunc("/api/tournaments/{id}", h.handleGetTournamentDetails).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments", "search_tournaments", h.handleSearchTournaments).Methods("GET")
+	h.toolRoute(r, "/api/tournaments/", "search_tournaments", h.handleSearchTournaments).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/search", "search_tournaments_by_date", h.handleSearchTournamentsByDate).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/recent", "get_recent_tournaments", h.handleGetRecentTournaments).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/upcoming", "get_upcoming_tournaments", h.handleGetUpcomingTournaments).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/series", "get_tournament_series", h.handleGetTournamentSeries).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/changes", "get_tournament_changes", h.handleGetTournamentChanges).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/activity", "get_region_activity", h.handleGetRegionActivity).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/organizer", "get_organizer_tournaments", h.handleGetOrganizerTournaments).Methods("GET")
+	h.toolRoute(r, "/api/v1/tournaments/{id}", "get_tournament_details", h.handleGetTournamentDetails).Methods("GET")
+	h.toolRoute(r, "/api/tournaments/{id}", "get_tournament_details", h.handleGetTournamentDetails).Methods("GET")
 
 	// Region endpoints
-	r.HandleFunc("/api/v1/addresses/regions", h.handleGetRegions).Methods("GET")
-	r.HandleFunc("/api/v1/addresses/{region}", h.handleGetRegionAddresses).Methods("GET")
+	h.toolRoute(r, "/api/v1/addresses/regions", "get_regions", h.handleGetRegions).Methods("GET")
+	h.toolRoute(r, "/api/v1/addresses/{region}", "get_region_addresses", h.handleGetRegionAddresses).Methods("GET")
 
 	return r
 }
@@ -84,7 +146,8 @@ func (h *HTTPBridge) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+SessionHeader+", "+ProfileHeader)
+		w.Header().Set("Access-Control-Expose-Headers", SessionHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -108,6 +171,45 @@ func (h *HTTPBridge) loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// sessionMiddleware attaches the session named by the session header to the
+// request context. Requests without the header are served statelessly.
+func (h *HTTPBridge) sessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(SessionHeader)
+		if id == "" || h.server.sessions == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		session, ok := h.server.sessions.Get(id)
+		if !ok {
+			h.writeErrorResponse(w, http.StatusNotFound, "Session not found or expired", "SESSION_NOT_FOUND")
+			return
+		}
+
+		w.Header().Set(SessionHeader, session.ID)
+		next.ServeHTTP(w, r.WithContext(withSession(r.Context(), session)))
+	})
+}
+
+// profileMiddleware selects the upstream profile named by the profile
+// header for the request. Requests without the header use the default profile.
+func (h *HTTPBridge) profileMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.Header.Get(ProfileHeader)
+		if name == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if _, err := h.server.profileServer(name); err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), "UNKNOWN_PROFILE")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withProfile(r.Context(), name)))
+	})
+}
+
 // Helper function to write JSON responses
 func (h *HTTPBridge) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -117,6 +219,27 @@ func (h *HTTPBridge) writeJSONResponse(w http.ResponseWriter, statusCode int, da
 	}
 }
 
+// writeRawJSONResponse writes a JSON document as is, without decoding and
+// encoding it again
+func (h *HTTPBridge) writeRawJSONResponse(w http.ResponseWriter, statusCode int, data string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, err := io.WriteString(w, data)
+	if err == nil && !strings.HasSuffix(data, "\n") {
+		_, err = io.WriteString(w, "\n")
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to write JSON response")
+	}
+}
+
+// validJSON reports whether text is a JSON document. It validates the
+// string in place, as converting a large tool result to a byte slice
+// would copy it.
+func validJSON(text string) bool {
+	return json.Valid(unsafe.Slice(unsafe.StringData(text), len(text)))
+}
+
 // Helper function to write error responses
 func (h *HTTPBridge) writeErrorResponse(w http.ResponseWriter, statusCode int, message, code string) {
 	h.writeJSONResponse(w, statusCode, map[string]interface{}{
@@ -164,20 +287,189 @@ func (h *HTTPBridge) handleCacheStats(w http.ResponseWriter, r *http.Request) {
 	h.writeMCPToolResponse(w, result)
 }
 
+// handleConnectionStats handles connection pool statistics requests
+func (h *HTTPBridge) handleConnectionStats(w http.ResponseWriter, r *http.Request) {
+	result, err := h.callMCPTool(r.Context(), "get_connection_stats", map[string]interface{}{})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get connection stats", "CONNECTION_STATS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleDiagnoseConnection handles upstream connection diagnosis requests
+func (h *HTTPBridge) handleDiagnoseConnection(w http.ResponseWriter, r *http.Request) {
+	result, err := h.callMCPTool(r.Context(), "diagnose_upstream_connection", map[string]interface{}{})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to diagnose upstream connection", "CONNECTION_DIAGNOSIS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleDebugUpstreamRequest handles upstream debug requests. The path
+// parameter names the API path; other parameters except max_body_bytes are
+// passed on as its query.
+func (h *HTTPBridge) handleDebugUpstreamRequest(w http.ResponseWriter, r *http.Request) {
+	args := map[string]interface{}{"path": r.URL.Query().Get("path")}
+	query := map[string]interface{}{}
+	for name, values := range r.URL.Query() {
+		switch name {
+		case "path":
+		case "max_body_bytes":
+			maxBody, err := strconv.Atoi(values[0])
+			if err != nil || maxBody < 0 {
+				h.writeErrorResponse(w, http.StatusBadRequest, "max_body_bytes must be a non-negative integer", "INVALID_REQUEST")
+				return
+			}
+			args["max_body_bytes"] = float64(maxBody)
+		default:
+			query[name] = values[0]
+		}
+	}
+	args["query"] = query
+
+	result, err := h.callMCPTool(r.Context(), "debug_upstream_request", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to send upstream debug request", "UPSTREAM_DEBUG_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleCheckSSLConfig handles TLS configuration check requests
+func (h *HTTPBridge) handleCheckSSLConfig(w http.ResponseWriter, r *http.Request) {
+	result, err := h.callMCPTool(r.Context(), "check_ssl_config", map[string]interface{}{})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to check SSL configuration", "SSL_CHECK_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleRuntimeStats handles runtime statistics requests
+func (h *HTTPBridge) handleRuntimeStats(w http.ResponseWriter, r *http.Request) {
+	result, err := h.callMCPTool(r.Context(), "get_runtime_stats", map[string]interface{}{})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get runtime stats", "RUNTIME_STATS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleSLOStatus handles service level objective requests
+func (h *HTTPBridge) handleSLOStatus(w http.ResponseWriter, r *http.Request) {
+	result, err := h.callMCPTool(r.Context(), "get_slo_status", map[string]interface{}{})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get SLO status", "SLO_STATUS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleEffectiveConfig handles effective configuration requests, optionally
+// filtered by the prefix parameter
+func (h *HTTPBridge) handleEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	args := map[string]interface{}{"prefix": r.URL.Query().Get("prefix")}
+	result, err := h.callMCPTool(r.Context(), "get_effective_config", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get effective configuration", "EFFECTIVE_CONFIG_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetFeatureFlags lists the runtime feature flags
+func (h *HTTPBridge) handleGetFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	result, err := h.callMCPTool(r.Context(), "get_feature_flags", map[string]interface{}{})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to get feature flags", "FEATURE_FLAGS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleSetFeatureFlag changes a feature flag. The body is {"enabled": bool}.
+func (h *HTTPBridge) handleSetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if !features.IsKnown(name) {
+		h.writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Unknown feature flag %q", name), "UNKNOWN_FEATURE_FLAG")
+		return
+	}
+
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Enabled == nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Request body must be {\"enabled\": true|false}", "INVALID_REQUEST")
+		return
+	}
+
+	result, err := h.callMCPTool(r.Context(), "set_feature_flag", map[string]interface{}{
+		"name":    name,
+		"enabled": *body.Enabled,
+	})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to set feature flag", "FEATURE_FLAGS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// Session handlers
+
+// handleCreateSession starts a new session
+func (h *HTTPBridge) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	if h.server.sessions == nil {
+		h.writeErrorResponse(w, http.StatusNotImplemented, "Sessions are disabled", "SESSIONS_DISABLED")
+		return
+	}
+
+	session, err := h.server.sessions.Create()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create session")
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to create session", "SESSION_CREATE_FAILED")
+		return
+	}
+
+	h.logger.WithField("session_id", session.ID).Debug("Session created")
+
+	w.Header().Set(SessionHeader, session.ID)
+	h.writeJSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"session_id": session.ID,
+		"created_at": session.CreatedAt.Format(time.RFC3339),
+		"ttl":        h.server.config.MCP.Sessions.TTL.String(),
+	})
+}
+
+// handleDeleteSession ends the session named by the session header
+func (h *HTTPBridge) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	session, ok := SessionFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusBadRequest, "Missing "+SessionHeader+" header", "SESSION_REQUIRED")
+		return
+	}
+
+	h.server.sessions.Delete(session.ID)
+	h.logger.WithField("session_id", session.ID).Debug("Session deleted")
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // MCP Protocol handlers
 
 // handleListTools handles tool listing requests
 func (h *HTTPBridge) handleListTools(w http.ResponseWriter, r *http.Request) {
-	tools := make([]Tool, 0, len(h.server.tools))
-	
-	// Add all registered tools
-	for name := range h.server.tools {
-		tool := h.server.GetToolDefinition(name)
-		tools = append(tools, tool)
-	}
-
 	response := ListToolsResponse{
-		Tools: tools,
+		Tools: h.server.toolDefinitions(TransportHTTP),
 	}
 
 	h.writeJSONResponse(w, http.StatusOK, response)
@@ -190,15 +482,43 @@ func (h *HTTPBridge) handleCallTool(w http.ResponseWriter, r *http.Request) {
 		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
 		return
 	}
+	if _, _, err := h.server.profileFor(r.Context(), req.Arguments); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), "UNKNOWN_PROFILE")
+		return
+	}
+	if authenticatedTools[req.Name] && h.server.toolExposed(TransportHTTP, req.Name) && !h.adminAuthorized(r) {
+		h.writeUnauthorized(w, "admin")
+		return
+	}
 
-	result, err := h.callMCPTool(r.Context(), req.Name, req.Arguments)
+	ctx, warnings := withWarnings(r.Context())
+	result, err := h.callMCPTool(ctx, req.Name, req.Arguments)
+	var busy *BusyError
+	if errors.As(err, &busy) {
+		h.writeBusyResponse(w, busy)
+		return
+	}
+	var disabled *WriteDisabledError
+	if errors.As(err, &disabled) {
+		h.writeErrorResponse(w, http.StatusForbidden, disabled.Error(), "WRITE_DISABLED")
+		return
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		h.writeJSONResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"message":     "Internal server error",
+			"code":        "INTERNAL_ERROR",
+			"incident_id": panicErr.IncidentID,
+		})
+		return
+	}
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Tool execution failed: %v", err), "TOOL_EXECUTION_FAILED")
 		return
 	}
 
-	// For MCP tool calls, return the raw MCP response format
-	h.writeJSONResponse(w, http.StatusOK, result)
+	// For MCP tool calls, return the MCP response format
+	h.writeJSONResponse(w, http.StatusOK, h.server.wrapResult(result, warnings))
 }
 
 // handleListResources handles resource listing requests
@@ -252,10 +572,16 @@ func (h *HTTPBridge) handleListResources(w http.ResponseWriter, r *http.Request)
 			Description: "API cache performance metrics",
 			MimeType:    "application/json",
 		},
+		{
+			URI:         "admin://anomalies",
+			Name:        "Upstream API Anomalies",
+			Description: "Upstream responses deviating from the expected schema, such as unknown or missing fields",
+			MimeType:    "application/json",
+		},
 	}
 
 	response := ListResourcesResponse{
-		Resources: resources,
+		Resources: h.server.exposedResources(TransportHTTP, resources),
 	}
 
 	h.writeJSONResponse(w, http.StatusOK, response)
@@ -270,17 +596,20 @@ func (h *HTTPBridge) handleReadResource(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Parse URI and find appropriate handler
-	parts := strings.SplitN(req.URI, "://", 2)
-	if len(parts) != 2 {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid resource URI format", "INVALID_URI")
+	scheme, path, err := parseResourceURI(req.URI)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid resource URI format: %v", err), "INVALID_URI")
 		return
 	}
 
-	scheme := parts[0]
-	path := parts[1]
+	server, _, err := h.server.profileFor(r.Context(), nil)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), "UNKNOWN_PROFILE")
+		return
+	}
 
-	handler, exists := h.server.resources[scheme]
-	if !exists {
+	handler, exists := server.resources[scheme]
+	if !exists || !server.resourceExposed(TransportHTTP, req.URI) {
 		h.writeErrorResponse(w, http.StatusNotFound, fmt.Sprintf("Resource scheme not found: %s", scheme), "RESOURCE_NOT_FOUND")
 		return
 	}
@@ -325,9 +654,17 @@ func (h *HTTPBridge) handleGetPlayerProfile(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	playerID := vars["id"]
 
-	result, err := h.callMCPTool(r.Context(), "get_player_profile", map[string]interface{}{
+	args := map[string]interface{}{
 		"player_id": playerID,
-	})
+	}
+	if includeElo, err := strconv.ParseBool(r.URL.Query().Get("include_elo")); err == nil {
+		args["include_elo"] = includeElo
+	}
+	if bypass, err := strconv.ParseBool(r.URL.Query().Get("bypass_cache")); err == nil {
+		args["bypass_cache"] = bypass
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_player_profile", args)
 	
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Player profile retrieval failed", "PLAYER_PROFILE_FAILED")
@@ -354,8 +691,176 @@ func (h *HTTPBridge) handleGetPlayerRatingHistory(w http.ResponseWriter, r *http
 	h.writeMCPToolResponse(w, result)
 }
 
+// handleRenderRatingChart serves a player's rating chart as an image
+// (?format=svg&width=800&height=400)
+func (h *HTTPBridge) handleRenderRatingChart(w http.ResponseWriter, r *http.Request) {
+	args := chartQueryArgs(r, "width", "height")
+	args["player_id"] = mux.Vars(r)["id"]
+
+	result, err := h.callMCPTool(r.Context(), "render_rating_chart", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Rating chart rendering failed", "RATING_CHART_FAILED")
+		return
+	}
+
+	h.writeMCPImageResponse(w, result)
+}
+
+// handleRenderClubDistribution serves a club's rating distribution as an
+// image (?format=svg&bucket_size=200)
+func (h *HTTPBridge) handleRenderClubDistribution(w http.ResponseWriter, r *http.Request) {
+	args := chartQueryArgs(r, "width", "height", "bucket_size")
+	args["club_id"] = mux.Vars(r)["id"]
+
+	result, err := h.callMCPTool(r.Context(), "render_club_distribution", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Club distribution rendering failed", "CLUB_DISTRIBUTION_FAILED")
+		return
+	}
+
+	h.writeMCPImageResponse(w, result)
+}
+
+// chartQueryArgs returns the format and the given integer query parameters
+// of a chart request as tool arguments
+func chartQueryArgs(r *http.Request, integers ...string) map[string]interface{} {
+	query := r.URL.Query()
+	args := map[string]interface{}{"format": query.Get("format")}
+	for _, name := range integers {
+		if n, err := strconv.Atoi(query.Get(name)); err == nil {
+			args[name] = float64(n)
+		}
+	}
+	return args
+}
+
+// handleGetPlayerRatingAtDate handles historical rating requests (?date=2022-01)
+func (h *HTTPBridge) handleGetPlayerRatingAtDate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	playerID := vars["id"]
+
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "date query parameter is required", "INVALID_REQUEST")
+		return
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_player_rating_at_date", map[string]interface{}{
+		"player_id": playerID,
+		"date":      date,
+	})
+
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Player rating retrieval failed", "PLAYER_RATING_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetPlayerForm handles player form requests (?evaluations=5)
+func (h *HTTPBridge) handleGetPlayerForm(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	args := map[string]interface{}{
+		"player_id": vars["id"],
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("evaluations")); err == nil {
+		args["evaluations"] = float64(n)
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_player_form", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Player form retrieval failed", "PLAYER_FORM_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetPlayerPercentile handles player percentile requests
+// (?region=Württemberg&include_national=true)
+func (h *HTTPBridge) handleGetPlayerPercentile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	args := map[string]interface{}{
+		"player_id": vars["id"],
+		"region":    r.URL.Query().Get("region"),
+	}
+	if includeNational, err := strconv.ParseBool(r.URL.Query().Get("include_national")); err == nil {
+		args["include_national"] = includeNational
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_player_percentile", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Player percentile retrieval failed", "PLAYER_PERCENTILE_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
 // Club handlers
 
+// handleDownloadClubExport streams a club export archive for a signed URL
+// returned by export_club_data (?expires=...&signature=...)
+func (h *HTTPBridge) handleDownloadClubExport(w http.ResponseWriter, r *http.Request) {
+	clubID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	profile := query.Get("profile")
+	if err := verifyExportSignature(h.server.exportKey, profile, clubID, query.Get("expires"), query.Get("signature"), time.Now()); err != nil {
+		h.writeErrorResponse(w, http.StatusForbidden, err.Error(), "INVALID_EXPORT_LINK")
+		return
+	}
+	server, err := h.server.profileServer(profile)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), "UNKNOWN_PROFILE")
+		return
+	}
+
+	data, err := server.loadClubExport(r.Context(), clubID)
+	if err != nil {
+		h.logger.WithError(err).WithField("club_id", clubID).Error("Club export failed")
+		h.writeErrorResponse(w, http.StatusBadGateway, "Club export failed", "EXPORT_FAILED")
+		return
+	}
+
+	filename := fmt.Sprintf("club-%s-%s.zip", clubID, time.Now().Format("20060102"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := writeClubExport(w, data); err != nil {
+		h.logger.WithError(err).WithField("club_id", clubID).Error("Failed to stream club export")
+	}
+}
+
+// handleGetQRCode serves the QR code of an entity's deep link as a PNG
+// image (?scale=8&error_correction=M)
+func (h *HTTPBridge) handleGetQRCode(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	scale := defaultQRScale
+	if n, err := strconv.Atoi(query.Get("scale")); err == nil {
+		scale = n
+	}
+	level := "M"
+	if value := query.Get("error_correction"); value != "" {
+		level = value
+	}
+
+	server, err := h.server.profileServer(profileFromContext(r.Context()))
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), "UNKNOWN_PROFILE")
+		return
+	}
+	_, image, err := server.entityQRCode(mux.Vars(r)["id"], scale, level)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), "INVALID_REQUEST")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if _, err := w.Write(image); err != nil {
+		h.logger.WithError(err).Warn("Failed to write QR code")
+	}
+}
+
 // handleSearchClubs handles club search requests
 func (h *HTTPBridge) handleSearchClubs(w http.ResponseWriter, r *http.Request) {
 	params := h.parseSearchParams(r)
@@ -426,9 +931,14 @@ func (h *HTTPBridge) handleGetClubStatistics(w http.ResponseWriter, r *http.Requ
 	vars := mux.Vars(r)
 	clubID := vars["id"]
 
-	result, err := h.callMCPTool(r.Context(), "get_club_statistics", map[string]interface{}{
+	args := map[string]interface{}{
 		"club_id": clubID,
-	})
+	}
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		args["as_of"] = asOf
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_club_statistics", args)
 	
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Club statistics retrieval failed", "CLUB_STATISTICS_FAILED")
@@ -438,6 +948,78 @@ func (h *HTTPBridge) handleGetClubStatistics(w http.ResponseWriter, r *http.Requ
 	h.writeMCPToolResponse(w, result)
 }
 
+// handleGetClubTeams handles club team requests (?season=2023/24)
+func (h *HTTPBridge) handleGetClubTeams(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	result, err := h.callMCPTool(r.Context(), "get_club_teams", map[string]interface{}{
+		"club_id": vars["id"],
+		"season":  r.URL.Query().Get("season"),
+	})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Club teams retrieval failed", "CLUB_TEAMS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetClubOfficials handles club officials requests
+func (h *HTTPBridge) handleGetClubOfficials(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	result, err := h.callMCPTool(r.Context(), "get_club_officials", map[string]interface{}{
+		"club_id": vars["id"],
+	})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Club officials retrieval failed", "CLUB_OFFICIALS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetReactivationCandidates handles requests for the inactive members
+// of a club (?min_dwz=1600&limit=20)
+func (h *HTTPBridge) handleGetReactivationCandidates(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	args := map[string]interface{}{
+		"club_id": vars["id"],
+	}
+	if minDWZ, err := strconv.Atoi(query.Get("min_dwz")); err == nil {
+		args["min_dwz"] = float64(minDWZ)
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		args["limit"] = float64(limit)
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_reactivation_candidates", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Reactivation candidates retrieval failed", "REACTIVATION_CANDIDATES_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetTeamRoster handles team roster requests
+func (h *HTTPBridge) handleGetTeamRoster(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	result, err := h.callMCPTool(r.Context(), "get_team_roster", map[string]interface{}{
+		"club_id": vars["id"],
+		"team":    vars["team"],
+	})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Team roster retrieval failed", "TEAM_ROSTER_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
 // Tournament handlers
 
 // handleSearchTournaments handles tournament search requests
@@ -473,6 +1055,7 @@ func (h *HTTPBridge) handleSearchTournamentsByDate(w http.ResponseWriter, r *htt
 	result, err := h.callMCPTool(r.Context(), "search_tournaments_by_date", map[string]interface{}{
 		"start_date":   startDate,
 		"end_date":     endDate,
+		"period":       r.URL.Query().Get("period"),
 		"query":        params["query"],
 		"limit":        params["limit"],
 		"offset":       params["offset"],
@@ -520,14 +1103,143 @@ func (h *HTTPBridge) handleGetRecentTournaments(w http.ResponseWriter, r *http.R
 	h.writeMCPToolResponse(w, result)
 }
 
+// handleGetTournamentChanges handles incremental tournament sync requests
+// (?changes_since=<sync_token>&days=30)
+func (h *HTTPBridge) handleGetTournamentChanges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	args := map[string]interface{}{
+		"changes_since": query.Get("changes_since"),
+	}
+	if days, err := strconv.Atoi(query.Get("days")); err == nil {
+		args["days"] = float64(days)
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_tournament_changes", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Tournament changes retrieval failed", "TOURNAMENT_CHANGES_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetUpcomingTournaments handles upcoming tournaments requests
+// (?days=30&region=Württemberg&city=Ulm&limit=50)
+func (h *HTTPBridge) handleGetUpcomingTournaments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	args := map[string]interface{}{
+		"region": query.Get("region"),
+		"city":   query.Get("city"),
+	}
+	if days, err := strconv.Atoi(query.Get("days")); err == nil {
+		args["days_ahead"] = float64(days)
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		args["limit"] = float64(limit)
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_upcoming_tournaments", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Upcoming tournaments retrieval failed", "UPCOMING_TOURNAMENTS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetRegionActivity handles monthly tournament activity requests
+// (?region=Württemberg&start_date=2024-01&end_date=2024-12 or &period=last 2 years)
+func (h *HTTPBridge) handleGetRegionActivity(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("region") == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "region parameter is required", "INVALID_REQUEST")
+		return
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_region_activity", map[string]interface{}{
+		"region":     query.Get("region"),
+		"start_date": query.Get("start_date"),
+		"end_date":   query.Get("end_date"),
+		"period":     query.Get("period"),
+	})
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Region activity retrieval failed", "REGION_ACTIVITY_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetOrganizerTournaments handles organizer tournament requests
+// (?club_id=C0327 or ?organizer=SF Ulm, with &period=2024 or &start_date=...&end_date=...&limit=100)
+func (h *HTTPBridge) handleGetOrganizerTournaments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("club_id") == "" && query.Get("organizer") == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "club_id or organizer parameter is required", "INVALID_REQUEST")
+		return
+	}
+
+	args := map[string]interface{}{
+		"club_id":    query.Get("club_id"),
+		"organizer":  query.Get("organizer"),
+		"start_date": query.Get("start_date"),
+		"end_date":   query.Get("end_date"),
+		"period":     query.Get("period"),
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		args["limit"] = float64(limit)
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_organizer_tournaments", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Organizer tournaments retrieval failed", "ORGANIZER_TOURNAMENTS_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
+// handleGetTournamentSeries handles tournament series requests
+// (?query=Ulm Open&max_editions=10&include_winners=false)
+func (h *HTTPBridge) handleGetTournamentSeries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "query parameter is required", "INVALID_REQUEST")
+		return
+	}
+
+	args := map[string]interface{}{
+		"query": query,
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("max_editions")); err == nil {
+		args["max_editions"] = float64(n)
+	}
+	if include, err := strconv.ParseBool(r.URL.Query().Get("include_winners")); err == nil {
+		args["include_winners"] = include
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_tournament_series", args)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "Tournament series retrieval failed", "TOURNAMENT_SERIES_FAILED")
+		return
+	}
+
+	h.writeMCPToolResponse(w, result)
+}
+
 // handleGetTournamentDetails handles tournament details requests
 func (h *HTTPBridge) handleGetTournamentDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tournamentID := vars["id"]
 
-	result, err := h.callMCPTool(r.Context(), "get_tournament_details", map[string]interface{}{
+	args := map[string]interface{}{
 		"tournament_id": tournamentID,
-	})
+	}
+	if bypass, err := strconv.ParseBool(r.URL.Query().Get("bypass_cache")); err == nil {
+		args["bypass_cache"] = bypass
+	}
+
+	result, err := h.callMCPTool(r.Context(), "get_tournament_details", args)
 	
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusInternalServerError, "Tournament details retrieval failed", "TOURNAMENT_DETAILS_FAILED")
@@ -622,15 +1334,24 @@ func (h *HTTPBridge) parseSearchParams(r *http.Request) map[string]interface{} {
 
 // callMCPTool calls an MCP tool and returns the result
 func (h *HTTPBridge) callMCPTool(ctx context.Context, toolName string, args map[string]interface{}) (*CallToolResponse, error) {
-	handler, exists := h.server.tools[toolName]
+	profile, args, err := h.server.profileFor(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	handler, exists := profile.lookupTool(TransportHTTP, toolName)
 	if !exists {
 		return nil, fmt.Errorf("tool not found: %s", toolName)
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"tool": toolName,
 		"args": args,
-	}).Debug("Executing tool via HTTP bridge")
+	}
+	if profile.profile != "" {
+		fields["profile"] = profile.profile
+	}
+	h.logger.WithFields(fields).Debug("Executing tool via HTTP bridge")
 
 	result, err := handler(ctx, args)
 	if err != nil {
@@ -641,6 +1362,29 @@ func (h *HTTPBridge) callMCPTool(ctx context.Context, toolName string, args map[
 	return result, nil
 }
 
+// writeMCPImageResponse writes the first image of an MCP tool response
+// with its MIME type. Responses without an image are written as JSON.
+func (h *HTTPBridge) writeMCPImageResponse(w http.ResponseWriter, result *CallToolResponse) {
+	if result != nil && !result.IsError {
+		for _, content := range result.Content {
+			encoded, ok := content.Data.(string)
+			if content.Type != "image" || !ok {
+				continue
+			}
+			image, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				break
+			}
+			w.Header().Set("Content-Type", content.MimeType)
+			if _, err := w.Write(image); err != nil {
+				h.logger.WithError(err).Warn("Failed to write image")
+			}
+			return
+		}
+	}
+	h.writeMCPToolResponse(w, result)
+}
+
 // writeMCPToolResponse writes an MCP tool response as HTTP JSON
 func (h *HTTPBridge) writeMCPToolResponse(w http.ResponseWriter, result *CallToolResponse) {
 	if result == nil {
@@ -658,10 +1402,10 @@ func (h *HTTPBridge) writeMCPToolResponse(w http.ResponseWriter, result *CallToo
 		// Handle text content
 		textContent := result.Content[0].Text
 		if textContent != "" {
-			// Try to parse as JSON first
-			var jsonData interface{}
-			if err := json.Unmarshal([]byte(textContent), &jsonData); err == nil {
-				h.writeJSONResponse(w, http.StatusOK, jsonData)
+			// JSON text is passed through, large tool results would
+			// otherwise be decoded and encoded once more
+			if validJSON(textContent) {
+				h.writeRawJSONResponse(w, http.StatusOK, textContent)
 				return
 			}
 			// If not JSON, return as text response
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMCPToolResponse(t *testing.T) {
+	s := newTestServer()
+	bridge := NewHTTPBridge(s, s.logger)
+	write := func(result *CallToolResponse) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		bridge.writeMCPToolResponse(rec, result)
+		return rec
+	}
+
+	t.Run("JSON text is passed through", func(t *testing.T) {
+		text := "{\n  \"name\": \"Müller\",\n  \"url\": \"a<b>&c\"\n}"
+		rec := write(&CallToolResponse{Content: []ToolContent{{Type: "text", Text: text}}})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, text+"\n", rec.Body.String())
+	})
+
+	t.Run("Plain text is wrapped", func(t *testing.T) {
+		rec := write(&CallToolResponse{Content: []ToolContent{{Type: "text", Text: "not json"}}})
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "not json", body["data"])
+	})
+
+	t.Run("Data content is encoded", func(t *testing.T) {
+		rec := write(&CallToolResponse{Content: []ToolContent{{Type: "resource", Data: map[string]int{"count": 3}}}})
+		assert.JSONEq(t, `{"count": 3}`, rec.Body.String())
+	})
+
+	t.Run("Tool errors", func(t *testing.T) {
+		rec := write(&CallToolResponse{IsError: true, Content: []ToolContent{{Type: "text", Text: "Error: failed"}}})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -0,0 +1,233 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/aliases"
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// idArguments maps tool argument names to the kind of ID they carry
+var idArguments = map[string]api.IDKind{
+	"player_id":     api.IDKindPlayer,
+	"club_id":       api.IDKindClub,
+	"tournament_id": api.IDKindTournament,
+}
+
+// maxIDSuggestions bounds the number of suggestions returned by resolve_id
+const maxIDSuggestions = 5
+
+// digitsPattern extracts the numeric part of an ID
+var digitsPattern = regexp.MustCompile(`\d+`)
+
+// normalizeIDArgs wraps a tool handler so that ID arguments in common
+// variants (lowercase, "/" separators, missing zero padding) are normalized
+// before the call. IDs that cannot be parsed are passed on unchanged.
+func normalizeIDArgs(handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		normalized := make(map[string]interface{}, len(args))
+		for k, v := range args {
+			normalized[k] = v
+			kind, ok := idArguments[k]
+			if !ok {
+				continue
+			}
+			if raw, ok := v.(string); ok {
+				if id, err := api.NormalizeID(kind, raw); err == nil {
+					normalized[k] = id
+				}
+			}
+		}
+		return handler(ctx, normalized)
+	}
+}
+
+// IDResolution is the result of resolving a possibly malformed ID
+type IDResolution struct {
+	Input       string         `json:"input"`
+	Kind        api.IDKind     `json:"kind"`
+	Normalized  string         `json:"normalized,omitempty"`
+	Valid       bool           `json:"valid"`
+	Exists      bool           `json:"exists"`
+	Error       string         `json:"error,omitempty"`
+	Suggestions []IDSuggestion `json:"suggestions,omitempty"`
+	// CurrentID is the ID a historic club ID was merged into or renamed
+	// to, following Aliases
+	CurrentID string          `json:"current_id,omitempty"`
+	Aliases   []aliases.Alias `json:"aliases,omitempty"`
+}
+
+// IDSuggestion is a likely correction of an ID
+type IDSuggestion struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// resolveID validates an ID, checks that it exists and suggests corrections
+func (s *Server) resolveID(ctx context.Context, raw string, kind api.IDKind) *IDResolution {
+	res := &IDResolution{Input: raw, Kind: kind}
+
+	if kind == "" || kind == api.IDKindUnknown {
+		res.Kind, res.Normalized = api.DetectIDKind(raw)
+		res.Valid = res.Kind != api.IDKindUnknown
+	} else if id, err := api.NormalizeID(kind, raw); err == nil {
+		res.Normalized = id
+		res.Valid = true
+	} else {
+		res.Error = err.Error()
+	}
+
+	if res.Valid && res.Kind == api.IDKindClub {
+		if current, chain := s.clubAliases.resolve(res.Normalized); len(chain) > 0 {
+			res.CurrentID, res.Aliases = current, chain
+		}
+	}
+
+	if res.Valid {
+		var err error
+		switch res.Kind {
+		case api.IDKindPlayer:
+			_, err = s.apiClient.GetPlayerProfile(ctx, res.Normalized)
+		case api.IDKindClub:
+			id := res.Normalized
+			if res.CurrentID != "" {
+				id = res.CurrentID
+			}
+			_, err = s.apiClient.GetClubProfile(ctx, id)
+		case api.IDKindTournament:
+			_, err = s.apiClient.GetTournamentDetails(ctx, res.Normalized)
+		}
+		if err == nil {
+			res.Exists = true
+			return res
+		}
+		res.Error = err.Error()
+	}
+
+	res.Suggestions = s.suggestIDs(ctx, raw, res)
+	return res
+}
+
+// suggestIDs searches for IDs similar to an unresolved one
+func (s *Server) suggestIDs(ctx context.Context, raw string, res *IDResolution) []IDSuggestion {
+	var suggestions []IDSuggestion
+	target := res.Normalized
+	if target == "" {
+		target = strings.ToUpper(strings.TrimSpace(raw))
+	}
+
+	switch res.Kind {
+	case api.IDKindPlayer:
+		// A valid club with a wrong member number: suggest close members
+		clubID := strings.SplitN(target, "-", 2)[0]
+		if result, err := s.apiClient.GetClubPlayers(ctx, clubID, api.SearchParams{Limit: 500}); err == nil {
+			players, _ := result.Data.([]api.PlayerResponse)
+			for _, p := range players {
+				if levenshtein(p.ID, target) <= 2 {
+					suggestions = append(suggestions, IDSuggestion{ID: p.ID, Name: p.Firstname + " " + p.Name, Reason: "similar member number"})
+				}
+			}
+		}
+	case api.IDKindTournament:
+		if result, err := s.apiClient.SearchTournaments(ctx, api.SearchParams{Query: raw, Limit: maxIDSuggestions}); err == nil {
+			tournaments, _ := result.Data.([]api.TournamentResponse)
+			for _, t := range tournaments {
+				suggestions = append(suggestions, IDSuggestion{ID: t.ID, Name: t.Name, Reason: "search match"})
+			}
+		}
+	default:
+		// Clubs and IDs missing their prefix: search clubs by the numeric part
+		digits := digitsPattern.FindString(target)
+		if digits == "" {
+			break
+		}
+		if result, err := s.apiClient.SearchClubs(ctx, api.SearchParams{Query: digits, Limit: 50}); err == nil {
+			clubs, _ := result.Data.([]api.ClubResponse)
+			member := ""
+			if parts := strings.SplitN(target, "-", 2); len(parts) == 2 {
+				member = parts[1]
+			}
+			for _, c := range clubs {
+				if !strings.HasSuffix(c.ID, digits) && levenshtein(c.ID, target) > 1 {
+					continue
+				}
+				if member != "" {
+					suggestions = append(suggestions, IDSuggestion{ID: c.ID + "-" + member, Name: c.Name, Reason: "club prefix added"})
+				} else {
+					suggestions = append(suggestions, IDSuggestion{ID: c.ID, Name: c.Name, Reason: "similar club ID"})
+				}
+			}
+		}
+	}
+
+	if len(suggestions) > maxIDSuggestions {
+		suggestions = suggestions[:maxIDSuggestions]
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// handleResolveID handles ID validation and correction requests
+func (s *Server) handleResolveID(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	raw, ok := args["id"].(string)
+	if !ok || strings.TrimSpace(raw) == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	var kind api.IDKind
+	if k, ok := args["kind"].(string); ok && k != "" {
+		kind = api.IDKind(strings.ToLower(k))
+		if _, known := map[api.IDKind]bool{api.IDKindPlayer: true, api.IDKindClub: true, api.IDKindTournament: true}[kind]; !known {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: invalid kind %q, expected player, club or tournament", k),
+				}},
+				IsError: true,
+			}, nil
+		}
+	}
+
+	result := s.resolveID(ctx, raw, kind)
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
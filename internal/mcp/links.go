@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// linkFields are the ID fields of tool results referring to other entities
+// and the fields their deep links are added as
+var linkFields = map[string]struct {
+	kind api.IDKind
+	link string
+}{
+	"player_id":         {api.IDKindPlayer, "player_url"},
+	"club_id":           {api.IDKindClub, "club_url"},
+	"organizer_club_id": {api.IDKindClub, "organizer_club_url"},
+	"tournament_id":     {api.IDKindTournament, "tournament_url"},
+}
+
+// deepLinks builds links to the public web pages of entities from the
+// templates of links in the config
+type deepLinks map[api.IDKind]string
+
+// deepLinks returns the link templates, nil if no links are added. Links
+// point to the pages of the default federation, so other profiles have
+// none.
+func (s *Server) deepLinks() deepLinks {
+	if s.config == nil || s.profile != "" {
+		return nil
+	}
+	links := deepLinks{}
+	for kind, template := range map[api.IDKind]string{
+		api.IDKindPlayer:     s.config.Links.PlayerURL,
+		api.IDKindClub:       s.config.Links.ClubURL,
+		api.IDKindTournament: s.config.Links.TournamentURL,
+	} {
+		if template != "" {
+			links[kind] = template
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return links
+}
+
+// url returns the link of an entity, empty if the value is no canonical ID
+// of the kind or the kind has no template
+func (l deepLinks) url(kind api.IDKind, id string) string {
+	template, ok := l[kind]
+	if !ok || id == "" {
+		return ""
+	}
+	if canonical, err := api.NormalizeID(kind, id); err != nil || canonical != id {
+		return ""
+	}
+	return strings.ReplaceAll(template, "{id}", url.PathEscape(id))
+}
+
+// addDeepLinks adds a url next to the id of players, clubs and tournaments
+// in the results of data tools, and a player_url, club_url or
+// tournament_url next to fields referring to them, so that clients can cite
+// a page for every entity they mention. The order of the fields is kept.
+func (s *Server) addDeepLinks(name string, handler ToolHandler) ToolHandler {
+	if !dataTool(name) {
+		return handler
+	}
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		result, err := handler(ctx, args)
+		links := s.deepLinks()
+		if err != nil || result == nil || result.IsError || links == nil {
+			return result, err
+		}
+		for i, content := range result.Content {
+			if content.Type != "text" || !json.Valid([]byte(content.Text)) {
+				continue
+			}
+			var linked bytes.Buffer
+			dec := json.NewDecoder(strings.NewReader(content.Text))
+			dec.UseNumber()
+			if err := links.rewrite(dec, &linked); err != nil {
+				s.logger.WithError(err).WithField("tool", name).Warn("Failed to add deep links")
+				continue
+			}
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, linked.Bytes(), "", "  "); err != nil {
+				continue
+			}
+			result.Content[i].Text = indented.String()
+		}
+		return result, nil
+	}
+}
+
+// linkedMember is a member of a JSON object being rewritten
+type linkedMember struct {
+	key   string
+	value []byte
+	text  string // Value of string members
+}
+
+// rewrite copies the next JSON value of dec to out, adding links to the
+// objects in it
+func (l deepLinks) rewrite(dec *json.Decoder, out *bytes.Buffer) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			return l.rewriteArray(dec, out)
+		}
+		return l.rewriteObject(dec, out)
+	case string:
+		data, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		out.Write(data)
+	case json.Number:
+		out.WriteString(token.String())
+	case bool:
+		if token {
+			out.WriteString("true")
+		} else {
+			out.WriteString("false")
+		}
+	case nil:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+// rewriteArray copies the elements of an array after its opening bracket
+func (l deepLinks) rewriteArray(dec *json.Decoder, out *bytes.Buffer) error {
+	out.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if err := l.rewrite(dec, out); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	out.WriteByte(']')
+	return nil
+}
+
+// rewriteObject copies the members of an object after its opening brace.
+// The id of an object is linked if it is a player or tournament ID, or a
+// club ID of an object that does not refer to a player or tournament, as
+// games and evaluations carry IDs of their own.
+func (l deepLinks) rewriteObject(dec *json.Decoder, out *bytes.Buffer) error {
+	var members []linkedMember
+	keys := make(map[string]bool)
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		var value bytes.Buffer
+		if err := l.rewrite(dec, &value); err != nil {
+			return err
+		}
+		member := linkedMember{key: key, value: value.Bytes()}
+		json.Unmarshal(member.value, &member.text)
+		members = append(members, member)
+		keys[key] = true
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	out.WriteByte('{')
+	for i, member := range members {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		writeMember(out, member.key, member.value)
+
+		var link, field string
+		if member.key == "id" {
+			field = "url"
+			kind, _ := api.DetectIDKind(member.text)
+			if kind != api.IDKindClub || (!keys["player_id"] && !keys["tournament_id"]) {
+				link = l.url(kind, member.text)
+			}
+		} else if ref, ok := linkFields[member.key]; ok {
+			field = ref.link
+			link = l.url(ref.kind, member.text)
+		}
+		if link != "" && !keys[field] {
+			out.WriteByte(',')
+			value, _ := json.Marshal(link)
+			writeMember(out, field, value)
+		}
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+// writeMember writes a member of an object
+func writeMember(out io.Writer, key string, value []byte) {
+	data, _ := json.Marshal(key)
+	out.Write(data)
+	io.WriteString(out, ":")
+	out.Write(value)
+}
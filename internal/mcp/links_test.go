@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestAddDeepLinks(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{Links: config.LinksConfig{
+		PlayerURL: "https://www.schachbund.de/spieler/{id}.html",
+		ClubURL:   "https://www.schachbund.de/verein/{id}.html",
+	}}
+	output := `{"tournament": {"id": "C350-C01-SMU", "organizer_club_id": "C0327"},
+		"games": [{"id": "1234", "tournament_id": "C350-C01-SMU", "player_id": "C0327-297", "score": 0.5}],
+		"clubs": [{"id": "C0327", "url": "https://www.sc-altbach.de"}, {"id": "c0327"}],
+		"total": 3, "complete": true, "next": null}`
+	handler := s.addDeepLinks("get_tournament_details", func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		return &CallToolResponse{Content: []ToolContent{{Type: "text", Text: output}}}, nil
+	})
+
+	result, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tournament": {"id": "C350-C01-SMU", "organizer_club_id": "C0327", "organizer_club_url": "https://www.schachbund.de/verein/C0327.html"},
+		"games": [{"id": "1234", "tournament_id": "C350-C01-SMU", "player_id": "C0327-297", "player_url": "https://www.schachbund.de/spieler/C0327-297.html", "score": 0.5}],
+		"clubs": [{"id": "C0327", "url": "https://www.sc-altbach.de"}, {"id": "c0327"}],
+		"total": 3, "complete": true, "next": null}`, result.Content[0].Text,
+		"tournaments without a template, IDs of games and non-canonical IDs are not linked, existing fields are kept")
+	assert.Regexp(t, `"player_id": "C0327-297",\n\s+"player_url"`, result.Content[0].Text, "links follow their ID field")
+
+	s.profile = "test"
+	result, err = handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, output, result.Content[0].Text, "other profiles are not linked")
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// limitListener returns a listener accepting at most n simultaneous
+// connections. Further connections wait in the kernel backlog until an
+// accepted one is closed, which applies back-pressure to clients instead of
+// growing the number of connections without bound.
+func limitListener(l net.Listener, n int) net.Listener {
+	return &limitedListener{
+		Listener: l,
+		sem:      make(chan struct{}, n),
+		done:     make(chan struct{}),
+	}
+}
+
+type limitedListener struct {
+	net.Listener
+	sem       chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// acquire blocks until a connection slot is free or the listener is closed
+func (l *limitedListener) acquire() bool {
+	select {
+	case <-l.done:
+		return false
+	case l.sem <- struct{}{}:
+		return true
+	}
+}
+
+func (l *limitedListener) release() { <-l.sem }
+
+func (l *limitedListener) Accept() (net.Conn, error) {
+	if !l.acquire() {
+		// The listener is closed, let the underlying Accept report it
+		return l.Listener.Accept()
+	}
+
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	return &limitedConn{Conn: conn, release: l.release}, nil
+}
+
+func (l *limitedListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+// limitedConn frees its connection slot when closed
+type limitedConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
+
+// Environment variables through which a listening socket is passed to the
+// process. LISTEN_PID and LISTEN_FDS follow the systemd socket activation
+// protocol, the handover variables are set by Handover for its successor.
+const (
+	listenPIDEnv      = "LISTEN_PID"
+	listenFDsEnv      = "LISTEN_FDS"
+	listenFDNamesEnv  = "LISTEN_FDNAMES"
+	handoverFDEnv     = "PORTAL64_HANDOVER_FD"
+	handoverReadyEnv  = "PORTAL64_HANDOVER_READY_FD"
+	listenFDsStart    = 3 // First file descriptor passed by systemd
+	handoverFDName    = "handover"
+	handoverReadyName = "handover-ready"
+)
+
+// inheritedListener returns the listening socket passed by systemd socket
+// activation or by a predecessor handing over, or nil if there is none. The
+// variables are removed from the environment, so that child processes do
+// not take them for their own.
+func inheritedListener() (net.Listener, error) {
+	if fd, ok := envFD(handoverFDEnv); ok {
+		os.Unsetenv(handoverFDEnv)
+		return fileListener(fd, handoverFDName)
+	}
+
+	pid, fds := os.Getenv(listenPIDEnv), os.Getenv(listenFDsEnv)
+	if pid == "" || fds == "" {
+		return nil, nil
+	}
+	os.Unsetenv(listenPIDEnv)
+	os.Unsetenv(listenFDsEnv)
+	os.Unsetenv(listenFDNamesEnv)
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	if n, err := strconv.Atoi(fds); err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid %s: %q", listenFDsEnv, fds)
+	}
+	return fileListener(listenFDsStart, "systemd")
+}
+
+// envFD returns the file descriptor number stored in an environment variable
+func envFD(name string) (uintptr, bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, false
+	}
+	fd, err := strconv.ParseUint(value, 10, 32)
+	return uintptr(fd), err == nil
+}
+
+// fileListener creates a listener from an inherited file descriptor
+func fileListener(fd uintptr, name string) (net.Listener, error) {
+	file := os.NewFile(fd, name)
+	if file == nil {
+		return nil, fmt.Errorf("invalid %s socket file descriptor %d", name, fd)
+	}
+	defer file.Close()
+	l, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s socket file descriptor %d: %w", name, fd, err)
+	}
+	return l, nil
+}
+
+// listen returns the listening socket of the HTTP bridge. A socket passed by
+// systemd or by a predecessor is used as is, otherwise the address is bound,
+// with SO_REUSEPORT if reusePort is set.
+func listen(ctx context.Context, addr string, reusePort bool) (net.Listener, error) {
+	l, err := inheritedListener()
+	if l != nil || err != nil {
+		return l, err
+	}
+	var lc net.ListenConfig
+	if reusePort {
+		lc.Control = reusePortControl
+	}
+	return lc.Listen(ctx, "tcp", addr)
+}
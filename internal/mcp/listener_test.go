@@ -0,0 +1,240 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestLimitListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l := limitListener(inner, 1)
+	defer l.Close()
+
+	for i := 0; i < 2; i++ {
+		client, err := net.Dial("tcp", inner.Addr().String())
+		require.NoError(t, err)
+		defer client.Close()
+	}
+
+	first, err := l.Accept()
+	require.NoError(t, err)
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+		close(accepted)
+	}()
+
+	select {
+	case <-accepted:
+		t.Fatal("second connection accepted while the limit is reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Close())
+	first.Close() // Closing twice frees the slot only once
+	select {
+	case second := <-accepted:
+		require.NotNil(t, second)
+		second.Close()
+	case <-time.After(time.Second):
+		t.Fatal("second connection not accepted after the first was closed")
+	}
+}
+
+func TestLimitListener_Close(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l := limitListener(inner, 1)
+
+	client, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	errs := make(chan error)
+	go func() {
+		_, err := l.Accept()
+		errs <- err
+	}()
+	require.NoError(t, l.Close())
+
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Accept blocked after Close")
+	}
+}
+
+func TestInheritedListener_Handover(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer inner.Close()
+	file, err := inner.(*net.TCPListener).File()
+	require.NoError(t, err)
+	t.Setenv(handoverFDEnv, strconv.Itoa(int(file.Fd())))
+
+	l, err := inheritedListener()
+	file.Close() // Already closed by inheritedListener, which owns the descriptor
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	defer l.Close()
+	assert.Equal(t, inner.Addr().String(), l.Addr().String())
+	assert.Empty(t, os.Getenv(handoverFDEnv))
+
+	// The inherited socket accepts connections to the original address
+	client, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	inner.Close()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestInheritedListener_Systemd(t *testing.T) {
+	t.Run("no socket", func(t *testing.T) {
+		l, err := inheritedListener()
+		assert.NoError(t, err)
+		assert.Nil(t, l)
+	})
+
+	t.Run("other process", func(t *testing.T) {
+		t.Setenv(listenPIDEnv, strconv.Itoa(os.Getpid()+1))
+		t.Setenv(listenFDsEnv, "1")
+		l, err := inheritedListener()
+		assert.NoError(t, err)
+		assert.Nil(t, l)
+		assert.Empty(t, os.Getenv(listenPIDEnv))
+		assert.Empty(t, os.Getenv(listenFDsEnv))
+	})
+
+	t.Run("no descriptors", func(t *testing.T) {
+		t.Setenv(listenPIDEnv, strconv.Itoa(os.Getpid()))
+		t.Setenv(listenFDsEnv, "0")
+		_, err := inheritedListener()
+		assert.EqualError(t, err, `invalid LISTEN_FDS: "0"`)
+	})
+}
+
+func TestListen_ReusePort(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT semantics tested on Linux only")
+	}
+	first, err := listen(context.Background(), "127.0.0.1:0", true)
+	require.NoError(t, err)
+	defer first.Close()
+
+	second, err := listen(context.Background(), first.Addr().String(), true)
+	require.NoError(t, err)
+	second.Close()
+
+	_, err = listen(context.Background(), first.Addr().String(), false)
+	assert.Error(t, err)
+}
+
+func TestWaitHandoverReady(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer r.Close()
+		_, err = w.Write([]byte{1})
+		require.NoError(t, err)
+		w.Close()
+		assert.NoError(t, waitHandoverReady(r, time.Second))
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer r.Close()
+		w.Close()
+		assert.EqualError(t, waitHandoverReady(r, time.Second), "new process exited before taking over")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer r.Close()
+		defer w.Close()
+		assert.EqualError(t, waitHandoverReady(r, 50*time.Millisecond), "new process did not take over within 50ms")
+	})
+}
+
+func TestHandover_NotListening(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{MCP: config.MCPConfig{Mode: "both"}}
+	assert.EqualError(t, s.Handover(), "handover needs mcp.mode http")
+
+	s.config.MCP.Mode = "http"
+	assert.EqualError(t, s.Handover(), "HTTP bridge is not listening")
+}
+
+func TestDrainHTTPServer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		delay    time.Duration
+		timeout  time.Duration
+		finished bool
+	}{
+		{name: "requests finish", delay: 50 * time.Millisecond, timeout: 5 * time.Second, finished: true},
+		{name: "drain timeout", delay: 5 * time.Second, timeout: 50 * time.Millisecond, finished: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			started := make(chan struct{})
+			s := newTestServer()
+			s.config = &config.Config{MCP: config.MCPConfig{HTTP: config.HTTPConfig{DrainTimeout: tc.timeout}}}
+			s.httpServer = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tc.delay):
+				case <-r.Context().Done():
+				}
+				fmt.Fprint(w, "done")
+			})}
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			go s.httpServer.Serve(l)
+
+			errs := make(chan error, 1)
+			go func() {
+				resp, err := http.Get("http://" + l.Addr().String())
+				if err == nil {
+					resp.Body.Close()
+				}
+				errs <- err
+			}()
+			<-started
+
+			begin := time.Now()
+			s.drainHTTPServer()
+			assert.Less(t, time.Since(begin), 2*time.Second)
+			if tc.finished {
+				assert.NoError(t, <-errs)
+			} else {
+				assert.Error(t, <-errs)
+			}
+		})
+	}
+}
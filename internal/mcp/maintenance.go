@@ -0,0 +1,26 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// warnMaintenance adds a warning to the results of data tools called while
+// the Portal64 API is under maintenance, as their data is then served from
+// the response cache only and may be stale
+func (s *Server) warnMaintenance(name string, handler ToolHandler) ToolHandler {
+	if !dataTool(name) {
+		return handler
+	}
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		result, err := handler(ctx, args)
+		if s.apiClient == nil {
+			return result, err
+		}
+		if maintenance := s.apiClient.Maintenance(); maintenance.Active {
+			addWarning(ctx, fmt.Sprintf("The Portal64 API is under maintenance (%s) until %s; data is served from the cache only and may be stale", maintenance.Reason, maintenance.Until.Format(time.RFC3339)))
+		}
+		return result, err
+	}
+}
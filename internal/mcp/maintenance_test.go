@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestWarnMaintenance(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Wartungsarbeiten bis 04:00 Uhr", http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.apiClient.ConfigureMaintenance(api.MaintenanceOptions{Pattern: regexp.MustCompile(`(?i)wartung`), Hold: time.Hour})
+
+	ctx, collector := withWarnings(s.ctx)
+	result, err := s.warnMaintenance("get_player_profile", s.handleGetPlayerProfile)(ctx, map[string]interface{}{"player_id": "C0101-999"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError, "uncached data is not available")
+	require.Len(t, collector.warnings, 1)
+	assert.Contains(t, collector.warnings[0], "The Portal64 API is under maintenance (detected) until")
+	assert.Contains(t, collector.warnings[0], "may be stale")
+
+	ctx, collector = withWarnings(s.ctx)
+	_, err = s.warnMaintenance("calculate_tournament_dwz", s.handleCalculateTournamentDWZ)(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Empty(t, collector.warnings, "tools without API calls are not affected")
+}
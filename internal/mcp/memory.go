@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"sort"
+
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+// registerMemoryStores registers the caches and snapshots of the server
+// with the memory budget. Stores of profile servers are named after the
+// profile.
+func (s *Server) registerMemoryStores() {
+	if s.memory == nil {
+		return
+	}
+	prefix := ""
+	if s.profile != "" {
+		prefix = s.profile + "/"
+	}
+	s.memory.Register(prefix+"rosters", &s.rosters)
+	s.memory.Register(prefix+"series", &s.series)
+	s.memory.Register(prefix+"distributions", &s.distributions)
+	s.memory.Register(prefix+"addresses", &s.addresses)
+	s.memory.Register(prefix+"reactivation", &s.reactivation)
+	s.memory.Register(prefix+"activity", &s.activity)
+	s.memory.Register(prefix+"sync_tokens", &s.syncTokens)
+	if s.apiClient != nil && s.apiClient.ResponseCache() != nil {
+		s.memory.Register(prefix+"responses", s.apiClient.ResponseCache())
+	}
+	if store, ok := s.geocoder.(memory.Store); ok && s.profile == "" {
+		s.memory.Register("geocoder", store)
+	}
+}
+
+// MemoryUsage implements memory.Store
+func (c *rosterCache) MemoryUsage() memory.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	usage := memory.Usage{Entries: len(c.entries)}
+	for _, entry := range c.entries {
+		usage.Bytes += entry.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the least recently used rosters
+func (c *rosterCache) Evict(bytes int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clubIDs := make([]string, 0, len(c.entries))
+	for clubID := range c.entries {
+		clubIDs = append(clubIDs, clubID)
+	}
+	sort.Slice(clubIDs, func(i, j int) bool { return c.entries[clubIDs[i]].used.Before(c.entries[clubIDs[j]].used) })
+
+	var freed int64
+	for _, clubID := range clubIDs {
+		if freed >= bytes {
+			break
+		}
+		freed += c.entries[clubID].size
+		delete(c.entries, clubID)
+	}
+	return freed
+}
+
+// MemoryUsage implements memory.Store
+func (c *seriesCache) MemoryUsage() memory.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	usage := memory.Usage{Entries: len(c.entries)}
+	for _, entry := range c.entries {
+		usage.Bytes += entry.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the series cached longest ago
+func (c *seriesCache) Evict(bytes int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return c.entries[keys[i]].expires.Before(c.entries[keys[j]].expires) })
+
+	var freed int64
+	for _, key := range keys {
+		if freed >= bytes {
+			break
+		}
+		freed += c.entries[key].size
+		delete(c.entries, key)
+	}
+	return freed
+}
+
+// MemoryUsage implements memory.Store
+func (c *reactivationCache) MemoryUsage() memory.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	usage := memory.Usage{Entries: len(c.entries)}
+	for _, entry := range c.entries {
+		usage.Bytes += entry.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the clubs cached longest ago
+func (c *reactivationCache) Evict(bytes int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clubIDs := make([]string, 0, len(c.entries))
+	for clubID := range c.entries {
+		clubIDs = append(clubIDs, clubID)
+	}
+	sort.Slice(clubIDs, func(i, j int) bool { return c.entries[clubIDs[i]].expires.Before(c.entries[clubIDs[j]].expires) })
+
+	var freed int64
+	for _, clubID := range clubIDs {
+		if freed >= bytes {
+			break
+		}
+		freed += c.entries[clubID].size
+		delete(c.entries, clubID)
+	}
+	return freed
+}
+
+// MemoryUsage implements memory.Store
+func (c *activityCache) MemoryUsage() memory.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	usage := memory.Usage{Entries: len(c.entries)}
+	for _, entry := range c.entries {
+		usage.Bytes += entry.size
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the months cached longest ago
+func (c *activityCache) Evict(bytes int64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	months := make([]string, 0, len(c.entries))
+	for month := range c.entries {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool { return c.entries[months[i]].expires.Before(c.entries[months[j]].expires) })
+
+	var freed int64
+	for _, month := range months {
+		if freed >= bytes {
+			break
+		}
+		freed += c.entries[month].size
+		delete(c.entries, month)
+	}
+	return freed
+}
+
+// distributionSize estimates the memory held by a distribution snapshot
+func distributionSize(d *RatingDistribution) int64 {
+	return int64(len(d.ratings))*8 + memory.EstimateSize(d)
+}
+
+// MemoryUsage implements memory.Store
+func (d *distributionSnapshots) MemoryUsage() memory.Usage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	usage := memory.Usage{Entries: len(d.snapshots)}
+	for _, snapshot := range d.snapshots {
+		usage.Bytes += distributionSize(snapshot)
+	}
+	return usage
+}
+
+// Evict implements memory.Store, removing the oldest snapshots. The
+// national snapshot is only rebuilt by the background job, so it goes last.
+func (d *distributionSnapshots) Evict(bytes int64) int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := make([]string, 0, len(d.snapshots))
+	for key := range d.snapshots {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == scopeNational) != (keys[j] == scopeNational) {
+			return keys[j] == scopeNational
+		}
+		return d.snapshots[keys[i]].ComputedAt.Before(d.snapshots[keys[j]].ComputedAt)
+	})
+
+	var freed int64
+	for _, key := range keys {
+		if freed >= bytes {
+			break
+		}
+		freed += distributionSize(d.snapshots[key])
+		delete(d.snapshots, key)
+	}
+	return freed
+}
+
+// MemoryUsage implements memory.Store
+func (b *addressBook) MemoryUsage() memory.Usage {
+	usage := memory.Usage{Bytes: b.size.Load()}
+	if usage.Bytes > 0 {
+		usage.Entries = 1
+	}
+	return usage
+}
+
+// Evict implements memory.Store, dropping the address data unless it is
+// being loaded or a standby
+func (b *addressBook) Evict(bytes int64) int64 {
+	if !b.loading.TryLock() {
+		return 0
+	}
+	defer b.loading.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.standby {
+		return 0
+	}
+	b.regions, b.addresses, b.officials = nil, nil, nil
+	return b.size.Swap(0)
+}
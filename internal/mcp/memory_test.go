@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+func TestRosterCache_Evict(t *testing.T) {
+	var cache rosterCache
+	now := time.Now()
+	players := []api.PlayerResponse{{ID: "C0327-1", Name: "Müller"}}
+	cache.put("C0301", players, false, now, 0)
+	cache.put("C0302", players, false, now.Add(time.Minute), 0)
+	cache.get("C0301", now.Add(2*time.Minute))
+
+	usage := cache.MemoryUsage()
+	assert.Equal(t, 2, usage.Entries)
+	assert.Equal(t, 2*memory.EstimateSize(players), usage.Bytes)
+
+	assert.Equal(t, memory.EstimateSize(players), cache.Evict(1))
+	_, _, ok := cache.get("C0301", now)
+	assert.True(t, ok, "recently used roster is kept")
+}
+
+func TestDistributionSnapshots_Evict(t *testing.T) {
+	var snapshots distributionSnapshots
+	now := time.Now()
+	national := newRatingDistribution(scopeNational, "", []api.PlayerResponse{{ID: "a", CurrentDWZ: 1500}}, now.Add(-time.Hour))
+	older := newRatingDistribution(scopeRegion, "Württemberg", []api.PlayerResponse{{ID: "b", CurrentDWZ: 1600}}, now.Add(-time.Minute))
+	newer := newRatingDistribution(scopeRegion, "Baden", []api.PlayerResponse{{ID: "c", CurrentDWZ: 1700}}, now)
+	snapshots.put(distributionKey(scopeNational, ""), national)
+	snapshots.put(distributionKey(scopeRegion, "Württemberg"), older)
+	snapshots.put(distributionKey(scopeRegion, "Baden"), newer)
+
+	snapshots.Evict(1)
+	assert.Nil(t, snapshots.get(distributionKey(scopeRegion, "Württemberg")))
+	snapshots.Evict(1)
+	assert.Nil(t, snapshots.get(distributionKey(scopeRegion, "Baden")))
+	assert.NotNil(t, snapshots.get(distributionKey(scopeNational, "")), "national snapshot goes last")
+	assert.Equal(t, distributionSize(national), snapshots.Evict(1))
+	assert.Zero(t, snapshots.MemoryUsage().Entries)
+}
+
+func TestRegisterMemoryStores(t *testing.T) {
+	s := newTestServer()
+	s.memory = memory.NewBudget(1, logrus.New())
+	s.registerMemoryStores()
+	require.NoError(t, s.AddProfile("test", s.apiClient))
+
+	s.rosters.put("C0327", []api.PlayerResponse{{ID: "C0327-1"}}, false, time.Now(), 0)
+	s.series.put("ulm open", []TournamentSeries{{Key: "ulm open"}})
+	s.addresses.size.Store(1000)
+
+	var names []string
+	for _, store := range s.memory.Stats().Stores {
+		names = append(names, store.Name)
+	}
+	assert.Equal(t, []string{"rosters", "series", "distributions", "addresses", "reactivation", "activity", "sync_tokens", "test/rosters", "test/series", "test/distributions", "test/addresses", "test/reactivation", "test/activity", "test/sync_tokens"}, names)
+
+	assert.Positive(t, s.memory.Enforce())
+	assert.Zero(t, s.memory.Stats().UsedBytes)
+}
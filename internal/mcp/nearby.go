@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/geo"
+)
+
+// maxGeocodedCities bounds the number of club cities geocoded per search
+const maxGeocodedCities = 100
+
+// NearbyClub is a club with its distance from the searched location
+type NearbyClub struct {
+	api.ClubResponse
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearbyClubsResult is the result of a nearest-club search
+type NearbyClubsResult struct {
+	Origin           geo.Location `json:"origin"`
+	RadiusKm         float64      `json:"radius_km"`
+	Clubs            []NearbyClub `json:"clubs"`
+	ClubsSearched    int          `json:"clubs_searched"`
+	UnresolvedCities []string     `json:"unresolved_cities,omitempty"`
+}
+
+// findClubsNear ranks clubs by their distance from a place. Club positions
+// are approximated by their city, since the API provides no coordinates.
+func (s *Server) findClubsNear(ctx context.Context, location string, radiusKm float64, limit int, region string) (*NearbyClubsResult, error) {
+	if s.geocoder == nil {
+		return nil, fmt.Errorf("geocoding is disabled")
+	}
+
+	origin, err := s.geocoder.Geocode(ctx, location)
+	if err != nil {
+		if errors.Is(err, geo.ErrNotFound) {
+			return nil, fmt.Errorf("location %q not found", location)
+		}
+		return nil, err
+	}
+
+	params := api.SearchParams{}
+	if region != "" {
+		params.FilterBy = "region"
+		params.FilterValue = region
+	}
+	clubs, _, err := s.fetchAllClubs(ctx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an explicit region, only consider clubs in the origin's state
+	if region == "" && origin.State != "" {
+		inState := clubs[:0:0]
+		for _, c := range clubs {
+			if c.State == "" || strings.EqualFold(c.State, origin.State) {
+				inState = append(inState, c)
+			}
+		}
+		clubs = inState
+	}
+
+	result := &NearbyClubsResult{
+		Origin:        *origin,
+		RadiusKm:      radiusKm,
+		Clubs:         []NearbyClub{},
+		ClubsSearched: len(clubs),
+	}
+
+	// Geocode each distinct city once
+	cities := make(map[string]*geo.Location)
+	var order []string
+	for _, c := range clubs {
+		key := cityKey(c)
+		if _, seen := cities[key]; !seen && key != "" {
+			cities[key] = nil
+			order = append(order, key)
+		}
+	}
+
+	for i, key := range order {
+		if i >= maxGeocodedCities {
+			result.UnresolvedCities = append(result.UnresolvedCities, order[i:]...)
+			break
+		}
+
+		loc, err := s.geocoder.Geocode(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.UnresolvedCities = append(result.UnresolvedCities, key)
+			continue
+		}
+		cities[key] = loc
+		reportProgress(ctx, i+1, len(order), fmt.Sprintf("Geocoded %s", key), nil)
+	}
+
+	for _, c := range clubs {
+		loc := cities[cityKey(c)]
+		if loc == nil {
+			continue
+		}
+		distance := geo.Distance(*origin, *loc)
+		if distance <= radiusKm {
+			result.Clubs = append(result.Clubs, NearbyClub{
+				ClubResponse: c,
+				DistanceKm:   math.Round(distance*10) / 10,
+			})
+		}
+	}
+
+	sort.SliceStable(result.Clubs, func(i, j int) bool {
+		return result.Clubs[i].DistanceKm < result.Clubs[j].DistanceKm
+	})
+	if limit > 0 && len(result.Clubs) > limit {
+		result.Clubs = result.Clubs[:limit]
+	}
+
+	return result, nil
+}
+
+// cityKey returns the geocoding query for a club's location
+func cityKey(c api.ClubResponse) string {
+	if c.City == "" {
+		return ""
+	}
+	if c.State != "" {
+		return c.City + ", " + c.State
+	}
+	return c.City
+}
+
+// handleFindClubsNear handles nearest-club search requests
+func (s *Server) handleFindClubsNear(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	location, ok := args["location"].(string)
+	if !ok || strings.TrimSpace(location) == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: location is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	radius := 25.0
+	if r, ok := args["radius_km"].(float64); ok && r > 0 {
+		radius = r
+	}
+	limit := 10
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	region, _ := args["region"].(string)
+
+	result, err := s.findClubsNear(ctx, location, radius, limit, region)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error finding clubs near %s: %v", location, err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
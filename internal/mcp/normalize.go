@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// umlautRestorer spells the ASCII forms of German umlauts as umlauts. ß is
+// left out, since "ss" is far more often meant literally.
+var umlautRestorer = strings.NewReplacer("ae", "ä", "oe", "ö", "ue", "ü")
+
+// normalizeQuery folds case and umlauts of a search query and collapses
+// whitespace, so that "MÜLLER  Hans" searches for "mueller hans"
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(umlautReplacer.Replace(strings.ToLower(query))), " ")
+}
+
+// alternativeSpelling returns the spelling of a query tried when its
+// normalized form finds nothing: the query with its umlauts if it had any,
+// else the normalized query with umlauts restored. It returns "" if there is
+// no other spelling.
+func alternativeSpelling(query, normalized string) string {
+	if withUmlauts := strings.Join(strings.Fields(strings.ToLower(query)), " "); withUmlauts != normalized {
+		return withUmlauts
+	}
+	if restored := umlautRestorer.Replace(normalized); restored != normalized {
+		return restored
+	}
+	return ""
+}
+
+// searchHitCount returns the number of hits of a search response
+func searchHitCount(result *api.SearchResponse) int {
+	switch data := result.Data.(type) {
+	case []api.PlayerResponse:
+		return len(data)
+	case []api.ClubResponse:
+		return len(data)
+	case []api.TournamentResponse:
+		return len(data)
+	case []RankedPlayer:
+		return len(data)
+	case []RankedClub:
+		return len(data)
+	case []RankedTournament:
+		return len(data)
+	default:
+		return result.Pagination.Count
+	}
+}
+
+// searchNormalized runs a search with the normalized query and, if nothing
+// is found, once more with the alternative spelling. It returns the response
+// together with the parameters of the search that produced it.
+func searchNormalized(ctx context.Context, params api.SearchParams, search func(api.SearchParams) (*api.SearchResponse, error)) (*api.SearchResponse, api.SearchParams, error) {
+	query := params.Query
+	params.Query = normalizeQuery(query)
+
+	result, err := search(params)
+	if err != nil || params.Query == "" || searchHitCount(result) > 0 {
+		return result, params, err
+	}
+
+	alternative := alternativeSpelling(query, params.Query)
+	if alternative == "" {
+		return result, params, nil
+	}
+	retry := params
+	retry.Query = alternative
+	retryResult, err := search(retry)
+	if err != nil || searchHitCount(retryResult) == 0 {
+		// The first search succeeded, so its empty result stands
+		return result, params, nil
+	}
+	addWarning(ctx, fmt.Sprintf("no results for %q, showing results for %q", params.Query, alternative))
+	return retryResult, retry, nil
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, "mueller hans", normalizeQuery("  MÜLLER   Hans "))
+	assert.Equal(t, "grosse", normalizeQuery("Große"))
+	assert.Equal(t, "", normalizeQuery("   "))
+}
+
+func TestAlternativeSpelling(t *testing.T) {
+	assert.Equal(t, "müller hans", alternativeSpelling("MÜLLER  Hans", "mueller hans"))
+	assert.Equal(t, "müller", alternativeSpelling("Mueller", "mueller"))
+	assert.Equal(t, "jörg", alternativeSpelling("Joerg", "joerg"))
+	assert.Equal(t, "", alternativeSpelling("Schmidt", "schmidt"))
+	assert.Equal(t, "", alternativeSpelling("Strasser", "strasser"), "ss is not respelled")
+}
+
+// newSearchUpstream serves player searches, finding players only for the
+// given query, and records the queries received
+func newSearchUpstream(t *testing.T, match string) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var queries []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+		if query == match {
+			w.Write([]byte(`{"data": [{"id": "C0327-13", "name": "Müller", "firstname": "Hans"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestHandleSearchPlayers_Normalization(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		match   string
+		sent    []string
+		found   bool
+		warning bool
+	}{
+		{"normalized query found", "MÜLLER Hans", "mueller hans", []string{"mueller hans"}, true, false},
+		{"umlaut spelling fallback", "Müller", "müller", []string{"mueller", "müller"}, true, true},
+		{"restored umlaut fallback", "Mueller", "müller", []string{"mueller", "müller"}, true, true},
+		{"no alternative spelling", "Schmidt", "müller", []string{"schmidt", "schm"}, false, false},
+		{"fallback finds nothing", "Mueller", "meier", []string{"mueller", "müller", "muel", "mül"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, queries := newSearchUpstream(t, tt.match)
+			s := newTestServer()
+			s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+			ctx, collector := withWarnings(context.Background())
+			result, err := s.handleSearchPlayers(ctx, map[string]interface{}{"query": tt.query})
+			require.NoError(t, err)
+			require.False(t, result.IsError, result.Content[0].Text)
+
+			var response struct {
+				Data []RankedPlayer `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &response))
+			assert.Equal(t, tt.found, len(response.Data) == 1)
+			assert.Equal(t, tt.sent, queries())
+			assert.Equal(t, tt.warning, len(collector.warnings) == 1, collector.warnings)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/features"
+)
+
+// roleSynonyms maps English role terms to the German terms used in the
+// address data, so "youth coordinator" finds a "Jugendwart"
+var roleSynonyms = map[string][]string{
+	"youth":      {"jugend"},
+	"president":  {"präsident", "vorsitzende", "vorsitzender"},
+	"chairman":   {"vorsitzende", "vorsitzender"},
+	"vice":       {"stellv", "vize"},
+	"treasurer":  {"kassenwart", "schatzmeister", "finanz"},
+	"secretary":  {"schriftführer", "geschäftsführer", "geschäftsstelle"},
+	"tournament": {"turnier", "spielleiter"},
+	"referee":    {"schiedsrichter"},
+	"arbiter":    {"schiedsrichter"},
+	"rating":     {"wertung", "dwz"},
+	"women":      {"frauen"},
+	"senior":     {"senioren"},
+	"press":      {"presse", "öffentlichkeit"},
+	"training":   {"ausbildung", "lehr", "trainer"},
+	"coach":      {"trainer"},
+	"school":     {"schulschach", "schule"},
+}
+
+// Official is an address book entry together with its region
+type Official struct {
+	api.RegionAddressResponse
+	RegionName string `json:"region_name,omitempty"`
+}
+
+// officialMatchScore returns how many query terms match an official, or 0
+// if any term does not match
+func officialMatchScore(o Official, terms []string) int {
+	fields := strings.ToLower(strings.Join([]string{
+		o.Name, o.Position, o.Type, o.Email, o.City, o.Region, o.RegionName,
+	}, " "))
+
+	score := 0
+	for _, term := range terms {
+		if !matchesTerm(fields, term) {
+			return 0
+		}
+		score++
+		// Prefer matches in the name
+		if strings.Contains(strings.ToLower(o.Name), term) {
+			score++
+		}
+	}
+	return score
+}
+
+// matchesTerm reports whether text contains a term or one of its synonyms
+func matchesTerm(text, term string) bool {
+	if strings.Contains(text, term) {
+		return true
+	}
+	for _, synonym := range roleSynonyms[term] {
+		if strings.Contains(text, synonym) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRegion reports whether an official belongs to a region given by code or name
+func matchesRegion(o Official, region string) bool {
+	region = strings.ToLower(region)
+	return strings.EqualFold(o.Region, region) || strings.Contains(strings.ToLower(o.RegionName), region)
+}
+
+// searchOfficials filters and ranks officials by a free-text query
+func searchOfficials(officials []Official, query, role, region string, limit int) []Official {
+	terms := strings.Fields(strings.ToLower(query))
+	roleTerms := strings.Fields(strings.ToLower(role))
+
+	type scored struct {
+		official Official
+		score    int
+	}
+	var matches []scored
+	for _, o := range officials {
+		if region != "" && !matchesRegion(o, region) {
+			continue
+		}
+		if len(roleTerms) > 0 {
+			roleText := strings.ToLower(o.Position + " " + o.Type)
+			matched := true
+			for _, term := range roleTerms {
+				if !matchesTerm(roleText, term) {
+					matched = false
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		score := 1
+		if len(terms) > 0 {
+			score = officialMatchScore(o, terms)
+		}
+		if score > 0 {
+			matches = append(matches, scored{o, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].official.Name < matches[j].official.Name
+	})
+
+	result := make([]Official, 0, len(matches))
+	for i, m := range matches {
+		if limit > 0 && i >= limit {
+			break
+		}
+		result = append(result, m.official)
+	}
+	return result
+}
+
+// handleSearchOfficials handles address book search requests
+func (s *Server) handleSearchOfficials(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	query, _ := args["query"].(string)
+	role, _ := args["role"].(string)
+	region, _ := args["region"].(string)
+	if strings.TrimSpace(query) == "" && strings.TrimSpace(role) == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: query or role is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	limit := 20
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	officials, err := s.loadOfficials(ctx)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error loading address data: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	matches := searchOfficials(officials, query, role, region, limit)
+	for i := range matches {
+		matches[i].RegionAddressResponse = s.redactContact(matches[i].RegionAddressResponse)
+	}
+	result := map[string]interface{}{
+		"officials": matches,
+		"count":     len(matches),
+		"searched":  len(officials),
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// clubRoles are the roles of a club contact, with the terms that identify
+// them in the type and position of an address record
+var clubRoles = []struct {
+	role    string
+	name    func(*api.ClubContact) string
+	terms   []string
+	exclude string
+}{
+	{"president", func(c *api.ClubContact) string { return c.President }, []string{"president"}, "vice"},
+	{"vice_president", func(c *api.ClubContact) string { return c.VicePresident }, []string{"vice", "president"}, ""},
+	{"secretary", func(c *api.ClubContact) string { return c.Secretary }, []string{"secretary"}, ""},
+	{"treasurer", func(c *api.ClubContact) string { return c.Treasurer }, []string{"treasurer"}, ""},
+	{"coach", func(c *api.ClubContact) string { return c.Coach }, []string{"coach"}, ""},
+}
+
+// ClubOfficial is a role of a club contact completed with the matching
+// address record
+type ClubOfficial struct {
+	Role string `json:"role"`
+	Name string `json:"name"`
+	// MatchedBy is "name" or "email" if an address record was found
+	MatchedBy string    `json:"matched_by,omitempty"`
+	Record    *Official `json:"address_record,omitempty"`
+}
+
+// ClubOfficials is the result of get_club_officials
+type ClubOfficials struct {
+	ClubID    string         `json:"club_id"`
+	ClubName  string         `json:"club_name"`
+	Email     string         `json:"email,omitempty"`
+	Phone     string         `json:"phone,omitempty"`
+	Website   string         `json:"website,omitempty"`
+	Address   string         `json:"address,omitempty"`
+	Officials []ClubOfficial `json:"officials"`
+	// Missing are the roles without a name in the club contact and without
+	// an address record
+	Missing []string `json:"missing"`
+}
+
+// sameName reports whether two names consist of the same words, ignoring
+// case, umlaut spelling, punctuation and order ("Müller, Hans" and
+// "Hans Mueller")
+func sameName(a, b string) bool {
+	wordsA, wordsB := matchTokens(a), matchTokens(b)
+	if len(wordsA) == 0 || len(wordsA) != len(wordsB) {
+		return false
+	}
+	sort.Strings(wordsA)
+	sort.Strings(wordsB)
+	for i := range wordsA {
+		if wordsA[i] != wordsB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRole reports whether an address record holds a club role
+func hasRole(o Official, terms []string, exclude string) bool {
+	text := strings.ToLower(o.Type + " " + o.Position)
+	if exclude != "" && matchesTerm(text, exclude) {
+		return false
+	}
+	for _, term := range terms {
+		if !matchesTerm(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeClubOfficials completes the roles of a club contact with address
+// records. A role is matched by the name of the record, preferring records
+// with the club's email address, or else by a record of the same role with
+// the club's email address; the latter also fills roles the contact has no
+// name for.
+func mergeClubOfficials(contact *api.ClubContact, officials []Official) ([]ClubOfficial, []string) {
+	result := []ClubOfficial{}
+	missing := []string{}
+	clubEmail := strings.TrimSpace(contact.Email)
+	used := make(map[int]bool)
+
+	for _, r := range clubRoles {
+		official := ClubOfficial{Role: r.role, Name: strings.TrimSpace(r.name(contact))}
+		match := -1
+		if official.Name != "" {
+			for i, o := range officials {
+				if used[i] || !sameName(official.Name, o.Name) {
+					continue
+				}
+				if match < 0 || (clubEmail != "" && strings.EqualFold(o.Email, clubEmail)) {
+					match = i
+				}
+			}
+			if match >= 0 {
+				official.MatchedBy = "name"
+			}
+		}
+		if match < 0 && clubEmail != "" {
+			for i, o := range officials {
+				if !used[i] && strings.EqualFold(o.Email, clubEmail) && hasRole(o, r.terms, r.exclude) {
+					match = i
+					official.MatchedBy = "email"
+					break
+				}
+			}
+		}
+
+		if match >= 0 {
+			used[match] = true
+			record := officials[match]
+			official.Record = &record
+			if official.Name == "" {
+				official.Name = record.Name
+			}
+		}
+		if official.Name == "" {
+			missing = append(missing, r.role)
+			continue
+		}
+		result = append(result, official)
+	}
+	return result, missing
+}
+
+// handleGetClubOfficials handles club officials requests, joining the club
+// contact with the address data of the regions
+func (s *Server) handleGetClubOfficials(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, ok := args["club_id"].(string)
+	if !ok || clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	profile, err := s.apiClient.GetClubProfile(ctx, clubID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club profile: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result := ClubOfficials{ClubID: clubID, Officials: []ClubOfficial{}, Missing: []string{}}
+	if profile.Club != nil {
+		result.ClubID, result.ClubName = profile.Club.ID, profile.Club.Name
+	}
+	contact := profile.Contact
+	if contact == nil {
+		addWarning(ctx, "the club profile has no contact data")
+		contact = &api.ClubContact{}
+	}
+	result.Email, result.Website = contact.Email, contact.Website
+	if !s.features.Enabled(features.PrivacyMode) {
+		result.Phone, result.Address = contact.Phone, contact.Address
+	}
+
+	officials, err := s.loadOfficials(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		addWarning(ctx, fmt.Sprintf("regional address data could not be loaded, officials are listed from the club contact only: %v", err))
+		officials = nil
+	}
+
+	result.Officials, result.Missing = mergeClubOfficials(contact, officials)
+	for _, official := range result.Officials {
+		if official.Record != nil {
+			official.Record.RegionAddressResponse = s.redactContact(official.Record.RegionAddressResponse)
+		}
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
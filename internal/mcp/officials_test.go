@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/features"
+)
+
+func TestSearchOfficials(t *testing.T) {
+	officials := []Official{
+		{RegionAddressResponse: api.RegionAddressResponse{Name: "Eva Berger", Position: "Jugendwart", Region: "BW", Email: "jugend@svw.info"}, RegionName: "Baden-Württemberg"},
+		{RegionAddressResponse: api.RegionAddressResponse{Name: "Karl Huber", Position: "Präsident", Region: "BY"}, RegionName: "Bayern"},
+		{RegionAddressResponse: api.RegionAddressResponse{Name: "Jana Jugendlich", Position: "Kassenwart", Region: "BW"}, RegionName: "Baden-Württemberg"},
+	}
+
+	t.Run("role synonym and region name", func(t *testing.T) {
+		result := searchOfficials(officials, "", "youth", "Baden-Württemberg", 10)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Eva Berger", result[0].Name)
+	})
+
+	t.Run("free text ranks name matches first", func(t *testing.T) {
+		result := searchOfficials(officials, "jugend", "", "", 10)
+		require.Len(t, result, 2)
+		assert.Equal(t, "Jana Jugendlich", result[0].Name)
+	})
+
+	t.Run("email fragment and region code", func(t *testing.T) {
+		result := searchOfficials(officials, "svw.info", "", "bw", 10)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Eva Berger", result[0].Name)
+	})
+
+	t.Run("all terms must match", func(t *testing.T) {
+		assert.Empty(t, searchOfficials(officials, "president bw", "", "", 10))
+		assert.Len(t, searchOfficials(officials, "president bayern", "", "", 10), 1)
+	})
+}
+
+func TestMergeClubOfficials(t *testing.T) {
+	officials := []Official{
+		{RegionAddressResponse: api.RegionAddressResponse{ID: "a1", Name: "Müller, Hans", Position: "Präsident", Email: "hans@example.org", Phone: "0711 1"}},
+		{RegionAddressResponse: api.RegionAddressResponse{ID: "a2", Name: "Hans Mueller", Position: "Kassenwart", Email: "info@sf-ulm.de"}},
+		{RegionAddressResponse: api.RegionAddressResponse{ID: "a3", Name: "Eva Berger", Position: "Stellv. Vorsitzende", Email: "info@sf-ulm.de"}},
+		{RegionAddressResponse: api.RegionAddressResponse{ID: "a4", Name: "Karl Huber", Type: "secretary", Email: "karl@example.org"}},
+	}
+	contact := &api.ClubContact{
+		President: "Hans Müller",
+		Treasurer: "Jana Kurz",
+		Coach:     "Otto Lang",
+		Email:     "INFO@sf-ulm.de",
+	}
+
+	result, missing := mergeClubOfficials(contact, officials)
+	require.Len(t, result, 4)
+
+	// Both records match the name; the one with the club's email is preferred
+	assert.Equal(t, "president", result[0].Role)
+	assert.Equal(t, "name", result[0].MatchedBy)
+	assert.Equal(t, "a2", result[0].Record.ID)
+
+	// The vice president has no name in the contact and is found by email and role
+	assert.Equal(t, "vice_president", result[1].Role)
+	assert.Equal(t, "Eva Berger", result[1].Name)
+	assert.Equal(t, "email", result[1].MatchedBy)
+
+	// No record of the club, the contact's name is kept
+	assert.Equal(t, ClubOfficial{Role: "treasurer", Name: "Jana Kurz"}, result[2])
+	assert.Equal(t, ClubOfficial{Role: "coach", Name: "Otto Lang"}, result[3])
+
+	// The secretary record belongs to another club
+	assert.Equal(t, []string{"secretary"}, missing)
+
+	result, missing = mergeClubOfficials(&api.ClubContact{}, nil)
+	assert.Empty(t, result)
+	assert.Len(t, missing, len(clubRoles))
+}
+
+func TestGetClubOfficials(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clubs/C0327/profile":
+			w.Write([]byte(`{"club":{"id":"C0327","name":"SF Ulm"},"contact":{"president":"Anna Alt","email":"info@sf-ulm.de","phone":"0731 1","address":"Hauptstr. 1"}}`))
+		case "/api/v1/addresses/regions":
+			w.Write([]byte(`[{"code":"C","name":"Württemberg"}]`))
+		case "/api/v1/addresses/C":
+			w.Write([]byte(`[{"id":"a1","name":"Alt, Anna","position":"Vorsitzende","email":"anna@sf-ulm.de","phone":"0731 2","postal_code":"89073"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	result, err := s.handleGetClubOfficials(s.ctx, map[string]interface{}{"club_id": "C0327"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var officials ClubOfficials
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &officials))
+	assert.Equal(t, "SF Ulm", officials.ClubName)
+	assert.Equal(t, "0731 1", officials.Phone)
+	require.Len(t, officials.Officials, 1)
+	assert.Equal(t, "Anna Alt", officials.Officials[0].Name)
+	require.NotNil(t, officials.Officials[0].Record)
+	assert.Equal(t, "anna@sf-ulm.de", officials.Officials[0].Record.Email)
+	assert.Equal(t, "Württemberg", officials.Officials[0].Record.RegionName)
+	assert.Len(t, officials.Missing, 4)
+
+	// Contact details are left out in privacy mode
+	s.features = features.New(map[string]bool{features.PrivacyMode: true})
+	result, err = s.handleGetClubOfficials(s.ctx, map[string]interface{}{"club_id": "C0327"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Content[0].Text, "0731")
+	assert.NotContains(t, result.Content[0].Text, "89073")
+	assert.Contains(t, result.Content[0].Text, "anna@sf-ulm.de")
+}
+
+func TestGetClubOfficials_WithoutAddressData(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/clubs/C0327/profile" {
+			w.Write([]byte(`{"club":{"id":"C0327","name":"SF Ulm"}}`))
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.logger.SetOutput(io.Discard)
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, collector := withWarnings(s.ctx)
+	result, err := s.handleGetClubOfficials(ctx, map[string]interface{}{"club_id": "C0327"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	assert.Contains(t, result.Content[0].Text, `"officials": []`)
+	require.Len(t, collector.warnings, 2)
+	assert.Equal(t, "the club profile has no contact data", collector.warnings[0])
+	assert.Contains(t, collector.warnings[1], "regional address data could not be loaded")
+}
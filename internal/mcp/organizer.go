@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// defaultOrganizerLimit is the number of tournaments listed by default
+const defaultOrganizerLimit = 100
+
+// OrganizedTournament is a tournament listed by get_organizer_tournaments
+type OrganizedTournament struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	StartDate        string `json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate          string `json:"end_date,omitempty"`
+	City             string `json:"city,omitempty"`
+	Organizer        string `json:"organizer"`
+	Participants     int    `json:"participants"`
+	Status           string `json:"status,omitempty"`
+	EvaluationStatus string `json:"evaluation_status,omitempty"`
+}
+
+// OrganizerTournaments is the result of get_organizer_tournaments
+type OrganizerTournaments struct {
+	ClubID       string                `json:"club_id,omitempty"`
+	Organizer    string                `json:"organizer,omitempty"` // Organizer name searched, the club name for club_id
+	From         string                `json:"from"`
+	To           string                `json:"to"`
+	Tournaments  []OrganizedTournament `json:"tournaments"`
+	Count        int                   `json:"count"`
+	Participants int                   `json:"participants"`
+	// Statuses counts the tournaments per status, e.g. "finished"
+	Statuses map[string]int `json:"statuses"`
+	// FailedItems are the months whose tournaments failed to load
+	FailedItems []FailedItem `json:"failed_items,omitempty"`
+}
+
+// tournamentOrganizer returns the organizer name of a tournament from
+// either field of the API
+func tournamentOrganizer(t api.TournamentResponse) string {
+	if t.Organization == "" {
+		return t.Organizer
+	}
+	return t.Organization
+}
+
+// matchesOrganizerName reports whether all words of name occur in the
+// organizer of a tournament, ignoring case and umlaut spelling, so "SF Ulm"
+// matches "SF Ulm 1946 e.V."
+func matchesOrganizerName(t api.TournamentResponse, name string) bool {
+	words := matchTokens(name)
+	if len(words) == 0 {
+		return false
+	}
+	organizer := make(map[string]bool)
+	for _, word := range matchTokens(t.Organization + " " + t.Organizer) {
+		organizer[word] = true
+	}
+	for _, word := range words {
+		if !organizer[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesOrganizerClub reports whether a tournament was organized by a club,
+// by the organizer club ID or, for tournaments without one, by the club name
+func matchesOrganizerClub(t api.TournamentResponse, clubID, clubName string) bool {
+	if t.OrganizerClubID != "" {
+		id, err := api.NormalizeClubID(t.OrganizerClubID)
+		return err == nil && id == clubID
+	}
+	return clubName != "" && matchesOrganizerName(t, clubName)
+}
+
+// organizedTournaments keeps the tournaments matching an organizer that
+// start within the months, without duplicates and ordered by start date
+func organizedTournaments(months []dateChunk, tournaments []api.TournamentResponse, matches func(api.TournamentResponse) bool) []OrganizedTournament {
+	from, to := months[0].start, months[len(months)-1].end.AddDate(0, 0, 1)
+	var found []api.TournamentResponse
+	seen := make(map[string]bool)
+	for _, t := range tournaments {
+		start := tournamentStart(t)
+		if start.IsZero() || start.Before(from) || !start.Before(to) || seen[t.ID] || !matches(t) {
+			continue
+		}
+		seen[t.ID] = true
+		found = append(found, t)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return tournamentStart(found[i]).Before(tournamentStart(found[j])) })
+
+	organized := make([]OrganizedTournament, len(found))
+	for i, t := range found {
+		organized[i] = OrganizedTournament{
+			ID:               t.ID,
+			Name:             t.Name,
+			StartDate:        tournamentStart(t).In(portalLocation).Format("2006-01-02"),
+			City:             t.City,
+			Organizer:        tournamentOrganizer(t),
+			Participants:     tournamentParticipants(t),
+			Status:           t.Status,
+			EvaluationStatus: t.EvaluationStatus,
+		}
+		if t.EndDate != nil && !t.EndDate.IsZero() {
+			organized[i].EndDate = t.EndDate.In(portalLocation).Format("2006-01-02")
+		}
+	}
+	return organized
+}
+
+// organizerTournaments lists the tournaments of an organizer, given by club
+// ID or name, starting from the month of start to the month of end
+func (s *Server) organizerTournaments(ctx context.Context, clubID, name string, start, end time.Time) (*OrganizerTournaments, error) {
+	result := &OrganizerTournaments{ClubID: clubID, Organizer: name, Statuses: map[string]int{}}
+	matches := func(t api.TournamentResponse) bool { return matchesOrganizerName(t, name) }
+	if clubID != "" {
+		profile, err := s.apiClient.GetClubProfile(ctx, clubID)
+		switch {
+		case err == nil && profile.Club != nil:
+			result.Organizer = profile.Club.Name
+		case err != nil && api.IsNotFound(err):
+			return nil, err
+		case err != nil:
+			addWarning(ctx, fmt.Sprintf("the club name could not be loaded, tournaments without organizer club ID are missing: %v", err))
+		}
+		matches = func(t api.TournamentResponse) bool { return matchesOrganizerClub(t, clubID, result.Organizer) }
+	}
+
+	months := activityMonths(start, end)
+	tournaments, failed, err := s.searchActivityMonths(ctx, months)
+	if err != nil {
+		return nil, err
+	}
+
+	result.From = months[0].start.Format("2006-01-02")
+	result.To = months[len(months)-1].end.Format("2006-01-02")
+	result.Tournaments = organizedTournaments(months, tournaments, matches)
+	result.Count = len(result.Tournaments)
+	result.FailedItems = failed
+	for _, t := range result.Tournaments {
+		result.Participants += t.Participants
+		status := t.Status
+		if status == "" {
+			status = "unknown"
+		}
+		result.Statuses[status]++
+	}
+	return result, nil
+}
+
+// handleGetOrganizerTournaments handles requests for the tournaments of an
+// organizer
+func (s *Server) handleGetOrganizerTournaments(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, _ := args["club_id"].(string)
+	name, _ := args["organizer"].(string)
+	name = strings.TrimSpace(name)
+	if (clubID == "") == (name == "") {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: either club_id or organizer is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	limit := defaultOrganizerLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	start, end, err := activityRangeArgs(ctx, args, time.Now())
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result, err := s.organizerTournaments(ctx, clubID, name, start, end)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting organizer tournaments: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if len(result.Tournaments) > limit {
+		addWarning(ctx, fmt.Sprintf("%d tournaments match, only the first %d are listed", len(result.Tournaments), limit))
+		result.Tournaments = result.Tournaments[:limit]
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
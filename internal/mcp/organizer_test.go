@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestMatchesOrganizer(t *testing.T) {
+	byID := api.TournamentResponse{OrganizerClubID: "c0327", Organization: "Schachfreunde Ulm"}
+	byName := api.TournamentResponse{Organization: "SF Ulm 1946 e.V."}
+
+	assert.True(t, matchesOrganizerClub(byID, "C0327", ""))
+	assert.False(t, matchesOrganizerClub(byID, "C0505", "Schachfreunde Ulm"))
+	assert.True(t, matchesOrganizerClub(byName, "C0327", "SF Ulm 1946"))
+	assert.False(t, matchesOrganizerClub(byName, "C0327", ""))
+
+	assert.True(t, matchesOrganizerName(byName, "sf ulm"))
+	assert.True(t, matchesOrganizerName(api.TournamentResponse{Organizer: "SK Göppingen"}, "Goeppingen"))
+	assert.False(t, matchesOrganizerName(byName, "Ulmer SF"))
+	assert.False(t, matchesOrganizerName(byName, ""))
+}
+
+func TestGetOrganizerTournaments(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/clubs/C0327/profile":
+			w.Write([]byte(`{"club": {"id": "C0327", "name": "SF Ulm 1946"}}`))
+		case r.URL.Query().Get("start_date") == "2024-01-01":
+			w.Write([]byte(`{"data": [
+				{"id": "T2", "name": "Blitz", "organization": "SF Ulm 1946 e.V.", "start_date": "2024-01-20T00:00:00Z", "participants": 20, "status": "finished"},
+				{"id": "T1", "name": "Open", "organizer_club_id": "C0327", "start_date": "2024-01-05T00:00:00Z", "end_date": "2024-01-07T00:00:00Z", "participants": 60, "status": "finished"},
+				{"id": "T3", "name": "Cup", "organizer_club_id": "C0505", "organization": "SF Ulm 1946 e.V.", "start_date": "2024-01-10T00:00:00Z"}
+			], "meta": {"total": 3}}`))
+		case r.URL.Query().Get("start_date") == "2024-02-01":
+			w.Write([]byte(`{"data": [
+				{"id": "T4", "name": "Rapid", "organization": "SF Ulm 1946", "start_date": "2024-02-03T00:00:00Z", "participant_count": 15, "status": "running"}
+			], "meta": {"total": 1}}`))
+		default:
+			w.Write([]byte(`{"data": [], "meta": {"total": 0}}`))
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	result, err := s.handleGetOrganizerTournaments(s.ctx, map[string]interface{}{"club_id": "C0327", "start_date": "2024-01", "end_date": "2024-02"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var organized OrganizerTournaments
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &organized))
+	assert.Equal(t, "SF Ulm 1946", organized.Organizer)
+	assert.Equal(t, "2024-01-01", organized.From)
+	assert.Equal(t, "2024-02-29", organized.To)
+	require.Equal(t, 3, organized.Count)
+	assert.Equal(t, "T1", organized.Tournaments[0].ID)
+	assert.Equal(t, "2024-01-07", organized.Tournaments[0].EndDate)
+	assert.Equal(t, "T2", organized.Tournaments[1].ID)
+	assert.Equal(t, 15, organized.Tournaments[2].Participants)
+	assert.Equal(t, 95, organized.Participants)
+	assert.Equal(t, map[string]int{"finished": 2, "running": 1}, organized.Statuses)
+
+	result, err = s.handleGetOrganizerTournaments(s.ctx, map[string]interface{}{"organizer": "sf ulm", "period": "2024-01", "limit": float64(1)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &organized))
+	assert.Equal(t, 2, organized.Count)
+	require.Len(t, organized.Tournaments, 1)
+	assert.Equal(t, "T3", organized.Tournaments[0].ID)
+
+	for _, args := range []map[string]interface{}{{}, {"club_id": "C0327", "organizer": "SF Ulm"}} {
+		result, err := s.handleGetOrganizerTournaments(s.ctx, args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Error: either club_id or organizer is required", result.Content[0].Text)
+	}
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// defaultMaxFailureRatio is the share of failed sub-requests up to which an
+// aggregate tool returns a partial result when no configuration is loaded
+const defaultMaxFailureRatio = 0.5
+
+// FailedItem is a sub-request of an aggregate tool that failed, such as the
+// rating history of one club member
+type FailedItem struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// failedItems collects the failed sub-requests of an aggregate. It is safe
+// for concurrent use.
+type failedItems struct {
+	mu    sync.Mutex
+	items []FailedItem
+}
+
+// add records a failed sub-request
+func (f *failedItems) add(id string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items = append(f.items, FailedItem{ID: id, Error: err.Error()})
+}
+
+// list returns the failed sub-requests ordered by ID, nil if none failed
+func (f *failedItems) list() []FailedItem {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.items) == 0 {
+		return nil
+	}
+	items := append([]FailedItem(nil), f.items...)
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// maxFailureRatio returns the share of failed sub-requests up to which
+// aggregate tools return partial results
+func (s *Server) maxFailureRatio() float64 {
+	if s.config == nil {
+		return defaultMaxFailureRatio
+	}
+	return s.config.MCP.Aggregates.MaxFailureRatio
+}
+
+// checkFailures returns an error if more than the allowed share of the
+// total sub-requests failed, and otherwise warns that the result is partial
+// if any failed. what names the sub-requests, e.g. "member rating
+// histories".
+func (s *Server) checkFailures(ctx context.Context, failed []FailedItem, total int, what string) error {
+	if len(failed) == 0 || total == 0 {
+		return nil
+	}
+	ratio := s.maxFailureRatio()
+	if float64(len(failed))/float64(total) > ratio {
+		return fmt.Errorf("%d of %d %s failed, more than the allowed %g%% (first error: %s)",
+			len(failed), total, what, ratio*100, failed[0].Error)
+	}
+	addWarning(ctx, fmt.Sprintf("%d of %d %s failed, the result is partial (see failed_items)", len(failed), total, what))
+	return nil
+}
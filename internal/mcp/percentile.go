@@ -0,0 +1,430 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// Distribution scopes
+const (
+	scopeClub     = "club"
+	scopeRegion   = "region"
+	scopeNational = "national"
+)
+
+const (
+	// defaultDistributionMaxAge is how long a snapshot is used when the
+	// background aggregation job is disabled
+	defaultDistributionMaxAge = 24 * time.Hour
+	// maxNationalPages bounds the player search pages of the national snapshot
+	maxNationalPages = 1000
+)
+
+// RatingDistribution is a snapshot of the DWZ of all rated players of a club,
+// region or the whole federation
+type RatingDistribution struct {
+	Scope      string    `json:"scope"`
+	Name       string    `json:"name,omitempty"`
+	Players    int       `json:"players"` // Rated players
+	ComputedAt time.Time `json:"computed_at"`
+	// FailedItems are the clubs whose members are missing because their
+	// member list failed to load
+	FailedItems []FailedItem `json:"failed_items,omitempty"`
+	ratings     []int        // Ascending
+}
+
+// newRatingDistribution builds a distribution of the rated players
+func newRatingDistribution(scope, name string, players []api.PlayerResponse, computedAt time.Time) *RatingDistribution {
+	seen := make(map[string]bool, len(players))
+	ratings := make([]int, 0, len(players))
+	for _, p := range players {
+		if p.CurrentDWZ <= 0 || (p.ID != "" && seen[p.ID]) {
+			continue
+		}
+		seen[p.ID] = true
+		ratings = append(ratings, p.CurrentDWZ)
+	}
+	sort.Ints(ratings)
+	return &RatingDistribution{Scope: scope, Name: name, Players: len(ratings), ComputedAt: computedAt, ratings: ratings}
+}
+
+// PercentileRank is the position of a rating within a distribution
+type PercentileRank struct {
+	Scope      string    `json:"scope"`
+	Name       string    `json:"name,omitempty"`
+	Rank       int       `json:"rank"`       // 1 for the highest rating, shared by equal ratings
+	Total      int       `json:"total"`      // Rated players in the distribution
+	Percentile float64   `json:"percentile"` // Share of players rated lower, counting equal ratings half
+	ComputedAt time.Time `json:"computed_at"`
+	// FailedItems are the parts of the distribution that are missing
+	FailedItems []FailedItem `json:"failed_items,omitempty"`
+}
+
+// rank returns the position of dwz within the distribution
+func (d *RatingDistribution) rank(dwz int) PercentileRank {
+	below := sort.SearchInts(d.ratings, dwz)
+	notAbove := sort.SearchInts(d.ratings, dwz+1)
+	result := PercentileRank{
+		Scope:       d.Scope,
+		Name:        d.Name,
+		Rank:        len(d.ratings) - notAbove + 1,
+		Total:       len(d.ratings),
+		ComputedAt:  d.ComputedAt,
+		FailedItems: d.FailedItems,
+	}
+	if result.Total > 0 {
+		percentile := (float64(below) + float64(notAbove-below)/2) / float64(result.Total) * 100
+		result.Percentile = math.Round(percentile*10) / 10
+	}
+	return result
+}
+
+// distributionSnapshots holds the region and national distributions
+// maintained by the background aggregation job
+type distributionSnapshots struct {
+	mu        sync.Mutex
+	snapshots map[string]*RatingDistribution
+	building  map[string]bool
+}
+
+func distributionKey(scope, name string) string {
+	if scope == scopeNational {
+		return scopeNational
+	}
+	return scope + ":" + strings.ToLower(name)
+}
+
+func (d *distributionSnapshots) get(key string) *RatingDistribution {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snapshots[key]
+}
+
+func (d *distributionSnapshots) put(key string, distribution *RatingDistribution) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.snapshots == nil {
+		d.snapshots = make(map[string]*RatingDistribution)
+	}
+	d.snapshots[key] = distribution
+}
+
+// all returns all snapshots in key order
+func (d *distributionSnapshots) all() []*RatingDistribution {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := make([]string, 0, len(d.snapshots))
+	for key := range d.snapshots {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]*RatingDistribution, len(keys))
+	for i, key := range keys {
+		result[i] = d.snapshots[key]
+	}
+	return result
+}
+
+// startBuild marks a snapshot as being built, returning false if it already is
+func (d *distributionSnapshots) startBuild(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.building[key] {
+		return false
+	}
+	if d.building == nil {
+		d.building = make(map[string]bool)
+	}
+	d.building[key] = true
+	return true
+}
+
+func (d *distributionSnapshots) finishBuild(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.building, key)
+}
+
+// distributionMaxAge returns how long a snapshot is used before it is
+// rebuilt on demand. With the aggregation job running, snapshots are
+// refreshed every interval and only rebuilt if the job falls behind.
+func (s *Server) distributionMaxAge() time.Duration {
+	if s.config != nil && s.config.Distributions.RefreshInterval > 0 {
+		return 2 * s.config.Distributions.RefreshInterval
+	}
+	return defaultDistributionMaxAge
+}
+
+// nationalDistributionEnabled reports whether a national snapshot is maintained
+func (s *Server) nationalDistributionEnabled() bool {
+	return s.config != nil && s.config.Distributions.National
+}
+
+// buildRegionDistribution collects the members of all clubs of a region.
+// Clubs whose member list fails to load are listed as failed items, unless
+// more than the allowed share of them fails.
+func (s *Server) buildRegionDistribution(ctx context.Context, region string) (*RatingDistribution, error) {
+	clubs, _, err := s.fetchAllClubs(ctx, api.SearchParams{FilterBy: "region", FilterValue: region}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Per-club progress of fetchAllClubPlayers is not meaningful here
+	quiet := withProgress(ctx, nil)
+	members := make([][]api.PlayerResponse, len(clubs))
+	var failed failedItems
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, asOfConcurrency)
+	for i, club := range clubs {
+		wg.Add(1)
+		go func(i int, clubID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			players, _, err := s.fetchAllClubPlayers(quiet, clubID, api.SearchParams{})
+			if err != nil {
+				s.logger.WithError(err).WithField("club_id", clubID).Debug("Failed to get club members for region distribution")
+				failed.add(clubID, err)
+			}
+			members[i] = players
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, done, len(clubs), fmt.Sprintf("Collected members of %d of %d clubs in %s", done, len(clubs), region), nil)
+			mu.Unlock()
+		}(i, club.ID)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	failedClubs := failed.list()
+	if err := s.checkFailures(ctx, failedClubs, len(clubs), "club member lists"); err != nil {
+		return nil, err
+	}
+
+	var players []api.PlayerResponse
+	for _, m := range members {
+		players = append(players, m...)
+	}
+	distribution := newRatingDistribution(scopeRegion, region, players, time.Now())
+	distribution.FailedItems = failedClubs
+	return distribution, nil
+}
+
+// buildNationalDistribution walks the player search without a query
+func (s *Server) buildNationalDistribution(ctx context.Context) (*RatingDistribution, error) {
+	var players []api.PlayerResponse
+	for page := 0; page < maxNationalPages; page++ {
+		params := api.SearchParams{Limit: aggregatePageSize, Offset: page * aggregatePageSize}
+		result, err := s.apiClient.SearchPlayers(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		pagePlayers, _ := result.Data.([]api.PlayerResponse)
+		players = append(players, pagePlayers...)
+
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		if len(pagePlayers) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			break
+		}
+	}
+	return newRatingDistribution(scopeNational, "", players, time.Now()), nil
+}
+
+// buildDistribution builds the snapshot of a scope and stores it
+func (s *Server) buildDistribution(ctx context.Context, scope, name string) (*RatingDistribution, error) {
+	var distribution *RatingDistribution
+	var err error
+	if scope == scopeNational {
+		distribution, err = s.buildNationalDistribution(ctx)
+	} else {
+		distribution, err = s.buildRegionDistribution(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.distributions.put(distributionKey(scope, name), distribution)
+	return distribution, nil
+}
+
+// buildDistributionAsync builds a snapshot in the background unless it is
+// already being built
+func (s *Server) buildDistributionAsync(scope, name string) {
+	key := distributionKey(scope, name)
+	if !s.distributions.startBuild(key) {
+		return
+	}
+	go func() {
+		defer s.distributions.finishBuild(key)
+		if _, err := s.buildDistribution(s.ctx, scope, name); err != nil {
+			s.logger.WithError(err).WithField("distribution", key).Warn("Failed to build rating distribution")
+		}
+	}()
+}
+
+// regionDistribution returns the region snapshot, building it on first use
+// or when it is older than distributionMaxAge
+func (s *Server) regionDistribution(ctx context.Context, region string) (*RatingDistribution, error) {
+	if d := s.distributions.get(distributionKey(scopeRegion, region)); d != nil && time.Since(d.ComputedAt) < s.distributionMaxAge() {
+		return d, nil
+	}
+	return s.buildDistribution(ctx, scopeRegion, region)
+}
+
+// refreshDistributions rebuilds all snapshots, adding the national one when
+// enabled. Regions are those requested since the server started.
+func (s *Server) refreshDistributions(ctx context.Context) {
+	snapshots := s.distributions.all()
+	if s.nationalDistributionEnabled() && s.distributions.get(scopeNational) == nil {
+		snapshots = append(snapshots, &RatingDistribution{Scope: scopeNational})
+	}
+
+	for _, snapshot := range snapshots {
+		if ctx.Err() != nil {
+			return
+		}
+		key := distributionKey(snapshot.Scope, snapshot.Name)
+		if !s.distributions.startBuild(key) {
+			continue
+		}
+		_, err := s.buildDistribution(ctx, snapshot.Scope, snapshot.Name)
+		s.distributions.finishBuild(key)
+		if err != nil {
+			s.logger.WithError(err).WithField("distribution", key).Warn("Failed to refresh rating distribution")
+		}
+	}
+}
+
+// runDistributionJob refreshes the distribution snapshots every interval
+// until ctx is done. The national snapshot is built right away.
+func (s *Server) runDistributionJob(ctx context.Context, interval time.Duration) {
+	s.refreshDistributions(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshDistributions(ctx)
+		}
+	}
+}
+
+// PlayerPercentile is the standing of a player's DWZ in several distributions
+type PlayerPercentile struct {
+	PlayerID string          `json:"player_id"`
+	Name     string          `json:"name"`
+	DWZ      int             `json:"dwz"`
+	Club     *PercentileRank `json:"club,omitempty"`
+	Region   *PercentileRank `json:"region,omitempty"`
+	National *PercentileRank `json:"national,omitempty"`
+}
+
+// handleGetPlayerPercentile handles player percentile requests
+func (s *Server) handleGetPlayerPercentile(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	playerID, ok := args["player_id"].(string)
+	if !ok || playerID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: player_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	player, err := s.apiClient.GetPlayerProfile(ctx, playerID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting player profile: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if player.CurrentDWZ <= 0 {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: player %s has no DWZ", playerID),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result := &PlayerPercentile{PlayerID: player.ID, Name: player.Name, DWZ: player.CurrentDWZ}
+	if player.Firstname != "" {
+		result.Name += ", " + player.Firstname
+	}
+
+	region, _ := args["region"].(string)
+	if player.ClubID != "" {
+		members, _, err := s.fetchAllClubPlayers(withProgress(ctx, nil), player.ClubID, api.SearchParams{})
+		if err != nil {
+			addWarning(ctx, fmt.Sprintf("Club distribution unavailable: %v", err))
+		} else {
+			rank := newRatingDistribution(scopeClub, player.ClubID, members, time.Now()).rank(player.CurrentDWZ)
+			result.Club = &rank
+		}
+
+		if region == "" {
+			if profile, err := s.apiClient.GetClubProfile(ctx, player.ClubID); err == nil && profile.Club != nil {
+				region = profile.Club.Region
+			}
+		}
+	}
+
+	if region == "" {
+		addWarning(ctx, "The player's region is unknown, pass region to rank within a region")
+	} else if distribution, err := s.regionDistribution(ctx, region); err != nil {
+		addWarning(ctx, fmt.Sprintf("Region distribution unavailable: %v", err))
+	} else {
+		rank := distribution.rank(player.CurrentDWZ)
+		result.Region = &rank
+	}
+
+	explain(ctx,
+		"percentile = (players rated lower + players rated equal / 2) / total * 100",
+		"rank = players rated higher + 1, shared by equal ratings",
+	)
+	addCaveat(ctx, "Distributions count rated players only, each player once")
+	addCaveat(ctx, "Region and national distributions are snapshots as of their computed_at; the requests that built them are not listed")
+
+	if includeNational, _ := args["include_national"].(bool); includeNational {
+		if distribution := s.distributions.get(scopeNational); distribution != nil {
+			rank := distribution.rank(player.CurrentDWZ)
+			result.National = &rank
+		} else if s.nationalDistributionEnabled() {
+			s.buildDistributionAsync(scopeNational, "")
+			addWarning(ctx, "The national distribution is being computed, try again later")
+		} else {
+			addWarning(ctx, "The national distribution is disabled (distributions.national)")
+		}
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestRatingDistribution_Rank(t *testing.T) {
+	players := []api.PlayerResponse{
+		{ID: "1", CurrentDWZ: 1400},
+		{ID: "2", CurrentDWZ: 1600},
+		{ID: "3", CurrentDWZ: 1600},
+		{ID: "4", CurrentDWZ: 1800},
+		{ID: "5", CurrentDWZ: 0},
+		{ID: "4", CurrentDWZ: 1800},
+	}
+	distribution := newRatingDistribution(scopeClub, "C0327", players, time.Now())
+	assert.Equal(t, 4, distribution.Players, "unrated and duplicate players are skipped")
+
+	testCases := []struct {
+		dwz        int
+		rank       int
+		percentile float64
+	}{
+		{1800, 1, 87.5},
+		{1600, 2, 50},
+		{1400, 4, 12.5},
+		{2000, 1, 100},
+		{1000, 5, 0},
+	}
+	for _, tc := range testCases {
+		rank := distribution.rank(tc.dwz)
+		assert.Equal(t, tc.rank, rank.Rank, "rank of %d", tc.dwz)
+		assert.Equal(t, tc.percentile, rank.Percentile, "percentile of %d", tc.dwz)
+		assert.Equal(t, 4, rank.Total)
+	}
+}
+
+func TestHandleGetPlayerPercentile(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/players/C0327-297":
+			w.Write([]byte(`{"id": "C0327-297", "name": "Tran", "firstname": "Minh Cuong", "club_id": "C0327", "current_dwz": 1700}`))
+		case "/api/v1/clubs/C0327/profile":
+			w.Write([]byte(`{"club": {"id": "C0327", "name": "SV Tübingen", "region": "Württemberg"}}`))
+		case "/api/v1/clubs/C0327/players":
+			w.Write([]byte(`[{"id": "C0327-297", "name": "Tran", "current_dwz": 1700}, {"id": "C0327-298", "name": "Meyer", "current_dwz": 1900}]`))
+		case "/api/v1/clubs/C0505/players":
+			w.Write([]byte(`[{"id": "C0505-1", "name": "Kraus", "current_dwz": 1500}, {"id": "C0505-2", "name": "Weber", "current_dwz": 1300}]`))
+		case "/api/v1/clubs":
+			assert.Equal(t, "Württemberg", r.URL.Query().Get("filter_value"))
+			w.Write([]byte(`[{"id": "C0327", "name": "SV Tübingen"}, {"id": "C0505", "name": "SC Stuttgart"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	ctx, warnings := withWarnings(context.Background())
+	result, err := s.handleGetPlayerPercentile(ctx, map[string]interface{}{"player_id": "C0327-297", "include_national": true})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var percentile PlayerPercentile
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &percentile))
+	assert.Equal(t, "Tran, Minh Cuong", percentile.Name)
+	require.NotNil(t, percentile.Club)
+	assert.Equal(t, 2, percentile.Club.Rank)
+	assert.Equal(t, 25.0, percentile.Club.Percentile)
+	require.NotNil(t, percentile.Region)
+	assert.Equal(t, "Württemberg", percentile.Region.Name)
+	assert.Equal(t, 2, percentile.Region.Rank)
+	assert.Equal(t, 4, percentile.Region.Total)
+	assert.Nil(t, percentile.National)
+	assert.Equal(t, []string{"The national distribution is disabled (distributions.national)"}, warnings.warnings)
+
+	snapshot := s.distributions.get(distributionKey(scopeRegion, "württemberg"))
+	require.NotNil(t, snapshot, "the region snapshot is kept for the aggregation job")
+	assert.Equal(t, 4, snapshot.Players)
+}
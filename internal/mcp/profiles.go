@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+const (
+	// ProfileHeader selects the upstream profile of HTTP bridge requests
+	ProfileHeader = "X-Portal64-Profile"
+	// profileArgument selects the upstream profile of a tool call and takes
+	// precedence over the header
+	profileArgument = "profile"
+)
+
+type profileKey struct{}
+
+// withProfile returns a context selecting the named upstream profile
+func withProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, profileKey{}, name)
+}
+
+// profileFromContext returns the upstream profile selected for a request
+func profileFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(profileKey{}).(string)
+	return name
+}
+
+// AddProfile registers a named upstream profile. Tool calls selecting the
+// profile are served by the given API client with their own caches, so
+// data of different Portal64 instances never mix; all other state is
+// shared with the default server.
+func (s *Server) AddProfile(name string, apiClient *api.Client) error {
+	if name == "" || name == config.DefaultProfile {
+		return fmt.Errorf("profile name %q is reserved", name)
+	}
+	if _, exists := s.profiles[name]; exists {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	profile := &Server{
+		sharedState: s.sharedState,
+		apiClient:   apiClient,
+		tools:       make(map[string]ToolHandler),
+		resources:   make(map[string]ResourceHandler),
+		profile:     name,
+	}
+	profile.registerTools()
+	profile.registerResources()
+	profile.registerMemoryStores()
+	profile.attachResponseCache()
+
+	if s.profiles == nil {
+		s.profiles = make(map[string]*Server)
+	}
+	s.profiles[name] = profile
+	return nil
+}
+
+// Profiles returns the names of the upstream profiles in order, starting
+// with the default profile
+func (s *Server) Profiles() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{config.DefaultProfile}, names...)
+}
+
+// profileServer returns the server of a named upstream profile. An empty
+// name selects the default profile.
+func (s *Server) profileServer(name string) (*Server, error) {
+	if name == "" || name == config.DefaultProfile {
+		return s, nil
+	}
+	if profile, ok := s.profiles[name]; ok {
+		return profile, nil
+	}
+	return nil, fmt.Errorf("unknown profile %q, expected one of: %s", name, strings.Join(s.Profiles(), ", "))
+}
+
+// profileFor returns the server of the profile selected by the profile
+// argument of a tool call or by the request context, together with the
+// arguments without the profile argument
+func (s *Server) profileFor(ctx context.Context, args map[string]interface{}) (*Server, map[string]interface{}, error) {
+	name := profileFromContext(ctx)
+	if value, ok := args[profileArgument]; ok {
+		str, isString := value.(string)
+		if !isString {
+			return nil, nil, fmt.Errorf("profile must be a string")
+		}
+		name = str
+
+		rest := make(map[string]interface{}, len(args)-1)
+		for k, v := range args {
+			if k != profileArgument {
+				rest[k] = v
+			}
+		}
+		args = rest
+	}
+
+	profile, err := s.profileServer(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return profile, args, nil
+}
+
+// withProfileArgument adds the profile argument to a tool definition when
+// upstream profiles are configured
+func (s *Server) withProfileArgument(tool Tool) Tool {
+	if len(s.profiles) == 0 {
+		return tool
+	}
+	properties := make(map[string]interface{}, len(tool.InputSchema.Properties)+1)
+	for k, v := range tool.InputSchema.Properties {
+		properties[k] = v
+	}
+	properties[profileArgument] = map[string]interface{}{
+		"type":        "string",
+		"description": "Portal64 instance to query (default: " + config.DefaultProfile + ")",
+		"enum":        s.Profiles(),
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// newClubUpstream serves a club profile with the given club name
+func newClubUpstream(t *testing.T, clubName string) *httptest.Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"club": {"id": "C0327", "name": "` + clubName + `"}}`))
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+func newProfileTestServer(t *testing.T) *Server {
+	s := newTestServer()
+	s.apiClient = api.NewClient(newClubUpstream(t, "Production").URL, 5*time.Second, nil)
+	s.exportKey = []byte("secret")
+	s.registerTools()
+	s.registerResources()
+	require.NoError(t, s.AddProfile("test", api.NewClient(newClubUpstream(t, "Test").URL, 5*time.Second, nil)))
+	return s
+}
+
+func TestAddProfile(t *testing.T) {
+	s := newProfileTestServer(t)
+
+	assert.Equal(t, []string{"default", "test"}, s.Profiles())
+	assert.Error(t, s.AddProfile("default", s.apiClient))
+	assert.Error(t, s.AddProfile("test", s.apiClient))
+
+	profile, err := s.profileServer("test")
+	require.NoError(t, err)
+	assert.NotSame(t, s.apiClient, profile.apiClient)
+	assert.Same(t, s.sharedState, profile.sharedState, "state other than the upstream and its caches is shared")
+	assert.NotSame(t, &s.rosters, &profile.rosters)
+	assert.Equal(t, "C0327-297", s.storeKey("C0327-297"))
+	assert.Equal(t, "test/C0327-297", profile.storeKey("C0327-297"))
+
+	_, err = s.profileServer("staging")
+	assert.EqualError(t, err, `unknown profile "staging", expected one of: default, test`)
+}
+
+func TestHandleCallTool_ProfileArgument(t *testing.T) {
+	s := newProfileTestServer(t)
+
+	call := func(args string) *Message {
+		response, err := s.handleMessage([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_club_profile","arguments":` + args + `}}`))
+		require.NoError(t, err)
+		return response
+	}
+
+	response := call(`{"club_id":"C0327"}`)
+	require.Nil(t, response.Error)
+	assert.Contains(t, string(mustMarshal(t, response.Result)), "Production")
+
+	response = call(`{"club_id":"C0327","profile":"test"}`)
+	require.Nil(t, response.Error)
+	assert.Contains(t, string(mustMarshal(t, response.Result)), "Test")
+
+	response = call(`{"club_id":"C0327","profile":"staging"}`)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, InvalidParams, response.Error.Code)
+}
+
+func TestGetToolDefinition_ProfileArgument(t *testing.T) {
+	s := newTestServer()
+	assert.NotContains(t, s.GetToolDefinition("get_club_profile").InputSchema.Properties, "profile")
+
+	s = newProfileTestServer(t)
+	tool := s.GetToolDefinition("get_club_profile")
+	require.Contains(t, tool.InputSchema.Properties, "profile")
+	assert.Equal(t, []string{"default", "test"}, tool.InputSchema.Properties["profile"].(map[string]interface{})["enum"])
+	assert.Contains(t, tool.InputSchema.Properties, "club_id")
+	assert.Contains(t, s.GetToolDefinition("get_regions").InputSchema.Properties, "profile")
+}
+
+func TestHTTPBridge_ProfileHeader(t *testing.T) {
+	s := newProfileTestServer(t)
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	get := func(profile string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/clubs/C0327", nil)
+		if profile != "" {
+			req.Header.Set(ProfileHeader, profile)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "Production")
+
+	rec = get("test")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "Test")
+
+	rec = get("staging")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "UNKNOWN_PROFILE")
+
+	// The profile argument takes precedence over the header
+	body, _ := json.Marshal(CallToolRequest{Name: "get_club_profile", Arguments: map[string]interface{}{"club_id": "C0327", "profile": "default"}})
+	req := httptest.NewRequest(http.MethodPost, "/tools/call", bytes.NewReader(body))
+	req.Header.Set(ProfileHeader, "test")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "Production")
+}
+
+func TestSignedExportURL_Profile(t *testing.T) {
+	s := newProfileTestServer(t)
+	profile, err := s.profileServer("test")
+	require.NoError(t, err)
+
+	link, err := url.Parse(profile.signedExportURL("C0327", time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+	query := link.Query()
+	assert.Equal(t, "test", query.Get("profile"))
+	assert.NoError(t, verifyExportSignature(s.exportKey, "test", "C0327", query.Get("expires"), query.Get("signature"), time.Now()))
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,35 @@
+package mcp
+
+import "context"
+
+// ProgressFunc reports the progress of a long-running tool call.
+// Partial carries intermediate results and may be nil.
+type ProgressFunc func(progress, total int, message string, partial interface{})
+
+type progressKey struct{}
+
+// withProgress attaches a progress reporter to the context
+func withProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress reports progress if the caller requested it
+func reportProgress(ctx context.Context, progress, total int, message string, partial interface{}) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(progress, total, message, partial)
+	}
+}
+
+// progressReporter returns a ProgressFunc sending notifications/progress
+// messages for the given token
+func (s *Server) progressReporter(token interface{}) ProgressFunc {
+	return func(progress, total int, message string, partial interface{}) {
+		s.sendNotification(NewNotification("notifications/progress", ProgressNotification{
+			ProgressToken: token,
+			Progress:      float64(progress),
+			Total:         float64(total),
+			Message:       message,
+			Partial:       partial,
+		}))
+	}
+}
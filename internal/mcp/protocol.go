@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -32,6 +33,14 @@ const (
 	MethodNotFound = -32601
 	InvalidParams  = -32602
 	InternalError  = -32603
+
+	// ServerBusy is returned for tool calls shed while the server is overloaded
+	ServerBusy = -32000
+	// WriteDisabled is returned for calls of write tools in read-only mode
+	WriteDisabled = -32001
+
+	// RequestCancelled is returned when the client cancelled an in-flight request
+	RequestCancelled = -32800
 )
 
 // Initialize request and response
@@ -89,9 +98,20 @@ type ListToolsResponse struct {
 }
 
 type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema ToolSchema  `json:"inputSchema"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	InputSchema ToolSchema       `json:"inputSchema"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints on the behavior of a tool for clients. Hints are
+// pointers so that unset hints keep the defaults of the MCP specification.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
 }
 
 type ToolSchema struct {
@@ -103,6 +123,12 @@ type ToolSchema struct {
 type CallToolRequest struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Meta      *RequestMeta           `json:"_meta,omitempty"`
+}
+
+// RequestMeta carries request metadata such as the progress token
+type RequestMeta struct {
+	ProgressToken interface{} `json:"progressToken,omitempty"`
 }
 
 type CallToolResponse struct {
@@ -111,9 +137,10 @@ type CallToolResponse struct {
 }
 
 type ToolContent struct {
-	Type string      `json:"type"`
-	Text string      `json:"text,omitempty"`
-	Data interface{} `json:"data,omitempty"`
+	Type     string      `json:"type"`
+	Text     string      `json:"text,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	MimeType string      `json:"mimeType,omitempty"`
 }
 
 // Resource-related structures
@@ -153,6 +180,32 @@ type Notification struct {
 
 type InitializedNotification struct{}
 
+// ProgressNotification reports progress of a long-running request
+type ProgressNotification struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
+	Partial       interface{} `json:"partial,omitempty"`
+}
+
+// CancelledNotification cancels an in-flight request. MCP clients send
+// notifications/cancelled with requestId, LSP-style clients send
+// $/cancelRequest with id.
+type CancelledNotification struct {
+	RequestID interface{} `json:"requestId,omitempty"`
+	ID        interface{} `json:"id,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// TargetID returns the ID of the request to cancel
+func (n CancelledNotification) TargetID() interface{} {
+	if n.RequestID != nil {
+		return n.RequestID
+	}
+	return n.ID
+}
+
 // Helper functions for creating responses
 func NewSuccessResponse(id interface{}, result interface{}) *Message {
 	return &Message{
@@ -182,12 +235,25 @@ func NewNotification(method string, params interface{}) *Message {
 	}
 }
 
-// ParseMessage parses a JSON message into an MCP Message
+// errInvalidRequest marks messages that are valid JSON but not valid
+// JSON-RPC 2.0
+var errInvalidRequest = errors.New("invalid request")
+
+// ParseMessage parses a JSON message into an MCP Message. Messages that are
+// valid JSON but not JSON-RPC 2.0 return an error wrapping errInvalidRequest.
 func ParseMessage(data []byte) (*Message, error) {
 	var msg Message
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return nil, fmt.Errorf("failed to parse message: %w", err)
 	}
+	if msg.JSONRPC != "2.0" {
+		return nil, fmt.Errorf("%w: jsonrpc must be \"2.0\", got %q", errInvalidRequest, msg.JSONRPC)
+	}
+	switch msg.ID.(type) {
+	case nil, string, float64:
+	default:
+		return nil, fmt.Errorf("%w: id must be a string or number", errInvalidRequest)
+	}
 	return &msg, nil
 }
 
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -409,3 +410,82 @@ func TestListResourcesResponse(t *testing.T) {
 	assert.Equal(t, "players://12345", deserializedResponse.Resources[0].URI)
 	assert.Equal(t, "clubs://001", deserializedResponse.Resources[1].URI)
 }
+
+func TestParseMessage(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		invalid bool // valid JSON, but not JSON-RPC 2.0
+		err     bool
+	}{
+		{"Request", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, false, false},
+		{"String ID", `{"jsonrpc":"2.0","id":"abc","method":"tools/list"}`, false, false},
+		{"Notification", `{"jsonrpc":"2.0","method":"notifications/initialized"}`, false, false},
+		{"Null ID", `{"jsonrpc":"2.0","id":null,"method":"test"}`, false, false},
+		{"Missing version", `{"id":1,"method":"tools/list"}`, true, true},
+		{"Wrong version", `{"jsonrpc":"1.0","id":1,"method":"tools/list"}`, true, true},
+		{"Object ID", `{"jsonrpc":"2.0","id":{},"method":"tools/list"}`, true, true},
+		{"Boolean ID", `{"jsonrpc":"2.0","id":true,"method":"tools/list"}`, true, true},
+		{"Array", `[{"jsonrpc":"2.0","id":1,"method":"tools/list"}]`, false, true},
+		{"Trailing data", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}x`, false, true},
+		{"Truncated", `{"jsonrpc":"2.0","id":1,`, false, true},
+		{"Empty", ``, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, err := ParseMessage([]byte(tc.input))
+			if !tc.err {
+				require.NoError(t, err)
+				assert.Equal(t, "2.0", msg.JSONRPC)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tc.invalid, errors.Is(err, errInvalidRequest))
+		})
+	}
+}
+
+func TestHandleMessage_InvalidRequest(t *testing.T) {
+	s := newTestServer()
+
+	response, err := s.handleMessage([]byte(`{"jsonrpc":"1.0","id":1,"method":"tools/list"}`))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, InvalidRequest, response.Error.Code)
+
+	response, err = s.handleMessage([]byte(`{"jsonrpc":"2.0","id":1`))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, ParseError, response.Error.Code)
+}
+
+func FuzzParseMessage(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"search_players","arguments":{"query":"x"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`,
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"x"}}`,
+		`{"jsonrpc":"2.0","id":1e400}`,
+		`{"jsonrpc":"1.0"}`,
+		`null`,
+		`[]`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := ParseMessage(data)
+		if err != nil {
+			return
+		}
+
+		// Parsed messages serialize and parse again to the same message
+		encoded, err := SerializeMessage(msg)
+		require.NoError(t, err)
+		reparsed, err := ParseMessage(encoded)
+		require.NoError(t, err, "%s", encoded)
+		assert.Equal(t, msg.JSONRPC, reparsed.JSONRPC)
+		assert.Equal(t, msg.ID, reparsed.ID)
+		assert.Equal(t, msg.Method, reparsed.Method)
+	})
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/qrcode"
+)
+
+// QR codes are rendered with defaultQRScale pixels per module unless a
+// scale up to maxQRScale is requested, and a quiet zone of qrBorder modules
+const (
+	defaultQRScale = 8
+	maxQRScale     = 32
+	qrBorder       = 4
+)
+
+// qrLevels are the error correction levels of the error_correction argument
+var qrLevels = map[string]qrcode.Level{
+	"L": qrcode.LevelL,
+	"M": qrcode.LevelM,
+	"Q": qrcode.LevelQ,
+	"H": qrcode.LevelH,
+}
+
+// QRCode describes the QR code of the deep link of an entity
+type QRCode struct {
+	ID              string     `json:"id"`
+	Kind            api.IDKind `json:"kind"`
+	URL             string     `json:"url"`
+	ErrorCorrection string     `json:"error_correction"`
+	Version         int        `json:"version"`
+	// Pixels is the width and height of the image, including the quiet zone
+	Pixels int `json:"pixels"`
+}
+
+// entityQRCode renders the deep link of a player, club or tournament as a
+// PNG image. Errors are caused by invalid arguments.
+func (s *Server) entityQRCode(id string, scale int, level string) (*QRCode, []byte, error) {
+	kind, canonical := api.DetectIDKind(id)
+	if kind == api.IDKindUnknown {
+		return nil, nil, fmt.Errorf("invalid id %q, expected a player (C0101-123), club (C0101) or tournament (C350-C01-SMU) ID", id)
+	}
+	if scale < 1 || scale > maxQRScale {
+		return nil, nil, fmt.Errorf("scale must be between 1 and %d", maxQRScale)
+	}
+	level = strings.ToUpper(level)
+	qrLevel, ok := qrLevels[level]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid error_correction %q, expected L, M, Q or H", level)
+	}
+	link := s.deepLinks().url(kind, canonical)
+	if link == "" {
+		return nil, nil, fmt.Errorf("no deep link is configured for %s IDs", kind)
+	}
+
+	code, err := qrcode.Encode([]byte(link), qrLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+	image, err := code.PNG(scale, qrBorder)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &QRCode{
+		ID:              canonical,
+		Kind:            kind,
+		URL:             link,
+		ErrorCorrection: level,
+		Version:         code.Version,
+		Pixels:          (code.Size + 2*qrBorder) * scale,
+	}, image, nil
+}
+
+// handleGetQRCode returns the QR code of an entity's deep link as PNG image
+// content, followed by the link it encodes
+func (s *Server) handleGetQRCode(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	id, ok := args["id"].(string)
+	if !ok || strings.TrimSpace(id) == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	scale := defaultQRScale
+	if value, ok := args["scale"].(float64); ok {
+		scale = int(value)
+	}
+	level := "M"
+	if value, ok := args["error_correction"].(string); ok && value != "" {
+		level = value
+	}
+
+	code, image, err := s.entityQRCode(id, scale, level)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	data, _ := json.MarshalIndent(code, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{
+			{
+				Type:     "image",
+				Data:     base64.StdEncoding.EncodeToString(image),
+				MimeType: "image/png",
+			},
+			{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+	}, nil
+}
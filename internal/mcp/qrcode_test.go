@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestHandleGetQRCode(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{Links: config.LinksConfig{
+		PlayerURL: "https://www.schachbund.de/spieler/{id}.html",
+		ClubURL:   "https://www.schachbund.de/verein/{id}.html",
+	}}
+
+	result, err := s.handleGetQRCode(context.Background(), map[string]interface{}{"id": "c0327/297", "scale": float64(2)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	require.Len(t, result.Content, 2)
+	assert.Equal(t, "image", result.Content[0].Type)
+	assert.Equal(t, "image/png", result.Content[0].MimeType)
+
+	var code QRCode
+	require.NoError(t, json.Unmarshal([]byte(result.Content[1].Text), &code))
+	assert.Equal(t, "C0327-297", code.ID)
+	assert.Equal(t, "https://www.schachbund.de/spieler/C0327-297.html", code.URL)
+	assert.Equal(t, "M", code.ErrorCorrection)
+
+	data, err := base64.StdEncoding.DecodeString(result.Content[0].Data.(string))
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, code.Pixels, img.Bounds().Dx())
+
+	for _, tc := range []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"missing id", map[string]interface{}{}},
+		{"invalid id", map[string]interface{}{"id": "Schachfreunde"}},
+		{"no template", map[string]interface{}{"id": "C350-C01-SMU"}},
+		{"scale", map[string]interface{}{"id": "C0327", "scale": float64(100)}},
+		{"level", map[string]interface{}{"id": "C0327", "error_correction": "X"}},
+	} {
+		result, err := s.handleGetQRCode(context.Background(), tc.args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, tc.name)
+		assert.Contains(t, result.Content[0].Text, "Error: ", tc.name)
+	}
+}
+
+func TestHTTPBridge_QRCode(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{Links: config.LinksConfig{ClubURL: "https://www.schachbund.de/verein/{id}.html"}}
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/qr/C0327?scale=3&error_correction=h", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	img, err := png.Decode(rec.Body)
+	require.NoError(t, err)
+	assert.Zero(t, img.Bounds().Dx()%3)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/qr/C0327-297", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "players have no template")
+}
@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+const (
+	// reactivationCacheTTL is how long the candidates of a club are cached
+	reactivationCacheTTL = 6 * time.Hour
+	// defaultReactivationLimit is the number of candidates listed by default
+	defaultReactivationLimit = 50
+)
+
+// ReactivationCandidate is an inactive club member with the last evaluation
+// of their rating history
+type ReactivationCandidate struct {
+	PlayerID  string `json:"player_id"`
+	Name      string `json:"name"`
+	Firstname string `json:"firstname"`
+	BirthYear int    `json:"birth_year,omitempty"`
+	Status    string `json:"status"`
+	// LastDWZ is the DWZ after the last evaluation, the current DWZ for
+	// members without a dated evaluation
+	LastDWZ        int    `json:"last_dwz"`
+	LastEvaluation string `json:"last_evaluation,omitempty"` // YYYY-MM-DD
+	LastTournament string `json:"last_tournament,omitempty"`
+	Evaluations    int    `json:"evaluations"`
+}
+
+// ReactivationCandidates is the result of get_reactivation_candidates
+type ReactivationCandidates struct {
+	ClubID          string                  `json:"club_id"`
+	InactiveMembers int                     `json:"inactive_members"`
+	Candidates      []ReactivationCandidate `json:"candidates"`
+	Count           int                     `json:"count"`
+	// FailedItems are the members whose rating history failed to load
+	FailedItems []FailedItem `json:"failed_items,omitempty"`
+}
+
+// reactivationCache caches the candidates of clubs
+type reactivationCache struct {
+	mu      sync.Mutex
+	entries map[string]reactivationCacheEntry
+}
+
+type reactivationCacheEntry struct {
+	candidates []ReactivationCandidate
+	size       int64
+	expires    time.Time
+}
+
+func (c *reactivationCache) get(clubID string) ([]ReactivationCandidate, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[clubID]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.candidates, true
+}
+
+func (c *reactivationCache) put(clubID string, candidates []ReactivationCandidate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]reactivationCacheEntry)
+	}
+	c.entries[clubID] = reactivationCacheEntry{candidates: candidates, size: memory.EstimateSize(candidates), expires: time.Now().Add(reactivationCacheTTL)}
+}
+
+// isInactive reports whether a member is inactive. Members without a status
+// count as active, like in the club statistics.
+func isInactive(p api.PlayerResponse) bool {
+	return p.Status != "" && p.Status != "active"
+}
+
+// reactivationCandidate summarizes the rating history of an inactive member
+func reactivationCandidate(p api.PlayerResponse, history []api.Evaluation) ReactivationCandidate {
+	candidate := ReactivationCandidate{
+		PlayerID:    p.ID,
+		Name:        p.Name,
+		Firstname:   p.Firstname,
+		BirthYear:   p.BirthYear,
+		Status:      p.Status,
+		LastDWZ:     p.CurrentDWZ,
+		Evaluations: len(history),
+	}
+	var last *api.Evaluation
+	for i := range history {
+		if !history[i].Date.IsZero() && (last == nil || history[i].Date.After(last.Date)) {
+			last = &history[i]
+		}
+	}
+	if last != nil {
+		candidate.LastEvaluation = last.Date.Format("2006-01-02")
+		candidate.LastTournament = last.TournamentName
+		if last.NewDWZ > 0 {
+			candidate.LastDWZ = last.NewDWZ
+		}
+	}
+	return candidate
+}
+
+// sortReactivationCandidates sorts the most recently evaluated members
+// first, members evaluated on the same day by their last DWZ and members
+// never evaluated last
+func sortReactivationCandidates(candidates []ReactivationCandidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.LastEvaluation != b.LastEvaluation {
+			return a.LastEvaluation > b.LastEvaluation
+		}
+		if a.LastDWZ != b.LastDWZ {
+			return a.LastDWZ > b.LastDWZ
+		}
+		return a.PlayerID < b.PlayerID
+	})
+}
+
+// clubReactivationCandidates loads the rating histories of the inactive
+// members of a club. Members whose history fails to load are left out and
+// listed as failed items, unless more than the allowed share of them fails.
+// Complete results are cached.
+func (s *Server) clubReactivationCandidates(ctx context.Context, clubID string) ([]ReactivationCandidate, []FailedItem, error) {
+	if candidates, ok := s.reactivation.get(clubID); ok {
+		return candidates, nil, nil
+	}
+
+	members, _, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var inactive []api.PlayerResponse
+	for _, m := range members {
+		if isInactive(m) {
+			inactive = append(inactive, m)
+		}
+	}
+
+	candidates := make([]*ReactivationCandidate, len(inactive))
+	var failed failedItems
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	sem := make(chan struct{}, asOfConcurrency)
+	for i, member := range inactive {
+		wg.Add(1)
+		go func(i int, member api.PlayerResponse) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			history, err := s.ratingHistory(ctx, member.ID)
+			if err == nil || api.IsNotFound(err) {
+				candidate := reactivationCandidate(member, history)
+				candidates[i] = &candidate
+			} else {
+				failed.add(member.ID, err)
+			}
+
+			mu.Lock()
+			done++
+			reportProgress(ctx, done, len(inactive), fmt.Sprintf("Loaded rating histories of %d of %d inactive members", done, len(inactive)), nil)
+			mu.Unlock()
+		}(i, member)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	failedMembers := failed.list()
+	if err := s.checkFailures(ctx, failedMembers, len(inactive), "member rating histories"); err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]ReactivationCandidate, 0, len(inactive))
+	for _, candidate := range candidates {
+		if candidate != nil {
+			result = append(result, *candidate)
+		}
+	}
+	sortReactivationCandidates(result)
+	if len(failedMembers) == 0 {
+		s.reactivation.put(clubID, result)
+	}
+	return result, failedMembers, nil
+}
+
+// handleGetReactivationCandidates handles requests for the inactive members
+// of a club
+func (s *Server) handleGetReactivationCandidates(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, ok := args["club_id"].(string)
+	if !ok || clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	limit := defaultReactivationLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+	minDWZ := 0
+	if m, ok := args["min_dwz"].(float64); ok {
+		minDWZ = int(m)
+	}
+
+	candidates, failed, err := s.clubReactivationCandidates(ctx, clubID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting reactivation candidates: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	result := ReactivationCandidates{ClubID: clubID, InactiveMembers: len(candidates) + len(failed), Candidates: []ReactivationCandidate{}, FailedItems: failed}
+	for _, candidate := range candidates {
+		if candidate.LastDWZ >= minDWZ {
+			result.Candidates = append(result.Candidates, candidate)
+		}
+	}
+	result.Count = len(result.Candidates)
+	if len(result.Candidates) > limit {
+		result.Candidates = result.Candidates[:limit]
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,143 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestReactivationCandidate(t *testing.T) {
+	player := api.PlayerResponse{ID: "C0327-1", Name: "Meyer", Status: "passive", CurrentDWZ: 1650}
+	history := []api.Evaluation{
+		{TournamentName: "Open 2019", Date: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), NewDWZ: 1700},
+		{TournamentName: "Undated", NewDWZ: 1900},
+		{TournamentName: "Club Championship", Date: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), NewDWZ: 1680},
+	}
+
+	candidate := reactivationCandidate(player, history)
+	assert.Equal(t, "2021-03-01", candidate.LastEvaluation)
+	assert.Equal(t, "Club Championship", candidate.LastTournament)
+	assert.Equal(t, 1680, candidate.LastDWZ)
+	assert.Equal(t, 3, candidate.Evaluations)
+
+	unrated := reactivationCandidate(player, nil)
+	assert.Empty(t, unrated.LastEvaluation)
+	assert.Equal(t, 1650, unrated.LastDWZ)
+}
+
+func TestSortReactivationCandidates(t *testing.T) {
+	candidates := []ReactivationCandidate{
+		{PlayerID: "C0327-1", LastDWZ: 1900},
+		{PlayerID: "C0327-2", LastEvaluation: "2019-05-01", LastDWZ: 1500},
+		{PlayerID: "C0327-3", LastEvaluation: "2022-01-15", LastDWZ: 1400},
+		{PlayerID: "C0327-4", LastEvaluation: "2022-01-15", LastDWZ: 1800},
+	}
+	sortReactivationCandidates(candidates)
+
+	var ids []string
+	for _, c := range candidates {
+		ids = append(ids, c.PlayerID)
+	}
+	assert.Equal(t, []string{"C0327-4", "C0327-3", "C0327-2", "C0327-1"}, ids)
+}
+
+func TestGetReactivationCandidates(t *testing.T) {
+	var historyRequests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/clubs/C0327/players":
+			w.Write([]byte(`{"data": [
+				{"id": "C0327-1", "name": "Active", "current_dwz": 2000, "status": "active"},
+				{"id": "C0327-2", "name": "Former", "current_dwz": 1500, "status": "passive"},
+				{"id": "C0327-3", "name": "Recent", "current_dwz": 1700, "status": "passive"},
+				{"id": "C0327-4", "name": "Never", "current_dwz": 0, "status": "passive"}
+			], "meta": {"total": 4}}`))
+		case r.URL.Path == "/api/v1/players/C0327-2/rating-history":
+			atomic.AddInt32(&historyRequests, 1)
+			w.Write([]byte(`[{"id": 1, "tournament_id": "T1", "tournament_name": "Open 2015", "tournament_date": "2015-06-01T00:00:00Z", "dwz_old": 1450, "dwz_new": 1500}]`))
+		case r.URL.Path == "/api/v1/players/C0327-3/rating-history":
+			atomic.AddInt32(&historyRequests, 1)
+			w.Write([]byte(`[{"id": 2, "tournament_id": "T2", "tournament_name": "Open 2020", "tournament_date": "2020-02-01T00:00:00Z", "dwz_old": 1650, "dwz_new": 1700}]`))
+		case strings.HasSuffix(r.URL.Path, "/rating-history"):
+			atomic.AddInt32(&historyRequests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	result, err := s.handleGetReactivationCandidates(s.ctx, map[string]interface{}{"club_id": "C0327"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var candidates ReactivationCandidates
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &candidates))
+	assert.Equal(t, 3, candidates.InactiveMembers)
+	assert.Equal(t, 3, candidates.Count)
+	require.Len(t, candidates.Candidates, 3)
+	assert.Equal(t, "C0327-3", candidates.Candidates[0].PlayerID)
+	assert.Equal(t, "Open 2020", candidates.Candidates[0].LastTournament)
+	assert.Equal(t, "C0327-2", candidates.Candidates[1].PlayerID)
+	assert.Equal(t, "C0327-4", candidates.Candidates[2].PlayerID)
+	assert.Empty(t, candidates.Candidates[2].LastEvaluation)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&historyRequests))
+
+	// Cached results are filtered without loading the histories again
+	result, err = s.handleGetReactivationCandidates(s.ctx, map[string]interface{}{"club_id": "C0327", "min_dwz": float64(1600)})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &candidates))
+	assert.Equal(t, 1, candidates.Count)
+	assert.Equal(t, "C0327-3", candidates.Candidates[0].PlayerID)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&historyRequests))
+
+	result, err = s.handleGetReactivationCandidates(s.ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Error: club_id is required", result.Content[0].Text)
+}
+
+func TestGetReactivationCandidates_PartialFailures(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clubs/C0327/players":
+			w.Write([]byte(`{"data": [
+				{"id": "C0327-1", "current_dwz": 1800, "status": "passive"},
+				{"id": "C0327-2", "current_dwz": 1500, "status": "passive"}
+			], "meta": {"total": 2}}`))
+		case "/api/v1/players/C0327-1/rating-history":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.config = &config.Config{MCP: config.MCPConfig{Aggregates: config.AggregatesConfig{MaxFailureRatio: 0.5}}}
+
+	candidates, failed, err := s.clubReactivationCandidates(s.ctx, "C0327")
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "C0327-2", failed[0].ID)
+
+	// Incomplete results are not cached
+	_, ok := s.reactivation.get("C0327")
+	assert.False(t, ok)
+}
@@ -0,0 +1,228 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+// ErrorReporter forwards recovered panics and tool failures to an external
+// error tracker. It is implemented by *telemetry.ErrorTracker.
+type ErrorReporter interface {
+	ReportPanic(recovered interface{}, stack []byte, tags map[string]string)
+	ReportError(err error, level string, tags map[string]string)
+}
+
+// PanicError is returned for a tool call that panicked. It carries an
+// incident ID that is logged with the stack trace, while the panic value
+// itself is not disclosed to clients.
+type PanicError struct {
+	IncidentID string
+	Value      interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("internal error (incident %s)", e.IncidentID)
+}
+
+// SetErrorReporter sets the reporter that recovered panics and tool failures
+// are forwarded to, including those of upstream profiles
+func (s *Server) SetErrorReporter(reporter ErrorReporter) {
+	s.errorReporter = reporter
+	for _, profile := range s.profiles {
+		profile.SetErrorReporter(reporter)
+	}
+}
+
+// PanicCount returns the number of panics recovered since the server started
+func (s *Server) PanicCount() int64 {
+	return s.panics.Load()
+}
+
+// newIncidentID returns a random ID correlating a client error with the log
+func newIncidentID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
+
+// recordPanic logs a recovered panic with its stack trace, counts it and
+// reports it to the error tracker. It returns the incident ID of the panic.
+func (s *Server) recordPanic(recovered interface{}, tags map[string]string) string {
+	stack := debug.Stack()
+	incidentID := newIncidentID()
+	s.panics.Add(1)
+
+	fields := logrus.Fields{
+		"panic":       fmt.Sprint(recovered),
+		"incident_id": incidentID,
+		"stack":       string(stack),
+	}
+	for k, v := range tags {
+		fields[k] = v
+	}
+	s.logger.WithFields(fields).Error("Recovered from panic")
+
+	if s.errorReporter != nil {
+		reportTags := map[string]string{"incident_id": incidentID}
+		for k, v := range tags {
+			reportTags[k] = v
+		}
+		s.errorReporter.ReportPanic(recovered, stack, reportTags)
+	}
+	return incidentID
+}
+
+// recoverTool wraps a tool handler so that a panic is turned into a
+// PanicError instead of crashing the server
+func (s *Server) recoverTool(name string, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (result *CallToolResponse, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				tags := map[string]string{"tool": name}
+				if s.profile != "" {
+					tags["profile"] = s.profile
+				}
+				incidentID := s.recordPanic(recovered, tags)
+				result, err = nil, &PanicError{IncidentID: incidentID, Value: recovered}
+			}
+		}()
+		return handler(ctx, args)
+	}
+}
+
+// reportToolErrors wraps a tool handler so that its failures are reported to
+// the error reporter. Results starting with "Error: " reject invalid
+// arguments and are not reported; other error results are failed operations,
+// usually of the Portal64 API, and reported as warnings.
+func (s *Server) reportToolErrors(name string, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		result, err := handler(ctx, args)
+		if s.errorReporter == nil || ctx.Err() != nil {
+			return result, err
+		}
+
+		// Argument values may contain personal data, only their names are sent
+		names := make([]string, 0, len(args))
+		for k := range args {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		tags := map[string]string{"tool": name, "arguments": strings.Join(names, ",")}
+		if s.profile != "" {
+			tags["profile"] = s.profile
+		}
+		switch {
+		case err != nil:
+			s.errorReporter.ReportError(err, telemetry.LevelError, tags)
+		case result != nil && result.IsError && !invalidArguments(result):
+			s.errorReporter.ReportError(errors.New(result.Content[0].Text), telemetry.LevelWarning, tags)
+		}
+		return result, err
+	}
+}
+
+// invalidArguments reports whether an error result rejects invalid
+// arguments rather than reporting a failed operation
+func invalidArguments(result *CallToolResponse) bool {
+	return len(result.Content) == 0 || strings.HasPrefix(result.Content[0].Text, "Error: ")
+}
+
+// handleMessageSafely handles a stdio message, answering a panic outside of
+// tool handlers with an internal error instead of ending the process
+func (s *Server) handleMessageSafely(data []byte) (response *Message, err error) {
+	defer s.recoverMessage(data, &response, &err)
+	return s.handleMessage(data)
+}
+
+// callToolSafely runs a stdio tool call with the context registered for it,
+// recovering from panics like handleMessageSafely
+func (s *Server) callToolSafely(ctx context.Context, msg *Message, data []byte) (response *Message, err error) {
+	defer s.recoverMessage(data, &response, &err)
+	return s.callTool(ctx, msg)
+}
+
+// recoverMessage answers a panic while handling a stdio message with an
+// internal error. It must be deferred.
+func (s *Server) recoverMessage(data []byte, response **Message, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	var id interface{}
+	tags := map[string]string{"transport": TransportStdio}
+	if msg, parseErr := ParseMessage(data); parseErr == nil {
+		id = msg.ID
+		tags["method"] = msg.Method
+	}
+	incidentID := s.recordPanic(recovered, tags)
+	if id == nil {
+		// Notifications get no response
+		*response, *err = nil, nil
+		return
+	}
+	*response, *err = NewErrorResponse(id, InternalError, "Internal error", map[string]string{"incident_id": incidentID}), nil
+}
+
+// toolErrorResponse converts a tool execution error into a JSON-RPC error
+func toolErrorResponse(id interface{}, err error) *Message {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return NewErrorResponse(id, InternalError, "Internal error", map[string]string{"incident_id": panicErr.IncidentID})
+	}
+	var busy *BusyError
+	if errors.As(err, &busy) {
+		return NewErrorResponse(id, ServerBusy, "Server busy", map[string]interface{}{
+			"reason":              busy.Reason,
+			"retry_after_seconds": math.Ceil(busy.RetryAfter.Seconds()),
+		})
+	}
+	var disabled *WriteDisabledError
+	if errors.As(err, &disabled) {
+		return NewErrorResponse(id, WriteDisabled, "Write tool disabled", map[string]string{
+			"tool":   disabled.Tool,
+			"reason": disabled.Error(),
+		})
+	}
+	return NewErrorResponse(id, InternalError, "Tool execution failed", err.Error())
+}
+
+// recoveryMiddleware answers a panicking HTTP handler with a structured 500
+// response instead of dropping the connection
+func (h *HTTPBridge) recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				// Deliberate abort of the response, let net/http handle it
+				panic(recovered)
+			}
+			incidentID := h.server.recordPanic(recovered, map[string]string{
+				"transport": TransportHTTP,
+				"method":    r.Method,
+				"path":      r.URL.Path,
+			})
+			h.writeJSONResponse(w, http.StatusInternalServerError, map[string]interface{}{
+				"message":     "Internal server error",
+				"code":        "INTERNAL_ERROR",
+				"incident_id": incidentID,
+			})
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+type recordingReporter struct {
+	panics []interface{}
+	tags   []map[string]string
+	errors []string
+	levels []string
+}
+
+func (r *recordingReporter) ReportPanic(recovered interface{}, stack []byte, tags map[string]string) {
+	r.panics = append(r.panics, recovered)
+	r.tags = append(r.tags, tags)
+}
+
+func (r *recordingReporter) ReportError(err error, level string, tags map[string]string) {
+	r.errors = append(r.errors, err.Error())
+	r.levels = append(r.levels, level)
+	r.tags = append(r.tags, tags)
+}
+
+func panickingTool(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	var players map[string]int
+	players["C0101-123"] = 1
+	return nil, nil
+}
+
+func TestRecoverTool_StdioCall(t *testing.T) {
+	s := newTestServer()
+	reporter := &recordingReporter{}
+	s.SetErrorReporter(reporter)
+	s.tools["broken"] = s.recoverTool("broken", panickingTool)
+
+	response, err := s.handleMessageSafely([]byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"broken","arguments":{}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, InternalError, response.Error.Code)
+	assert.Equal(t, "Internal error", response.Error.Message)
+
+	data, ok := response.Error.Data.(map[string]string)
+	require.True(t, ok)
+	assert.Len(t, data["incident_id"], 16)
+
+	assert.Equal(t, int64(1), s.PanicCount())
+	require.Len(t, reporter.panics, 1)
+	assert.Equal(t, "broken", reporter.tags[0]["tool"])
+	assert.Equal(t, data["incident_id"], reporter.tags[0]["incident_id"])
+}
+
+func TestHandleMessageSafely_Resource(t *testing.T) {
+	s := newTestServer()
+	s.resources["boom"] = func(ctx context.Context, uri string) (*ReadResourceResponse, error) {
+		panic("resource failure")
+	}
+
+	response, err := s.handleMessageSafely([]byte(`{"jsonrpc":"2.0","id":"r1","method":"resources/read","params":{"uri":"boom://x"}}`))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "r1", response.ID)
+	assert.Equal(t, int64(1), s.PanicCount())
+}
+
+func TestHTTPBridge_Recovery(t *testing.T) {
+	s := newTestServer()
+	s.tools["broken"] = s.recoverTool("broken", panickingTool)
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	t.Run("tool call", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader(`{"name":"broken","arguments":{}}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "INTERNAL_ERROR", body["code"])
+		assert.NotEmpty(t, body["incident_id"])
+		assert.NotContains(t, rec.Body.String(), "nil map")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		router.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+			panic("handler failure")
+		})
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INTERNAL_ERROR")
+	})
+
+	assert.Equal(t, int64(2), s.PanicCount())
+}
+
+func TestReportToolErrors(t *testing.T) {
+	s := newTestServer()
+	reporter := &recordingReporter{}
+	s.SetErrorReporter(reporter)
+
+	results := map[string]*CallToolResponse{
+		"invalid":  {Content: []ToolContent{{Type: "text", Text: "Error: club_id is required"}}, IsError: true},
+		"upstream": {Content: []ToolContent{{Type: "text", Text: "Error getting club profile: API error 502"}}, IsError: true},
+		"ok":       {Content: []ToolContent{{Type: "text", Text: "{}"}}},
+	}
+	for name, result := range results {
+		result := result
+		handler := s.reportToolErrors("get_club_profile", func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+			return result, nil
+		})
+		_, err := handler(context.Background(), map[string]interface{}{"club_id": "C0327", "include": "x"})
+		require.NoError(t, err, name)
+	}
+
+	require.Equal(t, []string{"Error getting club profile: API error 502"}, reporter.errors)
+	assert.Equal(t, []string{telemetry.LevelWarning}, reporter.levels)
+	assert.Equal(t, map[string]string{"tool": "get_club_profile", "arguments": "club_id,include"}, reporter.tags[0])
+}
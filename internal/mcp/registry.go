@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CheckToolRegistry reports inconsistencies of the tool registry: tools
+// registered without a schema definition or title, schema definitions of
+// tools that are not registered, and tool lists naming unknown tools
+func CheckToolRegistry() []string {
+	s := &Server{
+		sharedState: &sharedState{
+			logger:   logrus.New(),
+			inflight: make(map[string]context.CancelFunc),
+			ctx:      context.Background(),
+		},
+		tools:     make(map[string]ToolHandler),
+		resources: make(map[string]ResourceHandler),
+	}
+	s.registerTools()
+	schemas := toolSchemas()
+
+	var problems []string
+	for name := range s.tools {
+		if _, ok := schemas[name]; !ok {
+			problems = append(problems, fmt.Sprintf("tool %s is registered without a schema definition", name))
+		}
+		if _, ok := toolTitles[name]; !ok {
+			problems = append(problems, fmt.Sprintf("tool %s has no title", name))
+		}
+	}
+	for name, schema := range schemas {
+		if _, ok := s.tools[name]; !ok {
+			problems = append(problems, fmt.Sprintf("schema definition of %s has no registered tool", name))
+		}
+		if schema.Name != name {
+			problems = append(problems, fmt.Sprintf("schema definition of %s is named %s", name, schema.Name))
+		}
+	}
+	for list, tools := range map[string]map[string]bool{
+		"titles":                keys(toolTitles),
+		"admin tools":           adminTools,
+		"closed world tools":    closedWorldTools,
+		"mutating tools":        mutatingTools,
+		"repeated effect tools": repeatedEffectTools,
+	} {
+		for name := range tools {
+			if _, ok := s.tools[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s name unknown tool %s", list, name))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// keys returns the keys of a map as a set
+func keys(m map[string]string) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for key := range m {
+		set[key] = true
+	}
+	return set
+}
@@ -0,0 +1,15 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckToolRegistry(t *testing.T) {
+	assert.Empty(t, CheckToolRegistry())
+
+	adminTools["get_no_such_stats"] = true
+	defer delete(adminTools, "get_no_such_stats")
+	assert.Equal(t, []string{"admin tools name unknown tool get_no_such_stats"}, CheckToolRegistry())
+}
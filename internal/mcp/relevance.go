@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// umlautReplacer folds German umlauts to their ASCII spelling, so that
+// "Müller" and "Mueller" match
+var umlautReplacer = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+
+// matchTokens normalizes a name for fuzzy matching and splits it into words
+func matchTokens(s string) []string {
+	s = umlautReplacer.Replace(strings.ToLower(s))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns the trigrams of a word padded at both ends
+func trigrams(word string) map[string]int {
+	runes := []rune("  " + word + " ")
+	grams := make(map[string]int, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])]++
+	}
+	return grams
+}
+
+// trigramSimilarity returns the Dice coefficient of the trigrams of two words
+func trigramSimilarity(a, b string) float64 {
+	gramsA, gramsB := trigrams(a), trigrams(b)
+	shared, total := 0, 0
+	for gram, n := range gramsA {
+		shared += min(n, gramsB[gram])
+		total += n
+	}
+	for _, n := range gramsB {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+// wordSimilarity scores how well a query word matches a word of a name,
+// from 0 to 1. Prefixes score high since queries are often typed partially.
+func wordSimilarity(query, word string) float64 {
+	if query == word {
+		return 1
+	}
+	q, w := []rune(query), []rune(word)
+	longest := max(len(q), len(w))
+	score := 1 - float64(levenshtein(query, word))/float64(longest)
+	score = max(score, trigramSimilarity(query, word))
+	if strings.HasPrefix(word, query) {
+		score = max(score, 0.5+0.5*float64(len(q))/float64(len(w)))
+	}
+	return score
+}
+
+// nameRelevance scores a name against a query, from 0 to 1. Every query
+// word is matched with its most similar word of the name.
+func nameRelevance(queryWords []string, name string) float64 {
+	words := matchTokens(name)
+	if len(queryWords) == 0 || len(words) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, q := range queryWords {
+		best := 0.0
+		for _, w := range words {
+			best = max(best, wordSimilarity(q, w))
+		}
+		total += best
+	}
+	return total / float64(len(queryWords))
+}
+
+// searchHit describes a search result for relevance scoring
+type searchHit struct {
+	ids   []string // Exact matches rank first
+	names []string
+}
+
+// relevanceScorer scores search results against a query
+type relevanceScorer struct {
+	words []string
+	id    string
+}
+
+func newRelevanceScorer(query string) relevanceScorer {
+	_, id := api.DetectIDKind(strings.TrimSpace(query))
+	return relevanceScorer{words: matchTokens(query), id: id}
+}
+
+// score returns the relevance of a hit and whether one of its IDs matches
+// the query exactly
+func (r relevanceScorer) score(hit searchHit) (float64, bool) {
+	for _, id := range hit.ids {
+		if id != "" && strings.EqualFold(id, r.id) {
+			return 1, true
+		}
+	}
+	score := 0.0
+	for _, name := range hit.names {
+		score = max(score, nameRelevance(r.words, name))
+	}
+	return math.Round(score*1000) / 1000, false
+}
+
+// rankByRelevance scores items against a query and, if rerank is set,
+// orders them by descending relevance with exact ID matches first. Items of
+// equal relevance keep the upstream order. The scores are returned in the
+// order of the returned items.
+func rankByRelevance[T any](query string, items []T, rerank bool, describe func(T) searchHit) ([]T, []float64) {
+	scorer := newRelevanceScorer(query)
+	type ranked struct {
+		item    T
+		score   float64
+		idMatch bool
+	}
+	hits := make([]ranked, len(items))
+	for i, item := range items {
+		score, idMatch := scorer.score(describe(item))
+		hits[i] = ranked{item: item, score: score, idMatch: idMatch}
+	}
+	if rerank {
+		sort.SliceStable(hits, func(i, j int) bool {
+			if hits[i].idMatch != hits[j].idMatch {
+				return hits[i].idMatch
+			}
+			return hits[i].score > hits[j].score
+		})
+	}
+
+	sorted := make([]T, len(hits))
+	scores := make([]float64, len(hits))
+	for i, hit := range hits {
+		sorted[i], scores[i] = hit.item, hit.score
+	}
+	return sorted, scores
+}
+
+// rankSearchResults annotates the hits of a search with their relevance to
+// the query and orders them by relevance unless a sort field was requested.
+// The ranking covers the returned page only. Searches without a query are
+// left unchanged.
+func rankSearchResults(result *api.SearchResponse, params api.SearchParams) {
+	if strings.TrimSpace(params.Query) == "" {
+		return
+	}
+	rerank := params.SortBy == ""
+	switch data := result.Data.(type) {
+	case []api.PlayerResponse:
+		result.Data = rankPlayers(params.Query, data, rerank)
+	case []api.ClubResponse:
+		result.Data = rankClubs(params.Query, data, rerank)
+	case []api.TournamentResponse:
+		result.Data = rankTournaments(params.Query, data, rerank)
+	}
+}
+
+// RankedPlayer is a player search hit annotated with its relevance
+type RankedPlayer struct {
+	api.PlayerResponse
+	Relevance float64 `json:"relevance"`
+}
+
+// MarshalJSON adds the relevance to the player fields. The embedded player
+// marshals itself, which would drop the relevance otherwise.
+func (p RankedPlayer) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(p.PlayerResponse)
+	if err != nil {
+		return nil, err
+	}
+	relevance, err := json.Marshal(p.Relevance)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}"))
+	if len(data) > 1 {
+		data = append(data, ',')
+	}
+	data = append(data, `"relevance":`...)
+	return append(append(data, relevance...), '}'), nil
+}
+
+// RankedClub is a club search hit annotated with its relevance
+type RankedClub struct {
+	api.ClubResponse
+	Relevance float64 `json:"relevance"`
+}
+
+// RankedTournament is a tournament search hit annotated with its relevance
+type RankedTournament struct {
+	api.TournamentResponse
+	Relevance float64 `json:"relevance"`
+}
+
+// rankPlayers annotates player search hits with their relevance to the query
+func rankPlayers(query string, players []api.PlayerResponse, rerank bool) []RankedPlayer {
+	sorted, scores := rankByRelevance(query, players, rerank, func(p api.PlayerResponse) searchHit {
+		hit := searchHit{
+			ids:   []string{p.ID, p.PKZ},
+			names: []string{p.Name + " " + p.Firstname},
+		}
+		if p.FideID != 0 {
+			hit.ids = append(hit.ids, strconv.Itoa(p.FideID))
+		}
+		return hit
+	})
+	ranked := make([]RankedPlayer, len(sorted))
+	for i, p := range sorted {
+		ranked[i] = RankedPlayer{PlayerResponse: p, Relevance: scores[i]}
+	}
+	return ranked
+}
+
+// rankClubs annotates club search hits with their relevance to the query
+func rankClubs(query string, clubs []api.ClubResponse, rerank bool) []RankedClub {
+	sorted, scores := rankByRelevance(query, clubs, rerank, func(c api.ClubResponse) searchHit {
+		return searchHit{ids: []string{c.ID}, names: []string{c.Name, c.ShortName}}
+	})
+	ranked := make([]RankedClub, len(sorted))
+	for i, c := range sorted {
+		ranked[i] = RankedClub{ClubResponse: c, Relevance: scores[i]}
+	}
+	return ranked
+}
+
+// rankTournaments annotates tournament search hits with their relevance to
+// the query
+func rankTournaments(query string, tournaments []api.TournamentResponse, rerank bool) []RankedTournament {
+	sorted, scores := rankByRelevance(query, tournaments, rerank, func(t api.TournamentResponse) searchHit {
+		return searchHit{ids: []string{t.ID, t.Code}, names: []string{t.Name}}
+	})
+	ranked := make([]RankedTournament, len(sorted))
+	for i, t := range sorted {
+		ranked[i] = RankedTournament{TournamentResponse: t, Relevance: scores[i]}
+	}
+	return ranked
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestWordSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, wordSimilarity("mueller", "mueller"))
+	assert.Greater(t, wordSimilarity("muller", "mueller"), 0.8, "typo")
+	assert.Greater(t, wordSimilarity("muel", "mueller"), 0.75, "prefix")
+	assert.Less(t, wordSimilarity("mueller", "schmidt"), 0.3)
+}
+
+func TestNameRelevance(t *testing.T) {
+	query := matchTokens("Müller, Hans")
+	assert.Equal(t, []string{"mueller", "hans"}, query)
+
+	exact := nameRelevance(query, "Mueller Hans")
+	partial := nameRelevance(query, "Müller Hans-Peter")
+	other := nameRelevance(query, "Müllerschön Anna")
+	assert.Equal(t, 1.0, exact)
+	assert.Equal(t, 1.0, partial, "every query word matches a word of the name")
+	assert.Less(t, other, partial)
+	assert.Equal(t, 0.0, nameRelevance(query, ""))
+}
+
+func TestRankPlayers(t *testing.T) {
+	players := []api.PlayerResponse{
+		{ID: "C0327-12", PKZ: "10001", Name: "Müllerschön", Firstname: "Anna"},
+		{ID: "C0327-13", PKZ: "10002", Name: "Müller", Firstname: "Hans"},
+		{ID: "C0101-1", PKZ: "10003", Name: "Mahler", Firstname: "Hans"},
+	}
+
+	ranked := rankPlayers("Müller Hans", players, true)
+	require.Len(t, ranked, 3)
+	assert.Equal(t, "C0327-13", ranked[0].ID)
+	assert.Equal(t, 1.0, ranked[0].Relevance)
+	assert.GreaterOrEqual(t, ranked[1].Relevance, ranked[2].Relevance)
+
+	// Exact ID matches rank first
+	ranked = rankPlayers("c0101-1", players, true)
+	assert.Equal(t, "C0101-1", ranked[0].ID)
+	assert.Equal(t, 1.0, ranked[0].Relevance)
+
+	ranked = rankPlayers("10001", players, true)
+	assert.Equal(t, "10001", ranked[0].PKZ)
+
+	// Without reranking the upstream order is kept
+	ranked = rankPlayers("Müller Hans", players, false)
+	assert.Equal(t, "C0327-12", ranked[0].ID)
+	assert.Less(t, ranked[0].Relevance, 1.0)
+}
+
+func TestRankedPlayer_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(RankedPlayer{PlayerResponse: api.PlayerResponse{ID: "C0327-13", Gender: "female"}, Relevance: 0.75})
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "C0327-13", fields["id"])
+	assert.Equal(t, "w", fields["gender"])
+	assert.Equal(t, 0.75, fields["relevance"])
+}
+
+func TestHandleSearchClubs_Relevance(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [
+			{"id": "C0301", "name": "Schachfreunde Ulmer Land"},
+			{"id": "C0327", "name": "SC Ulm"},
+			{"id": "C0302", "name": "SV Ulm-Wiblingen"}
+		]}`))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	var response struct {
+		Data []RankedClub `json:"data"`
+	}
+
+	result, err := s.handleSearchClubs(context.Background(), map[string]interface{}{"query": "SC Ulm"})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &response))
+	require.Len(t, response.Data, 3)
+	assert.Equal(t, "C0327", response.Data[0].ID)
+	assert.Equal(t, 1.0, response.Data[0].Relevance)
+
+	// An explicit sort order is kept, hits are still annotated
+	result, err = s.handleSearchClubs(context.Background(), map[string]interface{}{"query": "SC Ulm", "sort_by": "name"})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &response))
+	assert.Equal(t, "C0301", response.Data[0].ID)
+	assert.Greater(t, response.Data[0].Relevance, 0.0)
+
+	// Searches without a query are not annotated
+	result, err = s.handleSearchClubs(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Content[0].Text, "relevance")
+}
@@ -16,6 +16,35 @@ func (s *Server) registerResources() {
 	s.resources["admin"] = s.handleAdminResource
 }
 
+// parseResourceURI splits a resource URI into its scheme and path. The path
+// ends up in requests to the Portal64 API, so query strings, fragments,
+// escapes and dot segments are rejected rather than passed on.
+func parseResourceURI(uri string) (scheme, path string, err error) {
+	scheme, path, ok := strings.Cut(uri, "://")
+	if !ok {
+		return "", "", fmt.Errorf("missing scheme separator")
+	}
+	if scheme == "" {
+		return "", "", fmt.Errorf("empty scheme")
+	}
+	for i, r := range scheme {
+		if !(r >= 'a' && r <= 'z' || i > 0 && (r >= '0' && r <= '9' || r == '+' || r == '.' || r == '-')) {
+			return "", "", fmt.Errorf("invalid character %q in scheme", r)
+		}
+	}
+	for _, r := range path {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune("?#%\\", r) {
+			return "", "", fmt.Errorf("invalid character %q in path", r)
+		}
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return "", "", fmt.Errorf("invalid path segment %q", segment)
+		}
+	}
+	return scheme, path, nil
+}
+
 // handlePlayerResource handles player resource requests
 func (s *Server) handlePlayerResource(ctx context.Context, path string) (*ReadResourceResponse, error) {
 	// Remove leading slash if present
@@ -94,10 +123,14 @@ func (s *Server) handleTournamentResource(ctx context.Context, path string) (*Re
 		return nil, fmt.Errorf("tournament ID is required")
 	}
 
+	if path == "upcoming" || strings.HasPrefix(path, "upcoming/") {
+		return s.handleUpcomingTournamentsResource(ctx, strings.TrimPrefix(strings.TrimPrefix(path, "upcoming"), "/"))
+	}
+
 	tournamentID := path
 	
 	// Get tournament details
-	tournament, err := s.apiClient.GetTournamentDetails(ctx, tournamentID)
+	tournament, err := s.tournamentDetails(ctx, tournamentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tournament details: %w", err)
 	}
@@ -115,13 +148,41 @@ func (s *Server) handleTournamentResource(ctx context.Context, path string) (*Re
 		}},
 	}, nil
 }
+
+// handleUpcomingTournamentsResource serves tournaments://upcoming and
+// tournaments://upcoming/{region} with the tournaments of the next 30 days
+func (s *Server) handleUpcomingTournamentsResource(ctx context.Context, region string) (*ReadResourceResponse, error) {
+	result, err := s.upcomingTournaments(ctx, defaultUpcomingDays, defaultUpcomingLimit, region, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming tournaments: %w", err)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize upcoming tournaments: %w", err)
+	}
+
+	uri := "tournaments://upcoming"
+	if region != "" {
+		uri += "/" + region
+	}
+
+	return &ReadResourceResponse{
+		Contents: []ResourceContent{{
+			URI:      uri,
+			MimeType: "application/json",
+			Text:     string(data),
+		}},
+	}, nil
+}
+
 // handleAddressResource handles address resource requests
 func (s *Server) handleAddressResource(ctx context.Context, path string) (*ReadResourceResponse, error) {
 	path = strings.TrimPrefix(path, "/")
 	
 	if path == "regions" {
 		// Get list of regions
-		regions, err := s.apiClient.GetRegions(ctx)
+		regions, err := s.regions(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get regions: %w", err)
 		}
@@ -153,7 +214,7 @@ func (s *Server) handleAddressResource(ctx context.Context, path string) (*ReadR
 	}
 
 	// Get regional addresses
-	addresses, err := s.apiClient.GetRegionAddresses(ctx, region, addressType)
+	addresses, err := s.regionAddresses(ctx, region, addressType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get region addresses: %w", err)
 	}
@@ -220,6 +281,27 @@ func (s *Server) handleAdminResource(ctx context.Context, path string) (*ReadRes
 			}},
 		}, nil
 
+	case "anomalies":
+		anomalyLog := s.apiClient.AnomalyLog()
+		if anomalyLog == nil {
+			return nil, fmt.Errorf("anomaly detection is disabled (api.anomalies.enabled)")
+		}
+
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"anomalies": anomalyLog.Anomalies(),
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize anomalies: %w", err)
+		}
+
+		return &ReadResourceResponse{
+			Contents: []ResourceContent{{
+				URI:      "admin://anomalies",
+				MimeType: "application/json",
+				Text:     string(data),
+			}},
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unknown admin resource: %s", path)
 	}
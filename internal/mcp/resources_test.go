@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceURI(t *testing.T) {
+	testCases := []struct {
+		uri    string
+		scheme string
+		path   string
+		err    bool
+	}{
+		{uri: "players://C0327-297", scheme: "players", path: "C0327-297"},
+		{uri: "clubs://C0327/profile", scheme: "clubs", path: "C0327/profile"},
+		{uri: "tournaments://upcoming", scheme: "tournaments", path: "upcoming"},
+		{uri: "addresses:///Württemberg", scheme: "addresses", path: "/Württemberg"},
+		{uri: "admin://", scheme: "admin", path: ""},
+		{uri: "players", err: true},
+		{uri: "://C0327", err: true},
+		{uri: "Players://C0327", err: true},
+		{uri: "1players://C0327", err: true},
+		{uri: "players://C0327?x=1", err: true},
+		{uri: "players://C0327#x", err: true},
+		{uri: "players://C0327%2F..", err: true},
+		{uri: "players://../clubs/C0327", err: true},
+		{uri: "clubs://C0327/./profile", err: true},
+		{uri: "players://C0327\\..", err: true},
+		{uri: "players://C0327\n", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.uri, func(t *testing.T) {
+			scheme, path, err := parseResourceURI(tc.uri)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.scheme, scheme)
+			assert.Equal(t, tc.path, path)
+		})
+	}
+}
+
+func FuzzParseResourceURI(f *testing.F) {
+	for _, seed := range []string{"players://C0327-297", "clubs://C0327/profile", "addresses://1/club", "admin://health", "a://../b", "x://%00", "://"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, uri string) {
+		scheme, path, err := parseResourceURI(uri)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, uri, scheme+"://"+path)
+		assert.NotEmpty(t, scheme)
+		assert.NotContains(t, scheme, ":")
+		assert.False(t, strings.ContainsAny(path, "?#%\\"), "path %q", path)
+		for _, segment := range strings.Split(path, "/") {
+			assert.NotContains(t, []string{".", ".."}, segment)
+		}
+	})
+}
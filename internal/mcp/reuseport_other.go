@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd
+
+package mcp
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+)
+
+// reusePortControl reports that SO_REUSEPORT is not supported
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	return fmt.Errorf("mcp.http.reuse_port is not supported on %s", runtime.GOOS)
+}
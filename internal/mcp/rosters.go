@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+// rosterCache caches the member lists of clubs. Stale rosters are served
+// while they are refreshed in the background.
+type rosterCache struct {
+	mu      sync.Mutex
+	entries map[string]*rosterEntry
+	stats   RosterCacheStats
+}
+
+type rosterEntry struct {
+	players    []api.PlayerResponse
+	truncated  bool // The roster has more members than were fetched
+	size       int64
+	fetched    time.Time
+	used       time.Time
+	refreshing bool
+}
+
+// RosterCacheStats reports the use of the club roster cache
+type RosterCacheStats struct {
+	Clubs     int   `json:"clubs"`
+	Hits      int64 `json:"hits"`
+	StaleHits int64 `json:"stale_hits"` // Served while refreshed in the background
+	Misses    int64 `json:"misses"`
+	Refreshes int64 `json:"refreshes"` // Background refreshes started
+}
+
+// CacheStats combines the statistics of the Portal64 API cache with those
+// of the club roster cache, the response cache and the memory use of all
+// in-memory stores
+type CacheStats struct {
+	*api.CacheStatsResponse
+	Rosters RosterCacheStats `json:"rosters"`
+	// Responses reports the cache of upstream responses, nil if it is not
+	// configured
+	Responses *api.ResponseCacheStats `json:"responses,omitempty"`
+	Memory    *memory.Stats           `json:"memory,omitempty"`
+}
+
+// get returns the cached roster of a club and its age
+func (c *rosterCache) get(clubID string, now time.Time) (*rosterEntry, time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[clubID]
+	if !ok {
+		return nil, 0, false
+	}
+	entry.used = now
+	return entry, now.Sub(entry.fetched), true
+}
+
+// put stores the roster of a club, evicting the least recently used
+// rosters beyond maxClubs
+func (c *rosterCache) put(clubID string, players []api.PlayerResponse, truncated bool, now time.Time, maxClubs int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*rosterEntry)
+	}
+	c.entries[clubID] = &rosterEntry{players: players, truncated: truncated, size: memory.EstimateSize(players), fetched: now, used: now}
+
+	for maxClubs > 0 && len(c.entries) > maxClubs {
+		oldest := ""
+		for id, entry := range c.entries {
+			if oldest == "" || entry.used.Before(c.entries[oldest].used) {
+				oldest = id
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// startRefresh marks a roster as being refreshed, returning false if it
+// already is
+func (c *rosterCache) startRefresh(clubID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[clubID]
+	if !ok || entry.refreshing {
+		return false
+	}
+	entry.refreshing = true
+	c.stats.Refreshes++
+	return true
+}
+
+// endRefresh clears the refresh mark of a roster whose refresh failed
+func (c *rosterCache) endRefresh(clubID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[clubID]; ok {
+		entry.refreshing = false
+	}
+}
+
+// count records a cache lookup
+func (c *rosterCache) count(hits, staleHits, misses int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Hits += hits
+	c.stats.StaleHits += staleHits
+	c.stats.Misses += misses
+}
+
+// snapshot returns the cache statistics
+func (c *rosterCache) snapshot() RosterCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Clubs = len(c.entries)
+	return stats
+}
+
+// clubRoster returns all members of a club. Rosters younger than
+// rosters.ttl are served from the cache; older ones up to rosters.max_age
+// are served as well and refreshed in the background. If fetching an
+// expired roster fails, the cached one is returned with a warning. Like
+// walkClubPlayers, it reports whether the roster was truncated.
+func (s *Server) clubRoster(ctx context.Context, clubID string) ([]api.PlayerResponse, bool, error) {
+	if s.config == nil || s.config.Rosters.TTL <= 0 {
+		return s.walkClubPlayers(ctx, clubID, api.SearchParams{})
+	}
+	cfg := s.config.Rosters
+
+	cached, age, ok := s.rosters.get(clubID, time.Now())
+	switch {
+	case ok && age < cfg.TTL:
+		s.rosters.count(1, 0, 0)
+		return append([]api.PlayerResponse(nil), cached.players...), cached.truncated, nil
+	case ok && age < cfg.MaxAge:
+		s.rosters.count(0, 1, 0)
+		if s.rosters.startRefresh(clubID) {
+			go s.refreshRoster(clubID)
+		}
+		return append([]api.PlayerResponse(nil), cached.players...), cached.truncated, nil
+	}
+
+	s.rosters.count(0, 0, 1)
+	players, truncated, err := s.walkClubPlayers(ctx, clubID, api.SearchParams{})
+	if err != nil {
+		if ok {
+			addWarning(ctx, fmt.Sprintf("fetching the club roster of %s failed, using the roster cached %s ago: %v", clubID, age.Round(time.Minute), err))
+			return append([]api.PlayerResponse(nil), cached.players...), cached.truncated, nil
+		}
+		return nil, false, err
+	}
+	s.rosters.put(clubID, players, truncated, time.Now(), cfg.MaxClubs)
+	return append([]api.PlayerResponse(nil), players...), truncated, nil
+}
+
+// refreshRoster fetches the roster of a club in the background
+func (s *Server) refreshRoster(clubID string) {
+	players, truncated, err := s.walkClubPlayers(withProgress(s.ctx, nil), clubID, api.SearchParams{})
+	if err != nil {
+		s.logger.WithError(err).WithField("club_id", clubID).Debug("Failed to refresh club roster")
+		s.rosters.endRefresh(clubID)
+		return
+	}
+	s.rosters.put(clubID, players, truncated, time.Now(), s.config.Rosters.MaxClubs)
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+// newRosterTestServer serves club rosters whose single member is named
+// after the number of requests so far. While failing is set, the upstream
+// answers 500.
+func newRosterTestServer(t *testing.T, rosters config.RostersConfig) (*Server, *int32, *atomic.Bool) {
+	var requests int32
+	var failing atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"data": [{"id": "C0327-1", "name": "Version %d"}], "pagination": {"total": 1}}`, n)
+	}))
+	t.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.config = &config.Config{Rosters: rosters}
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	return s, &requests, &failing
+}
+
+// ageRoster moves the fetch time of a cached roster into the past
+func ageRoster(s *Server, clubID string, age time.Duration) {
+	s.rosters.mu.Lock()
+	defer s.rosters.mu.Unlock()
+	s.rosters.entries[clubID].fetched = time.Now().Add(-age)
+}
+
+func rosterName(t *testing.T, s *Server, ctx context.Context, clubID string) string {
+	players, _, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, players, 1)
+	return players[0].Name
+}
+
+func TestClubRoster_Cache(t *testing.T) {
+	s, requests, _ := newRosterTestServer(t, config.RostersConfig{TTL: time.Hour, MaxAge: 24 * time.Hour})
+	ctx := context.Background()
+
+	assert.Equal(t, "Version 1", rosterName(t, s, ctx, "C0327"))
+	assert.Equal(t, "Version 1", rosterName(t, s, ctx, "C0327"))
+	assert.EqualValues(t, 1, atomic.LoadInt32(requests))
+
+	// Filtered member lists bypass the cache
+	_, _, err := s.fetchAllClubPlayers(ctx, "C0327", api.SearchParams{Query: "Version"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(requests))
+
+	// A stale roster is served and refreshed in the background
+	ageRoster(s, "C0327", 2*time.Hour)
+	assert.Equal(t, "Version 1", rosterName(t, s, ctx, "C0327"))
+	assert.Eventually(t, func() bool {
+		cached, _, _ := s.rosters.get("C0327", time.Now())
+		return cached.players[0].Name == "Version 3"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "Version 3", rosterName(t, s, ctx, "C0327"))
+
+	// An expired roster is fetched before it is served
+	ageRoster(s, "C0327", 25*time.Hour)
+	assert.Equal(t, "Version 4", rosterName(t, s, ctx, "C0327"))
+
+	assert.Equal(t, RosterCacheStats{Clubs: 1, Hits: 2, StaleHits: 1, Misses: 2, Refreshes: 1}, s.rosters.snapshot())
+}
+
+func TestClubRoster_ExpiredFallback(t *testing.T) {
+	s, _, failing := newRosterTestServer(t, config.RostersConfig{TTL: time.Hour, MaxAge: 24 * time.Hour})
+	assert.Equal(t, "Version 1", rosterName(t, s, context.Background(), "C0327"))
+
+	failing.Store(true)
+	ageRoster(s, "C0327", 25*time.Hour)
+	ctx, collector := withWarnings(context.Background())
+	assert.Equal(t, "Version 1", rosterName(t, s, ctx, "C0327"))
+	require.Len(t, collector.warnings, 1)
+	assert.Contains(t, collector.warnings[0], "using the roster cached 25h0m0s ago")
+
+	_, _, err := s.fetchAllClubPlayers(ctx, "C0328", api.SearchParams{})
+	assert.Error(t, err)
+}
+
+func TestClubRoster_Eviction(t *testing.T) {
+	s, _, _ := newRosterTestServer(t, config.RostersConfig{TTL: time.Hour, MaxAge: time.Hour, MaxClubs: 2})
+	ctx := context.Background()
+
+	rosterName(t, s, ctx, "C0301")
+	rosterName(t, s, ctx, "C0302")
+	rosterName(t, s, ctx, "C0301")
+	rosterName(t, s, ctx, "C0303")
+
+	_, _, ok := s.rosters.get("C0302", time.Now())
+	assert.False(t, ok, "least recently used roster is evicted")
+	_, _, ok = s.rosters.get("C0301", time.Now())
+	assert.True(t, ok)
+	assert.Equal(t, 2, s.rosters.snapshot().Clubs)
+}
+
+func TestClubRoster_Disabled(t *testing.T) {
+	s, requests, _ := newRosterTestServer(t, config.RostersConfig{})
+	rosterName(t, s, context.Background(), "C0327")
+	rosterName(t, s, context.Background(), "C0327")
+	assert.EqualValues(t, 2, atomic.LoadInt32(requests))
+	assert.Zero(t, s.rosters.snapshot().Clubs)
+}
@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+// priorityArgument overrides the priority class of a single tool call
+const priorityArgument = "priority"
+
+// Priority classes of tool calls
+const (
+	PriorityInteractive = "interactive"
+	PriorityBatch       = "batch"
+)
+
+// toolPriority orders tool calls for load shedding and the concurrency
+// limiter
+type toolPriority int
+
+const (
+	// priorityBatch calls fan out to many upstream requests; they are shed
+	// first and wait for interactive calls
+	priorityBatch toolPriority = iota
+	priorityInteractive
+	// priorityCritical calls are never shed or queued, so that operators can
+	// diagnose an overloaded server
+	priorityCritical
+)
+
+// defaultBatchTools are expensive aggregations that can wait for
+// user-facing queries
+var defaultBatchTools = map[string]bool{
+	"export_club_data":            true,
+	"find_possible_duplicates":    true,
+	"get_club_officials":          true,
+	"get_club_youth_statistics":   true,
+	"get_organizer_tournaments":   true,
+	"get_player_percentile":       true,
+	"get_reactivation_candidates": true,
+	"get_region_activity":         true,
+	"get_region_statistics":       true,
+	"get_tournament_series":       true,
+	"search_officials":            true,
+}
+
+// batchToolSet returns the batch tools of a configuration
+func batchToolSet(cfg config.SchedulingConfig) map[string]bool {
+	tools := make(map[string]bool, len(defaultBatchTools)+len(cfg.BatchTools))
+	for name := range defaultBatchTools {
+		tools[name] = true
+	}
+	for _, name := range cfg.BatchTools {
+		tools[name] = true
+	}
+	for _, name := range cfg.InteractiveTools {
+		delete(tools, name)
+	}
+	return tools
+}
+
+// priorityOf returns the priority class of a tool
+func (s *Server) priorityOf(name string) toolPriority {
+	batchTools := s.batchTools
+	if batchTools == nil {
+		batchTools = defaultBatchTools
+	}
+	switch {
+	case adminTools[name]:
+		return priorityCritical
+	case batchTools[name]:
+		return priorityBatch
+	default:
+		return priorityInteractive
+	}
+}
+
+// callPriority returns the priority of a tool call, taking the priority
+// argument or parameter into account. Administrative tools keep their
+// priority.
+func (s *Server) callPriority(name, override string) (toolPriority, error) {
+	priority := s.priorityOf(name)
+	switch override {
+	case "":
+	case PriorityInteractive:
+		if priority != priorityCritical {
+			priority = priorityInteractive
+		}
+	case PriorityBatch:
+		if priority != priorityCritical {
+			priority = priorityBatch
+		}
+	default:
+		return priority, fmt.Errorf("priority must be %s or %s", PriorityInteractive, PriorityBatch)
+	}
+	return priority, nil
+}
+
+// withPriorityArgument adds the priority argument to the definition of a
+// tool that can be queued
+func withPriorityArgument(tool Tool) Tool {
+	if adminTools[tool.Name] {
+		return tool
+	}
+	properties := make(map[string]interface{}, len(tool.InputSchema.Properties)+1)
+	for k, v := range tool.InputSchema.Properties {
+		properties[k] = v
+	}
+	properties[priorityArgument] = map[string]interface{}{
+		"type":        "string",
+		"description": "Scheduling priority of the call: interactive calls go before batch calls while the server is busy (default depends on the tool)",
+		"enum":        []string{PriorityInteractive, PriorityBatch},
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// SchedulingStats reports the state of the concurrency limiter
+type SchedulingStats struct {
+	MaxConcurrent int   `json:"max_concurrent"`
+	Running       int   `json:"running"`
+	Queued        int   `json:"queued"`       // Waiting calls of both classes
+	QueuedBatch   int   `json:"queued_batch"` // Waiting batch calls
+	TimedOut      int64 `json:"timed_out"`    // Calls rejected after queue_timeout
+}
+
+// waiter is a tool call waiting for a slot of the concurrency limiter
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// concurrencyLimiter bounds the number of running tool calls. Calls beyond
+// the limit wait in one queue per priority class; a freed slot goes to the
+// longest waiting interactive call, then to batch calls. It is shared by
+// the profile servers.
+type concurrencyLimiter struct {
+	max          int
+	queueTimeout time.Duration
+	retryAfter   time.Duration
+
+	mu       sync.Mutex
+	running  int
+	queues   [priorityCritical][]*waiter
+	timedOut int64
+}
+
+// newConcurrencyLimiter returns a concurrency limiter, nil if tool calls
+// are unlimited
+func newConcurrencyLimiter(cfg config.SchedulingConfig, retryAfter time.Duration) *concurrencyLimiter {
+	if cfg.MaxConcurrent <= 0 {
+		return nil
+	}
+	return &concurrencyLimiter{max: cfg.MaxConcurrent, queueTimeout: cfg.QueueTimeout, retryAfter: retryAfter}
+}
+
+// acquire waits for a slot. It fails with a BusyError once the queue
+// timeout passes and with the context error if the call is cancelled. An
+// acquired slot must be freed with the returned release function.
+func (l *concurrencyLimiter) acquire(ctx context.Context, priority toolPriority) (func(), error) {
+	if priority == priorityCritical {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	if l.running < l.max {
+		l.running++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	w := &waiter{ready: make(chan struct{})}
+	l.queues[priority] = append(l.queues[priority], w)
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+		return l.release, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = &BusyError{
+			Reason:     fmt.Sprintf("no free slot for %s (%d tool calls running)", l.queueTimeout, l.max),
+			RetryAfter: l.retryAfter,
+		}
+	}
+
+	l.mu.Lock()
+	if w.granted {
+		// The slot was handed over while giving up, pass it on
+		l.mu.Unlock()
+		l.release()
+		return nil, err
+	}
+	queue := l.queues[priority]
+	for i, queued := range queue {
+		if queued == w {
+			l.queues[priority] = append(queue[:i:i], queue[i+1:]...)
+			break
+		}
+	}
+	if _, busy := err.(*BusyError); busy {
+		l.timedOut++
+	}
+	l.mu.Unlock()
+	return nil, err
+}
+
+// release frees a slot, handing it to the next waiting call
+func (l *concurrencyLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for priority := priorityInteractive; priority >= priorityBatch; priority-- {
+		if queue := l.queues[priority]; len(queue) > 0 {
+			next := queue[0]
+			l.queues[priority] = queue[1:]
+			next.granted = true
+			close(next.ready)
+			return
+		}
+	}
+	l.running--
+}
+
+// stats returns the state of the limiter
+func (l *concurrencyLimiter) stats() SchedulingStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return SchedulingStats{
+		MaxConcurrent: l.max,
+		Running:       l.running,
+		Queued:        len(l.queues[priorityBatch]) + len(l.queues[priorityInteractive]),
+		QueuedBatch:   len(l.queues[priorityBatch]),
+		TimedOut:      l.timedOut,
+	}
+}
+
+// splitPriorityArg removes the priority argument from the arguments of a
+// tool call and returns its value
+func splitPriorityArg(args map[string]interface{}) (string, map[string]interface{}, error) {
+	value, ok := args[priorityArgument]
+	if !ok {
+		return "", args, nil
+	}
+	priority, isString := value.(string)
+	if !isString {
+		return "", nil, fmt.Errorf("priority must be %s or %s", PriorityInteractive, PriorityBatch)
+	}
+	rest := make(map[string]interface{}, len(args)-1)
+	for k, v := range args {
+		if k != priorityArgument {
+			rest[k] = v
+		}
+	}
+	return priority, rest, nil
+}
+
+// requestPriority returns the priority parameter of a REST request
+func requestPriority(r *http.Request) string {
+	return r.URL.Query().Get(priorityArgument)
+}
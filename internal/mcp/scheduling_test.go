@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestPriorityOf(t *testing.T) {
+	s := newTestServer()
+	assert.Equal(t, priorityCritical, s.priorityOf("get_runtime_stats"))
+	assert.Equal(t, priorityBatch, s.priorityOf("get_region_statistics"))
+	assert.Equal(t, priorityInteractive, s.priorityOf("search_players"))
+
+	s.batchTools = batchToolSet(config.SchedulingConfig{
+		BatchTools:       []string{"get_club_statistics"},
+		InteractiveTools: []string{"search_officials"},
+	})
+	assert.Equal(t, priorityBatch, s.priorityOf("get_club_statistics"))
+	assert.Equal(t, priorityInteractive, s.priorityOf("search_officials"))
+	assert.Equal(t, priorityBatch, s.priorityOf("export_club_data"))
+}
+
+func TestCallPriority(t *testing.T) {
+	s := newTestServer()
+
+	priority, err := s.callPriority("search_players", PriorityBatch)
+	require.NoError(t, err)
+	assert.Equal(t, priorityBatch, priority)
+
+	priority, err = s.callPriority("get_region_statistics", PriorityInteractive)
+	require.NoError(t, err)
+	assert.Equal(t, priorityInteractive, priority)
+
+	priority, err = s.callPriority("check_api_health", PriorityBatch)
+	require.NoError(t, err)
+	assert.Equal(t, priorityCritical, priority)
+
+	_, err = s.callPriority("search_players", "urgent")
+	assert.EqualError(t, err, "priority must be interactive or batch")
+}
+
+func TestWithPriorityArgument(t *testing.T) {
+	s := newTestServer()
+	assert.Contains(t, s.GetToolDefinition("search_players").InputSchema.Properties, priorityArgument)
+	assert.NotContains(t, s.GetToolDefinition("get_cache_stats").InputSchema.Properties, priorityArgument)
+}
+
+// waitQueued waits until the limiter has n waiting calls
+func waitQueued(t *testing.T, l *concurrencyLimiter, n int) {
+	require.Eventually(t, func() bool { return l.stats().Queued == n }, time.Second, time.Millisecond)
+}
+
+func TestConcurrencyLimiter_InteractiveFirst(t *testing.T) {
+	limiter := newConcurrencyLimiter(config.SchedulingConfig{MaxConcurrent: 1}, 0)
+	release, err := limiter.acquire(context.Background(), priorityInteractive)
+	require.NoError(t, err)
+
+	order := make(chan string, 3)
+	start := func(name string, priority toolPriority) {
+		go func() {
+			release, err := limiter.acquire(context.Background(), priority)
+			if assert.NoError(t, err) {
+				order <- name
+				release()
+			}
+		}()
+	}
+	start("batch", priorityBatch)
+	waitQueued(t, limiter, 1)
+	start("interactive", priorityInteractive)
+	waitQueued(t, limiter, 2)
+
+	// Administrative calls bypass the queue
+	releaseAdmin, err := limiter.acquire(context.Background(), priorityCritical)
+	require.NoError(t, err)
+	releaseAdmin()
+
+	stats := limiter.stats()
+	assert.Equal(t, SchedulingStats{MaxConcurrent: 1, Running: 1, Queued: 2, QueuedBatch: 1}, stats)
+
+	release()
+	assert.Equal(t, "interactive", <-order)
+	assert.Equal(t, "batch", <-order)
+	require.Eventually(t, func() bool { return limiter.stats().Running == 0 }, time.Second, time.Millisecond)
+}
+
+func TestConcurrencyLimiter_Timeout(t *testing.T) {
+	limiter := newConcurrencyLimiter(config.SchedulingConfig{MaxConcurrent: 1, QueueTimeout: 20 * time.Millisecond}, 5*time.Second)
+	release, err := limiter.acquire(context.Background(), priorityInteractive)
+	require.NoError(t, err)
+	defer release()
+
+	_, err = limiter.acquire(context.Background(), priorityBatch)
+	var busy *BusyError
+	require.True(t, errors.As(err, &busy))
+	assert.Equal(t, "no free slot for 20ms (1 tool calls running)", busy.Reason)
+	assert.Equal(t, 5*time.Second, busy.RetryAfter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.acquire(ctx, priorityInteractive)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	stats := limiter.stats()
+	assert.Equal(t, 0, stats.Queued)
+	assert.Equal(t, int64(1), stats.TimedOut)
+
+	assert.Nil(t, newConcurrencyLimiter(config.SchedulingConfig{}, 0))
+}
+
+func TestShedLoad_PriorityArgument(t *testing.T) {
+	s := newTestServer()
+	s.limiter = newConcurrencyLimiter(config.SchedulingConfig{MaxConcurrent: 1}, 0)
+	handler := s.shedLoad("search_players", func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		assert.NotContains(t, args, priorityArgument)
+		assert.Equal(t, 1, s.limiter.stats().Running)
+		return &CallToolResponse{Content: []ToolContent{{Type: "text", Text: `{}`}}}, nil
+	})
+
+	result, err := handler(context.Background(), map[string]interface{}{"query": "Meyer", "priority": "batch"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, 0, s.limiter.stats().Running)
+
+	result, err = handler(context.Background(), map[string]interface{}{"priority": 1})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Error: priority must be interactive or batch", result.Content[0].Text)
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// searchAllTypes are the entity types searched by search_all, in the order
+// groups of equal relevance are returned
+var searchAllTypes = []string{"players", "clubs", "tournaments"}
+
+const (
+	// defaultSearchAllLimit is the default number of hits per type
+	defaultSearchAllLimit = 10
+	// maxSearchAllLimit bounds the number of hits per type
+	maxSearchAllLimit = 50
+)
+
+// SearchGroup holds the hits of one entity type of a universal search
+type SearchGroup struct {
+	Type         string      `json:"type"`
+	Total        int         `json:"total"`
+	TopRelevance float64     `json:"top_relevance"`
+	Hits         interface{} `json:"hits"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// UniversalSearchResult is the result of search_all. Groups are ordered by
+// the relevance of their best hit.
+type UniversalSearchResult struct {
+	Query  string        `json:"query"`
+	Groups []SearchGroup `json:"groups"`
+}
+
+// searchFunc returns the API search of an entity type
+func (s *Server) searchFunc(ctx context.Context, entityType string) func(api.SearchParams) (*api.SearchResponse, error) {
+	switch entityType {
+	case "players":
+		return func(params api.SearchParams) (*api.SearchResponse, error) {
+			return s.apiClient.SearchPlayers(ctx, params)
+		}
+	case "clubs":
+		return func(params api.SearchParams) (*api.SearchResponse, error) {
+			return s.apiClient.SearchClubs(ctx, params)
+		}
+	default:
+		return func(params api.SearchParams) (*api.SearchResponse, error) {
+			return s.apiClient.SearchTournaments(ctx, params)
+		}
+	}
+}
+
+// topRelevance returns the relevance of the best hit of a ranked search
+func topRelevance(data interface{}) float64 {
+	switch hits := data.(type) {
+	case []RankedPlayer:
+		if len(hits) > 0 {
+			return hits[0].Relevance
+		}
+	case []RankedClub:
+		if len(hits) > 0 {
+			return hits[0].Relevance
+		}
+	case []RankedTournament:
+		if len(hits) > 0 {
+			return hits[0].Relevance
+		}
+	}
+	return 0
+}
+
+// searchAll searches all given entity types concurrently. Types whose search
+// fails are returned with an error.
+func (s *Server) searchAll(ctx context.Context, query string, types []string, limit int) *UniversalSearchResult {
+	groups := make([]SearchGroup, len(types))
+	var wg sync.WaitGroup
+	for i, entityType := range types {
+		wg.Add(1)
+		go func(group *SearchGroup, entityType string) {
+			defer wg.Done()
+			group.Type = entityType
+
+			result, params, err := searchNormalized(ctx, api.SearchParams{Query: query, Limit: limit}, s.searchFunc(ctx, entityType))
+			if err != nil {
+				group.Error = err.Error()
+				group.Hits = []interface{}{}
+				return
+			}
+			rankSearchResults(result, params)
+			group.Total = result.Pagination.Total
+			group.Hits = result.Data
+			group.TopRelevance = topRelevance(result.Data)
+		}(&groups[i], entityType)
+	}
+	wg.Wait()
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].TopRelevance > groups[j].TopRelevance
+	})
+	return &UniversalSearchResult{Query: query, Groups: groups}
+}
+
+// handleSearchAll handles combined player, club and tournament searches
+func (s *Server) handleSearchAll(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: query is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	limit := defaultSearchAllLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = min(int(l), maxSearchAllLimit)
+	}
+
+	types := searchAllTypes
+	if requested, ok := args["types"].([]interface{}); ok && len(requested) > 0 {
+		types = nil
+		seen := make(map[string]bool)
+		for _, t := range requested {
+			name, _ := t.(string)
+			if name != "players" && name != "clubs" && name != "tournaments" {
+				return &CallToolResponse{
+					Content: []ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Error: invalid type %v (use players, clubs or tournaments)", t),
+					}},
+					IsError: true,
+				}, nil
+			}
+			if !seen[name] {
+				seen[name] = true
+				types = append(types, name)
+			}
+		}
+	}
+
+	result := s.searchAll(ctx, query, types, limit)
+
+	var failed []string
+	for _, group := range result.Groups {
+		if group.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", group.Type, group.Error))
+		}
+	}
+	if len(failed) == len(result.Groups) {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error searching: %s", strings.Join(failed, "; ")),
+			}},
+			IsError: true,
+		}, nil
+	}
+	for _, f := range failed {
+		addWarning(ctx, "search failed for "+f)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func newSearchAllTestServer(t *testing.T) *Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/players":
+			w.Write([]byte(`{"data": [{"id": "C0327-13", "name": "Ulmer", "firstname": "Hans"}], "pagination": {"total": 1}}`))
+		case "/api/v1/clubs":
+			w.Write([]byte(`{"data": [
+				{"id": "C0301", "name": "Schachfreunde Ulmer Land"},
+				{"id": "C0327", "name": "SC Ulm"}
+			], "pagination": {"total": 2}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	return s
+}
+
+func TestHandleSearchAll(t *testing.T) {
+	s := newSearchAllTestServer(t)
+
+	ctx, collector := withWarnings(context.Background())
+	result, err := s.handleSearchAll(ctx, map[string]interface{}{"query": "SC Ulm"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+
+	var response struct {
+		Query  string `json:"query"`
+		Groups []struct {
+			Type         string  `json:"type"`
+			Total        int     `json:"total"`
+			TopRelevance float64 `json:"top_relevance"`
+			Hits         []struct {
+				ID string `json:"id"`
+			} `json:"hits"`
+			Error string `json:"error"`
+		} `json:"groups"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &response))
+	require.Len(t, response.Groups, 3)
+
+	clubs := response.Groups[0]
+	assert.Equal(t, "clubs", clubs.Type)
+	assert.Equal(t, 2, clubs.Total)
+	assert.Equal(t, 1.0, clubs.TopRelevance)
+	require.Len(t, clubs.Hits, 2)
+	assert.Equal(t, "C0327", clubs.Hits[0].ID)
+
+	assert.Equal(t, "players", response.Groups[1].Type)
+	assert.Equal(t, "tournaments", response.Groups[2].Type)
+	assert.NotEmpty(t, response.Groups[2].Error)
+	assert.Empty(t, response.Groups[2].Hits)
+	assert.Len(t, collector.warnings, 1)
+}
+
+func TestHandleSearchAll_Arguments(t *testing.T) {
+	s := newSearchAllTestServer(t)
+
+	result, err := s.handleSearchAll(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = s.handleSearchAll(context.Background(), map[string]interface{}{"query": "Ulm", "types": []interface{}{"people"}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "invalid type people")
+
+	result, err = s.handleSearchAll(context.Background(), map[string]interface{}{"query": "Ulm", "types": []interface{}{"clubs", "clubs"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	var response UniversalSearchResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &response))
+	require.Len(t, response.Groups, 1)
+	assert.Equal(t, "clubs", response.Groups[0].Type)
+
+	// All searches failing is an error
+	result, err = s.handleSearchAll(context.Background(), map[string]interface{}{"query": "Ulm", "types": []interface{}{"tournaments"}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "Error searching: tournaments:")
+}
@@ -0,0 +1,396 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+)
+
+const (
+	// seriesCacheTTL is how long a computed tournament series is cached
+	seriesCacheTTL = 6 * time.Hour
+	// seriesSearchPages limits the tournament search pages scanned per query
+	seriesSearchPages = 5
+	// defaultSeriesEditions is the number of most recent editions per series
+	// whose winners are looked up
+	defaultSeriesEditions = 10
+	// maxSeriesResults limits the number of series returned for a query
+	maxSeriesResults = 5
+	// seriesConcurrency limits parallel tournament detail requests
+	seriesConcurrency = 4
+)
+
+var (
+	// seriesYearPattern matches years and seasons like 2023, 2023/24 or 2023-2024
+	seriesYearPattern = regexp.MustCompile(`\b(19|20)\d{2}([/-]\d{2,4})?\b`)
+	// seriesEditionPattern matches edition numbers like "25." or "XXV."
+	seriesEditionPattern = regexp.MustCompile(`(?i)(^|\s)(\d{1,3}|[ivxlc]+)\.(\s|$)`)
+	seriesNonWordPattern = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// seriesKey normalizes a tournament name to the name of its series by
+// removing years, seasons and edition numbers, so "25. Ulm Open 2024" and
+// "Ulm Open 2023" share the key "ulm open"
+func seriesKey(name string) string {
+	key := seriesYearPattern.ReplaceAllString(name, " ")
+	key = seriesEditionPattern.ReplaceAllString(key, " ")
+	key = seriesNonWordPattern.ReplaceAllString(strings.ToLower(key), " ")
+	return strings.Join(strings.Fields(key), " ")
+}
+
+// codeSeriesKey derives a series key from a tournament code by removing
+// the year and edition digits, e.g. "B408-2023-O" becomes "code:b o"
+func codeSeriesKey(code string) string {
+	key := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return ' '
+		}
+		return r
+	}, code)
+	key = seriesKey(key)
+	if key == "" {
+		return ""
+	}
+	return "code:" + key
+}
+
+// tournamentYear returns the year a tournament took place, or 0
+func tournamentYear(t api.TournamentResponse) int {
+	switch {
+	case t.StartDate != nil && !t.StartDate.IsZero():
+		return t.StartDate.Year()
+	case t.EndDate != nil && !t.EndDate.IsZero():
+		return t.EndDate.Year()
+	case !t.FinishedOn.IsZero():
+		return t.FinishedOn.Year()
+	}
+	if match := seriesYearPattern.FindString(t.Name); match != "" {
+		var year int
+		fmt.Sscanf(match[:4], "%d", &year)
+		return year
+	}
+	return 0
+}
+
+// SeriesEdition is a single tournament of a series
+type SeriesEdition struct {
+	TournamentID string `json:"tournament_id"`
+	Name         string `json:"name"`
+	Year         int    `json:"year,omitempty"`
+	Participants int    `json:"participants"`
+	Winner       string `json:"winner,omitempty"`
+	WinnerID     string `json:"winner_id,omitempty"`
+}
+
+// ParticipationTrend summarizes participant counts across editions
+type ParticipationTrend struct {
+	First     int     `json:"first"`
+	Last      int     `json:"last"`
+	Average   float64 `json:"average"`
+	Highest   int     `json:"highest"`
+	Change    int     `json:"change"` // Last minus first edition
+	Direction string  `json:"direction"`
+}
+
+// RepeatWinner is a player who won several editions
+type RepeatWinner struct {
+	Name  string `json:"name"`
+	Wins  int    `json:"wins"`
+	Years []int  `json:"years,omitempty"`
+}
+
+// TournamentSeries groups the editions of a recurring tournament
+type TournamentSeries struct {
+	Key           string             `json:"key"`
+	Name          string             `json:"name"` // Name of the latest edition
+	Editions      []SeriesEdition    `json:"editions"`
+	EditionCount  int                `json:"edition_count"`
+	FirstYear     int                `json:"first_year,omitempty"`
+	LastYear      int                `json:"last_year,omitempty"`
+	Participation ParticipationTrend `json:"participation"`
+	RepeatWinners []RepeatWinner     `json:"repeat_winners,omitempty"`
+}
+
+// seriesCache caches computed series per query
+type seriesCache struct {
+	mu      sync.Mutex
+	entries map[string]seriesCacheEntry
+}
+
+type seriesCacheEntry struct {
+	series  []TournamentSeries
+	size    int64
+	expires time.Time
+}
+
+func (c *seriesCache) get(key string) ([]TournamentSeries, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.series, true
+}
+
+func (c *seriesCache) put(key string, series []TournamentSeries) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]seriesCacheEntry)
+	}
+	c.entries[key] = seriesCacheEntry{series: series, size: memory.EstimateSize(series), expires: time.Now().Add(seriesCacheTTL)}
+}
+
+// groupTournamentSeries groups tournaments by series key. Editions are
+// sorted by year, series by edition count.
+func groupTournamentSeries(tournaments []api.TournamentResponse) []TournamentSeries {
+	groups := make(map[string][]api.TournamentResponse)
+	var keys []string
+	for _, t := range tournaments {
+		key := seriesKey(t.Name)
+		if key == "" {
+			key = codeSeriesKey(t.Code)
+		}
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	series := make([]TournamentSeries, 0, len(keys))
+	for _, key := range keys {
+		members := groups[key]
+		sort.SliceStable(members, func(i, j int) bool { return tournamentYear(members[i]) < tournamentYear(members[j]) })
+
+		s := TournamentSeries{Key: key, Name: members[len(members)-1].Name, EditionCount: len(members)}
+		for _, t := range members {
+			participants := t.Participants
+			if participants == 0 {
+				participants = t.ParticipantCount
+			}
+			s.Editions = append(s.Editions, SeriesEdition{
+				TournamentID: t.ID,
+				Name:         t.Name,
+				Year:         tournamentYear(t),
+				Participants: participants,
+			})
+		}
+		s.FirstYear, s.LastYear = s.Editions[0].Year, s.Editions[len(s.Editions)-1].Year
+		s.Participation = participationTrend(s.Editions)
+		series = append(series, s)
+	}
+
+	sort.SliceStable(series, func(i, j int) bool { return series[i].EditionCount > series[j].EditionCount })
+	return series
+}
+
+// participationTrend computes participant statistics of editions sorted by
+// year. Editions without participant count are ignored.
+func participationTrend(editions []SeriesEdition) ParticipationTrend {
+	trend := ParticipationTrend{Direction: "stable"}
+	counted, sum := 0, 0
+	for _, e := range editions {
+		if e.Participants <= 0 {
+			continue
+		}
+		if counted == 0 {
+			trend.First = e.Participants
+		}
+		trend.Last = e.Participants
+		trend.Highest = max(trend.Highest, e.Participants)
+		sum += e.Participants
+		counted++
+	}
+	if counted == 0 {
+		return trend
+	}
+
+	trend.Average = float64(sum) / float64(counted)
+	trend.Change = trend.Last - trend.First
+	// Changes of up to 10% of the first edition count as stable
+	switch threshold := max(trend.First/10, 1); {
+	case trend.Change > threshold:
+		trend.Direction = "growing"
+	case trend.Change < -threshold:
+		trend.Direction = "shrinking"
+	}
+	return trend
+}
+
+// tournamentWinner returns the participant with the most points in the
+// tournament's evaluations, using the performance as tie-break
+func tournamentWinner(details *api.EnhancedTournamentResponse) (id, name string) {
+	var best *api.Evaluation
+	for i := range details.Evaluations {
+		e := &details.Evaluations[i]
+		if best == nil || e.Points > best.Points || (e.Points == best.Points && e.Performance > best.Performance) {
+			best = e
+		}
+	}
+	if best == nil {
+		return "", ""
+	}
+
+	id = best.PlayerID
+	for _, p := range details.Participants {
+		if p.ID == id {
+			name = p.Name
+			if p.Firstname != "" {
+				name = p.Name + ", " + p.Firstname
+			}
+			break
+		}
+	}
+	return id, name
+}
+
+// addSeriesWinners looks up the winners of the latest editions of a series
+// and records players who won several times
+func (s *Server) addSeriesWinners(ctx context.Context, series *TournamentSeries, editions int) {
+	start := max(len(series.Editions)-editions, 0)
+	sem := make(chan struct{}, seriesConcurrency)
+	var wg sync.WaitGroup
+	for i := start; i < len(series.Editions); i++ {
+		wg.Add(1)
+		go func(edition *SeriesEdition) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			details, err := s.tournamentDetails(ctx, edition.TournamentID)
+			if err != nil {
+				s.logger.WithError(err).WithField("tournament_id", edition.TournamentID).Debug("Failed to get tournament winner")
+				return
+			}
+			edition.WinnerID, edition.Winner = tournamentWinner(details)
+			if edition.Winner == "" {
+				edition.Winner = edition.WinnerID
+			}
+		}(&series.Editions[i])
+	}
+	wg.Wait()
+
+	wins := make(map[string]*RepeatWinner)
+	var order []string
+	for _, e := range series.Editions {
+		if e.Winner == "" {
+			continue
+		}
+		key := e.WinnerID
+		if key == "" {
+			key = e.Winner
+		}
+		if wins[key] == nil {
+			wins[key] = &RepeatWinner{Name: e.Winner}
+			order = append(order, key)
+		}
+		wins[key].Wins++
+		if e.Year > 0 {
+			wins[key].Years = append(wins[key].Years, e.Year)
+		}
+	}
+	for _, key := range order {
+		if wins[key].Wins > 1 {
+			series.RepeatWinners = append(series.RepeatWinners, *wins[key])
+		}
+	}
+	sort.SliceStable(series.RepeatWinners, func(i, j int) bool { return series.RepeatWinners[i].Wins > series.RepeatWinners[j].Wins })
+}
+
+// findTournamentSeries searches tournaments matching query and groups them
+// into series. Winners are looked up for the latest editions only, and
+// not at all if editions is 0.
+func (s *Server) findTournamentSeries(ctx context.Context, query string, editions int) ([]TournamentSeries, error) {
+	var tournaments []api.TournamentResponse
+	for page := 0; page < seriesSearchPages; page++ {
+		params := api.SearchParams{Query: query, Limit: aggregatePageSize, Offset: page * aggregatePageSize}
+		result, err := s.apiClient.SearchTournaments(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		pageTournaments, _ := result.Data.([]api.TournamentResponse)
+		tournaments = append(tournaments, pageTournaments...)
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		if len(pageTournaments) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			break
+		}
+	}
+
+	series := groupTournamentSeries(tournaments)
+	if len(series) > maxSeriesResults {
+		series = series[:maxSeriesResults]
+	}
+	if editions > 0 {
+		for i := range series {
+			s.addSeriesWinners(ctx, &series[i], editions)
+		}
+	}
+	return series, nil
+}
+
+// handleGetTournamentSeries handles tournament series requests
+func (s *Server) handleGetTournamentSeries(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: query is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	editions := defaultSeriesEditions
+	if n, ok := args["max_editions"].(float64); ok && n > 0 {
+		editions = int(n)
+	}
+	if include, ok := args["include_winners"].(bool); ok && !include {
+		editions = 0
+	}
+
+	cacheKey := fmt.Sprintf("%s|%d", seriesKey(query), editions)
+	series, cached := s.series.get(cacheKey)
+	if !cached {
+		var err error
+		series, err = s.findTournamentSeries(ctx, query, editions)
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error searching tournament series: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		s.series.put(cacheKey, series)
+	}
+
+	if len(series) == 0 {
+		addWarning(ctx, "No tournaments found for the query")
+	}
+
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"query":  query,
+		"series": series,
+		"count":  len(series),
+	}, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestSeriesKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  string
+	}{
+		{"25. Ulm Open 2024", "ulm open"},
+		{"Ulm Open 2023", "ulm open"},
+		{"XXV. Ulmer Schachtage", "ulmer schachtage"},
+		{"Vereinsmeisterschaft 2023/24", "vereinsmeisterschaft"},
+		{"Stadtmeisterschaft Stuttgart 2022-2023", "stadtmeisterschaft stuttgart"},
+		{"Bad Wörishofen - Open (2021)", "bad wörishofen open"},
+		{"2024", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.key, seriesKey(tc.name))
+		})
+	}
+
+	assert.Equal(t, "code:b o", codeSeriesKey("B408-2023-O"))
+	assert.Empty(t, codeSeriesKey("2023"))
+}
+
+func TestGroupTournamentSeries(t *testing.T) {
+	date := func(year int) *time.Time {
+		d := time.Date(year, 5, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	tournaments := []api.TournamentResponse{
+		{ID: "T3", Name: "Ulm Open 2024", StartDate: date(2024), Participants: 120},
+		{ID: "X1", Name: "Blitzturnier Ulm 2023", StartDate: date(2023), Participants: 30},
+		{ID: "T1", Name: "23. Ulm Open", StartDate: date(2022), Participants: 100},
+		{ID: "T2", Name: "Ulm Open 2023", ParticipantCount: 105},
+	}
+
+	series := groupTournamentSeries(tournaments)
+	require.Len(t, series, 2)
+
+	ulm := series[0]
+	assert.Equal(t, "ulm open", ulm.Key)
+	assert.Equal(t, "Ulm Open 2024", ulm.Name)
+	assert.Equal(t, 3, ulm.EditionCount)
+	assert.Equal(t, 2022, ulm.FirstYear)
+	assert.Equal(t, 2024, ulm.LastYear)
+	assert.Equal(t, []string{"T1", "T2", "T3"}, []string{ulm.Editions[0].TournamentID, ulm.Editions[1].TournamentID, ulm.Editions[2].TournamentID})
+	assert.Equal(t, 105, ulm.Editions[1].Participants)
+	assert.Equal(t, ParticipationTrend{First: 100, Last: 120, Average: 325.0 / 3, Highest: 120, Change: 20, Direction: "growing"}, ulm.Participation)
+	assert.Equal(t, "blitzturnier ulm", series[1].Key)
+}
+
+func TestParticipationTrend(t *testing.T) {
+	editions := func(counts ...int) []SeriesEdition {
+		result := make([]SeriesEdition, len(counts))
+		for i, count := range counts {
+			result[i].Participants = count
+		}
+		return result
+	}
+
+	assert.Equal(t, "shrinking", participationTrend(editions(100, 80)).Direction)
+	assert.Equal(t, "stable", participationTrend(editions(100, 0, 105)).Direction)
+	assert.Equal(t, ParticipationTrend{Direction: "stable"}, participationTrend(editions(0, 0)))
+}
+
+func TestTournamentWinner(t *testing.T) {
+	details := &api.EnhancedTournamentResponse{
+		Participants: []api.PlayerResponse{
+			{ID: "C0327-297", Name: "Müller", Firstname: "Hans"},
+			{ID: "C0327-298", Name: "Schmidt"},
+		},
+		Evaluations: []api.Evaluation{
+			{PlayerID: "C0327-297", Points: 6, Performance: 2100},
+			{PlayerID: "C0327-298", Points: 6, Performance: 2150},
+			{PlayerID: "C0327-299", Points: 4},
+		},
+	}
+
+	id, name := tournamentWinner(details)
+	assert.Equal(t, "C0327-298", id)
+	assert.Equal(t, "Schmidt", name)
+
+	id, name = tournamentWinner(&api.EnhancedTournamentResponse{})
+	assert.Empty(t, id)
+	assert.Empty(t, name)
+}
+
+func TestSeriesCache(t *testing.T) {
+	var cache seriesCache
+	_, ok := cache.get("ulm open|10")
+	assert.False(t, ok)
+
+	cache.put("ulm open|10", []TournamentSeries{{Key: "ulm open"}})
+	series, ok := cache.get("ulm open|10")
+	require.True(t, ok)
+	assert.Equal(t, "ulm open", series[0].Key)
+}
@@ -4,31 +4,112 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
-	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/svw-info/portal64gomcp/internal/api"
 	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/features"
+	"github.com/svw-info/portal64gomcp/internal/geo"
+	"github.com/svw-info/portal64gomcp/internal/mail"
+	"github.com/svw-info/portal64gomcp/internal/render"
+	"github.com/svw-info/portal64gomcp/internal/memory"
+	"github.com/svw-info/portal64gomcp/internal/store"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
 )
 
-// Server represents the MCP server
+// Server represents the MCP server. The servers of upstream profiles share
+// the state of the default server and only have their own API client and
+// upstream data caches.
 type Server struct {
+	*sharedState
+	apiClient *api.Client
+	tools     map[string]ToolHandler
+	resources map[string]ResourceHandler
+	addresses addressBook
+	series    seriesCache
+	// distributions holds rating distribution snapshots for percentiles
+	distributions distributionSnapshots
+	// rosters caches the member lists of clubs
+	rosters rosterCache
+	// activity caches the tournaments of months for get_region_activity
+	activity activityCache
+	// reactivation caches the inactive members of clubs with their last
+	// evaluations
+	reactivation reactivationCache
+	// syncTokens keeps the snapshots of get_tournament_changes
+	syncTokens syncTokens
+	// profile names the upstream profile of a profile server, empty for
+	// the default profile
+	profile  string
+	profiles map[string]*Server
+}
+
+// sharedState is the state of the default server that the servers of
+// upstream profiles point to
+type sharedState struct {
 	config     *config.Config
 	logger     *logrus.Logger
-	apiClient  *api.Client
-	tools      map[string]ToolHandler
-	resources  map[string]ResourceHandler
 	listener   net.Listener
 	httpServer *http.Server
-	bridge     *HTTPBridge
-	ctx        context.Context
-	cancel     context.CancelFunc
-	wg         sync.WaitGroup
+	// httpListener is the listening socket of the HTTP bridge, handed over
+	// to a new process on restart
+	httpListener net.Listener
+	listenerMu   sync.Mutex
+	bridge       *HTTPBridge
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	out          io.Writer
+	outMu        sync.Mutex
+	inflight     map[string]context.CancelFunc
+	inflightMu   sync.Mutex
+	sessions     *SessionStore
+	geocoder     geo.Geocoder
+	// clubAliases maps the IDs of merged and renamed clubs to their
+	// current IDs
+	clubAliases clubAliases
+	// memory keeps the caches within the memory budget
+	memory *memory.Budget
+	// system samples the resource usage of the process, nil if disabled
+	system *telemetry.Sampler
+	// slo tracks the error budgets of the tools, nil if disabled
+	slo *telemetry.SLOTracker
+	// alerter notifies operators, nil without alerting
+	alerter *telemetry.Alerter
+	// shedder rejects tool calls while overloaded, nil if disabled
+	shedder *loadShedder
+	// limiter bounds concurrent tool calls, nil if unlimited
+	limiter *concurrencyLimiter
+	// batchTools are the tools scheduled after interactive calls
+	batchTools map[string]bool
+	// toolMetrics counts the calls and latencies per tool
+	toolMetrics toolMetrics
+	// mailer sends correction requests, nil if sending is not configured
+	mailer mail.Mailer
+	// digester batches the notifications of the email digests, nil without
+	// digests
+	digester *mail.Digester
+	watch    digestWatch
+	// exportKey signs club export download URLs
+	exportKey []byte
+	// started is the start time of the server, for the uptime
+	started time.Time
+	// panics counts panics recovered in tool and HTTP handlers
+	panics        atomic.Int64
+	errorReporter ErrorReporter
+	features      *features.Flags
+	// store persists rating histories and tournament details, with the
+	// keys of profiles prefixed by the profile name
+	store store.Store
 }
 
 // ToolHandler represents a function that handles tool calls
@@ -42,18 +123,79 @@ func NewServer(cfg *config.Config, logger *logrus.Logger, apiClient *api.Client)
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	server := &Server{
-		config:    cfg,
-		logger:    logger,
+		sharedState: &sharedState{
+			config:    cfg,
+			logger:    logger,
+			inflight:  make(map[string]context.CancelFunc),
+			features:  features.New(cfg.Features),
+			exportKey: newExportKey(cfg.Export.SigningKey),
+			started:   time.Now(),
+			ctx:       ctx,
+			cancel:    cancel,
+		},
 		apiClient: apiClient,
 		tools:     make(map[string]ToolHandler),
 		resources: make(map[string]ResourceHandler),
-		ctx:       ctx,
-		cancel:    cancel,
 	}
 
+	if cfg.MCP.Sessions.Enabled {
+		server.sessions = NewSessionStore(cfg.MCP.Sessions.TTL)
+	}
+
+	if cfg.Geocoder.Provider == "nominatim" {
+		server.geocoder = geo.NewCachingGeocoder(
+			geo.NewNominatimGeocoder(cfg.Geocoder.BaseURL, cfg.Geocoder.UserAgent, cfg.Geocoder.Timeout),
+			cfg.Geocoder.CacheTTL,
+		)
+	}
+
+	if cfg.Mail.SMTPHost != "" {
+		server.mailer = mail.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.Timeout)
+	}
+	if server.mailer != nil && len(cfg.Mail.Digests) > 0 {
+		tmpl, err := mail.ParseDigestTemplate(cfg.Mail.Template, server.locale())
+		if err != nil {
+			logger.WithError(err).Error("Failed to parse digest template, email digests disabled")
+		} else {
+			server.digester = mail.NewDigester(server.mailer, cfg.Mail.From, tmpl, cfg.Mail.MaxPerHour, logger)
+		}
+	}
+
+	if cfg.Store.Path != "" {
+		st, err := store.OpenBoltStore(cfg.Store.Path)
+		if err != nil {
+			logger.WithError(err).Error("Failed to open history store, persistence disabled")
+		} else {
+			if !st.Ready() {
+				logger.Warn("History store is in use by another instance, it is opened once released")
+			}
+			server.store = st
+		}
+	}
+
+	server.loadClubAliases()
+
+	server.memory = memory.NewBudget(int64(cfg.Memory.LimitMB)<<20, logger)
+	server.shedder = newLoadShedder(cfg.MCP.LoadShedding)
+	server.limiter = newConcurrencyLimiter(cfg.MCP.Scheduling, cfg.MCP.LoadShedding.RetryAfter)
+	server.batchTools = batchToolSet(cfg.MCP.Scheduling)
+
+	if system := cfg.Telemetry.System; system.Interval > 0 {
+		server.system = telemetry.NewSampler(system.LogDir, telemetry.Thresholds{
+			MaxHeapMB:     system.MaxHeapMB,
+			MaxGoroutines: system.MaxGoroutines,
+			MaxCPUPercent: system.MaxCPUPercent,
+			MaxLogDirMB:   system.MaxLogDirMB,
+		}, logger)
+	}
+	server.slo = newSLOTracker(cfg.Telemetry.SLO, logger)
+	server.registerMemoryStores()
+	server.attachResponseCache()
+
 	// Register tools and resources
 	server.registerTools()
 	server.registerResources()
+	server.checkHiddenTools()
 
 	// Initialize HTTP bridge
 	server.bridge = NewHTTPBridge(server, logger)
@@ -61,8 +203,63 @@ func NewServer(cfg *config.Config, logger *logrus.Logger, apiClient *api.Client)
 	return server
 }
 
+// attachResponseCache switches the response cache of the API client with
+// the caching feature flag
+func (s *Server) attachResponseCache() {
+	if s.apiClient == nil || s.apiClient.ResponseCache() == nil {
+		return
+	}
+	s.apiClient.ResponseCache().SetEnabled(func() bool { return s.features.Enabled(features.Caching) })
+}
+
+// locale returns the locale of human-readable output. Text renderers
+// format dates and numbers with it rather than with time layouts and fmt.
+func (s *Server) locale() render.Locale {
+	if s.config == nil {
+		return render.DefaultLocale
+	}
+	locale, err := render.ParseLocale(s.config.Render.Locale)
+	if err != nil {
+		return render.DefaultLocale
+	}
+	return locale
+}
+
 // Start starts the MCP server
 func (s *Server) Start() error {
+	if interval := s.config.Distributions.RefreshInterval; interval > 0 {
+		go s.runDistributionJob(s.ctx, interval)
+		for _, profile := range s.profiles {
+			go profile.runDistributionJob(s.ctx, interval)
+		}
+	}
+	if interval := s.config.ReferenceData.RefreshInterval; interval > 0 {
+		go s.runAddressBookJob(s.ctx, interval)
+		for _, profile := range s.profiles {
+			go profile.runAddressBookJob(s.ctx, interval)
+		}
+	}
+	if s.config.ClubAliases.URL != "" {
+		go s.runClubAliasJob(s.ctx, s.config.ClubAliases.RefreshInterval)
+	}
+	if s.config.Memory.LimitMB > 0 {
+		go s.memory.Run(s.ctx, s.config.Memory.CheckInterval)
+	}
+	if s.system != nil {
+		go s.system.Run(s.ctx, s.config.Telemetry.System.Interval)
+	}
+	if s.slo != nil {
+		go s.slo.Run(s.ctx)
+	}
+	if ssl, interval := s.config.API.SSL, s.config.Telemetry.Alerts.CertificateCheckInterval; s.alerter.Enabled(telemetry.AlertCertificateExpiry) &&
+		(ssl.ClientCert != "" || ssl.CAFile != "") && interval > 0 {
+		go s.runCertificateAlerts(s.ctx, interval)
+	}
+	if s.digester != nil {
+		go s.digester.Run(s.ctx, s.config.Mail.DigestInterval)
+		go s.runDigestWatch(s.ctx, s.config.Mail.CheckInterval)
+	}
+
 	switch s.config.MCP.Mode {
 	case "stdio":
 		s.logger.Info("Starting MCP server on stdio")
@@ -103,16 +300,34 @@ func (s *Server) Stop() {
 	}
 	
 	if s.httpServer != nil {
-		if err := s.httpServer.Shutdown(context.Background()); err != nil {
-			s.logger.WithError(err).Error("Error shutting down HTTP server")
+		s.drainHTTPServer()
+	}
+
+	if s.store != nil {
+		if err := s.store.Close(); err != nil {
+			s.logger.WithError(err).Error("Error closing history store")
 		}
 	}
 }
 
 // handleStdioConnection handles stdio-based communication
 func (s *Server) handleStdioConnection() error {
-	scanner := bufio.NewScanner(os.Stdin)
-	writer := os.Stdout
+	return s.serveStdio(os.Stdin, os.Stdout)
+}
+
+// serveStdio reads messages line by line from in and writes the responses
+// and notifications to out
+func (s *Server) serveStdio(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	s.outMu.Lock()
+	s.out = out
+	s.outMu.Unlock()
+
+	// Tool calls run concurrently so that cancellation notifications can
+	// reach calls that are still running. They are registered before the
+	// next message is read; all other messages are handled in order.
+	var pending sync.WaitGroup
+	defer pending.Wait()
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -122,42 +337,77 @@ func (s *Server) handleStdioConnection() error {
 
 		s.logger.WithField("message", line).Debug("Received message")
 
-		response, err := s.handleMessage([]byte(line))
-		if err != nil {
-			s.logger.WithError(err).Error("Error handling message")
+		data := []byte(line)
+		if msg, err := ParseMessage(data); err == nil && msg.ID != nil && msg.Method == "tools/call" {
+			ctx, done := s.startRequest(msg.ID)
+			pending.Add(1)
+			go func() {
+				defer pending.Done()
+				defer done()
+				s.writeStdioResponse(s.callToolSafely(ctx, msg, data))
+			}()
 			continue
 		}
+		s.writeStdioResponse(s.handleMessageSafely(data))
+	}
 
-		if response != nil {
-			responseData, err := SerializeMessage(response)
-			if err != nil {
-				s.logger.WithError(err).Error("Error serializing response")
-				continue
-			}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading from stdin: %w", err)
+	}
 
-			s.logger.WithField("response", string(responseData)).Debug("Sending response")
-			
-			if _, err := writer.Write(responseData); err != nil {
-				s.logger.WithError(err).Error("Error writing response")
-				continue
-			}
-			
-			if _, err := writer.Write([]byte("\n")); err != nil {
-				s.logger.WithError(err).Error("Error writing newline")
-				continue
-			}
+	return nil
+}
+
+// writeStdioResponse writes the response to a stdio message, if any
+func (s *Server) writeStdioResponse(response *Message, err error) {
+	if err != nil {
+		s.logger.WithError(err).Error("Error handling message")
+		return
+	}
+	if response != nil {
+		if err := s.writeMessage(response); err != nil {
+			s.logger.WithError(err).Error("Error writing response")
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading from stdin: %w", err)
+// writeMessage serializes a message and writes it to the stdio output.
+// Writes are serialized so notifications never interleave with responses.
+func (s *Server) writeMessage(msg *Message) error {
+	data, err := SerializeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+
+	if s.out == nil {
+		return nil
+	}
+
+	s.logger.WithField("response", string(data)).Debug("Sending response")
+
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
 	}
 
 	return nil
 }
+
+// sendNotification sends a server-initiated notification to the stdio client
+func (s *Server) sendNotification(msg *Message) {
+	if err := s.writeMessage(msg); err != nil {
+		s.logger.WithError(err).WithField("method", msg.Method).Warn("Failed to send notification")
+	}
+}
+
 // handleMessage processes incoming MCP messages
 func (s *Server) handleMessage(data []byte) (*Message, error) {
 	msg, err := ParseMessage(data)
+	if errors.Is(err, errInvalidRequest) {
+		return NewErrorResponse(nil, InvalidRequest, "Invalid request", err.Error()), nil
+	}
 	if err != nil {
 		return NewErrorResponse(nil, ParseError, "Parse error", err.Error()), nil
 	}
@@ -190,12 +440,77 @@ func (s *Server) handleNotification(msg *Message) (*Message, error) {
 	case "notifications/initialized":
 		s.logger.Info("Client initialized")
 		return nil, nil
+	case "notifications/cancelled", "$/cancelRequest":
+		var n CancelledNotification
+		if err := s.parseParams(msg.Params, &n); err != nil {
+			s.logger.WithError(err).Warn("Invalid cancellation notification")
+			return nil, nil
+		}
+		s.cancelRequest(n.TargetID(), n.Reason)
+		return nil, nil
 	default:
 		s.logger.WithField("method", msg.Method).Warn("Unknown notification method")
 		return nil, nil
 	}
 }
 
+// requestKey returns a map key for a JSON-RPC request ID, keeping numeric
+// and string IDs distinct
+func requestKey(id interface{}) string {
+	data, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Sprintf("%v", id)
+	}
+	return string(data)
+}
+
+// trackRequest registers the cancel function of an in-flight request
+func (s *Server) trackRequest(id interface{}, cancel context.CancelFunc) {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+
+	if s.inflight == nil {
+		s.inflight = make(map[string]context.CancelFunc)
+	}
+	s.inflight[requestKey(id)] = cancel
+}
+
+// startRequest registers a cancellable context for a request. The returned
+// function untracks the request and releases the context.
+func (s *Server) startRequest(id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.trackRequest(id, cancel)
+	return ctx, func() {
+		s.untrackRequest(id)
+		cancel()
+	}
+}
+
+// untrackRequest removes a finished request
+func (s *Server) untrackRequest(id interface{}) {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+
+	delete(s.inflight, requestKey(id))
+}
+
+// cancelRequest cancels an in-flight request. Unknown IDs are ignored since
+// the request may already have completed.
+func (s *Server) cancelRequest(id interface{}, reason string) {
+	s.inflightMu.Lock()
+	cancel, ok := s.inflight[requestKey(id)]
+	s.inflightMu.Unlock()
+
+	fields := logrus.Fields{"request_id": id, "reason": reason}
+	if !ok {
+		s.logger.WithFields(fields).Debug("Cancellation for unknown or completed request")
+		return
+	}
+
+	s.logger.WithFields(fields).Info("Cancelling request")
+	cancel()
+}
+
 // handleInitialize processes initialization requests
 func (s *Server) handleInitialize(msg *Message) (*Message, error) {
 	var req InitializeRequest
@@ -231,16 +546,8 @@ func (s *Server) handleInitialize(msg *Message) (*Message, error) {
 
 // handleListTools processes tool listing requests
 func (s *Server) handleListTools(msg *Message) (*Message, error) {
-	tools := make([]Tool, 0, len(s.tools))
-	
-	// Add all registered tools
-	for name := range s.tools {
-		tool := s.GetToolDefinition(name)
-		tools = append(tools, tool)
-	}
-
 	response := ListToolsResponse{
-		Tools: tools,
+		Tools: s.toolDefinitions(TransportStdio),
 	}
 
 	return NewSuccessResponse(msg.ID, response), nil
@@ -248,28 +555,53 @@ func (s *Server) handleListTools(msg *Message) (*Message, error) {
 
 // handleCallTool processes tool execution requests
 func (s *Server) handleCallTool(msg *Message) (*Message, error) {
+	ctx, done := s.startRequest(msg.ID)
+	defer done()
+	return s.callTool(ctx, msg)
+}
+
+// callTool executes a tool call in the context registered for the request
+func (s *Server) callTool(ctx context.Context, msg *Message) (*Message, error) {
 	var req CallToolRequest
 	if err := s.parseParams(msg.Params, &req); err != nil {
 		return NewErrorResponse(msg.ID, InvalidParams, "Invalid parameters", err.Error()), nil
 	}
 
-	handler, exists := s.tools[req.Name]
+	profile, args, err := s.profileFor(s.ctx, req.Arguments)
+	if err != nil {
+		return NewErrorResponse(msg.ID, InvalidParams, "Invalid parameters", err.Error()), nil
+	}
+
+	handler, exists := profile.lookupTool(TransportStdio, req.Name)
 	if !exists {
 		return NewErrorResponse(msg.ID, MethodNotFound, fmt.Sprintf("Tool not found: %s", req.Name), nil), nil
 	}
 
-	s.logger.WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"tool": req.Name,
-		"args": req.Arguments,
-	}).Info("Executing tool")
+		"args": args,
+	}
+	if profile.profile != "" {
+		fields["profile"] = profile.profile
+	}
+	s.logger.WithFields(fields).Info("Executing tool")
 
-	result, err := handler(s.ctx, req.Arguments)
+	if req.Meta != nil && req.Meta.ProgressToken != nil {
+		ctx = withProgress(ctx, s.progressReporter(req.Meta.ProgressToken))
+	}
+	ctx, warnings := withWarnings(ctx)
+
+	result, err := handler(ctx, args)
+	if ctx.Err() == context.Canceled && s.ctx.Err() == nil {
+		s.logger.WithField("tool", req.Name).Info("Tool execution cancelled by client")
+		return NewErrorResponse(msg.ID, RequestCancelled, "Request cancelled", nil), nil
+	}
 	if err != nil {
 		s.logger.WithError(err).Error("Tool execution failed")
-		return NewErrorResponse(msg.ID, InternalError, "Tool execution failed", err.Error()), nil
+		return toolErrorResponse(msg.ID, err), nil
 	}
 
-	return NewSuccessResponse(msg.ID, result), nil
+	return NewSuccessResponse(msg.ID, s.wrapResult(result, warnings)), nil
 }
 // handleListResources processes resource listing requests
 func (s *Server) handleListResources(msg *Message) (*Message, error) {
@@ -292,6 +624,12 @@ func (s *Server) handleListResources(msg *Message) (*Message, error) {
 			Description: "Comprehensive club profile with members and statistics",
 			MimeType:    "application/json",
 		},
+		{
+			URI:         "tournaments://upcoming",
+			Name:        "Upcoming Tournaments",
+			Description: "Tournaments starting within the next 30 days (tournaments://upcoming/{region} filters by region)",
+			MimeType:    "application/json",
+		},
 		{
 			URI:         "tournaments://{id}",
 			Name:        "Tournament Details",
@@ -322,10 +660,16 @@ func (s *Server) handleListResources(msg *Message) (*Message, error) {
 			Description: "API cache performance metrics",
 			MimeType:    "application/json",
 		},
+		{
+			URI:         "admin://anomalies",
+			Name:        "Upstream API Anomalies",
+			Description: "Upstream responses deviating from the expected schema, such as unknown or missing fields",
+			MimeType:    "application/json",
+		},
 	}
 
 	response := ListResourcesResponse{
-		Resources: resources,
+		Resources: s.exposedResources(TransportStdio, resources),
 	}
 
 	return NewSuccessResponse(msg.ID, response), nil
@@ -341,16 +685,13 @@ func (s *Server) handleReadResource(msg *Message) (*Message, error) {
 	s.logger.WithField("uri", req.URI).Info("Reading resource")
 
 	// Parse URI and find appropriate handler
-	parts := strings.SplitN(req.URI, "://", 2)
-	if len(parts) != 2 {
-		return NewErrorResponse(msg.ID, InvalidParams, "Invalid resource URI format", nil), nil
+	scheme, path, err := parseResourceURI(req.URI)
+	if err != nil {
+		return NewErrorResponse(msg.ID, InvalidParams, "Invalid resource URI format", err.Error()), nil
 	}
 
-	scheme := parts[0]
-	path := parts[1]
-
 	handler, exists := s.resources[scheme]
-	if !exists {
+	if !exists || !s.resourceExposed(TransportStdio, req.URI) {
 		return NewErrorResponse(msg.ID, MethodNotFound, fmt.Sprintf("Resource scheme not found: %s", scheme), nil), nil
 	}
 
@@ -386,11 +727,66 @@ func (s *Server) startHTTPServer() error {
 	router := s.bridge.SetupRoutes()
 	
 	addr := fmt.Sprintf(":%d", s.config.MCP.HTTPPort)
+	tuning := s.config.MCP.HTTP
 	s.httpServer = &http.Server{
-		Addr:    addr,
-		Handler: router,
+		Addr:              addr,
+		Handler:           router,
+		ReadTimeout:       tuning.ReadTimeout,
+		ReadHeaderTimeout: tuning.ReadHeaderTimeout,
+		WriteTimeout:      tuning.WriteTimeout,
+		IdleTimeout:       tuning.IdleTimeout,
+		MaxHeaderBytes:    tuning.MaxHeaderBytes,
 	}
 	
-	s.logger.WithField("addr", addr).Info("Starting HTTP server")
-	return s.httpServer.ListenAndServe()
+	if s.sessions != nil {
+		go s.sessions.Run(s.ctx)
+	}
+
+	listener, err := listen(s.ctx, addr, tuning.ReusePort)
+	if err != nil {
+		return err
+	}
+	s.listenerMu.Lock()
+	s.httpListener = listener
+	s.listenerMu.Unlock()
+	if tuning.MaxConnections > 0 {
+		listener = limitListener(listener, tuning.MaxConnections)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"addr":            listener.Addr().String(),
+		"max_connections": tuning.MaxConnections,
+		"reuse_port":      tuning.ReusePort,
+	}).Info("Starting HTTP server")
+	notifyHandoverReady(s.logger, listener)
+	return s.httpServer.Serve(listener)
+}
+
+// HTTPAddr returns the address the HTTP bridge listens on, nil until it is
+// listening. With mcp.http_port 0 the port is chosen by the system.
+func (s *Server) HTTPAddr() net.Addr {
+	s.listenerMu.Lock()
+	defer s.listenerMu.Unlock()
+	if s.httpListener == nil {
+		return nil
+	}
+	return s.httpListener.Addr()
+}
+
+// drainHTTPServer stops accepting connections and waits for the requests in
+// flight to finish, at most the drain timeout. Connections still open then
+// are closed.
+func (s *Server) drainHTTPServer() {
+	ctx := context.Background()
+	if timeout := s.config.MCP.HTTP.DrainTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.logger.WithError(err).Warn("Drain timeout exceeded, closing remaining connections")
+		if err := s.httpServer.Close(); err != nil {
+			s.logger.WithError(err).Error("Error closing HTTP server")
+		}
+	}
 }
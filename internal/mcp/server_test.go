@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer() *Server {
+	return &Server{
+		sharedState: &sharedState{
+			logger:   logrus.New(),
+			inflight: make(map[string]context.CancelFunc),
+			ctx:      context.Background(),
+		},
+		tools:     make(map[string]ToolHandler),
+		resources: make(map[string]ResourceHandler),
+	}
+}
+
+func TestHandleCallTool_Cancellation(t *testing.T) {
+	testCases := []struct {
+		name         string
+		notification string
+	}{
+		{
+			name:         "notifications/cancelled",
+			notification: `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user abort"}}`,
+		},
+		{
+			name:         "$/cancelRequest",
+			notification: `{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":7}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer()
+			started := make(chan struct{})
+			s.tools["slow"] = func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+
+			done := make(chan *Message, 1)
+			go func() {
+				resp, _ := s.handleMessage([]byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"slow","arguments":{}}}`))
+				done <- resp
+			}()
+
+			<-started
+			resp, err := s.handleMessage([]byte(tc.notification))
+			require.NoError(t, err)
+			assert.Nil(t, resp)
+
+			select {
+			case resp := <-done:
+				require.NotNil(t, resp.Error)
+				assert.Equal(t, RequestCancelled, resp.Error.Code)
+				assert.Equal(t, float64(7), resp.ID)
+			case <-time.After(2 * time.Second):
+				t.Fatal("tool call was not cancelled")
+			}
+
+			assert.Empty(t, s.inflight)
+		})
+	}
+}
+
+func TestServeStdio_Ordering(t *testing.T) {
+	s := newTestServer()
+	s.tools["slow"] = func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	// The cancellation directly follows the call, before the tool may have
+	// started, and the other requests are answered in order meanwhile
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`,
+	}, "\n")
+	var out bytes.Buffer
+	served := make(chan error, 1)
+	go func() { served <- s.serveStdio(strings.NewReader(in), &out) }()
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+
+	var ids []interface{}
+	var cancelled *Message
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg Message
+		require.NoError(t, json.Unmarshal([]byte(line), &msg))
+		ids = append(ids, msg.ID)
+		if msg.ID == float64(1) {
+			cancelled = &msg
+		}
+	}
+	assert.Equal(t, []interface{}{float64(2), float64(3), float64(4), float64(1)}, ids)
+	require.NotNil(t, cancelled)
+	require.NotNil(t, cancelled.Error)
+	assert.Equal(t, RequestCancelled, cancelled.Error.Code)
+	assert.Empty(t, s.inflight)
+}
+
+func TestCancelRequest_UnknownID(t *testing.T) {
+	s := newTestServer()
+
+	params, _ := json.Marshal(CancelledNotification{RequestID: "missing"})
+	resp, err := s.handleMessage([]byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":` + string(params) + `}`))
+
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestRequestKey_DistinguishesTypes(t *testing.T) {
+	assert.NotEqual(t, requestKey(float64(1)), requestKey("1"))
+	assert.Equal(t, requestKey(float64(1)), requestKey(float64(1)))
+}
+
+func TestNormalizeIDArgs(t *testing.T) {
+	var got map[string]interface{}
+	handler := normalizeIDArgs(func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		got = args
+		return &CallToolResponse{}, nil
+	})
+
+	args := map[string]interface{}{
+		"player_id": "c0327/297",
+		"club_id":   "not-an-id",
+		"limit":     float64(5),
+	}
+	_, err := handler(context.Background(), args)
+	require.NoError(t, err)
+
+	assert.Equal(t, "C0327-297", got["player_id"])
+	assert.Equal(t, "not-an-id", got["club_id"], "unparseable IDs are passed on unchanged")
+	assert.Equal(t, float64(5), got["limit"])
+	assert.Equal(t, "c0327/297", args["player_id"], "caller arguments are not modified")
+}
@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// SessionHeader carries the session ID on HTTP requests and responses
+const SessionHeader = "Mcp-Session-Id"
+
+// Session holds per-client state that persists across HTTP requests
+type Session struct {
+	ID        string
+	CreatedAt time.Time
+
+	mu       sync.RWMutex
+	lastSeen time.Time
+	values   map[string]interface{}
+}
+
+// Get returns a session value
+func (s *Session) Get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores a session value
+func (s *Session) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+}
+
+// Delete removes a session value
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+}
+
+// LastSeen returns the time the session was last used
+func (s *Session) LastSeen() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lastSeen
+}
+
+// touch marks the session as used
+func (s *Session) touch(now time.Time) {
+	s.mu.Lock()
+	s.lastSeen = now
+	s.mu.Unlock()
+}
+
+// SessionStore keeps sessions in memory and expires them after a period of
+// inactivity
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewSessionStore creates a session store with the given idle TTL
+func NewSessionStore(ttl time.Duration) *SessionStore {
+	return &SessionStore{
+		sessions: make(map[string]*Session),
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// Create starts a new session
+func (st *SessionStore) Create() (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := st.now()
+	session := &Session{
+		ID:        id,
+		CreatedAt: now,
+		lastSeen:  now,
+		values:    make(map[string]interface{}),
+	}
+
+	st.mu.Lock()
+	st.sessions[id] = session
+	st.mu.Unlock()
+
+	return session, nil
+}
+
+// Get returns a live session and refreshes its expiry
+func (st *SessionStore) Get(id string) (*Session, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	session, ok := st.sessions[id]
+	if !ok {
+		return nil, false
+	}
+
+	now := st.now()
+	if st.expired(session, now) {
+		delete(st.sessions, id)
+		return nil, false
+	}
+
+	session.touch(now)
+	return session, true
+}
+
+// Delete ends a session
+func (st *SessionStore) Delete(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	_, ok := st.sessions[id]
+	delete(st.sessions, id)
+	return ok
+}
+
+// Len returns the number of stored sessions
+func (st *SessionStore) Len() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return len(st.sessions)
+}
+
+// Cleanup removes expired sessions and returns how many were removed
+func (st *SessionStore) Cleanup() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	removed := 0
+	for id, session := range st.sessions {
+		if st.expired(session, now) {
+			delete(st.sessions, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Run removes expired sessions periodically until ctx is done
+func (st *SessionStore) Run(ctx context.Context) {
+	interval := st.ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st.Cleanup()
+		}
+	}
+}
+
+// expired reports whether a session has been idle longer than the TTL
+func (st *SessionStore) expired(session *Session, now time.Time) bool {
+	return st.ttl > 0 && now.Sub(session.LastSeen()) > st.ttl
+}
+
+// newSessionID returns a random 128-bit hex session ID
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type sessionKey struct{}
+
+// withSession attaches a session to the context
+func withSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFromContext returns the session attached to a request, if any
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(*Session)
+	return session, ok && session != nil
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestSessionStore_Expiry(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewSessionStore(10 * time.Minute)
+	store.now = func() time.Time { return now }
+
+	session, err := store.Create()
+	require.NoError(t, err)
+	assert.Len(t, session.ID, 32)
+
+	now = now.Add(9 * time.Minute)
+	_, ok := store.Get(session.ID)
+	assert.True(t, ok, "session should be refreshed on access")
+
+	now = now.Add(9 * time.Minute)
+	_, ok = store.Get(session.ID)
+	assert.True(t, ok, "access should extend the TTL")
+
+	now = now.Add(11 * time.Minute)
+	assert.Equal(t, 1, store.Cleanup())
+	_, ok = store.Get(session.ID)
+	assert.False(t, ok)
+}
+
+func TestSession_Values(t *testing.T) {
+	store := NewSessionStore(time.Minute)
+	session, err := store.Create()
+	require.NoError(t, err)
+
+	session.Set("locale", "de")
+	value, ok := session.Get("locale")
+	assert.True(t, ok)
+	assert.Equal(t, "de", value)
+
+	session.Delete("locale")
+	_, ok = session.Get("locale")
+	assert.False(t, ok)
+}
+
+func TestHTTPBridge_Sessions(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{MCP: config.MCPConfig{Sessions: config.SessionConfig{Enabled: true, TTL: time.Minute}}}
+	s.sessions = NewSessionStore(time.Minute)
+	s.bridge = NewHTTPBridge(s, s.logger)
+
+	var seen *Session
+	s.tools["whoami"] = func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		seen, _ = SessionFromContext(ctx)
+		return &CallToolResponse{Content: []ToolContent{{Type: "text", Text: "{}"}}}, nil
+	}
+
+	router := s.bridge.SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/sessions", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	id := rec.Header().Get(SessionHeader)
+	require.NotEmpty(t, id)
+
+	req := httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader(`{"name":"whoami","arguments":{}}`))
+	req.Header.Set(SessionHeader, id)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, seen)
+	assert.Equal(t, id, seen.ID)
+
+	req = httptest.NewRequest(http.MethodDelete, "/sessions", nil)
+	req.Header.Set(SessionHeader, id)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader(`{"name":"whoami","arguments":{}}`))
+	req.Header.Set(SessionHeader, id)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,204 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+// hardOverload is the overload at which all but administrative tool calls
+// are shed
+const hardOverload = 2
+
+// BusyError rejects a tool call while the server is overloaded
+type BusyError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *BusyError) Error() string {
+	return "server busy: " + e.Reason
+}
+
+// LoadSheddingStats reports the state of load shedding
+type LoadSheddingStats struct {
+	InFlight int64   `json:"in_flight"` // Admitted tool calls in flight
+	Overload float64 `json:"overload"`  // Load relative to the thresholds, 1 at a threshold
+	Rejected int64   `json:"rejected"`  // Tool calls rejected since start
+}
+
+// loadShedder admits tool calls depending on their priority and the load of
+// the server. It is shared by the profile servers.
+type loadShedder struct {
+	maxInFlight int
+	maxLatency  time.Duration
+	retryAfter  time.Duration
+	inFlight    atomic.Int64
+	rejected    atomic.Int64
+}
+
+// newLoadShedder returns a load shedder, nil if load shedding is disabled
+func newLoadShedder(cfg config.LoadSheddingConfig) *loadShedder {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &loadShedder{maxInFlight: cfg.MaxInFlight, maxLatency: cfg.MaxLatency, retryAfter: cfg.RetryAfter}
+}
+
+// overload returns the load relative to the thresholds and describes the
+// dominating one
+func (l *loadShedder) overload(latency time.Duration) (float64, string) {
+	load, reason := 0.0, ""
+	if l.maxInFlight > 0 {
+		inFlight := l.inFlight.Load()
+		load = float64(inFlight) / float64(l.maxInFlight)
+		reason = fmt.Sprintf("%d tool calls in flight (threshold %d)", inFlight, l.maxInFlight)
+	}
+	if l.maxLatency > 0 {
+		if latencyLoad := float64(latency) / float64(l.maxLatency); latencyLoad > load {
+			load = latencyLoad
+			reason = fmt.Sprintf("upstream latency %s (threshold %s)", latency.Round(time.Millisecond), l.maxLatency)
+		}
+	}
+	return load, reason
+}
+
+// admit admits a tool call or rejects it with a BusyError. An admitted call
+// must call the returned release function when done.
+func (l *loadShedder) admit(priority toolPriority, latency time.Duration) (func(), error) {
+	if priority != priorityCritical {
+		load, reason := l.overload(latency)
+		if (load >= 1 && priority == priorityBatch) || load >= hardOverload {
+			l.rejected.Add(1)
+			return nil, &BusyError{Reason: reason, RetryAfter: l.retryAfter}
+		}
+	}
+	l.inFlight.Add(1)
+	return func() { l.inFlight.Add(-1) }, nil
+}
+
+// stats returns the state of load shedding
+func (l *loadShedder) stats(latency time.Duration) LoadSheddingStats {
+	load, _ := l.overload(latency)
+	return LoadSheddingStats{
+		InFlight: l.inFlight.Load(),
+		Overload: math.Round(load*1000) / 1000,
+		Rejected: l.rejected.Load(),
+	}
+}
+
+type admittedKey struct{}
+
+// admitted reports whether a tool call of the context was admitted already
+func admitted(ctx context.Context) bool {
+	return ctx.Value(admittedKey{}) != nil
+}
+
+// admitCall admits a tool call of the server and waits for a slot of the
+// concurrency limiter. The returned context marks the call as admitted, so
+// that nested tool calls are not counted again.
+func (s *Server) admitCall(ctx context.Context, name string, priority toolPriority) (context.Context, func(), error) {
+	if (s.shedder == nil && s.limiter == nil) || admitted(ctx) {
+		return ctx, func() {}, nil
+	}
+
+	releaseShedder := func() {}
+	if s.shedder != nil {
+		var err error
+		if releaseShedder, err = s.shedder.admit(priority, s.apiClient.RecentLatency()); err != nil {
+			s.logger.WithField("tool", name).WithError(err).Warn("Shedding tool call")
+			return ctx, nil, err
+		}
+	}
+
+	release := releaseShedder
+	if s.limiter != nil {
+		releaseLimiter, err := s.limiter.acquire(ctx, priority)
+		if err != nil {
+			releaseShedder()
+			if _, busy := err.(*BusyError); busy {
+				s.logger.WithField("tool", name).WithError(err).Warn("Tool call timed out in queue")
+			}
+			return ctx, nil, err
+		}
+		release = func() {
+			releaseLimiter()
+			releaseShedder()
+		}
+	}
+	return context.WithValue(ctx, admittedKey{}, true), release, nil
+}
+
+// shedLoad wraps a tool handler so that its calls are scheduled by priority
+// and rejected while the server is overloaded. The priority argument is
+// removed from the arguments.
+func (s *Server) shedLoad(name string, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		override, args, err := splitPriorityArg(args)
+		var priority toolPriority
+		if err == nil {
+			priority, err = s.callPriority(name, override)
+		}
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		ctx, release, err := s.admitCall(ctx, name, priority)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, args)
+	}
+}
+
+// shedRoute wraps a REST route backed by a tool so that it is scheduled by
+// priority and answers 503 while the server is overloaded. The admission
+// covers the tool calls of the route.
+func (h *HTTPBridge) shedRoute(tool string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		server, _, err := h.server.profileFor(r.Context(), nil)
+		if err != nil {
+			server = h.server
+		}
+		priority, err := server.callPriority(tool, requestPriority(r))
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), "INVALID_REQUEST")
+			return
+		}
+
+		ctx, release, err := server.admitCall(r.Context(), tool, priority)
+		var busy *BusyError
+		if errors.As(err, &busy) {
+			h.writeBusyResponse(w, busy)
+			return
+		}
+		if err != nil {
+			// The client went away while queued
+			return
+		}
+		defer release()
+		handler(w, r.WithContext(ctx))
+	}
+}
+
+// writeBusyResponse answers a shed request with 503 and a Retry-After header
+func (h *HTTPBridge) writeBusyResponse(w http.ResponseWriter, busy *BusyError) {
+	if busy.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(busy.RetryAfter.Seconds()))))
+	}
+	h.writeErrorResponse(w, http.StatusServiceUnavailable, "Server busy: "+busy.Reason, "SERVER_BUSY")
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestLoadShedder_InFlight(t *testing.T) {
+	shedder := newLoadShedder(config.LoadSheddingConfig{Enabled: true, MaxInFlight: 2, RetryAfter: 3 * time.Second})
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := shedder.admit(priorityInteractive, 0)
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+
+	// At the threshold batch calls are shed
+	_, err := shedder.admit(priorityBatch, 0)
+	var busy *BusyError
+	require.True(t, errors.As(err, &busy))
+	assert.Equal(t, "2 tool calls in flight (threshold 2)", busy.Reason)
+	assert.Equal(t, 3*time.Second, busy.RetryAfter)
+
+	for i := 0; i < 2; i++ {
+		release, err := shedder.admit(priorityInteractive, 0)
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+
+	// At twice the threshold all but administrative calls are shed
+	_, err = shedder.admit(priorityInteractive, 0)
+	assert.Error(t, err)
+	release, err := shedder.admit(priorityCritical, 0)
+	require.NoError(t, err)
+	releases = append(releases, release)
+
+	stats := shedder.stats(0)
+	assert.Equal(t, int64(5), stats.InFlight)
+	assert.Equal(t, 2.5, stats.Overload)
+	assert.Equal(t, int64(2), stats.Rejected)
+
+	for _, release := range releases {
+		release()
+	}
+	_, err = shedder.admit(priorityBatch, 0)
+	assert.NoError(t, err)
+}
+
+func TestLoadShedder_Latency(t *testing.T) {
+	shedder := newLoadShedder(config.LoadSheddingConfig{Enabled: true, MaxLatency: time.Second})
+
+	_, err := shedder.admit(priorityBatch, 500*time.Millisecond)
+	assert.NoError(t, err)
+	_, err = shedder.admit(priorityBatch, 1500*time.Millisecond)
+	assert.EqualError(t, err, "server busy: upstream latency 1.5s (threshold 1s)")
+	_, err = shedder.admit(priorityInteractive, 1500*time.Millisecond)
+	assert.NoError(t, err)
+	_, err = shedder.admit(priorityInteractive, 2*time.Second)
+	assert.Error(t, err)
+
+	assert.Nil(t, newLoadShedder(config.LoadSheddingConfig{MaxInFlight: 1}))
+}
+
+func newSheddingTestServer(t *testing.T) *Server {
+	s := newTestServer()
+	s.apiClient = api.NewClient("http://127.0.0.1:1", 5*time.Second, nil)
+	s.shedder = newLoadShedder(config.LoadSheddingConfig{Enabled: true, MaxInFlight: 1, RetryAfter: 1500 * time.Millisecond})
+	s.tools["search_players"] = s.shedLoad("search_players", func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		// Nested calls of an admitted call are not counted again
+		assert.Equal(t, int64(1), s.shedder.inFlight.Load())
+		return &CallToolResponse{Content: []ToolContent{{Type: "text", Text: `{}`}}}, nil
+	})
+	s.bridge = NewHTTPBridge(s, s.logger)
+	return s
+}
+
+func TestShedLoad_Stdio(t *testing.T) {
+	s := newSheddingTestServer(t)
+
+	response, err := s.handleMessage([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_players","arguments":{}}}`))
+	require.NoError(t, err)
+	assert.Nil(t, response.Error)
+	assert.Zero(t, s.shedder.inFlight.Load())
+
+	s.shedder.inFlight.Add(2)
+	response, err = s.handleMessage([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_players","arguments":{}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, ServerBusy, response.Error.Code)
+	data, _ := json.Marshal(response.Error.Data)
+	assert.JSONEq(t, `{"reason":"2 tool calls in flight (threshold 1)","retry_after_seconds":2}`, string(data))
+}
+
+func TestShedLoad_HTTP(t *testing.T) {
+	s := newSheddingTestServer(t)
+	router := s.bridge.SetupRoutes()
+	s.shedder.inFlight.Add(2)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader(`{"name":"search_players","arguments":{}}`)))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), "SERVER_BUSY")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/players?query=Meyer", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "SERVER_BUSY")
+
+	s.shedder.inFlight.Add(-2)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/players?query=Meyer", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, s.shedder.inFlight.Load())
+}
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+// SignatureHeader carries the signature of an HTTP bridge response
+const SignatureHeader = "X-Portal64-Signature"
+
+// Forms of signed bodies, named by the canon field of the signature
+const (
+	canonJSON = "json" // Canonical JSON, see canonicalBody
+	canonRaw  = "raw"  // The body as sent
+)
+
+// responseSigner signs response bodies with an HMAC secret or an Ed25519
+// private key
+type responseSigner struct {
+	algorithm  string
+	keyID      string
+	secret     []byte
+	privateKey ed25519.PrivateKey
+}
+
+// newResponseSigner returns the signer of a configuration, nil if signing
+// is disabled
+func newResponseSigner(cfg config.SigningConfig) (*responseSigner, error) {
+	signer := &responseSigner{algorithm: cfg.Algorithm, keyID: cfg.KeyID}
+	switch cfg.Algorithm {
+	case "":
+		return nil, nil
+	case config.SigningHMACSHA256:
+		signer.secret = []byte(cfg.Key)
+	case config.SigningEd25519:
+		key, err := config.ParseEd25519Key(cfg.Key)
+		if err != nil {
+			return nil, err
+		}
+		signer.privateKey = key
+	default:
+		return nil, fmt.Errorf("unknown signing algorithm %q", cfg.Algorithm)
+	}
+	return signer, nil
+}
+
+// sign returns the signature header value of a response:
+// alg=<algorithm>[,keyid=<key ID>],canon=<json|raw>,sig=<base64 signature>
+func (s *responseSigner) sign(status int, requestURI, contentType string, body []byte) string {
+	canonical, canon := canonicalBody(contentType, body)
+	message := signedMessage(status, requestURI, canonical)
+	var signature []byte
+	if s.privateKey != nil {
+		signature = ed25519.Sign(s.privateKey, message)
+	} else {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(message)
+		signature = mac.Sum(nil)
+	}
+
+	value := "alg=" + s.algorithm
+	if s.keyID != "" {
+		value += ",keyid=" + s.keyID
+	}
+	return value + ",canon=" + canon + ",sig=" + base64.StdEncoding.EncodeToString(signature)
+}
+
+// signedMessage returns the signed form of a response: its status and the
+// request URI on the first line, so that a signed response cannot be
+// replayed for another request, followed by the body
+func signedMessage(status int, requestURI string, canonical []byte) []byte {
+	return append([]byte(fmt.Sprintf("%d %s\n", status, requestURI)), canonical...)
+}
+
+// canonicalBody returns the form of a response body that is signed and its
+// name. A JSON document is re-encoded without insignificant whitespace, with
+// object keys sorted and numbers as sent, so that relays may reformat it.
+// Other bodies, including JSON bodies with data after the document, are
+// signed as they are.
+func canonicalBody(contentType string, body []byte) ([]byte, string) {
+	if !strings.Contains(contentType, "json") {
+		return body, canonRaw
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body, canonRaw
+	}
+	// The canonical form would leave out further values or garbage
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return body, canonRaw
+	}
+
+	var canonical bytes.Buffer
+	encoder := json.NewEncoder(&canonical)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return body, canonRaw
+	}
+	return bytes.TrimSuffix(canonical.Bytes(), []byte("\n")), canonJSON
+}
+
+// bufferedResponse holds a response until it is signed
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(data)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// signingMiddleware signs the responses of the bridge when response signing
+// is configured. Responses are buffered until the body is complete.
+func (h *HTTPBridge) signingMiddleware(next http.Handler) http.Handler {
+	if h.signer == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := &bufferedResponse{header: w.Header()}
+		next.ServeHTTP(response, r)
+		if response.status == 0 {
+			response.status = http.StatusOK
+		}
+
+		body := response.body.Bytes()
+		w.Header().Set(SignatureHeader, h.signer.sign(response.status, r.URL.RequestURI(), w.Header().Get("Content-Type"), body))
+		w.WriteHeader(response.status)
+		w.Write(body)
+	})
+}
+
+// SigningKey describes the public key of Ed25519 response signatures
+type SigningKey struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id,omitempty"`
+	PublicKey string `json:"public_key"` // Base64
+}
+
+// signingKeyRoute publishes the public key of Ed25519 response signatures.
+// HMAC secrets are shared out of band.
+func (h *HTTPBridge) signingKeyRoute(r *mux.Router) {
+	if h.signer == nil || h.signer.privateKey == nil {
+		return
+	}
+	r.HandleFunc("/signing-key", h.handleSigningKey).Methods("GET")
+}
+
+// handleSigningKey serves the public key of response signatures
+func (h *HTTPBridge) handleSigningKey(w http.ResponseWriter, r *http.Request) {
+	h.writeJSONResponse(w, http.StatusOK, SigningKey{
+		Algorithm: h.signer.algorithm,
+		KeyID:     h.signer.keyID,
+		PublicKey: base64.StdEncoding.EncodeToString(h.signer.privateKey.Public().(ed25519.PublicKey)),
+	})
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestCanonicalBody(t *testing.T) {
+	a, canon := canonicalBody("application/json", []byte(`{"b": [1, 2.50], "a": {"y": "<x>", "x": null}}`+"\n"))
+	assert.Equal(t, canonJSON, canon)
+	b, _ := canonicalBody("application/json; charset=utf-8", []byte(`{"a":{"x":null,"y":"<x>"},"b":[1,2.50]}`))
+	assert.Equal(t, `{"a":{"x":null,"y":"<x>"},"b":[1,2.50]}`, string(a))
+	assert.Equal(t, a, b)
+
+	for contentType, body := range map[string]string{
+		"text/plain":       "a  b\n",
+		"application/json": "{broken",
+	} {
+		canonical, canon := canonicalBody(contentType, []byte(body))
+		assert.Equal(t, body, string(canonical))
+		assert.Equal(t, canonRaw, canon)
+	}
+}
+
+func TestCanonicalBody_TrailingData(t *testing.T) {
+	// Data after the document is signed as well, as part of the raw body
+	for _, body := range []string{`{"a":1}{"evil":2}`, `{"a":1} garbage`, `{"a":1}` + "\n" + `[2]`} {
+		canonical, canon := canonicalBody("application/json", []byte(body))
+		assert.Equal(t, body, string(canonical))
+		assert.Equal(t, canonRaw, canon, body)
+	}
+
+	signer, err := newResponseSigner(config.SigningConfig{Algorithm: config.SigningHMACSHA256, Key: "secret"})
+	require.NoError(t, err)
+	assert.NotEqual(t,
+		signer.sign(http.StatusOK, "/tools/list", "application/json", []byte(`{"a":1}`)),
+		signer.sign(http.StatusOK, "/tools/list", "application/json", []byte(`{"a":1}{"evil":2}`)))
+}
+
+// signedServer returns a test server signing responses with a configuration
+func signedServer(signing config.SigningConfig) *Server {
+	s := newTestServer()
+	s.config = &config.Config{}
+	s.config.MCP.HTTP.Signing = signing
+	return s
+}
+
+// parseSignature splits a signature header into its fields
+func parseSignature(t *testing.T, header string) map[string]string {
+	fields := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		require.True(t, ok, header)
+		fields[key] = value
+	}
+	return fields
+}
+
+func TestSigningMiddleware_HMAC(t *testing.T) {
+	s := signedServer(config.SigningConfig{Algorithm: config.SigningHMACSHA256, Key: "secret", KeyID: "2026-10"})
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/list", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	signature := parseSignature(t, rec.Header().Get(SignatureHeader))
+	assert.Equal(t, "hmac-sha256", signature["alg"])
+	assert.Equal(t, "2026-10", signature["keyid"])
+
+	assert.Equal(t, canonJSON, signature["canon"])
+	canonical, _ := canonicalBody(rec.Header().Get("Content-Type"), rec.Body.Bytes())
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("200 /tools/list\n"))
+	mac.Write(canonical)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), signature["sig"])
+
+	// Error responses are signed as well
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(SignatureHeader))
+
+	// Without an Ed25519 key there is no public key to publish
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/signing-key", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSigningMiddleware_Ed25519(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	s := signedServer(config.SigningConfig{Algorithm: config.SigningEd25519, Key: base64.StdEncoding.EncodeToString(seed)})
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/signing-key", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var key SigningKey
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &key))
+	assert.Equal(t, "ed25519", key.Algorithm)
+	publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, ed25519.NewKeyFromSeed(seed).Public(), ed25519.PublicKey(publicKey))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/list?page=1", nil))
+	signature := parseSignature(t, rec.Header().Get(SignatureHeader))
+	assert.NotContains(t, signature, "keyid")
+	sig, err := base64.StdEncoding.DecodeString(signature["sig"])
+	require.NoError(t, err)
+
+	// A relay may reformat the JSON body without breaking the signature
+	var body interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	relayed, err := json.MarshalIndent(body, "", "    ")
+	require.NoError(t, err)
+	canonical, _ := canonicalBody("application/json", relayed)
+	assert.True(t, ed25519.Verify(publicKey, signedMessage(http.StatusOK, "/tools/list?page=1", canonical), sig))
+	modified, _ := canonicalBody("application/json", []byte(`{"tools":[{"name":"search_players"}]}`))
+	assert.False(t, ed25519.Verify(publicKey, signedMessage(http.StatusOK, "/tools/list?page=1", modified), sig))
+
+	// The signature does not verify for another request or status
+	assert.False(t, ed25519.Verify(publicKey, signedMessage(http.StatusOK, "/resources/list", canonical), sig))
+	assert.False(t, ed25519.Verify(publicKey, signedMessage(http.StatusNotFound, "/tools/list?page=1", canonical), sig))
+}
+
+func TestSigningMiddleware_Disabled(t *testing.T) {
+	s := signedServer(config.SigningConfig{})
+	router := NewHTTPBridge(s, s.logger).SetupRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tools/list", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(SignatureHeader))
+}
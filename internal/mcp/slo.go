@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+// newSLOTracker creates the tracker of the service level objectives of the
+// tools, nil if tracking is disabled
+func newSLOTracker(cfg config.SLOConfig, logger *logrus.Logger) *telemetry.SLOTracker {
+	if cfg.Window <= 0 {
+		return nil
+	}
+	targets := make(map[string]telemetry.SLOTarget, len(cfg.Tools))
+	for tool, target := range cfg.Tools {
+		targets[tool] = telemetry.SLOTarget{Availability: target.Availability, Latency: target.Latency, LatencyTarget: target.LatencyTarget}
+	}
+	return telemetry.NewSLOTracker(telemetry.SLOOptions{
+		Window:            cfg.Window,
+		Default:           telemetry.SLOTarget{Availability: cfg.Availability, Latency: cfg.Latency, LatencyTarget: cfg.LatencyTarget},
+		Targets:           targets,
+		AlertWindow:       cfg.AlertWindow,
+		BurnRateThreshold: cfg.BurnRateThreshold,
+		AlertMinCalls:     cfg.AlertMinCalls,
+	}, logger)
+}
+
+// handleGetSLOStatus reports the service level objectives of the tools
+func (s *Server) handleGetSLOStatus(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	if s.slo == nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: SLO tracking is disabled, set telemetry.slo.window to enable it",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	data, _ := json.MarshalIndent(s.slo.Report(), "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+func TestGetSLOStatus(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	result, err := s.handleGetSLOStatus(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError, "tracking is disabled")
+
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.slo = newSLOTracker(config.SLOConfig{Window: time.Hour, Availability: 0.99, AlertWindow: 5 * time.Minute, BurnRateThreshold: 6}, nil)
+	s.registerTools()
+
+	for _, args := range []map[string]interface{}{{}, {"player_id": "C0327-297"}} {
+		_, err := s.tools["get_player_profile"](context.Background(), args)
+		require.NoError(t, err)
+	}
+
+	result, err = s.handleGetSLOStatus(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	var report telemetry.SLOReport
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &report))
+	require.Len(t, report.Tools, 1)
+	require.Len(t, report.Tools[0].Objectives, 1, "no latency objective without a threshold")
+	availability := report.Tools[0].Objectives[0]
+	assert.Equal(t, int64(2), availability.Calls)
+	assert.Equal(t, int64(1), availability.Bad, "invalid arguments are no failures")
+	assert.True(t, availability.Alerting)
+
+	// The error budgets are exported without the system metrics
+	s.config = &config.Config{Telemetry: config.TelemetryConfig{SLO: config.SLOConfig{Export: true}}}
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `portal64_slo_attained{tool="get_player_profile",objective="availability"} 0.5`)
+	assert.NotContains(t, rec.Body.String(), "portal64_goroutines")
+}
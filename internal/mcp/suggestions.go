@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// maxSearchSuggestions bounds the number of suggestions of an empty search
+	maxSearchSuggestions = 5
+	// minSuggestionRelevance is the relevance a hit of a relaxed search needs
+	// to be suggested
+	minSuggestionRelevance = 0.5
+	// suggestionCandidates is the number of hits fetched per relaxed search
+	suggestionCandidates = 50
+)
+
+// SearchSuggestion is a likely correction of a search that found nothing
+type SearchSuggestion struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Relevance float64 `json:"relevance"`
+}
+
+// searchResult is a search response with suggestions for empty results
+type searchResult struct {
+	*api.SearchResponse
+	Suggestions []SearchSuggestion `json:"suggestions,omitempty"`
+}
+
+// relaxedQueries returns less specific variants of a normalized query that
+// find misspelled names: the longest word alone, a prefix of it, and the
+// prefix spelled with umlauts
+func relaxedQueries(normalized string) []string {
+	words := strings.Fields(normalized)
+	longest := ""
+	for _, w := range words {
+		if len([]rune(w)) > len([]rune(longest)) {
+			longest = w
+		}
+	}
+
+	var candidates []string
+	if len(words) > 1 {
+		candidates = append(candidates, longest)
+	}
+	if runes := []rune(longest); len(runes) >= 4 {
+		prefix := string(runes[:max(3, (len(runes)+1)/2)])
+		candidates = append(candidates, prefix, umlautRestorer.Replace(prefix))
+	}
+
+	seen := map[string]bool{normalized: true}
+	var queries []string
+	for _, q := range candidates {
+		if !seen[q] {
+			seen[q] = true
+			queries = append(queries, q)
+		}
+	}
+	return queries
+}
+
+// searchSuggestionHits returns the hits of a search response as suggestions
+// together with their descriptions for relevance scoring
+func searchSuggestionHits(result *api.SearchResponse) ([]SearchSuggestion, []searchHit) {
+	var suggestions []SearchSuggestion
+	var hits []searchHit
+	switch data := result.Data.(type) {
+	case []api.PlayerResponse:
+		for _, p := range data {
+			suggestions = append(suggestions, SearchSuggestion{ID: p.ID, Name: strings.TrimSpace(p.Firstname + " " + p.Name)})
+			hits = append(hits, searchHit{names: []string{p.Name + " " + p.Firstname}})
+		}
+	case []api.ClubResponse:
+		for _, c := range data {
+			suggestions = append(suggestions, SearchSuggestion{ID: c.ID, Name: c.Name})
+			hits = append(hits, searchHit{names: []string{c.Name, c.ShortName}})
+		}
+	case []api.TournamentResponse:
+		for _, t := range data {
+			suggestions = append(suggestions, SearchSuggestion{ID: t.ID, Name: t.Name})
+			hits = append(hits, searchHit{names: []string{t.Name}})
+		}
+	}
+	return suggestions, hits
+}
+
+// suggestSearches runs relaxed variants of a search that found nothing and
+// returns the hits most similar to the query. Failed searches are skipped.
+func suggestSearches(ctx context.Context, params api.SearchParams, search func(api.SearchParams) (*api.SearchResponse, error)) []SearchSuggestion {
+	scorer := newRelevanceScorer(params.Query)
+	seen := make(map[string]bool)
+	var suggestions []SearchSuggestion
+
+	for _, query := range relaxedQueries(normalizeQuery(params.Query)) {
+		if ctx.Err() != nil {
+			break
+		}
+		relaxed := params
+		relaxed.Query, relaxed.Limit, relaxed.Offset = query, suggestionCandidates, 0
+		result, err := search(relaxed)
+		if err != nil {
+			continue
+		}
+		candidates, hits := searchSuggestionHits(result)
+		for i, suggestion := range candidates {
+			if seen[suggestion.ID] {
+				continue
+			}
+			seen[suggestion.ID] = true
+			suggestion.Relevance, _ = scorer.score(hits[i])
+			if suggestion.Relevance >= minSuggestionRelevance {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Relevance > suggestions[j].Relevance
+	})
+	if len(suggestions) > maxSearchSuggestions {
+		suggestions = suggestions[:maxSearchSuggestions]
+	}
+	return suggestions
+}
+
+// withSuggestions adds suggestions to a search response without hits
+func withSuggestions(ctx context.Context, result *api.SearchResponse, params api.SearchParams, search func(api.SearchParams) (*api.SearchResponse, error)) *searchResult {
+	response := &searchResult{SearchResponse: result}
+	if strings.TrimSpace(params.Query) != "" && searchHitCount(result) == 0 {
+		response.Suggestions = suggestSearches(ctx, params, search)
+	}
+	return response
+}
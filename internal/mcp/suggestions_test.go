@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestRelaxedQueries(t *testing.T) {
+	assert.Equal(t, []string{"schm"}, relaxedQueries("schmidt"))
+	assert.Equal(t, []string{"muel", "mül"}, relaxedQueries("mueller"))
+	assert.Equal(t, []string{"schmidt", "schm"}, relaxedQueries("hans schmidt"))
+	assert.Empty(t, relaxedQueries("abc"))
+}
+
+func TestHandleSearchPlayers_Suggestions(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "schm":
+			w.Write([]byte(`{"data": [
+				{"id": "C0327-1", "name": "Schmitz", "firstname": "Anna"},
+				{"id": "C0327-2", "name": "Schmidt", "firstname": "Hans"},
+				{"id": "C0327-3", "name": "Schmalfeldt", "firstname": "Otto"}
+			]}`))
+		case "schmidt":
+			w.Write([]byte(`{"data": [{"id": "C0327-2", "name": "Schmidt", "firstname": "Hans"}]}`))
+		default:
+			w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+
+	var response struct {
+		Data        []RankedPlayer     `json:"data"`
+		Suggestions []SearchSuggestion `json:"suggestions"`
+	}
+
+	result, err := s.handleSearchPlayers(context.Background(), map[string]interface{}{"query": "Schmidtt"})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &response))
+	assert.Empty(t, response.Data)
+	require.NotEmpty(t, response.Suggestions)
+	assert.Equal(t, SearchSuggestion{ID: "C0327-2", Name: "Hans Schmidt", Relevance: response.Suggestions[0].Relevance}, response.Suggestions[0])
+	for _, suggestion := range response.Suggestions {
+		assert.GreaterOrEqual(t, suggestion.Relevance, minSuggestionRelevance)
+	}
+
+	// Searches with hits carry no suggestions
+	result, err = s.handleSearchPlayers(context.Background(), map[string]interface{}{"query": "Schmidt"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Content[0].Text, "suggestions")
+}
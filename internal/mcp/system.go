@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+// Statuses of the system health
+const (
+	systemStatusOK       = "ok"
+	systemStatusDegraded = "degraded"
+)
+
+// SystemHealth reports the latest system metrics sample and the health
+// thresholds it exceeds
+type SystemHealth struct {
+	Status   string                  `json:"status"`
+	Exceeded []string                `json:"exceeded,omitempty"`
+	Metrics  telemetry.SystemMetrics `json:"metrics"`
+}
+
+// systemHealth returns the system health, nil if the sampler is disabled or
+// has not sampled yet
+func (s *Server) systemHealth() *SystemHealth {
+	if s.system == nil {
+		return nil
+	}
+	latest, ok := s.system.Latest()
+	if !ok {
+		return nil
+	}
+	health := &SystemHealth{Status: systemStatusOK, Exceeded: s.system.Health(), Metrics: latest}
+	if len(health.Exceeded) > 0 {
+		health.Status = systemStatusDegraded
+	}
+	return health
+}
+
+// metricsRoute registers the export of the system metrics and the error
+// budgets, if either is enabled
+func (h *HTTPBridge) metricsRoute(r *mux.Router) {
+	if !h.exportsSystemMetrics() && !h.exportsSLOs() {
+		return
+	}
+	r.HandleFunc("/metrics", h.handleMetrics).Methods("GET")
+}
+
+// exportsSystemMetrics reports whether /metrics serves the system metrics
+func (h *HTTPBridge) exportsSystemMetrics() bool {
+	cfg := h.server.config
+	return cfg != nil && cfg.Telemetry.System.Export && h.server.system != nil
+}
+
+// exportsSLOs reports whether /metrics serves the error budgets
+func (h *HTTPBridge) exportsSLOs() bool {
+	cfg := h.server.config
+	return cfg != nil && cfg.Telemetry.SLO.Export && h.server.slo != nil
+}
+
+// handleMetrics serves the latest system metrics sample and the error
+// budgets of the tools in the Prometheus text format
+func (h *HTTPBridge) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if h.exportsSystemMetrics() {
+		sample, ok := h.server.system.Latest()
+		if !ok {
+			sample = h.server.system.Sample()
+		}
+		if err := telemetry.WritePrometheus(w, sample); err != nil {
+			h.logger.WithError(err).Warn("Failed to write metrics")
+			return
+		}
+	}
+	if h.exportsSLOs() {
+		if err := telemetry.WriteSLOPrometheus(w, h.server.slo.Report()); err != nil {
+			h.logger.WithError(err).Warn("Failed to write metrics")
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/telemetry"
+)
+
+func TestSystemHealth(t *testing.T) {
+	s := newTestServer()
+	assert.Nil(t, s.systemHealth())
+
+	s.system = telemetry.NewSampler("", telemetry.Thresholds{}, nil)
+	assert.Nil(t, s.systemHealth(), "no sample yet")
+
+	s.system.Sample()
+	health := s.systemHealth()
+	require.NotNil(t, health)
+	assert.Equal(t, systemStatusOK, health.Status)
+	assert.Empty(t, health.Exceeded)
+	assert.Positive(t, health.Metrics.Goroutines)
+
+	s.system = telemetry.NewSampler("", telemetry.Thresholds{MaxGoroutines: 1}, nil)
+	s.system.Sample()
+	health = s.systemHealth()
+	require.NotNil(t, health)
+	assert.Equal(t, systemStatusDegraded, health.Status)
+	assert.Len(t, health.Exceeded, 1)
+}
+
+func TestMetricsRoute(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{Telemetry: config.TelemetryConfig{System: config.SystemMetricsConfig{Export: true}}}
+	s.system = telemetry.NewSampler("", telemetry.Thresholds{}, nil)
+
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), "portal64_goroutines ")
+
+	s.config.Telemetry.System.Export = false
+	rec = httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
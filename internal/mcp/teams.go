@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// filterTeamsBySeason returns the teams whose season contains season, so
+// "2023" matches "2023/24"
+func filterTeamsBySeason(teams []api.ClubTeam, season string) []api.ClubTeam {
+	season = strings.TrimSpace(season)
+	if season == "" {
+		return teams
+	}
+	filtered := []api.ClubTeam{}
+	for _, team := range teams {
+		if strings.Contains(team.Season, season) {
+			filtered = append(filtered, team)
+		}
+	}
+	return filtered
+}
+
+// handleGetClubTeams handles club team listing requests
+func (s *Server) handleGetClubTeams(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, ok := args["club_id"].(string)
+	if !ok || clubID == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id is required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	teams, err := s.apiClient.GetClubTeams(ctx, clubID)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting club teams: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	season, _ := args["season"].(string)
+	teams = filterTeamsBySeason(teams, season)
+	if teams == nil {
+		teams = []api.ClubTeam{}
+	}
+
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"club_id": clubID,
+		"teams":   teams,
+		"count":   len(teams),
+	}, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// handleGetTeamRoster handles team roster requests
+func (s *Server) handleGetTeamRoster(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	clubID, _ := args["club_id"].(string)
+	team, _ := args["team"].(string)
+	if clubID == "" || team == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: club_id and team are required",
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	roster, err := s.apiClient.GetTeamRoster(ctx, clubID, team)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting team roster: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	if len(roster.Players) == 0 {
+		addWarning(ctx, "The API provides no player assignments for this team")
+	}
+
+	data, _ := json.MarshalIndent(roster, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
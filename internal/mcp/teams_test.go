@@ -0,0 +1,22 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestFilterTeamsBySeason(t *testing.T) {
+	teams := []api.ClubTeam{
+		{Name: "1. Mannschaft", Season: "2023/24"},
+		{Name: "1. Mannschaft", Season: "2024/25"},
+		{Name: "Jugend"},
+	}
+
+	assert.Equal(t, teams, filterTeamsBySeason(teams, ""))
+	assert.Equal(t, "2023/24", filterTeamsBySeason(teams, "2023")[0].Season)
+	assert.Equal(t, "2024/25", filterTeamsBySeason(teams, "2024/25")[0].Season)
+	assert.Empty(t, filterTeamsBySeason(teams, "2019"))
+}
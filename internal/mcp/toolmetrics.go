@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ToolStats are the calls, failures and latencies of a tool since the
+// server started
+type ToolStats struct {
+	Name         string     `json:"name"`
+	Calls        int64      `json:"calls"`
+	Errors       int64      `json:"errors"` // Calls with an error result, including invalid arguments
+	AvgLatencyMS float64    `json:"avg_latency_ms"`
+	MaxLatencyMS float64    `json:"max_latency_ms"`
+	LastCall     *time.Time `json:"last_call,omitempty"`
+}
+
+// toolCounter accumulates the calls of a tool
+type toolCounter struct {
+	calls    int64
+	errors   int64
+	total    time.Duration
+	max      time.Duration
+	lastCall time.Time
+}
+
+// toolMetrics records the calls of all tools
+type toolMetrics struct {
+	mu    sync.Mutex
+	tools map[string]*toolCounter
+}
+
+// record adds a call of a tool
+func (m *toolMetrics) record(name string, latency time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tools == nil {
+		m.tools = make(map[string]*toolCounter)
+	}
+	counter, ok := m.tools[name]
+	if !ok {
+		counter = &toolCounter{}
+		m.tools[name] = counter
+	}
+	counter.calls++
+	if failed {
+		counter.errors++
+	}
+	counter.total += latency
+	counter.max = max(counter.max, latency)
+	counter.lastCall = time.Now()
+}
+
+// stats returns the statistics of the tools called so far, ordered by name
+func (m *toolMetrics) stats() []ToolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := make([]ToolStats, 0, len(m.tools))
+	for name, counter := range m.tools {
+		lastCall := counter.lastCall
+		stats = append(stats, ToolStats{
+			Name:         name,
+			Calls:        counter.calls,
+			Errors:       counter.errors,
+			AvgLatencyMS: float64(counter.total) / float64(counter.calls) / float64(time.Millisecond),
+			MaxLatencyMS: float64(counter.max) / float64(time.Millisecond),
+			LastCall:     &lastCall,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// measureTool wraps a tool handler so that its calls are counted in the
+// tool metrics and the service level objectives. Calls rejecting invalid
+// arguments do not count against the availability objective, and calls
+// cancelled by the client not at all.
+func (s *Server) measureTool(name string, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		start := time.Now()
+		result, err := handler(ctx, args)
+		latency := time.Since(start)
+		s.toolMetrics.record(name, latency, err != nil || (result != nil && result.IsError))
+		if s.slo != nil && ctx.Err() == nil {
+			s.slo.Record(name, latency, err != nil || (result != nil && result.IsError && !invalidArguments(result)))
+		}
+		return result, err
+	}
+}
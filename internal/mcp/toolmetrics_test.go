@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolMetrics(t *testing.T) {
+	s := newTestServer()
+	s.registerTools()
+
+	_, err := s.tools["get_player_profile"](context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	_, err = s.tools["convert_rating"](context.Background(), map[string]interface{}{"rating": float64(1800)})
+	require.NoError(t, err)
+	_, err = s.tools["convert_rating"](context.Background(), map[string]interface{}{"rating": float64(1900)})
+	require.NoError(t, err)
+
+	stats := s.toolMetrics.stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "convert_rating", stats[0].Name)
+	assert.Equal(t, int64(2), stats[0].Calls)
+	assert.Zero(t, stats[0].Errors)
+	assert.GreaterOrEqual(t, stats[0].MaxLatencyMS, stats[0].AvgLatencyMS)
+	assert.NotNil(t, stats[0].LastCall)
+	assert.Equal(t, "get_player_profile", stats[1].Name)
+	assert.Equal(t, int64(1), stats[1].Errors)
+
+	result, err := s.handleGetRuntimeStats(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	var runtime RuntimeStats
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &runtime))
+	assert.Len(t, runtime.Tools, 2)
+}
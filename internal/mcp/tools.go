@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/features"
 )
 
 // registerTools registers all available MCP tools
@@ -17,34 +19,83 @@ func (s *Server) registerTools() {
 	s.tools["search_clubs"] = s.handleSearchClubs
 	s.tools["search_tournaments"] = s.handleSearchTournaments
 	s.tools["get_recent_tournaments"] = s.handleGetRecentTournaments
+	s.tools["get_upcoming_tournaments"] = s.handleGetUpcomingTournaments
+	s.tools["get_tournament_changes"] = s.handleGetTournamentChanges
 	s.tools["search_tournaments_by_date"] = s.handleSearchTournamentsByDate
+	s.tools["get_tournament_series"] = s.handleGetTournamentSeries
+	s.tools["get_organizer_tournaments"] = s.handleGetOrganizerTournaments
+	s.tools["resolve_id"] = s.handleResolveID
+	s.tools["search_all"] = s.handleSearchAll
 
 	// Detail tools
 	s.tools["get_player_profile"] = s.handleGetPlayerProfile
 	s.tools["get_club_profile"] = s.handleGetClubProfile
 	s.tools["get_tournament_details"] = s.handleGetTournamentDetails
 	s.tools["get_club_players"] = s.handleGetClubPlayers
+	s.tools["export_club_data"] = s.handleExportClubData
+	s.tools["get_qr_code"] = s.handleGetQRCode
 
 	// Analysis tools
 	s.tools["get_player_rating_history"] = s.handleGetPlayerRatingHistory
+	s.tools["render_rating_chart"] = s.handleRenderRatingChart
+	s.tools["get_player_rating_at_date"] = s.handleGetPlayerRatingAtDate
+	s.tools["get_player_form"] = s.handleGetPlayerForm
+	s.tools["get_player_percentile"] = s.handleGetPlayerPercentile
 	s.tools["get_club_statistics"] = s.handleGetClubStatistics
+	s.tools["render_club_distribution"] = s.handleRenderClubDistribution
+	s.tools["get_club_teams"] = s.handleGetClubTeams
+	s.tools["get_club_officials"] = s.handleGetClubOfficials
+	s.tools["get_reactivation_candidates"] = s.handleGetReactivationCandidates
+	s.tools["get_team_roster"] = s.handleGetTeamRoster
+	s.tools["get_region_statistics"] = s.handleGetRegionStatistics
+	s.tools["get_region_activity"] = s.handleGetRegionActivity
+	s.tools["calculate_tournament_dwz"] = s.handleCalculateTournamentDWZ
+	s.tools["convert_rating"] = s.handleConvertRating
+	s.tools["get_club_youth_statistics"] = s.handleGetClubYouthStatistics
+	s.tools["find_clubs_near"] = s.handleFindClubsNear
 
 	// Administrative tools
 	s.tools["check_api_health"] = s.handleCheckAPIHealth
 	s.tools["get_cache_stats"] = s.handleGetCacheStats
+	s.tools["get_connection_stats"] = s.handleGetConnectionStats
+	s.tools["diagnose_upstream_connection"] = s.handleDiagnoseUpstreamConnection
+	s.tools["debug_upstream_request"] = s.handleDebugUpstreamRequest
+	s.tools["check_ssl_config"] = s.handleCheckSSLConfig
+	s.tools["get_runtime_stats"] = s.handleGetRuntimeStats
+	s.tools["get_slo_status"] = s.handleGetSLOStatus
+	s.tools["get_effective_config"] = s.handleGetEffectiveConfig
 	s.tools["get_regions"] = s.handleGetRegions
 	s.tools["get_region_addresses"] = s.handleGetRegionAddresses
+	s.tools["search_officials"] = s.handleSearchOfficials
+	s.tools["find_possible_duplicates"] = s.handleFindPossibleDuplicates
+	s.tools["submit_address_correction"] = s.handleSubmitAddressCorrection
+	s.tools["draft_contact_correction"] = s.handleDraftContactCorrection
+	s.tools["get_feature_flags"] = s.handleGetFeatureFlags
+	s.tools["set_feature_flag"] = s.handleSetFeatureFlag
+
+	// Accept common ID variants (c0327-297, C0327/297) in all tools, follow
+	// aliases of historic club IDs, keep limit and offset within the bounds
+	// of the schema, skip the response cache on request, warn of data served
+	// from the cache during upstream maintenance, link entities to
+	// their web pages, recover from panics in any of them, report failures
+	// and measure the calls. Calls are scheduled by priority; calls shed while overloaded are
+	// neither reported nor measured, neither are calls of write tools
+	// rejected in read-only mode.
+	for name, handler := range s.tools {
+		s.tools[name] = s.guardWrites(name, s.shedLoad(name, s.measureTool(name, s.recoverTool(name, s.reportToolErrors(name, s.checkBounds(name, s.addDeepLinks(name, normalizeIDArgs(s.followClubAliases(s.warnMaintenance(name, bypassCache(handler)))))))))))
+	}
 }
 
-// GetToolDefinition returns the schema definition for a tool
-func (s *Server) GetToolDefinition(name string) Tool {
-	definitions := map[string]Tool{
+// toolSchemas returns the schema definitions of the tools by name
+func toolSchemas() map[string]Tool {
+	return map[string]Tool{
 		"search_players": {
 			Name:        "search_players",
 			Description: "Search for players with filtering and pagination support. Players have both ID (C0101-123 format) and PKZ (unique across club changes) identifiers.",
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
+					"filter": filterSchema,
 					"query": map[string]interface{}{
 						"type":        "string",
 						"description": "Search query for player name, ID, or PKZ",
@@ -74,15 +125,54 @@ func (s *Server) GetToolDefinition(name string) Tool {
 						"type":        "boolean",
 						"description": "Filter for active players only",
 					},
+					"gender": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by gender",
+						"enum":        []string{"male", "female", "divers"},
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by FIDE title (only applied if the API provides titles)",
+						"enum":        []string{"GM", "IM", "FM", "CM", "WGM", "WIM", "WFM", "WCM"},
+					},
+					"age_class": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by age class derived from birth year (youth classes include younger players)",
+						"enum":        ageClassNames,
+					},
+					"year": map[string]interface{}{
+						"type":        "integer",
+						"description": "Reference year for age classes (default: current year)",
+					},
 				},
 			},
 		},
+		"resolve_id": {
+			Name:        "resolve_id",
+			Description: "Validate and normalize a player (C0101-123), club (C0101) or tournament (C350-C01-SMU) ID, check that it exists and suggest likely corrections for malformed or unknown IDs",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID to resolve, e.g. c0327/297",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Expected ID kind (detected automatically if omitted)",
+						"enum":        []string{"player", "club", "tournament"},
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
 		"search_clubs": {
 			Name:        "search_clubs",
 			Description: "Search for clubs with geographic and membership filtering",
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
+					"filter": filterSchema,
 					"query": map[string]interface{}{
 						"type":        "string",
 						"description": "Search query for club name",
@@ -130,6 +220,10 @@ func (s *Server) GetToolDefinition(name string) Tool {
 						"type":        "string",
 						"description": "Player ID in format C0101-123",
 					},
+					"include_elo": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Add a heuristic Elo estimate from the current DWZ (see convert_rating)",
+					},
 				},
 				Required: []string{"player_id"},
 			},
@@ -162,6 +256,58 @@ func (s *Server) GetToolDefinition(name string) Tool {
 				Required: []string{"player_id"},
 			},
 		},
+		"render_rating_chart": {
+			Name:        "render_rating_chart",
+			Description: "Render a player's DWZ history as a line chart image (PNG or SVG) that chat clients can display directly",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: chartSchema(map[string]interface{}{
+					"player_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Player ID in format C0101-123",
+					},
+				}),
+				Required: []string{"player_id"},
+			},
+		},
+		"get_player_rating_at_date": {
+			Name:        "get_player_rating_at_date",
+			Description: "Get a player's DWZ at a historical date, reconstructed from the rating history (e.g. the rating in January 2022)",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"player_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Player ID in format C0101-123",
+					},
+					"date": map[string]interface{}{
+						"type":        "string",
+						"description": "Date: " + dateExpressionHelp + ". Periods mean their last day.",
+					},
+				},
+				Required: []string{"player_id", "date"},
+			},
+		},
+		"get_player_form": {
+			Name:        "get_player_form",
+			Description: "Get a player's current form: trend (improving/stable/declining), average DWZ change over the last evaluations, score percentage and gain/loss streaks",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"player_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Player ID in format C0101-123",
+					},
+					"evaluations": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of recent evaluations considered (default: 5)",
+						"minimum":     1,
+						"maximum":     50,
+					},
+				},
+				Required: []string{"player_id"},
+			},
+		},
 		"get_club_profile": {
 			Name:        "get_club_profile",
 			Description: "Get detailed club profile information",
@@ -196,6 +342,7 @@ func (s *Server) GetToolDefinition(name string) Tool {
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
+					"filter": filterSchema,
 					"club_id": map[string]interface{}{
 						"type":        "string",
 						"description": "Club ID",
@@ -223,6 +370,15 @@ func (s *Server) GetToolDefinition(name string) Tool {
 						"type":        "boolean",
 						"description": "Filter for active players only",
 					},
+					"age_class": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by age class derived from birth year (youth classes include younger players)",
+						"enum":        ageClassNames,
+					},
+					"year": map[string]interface{}{
+						"type":        "integer",
+						"description": "Reference year for age classes (default: current year)",
+					},
 				},
 				Required: []string{"club_id"},
 			},
@@ -237,6 +393,462 @@ func (s *Server) GetToolDefinition(name string) Tool {
 						"type":        "string",
 						"description": "Club ID",
 					},
+					"include_members": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Walk all member pages and include member statistics (reports progress notifications)",
+					},
+					"as_of": map[string]interface{}{
+						"type":        "string",
+						"description": "Historical date (" + dateExpressionHelp + "; periods mean their last day); returns member statistics with each current member's DWZ at that date",
+					},
+				},
+				Required: []string{"club_id"},
+			},
+		},
+		"get_player_percentile": {
+			Name:        "get_player_percentile",
+			Description: "Rank a player's DWZ within their club, region and optionally all of Germany, returning percentile and rank. Region and national distributions are snapshots refreshed by a background job.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"player_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Player ID in format C0101-123",
+					},
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Region to rank within (default: the region of the player's club)",
+					},
+					"include_national": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also rank within the national distribution, if a snapshot is available",
+					},
+				},
+				Required: []string{"player_id"},
+			},
+		},
+		"export_club_data": {
+			Name:        "export_club_data",
+			Description: "Export a club's members (CSV), member statistics (JSON) and recent tournaments (CSV) as a ZIP archive. Returns a signed download URL of the HTTP bridge that expires after a short time.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+				},
+				Required: []string{"club_id"},
+			},
+		},
+		"get_qr_code": {
+			Name:        "get_qr_code",
+			Description: "Render the link to the public web page of a player, club or tournament as a QR code PNG image, e.g. for flyers and posters",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "Player (C0101-123), club (C0101) or tournament (C350-C01-SMU) ID",
+					},
+					"scale": map[string]interface{}{
+						"type":        "integer",
+						"description": "Pixels per module of the code (default: 8)",
+						"minimum":     1,
+						"maximum":     maxQRScale,
+					},
+					"error_correction": map[string]interface{}{
+						"type":        "string",
+						"description": "Error correction level, H survives a logo printed over the code (default: M)",
+						"enum":        []string{"L", "M", "Q", "H"},
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		"render_club_distribution": {
+			Name:        "render_club_distribution",
+			Description: "Render the DWZ distribution of a club's rated members as a histogram image (PNG or SVG) that chat clients can display directly",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: chartSchema(map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+					"bucket_size": map[string]interface{}{
+						"type":        "integer",
+						"description": "DWZ range of each bar (default: 100)",
+						"enum":        []int{50, 100, 200},
+					},
+				}),
+				Required: []string{"club_id"},
+			},
+		},
+		"get_club_teams": {
+			Name:        "get_club_teams",
+			Description: "Get the league teams of a club with league, division and season",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+					"season": map[string]interface{}{
+						"type":        "string",
+						"description": "Only teams of this season (e.g. 2023/24 or 2023)",
+					},
+				},
+				Required: []string{"club_id"},
+			},
+		},
+		"get_club_officials": {
+			Name:        "get_club_officials",
+			Description: "Get the officials of a club (president, vice president, secretary, treasurer, coach) from its contact data, completed with email, phone and address from the regional address data matched by name or email",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+				},
+				Required: []string{"club_id"},
+			},
+		},
+		"get_reactivation_candidates": {
+			Name:        "get_reactivation_candidates",
+			Description: "List a club's inactive members for reactivation, most recently evaluated first and by their last DWZ, with the date and tournament of their last evaluation. Reports progress notifications while rating histories load.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+					"min_dwz": map[string]interface{}{
+						"type":        "integer",
+						"description": "Only members whose last DWZ is at least this",
+						"minimum":     0,
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of members (default: 50)",
+						"minimum":     1,
+						"maximum":     500,
+					},
+				},
+				Required: []string{"club_id"},
+			},
+		},
+		"get_team_roster": {
+			Name:        "get_team_roster",
+			Description: "Get a club team with its league, division, season and the players assigned to its boards",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+					"team": map[string]interface{}{
+						"type":        "string",
+						"description": "Team ID or name (e.g. 1. Mannschaft)",
+					},
+				},
+				Required: []string{"club_id", "team"},
+			},
+		},
+		"get_region_statistics": {
+			Name:        "get_region_statistics",
+			Description: "Aggregate club and membership statistics across all clubs of a region. Reports progress notifications with partial results per page.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Region name (e.g. Württemberg)",
+					},
+				},
+				Required: []string{"region"},
+			},
+		},
+		"get_region_activity": {
+			Name:        "get_region_activity",
+			Description: "Month-by-month count of a region's tournaments and their participants over a date range (default: the last 12 months), e.g. for a heatmap. Tournaments count in the month they start. Reports progress notifications per month searched.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Region by state or federation name (e.g. Württemberg), or the letter of its tournament IDs (e.g. C)",
+					},
+					"start_date": map[string]interface{}{
+						"type":        "string",
+						"description": "First day of the range: " + dateExpressionHelp + ". The range covers whole months.",
+					},
+					"end_date": map[string]interface{}{
+						"type":        "string",
+						"description": "Last day of the range, in the same formats",
+					},
+					"period": map[string]interface{}{
+						"type":        "string",
+						"description": "Whole range instead of start_date and end_date, e.g. \"last 2 years\" or \"2024\"",
+					},
+				},
+				Required: []string{"region"},
+			},
+		},
+		"find_clubs_near": {
+			Name:        "find_clubs_near",
+			Description: "Find chess clubs near a city or postal code, ranked by distance. Club positions are approximated by their city.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"location": map[string]interface{}{
+						"type":        "string",
+						"description": "City name or postal code (e.g. Stuttgart, 70173)",
+					},
+					"radius_km": map[string]interface{}{
+						"type":        "number",
+						"description": "Search radius in kilometers (default: 25)",
+						"minimum":     1,
+						"maximum":     200,
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of clubs (default: 10)",
+						"minimum":     1,
+						"maximum":     100,
+					},
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Restrict the search to a region (default: the state of the location)",
+					},
+				},
+				Required: []string{"location"},
+			},
+		},
+		"get_club_youth_statistics": {
+			Name:        "get_club_youth_statistics",
+			Description: "Summarize a club's youth members per age class (U8-U20) with member counts and DWZ averages",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID",
+					},
+					"year": map[string]interface{}{
+						"type":        "integer",
+						"description": "Reference year for age classes (default: current year)",
+					},
+				},
+				Required: []string{"club_id"},
+			},
+		},
+		"convert_rating": {
+			Name:        "convert_rating",
+			Description: "Heuristic estimate of a DWZ as Elo or of an Elo as DWZ, computed offline. There is no official conversion and the mapping is not fitted to rating data; the result is marked as a heuristic estimate and includes a rough margin and caveats.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"rating": map[string]interface{}{
+						"type":        "integer",
+						"description": "Rating to convert",
+						"minimum":     100,
+						"maximum":     3000,
+					},
+					"from": map[string]interface{}{
+						"type":        "string",
+						"description": "Rating system of the given rating (default: dwz)",
+						"enum":        []string{"dwz", "elo"},
+					},
+				},
+				Required: []string{"rating"},
+			},
+		},
+		"calculate_tournament_dwz": {
+			Name:        "calculate_tournament_dwz",
+			Description: "Offline dry-run of the DWZ evaluation: computes expected score, development coefficient and new DWZ for each player from pairings and results. Forfeits and games against unrated players are not rated.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"players": map[string]interface{}{
+						"type":        "array",
+						"description": "Participants with their rating before the tournament",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"id":    map[string]interface{}{"type": "string", "description": "Player identifier used in games"},
+								"name":  map[string]interface{}{"type": "string", "description": "Display name"},
+								"dwz":   map[string]interface{}{"type": "integer", "description": "Current DWZ (0 if unrated)"},
+								"index": map[string]interface{}{"type": "integer", "description": "Number of previous DWZ evaluations"},
+								"age":   map[string]interface{}{"type": "integer", "description": "Age in the evaluation year"},
+							},
+							"required": []string{"id", "dwz"},
+						},
+					},
+					"games": map[string]interface{}{
+						"type":        "array",
+						"description": "Pairings with results",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"white":  map[string]interface{}{"type": "string", "description": "Player ID with white"},
+								"black":  map[string]interface{}{"type": "string", "description": "Player ID with black"},
+								"result": map[string]interface{}{"type": "string", "description": "1-0, 0-1, ½-½ or forfeit (+-, -+, --)"},
+							},
+							"required": []string{"white", "black", "result"},
+						},
+					},
+				},
+				Required: []string{"players", "games"},
+			},
+		},
+		"search_all": {
+			Name:        "search_all",
+			Description: "Search players, clubs and tournaments by name in one call. Hits are grouped by type and ranked by relevance; groups are ordered by their best hit.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Name, ID or PKZ to search for",
+					},
+					"types": map[string]interface{}{
+						"type":        "array",
+						"description": "Entity types to search (default: all)",
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"players", "clubs", "tournaments"},
+						},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of hits per type (default: 10)",
+						"minimum":     1,
+						"maximum":     50,
+					},
+				},
+				Required: []string{"query"},
+			},
+		},
+		"search_officials": {
+			Name:        "search_officials",
+			Description: "Search chess officials across all regions' address data by name, role or email fragment. English role terms such as 'youth' or 'treasurer' also match German titles.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Free-text search over name, role, email and city",
+					},
+					"role": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by role (e.g. youth, president, Jugendwart)",
+					},
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by region code or name",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of results (default: 20)",
+						"minimum":     1,
+						"maximum":     100,
+					},
+				},
+			},
+		},
+		"find_possible_duplicates": {
+			Name:        "find_possible_duplicates",
+			Description: "Find player records with identical name and birth year under different IDs that may belong to the same person, scored by FIDE ID, gender, DWZ, club and status. Records sharing a PKZ are memberships in several clubs and not reported.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Player name whose records are compared",
+					},
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Compare the members of this club with all players sharing their surnames, instead of query",
+					},
+					"min_score": map[string]interface{}{
+						"type":        "number",
+						"description": "Lowest score of a reported pair (default: 0.5)",
+						"minimum":     0,
+						"maximum":     1,
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of pairs (default: 20)",
+						"minimum":     1,
+						"maximum":     100,
+					},
+				},
+			},
+		},
+		"submit_address_correction": {
+			Name:        "submit_address_correction",
+			Description: "Submit a correction of an official's address from get_region_addresses for review by the federation. Each call files a new correction.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"address_id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the address",
+					},
+					"field": map[string]interface{}{
+						"type":        "string",
+						"description": "Address field to correct",
+						"enum":        correctionFields,
+					},
+					"value": map[string]interface{}{
+						"type":        "string",
+						"description": "Corrected value, empty to remove the field",
+					},
+					"comment": map[string]interface{}{
+						"type":        "string",
+						"description": "Note for the reviewer, e.g. the source of the correction",
+					},
+				},
+				Required: []string{"address_id", "field", "value"},
+			},
+		},
+		"draft_contact_correction": {
+			Name:        "draft_contact_correction",
+			Description: "Draft a request to correct the contact details of a club: an email to the federation listing current and proposed values. Only fields given and different from the current details are included. With send, the request is emailed to the configured recipient.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Club ID (e.g., C0327)",
+					},
+					"president":      map[string]interface{}{"type": "string", "description": "Proposed president"},
+					"vice_president": map[string]interface{}{"type": "string", "description": "Proposed vice president"},
+					"secretary":      map[string]interface{}{"type": "string", "description": "Proposed secretary"},
+					"treasurer":      map[string]interface{}{"type": "string", "description": "Proposed treasurer"},
+					"coach":          map[string]interface{}{"type": "string", "description": "Proposed coach"},
+					"email":          map[string]interface{}{"type": "string", "description": "Proposed club email"},
+					"phone":          map[string]interface{}{"type": "string", "description": "Proposed club phone"},
+					"website":        map[string]interface{}{"type": "string", "description": "Proposed website"},
+					"address":        map[string]interface{}{"type": "string", "description": "Proposed postal address"},
+					"reason": map[string]interface{}{
+						"type":        "string",
+						"description": "Reason for the correction, e.g. the date of the annual meeting",
+					},
+					"submitter": map[string]interface{}{
+						"type":        "string",
+						"description": "Name and email of the person requesting the correction",
+					},
+					"send": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Email the request to the configured recipient (default: false, only draft)",
+					},
 				},
 				Required: []string{"club_id"},
 			},
@@ -247,92 +859,205 @@ func (s *Server) GetToolDefinition(name string) Tool {
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
-					"region": map[string]interface{}{
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Region name",
+					},
+					"type": map[string]interface{}{
+						"type":        "string",
+						"description": "Address type filter",
+					},
+				},
+				Required: []string{"region"},
+			},
+		},
+		"search_tournaments": {
+			Name:        "search_tournaments",
+			Description: "Search for tournaments with filtering and pagination support",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"filter": filterSchema,
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Search query for tournament name",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of results (default: 50)",
+						"minimum":     1,
+						"maximum":     200,
+					},
+					"offset": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of results to skip (default: 0)",
+						"minimum":     0,
+					},
+					"sort_by": map[string]interface{}{
+						"type":        "string",
+						"description": "Field to sort by",
+					},
+					"sort_order": map[string]interface{}{
+						"type":        "string",
+						"description": "Sort order",
+						"enum":        []string{"asc", "desc"},
+					},
+					"filter_by": map[string]interface{}{
+						"type":        "string",
+						"description": "Field to filter by",
+					},
+					"filter_value": map[string]interface{}{
+						"type":        "string",
+						"description": "Value to filter by when filter_by is specified",
+					},
+				},
+			},
+		},
+		"get_recent_tournaments": {
+			Name:        "get_recent_tournaments",
+			Description: "Get recent tournaments within a specified timeframe",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"days": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of days to look back (default: 30)",
+						"minimum":     1,
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of results (default: 50)",
+						"minimum":     1,
+						"maximum":     200,
+					},
+				},
+			},
+		},
+		"get_upcoming_tournaments": {
+			Name:        "get_upcoming_tournaments",
+			Description: "Get tournaments starting within the next days, optionally filtered by region and city, sorted by start date",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"days_ahead": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of days to look ahead (default: 30)",
+						"minimum":     1,
+						"maximum":     365,
+					},
+					"region": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by state or federation name (e.g. Württemberg), or a tournament ID region letter (e.g. C)",
+					},
+					"city": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by city or venue",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of results (default: 50)",
+						"minimum":     1,
+					},
+				},
+			},
+		},
+		"get_tournament_changes": {
+			Name:        "get_tournament_changes",
+			Description: "Get the recent tournaments added or updated since a sync token. Without changes_since all recent tournaments are returned as added; every result carries a new sync_token for the next call.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"changes_since": map[string]interface{}{
 						"type":        "string",
-						"description": "Region name",
+						"description": "sync_token of the previous call; tokens are valid for 7 days",
 					},
-					"type": map[string]interface{}{
-						"type":        "string",
-						"description": "Address type filter",
+					"days": map[string]interface{}{
+						"type":        "integer",
+						"description": "Window of recent tournaments to compare, in days (default: 30)",
+						"minimum":     1,
+						"maximum":     365,
 					},
 				},
-				Required: []string{"region"},
 			},
 		},
-		"search_tournaments": {
-			Name:        "search_tournaments",
-			Description: "Search for tournaments with filtering and pagination support",
+		"get_tournament_series": {
+			Name:        "get_tournament_series",
+			Description: "Group recurring tournaments (e.g. annual opens) across years by their normalized name and report participation and winner trends",
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
 					"query": map[string]interface{}{
 						"type":        "string",
-						"description": "Search query for tournament name",
+						"description": "Tournament name to search for (e.g. Ulm Open)",
 					},
-					"limit": map[string]interface{}{
-						"type":        "integer",
-						"description": "Maximum number of results (default: 50)",
+					"max_editions": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of most recent editions per series whose winners are looked up",
 						"minimum":     1,
-						"maximum":     200,
+						"default":     10,
 					},
-					"offset": map[string]interface{}{
-						"type":        "integer",
-						"description": "Number of results to skip (default: 0)",
-						"minimum":     0,
+					"include_winners": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Look up the winner of each edition from the tournament results",
+						"default":     true,
 					},
-					"sort_by": map[string]interface{}{
+				},
+				Required: []string{"query"},
+			},
+		},
+		"get_organizer_tournaments": {
+			Name:        "get_organizer_tournaments",
+			Description: "List the tournaments an organizer club or organizer name organized in a period (default: the last 12 months) with participant counts and statuses, e.g. for federation oversight. Reports progress notifications per month searched.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"club_id": map[string]interface{}{
 						"type":        "string",
-						"description": "Field to sort by",
+						"description": "Organizer club ID; tournaments without organizer club ID match by the club name",
 					},
-					"sort_order": map[string]interface{}{
+					"organizer": map[string]interface{}{
 						"type":        "string",
-						"description": "Sort order",
-						"enum":        []string{"asc", "desc"},
+						"description": "Organizer name instead of club_id; all its words must occur in the organizer (e.g. SF Ulm)",
 					},
-					"filter_by": map[string]interface{}{
+					"start_date": map[string]interface{}{
 						"type":        "string",
-						"description": "Field to filter by",
+						"description": "First day of the range: " + dateExpressionHelp + ". The range covers whole months.",
 					},
-					"filter_value": map[string]interface{}{
+					"end_date": map[string]interface{}{
 						"type":        "string",
-						"description": "Value to filter by when filter_by is specified",
+						"description": "Last day of the range, in the same formats",
 					},
-				},
-			},
-		},
-		"get_recent_tournaments": {
-			Name:        "get_recent_tournaments",
-			Description: "Get recent tournaments within a specified timeframe",
-			InputSchema: ToolSchema{
-				Type: "object",
-				Properties: map[string]interface{}{
-					"days": map[string]interface{}{
-						"type":        "integer",
-						"description": "Number of days to look back (default: 30)",
-						"minimum":     1,
+					"period": map[string]interface{}{
+						"type":        "string",
+						"description": "Whole range instead of start_date and end_date, e.g. \"last 2 years\" or \"2024\"",
 					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
-						"description": "Maximum number of results (default: 50)",
+						"description": "Maximum number of tournaments (default: 100)",
 						"minimum":     1,
-						"maximum":     200,
+						"maximum":     500,
 					},
 				},
 			},
 		},
 		"search_tournaments_by_date": {
 			Name:        "search_tournaments_by_date",
-			Description: "Search for tournaments within a specific date range",
+			Description: "Search for tournaments within a date range, given by start_date and end_date or by a period. Dates are calendar days in Europe/Berlin time, the time zone of Portal64; relative expressions count from the current day there.",
 			InputSchema: ToolSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
+					"filter": filterSchema,
 					"start_date": map[string]interface{}{
 						"type":        "string",
-						"description": "Start date in YYYY-MM-DD format",
+						"description": "First day of the range: " + dateExpressionHelp + ". Periods start the range with their first day.",
 					},
 					"end_date": map[string]interface{}{
 						"type":        "string",
-						"description": "End date in YYYY-MM-DD format",
+						"description": "Last day of the range, in the same formats. Periods end the range with their last day.",
+					},
+					"period": map[string]interface{}{
+						"type":        "string",
+						"description": "Whole range instead of start_date and end_date, e.g. \"last 90 days\", \"2024-Q1\", \"2024-03\" or \"this year\"",
 					},
 					"query": map[string]interface{}{
 						"type":        "string",
@@ -350,7 +1075,6 @@ func (s *Server) GetToolDefinition(name string) Tool {
 						"minimum":     0,
 					},
 				},
-				Required: []string{"start_date", "end_date"},
 			},
 		},
 		"check_api_health": {
@@ -367,6 +1091,103 @@ func (s *Server) GetToolDefinition(name string) Tool {
 				Type: "object",
 			},
 		},
+		"get_connection_stats": {
+			Name:        "get_connection_stats",
+			Description: "Get connection pool statistics of the Portal64 API client (open and idle connections, reuse rate, DNS/connect/TLS timings) for diagnosing upstream performance",
+			InputSchema: ToolSchema{
+				Type: "object",
+			},
+		},
+		"diagnose_upstream_connection": {
+			Name:        "diagnose_upstream_connection",
+			Description: "Open a new connection to the Portal64 API and report the negotiated HTTP version, TLS version and cipher suite, and the DNS, connect, TLS handshake and first-byte latencies",
+			InputSchema: ToolSchema{
+				Type: "object",
+			},
+		},
+		"debug_upstream_request": {
+			Name:        "debug_upstream_request",
+			Description: "Send a GET request for an allow-listed Portal64 API path from the server, bypassing the response cache, and report the status, headers, the DNS, connect, TLS handshake, first-byte and total latencies, and the start of the body, for diagnosing connectivity problems of the server",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "API path, e.g. /health or /api/v1/clubs/C0327/profile; allowed paths are configured in api.debug.allowed_paths",
+					},
+					"query": map[string]interface{}{
+						"type":        "object",
+						"description": "Query parameters of the request, e.g. {\"limit\": 5}",
+					},
+					"max_body_bytes": map[string]interface{}{
+						"type":        "integer",
+						"description": "Longest body returned (default and maximum: api.debug.max_body_bytes)",
+						"minimum":     0,
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+		"check_ssl_config": {
+			Name:        "check_ssl_config",
+			Description: "Check the TLS files of api.ssl without connecting: that the client certificate matches its key, has a complete chain, is valid for client authentication and not about to expire, that the CA bundle holds valid CA certificates, that no weak keys or signatures are used and that keys are not readable by others. Each finding names the action to fix it.",
+			InputSchema: ToolSchema{
+				Type: "object",
+			},
+		},
+		"get_runtime_stats": {
+			Name:        "get_runtime_stats",
+			Description: "Get Go runtime statistics of the server process (goroutine count, heap usage, GC cycles and recent pause times) for diagnosing memory and latency problems",
+			InputSchema: ToolSchema{
+				Type: "object",
+			},
+		},
+		"get_slo_status": {
+			Name:        "get_slo_status",
+			Description: "Get the availability and latency objectives of the tools called within the SLO window, with the share of good calls, the error budget left and its recent burn rate; objectives burning their budget too fast are marked as alerting",
+			InputSchema: ToolSchema{
+				Type: "object",
+			},
+		},
+		"get_effective_config": {
+			Name:        "get_effective_config",
+			Description: "Get the running configuration of the server as config keys with secrets masked, each with its default value and whether it was set by the config file, an environment variable or the default, and the changes in the config file and environment that a restart would apply",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"prefix": map[string]interface{}{
+						"type":        "string",
+						"description": "Only report config keys starting with this prefix, e.g. api.failover",
+					},
+				},
+			},
+		},
+		"get_feature_flags": {
+			Name:        "get_feature_flags",
+			Description: "List the runtime feature flags of the server with their current states",
+			InputSchema: ToolSchema{
+				Type: "object",
+			},
+		},
+		"set_feature_flag": {
+			Name:        "set_feature_flag",
+			Description: "Enable or disable a runtime feature flag. Changes are not persisted across restarts.",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Flag name",
+						"enum":        features.Names(),
+					},
+					"enabled": map[string]interface{}{
+						"type":        "boolean",
+						"description": "New state of the flag",
+					},
+				},
+				Required: []string{"name", "enabled"},
+			},
+		},
 		"get_regions": {
 			Name:        "get_regions",
 			Description: "Get list of all available regions",
@@ -375,17 +1196,22 @@ func (s *Server) GetToolDefinition(name string) Tool {
 			},
 		},
 	}
+}
 
-	if def, exists := definitions[name]; exists {
-		return def
+// GetToolDefinition returns the schema definition for a tool
+func (s *Server) GetToolDefinition(name string) Tool {
+	if def, exists := toolSchemas()[name]; exists {
+		def.Annotations = toolAnnotations(name)
+		return s.withProfileArgument(withPriorityArgument(withBypassCacheArgument(s.withWriteState(def))))
 	}
 
 	// Return a generic definition for tools not explicitly defined
-	return Tool{
+	return s.withProfileArgument(withPriorityArgument(withBypassCacheArgument(Tool{
 		Name:        name,
 		Description: fmt.Sprintf("Execute %s operation", name),
 		InputSchema: ToolSchema{Type: "object"},
-	}
+		Annotations: toolAnnotations(name),
+	})))
 }
 // handleSearchPlayers handles player search requests
 func (s *Server) handleSearchPlayers(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
@@ -412,9 +1238,32 @@ func (s *Server) handleSearchPlayers(ctx context.Context, args map[string]interf
 	if active, ok := args["active"].(bool); ok {
 		params.Active = &active
 	}
+	if gender, ok := args["gender"].(string); ok {
+		params.Gender = api.NormalizeGender(gender)
+	}
+	if title, ok := args["title"].(string); ok {
+		params.Title = strings.ToUpper(title)
+	}
 
 	// Call API
-	result, err := s.apiClient.SearchPlayers(ctx, params)
+	filter, err := parseFilterArg(args, api.PlayerResponse{})
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	search := func(params api.SearchParams) (*api.SearchResponse, error) {
+		return s.apiClient.SearchPlayers(ctx, params)
+	}
+	if filter != nil {
+		search = filteredSearch(ctx, filter, search)
+	}
+	result, params, err := searchNormalized(ctx, params, search)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -425,8 +1274,35 @@ func (s *Server) handleSearchPlayers(ctx context.Context, args map[string]interf
 		}, nil
 	}
 
+	// Re-apply gender and title filters in case the upstream API ignores them
+	if params.Gender != "" || params.Title != "" {
+		players, _ := result.Data.([]api.PlayerResponse)
+		if params.Title != "" && !hasTitles(players) && len(players) > 0 {
+			addWarning(ctx, "title filter could not be verified: the API returned no player titles")
+		}
+		result.Data = filterPlayersByGenderAndTitle(players, params.Gender, params.Title)
+	}
+
+	// Age classes are derived from the birth year and filtered locally
+	if ageClass, ok := args["age_class"].(string); ok && ageClass != "" {
+		players, _ := result.Data.([]api.PlayerResponse)
+		filtered, err := filterPlayersByAgeClass(players, ageClass, referenceYear(args))
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		result.Data = filtered
+	}
+
+	rankSearchResults(result, params)
+
 	// Format response
-	data, _ := json.MarshalIndent(result, "", "  ")
+	data, _ := json.MarshalIndent(withSuggestions(ctx, result, params, search), "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -463,7 +1339,24 @@ func (s *Server) handleSearchClubs(ctx context.Context, args map[string]interfac
 		params.FilterValue = filterValue
 	}
 
-	result, err := s.apiClient.SearchClubs(ctx, params)
+	filter, err := parseFilterArg(args, api.ClubResponse{})
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	search := func(params api.SearchParams) (*api.SearchResponse, error) {
+		return s.apiClient.SearchClubs(ctx, params)
+	}
+	if filter != nil {
+		search = filteredSearch(ctx, filter, search)
+	}
+	result, params, err := searchNormalized(ctx, params, search)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -474,7 +1367,9 @@ func (s *Server) handleSearchClubs(ctx context.Context, args map[string]interfac
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	rankSearchResults(result, params)
+
+	data, _ := json.MarshalIndent(withSuggestions(ctx, result, params, search), "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -501,13 +1396,20 @@ func (s *Server) handleGetPlayerProfile(ctx context.Context, args map[string]int
 		return &CallToolResponse{
 			Content: []ToolContent{{
 				Type: "text",
-				Text: fmt.Sprintf("Error getting player profile: %v", err),
+				Text: fmt.Sprintf("Error getting player profile: %v%s", err, cachedMissHint(err)),
 			}},
 			IsError: true,
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	var profile interface{} = result
+	if includeElo, _ := args["include_elo"].(bool); includeElo {
+		if profile, err = withApproximateElo(result); err != nil {
+			return nil, fmt.Errorf("failed to add approximate Elo: %w", err)
+		}
+	}
+
+	data, _ := json.MarshalIndent(profile, "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -583,7 +1485,24 @@ func (s *Server) handleSearchTournaments(ctx context.Context, args map[string]in
 		params.FilterValue = filterValue
 	}
 
-	result, err := s.apiClient.SearchTournaments(ctx, params)
+	filter, err := parseFilterArg(args, api.TournamentResponse{})
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	search := func(params api.SearchParams) (*api.SearchResponse, error) {
+		return s.apiClient.SearchTournaments(ctx, params)
+	}
+	if filter != nil {
+		search = filteredSearch(ctx, filter, search)
+	}
+	result, params, err := searchNormalized(ctx, params, search)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -594,7 +1513,9 @@ func (s *Server) handleSearchTournaments(ctx context.Context, args map[string]in
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	rankSearchResults(result, params)
+
+	data, _ := json.MarshalIndent(withSuggestions(ctx, result, params, search), "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -637,60 +1558,50 @@ func (s *Server) handleGetRecentTournaments(ctx context.Context, args map[string
 
 // handleSearchTournamentsByDate handles tournament search by date range
 func (s *Server) handleSearchTournamentsByDate(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
-	startDateStr, ok1 := args["start_date"].(string)
-	endDateStr, ok2 := args["end_date"].(string)
-	
-	if !ok1 || !ok2 {
+	startDate, endDate, notes, err := dateRangeArgs(args, time.Now())
+	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
 				Type: "text",
-				Text: "Error: start_date and end_date are required (format: YYYY-MM-DD)",
+				Text: fmt.Sprintf("Error: %v", err),
 			}},
 			IsError: true,
 		}, nil
 	}
+	for _, note := range notes {
+		addWarning(ctx, note)
+	}
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
-	if err != nil {
-		return &CallToolResponse{
-			Content: []ToolContent{{
-				Type: "text",
-				Text: "Error: invalid start_date format (use YYYY-MM-DD)",
-			}},
-			IsError: true,
-		}, nil
+	params := api.SearchParams{
+		Limit: 50,
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if query, ok := args["query"].(string); ok {
+		params.Query = query
+	}
+	if limit, ok := args["limit"].(float64); ok {
+		params.Limit = int(limit)
+	}
+	if offset, ok := args["offset"].(float64); ok {
+		params.Offset = int(offset)
+	}
+
+	filter, err := parseFilterArg(args, api.TournamentResponse{})
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
 				Type: "text",
-				Text: "Error: invalid end_date format (use YYYY-MM-DD)",
+				Text: fmt.Sprintf("Error: %v", err),
 			}},
 			IsError: true,
 		}, nil
 	}
 
-	params := api.DateRangeParams{
-		StartDate: startDate,
-		EndDate:   endDate,
-		SearchParams: api.SearchParams{
-			Limit: 50,
-		},
-	}
-
-	if query, ok := args["query"].(string); ok {
-		params.SearchParams.Query = query
-	}
-	if limit, ok := args["limit"].(float64); ok {
-		params.SearchParams.Limit = int(limit)
+	search := s.dateRangeSearch(ctx, startDate, endDate)
+	if filter != nil {
+		search = filteredSearch(ctx, filter, search)
 	}
-	if offset, ok := args["offset"].(float64); ok {
-		params.SearchParams.Offset = int(offset)
-	}
-
-	result, err := s.apiClient.SearchTournamentsByDate(ctx, params)
+	result, searchParams, err := searchNormalized(ctx, params, search)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -701,7 +1612,9 @@ func (s *Server) handleSearchTournamentsByDate(ctx context.Context, args map[str
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	rankSearchResults(result, searchParams)
+
+	data, _ := json.MarshalIndent(withSuggestions(ctx, result, searchParams, search), "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -755,12 +1668,12 @@ func (s *Server) handleGetTournamentDetails(ctx context.Context, args map[string
 		}, nil
 	}
 
-	result, err := s.apiClient.GetTournamentDetails(ctx, tournamentID)
+	result, err := s.tournamentDetails(ctx, tournamentID)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
 				Type: "text",
-				Text: fmt.Sprintf("Error getting tournament details: %v", err),
+				Text: fmt.Sprintf("Error getting tournament details: %v%s", err, cachedMissHint(err)),
 			}},
 			IsError: true,
 		}, nil
@@ -805,7 +1718,28 @@ func (s *Server) handleGetClubPlayers(ctx context.Context, args map[string]inter
 		params.Active = &active
 	}
 
-	result, err := s.apiClient.GetClubPlayers(ctx, clubID, params)
+	filter, err := parseFilterArg(args, api.PlayerResponse{})
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	if ageClass, ok := args["age_class"].(string); ok && ageClass != "" {
+		return s.getClubPlayersByAgeClass(ctx, clubID, params, ageClass, referenceYear(args), filter)
+	}
+
+	search := func(params api.SearchParams) (*api.SearchResponse, error) {
+		return s.apiClient.GetClubPlayers(ctx, clubID, params)
+	}
+	if filter != nil {
+		search = filteredSearch(ctx, filter, search)
+	}
+	result, err := search(params)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -838,7 +1772,7 @@ func (s *Server) handleGetPlayerRatingHistory(ctx context.Context, args map[stri
 		}, nil
 	}
 
-	result, err := s.apiClient.GetPlayerRatingHistory(ctx, playerID)
+	result, err := s.ratingHistory(ctx, playerID)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -870,6 +1804,41 @@ func (s *Server) handleGetClubStatistics(ctx context.Context, args map[string]in
 		}, nil
 	}
 
+	if asOf, ok := args["as_of"].(string); ok && asOf != "" {
+		date, err := parseAsOfDate(asOf, time.Now())
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		result, err := s.clubStatisticsAsOf(ctx, clubID, date)
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error getting club statistics: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		addWarning(ctx, "Historical statistics cover current members only; membership at the date is not known")
+		explainClubMemberStatistics(ctx)
+		explain(ctx, ratingAtDateFormula)
+
+		data, _ := json.MarshalIndent(result, "", "  ")
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: string(data),
+			}},
+		}, nil
+	}
+
 	result, err := s.apiClient.GetClubStatistics(ctx, clubID)
 	if err != nil {
 		return &CallToolResponse{
@@ -881,7 +1850,30 @@ func (s *Server) handleGetClubStatistics(ctx context.Context, args map[string]in
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	var output interface{} = result
+	if includeMembers, ok := args["include_members"].(bool); ok && includeMembers {
+		players, truncated, err := s.fetchAllClubPlayers(ctx, clubID, api.SearchParams{})
+		if err != nil {
+			return &CallToolResponse{
+				Content: []ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error getting club members: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		memberStats := computeClubMemberStatistics(clubID, players, pageCount(len(players), aggregatePageSize))
+		memberStats.Truncated = truncated
+		output = map[string]interface{}{
+			"rating_stats":      result,
+			"member_statistics": memberStats,
+		}
+		explainClubMemberStatistics(ctx)
+		addCaveat(ctx, "rating_stats are computed by the API, member_statistics from the member list")
+	}
+
+	data, _ := json.MarshalIndent(output, "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -903,7 +1895,18 @@ func (s *Server) handleCheckAPIHealth(ctx context.Context, args map[string]inter
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	health := struct {
+		*api.HealthResponse
+		FeatureFlags map[string]bool        `json:"feature_flags"`
+		Panics       int64                  `json:"recovered_panics"`
+		System       *SystemHealth          `json:"system,omitempty"`
+		Maintenance  *api.MaintenanceStatus `json:"maintenance,omitempty"` // Set while data is served from the cache only
+	}{HealthResponse: result, FeatureFlags: s.features.States(), Panics: s.PanicCount(), System: s.systemHealth()}
+	if maintenance := s.apiClient.Maintenance(); maintenance.Active {
+		health.Maintenance = &maintenance
+	}
+
+	data, _ := json.MarshalIndent(health, "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -925,7 +1928,60 @@ func (s *Server) handleGetCacheStats(ctx context.Context, args map[string]interf
 		}, nil
 	}
 
-	data, _ := json.MarshalIndent(result, "", "  ")
+	stats := CacheStats{CacheStatsResponse: result, Rosters: s.rosters.snapshot()}
+	if cache := s.apiClient.ResponseCache(); cache != nil {
+		responses := cache.Stats()
+		stats.Responses = &responses
+	}
+	if s.memory != nil {
+		memoryStats := s.memory.Stats()
+		stats.Memory = &memoryStats
+	}
+	data, _ := json.MarshalIndent(stats, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// handleGetConnectionStats handles connection pool statistics requests
+func (s *Server) handleGetConnectionStats(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	data, _ := json.MarshalIndent(s.apiClient.ConnectionStats(), "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// handleDiagnoseUpstreamConnection handles upstream connection diagnosis
+// requests
+func (s *Server) handleDiagnoseUpstreamConnection(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	data, _ := json.MarshalIndent(s.apiClient.DiagnoseConnection(ctx), "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
+
+// handleCheckSSLConfig handles TLS configuration check requests
+func (s *Server) handleCheckSSLConfig(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	var opts api.TLSOptions
+	if s.config != nil {
+		opts = api.TLSOptions{
+			CAFile:             s.config.API.SSL.CAFile,
+			ClientCert:         s.config.API.SSL.ClientCert,
+			ClientKey:          s.config.API.SSL.ClientKey,
+			InsecureSkipVerify: s.config.API.SSL.InsecureSkipVerify,
+		}
+	}
+
+	data, _ := json.MarshalIndent(api.CheckTLS(opts, time.Now()), "", "  ")
 	return &CallToolResponse{
 		Content: []ToolContent{{
 			Type: "text",
@@ -936,7 +1992,7 @@ func (s *Server) handleGetCacheStats(ctx context.Context, args map[string]interf
 
 // handleGetRegions handles region listing requests
 func (s *Server) handleGetRegions(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
-	result, err := s.apiClient.GetRegions(ctx)
+	result, err := s.regions(ctx)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -974,7 +2030,7 @@ func (s *Server) handleGetRegionAddresses(ctx context.Context, args map[string]i
 		addressType = t
 	}
 
-	result, err := s.apiClient.GetRegionAddresses(ctx, region, addressType)
+	result, err := s.regionAddresses(ctx, region, addressType)
 	if err != nil {
 		return &CallToolResponse{
 			Content: []ToolContent{{
@@ -984,6 +2040,9 @@ func (s *Server) handleGetRegionAddresses(ctx context.Context, args map[string]i
 			IsError: true,
 		}, nil
 	}
+	for i := range result {
+		result[i] = s.redactContact(result[i])
+	}
 
 	data, _ := json.MarshalIndent(result, "", "  ")
 	return &CallToolResponse{
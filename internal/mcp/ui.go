@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// uiFiles is the operator dashboard, a single page using the endpoints of
+// the HTTP bridge
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// uiPath is the path of the dashboard
+const uiPath = "/ui/"
+
+// uiRoutes registers the dashboard if it is enabled. It shows the panels of
+// hidden admin tools with an error, so hiding them is still effective.
+func (h *HTTPBridge) uiRoutes(r *mux.Router) {
+	cfg := h.server.config
+	if cfg == nil || !cfg.MCP.HTTP.UI.Enabled {
+		return
+	}
+	files, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to load dashboard")
+		return
+	}
+	r.Handle("/ui", http.RedirectHandler(uiPath, http.StatusMovedPermanently)).Methods("GET")
+	r.PathPrefix(uiPath).Handler(http.StripPrefix(uiPath, http.FileServer(http.FS(files)))).Methods("GET")
+}
@@ -0,0 +1,355 @@
+// Dashboard of the Portal64 MCP server. All data comes from the HTTP bridge
+// endpoints; panels whose endpoint is hidden or failing show the error.
+"use strict";
+
+const refreshInterval = 10000;
+
+async function fetchJSON(path, options) {
+  const response = await fetch(path, options);
+  const body = await response.json().catch(() => ({}));
+  if (!response.ok) {
+    throw new Error(body.message || response.status + " " + response.statusText);
+  }
+  return body;
+}
+
+// fillValues shows the scalar fields of an object, nested objects with
+// dotted names and lists with their length
+function fillValues(list, data, prefix) {
+  if (!prefix) {
+    list.replaceChildren();
+  }
+  for (const [key, value] of Object.entries(data)) {
+    const name = prefix ? prefix + "." + key : key;
+    if (value !== null && typeof value === "object" && !Array.isArray(value)) {
+      fillValues(list, value, name);
+      continue;
+    }
+    const term = document.createElement("dt");
+    term.textContent = name;
+    const definition = document.createElement("dd");
+    definition.textContent = Array.isArray(value) ? value.length + " entries" : String(value);
+    list.append(term, definition);
+  }
+}
+
+function showError(list, err) {
+  const item = document.createElement("dd");
+  item.className = "error";
+  item.textContent = err.message;
+  list.replaceChildren(item);
+}
+
+async function loadHealth() {
+  const status = document.getElementById("health-status");
+  const list = document.getElementById("health-values");
+  try {
+    const health = await fetchJSON("/health");
+    status.textContent = health.status || "ok";
+    status.className = "status ok";
+    fillValues(list, health);
+  } catch (err) {
+    status.textContent = "failed";
+    status.className = "status failed";
+    showError(list, err);
+  }
+}
+
+async function loadCache() {
+  const list = document.getElementById("cache-values");
+  try {
+    fillValues(list, await fetchJSON("/api/v1/admin/cache"));
+  } catch (err) {
+    showError(list, err);
+  }
+}
+
+async function loadRuntime() {
+  const list = document.getElementById("runtime-values");
+  const rows = document.getElementById("tool-rows");
+  try {
+    const stats = await fetchJSON("/api/v1/admin/runtime");
+    const tools = stats.tools || [];
+    delete stats.tools;
+    fillValues(list, stats);
+
+    rows.replaceChildren(...tools.map((tool) => {
+      const row = document.createElement("tr");
+      const cells = [
+        tool.name,
+        tool.calls,
+        tool.errors,
+        tool.avg_latency_ms.toFixed(1),
+        tool.max_latency_ms.toFixed(1),
+        tool.last_call ? new Date(tool.last_call).toLocaleTimeString() : "",
+      ];
+      for (const value of cells) {
+        const cell = document.createElement("td");
+        cell.textContent = value;
+        row.append(cell);
+      }
+      return row;
+    }));
+  } catch (err) {
+    showError(list, err);
+    rows.replaceChildren();
+  }
+}
+
+async function refresh() {
+  await Promise.all([loadHealth(), loadCache(), loadRuntime()]);
+  document.getElementById("updated").textContent = "Updated " + new Date().toLocaleTimeString();
+}
+
+// Playground. The argument form is generated from the input schema of the
+// selected tool; arguments without a form field, such as filters, are
+// edited as JSON.
+
+let toolDefinitions = [];
+let currentFields = [];
+
+function selectedTool() {
+  return toolDefinitions.find((t) => t.name === document.getElementById("tool-name").value);
+}
+
+// fieldKind returns how an argument is edited
+function fieldKind(schema) {
+  if (schema.type === "array") {
+    const items = schema.items || {};
+    if (items.enum) {
+      return "choices";
+    }
+    return items.type === "object" ? "json" : "list";
+  }
+  if (schema.type === "object") {
+    return "json";
+  }
+  if (schema.enum) {
+    return "select";
+  }
+  return schema.type || "string";
+}
+
+// createField creates the form field of an argument. Its read function
+// returns the entered value or undefined if the argument is not given.
+function createField(name, schema, required) {
+  const label = document.createElement("label");
+  const title = document.createElement("span");
+  title.textContent = name;
+  if (required) {
+    title.className = "required";
+  }
+  label.append(title);
+
+  const kind = fieldKind(schema);
+  let input;
+  let read;
+  let write;
+  switch (kind) {
+    case "select":
+    case "boolean": {
+      input = document.createElement("select");
+      const values = kind === "boolean" ? ["true", "false"] : schema.enum.map(String);
+      input.append(new Option("", ""), ...values.map((v) => new Option(v, v)));
+      read = () => (input.value === "" ? undefined : kind === "boolean" ? input.value === "true" : input.value);
+      write = (value) => { input.value = value === undefined ? "" : String(value); };
+      break;
+    }
+    case "integer":
+    case "number":
+      input = document.createElement("input");
+      input.type = "number";
+      input.step = kind === "integer" ? "1" : "any";
+      if (schema.minimum !== undefined) input.min = schema.minimum;
+      if (schema.maximum !== undefined) input.max = schema.maximum;
+      read = () => (input.value === "" ? undefined : Number(input.value));
+      write = (value) => { input.value = value === undefined ? "" : value; };
+      break;
+    case "choices": {
+      input = document.createElement("span");
+      input.className = "choices";
+      const boxes = schema.items.enum.map((value) => {
+        const choice = document.createElement("label");
+        const box = document.createElement("input");
+        box.type = "checkbox";
+        box.value = value;
+        choice.append(box, " " + value);
+        input.append(choice);
+        return box;
+      });
+      read = () => {
+        const checked = boxes.filter((b) => b.checked).map((b) => b.value);
+        return checked.length ? checked : undefined;
+      };
+      write = (value) => boxes.forEach((b) => { b.checked = Array.isArray(value) && value.includes(b.value); });
+      break;
+    }
+    case "list":
+      input = document.createElement("input");
+      input.placeholder = "comma separated";
+      read = () => {
+        const items = input.value.split(",").map((v) => v.trim()).filter((v) => v !== "");
+        return items.length ? items : undefined;
+      };
+      write = (value) => { input.value = Array.isArray(value) ? value.join(", ") : ""; };
+      break;
+    case "json":
+      label.className = "wide";
+      input = document.createElement("textarea");
+      input.rows = 3;
+      input.spellcheck = false;
+      input.placeholder = "JSON";
+      read = () => (input.value.trim() === "" ? undefined : JSON.parse(input.value));
+      write = (value) => { input.value = value === undefined ? "" : JSON.stringify(value); };
+      break;
+    default:
+      input = document.createElement("input");
+      read = () => (input.value === "" ? undefined : input.value);
+      write = (value) => { input.value = value === undefined ? "" : String(value); };
+  }
+  label.append(input);
+
+  if (schema.description) {
+    const hint = document.createElement("span");
+    hint.className = "hint";
+    hint.textContent = schema.description;
+    label.append(hint);
+  }
+  return { name, label, read, write };
+}
+
+function buildForm(tool) {
+  const schema = tool.inputSchema || {};
+  const properties = schema.properties || {};
+  const required = schema.required || [];
+  const names = Object.keys(properties).sort((a, b) => {
+    const order = Number(required.includes(b)) - Number(required.includes(a));
+    return order || a.localeCompare(b);
+  });
+  currentFields = names.map((name) => createField(name, properties[name], required.includes(name)));
+  document.getElementById("tool-fields").replaceChildren(...currentFields.map((f) => f.label));
+}
+
+// formArguments reads the arguments from the form fields
+function formArguments() {
+  const args = {};
+  for (const field of currentFields) {
+    let value;
+    try {
+      value = field.read();
+    } catch (err) {
+      throw new Error(field.name + ": " + err.message);
+    }
+    if (value !== undefined) {
+      args[field.name] = value;
+    }
+  }
+  return args;
+}
+
+function fillForm(args) {
+  for (const field of currentFields) {
+    field.write(args[field.name]);
+  }
+}
+
+function editingJSON() {
+  return document.getElementById("edit-json").checked;
+}
+
+function currentArguments() {
+  if (editingJSON()) {
+    return JSON.parse(document.getElementById("tool-args").value || "{}");
+  }
+  return formArguments();
+}
+
+function toggleJSON() {
+  const textarea = document.getElementById("tool-args");
+  const fields = document.getElementById("tool-fields");
+  const status = document.getElementById("call-status");
+  status.textContent = "";
+  try {
+    if (editingJSON()) {
+      textarea.value = JSON.stringify(formArguments(), null, 2);
+    } else {
+      fillForm(JSON.parse(textarea.value || "{}"));
+    }
+  } catch (err) {
+    status.textContent = "Invalid arguments: " + err.message;
+    document.getElementById("edit-json").checked = !editingJSON();
+    return;
+  }
+  textarea.hidden = !editingJSON();
+  fields.hidden = editingJSON();
+}
+
+function selectTool() {
+  const tool = selectedTool();
+  if (!tool) {
+    return;
+  }
+  document.getElementById("tool-description").textContent = tool.description || "";
+  buildForm(tool);
+  document.getElementById("tool-args").value = "{}";
+}
+
+async function loadTools() {
+  const select = document.getElementById("tool-name");
+  try {
+    toolDefinitions = (await fetchJSON("/tools/list")).tools || [];
+    select.replaceChildren(...toolDefinitions.map((tool) => new Option(tool.name, tool.name)));
+    selectTool();
+  } catch (err) {
+    document.getElementById("tool-description").textContent = "Failed to list tools: " + err.message;
+  }
+}
+
+async function callTool(event) {
+  event.preventDefault();
+  const status = document.getElementById("call-status");
+  const httpStatus = document.getElementById("call-http-status");
+  const output = document.getElementById("call-result");
+  let args;
+  try {
+    args = currentArguments();
+  } catch (err) {
+    status.textContent = "Invalid arguments: " + err.message;
+    return;
+  }
+
+  const request = { name: document.getElementById("tool-name").value, arguments: args };
+  document.getElementById("call-request").textContent = "POST /tools/call\n" + JSON.stringify(request, null, 2);
+  status.textContent = "Calling...";
+  httpStatus.textContent = "";
+  output.textContent = "";
+  const start = performance.now();
+  try {
+    const response = await fetch("/tools/call", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(request),
+    });
+    const body = await response.text();
+    status.textContent = "Done in " + Math.round(performance.now() - start) + " ms";
+    httpStatus.textContent = response.status + " " + response.statusText;
+    httpStatus.className = "status " + (response.ok ? "ok" : "failed");
+    try {
+      output.textContent = JSON.stringify(JSON.parse(body), null, 2);
+    } catch (err) {
+      output.textContent = body;
+    }
+  } catch (err) {
+    status.textContent = "Failed: " + err.message;
+  }
+  loadRuntime();
+}
+
+document.getElementById("refresh").addEventListener("click", refresh);
+document.getElementById("tool-name").addEventListener("change", selectTool);
+document.getElementById("edit-json").addEventListener("change", toggleJSON);
+document.getElementById("call-form").addEventListener("submit", callTool);
+
+loadTools();
+refresh();
+setInterval(refresh, refreshInterval);
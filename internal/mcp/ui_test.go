@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestUIRoutes(t *testing.T) {
+	s := newTestServer()
+	s.config = &config.Config{}
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("/ui/").Code)
+
+	s.config.MCP.HTTP.UI.Enabled = true
+	rec := get("/ui")
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/ui/", rec.Header().Get("Location"))
+
+	rec = get("/ui/")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "<title>Portal64 MCP Dashboard</title>")
+
+	rec = get("/ui/app.js")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `fetchJSON("/api/v1/admin/runtime")`)
+	assert.Contains(t, rec.Body.String(), "function buildForm(tool)")
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+const (
+	// defaultUpcomingDays is how far ahead upcoming tournaments are searched
+	defaultUpcomingDays = 30
+	maxUpcomingDays     = 365
+	// defaultUpcomingLimit is the default number of upcoming tournaments returned
+	defaultUpcomingLimit = 50
+	// maxUpcomingPages bounds the search pages scanned for upcoming tournaments
+	maxUpcomingPages = 10
+)
+
+// UpcomingTournaments is the result of an upcoming tournaments query
+type UpcomingTournaments struct {
+	From        string                   `json:"from"`
+	To          string                   `json:"to"`
+	Region      string                   `json:"region,omitempty"`
+	City        string                   `json:"city,omitempty"`
+	Tournaments []api.TournamentResponse `json:"tournaments"`
+	Count       int                      `json:"count"`
+	Truncated   bool                     `json:"truncated,omitempty"` // More tournaments matched than the limit
+}
+
+// tournamentStart returns the first day of a tournament, falling back to its
+// end date, or the zero time if it has no dates
+func tournamentStart(t api.TournamentResponse) time.Time {
+	if t.StartDate != nil && !t.StartDate.IsZero() {
+		return *t.StartDate
+	}
+	if t.EndDate != nil {
+		return *t.EndDate
+	}
+	return time.Time{}
+}
+
+// matchesTournamentRegion reports whether a tournament belongs to a region
+// given by state or federation name. A single letter matches the region
+// prefix of Portal64 tournament IDs (e.g. C for Württemberg).
+func matchesTournamentRegion(t api.TournamentResponse, region string) bool {
+	if len(region) == 1 {
+		return strings.HasPrefix(strings.ToUpper(t.ID), strings.ToUpper(region))
+	}
+	region = strings.ToLower(region)
+	return strings.Contains(strings.ToLower(t.State), region) || strings.Contains(strings.ToLower(t.Organization), region)
+}
+
+// matchesTournamentCity reports whether a tournament takes place in city
+func matchesTournamentCity(t api.TournamentResponse, city string) bool {
+	city = strings.ToLower(city)
+	return strings.Contains(strings.ToLower(t.City), city) || strings.Contains(strings.ToLower(t.Location), city)
+}
+
+// filterUpcomingTournaments keeps tournaments starting on or after from that
+// match region and city, sorted by start date. Tournaments that already
+// started, or have no dates, are dropped.
+func filterUpcomingTournaments(tournaments []api.TournamentResponse, from time.Time, region, city string) []api.TournamentResponse {
+	upcoming := make([]api.TournamentResponse, 0, len(tournaments))
+	seen := make(map[string]bool)
+	for _, t := range tournaments {
+		start := tournamentStart(t)
+		if start.IsZero() || start.Before(from) || seen[t.ID] {
+			continue
+		}
+		if region != "" && !matchesTournamentRegion(t, region) {
+			continue
+		}
+		if city != "" && !matchesTournamentCity(t, city) {
+			continue
+		}
+		seen[t.ID] = true
+		upcoming = append(upcoming, t)
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return tournamentStart(upcoming[i]).Before(tournamentStart(upcoming[j])) })
+	return upcoming
+}
+
+// upcomingTournaments searches tournaments starting within the next days
+func (s *Server) upcomingTournaments(ctx context.Context, days, limit int, region, city string) (*UpcomingTournaments, error) {
+	from := portalDay(time.Now())
+	to := from.AddDate(0, 0, days)
+
+	var tournaments []api.TournamentResponse
+	for page := 0; page < maxUpcomingPages; page++ {
+		params := api.DateRangeParams{
+			StartDate: from,
+			EndDate:   to,
+			SearchParams: api.SearchParams{
+				Limit:  aggregatePageSize,
+				Offset: page * aggregatePageSize,
+			},
+		}
+		result, err := s.apiClient.SearchTournamentsByDate(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		pageTournaments, _ := result.Data.([]api.TournamentResponse)
+		tournaments = append(tournaments, pageTournaments...)
+
+		totalPages := pageCount(result.Pagination.Total, aggregatePageSize)
+		if len(pageTournaments) < aggregatePageSize || (totalPages > 0 && page+1 >= totalPages) {
+			break
+		}
+	}
+
+	upcoming := filterUpcomingTournaments(tournaments, from, region, city)
+	result := &UpcomingTournaments{
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Region: region,
+		City:   city,
+	}
+	if len(upcoming) > limit {
+		upcoming = upcoming[:limit]
+		result.Truncated = true
+	}
+	result.Tournaments = upcoming
+	result.Count = len(upcoming)
+	return result, nil
+}
+
+// handleGetUpcomingTournaments handles upcoming tournament requests
+func (s *Server) handleGetUpcomingTournaments(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	days := defaultUpcomingDays
+	if d, ok := args["days_ahead"].(float64); ok && d > 0 {
+		days = min(int(d), maxUpcomingDays)
+	}
+
+	limit := defaultUpcomingLimit
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	region, _ := args["region"].(string)
+	city, _ := args["city"].(string)
+
+	result, err := s.upcomingTournaments(ctx, days, limit, strings.TrimSpace(region), strings.TrimSpace(city))
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error getting upcoming tournaments: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if result.Truncated {
+		addWarning(ctx, fmt.Sprintf("More tournaments match, only the first %d are returned", limit))
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func TestFilterUpcomingTournaments(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) *time.Time {
+		d := from.AddDate(0, 0, offset)
+		return &d
+	}
+	tournaments := []api.TournamentResponse{
+		{ID: "C350-C01-SMU", Name: "Ulm Open", City: "Ulm", State: "Württemberg", StartDate: day(10)},
+		{ID: "B735-705-QCB", Name: "Karlsruher Open", Location: "Karlsruhe", State: "Baden", StartDate: day(3)},
+		{ID: "C123-400-ABC", Name: "Stuttgarter Stadtmeisterschaft", City: "Stuttgart", Organization: "Schachverband Württemberg", EndDate: day(5)},
+		{ID: "C999-100-OLD", Name: "Running", City: "Ulm", StartDate: day(-2), EndDate: day(1)},
+		{ID: "C000-000-NOD", Name: "Undated", City: "Ulm"},
+		{ID: "C350-C01-SMU", Name: "Ulm Open", City: "Ulm", State: "Württemberg", StartDate: day(10)},
+	}
+
+	ids := func(tournaments []api.TournamentResponse) []string {
+		result := make([]string, len(tournaments))
+		for i, t := range tournaments {
+			result[i] = t.ID
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name   string
+		region string
+		city   string
+		ids    []string
+	}{
+		{"all sorted by start", "", "", []string{"B735-705-QCB", "C123-400-ABC", "C350-C01-SMU"}},
+		{"region by state or organization", "württemberg", "", []string{"C123-400-ABC", "C350-C01-SMU"}},
+		{"region by ID letter", "b", "", []string{"B735-705-QCB"}},
+		{"city or location", "", "karlsruhe", []string{"B735-705-QCB"}},
+		{"region and city", "Württemberg", "Ulm", []string{"C350-C01-SMU"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.ids, ids(filterUpcomingTournaments(tournaments, from, tc.region, tc.city)))
+		})
+	}
+}
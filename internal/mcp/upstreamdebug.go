@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// handleDebugUpstreamRequest sends a GET request for an allow-listed API
+// path and reports the response, its timing and the start of the body
+func (s *Server) handleDebugUpstreamRequest(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	invalid := func(format string, a ...interface{}) (*CallToolResponse, error) {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: " + fmt.Sprintf(format, a...),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	apiPath, _ := args["path"].(string)
+	apiPath = strings.TrimSpace(apiPath)
+	if apiPath == "" {
+		return invalid("path is required")
+	}
+	debug := s.config.API.Debug
+	if !debug.Allows(apiPath) {
+		return invalid("path %s is not allowed, allowed paths are %s (api.debug.allowed_paths)", apiPath, strings.Join(debug.AllowedPaths, ", "))
+	}
+
+	query := url.Values{}
+	if params, ok := args["query"].(map[string]interface{}); ok {
+		for name, value := range params {
+			switch value := value.(type) {
+			case string, float64, bool:
+				query.Set(name, fmt.Sprint(value))
+			default:
+				return invalid("query parameter %s must be a string, number or boolean", name)
+			}
+		}
+	}
+
+	maxBody := debug.MaxBodyBytes
+	if requested, ok := args["max_body_bytes"].(float64); ok {
+		if int(requested) > maxBody {
+			addWarning(ctx, fmt.Sprintf("max_body_bytes %d exceeds api.debug.max_body_bytes and was lowered to %d", int(requested), maxBody))
+		}
+		maxBody = min(int(requested), maxBody)
+	}
+
+	result, err := s.apiClient.DebugGet(ctx, apiPath, query, maxBody)
+	if err != nil {
+		return invalid("%v", err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+	"github.com/svw-info/portal64gomcp/internal/config"
+)
+
+func TestDebugUpstreamRequest(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path": "` + r.URL.Path + `", "limit": "` + r.URL.Query().Get("limit") + `"}`))
+	}))
+	defer upstream.Close()
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.config = &config.Config{}
+	s.config.API.Debug = config.APIDebugConfig{AllowedPaths: []string{"/health", "/api/v1/clubs*"}, MaxBodyBytes: 1000}
+
+	ctx, collector := withWarnings(context.Background())
+	result, err := s.handleDebugUpstreamRequest(ctx, map[string]interface{}{
+		"path":           "/api/v1/clubs/C0327/profile",
+		"query":          map[string]interface{}{"limit": float64(5)},
+		"max_body_bytes": float64(5000),
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	var debug api.DebugResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &debug))
+	assert.Equal(t, http.StatusOK, debug.StatusCode)
+	assert.JSONEq(t, `{"path": "/api/v1/clubs/C0327/profile", "limit": "5"}`, debug.Body)
+	require.Len(t, collector.warnings, 1)
+	assert.Contains(t, collector.warnings[0], "lowered to 1000")
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"path": "/api/v1/admin/cache"},
+		{"path": "/api/v1/clubs/../admin/cache"},
+		{"path": "/health", "query": map[string]interface{}{"ids": []interface{}{"a"}}},
+	} {
+		result, err := s.handleDebugUpstreamRequest(context.Background(), args)
+		require.NoError(t, err)
+		assert.True(t, result.IsError, args)
+		assert.Contains(t, result.Content[0].Text, "Error: ")
+	}
+
+	// The HTTP bridge passes other parameters on as the query
+	s.config.MCP.HTTP.Admin = config.AdminConfig{Enabled: true, Token: "secret"}
+	s.registerTools()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/upstream/debug?path=/api/v1/clubs&limit=7&max_body_bytes=20", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `/api/v1/clubs?limit=7"`)
+	assert.Contains(t, rec.Body.String(), `"body_truncated": true`)
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// writeTools change data of the Portal64 API. They are disabled while the
+// API client is read-only.
+var writeTools = map[string]bool{
+	"submit_address_correction": true,
+}
+
+// correctionFields are the address fields a correction may change
+var correctionFields = []string{"name", "email", "phone", "address", "city", "postal_code"}
+
+// WriteDisabledError rejects a call of a write tool in read-only mode
+type WriteDisabledError struct {
+	Tool string
+}
+
+func (e *WriteDisabledError) Error() string {
+	return fmt.Sprintf("tool %s changes data of the Portal64 API and is disabled because the server is read-only (api.read_only)", e.Tool)
+}
+
+// writesDisabled reports whether write tools are disabled
+func (s *Server) writesDisabled() bool {
+	return s.apiClient == nil || s.apiClient.ReadOnly()
+}
+
+// guardWrites wraps the handler of a write tool so that it is rejected with
+// a WriteDisabledError in read-only mode. Other tools are left unchanged.
+func (s *Server) guardWrites(name string, handler ToolHandler) ToolHandler {
+	if !writeTools[name] {
+		return handler
+	}
+	return func(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+		if s.writesDisabled() {
+			return nil, &WriteDisabledError{Tool: name}
+		}
+		result, err := handler(ctx, args)
+		if errors.Is(err, api.ErrReadOnly) {
+			return nil, &WriteDisabledError{Tool: name}
+		}
+		return result, err
+	}
+}
+
+// withWriteState notes in the description of a write tool that it is
+// disabled in read-only mode
+func (s *Server) withWriteState(tool Tool) Tool {
+	if writeTools[tool.Name] && s.writesDisabled() {
+		tool.Description += " Disabled: the server is read-only."
+	}
+	return tool
+}
+
+// handleSubmitAddressCorrection handles address correction submissions
+func (s *Server) handleSubmitAddressCorrection(ctx context.Context, args map[string]interface{}) (*CallToolResponse, error) {
+	correction := api.AddressCorrection{}
+	correction.AddressID, _ = args["address_id"].(string)
+	correction.Field, _ = args["field"].(string)
+	correction.Value, _ = args["value"].(string)
+	correction.Comment, _ = args["comment"].(string)
+	if correction.AddressID == "" || correction.Field == "" {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: "Error: address_id and field are required",
+			}},
+			IsError: true,
+		}, nil
+	}
+	valid := false
+	for _, field := range correctionFields {
+		valid = valid || correction.Field == field
+	}
+	if !valid {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: field must be one of: %s", strings.Join(correctionFields, ", ")),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	writer, err := s.apiClient.Writer()
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.SubmitAddressCorrection(ctx, correction)
+	if err != nil {
+		return &CallToolResponse{
+			Content: []ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error submitting address correction: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	s.logger.WithField("address_id", correction.AddressID).WithField("field", correction.Field).Info("Submitted address correction")
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return &CallToolResponse{
+		Content: []ToolContent{{
+			Type: "text",
+			Text: string(data),
+		}},
+	}, nil
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// newWriteTestServer returns a test server whose API client posts to an
+// upstream answering address corrections
+func newWriteTestServer(t *testing.T, readOnly bool) *Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/addresses/corrections", r.URL.Path)
+		w.Write([]byte(`{"id":"corr-1","address_id":"addr-1","status":"pending"}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	s := newTestServer()
+	s.apiClient = api.NewClient(upstream.URL, 5*time.Second, nil)
+	s.apiClient.SetReadOnly(readOnly)
+	s.registerTools()
+	return s
+}
+
+const correctionCall = `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"submit_address_correction","arguments":{"address_id":"addr-1","field":"city","value":"Ulm"}}}`
+
+func TestWriteTools_ReadOnly(t *testing.T) {
+	s := newWriteTestServer(t, true)
+
+	response, err := s.handleMessage([]byte(correctionCall))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, WriteDisabled, response.Error.Code)
+	data, _ := json.Marshal(response.Error.Data)
+	assert.Contains(t, string(data), `"tool":"submit_address_correction"`)
+	assert.Contains(t, string(data), "disabled because the server is read-only")
+
+	rec := httptest.NewRecorder()
+	NewHTTPBridge(s, s.logger).SetupRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tools/call",
+		strings.NewReader(`{"name":"submit_address_correction","arguments":{"address_id":"addr-1","field":"city","value":"Ulm"}}`)))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "WRITE_DISABLED")
+
+	assert.True(t, strings.HasSuffix(s.GetToolDefinition("submit_address_correction").Description, "Disabled: the server is read-only."))
+}
+
+func TestWriteTools_Writable(t *testing.T) {
+	s := newWriteTestServer(t, false)
+	assert.False(t, strings.Contains(s.GetToolDefinition("submit_address_correction").Description, "Disabled"))
+
+	response, err := s.handleMessage([]byte(correctionCall))
+	require.NoError(t, err)
+	require.Nil(t, response.Error)
+	data, _ := json.Marshal(response.Result)
+	assert.Contains(t, string(data), "corr-1")
+
+	result, err := s.tools["submit_address_correction"](s.ctx, map[string]interface{}{"address_id": "addr-1", "field": "birthday", "value": "x"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Error: field must be one of: name, email, phone, address, city, postal_code", result.Content[0].Text)
+}
@@ -0,0 +1,160 @@
+// Package memory keeps the in-memory caches and snapshots of the server
+// within a common memory budget.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// entryOverhead approximates the bookkeeping bytes of a cache entry
+const entryOverhead = 64
+
+// Store is an in-memory store that can give up entries under pressure
+type Store interface {
+	// MemoryUsage returns the estimated size and number of entries
+	MemoryUsage() Usage
+	// Evict removes entries, least recently used first, until at least
+	// bytes are freed or the store is empty, and returns the bytes freed
+	Evict(bytes int64) int64
+}
+
+// Usage is the estimated memory use of a store
+type Usage struct {
+	Bytes   int64 `json:"bytes"`
+	Entries int   `json:"entries"`
+}
+
+// StoreStats is the usage and eviction count of a registered store
+type StoreStats struct {
+	Name string `json:"name"`
+	Usage
+	Evictions    int64 `json:"evictions"`     // Eviction rounds that freed memory
+	EvictedBytes int64 `json:"evicted_bytes"` // Total bytes freed
+}
+
+// Stats reports the memory use of all registered stores
+type Stats struct {
+	LimitBytes int64        `json:"limit_bytes"` // 0 if unlimited
+	UsedBytes  int64        `json:"used_bytes"`
+	Stores     []StoreStats `json:"stores"`
+}
+
+type registration struct {
+	name         string
+	store        Store
+	evictions    int64
+	evictedBytes int64
+}
+
+// Budget tracks the memory use of registered stores and evicts entries of
+// the largest stores when the limit is exceeded
+type Budget struct {
+	limit  int64
+	logger *logrus.Logger
+	mu     sync.Mutex
+	stores []*registration
+}
+
+// NewBudget creates a budget of limit bytes. A limit of 0 only tracks usage.
+func NewBudget(limit int64, logger *logrus.Logger) *Budget {
+	return &Budget{limit: limit, logger: logger}
+}
+
+// Register adds a store to the budget. Registering a name again replaces
+// the store.
+func (b *Budget) Register(name string, store Store) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range b.stores {
+		if r.name == name {
+			r.store = store
+			return
+		}
+	}
+	b.stores = append(b.stores, &registration{name: name, store: store})
+}
+
+// Enforce evicts entries until the stores use at most 90% of the limit,
+// taking from the largest store first, and returns the bytes freed
+func (b *Budget) Enforce() int64 {
+	if b.limit <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	usage := make(map[*registration]int64, len(b.stores))
+	var used int64
+	for _, r := range b.stores {
+		usage[r] = r.store.MemoryUsage().Bytes
+		used += usage[r]
+	}
+	if used <= b.limit {
+		return 0
+	}
+
+	target := b.limit / 10 * 9
+	candidates := append([]*registration(nil), b.stores...)
+	sort.SliceStable(candidates, func(i, j int) bool { return usage[candidates[i]] > usage[candidates[j]] })
+
+	var freed int64
+	for _, r := range candidates {
+		if used <= target {
+			break
+		}
+		n := r.store.Evict(min(used-target, usage[r]))
+		if n > 0 {
+			r.evictions++
+			r.evictedBytes += n
+			used -= n
+			freed += n
+		}
+	}
+
+	if b.logger != nil {
+		b.logger.WithField("freed_bytes", freed).WithField("used_bytes", used).WithField("limit_bytes", b.limit).Info("Memory budget exceeded, evicted cache entries")
+	}
+	return freed
+}
+
+// Stats returns the current usage of all registered stores
+func (b *Budget) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := Stats{LimitBytes: b.limit, Stores: make([]StoreStats, len(b.stores))}
+	for i, r := range b.stores {
+		stats.Stores[i] = StoreStats{Name: r.name, Usage: r.store.MemoryUsage(), Evictions: r.evictions, EvictedBytes: r.evictedBytes}
+		stats.UsedBytes += stats.Stores[i].Bytes
+	}
+	return stats
+}
+
+// Run enforces the budget every interval until the context is done
+func (b *Budget) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.Enforce()
+		}
+	}
+}
+
+// EstimateSize estimates the memory held by a value from the size of its
+// JSON encoding plus a fixed overhead
+func EstimateSize(v interface{}) int64 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return entryOverhead
+	}
+	return int64(len(data)) + entryOverhead
+}
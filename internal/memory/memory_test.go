@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStore holds entries of the given sizes, evicting from the front
+type fakeStore struct {
+	entries []int64
+}
+
+func (f *fakeStore) MemoryUsage() Usage {
+	usage := Usage{Entries: len(f.entries)}
+	for _, size := range f.entries {
+		usage.Bytes += size
+	}
+	return usage
+}
+
+func (f *fakeStore) Evict(bytes int64) int64 {
+	var freed int64
+	for len(f.entries) > 0 && freed < bytes {
+		freed += f.entries[0]
+		f.entries = f.entries[1:]
+	}
+	return freed
+}
+
+func TestBudget_Enforce(t *testing.T) {
+	small := &fakeStore{entries: []int64{100, 100}}
+	large := &fakeStore{entries: []int64{300, 300, 200}}
+	budget := NewBudget(1000, nil)
+	budget.Register("small", small)
+	budget.Register("large", large)
+
+	assert.Zero(t, budget.Enforce(), "within the limit")
+
+	large.entries = append(large.entries, 300)
+	// 1300 bytes used: evict down to 900, from the largest store first
+	assert.Equal(t, int64(600), budget.Enforce())
+	assert.Len(t, large.entries, 2)
+	assert.Len(t, small.entries, 2)
+
+	stats := budget.Stats()
+	assert.Equal(t, int64(1000), stats.LimitBytes)
+	assert.Equal(t, int64(700), stats.UsedBytes)
+	assert.Equal(t, []StoreStats{
+		{Name: "small", Usage: Usage{Bytes: 200, Entries: 2}},
+		{Name: "large", Usage: Usage{Bytes: 500, Entries: 2}, Evictions: 1, EvictedBytes: 600},
+	}, stats.Stores)
+}
+
+func TestBudget_EnforceSeveralStores(t *testing.T) {
+	a := &fakeStore{entries: []int64{400}}
+	b := &fakeStore{entries: []int64{300}}
+	c := &fakeStore{entries: []int64{300}}
+	budget := NewBudget(500, nil)
+	budget.Register("a", &fakeStore{})
+	budget.Register("b", b)
+	budget.Register("c", c)
+	budget.Register("a", a)
+	assert.Len(t, budget.Stats().Stores, 3, "registering a name again replaces the store")
+
+	// 1000 bytes used, 450 allowed: emptying a is not enough, b follows
+	assert.Equal(t, int64(700), budget.Enforce())
+	assert.Empty(t, a.entries)
+	assert.Empty(t, b.entries)
+	assert.Len(t, c.entries, 1)
+}
+
+func TestBudget_Unlimited(t *testing.T) {
+	store := &fakeStore{entries: []int64{1 << 30}}
+	budget := NewBudget(0, nil)
+	budget.Register("store", store)
+	assert.Zero(t, budget.Enforce())
+	assert.Equal(t, int64(1<<30), budget.Stats().UsedBytes)
+}
+
+func TestEstimateSize(t *testing.T) {
+	assert.Equal(t, int64(entryOverhead+len(`{"a":1}`)), EstimateSize(map[string]int{"a": 1}))
+	assert.Equal(t, int64(entryOverhead), EstimateSize(func() {}))
+}
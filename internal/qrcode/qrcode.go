@@ -0,0 +1,525 @@
+// Package qrcode encodes QR codes (ISO/IEC 18004) in byte mode and renders
+// them as PNG images, so that the server needs no external encoder
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Level is the error correction level of a QR code. Higher levels survive
+// more damage, such as a logo printed over the code, at the cost of size.
+type Level int
+
+// Error correction levels, recovering about 7%, 15%, 25% and 30% of the
+// codewords
+const (
+	LevelL Level = iota
+	LevelM
+	LevelQ
+	LevelH
+)
+
+// formatBits are the bits of the levels in the format information
+var formatBits = [4]int{1, 0, 3, 2}
+
+const (
+	minVersion = 1
+	maxVersion = 40
+)
+
+// eccCodewordsPerBlock and numErrorCorrectionBlocks are the error
+// correction layout of each level and version (ISO/IEC 18004, table 9).
+// Index 0 is unused.
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is a QR code. Modules are indexed by row and column; true is dark.
+type Code struct {
+	Version int
+	Size    int // Modules per side, 17 + 4 * Version
+	modules [][]bool
+	// function marks the finder, timing, alignment, format and version
+	// modules, which carry no data and are not masked
+	function [][]bool
+}
+
+// Dark reports whether the module in row y, column x is dark
+func (c *Code) Dark(x, y int) bool {
+	return y >= 0 && y < c.Size && x >= 0 && x < c.Size && c.modules[y][x]
+}
+
+// Encode returns the smallest QR code holding data in byte mode at the
+// given error correction level, with the mask of the lowest penalty
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < LevelL || level > LevelH {
+		return nil, fmt.Errorf("invalid error correction level %d", level)
+	}
+	version := minVersion
+	for ; version <= maxVersion; version++ {
+		if 4+characterCountBits(version)+8*len(data) <= 8*dataCodewords(version, level) {
+			break
+		}
+	}
+	if version > maxVersion {
+		return nil, fmt.Errorf("%d bytes do not fit into a QR code at this error correction level", len(data))
+	}
+
+	// Mode indicator, character count and data, then a terminator of up to
+	// four zero bits and padding to the capacity
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), characterCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := 8 * dataCodewords(version, level)
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	code := newCode(version)
+	code.drawFunctionPatterns(level)
+	code.drawCodewords(addErrorCorrection(bits.bytes(), version, level))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		code.applyMask(mask)
+		code.drawFormatBits(level, mask)
+		if penalty := code.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		code.applyMask(mask) // Masks are their own inverse
+	}
+	code.applyMask(best)
+	code.drawFormatBits(level, best)
+	return code, nil
+}
+
+// PNG renders the code with scale pixels per module and a quiet zone of
+// border modules
+func (c *Code) PNG(scale, border int) ([]byte, error) {
+	if scale < 1 || border < 0 {
+		return nil, fmt.Errorf("invalid scale %d or border %d", scale, border)
+	}
+	side := (c.Size + 2*border) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			if c.Dark(x/scale-border, y/scale-border) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// characterCountBits returns the length of the character count of byte mode
+func characterCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// rawDataModules returns the number of modules available for data and
+// error correction codewords, including remainder bits
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// dataCodewords returns the number of data codewords of a version and level
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+// alignmentPositions returns the row and column centers of the alignment
+// patterns of a version
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	}
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, 17+4*version-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// newCode returns an empty code of a version
+func newCode(version int) *Code {
+	size := 17 + 4*version
+	c := &Code{Version: version, Size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+	return c
+}
+
+// setFunction sets a function module
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and
+// the version information, and reserves the format information
+func (c *Code) drawFunctionPatterns(level Level) {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+	for i, y := range positions {
+		for j, x := range positions {
+			// Skip the corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	c.drawFormatBits(level, 0)
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern with its separator around a center
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			if x+dx < 0 || x+dx >= c.Size || y+dy < 0 || y+dy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(x+dx, y+dy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information, the level
+// and mask protected by a BCH code
+func (c *Code) drawFormatBits(level Level, mask int) {
+	data := formatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.Size-8, true) // Always dark
+}
+
+// drawVersion draws both copies of the version information of versions 7
+// and up
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in the data modules, in two-module
+// wide columns zigzagging up and down from the bottom right
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y][x] = bit(int(codewords[i>>3]), 7-i&7)
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by a mask pattern
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.function[y][x] && masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// masked reports whether a mask pattern inverts the module at x, y
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores a masked code by the rules of ISO/IEC 18004 section 7.8.3:
+// runs of modules of one color, 2x2 blocks, patterns resembling finders and
+// an unbalanced share of dark modules. The mask with the lowest score is used.
+func (c *Code) penalty() int {
+	score := 0
+	dark := 0
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for i := 0; i < c.Size; i++ {
+		row := make([]bool, c.Size)
+		column := make([]bool, c.Size)
+		for j := 0; j < c.Size; j++ {
+			row[j], column[j] = c.modules[i][j], c.modules[j][i]
+			if row[j] {
+				dark++
+			}
+		}
+		for _, line := range [][]bool{row, column} {
+			run := 1
+			for j := 1; j <= len(line); j++ {
+				if j < len(line) && line[j] == line[j-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+			for j := 0; j+11 <= len(line); j++ {
+				for _, pattern := range finderLike {
+					if matches(line[j:j+11], pattern) {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+
+	for y := 0; y+1 < c.Size; y++ {
+		for x := 0; x+1 < c.Size; x++ {
+			m := c.modules[y][x]
+			if m == c.modules[y][x+1] && m == c.modules[y+1][x] && m == c.modules[y+1][x+1] {
+				score += 3
+			}
+		}
+	}
+
+	total := c.Size * c.Size
+	score += abs(dark*100/total-50) / 5 * 10
+	return score
+}
+
+// matches reports whether a run of modules equals a pattern
+func matches(line, pattern []bool) bool {
+	for i := range pattern {
+		if line[i] != pattern[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// addErrorCorrection splits the data codewords into blocks, appends the
+// Reed-Solomon codewords of each block and interleaves the blocks
+func addErrorCorrection(data []byte, version int, level Level) []byte {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	blockECC := eccCodewordsPerBlock[level][version]
+	raw := rawDataModules(version) / 8
+	numShortBlocks := numBlocks - raw%numBlocks
+	shortBlockLen := raw / numBlocks
+
+	divisor := reedSolomonDivisor(blockECC)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockECC
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := append([]byte(nil), data[k:k+dataLen]...)
+		k += dataLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0) // Placeholder, skipped when interleaving
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, raw)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECC || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial of a degree, highest
+// coefficient first without the leading 1
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// bitBuffer is a sequence of bits, most significant first
+type bitBuffer []bool
+
+// append adds the lowest n bits of value
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 != 0)
+	}
+}
+
+// bytes packs the bits into bytes
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, (len(b)+7)/8)
+	for i, set := range b {
+		if set {
+			result[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return result
+}
+
+// bit reports whether bit i of x is set
+func bit(x, i int) bool {
+	return (x>>i)&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
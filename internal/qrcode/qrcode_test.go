@@ -0,0 +1,200 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// Version 1-M example of ISO/IEC 18004, annex I
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	ecc := reedSolomonRemainder(data, reedSolomonDivisor(10))
+	assert.Equal(t, []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}, ecc)
+}
+
+func TestLayoutTables(t *testing.T) {
+	// Data capacities of ISO/IEC 18004, table 7
+	for _, tc := range []struct {
+		version  int
+		capacity [4]int
+	}{
+		{1, [4]int{19, 16, 13, 9}},
+		{10, [4]int{274, 216, 154, 122}},
+		{20, [4]int{861, 669, 485, 385}},
+		{40, [4]int{2956, 2334, 1666, 1276}},
+	} {
+		for level := LevelL; level <= LevelH; level++ {
+			assert.Equal(t, tc.capacity[level], dataCodewords(tc.version, level), "version %d level %d", tc.version, level)
+		}
+	}
+	assert.Equal(t, []int{6, 22, 38}, alignmentPositions(7))
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, alignmentPositions(32))
+	assert.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPositions(40))
+}
+
+func TestEncode_FunctionPatterns(t *testing.T) {
+	code, err := Encode([]byte(strings.Repeat("https://www.schachbund.de/", 6)), LevelM)
+	require.NoError(t, err)
+	require.Equal(t, 9, code.Version)
+
+	// Finder pattern in the top left corner, followed by its separator
+	for i, want := range []bool{true, true, true, true, true, true, true, false} {
+		assert.Equal(t, want, code.Dark(i, 0), "module %d", i)
+	}
+	// Version information of version 7 and up, 000111110010010100 for 7
+	version7 := newCode(7)
+	version7.drawVersion()
+	var bits int
+	for i := 17; i >= 0; i-- {
+		bits <<= 1
+		if version7.Dark(version7.Size-11+i%3, i/3) {
+			bits |= 1
+		}
+	}
+	assert.Equal(t, 0x07C94, bits)
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, level := range []Level{LevelL, LevelM, LevelQ, LevelH} {
+		for _, text := range []string{
+			"https://www.schachbund.de/spieler/C0327-297.html",
+			"C0327",
+			strings.Repeat("Turnier ", 40),
+		} {
+			code, err := Encode([]byte(text), level)
+			require.NoError(t, err)
+			assert.Equal(t, text, string(decode(t, code, level)), "level %d", level)
+		}
+	}
+
+	_, err := Encode(make([]byte, 3000), LevelH)
+	assert.Error(t, err)
+}
+
+func TestPNG(t *testing.T) {
+	code, err := Encode([]byte("https://www.schachbund.de/verein/C0327.html"), LevelM)
+	require.NoError(t, err)
+	data, err := code.PNG(4, 4)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, (code.Size+8)*4, img.Bounds().Dx())
+	r, _, _, _ := img.At(16, 16).RGBA()
+	assert.Zero(t, r, "the finder pattern starts after the quiet zone")
+	r, _, _, _ = img.At(15, 15).RGBA()
+	assert.NotZero(t, r)
+}
+
+// decode reads a code back: it checks the format information, removes the
+// mask, reads the codewords, checks the error correction of every block and
+// returns the data of the byte mode segment
+func decode(t *testing.T, code *Code, level Level) []byte {
+	var format int
+	for i := 14; i >= 9; i-- {
+		format = format<<1 | boolBit(code.Dark(14-i, 8))
+	}
+	format = format<<1 | boolBit(code.Dark(7, 8))
+	format = format<<1 | boolBit(code.Dark(8, 8))
+	format = format<<1 | boolBit(code.Dark(8, 7))
+	for i := 5; i >= 0; i-- {
+		format = format<<1 | boolBit(code.Dark(8, i))
+	}
+	format ^= 0x5412
+	require.Equal(t, formatBits[level], format>>13, "level of the format information")
+	mask := format >> 10 & 7
+
+	reserved := newCode(code.Version)
+	reserved.drawFunctionPatterns(level)
+	var codewords []byte
+	var current, n int
+	for right := code.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < code.Size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = code.Size - 1 - vert
+			}
+			for _, x := range []int{right, right - 1} {
+				if reserved.function[y][x] {
+					continue
+				}
+				current = current<<1 | boolBit(code.Dark(x, y) != masked(mask, x, y))
+				if n++; n%8 == 0 {
+					codewords = append(codewords, byte(current))
+					current = 0
+				}
+			}
+		}
+	}
+	require.Len(t, codewords, rawDataModules(code.Version)/8)
+
+	numBlocks := numErrorCorrectionBlocks[level][code.Version]
+	blockECC := eccCodewordsPerBlock[level][code.Version]
+	numShort := numBlocks - len(codewords)%numBlocks
+	shortLen := len(codewords)/numBlocks - blockECC
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortLen+1; i++ {
+		for j := range blocks {
+			if i < shortLen || j >= numShort {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < blockECC; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+
+	var data []byte
+	for j, block := range blocks {
+		root := byte(1)
+		for i := 0; i < blockECC; i++ {
+			var syndrome byte
+			for _, c := range block {
+				syndrome = gfMultiply(syndrome, root) ^ c
+			}
+			require.Zero(t, syndrome, "syndrome %d of block %d", i, j)
+			root = gfMultiply(root, 0x02)
+		}
+		data = append(data, block[:len(block)-blockECC]...)
+	}
+
+	bits := bitBuffer{}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	read := func(offset, n int) int {
+		value := 0
+		for _, set := range bits[offset : offset+n] {
+			value = value<<1 | boolBit(set)
+		}
+		return value
+	}
+	require.Equal(t, 0x4, read(0, 4), "byte mode")
+	countBits := characterCountBits(code.Version)
+	length := read(4, countBits)
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = byte(read(4+countBits+8*i, 8))
+	}
+	return result
+}
+
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,143 @@
+// Package render formats dates and numbers of human-readable output, such
+// as email digests, for a locale. Text renderers use it instead of ad-hoc
+// fmt calls and time layouts, so that all output of a deployment reads alike.
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Locale selects the conventions of the formatted output
+type Locale string
+
+// Supported locales. German is the default, matching the federation whose
+// data is served.
+const (
+	German  Locale = "de"
+	English Locale = "en"
+
+	DefaultLocale = German
+)
+
+// conventions are the separators and layouts of a locale
+type conventions struct {
+	date     string // time layout of dates
+	dateTime string // time layout of dates with the time of day
+	group    string // thousands separator
+	decimal  string // decimal separator
+}
+
+var locales = map[Locale]conventions{
+	German:  {date: "02.01.2006", dateTime: "02.01.2006 15:04", group: ".", decimal: ","},
+	English: {date: "2006-01-02", dateTime: "2006-01-02 15:04", group: ",", decimal: "."},
+}
+
+// minGroupingDigits is the number of integer digits from which thousands
+// are separated, so that four-digit numbers such as ratings and years stay
+// as they are written in chess results
+const minGroupingDigits = 5
+
+// ParseLocale returns the locale of a language tag such as "de", "de-DE" or
+// "en_US". The empty tag is the default locale.
+func ParseLocale(tag string) (Locale, error) {
+	if tag == "" {
+		return DefaultLocale, nil
+	}
+	language, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	locale := Locale(strings.ToLower(language))
+	if _, ok := locales[locale]; !ok {
+		return "", fmt.Errorf("unsupported locale %q, expected de or en", tag)
+	}
+	return locale, nil
+}
+
+// conventions returns the conventions of the locale, those of the default
+// locale for unknown ones
+func (l Locale) conventions() conventions {
+	if c, ok := locales[l]; ok {
+		return c
+	}
+	return locales[DefaultLocale]
+}
+
+// Date formats the date of t, e.g. 01.11.2026 in German
+func (l Locale) Date(t time.Time) string {
+	return t.Format(l.conventions().date)
+}
+
+// DateTime formats the date and time of day of t, e.g. 01.11.2026 14:30 in
+// German
+func (l Locale) DateTime(t time.Time) string {
+	return t.Format(l.conventions().dateTime)
+}
+
+// Int formats an integer with thousands separators, e.g. 12.345 in German.
+// Numbers of fewer than five digits are not separated.
+func (l Locale) Int(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	return sign + l.group(digits)
+}
+
+// Decimal formats a number with a fixed number of fraction digits, e.g.
+// 1.234,5 or 1712,3 in German
+func (l Locale) Decimal(f float64, digits int) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	formatted := strconv.FormatFloat(math.Abs(f), 'f', digits, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+	sign := ""
+	if f < 0 && strings.Trim(formatted, "0.") != "" {
+		sign = "-"
+	}
+	result := sign + l.group(integer)
+	if fraction != "" {
+		result += l.conventions().decimal + fraction
+	}
+	return result
+}
+
+// Change formats a difference with its sign, e.g. +22 or -5
+func (l Locale) Change(n int) string {
+	if n > 0 {
+		return "+" + l.Int(n)
+	}
+	return l.Int(n)
+}
+
+// group separates the thousands of a string of digits
+func (l Locale) group(digits string) string {
+	if len(digits) < minGroupingDigits {
+		return digits
+	}
+	separator := l.conventions().group
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// FuncMap returns the formatting functions of the locale for templates:
+// date, datetime, int, decimal and change
+func (l Locale) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"date":     l.Date,
+		"datetime": l.DateTime,
+		"int":      l.Int,
+		"decimal":  l.Decimal,
+		"change":   l.Change,
+	}
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocale(t *testing.T) {
+	for tag, want := range map[string]Locale{"": German, "de": German, "de-DE": German, "DE_at": German, "en": English, "en-GB": English} {
+		locale, err := ParseLocale(tag)
+		require.NoError(t, err, tag)
+		assert.Equal(t, want, locale, tag)
+	}
+	for _, tag := range []string{"fr", "-", "deutsch"} {
+		_, err := ParseLocale(tag)
+		assert.Error(t, err, tag)
+	}
+}
+
+func TestLocale_Format(t *testing.T) {
+	date := time.Date(2026, 11, 1, 14, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		locale                 Locale
+		date, dateTime         string
+		small, large, negative string
+		decimal, largeDecimal  string
+		change, negativeChange string
+	}{
+		{German, "01.11.2026", "01.11.2026 14:30", "1712", "123.456", "-12.345", "1712,3", "12.345,68", "+22", "-5"},
+		{English, "2026-11-01", "2026-11-01 14:30", "1712", "123,456", "-12,345", "1712.3", "12,345.68", "+22", "-5"},
+	} {
+		l := tc.locale
+		assert.Equal(t, tc.date, l.Date(date))
+		assert.Equal(t, tc.dateTime, l.DateTime(date))
+		assert.Equal(t, tc.small, l.Int(1712), "four digits are not separated")
+		assert.Equal(t, tc.large, l.Int(123456))
+		assert.Equal(t, tc.negative, l.Int(-12345))
+		assert.Equal(t, tc.decimal, l.Decimal(1712.31, 1))
+		assert.Equal(t, tc.largeDecimal, l.Decimal(12345.678, 2))
+		assert.Equal(t, tc.change, l.Change(22))
+		assert.Equal(t, tc.negativeChange, l.Change(-5))
+	}
+	assert.Equal(t, "0,0", German.Decimal(-0.01, 1), "no sign for values rounded to zero")
+	assert.Equal(t, "01.11.2026", Locale("fr").Date(date), "unknown locales format as the default")
+}
+
+func TestLocale_FuncMap(t *testing.T) {
+	tmpl := template.Must(template.New("").Funcs(German.FuncMap()).Parse(`{{date .Date}}: {{int .Count}} ({{change .Diff}}), Ø {{decimal .Average 1}}`))
+	var b strings.Builder
+	require.NoError(t, tmpl.Execute(&b, map[string]interface{}{
+		"Date": time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC), "Count": 24500, "Diff": 13, "Average": 1650.26,
+	}))
+	assert.Equal(t, "07.03.2026: 24.500 (+13), Ø 1650,3", b.String())
+}
@@ -0,0 +1,323 @@
+// Package store persists rating histories and tournament results fetched
+// from the Portal64 API, so historical data stays available when the
+// upstream prunes it or is rate limited.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+// ErrNotFound is returned when nothing is stored for an entity
+var ErrNotFound = errors.New("not stored")
+
+// ErrUnavailable is returned while the store file is locked by another
+// process, or when it failed to open after the lock was released
+var ErrUnavailable = errors.New("store is unavailable")
+
+// Store persists historical data keyed by entity ID and date
+type Store interface {
+	// SaveEvaluations merges evaluations of a player into the store and
+	// returns all stored evaluations of the player, oldest first
+	SaveEvaluations(playerID string, evaluations []api.Evaluation) ([]api.Evaluation, error)
+	// Evaluations returns the stored evaluations of a player, oldest first
+	Evaluations(playerID string) ([]api.Evaluation, error)
+	// SaveTournament stores the latest details of a tournament
+	SaveTournament(tournamentID string, details *api.EnhancedTournamentResponse) error
+	// Tournament returns the stored details of a tournament
+	Tournament(tournamentID string) (*api.EnhancedTournamentResponse, error)
+	Close() error
+}
+
+// Buckets of the store file. Evaluations are kept in a nested bucket per
+// player, keyed by evaluationKey; tournaments are keyed by ID.
+var (
+	evaluationsBucket = []byte("evaluations")
+	tournamentsBucket = []byte("tournaments")
+)
+
+// lockRetryInterval is how long opening the store file waits for the lock
+// of another process before retrying in the background
+var lockRetryInterval = time.Second
+
+// BoltStore is a Store backed by a bbolt database file, so the stored data
+// is read from disk on demand instead of being held in memory.
+//
+// Only one process can open the file. While another instance holds it, as
+// during a blue/green restart, the store keeps trying to open it in the
+// background and its methods return ErrUnavailable.
+type BoltStore struct {
+	mu      sync.RWMutex
+	db      *bolt.DB
+	openErr error // Why opening the file failed after waiting for the lock
+	closed  bool
+	done    chan struct{}
+}
+
+// OpenBoltStore opens or creates the store file at path
+func OpenBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	s := &BoltStore{done: make(chan struct{})}
+	db, err := openDB(path)
+	switch {
+	case errors.Is(err, bolt.ErrTimeout):
+		go s.waitForLock(path)
+	case err != nil:
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	default:
+		s.db = db
+	}
+	return s, nil
+}
+
+// openDB opens the store file and creates its buckets
+func openDB(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: lockRetryInterval})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{evaluationsBucket, tournamentsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// waitForLock opens the store file once the other process releases it. If
+// it then fails to open, the store stays unavailable and its methods return
+// the error wrapped in ErrUnavailable.
+func (s *BoltStore) waitForLock(path string) {
+	for {
+		db, err := openDB(path)
+		if err == nil {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.closed {
+				db.Close()
+				return
+			}
+			s.db = db
+			return
+		}
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		if !errors.Is(err, bolt.ErrTimeout) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.openErr = err
+			return
+		}
+	}
+}
+
+// Ready reports whether the store file is open. It is not while another
+// process holds it.
+func (s *BoltStore) Ready() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db != nil
+}
+
+// view runs a read-only transaction
+func (s *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := s.check(); err != nil {
+		return err
+	}
+	return s.db.View(fn)
+}
+
+// update runs a read-write transaction
+func (s *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := s.check(); err != nil {
+		return err
+	}
+	return s.db.Update(fn)
+}
+
+func (s *BoltStore) check() error {
+	switch {
+	case s.closed:
+		return fmt.Errorf("store is closed")
+	case s.openErr != nil:
+		return fmt.Errorf("%w: failed to open store: %v", ErrUnavailable, s.openErr)
+	case s.db == nil:
+		return fmt.Errorf("%w: file is in use by another process", ErrUnavailable)
+	}
+	return nil
+}
+
+// evaluationKey identifies an evaluation of a player by date and tournament
+func evaluationKey(evaluation api.Evaluation) string {
+	id := evaluation.TournamentID
+	if id == "" {
+		id = evaluation.ID
+	}
+	return evaluation.Date.UTC().Format(time.DateOnly) + "/" + id
+}
+
+// putEvaluation stores an evaluation of a player unless it is stored
+// unchanged
+func putEvaluation(tx *bolt.Tx, playerID, key string, data []byte) error {
+	player, err := tx.Bucket(evaluationsBucket).CreateBucketIfNotExists([]byte(playerID))
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(player.Get([]byte(key)), data) {
+		return nil
+	}
+	return player.Put([]byte(key), data)
+}
+
+// putTournament stores the details of a tournament unless they are stored
+// unchanged
+func putTournament(tx *bolt.Tx, tournamentID string, data []byte) error {
+	tournaments := tx.Bucket(tournamentsBucket)
+	if bytes.Equal(tournaments.Get([]byte(tournamentID)), data) {
+		return nil
+	}
+	return tournaments.Put([]byte(tournamentID), data)
+}
+
+// SaveEvaluations implements Store. Evaluations that are already stored
+// unchanged are not written again.
+func (s *BoltStore) SaveEvaluations(playerID string, evaluations []api.Evaluation) ([]api.Evaluation, error) {
+	var stored []api.Evaluation
+	err := s.update(func(tx *bolt.Tx) error {
+		for _, evaluation := range evaluations {
+			data, err := json.Marshal(evaluation)
+			if err != nil {
+				return err
+			}
+			if err := putEvaluation(tx, playerID, evaluationKey(evaluation), data); err != nil {
+				return err
+			}
+		}
+		var err error
+		stored, err = readEvaluations(tx, playerID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write store: %w", err)
+	}
+	return stored, nil
+}
+
+// Evaluations implements Store
+func (s *BoltStore) Evaluations(playerID string) ([]api.Evaluation, error) {
+	var stored []api.Evaluation
+	err := s.view(func(tx *bolt.Tx) error {
+		var err error
+		stored, err = readEvaluations(tx, playerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return nil, ErrNotFound
+	}
+	return stored, nil
+}
+
+// readEvaluations returns the stored evaluations of a player, oldest first
+func readEvaluations(tx *bolt.Tx, playerID string) ([]api.Evaluation, error) {
+	player := tx.Bucket(evaluationsBucket).Bucket([]byte(playerID))
+	if player == nil {
+		return nil, nil
+	}
+	var evaluations []api.Evaluation
+	err := player.ForEach(func(key, data []byte) error {
+		var evaluation api.Evaluation
+		if err := json.Unmarshal(data, &evaluation); err != nil {
+			return fmt.Errorf("evaluation %s of %s: %w", key, playerID, err)
+		}
+		evaluations = append(evaluations, evaluation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Keys start with the date, so only evaluations of the same day may
+	// need reordering
+	sort.SliceStable(evaluations, func(i, j int) bool {
+		return evaluations[i].Date.Before(evaluations[j].Date)
+	})
+	return evaluations, nil
+}
+
+// SaveTournament implements Store. Unchanged details are not written again.
+func (s *BoltStore) SaveTournament(tournamentID string, details *api.EnhancedTournamentResponse) error {
+	if details == nil {
+		return nil
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	if err := s.update(func(tx *bolt.Tx) error { return putTournament(tx, tournamentID, data) }); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	return nil
+}
+
+// Tournament implements Store
+func (s *BoltStore) Tournament(tournamentID string) (*api.EnhancedTournamentResponse, error) {
+	var details *api.EnhancedTournamentResponse
+	err := s.view(func(tx *bolt.Tx) error {
+		data := tx.Bucket(tournamentsBucket).Get([]byte(tournamentID))
+		if data == nil {
+			return ErrNotFound
+		}
+		details = &api.EnhancedTournamentResponse{}
+		return json.Unmarshal(data, details)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// Close implements Store
+func (s *BoltStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
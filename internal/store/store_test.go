@@ -0,0 +1,135 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/svw-info/portal64gomcp/internal/api"
+)
+
+func evaluation(tournamentID string, date string, oldDWZ, newDWZ int) api.Evaluation {
+	d, _ := time.Parse(time.DateOnly, date)
+	return api.Evaluation{TournamentID: tournamentID, Date: d, OldDWZ: oldDWZ, NewDWZ: newDWZ, DWZChange: newDWZ - oldDWZ}
+}
+
+func TestBoltStore_Evaluations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "store.db")
+	s, err := OpenBoltStore(path)
+	require.NoError(t, err)
+	assert.True(t, s.Ready())
+
+	_, err = s.Evaluations("C0327-297")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	merged, err := s.SaveEvaluations("C0327-297", []api.Evaluation{
+		evaluation("T2", "2023-05-01", 1610, 1625),
+		evaluation("T1", "2022-01-15", 1600, 1610),
+	})
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "T1", merged[0].TournamentID, "evaluations are sorted by date")
+
+	// The upstream pruned T1; the stored history keeps it
+	merged, err = s.SaveEvaluations("C0327-297", []api.Evaluation{
+		evaluation("T2", "2023-05-01", 1610, 1625),
+		evaluation("T3", "2024-02-01", 1625, 1640),
+	})
+	require.NoError(t, err)
+	require.Len(t, merged, 3)
+	assert.Equal(t, []string{"T1", "T2", "T3"}, []string{merged[0].TournamentID, merged[1].TournamentID, merged[2].TournamentID})
+	require.NoError(t, s.Close())
+
+	reopened, err := OpenBoltStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	stored, err := reopened.Evaluations("C0327-297")
+	require.NoError(t, err)
+	assert.Equal(t, merged, stored)
+}
+
+func TestBoltStore_Tournament(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	s, err := OpenBoltStore(path)
+	require.NoError(t, err)
+
+	_, err = s.Tournament("C529-K00-HT1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	details := &api.EnhancedTournamentResponse{
+		Tournament: &api.TournamentResponse{ID: "C529-K00-HT1", Name: "Herbstturnier"},
+		Games:      []api.GameResult{{Round: 1, Result: "1-0"}},
+	}
+	require.NoError(t, s.SaveTournament("C529-K00-HT1", details))
+	details.Games = append(details.Games, api.GameResult{Round: 2, Result: "0-1"})
+	require.NoError(t, s.SaveTournament("C529-K00-HT1", details))
+	require.NoError(t, s.Close())
+
+	reopened, err := OpenBoltStore(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	stored, err := reopened.Tournament("C529-K00-HT1")
+	require.NoError(t, err)
+	assert.Equal(t, "Herbstturnier", stored.Tournament.Name)
+	assert.Len(t, stored.Games, 2)
+}
+
+func TestBoltStore_Locked(t *testing.T) {
+	defer func(interval time.Duration) { lockRetryInterval = interval }(lockRetryInterval)
+	lockRetryInterval = 50 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "store.db")
+	first, err := OpenBoltStore(path)
+	require.NoError(t, err)
+
+	second, err := OpenBoltStore(path)
+	require.NoError(t, err)
+	defer second.Close()
+	assert.False(t, second.Ready())
+	_, err = second.Evaluations("C0327-297")
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	// The store is opened once the other process releases it
+	require.NoError(t, first.Close())
+	assert.Eventually(t, second.Ready, 2*time.Second, 10*time.Millisecond)
+	_, err = second.Evaluations("C0327-297")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBoltStore_FailsAfterLock(t *testing.T) {
+	defer func(interval time.Duration) { lockRetryInterval = interval }(lockRetryInterval)
+	lockRetryInterval = 50 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "store.db")
+	first, err := OpenBoltStore(path)
+	require.NoError(t, err)
+	second, err := OpenBoltStore(path)
+	require.NoError(t, err)
+	defer second.Close()
+
+	// The file is no store once the other process releases it
+	require.NoError(t, os.WriteFile(path, []byte("not a store"), 0o644))
+	require.NoError(t, first.Close())
+	assert.Eventually(t, func() bool {
+		_, err := second.Evaluations("C0327-297")
+		return err != nil && strings.Contains(err.Error(), "failed to open store")
+	}, 2*time.Second, 10*time.Millisecond)
+	_, err = second.Evaluations("C0327-297")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.False(t, second.Ready())
+}
+
+func TestBoltStore_Closed(t *testing.T) {
+	s, err := OpenBoltStore(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close())
+
+	_, err = s.SaveEvaluations("C0327-297", []api.Evaluation{evaluation("T1", "2022-01-15", 1600, 1610)})
+	assert.ErrorContains(t, err, "store is closed")
+}
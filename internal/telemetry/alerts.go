@@ -0,0 +1,299 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of alerts
+const (
+	AlertBreakerOpen       = "breaker_open"       // A circuit breaker of an upstream opened
+	AlertCertificateExpiry = "certificate_expiry" // A configured certificate expires soon
+	AlertUpstreamErrors    = "upstream_errors"    // Sustained 5xx responses of the Portal64 API
+	AlertSLOBurn           = "slo_burn"           // An error budget burns faster than the threshold
+)
+
+// AlertKinds lists the kinds of alerts
+var AlertKinds = []string{AlertBreakerOpen, AlertCertificateExpiry, AlertUpstreamErrors, AlertSLOBurn}
+
+const (
+	// alertQueueSize bounds the alerts waiting to be sent
+	alertQueueSize = 100
+	// Defaults of the alerter
+	defaultAlertCooldown       = time.Hour
+	defaultAlertBurstThreshold = 10
+	defaultAlertBurstWindow    = 5 * time.Minute
+)
+
+// DefaultAlertTemplate is the text/template of alert messages unless
+// configured
+const DefaultAlertTemplate = `[{{.Severity}}] {{.Summary}}{{if .Suppressed}} ({{.Suppressed}} repeats suppressed){{end}}` +
+	`{{range $name, $value := .Fields}}
+{{$name}}: {{$value}}{{end}}`
+
+// AlertOptions configures an Alerter. At least one of SlackWebhookURL and
+// WebhookURL must be set.
+type AlertOptions struct {
+	SlackWebhookURL string // Slack incoming webhook receiving the message as text
+	WebhookURL      string // Generic endpoint receiving alerts as JSON
+	// Template is the text/template of the message, executed with the
+	// Alert; DefaultAlertTemplate if empty
+	Template string
+	Kinds    []string // Kinds sent, all if empty
+	// Cooldown suppresses repeats of an alert with the same kind and key
+	Cooldown time.Duration
+	Timeout  time.Duration // Timeout of sending an alert
+	// UpstreamErrorThreshold 5xx responses of the API within
+	// UpstreamErrorWindow raise an upstream_errors alert
+	UpstreamErrorThreshold int
+	UpstreamErrorWindow    time.Duration
+}
+
+// Alert is a notification about a condition operators should act on
+type Alert struct {
+	Kind     string            `json:"kind"`
+	Key      string            `json:"key"`      // What the alert is about, e.g. the upstream URL
+	Severity string            `json:"severity"` // LevelWarning or LevelError
+	Summary  string            `json:"summary"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Time     time.Time         `json:"time"`
+	Host     string            `json:"host,omitempty"`
+	// Suppressed counts the repeats suppressed during the cooldown before
+	// this alert
+	Suppressed int    `json:"suppressed,omitempty"`
+	Message    string `json:"message"` // Rendered from the template
+}
+
+// alertState tracks the last alert of a kind and key
+type alertState struct {
+	sent       time.Time
+	suppressed int
+}
+
+// Alerter sends alerts to Slack and generic webhooks in the background.
+// Repeats of an alert within the cooldown are suppressed and counted.
+type Alerter struct {
+	options    AlertOptions
+	template   *template.Template
+	kinds      map[string]bool
+	host       string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+	bursts     *burstDetector
+	now        func() time.Time
+
+	stateMu sync.Mutex
+	states  map[string]*alertState // By kind and key
+
+	mu     sync.RWMutex // guards closing alerts
+	closed bool
+	alerts chan *Alert
+	done   chan struct{}
+}
+
+// NewAlerter creates an alerter and starts sending alerts. A nil logger
+// discards log output.
+func NewAlerter(opts AlertOptions, logger logrus.FieldLogger) (*Alerter, error) {
+	if opts.SlackWebhookURL == "" && opts.WebhookURL == "" {
+		return nil, fmt.Errorf("alerter requires a Slack webhook URL or a webhook URL")
+	}
+	if logger == nil {
+		discard := logrus.New()
+		discard.SetOutput(io.Discard)
+		logger = discard
+	}
+	if opts.Template == "" {
+		opts.Template = DefaultAlertTemplate
+	}
+	tmpl, err := template.New("alert").Parse(opts.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert template: %w", err)
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = defaultAlertCooldown
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultSendTimeout
+	}
+	if opts.UpstreamErrorThreshold <= 0 {
+		opts.UpstreamErrorThreshold = defaultAlertBurstThreshold
+	}
+	if opts.UpstreamErrorWindow <= 0 {
+		opts.UpstreamErrorWindow = defaultAlertBurstWindow
+	}
+
+	a := &Alerter{
+		options:    opts,
+		template:   tmpl,
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		logger:     logger,
+		bursts:     newBurstDetector(opts.UpstreamErrorThreshold, opts.UpstreamErrorWindow),
+		now:        time.Now,
+		states:     make(map[string]*alertState),
+		alerts:     make(chan *Alert, alertQueueSize),
+		done:       make(chan struct{}),
+	}
+	if len(opts.Kinds) > 0 {
+		a.kinds = make(map[string]bool, len(opts.Kinds))
+		for _, kind := range opts.Kinds {
+			a.kinds[kind] = true
+		}
+	}
+	a.host, _ = os.Hostname()
+
+	go a.run()
+	return a, nil
+}
+
+// Enabled reports whether alerts of a kind are sent
+func (a *Alerter) Enabled(kind string) bool {
+	return a != nil && (a.kinds == nil || a.kinds[kind])
+}
+
+// Send queues an alert unless its kind is disabled or an alert of the same
+// kind and key was sent within the cooldown, and reports whether it was
+// queued. The message is rendered from the template.
+func (a *Alerter) Send(alert Alert) bool {
+	if !a.Enabled(alert.Kind) {
+		return false
+	}
+	if alert.Time.IsZero() {
+		alert.Time = a.now()
+	}
+	if alert.Severity == "" {
+		alert.Severity = LevelWarning
+	}
+	alert.Host = a.host
+
+	a.stateMu.Lock()
+	id := alert.Kind + "\x00" + alert.Key
+	state, ok := a.states[id]
+	if ok && alert.Time.Sub(state.sent) < a.options.Cooldown {
+		state.suppressed++
+		a.stateMu.Unlock()
+		return false
+	}
+	if !ok {
+		state = &alertState{}
+		a.states[id] = state
+	}
+	alert.Suppressed = state.suppressed
+	state.sent, state.suppressed = alert.Time, 0
+	a.stateMu.Unlock()
+
+	var message bytes.Buffer
+	if err := a.template.Execute(&message, alert); err != nil {
+		a.logger.WithError(err).WithField("kind", alert.Kind).Warn("Failed to render alert template, sending the summary")
+		message.Reset()
+		message.WriteString(alert.Summary)
+	}
+	alert.Message = message.String()
+	return a.enqueue(&alert)
+}
+
+// UpstreamError records a 5xx response of the Portal64 API and raises an
+// upstream_errors alert when UpstreamErrorThreshold responses arrive within
+// UpstreamErrorWindow
+func (a *Alerter) UpstreamError(method, target string, status int) {
+	if !a.Enabled(AlertUpstreamErrors) {
+		return
+	}
+	count, burst := a.bursts.record(a.now())
+	if !burst {
+		return
+	}
+	a.Send(Alert{
+		Kind:     AlertUpstreamErrors,
+		Severity: LevelError,
+		Summary:  fmt.Sprintf("Portal64 API returned %d server errors within %s", count, a.options.UpstreamErrorWindow),
+		Fields:   map[string]string{"last_status": fmt.Sprint(status), "last_request": method + " " + target},
+	})
+}
+
+// Close stops the alerter after sending queued alerts, waiting at most
+// until ctx is done
+func (a *Alerter) Close(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.alerts)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue queues an alert for sending, dropping it if the queue is full or
+// the alerter is closed
+func (a *Alerter) enqueue(alert *Alert) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return false
+	}
+	select {
+	case a.alerts <- alert:
+		return true
+	default:
+		a.logger.WithField("kind", alert.Kind).Warn("Alert queue full, dropping alert")
+		return false
+	}
+}
+
+func (a *Alerter) run() {
+	defer close(a.done)
+	for alert := range a.alerts {
+		if a.options.SlackWebhookURL != "" {
+			if err := a.post(a.options.SlackWebhookURL, map[string]string{"text": alert.Message}); err != nil {
+				a.logger.WithError(err).WithField("kind", alert.Kind).Warn("Failed to send alert to Slack")
+			}
+		}
+		if a.options.WebhookURL != "" {
+			if err := a.post(a.options.WebhookURL, alert); err != nil {
+				a.logger.WithError(err).WithField("kind", alert.Kind).Warn("Failed to send alert to webhook")
+			}
+		}
+	}
+}
+
+// post sends a payload as JSON to a webhook
+func (a *Alerter) post(endpoint string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		// The URL of a webhook is its secret
+		return fmt.Errorf("request failed: %s", strings.ReplaceAll(err.Error(), endpoint, "<webhook>"))
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
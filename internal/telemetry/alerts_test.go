@@ -0,0 +1,106 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlerter_SlackAndWebhook(t *testing.T) {
+	slack, slackReceived := newReceiver(t)
+	webhook, webhookReceived := newReceiver(t)
+
+	alerter, err := NewAlerter(AlertOptions{
+		SlackWebhookURL: slack.URL + "/services/T0/B0/x",
+		WebhookURL:      webhook.URL + "/alerts",
+		Template:        `{{.Kind}}: {{.Summary}} ({{index .Fields "upstream"}})`,
+	}, nil)
+	require.NoError(t, err)
+	assert.True(t, alerter.Send(Alert{
+		Kind:     AlertBreakerOpen,
+		Key:      "https://portal64.de",
+		Severity: LevelError,
+		Summary:  "Circuit breaker opened",
+		Fields:   map[string]string{"upstream": "https://portal64.de"},
+	}))
+	require.NoError(t, alerter.Close(context.Background()))
+
+	requests := slackReceived()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/services/T0/B0/x", requests[0].path)
+	assert.Equal(t, "application/json", requests[0].contentType)
+	assert.JSONEq(t, `{"text": "breaker_open: Circuit breaker opened (https://portal64.de)"}`, string(requests[0].body))
+
+	requests = webhookReceived()
+	require.Len(t, requests, 1)
+	var alert Alert
+	require.NoError(t, json.Unmarshal(requests[0].body, &alert))
+	assert.Equal(t, AlertBreakerOpen, alert.Kind)
+	assert.Equal(t, LevelError, alert.Severity)
+	assert.Equal(t, "breaker_open: Circuit breaker opened (https://portal64.de)", alert.Message)
+	assert.False(t, alert.Time.IsZero())
+}
+
+func TestAlerter_Cooldown(t *testing.T) {
+	server, received := newReceiver(t)
+
+	alerter, err := NewAlerter(AlertOptions{WebhookURL: server.URL, Cooldown: time.Hour, Kinds: []string{AlertSLOBurn}}, nil)
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	alerter.now = func() time.Time { return now }
+
+	burn := Alert{Kind: AlertSLOBurn, Key: "search_players/availability", Summary: "Error budget burning"}
+	assert.True(t, alerter.Send(burn))
+	assert.False(t, alerter.Send(burn), "repeats within the cooldown are suppressed")
+	assert.False(t, alerter.Send(burn))
+	assert.True(t, alerter.Send(Alert{Kind: AlertSLOBurn, Key: "search_players/latency", Summary: "Error budget burning"}), "other keys are alerted")
+	assert.False(t, alerter.Send(Alert{Kind: AlertBreakerOpen, Summary: "Circuit breaker opened"}), "disabled kinds are not sent")
+
+	now = now.Add(time.Hour)
+	assert.True(t, alerter.Send(burn))
+	require.NoError(t, alerter.Close(context.Background()))
+	assert.False(t, alerter.Send(Alert{Kind: AlertSLOBurn, Key: "late"}), "alerts after Close are dropped")
+
+	requests := received()
+	require.Len(t, requests, 3)
+	var alert Alert
+	require.NoError(t, json.Unmarshal(requests[2].body, &alert))
+	assert.Equal(t, 2, alert.Suppressed)
+	assert.Equal(t, LevelWarning, alert.Severity)
+	assert.Equal(t, "[warning] Error budget burning (2 repeats suppressed)", alert.Message)
+}
+
+func TestAlerter_UpstreamErrors(t *testing.T) {
+	server, received := newReceiver(t)
+
+	alerter, err := NewAlerter(AlertOptions{WebhookURL: server.URL, UpstreamErrorThreshold: 3, UpstreamErrorWindow: time.Minute}, nil)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		alerter.UpstreamError(http.MethodGet, "http://portal64/api/v1/clubs", http.StatusBadGateway)
+	}
+	require.NoError(t, alerter.Close(context.Background()))
+
+	requests := received()
+	require.Len(t, requests, 1)
+	var alert Alert
+	require.NoError(t, json.Unmarshal(requests[0].body, &alert))
+	assert.Equal(t, AlertUpstreamErrors, alert.Kind)
+	assert.Equal(t, "Portal64 API returned 3 server errors within 1m0s", alert.Summary)
+	assert.Equal(t, "502", alert.Fields["last_status"])
+	assert.Contains(t, alert.Message, "last_request: GET http://portal64/api/v1/clubs")
+}
+
+func TestNewAlerter_Invalid(t *testing.T) {
+	_, err := NewAlerter(AlertOptions{}, nil)
+	assert.Error(t, err)
+	_, err = NewAlerter(AlertOptions{WebhookURL: "http://localhost", Template: "{{.Summary"}, nil)
+	assert.Error(t, err)
+
+	var alerter *Alerter
+	assert.False(t, alerter.Enabled(AlertSLOBurn), "a nil alerter is disabled")
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package telemetry
+
+import "time"
+
+// processCPUTime reports that the CPU time of the process is unavailable
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,367 @@
+// Package telemetry reports errors of the server to an external error
+// tracker, samples system metrics of the process, tracks service level
+// objectives and sends alerts to Slack or webhooks. Events are sent in the
+// Sentry envelope format when a Sentry DSN is configured, or as plain JSON
+// to a generic endpoint.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Event levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelFatal   = "fatal"
+)
+
+const (
+	// eventQueueSize bounds the events waiting to be sent; further events
+	// are dropped so that a failing tracker cannot block the server
+	eventQueueSize = 100
+	// defaultSendTimeout bounds sending a single event
+	defaultSendTimeout = 5 * time.Second
+	// Defaults of the upstream 5xx burst detection
+	defaultBurstThreshold = 5
+	defaultBurstWindow    = time.Minute
+	// clientName identifies the reporter to Sentry
+	clientName = "portal64gomcp"
+)
+
+// Options configures an ErrorTracker. Either DSN or Endpoint must be set.
+type Options struct {
+	DSN         string // Sentry DSN, https://<key>@<host>/<project>
+	Endpoint    string // Generic endpoint receiving events as JSON
+	Environment string
+	Release     string        // Version the events are tagged with
+	Timeout     time.Duration // Timeout of sending an event
+	// BurstThreshold upstream 5xx responses within BurstWindow are
+	// reported as a single event
+	BurstThreshold int
+	BurstWindow    time.Duration
+}
+
+// Event is an error event. The JSON encoding is a subset of the Sentry
+// event payload.
+type Event struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Logger      string                 `json:"logger"`
+	Release     string                 `json:"release,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Message     string                 `json:"message"`
+	Exception   *eventException        `json:"exception,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+type eventException struct {
+	Values []exceptionValue `json:"values"`
+}
+
+type exceptionValue struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ErrorTracker sends error events to an error tracker in the background
+type ErrorTracker struct {
+	options    Options
+	endpoint   string
+	authHeader string
+	serverName string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+	bursts     *burstDetector
+
+	mu     sync.RWMutex // guards closing events
+	closed bool
+	events chan *Event
+	done   chan struct{}
+}
+
+// NewErrorTracker creates an error tracker and starts sending events. A nil
+// logger discards log output.
+func NewErrorTracker(opts Options, logger logrus.FieldLogger) (*ErrorTracker, error) {
+	if logger == nil {
+		discard := logrus.New()
+		discard.SetOutput(io.Discard)
+		logger = discard
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultSendTimeout
+	}
+	if opts.BurstThreshold <= 0 {
+		opts.BurstThreshold = defaultBurstThreshold
+	}
+	if opts.BurstWindow <= 0 {
+		opts.BurstWindow = defaultBurstWindow
+	}
+
+	t := &ErrorTracker{
+		options:    opts,
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		logger:     logger,
+		bursts:     newBurstDetector(opts.BurstThreshold, opts.BurstWindow),
+		events:     make(chan *Event, eventQueueSize),
+		done:       make(chan struct{}),
+	}
+	t.serverName, _ = os.Hostname()
+
+	switch {
+	case opts.DSN != "":
+		endpoint, auth, err := parseDSN(opts.DSN, opts.Release)
+		if err != nil {
+			return nil, err
+		}
+		t.endpoint, t.authHeader = endpoint, auth
+	case opts.Endpoint != "":
+		t.endpoint = opts.Endpoint
+	default:
+		return nil, fmt.Errorf("error tracker requires a DSN or an endpoint")
+	}
+
+	go t.run()
+	return t, nil
+}
+
+// parseDSN returns the envelope endpoint and auth header of a Sentry DSN
+func parseDSN(dsn, release string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.Host == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN")
+	}
+	key := u.User.Username()
+	path := strings.Trim(u.Path, "/")
+	project := path
+	prefix := ""
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		prefix, project = "/"+path[:i], path[i+1:]
+	}
+	if key == "" || project == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN: key and project ID are required")
+	}
+
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, project)
+	client := clientName
+	if release != "" {
+		client += "/" + release
+	}
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", client, key)
+	return endpoint, auth, nil
+}
+
+// ReportPanic reports a recovered panic with its stack trace
+func (t *ErrorTracker) ReportPanic(recovered interface{}, stack []byte, tags map[string]string) {
+	event := t.newEvent(LevelFatal, fmt.Sprintf("panic: %v", recovered), tags)
+	event.Exception = &eventException{Values: []exceptionValue{{Type: "panic", Value: fmt.Sprint(recovered)}}}
+	event.Extra = map[string]interface{}{"stack": string(stack)}
+	t.enqueue(event)
+}
+
+// ReportError reports an error at the given level
+func (t *ErrorTracker) ReportError(err error, level string, tags map[string]string) {
+	event := t.newEvent(level, err.Error(), tags)
+	event.Exception = &eventException{Values: []exceptionValue{{Type: fmt.Sprintf("%T", err), Value: err.Error()}}}
+	t.enqueue(event)
+}
+
+// ReportUpstreamError records a 5xx response of the Portal64 API. Single
+// failures are not reported, only bursts of BurstThreshold responses within
+// BurstWindow, at most once per window.
+func (t *ErrorTracker) ReportUpstreamError(method, target string, status int) {
+	count, burst := t.bursts.record(time.Now())
+	if !burst {
+		return
+	}
+	event := t.newEvent(LevelError,
+		fmt.Sprintf("Portal64 API returned %d server errors within %s", count, t.options.BurstWindow),
+		map[string]string{"component": "upstream", "status": fmt.Sprint(status)})
+	event.Extra = map[string]interface{}{"last_method": method, "last_url": target}
+	t.enqueue(event)
+}
+
+// Close stops the tracker after sending queued events, waiting at most
+// until ctx is done
+func (t *ErrorTracker) Close(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.events)
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *ErrorTracker) newEvent(level, message string, tags map[string]string) *Event {
+	event := &Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Level:       level,
+		Platform:    "go",
+		Logger:      clientName,
+		Release:     t.options.Release,
+		Environment: t.options.Environment,
+		ServerName:  t.serverName,
+		Message:     message,
+		Tags:        make(map[string]string, len(tags)),
+	}
+	for k, v := range tags {
+		event.Tags[k] = v
+	}
+	return event
+}
+
+// enqueue queues an event for sending, dropping it if the queue is full
+// or the tracker is closed
+func (t *ErrorTracker) enqueue(event *Event) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return
+	}
+	select {
+	case t.events <- event:
+	default:
+		t.logger.WithField("event_id", event.EventID).Warn("Error tracker queue full, dropping event")
+	}
+}
+
+func (t *ErrorTracker) run() {
+	defer close(t.done)
+	for event := range t.events {
+		if err := t.send(event); err != nil {
+			t.logger.WithError(err).WithField("event_id", event.EventID).Warn("Failed to send error event")
+		}
+	}
+}
+
+// send posts a single event to the tracker
+func (t *ErrorTracker) send(event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	body := payload
+	contentType := "application/json"
+	if t.authHeader != "" {
+		body, err = envelope(event, payload, t.options.DSN)
+		if err != nil {
+			return err
+		}
+		contentType = "application/x-sentry-envelope"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if t.authHeader != "" {
+		req.Header.Set("X-Sentry-Auth", t.authHeader)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("error tracker returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// envelope wraps an event payload in a Sentry envelope
+func envelope(event *Event, payload []byte, dsn string) ([]byte, error) {
+	header, err := json.Marshal(map[string]interface{}{
+		"event_id": event.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+		"dsn":      dsn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	item, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(payload)})
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.WriteByte('\n')
+	buf.Write(item)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// newEventID returns a random 32 character hex event ID
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return strings.Repeat("0", 32)
+	}
+	return hex.EncodeToString(id)
+}
+
+// burstDetector detects bursts of events within a sliding window
+type burstDetector struct {
+	threshold int
+	window    time.Duration
+
+	mu       sync.Mutex
+	times    []time.Time
+	reported time.Time
+}
+
+func newBurstDetector(threshold int, window time.Duration) *burstDetector {
+	return &burstDetector{threshold: threshold, window: window}
+}
+
+// record adds an event at now and reports the number of events in the
+// window and whether a burst should be reported
+func (b *burstDetector) record(now time.Time) (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cutoff := now.Add(-b.window)
+	kept := b.times[:0]
+	for _, t := range b.times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	b.times = append(kept, now)
+
+	if len(b.times) < b.threshold || (!b.reported.IsZero() && now.Sub(b.reported) < b.window) {
+		return len(b.times), false
+	}
+	b.reported = now
+	return len(b.times), true
+}
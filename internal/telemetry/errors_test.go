@@ -0,0 +1,157 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	path        string
+	auth        string
+	contentType string
+	body        []byte
+}
+
+func newReceiver(t *testing.T) (*httptest.Server, func() []receivedRequest) {
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedRequest{r.URL.Path, r.Header.Get("X-Sentry-Auth"), r.Header.Get("Content-Type"), body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest(nil), received...)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	endpoint, auth, err := parseDSN("https://abc123@sentry.example.org/prefix/42", "v1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sentry.example.org/prefix/api/42/envelope/", endpoint)
+	assert.Equal(t, "Sentry sentry_version=7, sentry_client=portal64gomcp/v1.2.0, sentry_key=abc123", auth)
+
+	endpoint, _, err = parseDSN("http://key@localhost:9000/7", "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000/api/7/envelope/", endpoint)
+
+	for _, dsn := range []string{"sentry.example.org/42", "https://sentry.example.org/42", "https://key@sentry.example.org/"} {
+		_, _, err := parseDSN(dsn, "")
+		assert.Error(t, err, dsn)
+	}
+}
+
+func TestErrorTracker_SentryEnvelope(t *testing.T) {
+	server, received := newReceiver(t)
+	dsn := "http://public@" + server.Listener.Addr().String() + "/3"
+
+	tracker, err := NewErrorTracker(Options{DSN: dsn, Environment: "test", Release: "v1.0.0"}, nil)
+	require.NoError(t, err)
+	tracker.ReportPanic("boom", []byte("goroutine 1 [running]"), map[string]string{"tool": "search_players"})
+	require.NoError(t, tracker.Close(context.Background()))
+
+	requests := received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/api/3/envelope/", requests[0].path)
+	assert.Contains(t, requests[0].auth, "sentry_key=public")
+	assert.Equal(t, "application/x-sentry-envelope", requests[0].contentType)
+
+	lines := bytes.Split(bytes.TrimSpace(requests[0].body), []byte("\n"))
+	require.Len(t, lines, 3)
+	var header, item map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &header))
+	require.NoError(t, json.Unmarshal(lines[1], &item))
+	assert.Equal(t, dsn, header["dsn"])
+	assert.Equal(t, "event", item["type"])
+	assert.EqualValues(t, len(lines[2]), item["length"])
+
+	var event Event
+	require.NoError(t, json.Unmarshal(lines[2], &event))
+	assert.Equal(t, header["event_id"], event.EventID)
+	assert.Equal(t, LevelFatal, event.Level)
+	assert.Equal(t, "v1.0.0", event.Release)
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "search_players", event.Tags["tool"])
+	assert.Equal(t, "goroutine 1 [running]", event.Extra["stack"])
+}
+
+func TestErrorTracker_GenericEndpoint(t *testing.T) {
+	server, received := newReceiver(t)
+
+	tracker, err := NewErrorTracker(Options{Endpoint: server.URL + "/events"}, nil)
+	require.NoError(t, err)
+	tracker.ReportError(errors.New("upstream unavailable"), LevelWarning, nil)
+	require.NoError(t, tracker.Close(context.Background()))
+
+	// Events after Close are dropped
+	tracker.ReportError(errors.New("late"), LevelError, nil)
+
+	requests := received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/events", requests[0].path)
+	assert.Empty(t, requests[0].auth)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(requests[0].body, &event))
+	assert.Equal(t, "upstream unavailable", event.Message)
+	assert.Equal(t, LevelWarning, event.Level)
+	assert.Len(t, event.EventID, 32)
+}
+
+func TestErrorTracker_UpstreamBurst(t *testing.T) {
+	server, received := newReceiver(t)
+
+	tracker, err := NewErrorTracker(Options{Endpoint: server.URL, BurstThreshold: 3, BurstWindow: time.Hour}, nil)
+	require.NoError(t, err)
+	for i := 0; i < 7; i++ {
+		tracker.ReportUpstreamError(http.MethodGet, "http://portal64/api/v1/clubs", http.StatusBadGateway)
+	}
+	require.NoError(t, tracker.Close(context.Background()))
+
+	requests := received()
+	require.Len(t, requests, 1, "a burst is reported once per window")
+	var event Event
+	require.NoError(t, json.Unmarshal(requests[0].body, &event))
+	assert.Equal(t, "upstream", event.Tags["component"])
+	assert.Equal(t, "502", event.Tags["status"])
+}
+
+func TestBurstDetector(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := newBurstDetector(3, time.Minute)
+
+	_, burst := b.record(start)
+	assert.False(t, burst)
+	_, burst = b.record(start.Add(70 * time.Second))
+	assert.False(t, burst)
+	_, burst = b.record(start.Add(80 * time.Second))
+	assert.False(t, burst, "the first failure left the window")
+
+	count, burst := b.record(start.Add(90 * time.Second))
+	assert.True(t, burst)
+	assert.Equal(t, 3, count)
+
+	_, burst = b.record(start.Add(100 * time.Second))
+	assert.False(t, burst, "reported at most once per window")
+
+	for _, offset := range []time.Duration{160, 170, 175} {
+		_, burst = b.record(start.Add(offset * time.Second))
+	}
+	assert.True(t, burst, "a burst in the next window is reported again")
+}
@@ -0,0 +1,321 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sloBuckets is the number of buckets the rolling window of an SLO tracker
+// is divided into
+const sloBuckets = 288
+
+// Objectives of a tool
+const (
+	ObjectiveAvailability = "availability" // Calls without a failure
+	ObjectiveLatency      = "latency"      // Calls faster than the latency threshold
+)
+
+// SLOTarget holds the objectives of a tool. A zero target disables its
+// objective.
+type SLOTarget struct {
+	Availability  float64       // Share of calls that must not fail, e.g. 0.99
+	Latency       time.Duration // Calls slower than this count against the latency objective
+	LatencyTarget float64       // Share of calls that must be faster than Latency
+}
+
+// SLOOptions configures an SLO tracker
+type SLOOptions struct {
+	Window  time.Duration        // Rolling window of the error budgets
+	Default SLOTarget            // Objectives of tools without a target of their own
+	Targets map[string]SLOTarget // By tool name
+	// AlertWindow is the recent part of the window whose burn rate is
+	// checked for alerts
+	AlertWindow time.Duration
+	// BurnRateThreshold alerts when the error budget is spent this many
+	// times faster than it lasts for the window
+	BurnRateThreshold float64
+	// AlertMinCalls suppresses alerts of tools with fewer calls in the
+	// alert window, where single failures make the burn rate meaningless
+	AlertMinCalls int
+}
+
+// ObjectiveStatus reports an objective of a tool over the rolling window
+type ObjectiveStatus struct {
+	Objective        string  `json:"objective"` // ObjectiveAvailability or ObjectiveLatency
+	Target           float64 `json:"target"`
+	LatencyThreshold float64 `json:"latency_threshold_ms,omitempty"`
+	Calls            int64   `json:"calls"`
+	Bad              int64   `json:"bad"`              // Failed or slow calls
+	Attained         float64 `json:"attained"`         // Share of good calls, 1 without calls
+	BudgetRemaining  float64 `json:"budget_remaining"` // Share of the error budget left, negative when overspent
+	// BurnRate is how many times faster than sustainable the error budget
+	// was spent in the alert window
+	BurnRate float64 `json:"burn_rate"`
+	Alerting bool    `json:"alerting"`
+}
+
+// ToolSLO reports the objectives of a tool
+type ToolSLO struct {
+	Tool       string            `json:"tool"`
+	Objectives []ObjectiveStatus `json:"objectives"`
+}
+
+// SLOReport reports the objectives of all tools called within the window
+type SLOReport struct {
+	WindowSeconds      float64   `json:"window_seconds"`
+	AlertWindowSeconds float64   `json:"alert_window_seconds"`
+	BurnRateThreshold  float64   `json:"burn_rate_threshold"`
+	Tools              []ToolSLO `json:"tools"`
+}
+
+// sloBucket counts the calls of a tool in a part of the window
+type sloBucket struct {
+	index  int64 // Number of the bucket since the epoch
+	calls  int64
+	failed int64
+	slow   int64
+}
+
+// sloSeries holds the buckets of a tool in a ring
+type sloSeries struct {
+	buckets  []sloBucket
+	alerting map[string]bool // By objective
+}
+
+// SLOTracker records the calls of tools and computes the error budgets of
+// their availability and latency objectives over a rolling window
+type SLOTracker struct {
+	opts   SLOOptions
+	width  time.Duration // Of a bucket
+	now    func() time.Time
+	logger *logrus.Logger
+	// onAlert is called when the burn rate of an objective reaches the
+	// threshold
+	onAlert func(tool string, status ObjectiveStatus)
+
+	mu    sync.Mutex
+	tools map[string]*sloSeries
+}
+
+// NewSLOTracker creates an SLO tracker logging burn rate alerts to logger,
+// which may be nil
+func NewSLOTracker(opts SLOOptions, logger *logrus.Logger) *SLOTracker {
+	return &SLOTracker{
+		opts:   opts,
+		width:  max(opts.Window/sloBuckets, time.Second),
+		now:    time.Now,
+		logger: logger,
+		tools:  make(map[string]*sloSeries),
+	}
+}
+
+// OnAlert sets a function called by Check when the burn rate of an
+// objective reaches the threshold, e.g. to notify operators. It must be set
+// before Run is started.
+func (t *SLOTracker) OnAlert(hook func(tool string, status ObjectiveStatus)) {
+	t.onAlert = hook
+}
+
+// target returns the objectives of a tool. Unset fields of a tool's target
+// default to those of the default target.
+func (t *SLOTracker) target(tool string) SLOTarget {
+	target, ok := t.opts.Targets[tool]
+	if !ok {
+		return t.opts.Default
+	}
+	if target.Availability == 0 {
+		target.Availability = t.opts.Default.Availability
+	}
+	if target.Latency == 0 {
+		target.Latency = t.opts.Default.Latency
+	}
+	if target.LatencyTarget == 0 {
+		target.LatencyTarget = t.opts.Default.LatencyTarget
+	}
+	return target
+}
+
+// Record adds a call of a tool
+func (t *SLOTracker) Record(tool string, latency time.Duration, failed bool) {
+	target := t.target(tool)
+	index := t.now().UnixNano() / int64(t.width)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	series, ok := t.tools[tool]
+	if !ok {
+		series = &sloSeries{buckets: make([]sloBucket, sloBuckets), alerting: make(map[string]bool)}
+		t.tools[tool] = series
+	}
+	bucket := &series.buckets[index%sloBuckets]
+	if bucket.index != index {
+		*bucket = sloBucket{index: index}
+	}
+	bucket.calls++
+	if failed {
+		bucket.failed++
+	}
+	if target.Latency > 0 && latency > target.Latency {
+		bucket.slow++
+	}
+}
+
+// Report returns the objectives of the tools called within the window,
+// ordered by tool name
+func (t *SLOTracker) Report() SLOReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.report()
+}
+
+// report computes the report. It must be called with mu held.
+func (t *SLOTracker) report() SLOReport {
+	report := SLOReport{
+		WindowSeconds:      t.opts.Window.Seconds(),
+		AlertWindowSeconds: t.opts.AlertWindow.Seconds(),
+		BurnRateThreshold:  t.opts.BurnRateThreshold,
+		Tools:              []ToolSLO{},
+	}
+	current := t.now().UnixNano() / int64(t.width)
+	alertBuckets := int64(math.Ceil(float64(t.opts.AlertWindow) / float64(t.width)))
+
+	for tool, series := range t.tools {
+		var window, recent sloBucket
+		for _, bucket := range series.buckets {
+			age := current - bucket.index
+			if age < 0 || age >= sloBuckets {
+				continue
+			}
+			window.calls, window.failed, window.slow = window.calls+bucket.calls, window.failed+bucket.failed, window.slow+bucket.slow
+			if age < alertBuckets {
+				recent.calls, recent.failed, recent.slow = recent.calls+bucket.calls, recent.failed+bucket.failed, recent.slow+bucket.slow
+			}
+		}
+		if window.calls == 0 {
+			continue
+		}
+
+		target := t.target(tool)
+		slo := ToolSLO{Tool: tool, Objectives: []ObjectiveStatus{}}
+		if target.Availability > 0 {
+			slo.Objectives = append(slo.Objectives, t.objective(ObjectiveAvailability, target.Availability, window.calls, window.failed, recent.calls, recent.failed))
+		}
+		if target.Latency > 0 && target.LatencyTarget > 0 {
+			status := t.objective(ObjectiveLatency, target.LatencyTarget, window.calls, window.slow, recent.calls, recent.slow)
+			status.LatencyThreshold = float64(target.Latency) / float64(time.Millisecond)
+			slo.Objectives = append(slo.Objectives, status)
+		}
+		report.Tools = append(report.Tools, slo)
+	}
+	sort.Slice(report.Tools, func(i, j int) bool { return report.Tools[i].Tool < report.Tools[j].Tool })
+	return report
+}
+
+// objective computes the status of an objective from the calls and bad
+// calls in the window and in the alert window
+func (t *SLOTracker) objective(name string, target float64, calls, bad, recentCalls, recentBad int64) ObjectiveStatus {
+	status := ObjectiveStatus{Objective: name, Target: target, Calls: calls, Bad: bad, Attained: 1, BudgetRemaining: 1}
+	budget := 1 - target
+	if calls > 0 {
+		status.Attained = 1 - float64(bad)/float64(calls)
+	}
+	if budget > 0 {
+		status.BudgetRemaining = 1 - float64(bad)/(float64(calls)*budget)
+		if recentCalls > 0 {
+			status.BurnRate = float64(recentBad) / float64(recentCalls) / budget
+		}
+	}
+	status.Alerting = t.opts.BurnRateThreshold > 0 && recentCalls >= int64(t.opts.AlertMinCalls) && status.BurnRate >= t.opts.BurnRateThreshold
+	return status
+}
+
+// Check logs an alert for every objective whose burn rate reaches the
+// threshold, once until it falls below again, and returns the report
+func (t *SLOTracker) Check() SLOReport {
+	var alerts []ToolSLO
+	defer func() {
+		// Outside the lock, the hook may be slow
+		for _, alert := range alerts {
+			t.onAlert(alert.Tool, alert.Objectives[0])
+		}
+	}()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	report := t.report()
+	for _, slo := range report.Tools {
+		series := t.tools[slo.Tool]
+		for _, status := range slo.Objectives {
+			if status.Alerting == series.alerting[status.Objective] {
+				continue
+			}
+			series.alerting[status.Objective] = status.Alerting
+			if status.Alerting && t.onAlert != nil {
+				alerts = append(alerts, ToolSLO{Tool: slo.Tool, Objectives: []ObjectiveStatus{status}})
+			}
+			if t.logger == nil {
+				continue
+			}
+			fields := logrus.Fields{
+				"tool":             slo.Tool,
+				"objective":        status.Objective,
+				"burn_rate":        math.Round(status.BurnRate*100) / 100,
+				"budget_remaining": math.Round(status.BudgetRemaining*1000) / 1000,
+			}
+			if status.Alerting {
+				t.logger.WithFields(fields).Warnf("Error budget of %s burning %.1f times faster than sustainable", slo.Tool, status.BurnRate)
+			} else {
+				t.logger.WithFields(fields).Info("Error budget burn rate back below the alert threshold")
+			}
+		}
+	}
+	return report
+}
+
+// Run checks the burn rates once per bucket until the context is done
+func (t *SLOTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.width)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Check()
+		}
+	}
+}
+
+// WriteSLOPrometheus writes the objectives of a report in the Prometheus
+// text exposition format
+func WriteSLOPrometheus(w io.Writer, report SLOReport) error {
+	metrics := []struct {
+		name, help string
+		value      func(ObjectiveStatus) float64
+	}{
+		{"portal64_slo_target", "Target share of good calls of the objective.", func(s ObjectiveStatus) float64 { return s.Target }},
+		{"portal64_slo_attained", "Share of good calls of the objective in the window.", func(s ObjectiveStatus) float64 { return s.Attained }},
+		{"portal64_slo_error_budget_remaining", "Share of the error budget left in the window, negative when overspent.", func(s ObjectiveStatus) float64 { return s.BudgetRemaining }},
+		{"portal64_slo_burn_rate", "Rate the error budget was spent at in the alert window, 1 lasts exactly for the window.", func(s ObjectiveStatus) float64 { return s.BurnRate }},
+	}
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name); err != nil {
+			return err
+		}
+		for _, slo := range report.Tools {
+			for _, status := range slo.Objectives {
+				if _, err := fmt.Fprintf(w, "%s{tool=%q,objective=%q} %g\n", metric.name, slo.Tool, status.Objective, metric.value(status)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
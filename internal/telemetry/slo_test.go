@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLOTracker_Report(t *testing.T) {
+	tracker := NewSLOTracker(SLOOptions{
+		Window:            24 * time.Hour,
+		Default:           SLOTarget{Availability: 0.99, Latency: time.Second, LatencyTarget: 0.95},
+		Targets:           map[string]SLOTarget{"search_players": {Availability: 0.9}},
+		AlertWindow:       time.Hour,
+		BurnRateThreshold: 5,
+		AlertMinCalls:     10,
+	}, nil)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	// 100 calls a day ago, 2 failed, then 20 calls in the last hour, 2 failed
+	// and 4 slow
+	now = now.Add(-23 * time.Hour)
+	for i := 0; i < 100; i++ {
+		tracker.Record("get_player_profile", 10*time.Millisecond, i < 2)
+	}
+	tracker.Record("search_players", 10*time.Millisecond, false)
+	now = now.Add(23 * time.Hour)
+	for i := 0; i < 20; i++ {
+		tracker.Record("get_player_profile", time.Duration(i)*100*time.Millisecond, i >= 18)
+	}
+
+	report := tracker.Report()
+	assert.Equal(t, 86400.0, report.WindowSeconds)
+	require.Len(t, report.Tools, 2)
+	profile := report.Tools[0]
+	assert.Equal(t, "get_player_profile", profile.Tool)
+	require.Len(t, profile.Objectives, 2)
+
+	availability := profile.Objectives[0]
+	assert.Equal(t, ObjectiveAvailability, availability.Objective)
+	assert.Equal(t, int64(120), availability.Calls)
+	assert.Equal(t, int64(4), availability.Bad)
+	assert.InDelta(t, 1-4.0/120, availability.Attained, 1e-9)
+	assert.InDelta(t, 1-4/(120*0.01), availability.BudgetRemaining, 1e-9, "the budget is overspent")
+	assert.InDelta(t, 2.0/20/0.01, availability.BurnRate, 1e-9)
+	assert.True(t, availability.Alerting)
+
+	latency := profile.Objectives[1]
+	assert.Equal(t, ObjectiveLatency, latency.Objective)
+	assert.Equal(t, 1000.0, latency.LatencyThreshold)
+	assert.Equal(t, int64(9), latency.Bad, "calls of 1.1s to 1.9s are slow")
+	assert.InDelta(t, 9.0/20/0.05, latency.BurnRate, 1e-9)
+
+	players := report.Tools[1]
+	assert.Equal(t, 0.9, players.Objectives[0].Target, "tool targets override the default")
+	assert.Equal(t, 0.95, players.Objectives[1].Target, "unset fields of tool targets default")
+	assert.False(t, players.Objectives[0].Alerting, "too few calls to alert")
+
+	// Calls leave the window
+	now = now.Add(2 * time.Hour)
+	report = tracker.Report()
+	require.Len(t, report.Tools, 1)
+	assert.Equal(t, int64(20), report.Tools[0].Objectives[0].Calls)
+	assert.Zero(t, report.Tools[0].Objectives[0].BurnRate, "no calls in the alert window")
+}
+
+func TestSLOTracker_Check(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	tracker := NewSLOTracker(SLOOptions{
+		Window:            time.Hour,
+		Default:           SLOTarget{Availability: 0.99},
+		AlertWindow:       5 * time.Minute,
+		BurnRateThreshold: 10,
+	}, logger)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	var alerts []string
+	tracker.OnAlert(func(tool string, status ObjectiveStatus) { alerts = append(alerts, tool+"/"+status.Objective) })
+
+	tracker.Record("get_club_profile", time.Millisecond, true)
+	tracker.Check()
+	tracker.Check()
+	require.Len(t, hook.Entries, 1, "alerts are logged once")
+	assert.Equal(t, []string{"get_club_profile/availability"}, alerts)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "get_club_profile", hook.LastEntry().Data["tool"])
+
+	for i := 0; i < 999; i++ {
+		tracker.Record("get_club_profile", time.Millisecond, false)
+	}
+	tracker.Check()
+	require.Len(t, hook.Entries, 2)
+	assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+	assert.Len(t, alerts, 1, "recoveries are not alerted")
+}
+
+func TestWriteSLOPrometheus(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteSLOPrometheus(&b, SLOReport{Tools: []ToolSLO{{
+		Tool:       "search_players",
+		Objectives: []ObjectiveStatus{{Objective: ObjectiveAvailability, Target: 0.99, Attained: 0.995, BudgetRemaining: 0.5, BurnRate: 2}},
+	}}}))
+	assert.Contains(t, b.String(), "# TYPE portal64_slo_burn_rate gauge\n")
+	assert.Contains(t, b.String(), `portal64_slo_error_budget_remaining{tool="search_players",objective="availability"} 0.5`)
+	assert.NoError(t, WriteSLOPrometheus(io.Discard, SLOReport{}))
+}
@@ -0,0 +1,220 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultSampleHistory is the number of samples kept by a sampler
+const defaultSampleHistory = 120
+
+// SystemMetrics is a sample of the resource usage of the server process
+type SystemMetrics struct {
+	Timestamp      time.Time `json:"timestamp"`
+	HeapAllocBytes uint64    `json:"heap_alloc_bytes"`
+	SysBytes       uint64    `json:"sys_bytes"` // Obtained from the OS by the Go runtime
+	Goroutines     int       `json:"goroutines"`
+	// CPUPercent is the CPU usage of the process since the previous sample,
+	// 100 per fully used core. It is -1 where the CPU time is unavailable.
+	CPUPercent float64 `json:"cpu_percent"`
+	// LogDirBytes is the size of the files in the log directory, -1 if no
+	// log directory is configured or it cannot be read
+	LogDirBytes int64 `json:"log_dir_bytes"`
+}
+
+// Thresholds are limits of the system metrics beyond which the server
+// reports itself as degraded. Zero disables a threshold.
+type Thresholds struct {
+	MaxHeapMB     int
+	MaxGoroutines int
+	MaxCPUPercent int
+	MaxLogDirMB   int
+}
+
+// Check returns a description of every threshold the sample exceeds
+func (t Thresholds) Check(m SystemMetrics) []string {
+	var exceeded []string
+	if t.MaxHeapMB > 0 && m.HeapAllocBytes > uint64(t.MaxHeapMB)<<20 {
+		exceeded = append(exceeded, fmt.Sprintf("heap of %d MB exceeds %d MB", m.HeapAllocBytes>>20, t.MaxHeapMB))
+	}
+	if t.MaxGoroutines > 0 && m.Goroutines > t.MaxGoroutines {
+		exceeded = append(exceeded, fmt.Sprintf("%d goroutines exceed %d", m.Goroutines, t.MaxGoroutines))
+	}
+	if t.MaxCPUPercent > 0 && m.CPUPercent > float64(t.MaxCPUPercent) {
+		exceeded = append(exceeded, fmt.Sprintf("CPU usage of %.0f%% exceeds %d%%", m.CPUPercent, t.MaxCPUPercent))
+	}
+	if t.MaxLogDirMB > 0 && m.LogDirBytes > int64(t.MaxLogDirMB)<<20 {
+		exceeded = append(exceeded, fmt.Sprintf("log directory of %d MB exceeds %d MB", m.LogDirBytes>>20, t.MaxLogDirMB))
+	}
+	return exceeded
+}
+
+// Sampler records system metrics of the process at an interval and keeps
+// the recent samples
+type Sampler struct {
+	logDir     string
+	thresholds Thresholds
+	logger     *logrus.Logger
+
+	mu       sync.Mutex
+	samples  []SystemMetrics // Ring buffer, next points at the oldest
+	next     int
+	full     bool
+	lastCPU  time.Duration
+	lastWall time.Time
+	exceeded bool
+}
+
+// NewSampler creates a sampler measuring the disk usage of logDir, which may
+// be empty, and logging when the thresholds are exceeded
+func NewSampler(logDir string, thresholds Thresholds, logger *logrus.Logger) *Sampler {
+	return &Sampler{
+		logDir:     logDir,
+		thresholds: thresholds,
+		logger:     logger,
+		samples:    make([]SystemMetrics, defaultSampleHistory),
+	}
+}
+
+// Sample records a sample of the system metrics and returns it
+func (s *Sampler) Sample() SystemMetrics {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	now := time.Now()
+	sample := SystemMetrics{
+		Timestamp:      now,
+		HeapAllocBytes: m.HeapAlloc,
+		SysBytes:       m.Sys,
+		Goroutines:     runtime.NumGoroutine(),
+		CPUPercent:     -1,
+		LogDirBytes:    dirSize(s.logDir),
+	}
+	cpu, cpuOK := processCPUTime()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cpuOK {
+		if wall := now.Sub(s.lastWall); !s.lastWall.IsZero() && wall > 0 {
+			sample.CPUPercent = float64(cpu-s.lastCPU) / float64(wall) * 100
+		}
+		s.lastCPU, s.lastWall = cpu, now
+	}
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % len(s.samples)
+	s.full = s.full || s.next == 0
+
+	// Log when the thresholds are first exceeded and when they are met again
+	exceeded := s.thresholds.Check(sample)
+	degraded := len(exceeded) > 0
+	if degraded != s.exceeded && s.logger != nil {
+		if degraded {
+			s.logger.WithField("exceeded", exceeded).Warn("System metrics exceed health thresholds")
+		} else {
+			s.logger.Info("System metrics back within health thresholds")
+		}
+	}
+	s.exceeded = degraded
+	return sample
+}
+
+// Latest returns the most recent sample, if any
+func (s *Sampler) Latest() (SystemMetrics, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full && s.next == 0 {
+		return SystemMetrics{}, false
+	}
+	return s.samples[(s.next+len(s.samples)-1)%len(s.samples)], true
+}
+
+// History returns the recorded samples, oldest first
+func (s *Sampler) History() []SystemMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full {
+		return append([]SystemMetrics(nil), s.samples[:s.next]...)
+	}
+	return append(append([]SystemMetrics(nil), s.samples[s.next:]...), s.samples[:s.next]...)
+}
+
+// Health returns the exceeded thresholds of the most recent sample
+func (s *Sampler) Health() []string {
+	latest, ok := s.Latest()
+	if !ok {
+		return nil
+	}
+	return s.thresholds.Check(latest)
+}
+
+// Run samples every interval until the context is done
+func (s *Sampler) Run(ctx context.Context, interval time.Duration) {
+	s.Sample()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sample()
+		}
+	}
+}
+
+// dirSize returns the total size of the files below a directory, -1 if the
+// directory is empty or cannot be read
+func dirSize(dir string) int64 {
+	if dir == "" {
+		return -1
+	}
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// WritePrometheus writes a sample in the Prometheus text exposition format
+func WritePrometheus(w io.Writer, m SystemMetrics) error {
+	metrics := []struct {
+		name, help string
+		value      float64
+	}{
+		{"portal64_heap_alloc_bytes", "Bytes of allocated heap objects.", float64(m.HeapAllocBytes)},
+		{"portal64_sys_bytes", "Bytes obtained from the OS by the Go runtime.", float64(m.SysBytes)},
+		{"portal64_goroutines", "Number of goroutines.", float64(m.Goroutines)},
+		{"portal64_cpu_percent", "CPU usage of the process since the previous sample, 100 per core.", m.CPUPercent},
+		{"portal64_log_dir_bytes", "Size of the files in the log directory.", float64(m.LogDirBytes)},
+	}
+	for _, metric := range metrics {
+		if metric.value < 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n",
+			metric.name, metric.help, metric.name, metric.name, metric.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholds_Check(t *testing.T) {
+	sample := SystemMetrics{HeapAllocBytes: 300 << 20, Goroutines: 50, CPUPercent: 150, LogDirBytes: 2 << 20}
+
+	assert.Empty(t, Thresholds{}.Check(sample))
+	assert.Empty(t, Thresholds{MaxHeapMB: 512, MaxGoroutines: 100, MaxCPUPercent: 200, MaxLogDirMB: 10}.Check(sample))
+	assert.Equal(t, []string{
+		"heap of 300 MB exceeds 256 MB",
+		"50 goroutines exceed 10",
+		"CPU usage of 150% exceeds 100%",
+		"log directory of 2 MB exceeds 1 MB",
+	}, Thresholds{MaxHeapMB: 256, MaxGoroutines: 10, MaxCPUPercent: 100, MaxLogDirMB: 1}.Check(sample))
+}
+
+func TestSampler(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.log"), make([]byte, 1000), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "archive"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "archive", "old.log"), make([]byte, 500), 0o644))
+
+	s := NewSampler(dir, Thresholds{MaxGoroutines: 1}, nil)
+	_, ok := s.Latest()
+	assert.False(t, ok)
+	assert.Nil(t, s.Health())
+
+	first := s.Sample()
+	assert.Positive(t, first.Goroutines)
+	assert.Positive(t, first.HeapAllocBytes)
+	assert.Equal(t, int64(1500), first.LogDirBytes)
+	assert.Equal(t, -1.0, first.CPUPercent, "no CPU usage without a previous sample")
+
+	second := s.Sample()
+	if runtime.GOOS != "windows" {
+		assert.GreaterOrEqual(t, second.CPUPercent, 0.0)
+	}
+	latest, ok := s.Latest()
+	require.True(t, ok)
+	assert.Equal(t, second, latest)
+	assert.NotEmpty(t, s.Health())
+
+	assert.Equal(t, int64(-1), NewSampler("", Thresholds{}, nil).Sample().LogDirBytes)
+	assert.Equal(t, int64(-1), NewSampler(filepath.Join(dir, "missing"), Thresholds{}, nil).Sample().LogDirBytes)
+}
+
+func TestSampler_History(t *testing.T) {
+	s := NewSampler("", Thresholds{}, nil)
+	for i := 0; i < 3; i++ {
+		s.Sample()
+	}
+	history := s.History()
+	require.Len(t, history, 3)
+	assert.False(t, history[2].Timestamp.Before(history[0].Timestamp))
+
+	for i := 0; i < defaultSampleHistory; i++ {
+		s.Sample()
+	}
+	history = s.History()
+	require.Len(t, history, defaultSampleHistory)
+	latest, _ := s.Latest()
+	assert.Equal(t, latest, history[len(history)-1])
+	for i := 1; i < len(history); i++ {
+		assert.False(t, history[i].Timestamp.Before(history[i-1].Timestamp))
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePrometheus(&buf, SystemMetrics{HeapAllocBytes: 1024, Goroutines: 12, CPUPercent: 2.5, LogDirBytes: -1}))
+
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE portal64_goroutines gauge\nportal64_goroutines 12\n")
+	assert.Contains(t, out, "portal64_heap_alloc_bytes 1024\n")
+	assert.Contains(t, out, "portal64_cpu_percent 2.5\n")
+	assert.NotContains(t, out, "portal64_log_dir_bytes")
+}
@@ -0,0 +1,189 @@
+package testserver
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Player represents a player record as returned by the Portal64 API
+type Player struct {
+	ID         string `json:"id"`
+	PKZ        string `json:"pkz"` // Unique player identifier
+	Name       string `json:"name"`
+	Firstname  string `json:"firstname"`
+	Club       string `json:"club"`
+	ClubID     string `json:"club_id"`
+	BirthYear  int    `json:"birth_year"`
+	Gender     string `json:"gender"` // API returns m/w/d format
+	Nation     string `json:"nation"`
+	FideID     int    `json:"fide_id"`
+	CurrentDWZ int    `json:"current_dwz"`
+	DWZIndex   int    `json:"dwz_index"`
+	Status     string `json:"status"`
+}
+
+// Club represents a club record as returned by the Portal64 API
+type Club struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Region      string `json:"region"`
+	MemberCount int    `json:"member_count"`
+	ActiveCount int    `json:"active_count"`
+	Founded     string `json:"founded"`
+	Status      string `json:"status"`
+}
+
+// Tournament represents a tournament record as returned by the Portal64 API
+type Tournament struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Location     string `json:"location"`
+	StartDate    string `json:"start_date"`
+	EndDate      string `json:"end_date"`
+	Status       string `json:"status"`
+	Participants int    `json:"participants"`
+	Organization string `json:"organization"`
+}
+
+// MarshalJSON implements json.Marshaler. The dates are kept as YYYY-MM-DD
+// for filtering but served as timestamps like the API does.
+func (t Tournament) MarshalJSON() ([]byte, error) {
+	type alias Tournament
+	served := alias(t)
+	served.StartDate = timestamp(t.StartDate)
+	served.EndDate = timestamp(t.EndDate)
+	return json.Marshal(served)
+}
+
+// timestamp converts a YYYY-MM-DD date to RFC 3339, other values are
+// returned unchanged
+func timestamp(date string) string {
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	return date
+}
+
+// Region represents a region available for address lookups
+type Region struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Country      string   `json:"country"`
+	AddressTypes []string `json:"address_types"`
+}
+
+// HistoryEntry is a rating history entry as returned by the Portal64 API
+type HistoryEntry struct {
+	ID             int        `json:"id"`
+	TournamentID   string     `json:"tournament_id"`
+	TournamentName string     `json:"tournament_name"`
+	TournamentDate *time.Time `json:"tournament_date"`
+	Games          int        `json:"games"`
+	Points         float64    `json:"points"`
+	Achievement    int        `json:"achievement"`
+	DWZOld         int        `json:"dwz_old"`
+	DWZOldIndex    int        `json:"dwz_old_index"`
+	DWZNew         int        `json:"dwz_new"`
+	DWZNewIndex    int        `json:"dwz_new_index"`
+}
+
+// Dataset holds all data served by the mock server
+type Dataset struct {
+	Players     []Player
+	Clubs       []Club
+	Tournaments []Tournament
+	Regions     []Region
+	// ClubPlayers overrides the member list returned for a club. Clubs
+	// without an entry fall back to the players whose ClubID matches.
+	ClubPlayers map[string][]Player
+	// Histories are the rating histories by player ID, oldest first.
+	// Players without an entry have an empty history.
+	Histories map[string][]HistoryEntry
+}
+
+// DefaultDataset returns the static data set used by the standalone mock server
+func DefaultDataset() *Dataset {
+	return &Dataset{
+		Players: []Player{
+			{
+				ID: "C0327-297", PKZ: "PKZ123456789", Name: "Tran", Firstname: "Minh Cuong", Club: "SC Altbach 1926 e.V.", ClubID: "C0327",
+				BirthYear: 1990, Gender: "m", Nation: "GER", FideID: 24663832, CurrentDWZ: 1643, DWZIndex: 60, Status: "active",
+			},
+			{
+				ID: "C0505-1043", PKZ: "PKZ987654321", Name: "Aab", Firstname: "Manfred", Club: "SC Böblingen 1975 e.V.", ClubID: "C0505",
+				BirthYear: 1963, Gender: "m", Nation: "GER", FideID: 24663833, CurrentDWZ: 1643, DWZIndex: 60, Status: "active",
+			},
+			{
+				ID: "C0505-1044", PKZ: "PKZ555666777", Name: "Mueller", Firstname: "Anna", Club: "SC Böblingen 1975 e.V.", ClubID: "C0505",
+				BirthYear: 1985, Gender: "w", Nation: "GER", FideID: 24663834, CurrentDWZ: 1520, DWZIndex: 45, Status: "active",
+			},
+			{
+				ID: "C0327-298", PKZ: "PKZ111222333", Name: "Schmidt", Firstname: "Alex", Club: "SC Altbach 1926 e.V.", ClubID: "C0327",
+				BirthYear: 1992, Gender: "d", Nation: "GER", FideID: 24663835, CurrentDWZ: 1750, DWZIndex: 35, Status: "active",
+			},
+		},
+		Clubs: []Club{
+			{
+				ID: "C0327", Name: "SC Altbach 1926 e.V.", City: "Altbach", State: "Baden-Württemberg", Region: "Württemberg",
+				MemberCount: 45, ActiveCount: 38, Founded: "1926", Status: "active",
+			},
+			{
+				ID: "C0505", Name: "SC Böblingen 1975 e.V.", City: "Böblingen", State: "Baden-Württemberg", Region: "Württemberg",
+				MemberCount: 62, ActiveCount: 51, Founded: "1975", Status: "active",
+			},
+		},
+		Tournaments: []Tournament{
+			{
+				ID: "C350-C01-SMU", Name: "Ulm Open 2024", Location: "Ulm", StartDate: "2024-03-15", EndDate: "2024-03-17",
+				Status: "completed", Participants: 84, Organization: "SC Ulm 1946 e.V.",
+			},
+			{
+				ID: "B735-705-QCB", Name: "Bezirksliga Württemberg 2024", Location: "Stuttgart", StartDate: "2024-02-10", EndDate: "2024-02-11",
+				Status: "completed", Participants: 56, Organization: "Württemberg Chess Federation",
+			},
+			{
+				ID: "T96887", Name: "Kreismeisterschaft 2024", Location: "Esslingen", StartDate: "2024-01-20", EndDate: "2024-01-21",
+				Status: "completed", Participants: 32, Organization: "SK Esslingen 1925",
+			},
+		},
+		Regions: []Region{
+			{Code: "BW", Name: "Baden-Württemberg", Country: "Germany", AddressTypes: []string{"president", "secretary", "treasurer"}},
+			{Code: "BY", Name: "Bayern", Country: "Germany", AddressTypes: []string{"president", "secretary", "treasurer"}},
+			{Code: "BE", Name: "Berlin", Country: "Germany", AddressTypes: []string{"president", "secretary"}},
+			{Code: "NW", Name: "Nordrhein-Westfalen", Country: "Germany", AddressTypes: []string{"president", "secretary", "treasurer"}},
+		},
+		ClubPlayers: map[string][]Player{
+			"C0327": {
+				{
+					ID: "C0327-297", PKZ: "PKZ123456789", Name: "Tran", Firstname: "Minh Cuong", Club: "SC Altbach 1926 e.V.", ClubID: "C0327",
+					BirthYear: 1990, Gender: "m", Nation: "GER", FideID: 0, CurrentDWZ: 1850, DWZIndex: 25, Status: "active",
+				},
+				{
+					ID: "C0327-298", PKZ: "PKZ111222333", Name: "Schmidt", Firstname: "Alex", Club: "SC Altbach 1926 e.V.", ClubID: "C0327",
+					BirthYear: 1985, Gender: "d", Nation: "GER", FideID: 0, CurrentDWZ: 1720, DWZIndex: 18, Status: "active",
+				},
+				{
+					ID: "C0327-299", PKZ: "PKZ444555666", Name: "Mueller", Firstname: "Lisa", Club: "SC Altbach 1926 e.V.", ClubID: "C0327",
+					BirthYear: 1988, Gender: "w", Nation: "GER", FideID: 0, CurrentDWZ: 1680, DWZIndex: 22, Status: "active",
+				},
+			},
+		},
+	}
+}
+
+// playersForClub returns the member list for a club
+func (d *Dataset) playersForClub(clubID string) []Player {
+	if players, ok := d.ClubPlayers[clubID]; ok {
+		return players
+	}
+
+	var players []Player
+	for _, p := range d.Players {
+		if p.ClubID == clubID {
+			players = append(players, p)
+		}
+	}
+	return players
+}
@@ -0,0 +1,265 @@
+package testserver
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// GenerateOptions configures GenerateDataset. Zero values use the defaults.
+type GenerateOptions struct {
+	Seed        int64
+	Players     int       // Default 5000
+	Clubs       int       // Default 250
+	Tournaments int       // Default 400
+	MaxHistory  int       // Most rating history entries per player, default 20
+	Reference   time.Time // "Today" of the data set, default 2025-01-01
+}
+
+// Defaults of GenerateOptions
+const (
+	defaultGeneratedPlayers     = 5000
+	defaultGeneratedClubs       = 250
+	defaultGeneratedTournaments = 400
+	defaultGeneratedHistory     = 20
+)
+
+// defaultReference is fixed so that generated data sets do not depend on
+// the current date
+var defaultReference = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// generatedCity is a city clubs and tournaments are placed in. Club IDs
+// start with the letter code of the region.
+type generatedCity struct {
+	name, state, region, code string
+}
+
+var generatedCities = []generatedCity{
+	{"Stuttgart", "Baden-Württemberg", "Württemberg", "C"},
+	{"Ulm", "Baden-Württemberg", "Württemberg", "C"},
+	{"Esslingen", "Baden-Württemberg", "Württemberg", "C"},
+	{"Böblingen", "Baden-Württemberg", "Württemberg", "C"},
+	{"Tübingen", "Baden-Württemberg", "Württemberg", "C"},
+	{"Karlsruhe", "Baden-Württemberg", "Baden", "C"},
+	{"Freiburg", "Baden-Württemberg", "Baden", "C"},
+	{"Mannheim", "Baden-Württemberg", "Baden", "C"},
+	{"München", "Bayern", "Bayern", "B"},
+	{"Nürnberg", "Bayern", "Bayern", "B"},
+	{"Augsburg", "Bayern", "Bayern", "B"},
+	{"Regensburg", "Bayern", "Bayern", "B"},
+	{"Berlin", "Berlin", "Berlin", "D"},
+	{"Köln", "Nordrhein-Westfalen", "Nordrhein-Westfalen", "G"},
+	{"Düsseldorf", "Nordrhein-Westfalen", "Nordrhein-Westfalen", "G"},
+	{"Dortmund", "Nordrhein-Westfalen", "Nordrhein-Westfalen", "G"},
+	{"Hamburg", "Hamburg", "Hamburg", "H"},
+	{"Hannover", "Niedersachsen", "Niedersachsen", "N"},
+	{"Leipzig", "Sachsen", "Sachsen", "S"},
+	{"Dresden", "Sachsen", "Sachsen", "S"},
+}
+
+var (
+	generatedSurnames = []string{
+		"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker", "Schulz", "Hoffmann",
+		"Schäfer", "Koch", "Bauer", "Richter", "Klein", "Wolf", "Schröder", "Neumann", "Schwarz", "Zimmermann",
+		"Braun", "Krüger", "Hofmann", "Hartmann", "Lange", "Schmitt", "Werner", "Krause", "Meier", "Lehmann",
+		"Nguyen", "Tran", "Yilmaz", "Kowalski", "Novak", "Petrov", "Ivanov", "Rossi", "Berger", "Fuchs",
+	}
+	generatedMaleNames = []string{
+		"Thomas", "Michael", "Andreas", "Stefan", "Peter", "Jan", "Lukas", "Felix", "Jonas", "Leon",
+		"Paul", "Max", "Tim", "Florian", "Tobias", "Markus", "Daniel", "Matthias", "Minh", "Mehmet",
+	}
+	generatedFemaleNames = []string{
+		"Anna", "Laura", "Julia", "Sarah", "Lisa", "Lena", "Marie", "Sophie", "Katharina", "Hannah",
+		"Sabine", "Petra", "Monika", "Claudia", "Elena", "Mia", "Emma", "Lea", "Linh", "Ayse",
+	}
+	generatedClubPrefixes = []string{"SC", "SK", "SV", "SF", "Schachfreunde", "Schachklub", "TSV"}
+	generatedSeries       = []string{"Open", "Stadtmeisterschaft", "Schnellschach-Open", "Bezirksliga", "Kreismeisterschaft", "Jugendmeisterschaft", "Senioren-Open"}
+)
+
+// GenerateDataset returns a data set of the given size with realistic
+// players, clubs, tournaments and rating histories. The same options always
+// produce the same data set.
+func GenerateDataset(opts GenerateOptions) *Dataset {
+	if opts.Players <= 0 {
+		opts.Players = defaultGeneratedPlayers
+	}
+	if opts.Clubs <= 0 {
+		opts.Clubs = defaultGeneratedClubs
+	}
+	if opts.Tournaments <= 0 {
+		opts.Tournaments = defaultGeneratedTournaments
+	}
+	if opts.MaxHistory <= 0 {
+		opts.MaxHistory = defaultGeneratedHistory
+	}
+	if opts.Reference.IsZero() {
+		opts.Reference = defaultReference
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	dataset := &Dataset{
+		Regions:   DefaultDataset().Regions,
+		Histories: make(map[string][]HistoryEntry, opts.Players),
+	}
+
+	for i := 0; i < opts.Clubs; i++ {
+		city := generatedCities[rng.Intn(len(generatedCities))]
+		founded := 1880 + rng.Intn(130)
+		dataset.Clubs = append(dataset.Clubs, Club{
+			ID:      fmt.Sprintf("%s%04d", city.code, i+1),
+			Name:    fmt.Sprintf("%s %s %d e.V.", generatedClubPrefixes[rng.Intn(len(generatedClubPrefixes))], city.name, founded),
+			City:    city.name,
+			State:   city.state,
+			Region:  city.region,
+			Founded: fmt.Sprint(founded),
+			Status:  "active",
+		})
+	}
+
+	dataset.Tournaments = generateTournaments(rng, opts)
+	// Rating histories are built from the rated tournaments of the past
+	var past []Tournament
+	for _, t := range dataset.Tournaments {
+		if t.Status == "completed" {
+			past = append(past, t)
+		}
+	}
+
+	for i := 0; i < opts.Players; i++ {
+		clubIndex := rng.Intn(opts.Clubs)
+		club := &dataset.Clubs[clubIndex]
+		club.MemberCount++
+
+		player := generatePlayer(rng, club, club.MemberCount, opts.Reference.Year())
+		if player.Status == "active" {
+			club.ActiveCount++
+		}
+		if player.CurrentDWZ > 0 {
+			dataset.Histories[player.ID] = generateHistory(rng, player, past, opts.MaxHistory)
+		}
+		dataset.Players = append(dataset.Players, player)
+	}
+
+	return dataset
+}
+
+// generatePlayer returns a member of club with the given member number
+func generatePlayer(rng *rand.Rand, club *Club, number, year int) Player {
+	player := Player{
+		ID:        fmt.Sprintf("%s-%d", club.ID, number),
+		PKZ:       fmt.Sprintf("PKZ%09d", rng.Intn(1_000_000_000)),
+		Name:      generatedSurnames[rng.Intn(len(generatedSurnames))],
+		Club:      club.Name,
+		ClubID:    club.ID,
+		BirthYear: year - 8 - rng.Intn(75),
+		Nation:    "GER",
+		Status:    "active",
+	}
+
+	switch r := rng.Float64(); {
+	case r < 0.85:
+		player.Gender = "m"
+		player.Firstname = generatedMaleNames[rng.Intn(len(generatedMaleNames))]
+	case r < 0.99:
+		player.Gender = "w"
+		player.Firstname = generatedFemaleNames[rng.Intn(len(generatedFemaleNames))]
+	default:
+		player.Gender = "d"
+		player.Firstname = generatedFemaleNames[rng.Intn(len(generatedFemaleNames))]
+	}
+	if rng.Float64() < 0.1 {
+		player.Status = "passive"
+	}
+	if rng.Float64() < 0.05 {
+		player.Nation = []string{"AUT", "SUI", "POL", "VIE", "TUR", "UKR"}[rng.Intn(6)]
+	}
+
+	// About one in ten members is unrated; ratings are roughly normal
+	// around 1550, juniors lower
+	if rng.Float64() >= 0.1 {
+		mean := 1550.0
+		if year-player.BirthYear < 18 {
+			mean = 1150
+		}
+		player.CurrentDWZ = min(max(int(rng.NormFloat64()*300+mean), 600), 2650)
+		player.DWZIndex = 1 + rng.Intn(80)
+		if player.CurrentDWZ >= 1700 && rng.Float64() < 0.6 {
+			player.FideID = 4_000_000 + rng.Intn(30_000_000)
+		}
+	}
+	return player
+}
+
+// generateTournaments returns tournaments spread over the five years before
+// the reference date and the three months after it, in date order
+func generateTournaments(rng *rand.Rand, opts GenerateOptions) []Tournament {
+	start := opts.Reference.AddDate(-5, 0, 0)
+	span := int(opts.Reference.AddDate(0, 3, 0).Sub(start).Hours() / 24)
+
+	tournaments := make([]Tournament, opts.Tournaments)
+	for i := range tournaments {
+		city := generatedCities[rng.Intn(len(generatedCities))]
+		begin := start.AddDate(0, 0, rng.Intn(span))
+		end := begin.AddDate(0, 0, rng.Intn(3))
+		status := "completed"
+		if !end.Before(opts.Reference) {
+			status = "upcoming"
+		}
+		tournaments[i] = Tournament{
+			ID:           fmt.Sprintf("%s%d-%03d-%c%c%c", city.code, 100+i/1000, i%1000, 'A'+rng.Intn(26), 'A'+rng.Intn(26), 'A'+rng.Intn(26)),
+			Name:         fmt.Sprintf("%s %s %d", city.name, generatedSeries[rng.Intn(len(generatedSeries))], begin.Year()),
+			Location:     city.name,
+			StartDate:    begin.Format("2006-01-02"),
+			EndDate:      end.Format("2006-01-02"),
+			Status:       status,
+			Participants: 8 + rng.Intn(120),
+			Organization: fmt.Sprintf("Schachverband %s", city.region),
+		}
+	}
+	sort.SliceStable(tournaments, func(i, j int) bool { return tournaments[i].StartDate < tournaments[j].StartDate })
+	return tournaments
+}
+
+// generateHistory returns the rating history of a rated player, in date
+// order and ending with the current DWZ. The ratings walk back from the
+// current DWZ; some histories start with the first rating of a newcomer.
+func generateHistory(rng *rand.Rand, player Player, past []Tournament, maxEntries int) []HistoryEntry {
+	if len(past) == 0 {
+		return nil
+	}
+	n := min(1+rng.Intn(maxEntries), len(past))
+
+	// Distinct tournaments in date order
+	picked := rng.Perm(len(past))[:n]
+	sort.Ints(picked)
+
+	history := make([]HistoryEntry, n)
+	dwz, index := player.CurrentDWZ, player.DWZIndex
+	for i := n - 1; i >= 0; i-- {
+		t := past[picked[i]]
+		date, _ := time.Parse("2006-01-02", t.StartDate)
+		games := 5 + rng.Intn(5)
+		change := int(rng.NormFloat64() * 25)
+		old := max(dwz-change, 600)
+		if i == 0 && rng.Float64() < 0.3 {
+			// First rating of a newcomer
+			old = 0
+		}
+		history[i] = HistoryEntry{
+			ID:             rng.Intn(10_000_000),
+			TournamentID:   t.ID,
+			TournamentName: t.Name,
+			TournamentDate: &date,
+			Games:          games,
+			Points:         float64(rng.Intn(2*games+1)) / 2,
+			Achievement:    max(dwz+change*2+rng.Intn(100)-50, 0),
+			DWZOld:         old,
+			DWZOldIndex:    max(index-1, 0),
+			DWZNew:         dwz,
+			DWZNewIndex:    index,
+		}
+		dwz, index = old, max(index-1, 0)
+	}
+	return history
+}
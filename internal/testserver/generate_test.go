@@ -0,0 +1,92 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDataset_Deterministic(t *testing.T) {
+	opts := GenerateOptions{Seed: 42, Players: 500, Clubs: 20, Tournaments: 50}
+	first := GenerateDataset(opts)
+	second := GenerateDataset(opts)
+	assert.Equal(t, first, second)
+
+	other := GenerateDataset(GenerateOptions{Seed: 43, Players: 500, Clubs: 20, Tournaments: 50})
+	assert.NotEqual(t, first.Players, other.Players)
+}
+
+func TestGenerateDataset_Consistent(t *testing.T) {
+	dataset := GenerateDataset(GenerateOptions{Seed: 1})
+	require.Len(t, dataset.Players, 5000)
+	require.Len(t, dataset.Clubs, 250)
+	require.Len(t, dataset.Tournaments, 400)
+
+	clubs := make(map[string]Club)
+	for _, club := range dataset.Clubs {
+		require.NotContains(t, clubs, club.ID, "duplicate club ID")
+		clubs[club.ID] = club
+	}
+
+	players := make(map[string]bool)
+	members := make(map[string]int)
+	for _, player := range dataset.Players {
+		require.False(t, players[player.ID], "duplicate player ID %s", player.ID)
+		players[player.ID] = true
+		members[player.ClubID]++
+		assert.Equal(t, clubs[player.ClubID].Name, player.Club)
+
+		history := dataset.Histories[player.ID]
+		if player.CurrentDWZ == 0 {
+			assert.Empty(t, history)
+			continue
+		}
+		require.NotEmpty(t, history, player.ID)
+		assert.Equal(t, player.CurrentDWZ, history[len(history)-1].DWZNew)
+		for i := 1; i < len(history); i++ {
+			assert.Equal(t, history[i-1].DWZNew, history[i].DWZOld)
+			assert.False(t, history[i].TournamentDate.Before(*history[i-1].TournamentDate))
+		}
+	}
+	for _, club := range dataset.Clubs {
+		assert.Equal(t, members[club.ID], club.MemberCount, club.ID)
+	}
+}
+
+func TestGenerateDataset_Pagination(t *testing.T) {
+	dataset := GenerateDataset(GenerateOptions{Seed: 7, Players: 1000, Clubs: 10})
+	server := NewMockPortal64Server(Config{Dataset: dataset}).Start()
+	defer server.Close()
+
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += 100 {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/players?limit=100&offset=%d", server.URL, offset))
+		require.NoError(t, err)
+		var result SearchResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		resp.Body.Close()
+
+		assert.Equal(t, 1000, result.Data.Meta.Total)
+		for _, item := range result.Data.Data {
+			seen[item.(map[string]interface{})["id"].(string)] = true
+		}
+		if result.Data.Meta.Count < 100 {
+			break
+		}
+	}
+	assert.Len(t, seen, 1000)
+
+	player := dataset.Players[0]
+	resp, err := http.Get(server.URL + "/api/v1/players/" + player.ID + "/rating-history")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var history struct {
+		Data []HistoryEntry `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&history))
+	assert.Len(t, history.Data, len(dataset.Histories[player.ID]))
+}
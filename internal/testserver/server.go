@@ -0,0 +1,646 @@
+// Package testserver provides a programmable mock of the Portal64 REST API
+// for use by unit, integration, and end-to-end tests.
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures a MockPortal64Server
+type Config struct {
+	// Dataset served by the mock; DefaultDataset is used when nil
+	Dataset *Dataset
+	// Latency is added to every response before it is written
+	Latency time.Duration
+	// Errors are matched in order against each incoming request
+	Errors []ErrorScenario
+}
+
+// ErrorScenario makes the mock fail requests whose path starts with PathPrefix
+type ErrorScenario struct {
+	PathPrefix string
+	Method     string // empty matches any method
+	StatusCode int
+	Message    string
+	// Times limits how often the scenario fires; zero means always
+	Times int
+}
+
+// MockPortal64Server serves Portal64 API endpoints from an in-memory dataset
+type MockPortal64Server struct {
+	mu       sync.RWMutex
+	dataset  *Dataset
+	latency  time.Duration
+	errors   []ErrorScenario
+	fired    map[int]int
+	requests map[string]int
+	mux      *http.ServeMux
+}
+
+// SearchResponse mirrors the wrapped search response shape of the API
+type SearchResponse struct {
+	Success bool       `json:"success"`
+	Data    SearchData `json:"data"`
+}
+
+// SearchData holds a page of search results
+type SearchData struct {
+	Data []interface{} `json:"data"`
+	Meta MetaData      `json:"meta"`
+}
+
+// MetaData holds pagination metadata of a search response
+type MetaData struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Count  int `json:"count"`
+}
+
+// NewMockPortal64Server creates a new mock server from the given configuration
+func NewMockPortal64Server(cfg Config) *MockPortal64Server {
+	dataset := cfg.Dataset
+	if dataset == nil {
+		dataset = DefaultDataset()
+	}
+
+	s := &MockPortal64Server{
+		dataset:  dataset,
+		latency:  cfg.Latency,
+		errors:   append([]ErrorScenario(nil), cfg.Errors...),
+		fired:    make(map[int]int),
+		requests: make(map[string]int),
+		mux:      http.NewServeMux(),
+	}
+	s.registerRoutes()
+
+	return s
+}
+
+// Start starts the mock server on a random local port
+func (s *MockPortal64Server) Start() *httptest.Server {
+	return httptest.NewServer(s)
+}
+
+// ServeHTTP implements http.Handler
+func (s *MockPortal64Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests[r.URL.Path]++
+	latency := s.latency
+	scenario, failed := s.matchError(r)
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if failed {
+		writeJSON(w, scenario.StatusCode, map[string]interface{}{
+			"success": false,
+			"message": scenario.Message,
+		})
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetLatency changes the latency added to subsequent responses
+func (s *MockPortal64Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// AddErrorScenario registers an additional error scenario
+func (s *MockPortal64Server) AddErrorScenario(scenario ErrorScenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, scenario)
+}
+
+// ClearErrorScenarios removes all registered error scenarios
+func (s *MockPortal64Server) ClearErrorScenarios() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = nil
+	s.fired = make(map[int]int)
+}
+
+// RequestCount returns how many requests were received for a path
+func (s *MockPortal64Server) RequestCount(path string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.requests[path]
+}
+
+// matchError returns the first error scenario applying to the request.
+// The caller must hold the write lock.
+func (s *MockPortal64Server) matchError(r *http.Request) (ErrorScenario, bool) {
+	for i, scenario := range s.errors {
+		if !strings.HasPrefix(r.URL.Path, scenario.PathPrefix) {
+			continue
+		}
+		if scenario.Method != "" && scenario.Method != r.Method {
+			continue
+		}
+		if scenario.Times > 0 && s.fired[i] >= scenario.Times {
+			continue
+		}
+		s.fired[i]++
+		return scenario, true
+	}
+	return ErrorScenario{}, false
+}
+
+// registerRoutes registers all API endpoints
+func (s *MockPortal64Server) registerRoutes() {
+	s.mux.HandleFunc("/health", s.handleHealth)
+	s.mux.HandleFunc("/api/v1/health", s.handleAPIHealth)
+	s.mux.HandleFunc("/api/v1/players", s.handlePlayers)
+	s.mux.HandleFunc("/api/v1/players/", s.handlePlayerDetails)
+	s.mux.HandleFunc("/api/v1/clubs", s.handleClubs)
+	s.mux.HandleFunc("/api/v1/clubs/", s.handleClubDetails)
+	s.mux.HandleFunc("/api/v1/tournaments", s.handleTournaments)
+	s.mux.HandleFunc("/api/v1/tournaments/", s.handleTournamentDetails)
+	s.mux.HandleFunc("/api/v1/tournaments/search", s.handleTournamentSearch)
+	s.mux.HandleFunc("/api/v1/tournaments/recent", s.handleRecentTournaments)
+	s.mux.HandleFunc("/api/v1/admin/cache", s.handleCacheStats)
+	s.mux.HandleFunc("/api/v1/addresses/regions", s.handleRegions)
+	s.mux.HandleFunc("/api/v1/addresses/", s.handleRegionAddresses)
+}
+
+// Endpoints lists the endpoints served by the mock
+func Endpoints() []string {
+	return []string{
+		"GET /health",
+		"GET /api/v1/health",
+		"GET /api/v1/players",
+		"GET /api/v1/players/{id}",
+		"GET /api/v1/players/{id}/history",
+		"GET /api/v1/players/{id}/rating-history",
+		"GET /api/v1/clubs",
+		"GET /api/v1/clubs/{id}",
+		"GET /api/v1/clubs/{id}/players",
+		"GET /api/v1/clubs/{id}/profile",
+		"GET /api/v1/clubs/{id}/statistics",
+		"GET /api/v1/tournaments",
+		"GET /api/v1/tournaments/{id}",
+		"GET /api/v1/tournaments/search",
+		"GET /api/v1/tournaments/recent",
+		"GET /api/v1/admin/cache",
+		"GET /api/v1/addresses/regions",
+		"GET /api/v1/addresses/{region}",
+	}
+}
+
+func (s *MockPortal64Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "healthy",
+		"version": "1.0.0",
+	})
+}
+
+func (s *MockPortal64Server) handleAPIHealth(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().Format(time.RFC3339)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":        "healthy",
+		"response_time": 15,
+		"api_version":   "1.0.0",
+		"timestamp":     now,
+		"services": map[string]interface{}{
+			"database": map[string]interface{}{
+				"status":        "healthy",
+				"response_time": 5,
+				"last_check":    now,
+			},
+			"cache": map[string]interface{}{
+				"status":        "healthy",
+				"response_time": 2,
+				"last_check":    now,
+			},
+		},
+	})
+}
+
+func (s *MockPortal64Server) handlePlayers(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	limit, offset := parsePagination(r)
+
+	var filtered []interface{}
+	for _, player := range s.dataset.Players {
+		if query == "" ||
+			strings.Contains(strings.ToLower(player.Name+" "+player.Firstname), query) ||
+			strings.Contains(strings.ToLower(player.ID), query) ||
+			strings.Contains(strings.ToLower(player.PKZ), query) {
+			filtered = append(filtered, player)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, paginate(filtered, limit, offset))
+}
+
+func (s *MockPortal64Server) handlePlayerDetails(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/players/")
+
+	if playerID, ok := strings.CutSuffix(path, "/rating-history"); ok {
+		s.handleRatingHistory(w, r, playerID)
+		return
+	}
+
+	if strings.Contains(path, "/history") {
+		s.handlePlayerHistory(w, r)
+		return
+	}
+
+	for _, player := range s.dataset.Players {
+		if player.ID == path {
+			writeJSON(w, http.StatusOK, single(player))
+			return
+		}
+	}
+
+	http.Error(w, "Player not found", http.StatusNotFound)
+}
+
+func (s *MockPortal64Server) handlePlayerHistory(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []map[string]interface{}{
+		{"period": "2024-01", "dwz": 1850, "index": 25, "games": 8, "performance": 1900},
+		{"period": "2023-12", "dwz": 1830, "index": 23, "games": 6, "performance": 1875},
+		{"period": "2023-11", "dwz": 1810, "index": 22, "games": 9, "performance": 1850},
+	})
+}
+
+func (s *MockPortal64Server) handleRatingHistory(w http.ResponseWriter, r *http.Request, playerID string) {
+	history := s.dataset.Histories[playerID]
+	if history == nil {
+		history = []HistoryEntry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    history,
+	})
+}
+
+func (s *MockPortal64Server) handleClubs(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	limit, offset := parsePagination(r)
+
+	var filtered []interface{}
+	for _, club := range s.dataset.Clubs {
+		if query == "" || strings.Contains(strings.ToLower(club.Name), query) {
+			filtered = append(filtered, club)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, paginate(filtered, limit, offset))
+}
+
+func (s *MockPortal64Server) handleClubDetails(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/clubs/")
+
+	if strings.HasSuffix(path, "/players") {
+		s.handleClubPlayers(w, r, strings.TrimSuffix(path, "/players"))
+		return
+	}
+
+	if strings.HasSuffix(path, "/statistics") {
+		s.handleClubStatistics(w, r, strings.TrimSuffix(path, "/statistics"))
+		return
+	}
+
+	if clubID, ok := strings.CutSuffix(path, "/profile"); ok {
+		s.handleClubProfile(w, r, clubID)
+		return
+	}
+
+	for _, club := range s.dataset.Clubs {
+		if club.ID == path {
+			writeJSON(w, http.StatusOK, single(club))
+			return
+		}
+	}
+
+	http.Error(w, "Club not found", http.StatusNotFound)
+}
+
+func (s *MockPortal64Server) handleClubPlayers(w http.ResponseWriter, r *http.Request, clubID string) {
+	players := s.dataset.playersForClub(clubID)
+
+	data := make([]interface{}, len(players))
+	for i, p := range players {
+		data[i] = p
+	}
+
+	// Member lists are paged when a limit is given
+	if r.URL.Query().Has("limit") {
+		limit, offset := parsePagination(r)
+		writeJSON(w, http.StatusOK, paginate(data, limit, offset))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{
+		Success: true,
+		Data: SearchData{
+			Data: data,
+			Meta: MetaData{Total: len(data), Limit: len(data), Offset: 0, Count: len(data)},
+		},
+	})
+}
+
+func (s *MockPortal64Server) handleClubProfile(w http.ResponseWriter, r *http.Request, clubID string) {
+	for _, club := range s.dataset.Clubs {
+		if club.ID != clubID {
+			continue
+		}
+		players := s.dataset.playersForClub(clubID)
+		active := 0
+		for _, player := range players {
+			if player.Status == "active" {
+				active++
+			}
+		}
+		writeJSON(w, http.StatusOK, single(map[string]interface{}{
+			"club":                club,
+			"players":             players,
+			"rating_stats":        ratingStats(players),
+			"player_count":        len(players),
+			"active_player_count": active,
+			"tournament_count":    0,
+		}))
+		return
+	}
+
+	http.Error(w, "Club not found", http.StatusNotFound)
+}
+
+// ratingStats computes the rating statistics of a club profile from its
+// members, with the distribution in 200 point bands
+func ratingStats(players []Player) map[string]interface{} {
+	var ratings []int
+	distribution := make(map[string]int)
+	for _, player := range players {
+		if player.CurrentDWZ <= 0 {
+			continue
+		}
+		ratings = append(ratings, player.CurrentDWZ)
+		band := player.CurrentDWZ / 200 * 200
+		distribution[fmt.Sprintf("%d-%d", band, band+199)]++
+	}
+	stats := map[string]interface{}{
+		"players_with_dwz":    len(ratings),
+		"rating_distribution": distribution,
+	}
+	if len(ratings) == 0 {
+		return stats
+	}
+
+	sort.Ints(ratings)
+	sum := 0
+	for _, rating := range ratings {
+		sum += rating
+	}
+	median := float64(ratings[len(ratings)/2])
+	if len(ratings)%2 == 0 {
+		median = float64(ratings[len(ratings)/2-1]+ratings[len(ratings)/2]) / 2
+	}
+	stats["average_dwz"] = float64(sum) / float64(len(ratings))
+	stats["median_dwz"] = median
+	stats["highest_dwz"] = ratings[len(ratings)-1]
+	stats["lowest_dwz"] = ratings[0]
+	return stats
+}
+
+func (s *MockPortal64Server) handleClubStatistics(w http.ResponseWriter, r *http.Request, clubID string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"club_id":      clubID,
+		"member_count": 45,
+		"active_count": 38,
+		"average_dwz":  1650,
+		"rating_distribution": map[string]interface{}{
+			"under_1200": 5,
+			"1200_1400":  8,
+			"1400_1600":  12,
+			"1600_1800":  10,
+			"1800_2000":  7,
+			"over_2000":  3,
+		},
+		"tournament_participation": map[string]interface{}{
+			"total_tournaments": 24,
+			"avg_per_player":    2.1,
+			"top_performers":    []string{"C0327-297", "C0327-298"},
+		},
+		"performance_trends": map[string]interface{}{
+			"last_6_months": map[string]interface{}{
+				"average_change":    15,
+				"improving_players": 12,
+				"declining_players": 8,
+			},
+		},
+	})
+}
+
+func (s *MockPortal64Server) handleTournaments(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	limit, offset := parsePagination(r)
+
+	var filtered []interface{}
+	for _, tournament := range s.dataset.Tournaments {
+		if query == "" || strings.Contains(strings.ToLower(tournament.Name+" "+tournament.Location), query) {
+			filtered = append(filtered, tournament)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, paginate(filtered, limit, offset))
+}
+
+func (s *MockPortal64Server) handleTournamentDetails(w http.ResponseWriter, r *http.Request) {
+	tournamentID := strings.TrimPrefix(r.URL.Path, "/api/v1/tournaments/")
+
+	for _, tournament := range s.dataset.Tournaments {
+		if tournament.ID == tournamentID {
+			writeJSON(w, http.StatusOK, single(tournament))
+			return
+		}
+	}
+
+	http.Error(w, "Tournament not found", http.StatusNotFound)
+}
+
+func (s *MockPortal64Server) handleTournamentSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+
+	filtered := []interface{}{}
+	for _, tournament := range s.dataset.Tournaments {
+		if query != "" && !strings.Contains(strings.ToLower(tournament.Name), query) &&
+			!strings.Contains(strings.ToLower(tournament.Location), query) {
+			continue
+		}
+		// Dates are ISO formatted, so lexical comparison is sufficient
+		if startDate != "" && tournament.EndDate < startDate {
+			continue
+		}
+		if endDate != "" && tournament.StartDate > endDate {
+			continue
+		}
+		filtered = append(filtered, tournament)
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{
+		Success: true,
+		Data: SearchData{
+			Data: filtered,
+			Meta: MetaData{Total: len(filtered), Limit: len(filtered), Offset: 0, Count: len(filtered)},
+		},
+	})
+}
+
+func (s *MockPortal64Server) handleRecentTournaments(w http.ResponseWriter, r *http.Request) {
+	limit := 25
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
+		limit = l
+	}
+
+	recent := s.dataset.Tournaments
+	if len(recent) > limit {
+		recent = recent[:limit]
+	}
+
+	// Return direct array, not wrapped in SearchResponse
+	writeJSON(w, http.StatusOK, recent)
+}
+
+func (s *MockPortal64Server) handleCacheStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"hit_ratio": 0.893,
+		"operations": map[string]interface{}{
+			"hits":    int64(1250),
+			"misses":  int64(150),
+			"sets":    int64(450),
+			"deletes": int64(25),
+			"flushes": int64(5),
+		},
+		"performance": map[string]interface{}{
+			"average_get_time": int64(2500000), // 2.5 ms in nanoseconds
+			"average_set_time": int64(3200000), // 3.2 ms in nanoseconds
+			"connection_time":  int64(1000000), // 1 ms in nanoseconds
+		},
+		"usage": map[string]interface{}{
+			"used_memory":    int64(2516582),  // ~2.4MB in bytes
+			"max_memory":     int64(16777216), // 16MB in bytes
+			"memory_percent": 15.0,
+			"key_count":      int64(450),
+			"expired_keys":   int64(12),
+		},
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
+func (s *MockPortal64Server) handleRegions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.dataset.Regions)
+}
+
+func (s *MockPortal64Server) handleRegionAddresses(w http.ResponseWriter, r *http.Request) {
+	region := strings.TrimPrefix(r.URL.Path, "/api/v1/addresses/")
+	lower := strings.ToLower(region)
+
+	writeJSON(w, http.StatusOK, []map[string]interface{}{
+		{
+			"id":          "addr-" + region + "-1",
+			"region":      region,
+			"type":        "president",
+			"name":        "Hans Müller",
+			"position":    "President",
+			"email":       "president@chess-" + lower + ".de",
+			"phone":       "+49 123 456789",
+			"address":     "Musterstraße 123",
+			"city":        region,
+			"postal_code": "12345",
+			"country":     "Germany",
+		},
+		{
+			"id":          "addr-" + region + "-2",
+			"region":      region,
+			"type":        "secretary",
+			"name":        "Maria Schmidt",
+			"position":    "Secretary",
+			"email":       "secretary@chess-" + lower + ".de",
+			"phone":       "+49 123 456790",
+			"address":     "Beispielweg 456",
+			"city":        region,
+			"postal_code": "12346",
+			"country":     "Germany",
+		},
+	})
+}
+
+// parsePagination extracts limit and offset query parameters
+func parsePagination(r *http.Request) (int, int) {
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
+		limit = l
+	}
+
+	offset := 0
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil {
+		offset = o
+	}
+
+	return limit, offset
+}
+
+// paginate slices items and wraps them in a search response
+func paginate(items []interface{}, limit, offset int) SearchResponse {
+	start := offset
+	if start < 0 {
+		start = 0
+	}
+	end := start + limit
+	if end < start {
+		end = start
+	}
+	if start > len(items) {
+		start = len(items)
+	}
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := items[start:end]
+	return SearchResponse{
+		Success: true,
+		Data: SearchData{
+			Data: page,
+			Meta: MetaData{Total: len(items), Limit: limit, Offset: offset, Count: len(page)},
+		},
+	}
+}
+
+// single wraps one entity like the detail endpoints of the API
+func single(item interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"success": true,
+		"data":    item,
+	}
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
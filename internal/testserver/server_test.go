@@ -0,0 +1,122 @@
+package testserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockPortal64Server_DefaultDataset(t *testing.T) {
+	server := NewMockPortal64Server(Config{}).Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/players?query=minh")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result SearchResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, result.Data.Meta.Total)
+	assert.Len(t, result.Data.Data, 1)
+}
+
+func TestMockPortal64Server_CustomDatasetAndPagination(t *testing.T) {
+	dataset := &Dataset{}
+	for i := 0; i < 5; i++ {
+		dataset.Clubs = append(dataset.Clubs, Club{ID: "C000" + string(rune('0'+i)), Name: "Club"})
+	}
+	server := NewMockPortal64Server(Config{Dataset: dataset}).Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/clubs?limit=2&offset=4")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result SearchResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	assert.Equal(t, 5, result.Data.Meta.Total)
+	assert.Equal(t, 1, result.Data.Meta.Count)
+}
+
+func TestMockPortal64Server_ErrorScenarios(t *testing.T) {
+	mock := NewMockPortal64Server(Config{
+		Errors: []ErrorScenario{
+			{PathPrefix: "/api/v1/clubs", StatusCode: http.StatusServiceUnavailable, Message: "maintenance", Times: 1},
+		},
+	})
+	server := mock.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/clubs")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// Scenario is exhausted after one failure
+	resp, err = http.Get(server.URL + "/api/v1/clubs")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, mock.RequestCount("/api/v1/clubs"))
+}
+
+func TestMockPortal64Server_Latency(t *testing.T) {
+	mock := NewMockPortal64Server(Config{})
+	mock.SetLatency(50 * time.Millisecond)
+	server := mock.Start()
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestMockPortal64Server_Details(t *testing.T) {
+	server := NewMockPortal64Server(Config{}).Start()
+	defer server.Close()
+
+	get := func(path string, v interface{}) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+
+	var player struct {
+		Success bool   `json:"success"`
+		Data    Player `json:"data"`
+	}
+	get("/api/v1/players/C0327-297", &player)
+	assert.True(t, player.Success)
+	assert.Equal(t, "C0327-297", player.Data.ID)
+
+	var tournament struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	get("/api/v1/tournaments/C350-C01-SMU", &tournament)
+	assert.Equal(t, "2024-03-15T00:00:00Z", tournament.Data["start_date"])
+
+	var profile struct {
+		Data struct {
+			Club        Club                   `json:"club"`
+			Players     []Player               `json:"players"`
+			RatingStats map[string]interface{} `json:"rating_stats"`
+		} `json:"data"`
+	}
+	get("/api/v1/clubs/C0327/profile", &profile)
+	assert.Equal(t, "C0327", profile.Data.Club.ID)
+	require.NotEmpty(t, profile.Data.Players)
+	assert.Equal(t, float64(len(profile.Data.Players)), profile.Data.RatingStats["players_with_dwz"])
+	assert.Contains(t, profile.Data.RatingStats, "average_dwz")
+}
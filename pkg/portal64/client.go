@@ -84,7 +84,8 @@ func (c *clientImpl) GetPlayerProfile(ctx context.Context, playerID string) (*Pl
 		return nil, err
 	}
 	
-	return (*PlayerResponse)(result), nil
+	player := convertPlayerResponse(result)
+	return &player, nil
 }
 
 func (c *clientImpl) GetPlayerRatingHistory(ctx context.Context, playerID string) ([]Evaluation, error) {
@@ -95,7 +96,7 @@ func (c *clientImpl) GetPlayerRatingHistory(ctx context.Context, playerID string
 	
 	evaluations := make([]Evaluation, len(result))
 	for i, eval := range result {
-		evaluations[i] = Evaluation(eval)
+		evaluations[i] = convertEvaluation(&eval)
 	}
 	
 	return evaluations, nil
@@ -254,7 +255,7 @@ func (c *clientImpl) GetRecentTournaments(ctx context.Context, days, limit int)
 	
 	tournaments := make([]TournamentResponse, len(result))
 	for i, tournament := range result {
-		tournaments[i] = TournamentResponse(tournament)
+		tournaments[i] = convertTournamentResponse(&tournament)
 	}
 	
 	return tournaments, nil
@@ -444,8 +445,11 @@ func convertTournamentResponse(result *api.TournamentResponse) TournamentRespons
 		Name: result.Name,
 		Organizer: result.Organizer,
 		OrganizerClubID: result.OrganizerClubID,
-		StartDate: result.StartDate,
-		EndDate: result.EndDate,
+		StartDate: timeValue(result.StartDate),
+		EndDate: timeValue(result.EndDate),
+		FinishedOn: result.FinishedOn,
+		ComputedOn: result.ComputedOn,
+		RecomputedOn: result.RecomputedOn,
 		Location: result.Location,
 		City: result.City,
 		State: result.State,
@@ -606,3 +610,11 @@ func convertCacheStatsResponse(result *api.CacheStatsResponse) *CacheStatsRespon
 		Timestamp: result.Timestamp,
 	}
 }
+
+// timeValue dereferences a nullable API timestamp
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
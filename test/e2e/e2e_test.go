@@ -0,0 +1,154 @@
+//go:build e2e
+
+// Package e2etest runs the integration suite against a mock Portal64 API
+// and an MCP server on ephemeral ports and reports the analysis of the
+// results:
+//
+//	go test -tags e2e ./test/e2e
+//	go test -tags e2e ./test/e2e -args -e2e.format json -e2e.out e2e.json
+//
+// Settings are flags after -args or environment variables named
+// PORTAL64_E2E_ and the flag name, such as PORTAL64_E2E_RUN.
+package e2etest
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/e2e"
+)
+
+var (
+	configFile = ""
+	run        = ""
+	pkg        = "../integration"
+	format     = "markdown"
+	out        = ""
+	verbose    = false
+	timeout    = 10 * time.Minute
+)
+
+func init() {
+	stringSetting(&configFile, "config", "Path to configuration file of the MCP server")
+	stringSetting(&run, "run", "Only run tests of the suite matching this pattern")
+	stringSetting(&pkg, "package", "Package of the integration suite")
+	stringSetting(&format, "format", "Report format (markdown, json)")
+	stringSetting(&out, "out", "Write the report to this file, relative to the module root, instead of stdout")
+
+	if value, err := strconv.ParseBool(os.Getenv(settingEnv("verbose"))); err == nil {
+		verbose = value
+	}
+	flag.BoolVar(&verbose, "e2e.verbose", verbose,
+		fmt.Sprintf("Print the test output and server logs to stderr (%s)", settingEnv("verbose")))
+	if value, err := time.ParseDuration(os.Getenv(settingEnv("timeout"))); err == nil {
+		timeout = value
+	}
+	flag.DurationVar(&timeout, "e2e.timeout", timeout,
+		fmt.Sprintf("Timeout of the suite run (%s)", settingEnv("timeout")))
+}
+
+// settingEnv returns the environment variable of a setting
+func settingEnv(name string) string {
+	return "PORTAL64_E2E_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// stringSetting registers the flag -e2e.<name> for a string setting,
+// defaulting to its environment variable or else the current value
+func stringSetting(p *string, name, usage string) {
+	if value := os.Getenv(settingEnv(name)); value != "" {
+		*p = value
+	}
+	flag.StringVar(p, "e2e."+name, *p, fmt.Sprintf("%s (%s)", usage, settingEnv(name)))
+}
+
+// report is the JSON report of a suite run
+type report struct {
+	MockURL   string           `json:"mock_url"`
+	ServerURL string           `json:"server_url"`
+	Analysis  e2e.TestAnalysis `json:"analysis"`
+	Results   []e2e.TestResult `json:"results"`
+}
+
+// TestE2E runs the integration suite against ephemeral servers and fails
+// if a test of the suite failed
+func TestE2E(t *testing.T) {
+	if format != "markdown" && format != "json" {
+		t.Fatalf("Invalid report format %q, expected markdown or json", format)
+	}
+
+	cfg, err := config.Load(configFile)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		t.Fatalf("Invalid configuration: %v", err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	if !verbose {
+		logger.SetLevel(logrus.WarnLevel)
+	}
+
+	env, err := e2e.Start(cfg, logger, 0)
+	if err != nil {
+		t.Fatalf("Failed to start the test environment: %v", err)
+	}
+	defer env.Close()
+	t.Logf("Mock API on %s, MCP server on %s", env.MockURL, env.ServerURL)
+
+	opts := e2e.Options{Package: pkg, Run: run, Timeout: timeout}
+	if verbose {
+		opts.Output = os.Stderr
+	}
+	results, err := e2e.RunSuite(context.Background(), env.ServerURL, opts)
+	if err != nil {
+		t.Fatalf("Failed to run the integration suite: %v", err)
+	}
+
+	analyzer := &e2e.TestResultAnalyzer{Results: results}
+	analysis := analyzer.AnalyzeResults()
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		// go test runs the test in test/e2e
+		if !filepath.IsAbs(out) {
+			out = filepath.Join("..", "..", out)
+		}
+		file, err := os.Create(out)
+		if err != nil {
+			t.Fatalf("Failed to create report: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report{
+			MockURL:   env.MockURL,
+			ServerURL: env.ServerURL,
+			Analysis:  analysis,
+			Results:   results,
+		})
+	} else {
+		_, err = io.WriteString(w, analysis.GenerateDetailedReport())
+	}
+	if err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	if analysis.FailedTests > 0 {
+		t.Errorf("%d of %d tests of the integration suite failed", analysis.FailedTests, analysis.TotalTests)
+	}
+}
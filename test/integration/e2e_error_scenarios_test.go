@@ -12,7 +12,7 @@ import (
 func TestPortal64MCP_E2E_ErrorScenarios(t *testing.T) {
 	// Verify server is running before testing error scenarios
 	if !isServerRunning() {
-		t.Skip("Portal64 MCP Server is not running on localhost:8888 - skipping error scenario tests")
+		t.Skipf("Portal64 MCP Server is not running on %s - skipping error scenario tests", BaseURL)
 	}
 
 	t.Run("Invalid Player/Club/Tournament IDs", testInvalidIDs)
@@ -193,7 +193,7 @@ func testServerUnavailability(t *testing.T) {
 // Additional edge case tests
 func TestPortal64MCP_E2E_EdgeCases(t *testing.T) {
 	if !isServerRunning() {
-		t.Skip("Portal64 MCP Server is not running on localhost:8888 - skipping edge case tests")
+		t.Skipf("Portal64 MCP Server is not running on %s - skipping edge case tests", BaseURL)
 	}
 
 	t.Run("Boundary Value Tests", testBoundaryValues)
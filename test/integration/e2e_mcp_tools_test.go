@@ -13,8 +13,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-const (
-	// Base URL for MCP server as specified in e2e test strategy
+var (
+	// Base URL for MCP server as specified in e2e test strategy, see
+	// harness_test.go for overriding it and the test data
 	BaseURL = "http://localhost:8888"
 	
 	// Test data specifications from e2e test strategy
@@ -47,11 +48,11 @@ type MCPContent struct {
 }
 
 // E2E Test Suite for Portal64 MCP Server
-// Tests all MCP functions through REST API calls against localhost:8888
+// Tests all MCP functions through REST API calls against BaseURL
 func TestPortal64MCP_E2E_AllTools(t *testing.T) {
 	// Verify server is running
 	if !isServerRunning() {
-		t.Skip("Portal64 MCP Server is not running on localhost:8888 - skipping e2e tests")
+		t.Skipf("Portal64 MCP Server is not running on %s - skipping e2e tests", BaseURL)
 	}
 
 	// Test execution order as specified in strategy:
@@ -497,7 +498,7 @@ func testMCPProtocol(t *testing.T) {
 
 // Helper functions
 
-// isServerRunning checks if the Portal64 MCP server is running on localhost:8888
+// isServerRunning checks if the Portal64 MCP server is running on BaseURL
 func isServerRunning() bool {
 	client := &http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Get(BaseURL + "/health")
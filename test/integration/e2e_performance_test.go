@@ -14,7 +14,7 @@ import (
 // Tests performance requirements as specified in the e2e test strategy
 func TestPortal64MCP_E2E_Performance(t *testing.T) {
 	if !isServerRunning() {
-		t.Skip("Portal64 MCP Server is not running on localhost:8888 - skipping performance tests")
+		t.Skipf("Portal64 MCP Server is not running on %s - skipping performance tests", BaseURL)
 	}
 
 	t.Run("Response Time Requirements", testResponseTimes)
@@ -282,11 +282,14 @@ func testMemoryUsage(t *testing.T) {
 		}
 		secondHalfAvg /= time.Duration(len(secondHalf))
 		
-		// Response times shouldn't increase by more than 50% (indicating potential memory issues)
+		// Response times shouldn't increase by more than 50% (indicating potential memory issues).
+		// Against a local server a few milliseconds are noise, not degradation.
 		degradationRatio := float64(secondHalfAvg) / float64(firstHalfAvg)
-		assert.Less(t, degradationRatio, 1.5, 
-			"Response times should not degrade significantly (first half avg: %v, second half avg: %v)", 
-			firstHalfAvg, secondHalfAvg)
+		if secondHalfAvg-firstHalfAvg > 10*time.Millisecond {
+			assert.Less(t, degradationRatio, 1.5, 
+				"Response times should not degrade significantly (first half avg: %v, second half avg: %v)", 
+				firstHalfAvg, secondHalfAvg)
+		}
 		
 		t.Logf("Memory usage test - first half avg: %v, second half avg: %v, ratio: %.2f", 
 			firstHalfAvg, secondHalfAvg, degradationRatio)
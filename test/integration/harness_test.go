@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/svw-info/portal64gomcp/internal/config"
+	"github.com/svw-info/portal64gomcp/internal/e2e"
+)
+
+// The server under test and the test data are set by flags, for example
+//
+//	go test ./test/integration -args -e2e.base-url http://localhost:9000
+//
+// or by environment variables named PORTAL64_E2E_ and the flag name, such
+// as PORTAL64_E2E_BASE_URL. With -e2e.in-process the suite starts the MCP
+// server against the mock Portal64 API itself and needs no other services.
+var (
+	inProcess = false
+	port      = 0
+)
+
+func init() {
+	stringSetting(&BaseURL, "base-url", "MCP server to test")
+	stringSetting(&TestPlayerQuery, "player-query", "Player search query with results")
+	stringSetting(&TestPlayerID, "player-id", "Existing player ID")
+	stringSetting(&TestClubQuery, "club-query", "Club search query with results")
+	stringSetting(&TestClubID, "club-id", "Existing club ID")
+	stringSetting(&TestTournamentQuery, "tournament-query", "Tournament search query with results")
+	stringSetting(&TestTournamentID, "tournament-id", "Existing tournament ID")
+	stringSetting(&TestStartDate, "start-date", "Start of the date range with tournaments")
+	stringSetting(&TestEndDate, "end-date", "End of the date range with tournaments")
+
+	if value, err := strconv.ParseBool(os.Getenv(settingEnv("in-process"))); err == nil {
+		inProcess = value
+	}
+	flag.BoolVar(&inProcess, "e2e.in-process", inProcess,
+		fmt.Sprintf("Start the MCP server against the mock Portal64 API instead of using -e2e.base-url (%s)", settingEnv("in-process")))
+	if value, err := strconv.Atoi(os.Getenv(settingEnv("port"))); err == nil {
+		port = value
+	}
+	flag.IntVar(&port, "e2e.port", port,
+		fmt.Sprintf("Port of the in-process MCP server, 0 for a free one (%s)", settingEnv("port")))
+}
+
+// settingEnv returns the environment variable of a setting
+func settingEnv(name string) string {
+	return "PORTAL64_E2E_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// stringSetting registers the flag -e2e.<name> for a string setting,
+// defaulting to its environment variable or else the current value
+func stringSetting(p *string, name, usage string) {
+	if value := os.Getenv(settingEnv(name)); value != "" {
+		*p = value
+	}
+	flag.StringVar(p, "e2e."+name, *p, fmt.Sprintf("%s (%s)", usage, settingEnv(name)))
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if !inProcess {
+		os.Exit(m.Run())
+	}
+
+	cfg, err := config.Load("")
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	env, err := e2e.Start(cfg, logger, port)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to start the test environment: %v\n", err)
+		os.Exit(1)
+	}
+	BaseURL = env.ServerURL
+
+	code := m.Run()
+	env.Close()
+	os.Exit(code)
+}
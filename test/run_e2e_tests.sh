@@ -13,7 +13,8 @@ BLUE='\033[0;34m'
 NC='\033[0m' # No Color
 
 # Configuration
-BASE_URL="http://localhost:8888"
+BASE_URL="${PORTAL64_E2E_BASE_URL:-http://localhost:8888}"
+export PORTAL64_E2E_BASE_URL="${BASE_URL}"
 TEST_RESULTS_DIR="test-results"
 TIMESTAMP=$(date +"%Y%m%d_%H%M%S")
 RESULTS_FILE="${TEST_RESULTS_DIR}/e2e_test_results_${TIMESTAMP}.txt"
@@ -61,7 +62,7 @@ check_server() {
         return 0
     else
         print_error "Server is not running or not responding at ${BASE_URL}"
-        print_info "Please start the Portal64 MCP Server on localhost:8888 before running tests"
+        print_info "Please start the Portal64 MCP Server on ${BASE_URL} before running tests"
         return 1
     fi
 }
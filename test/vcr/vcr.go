@@ -0,0 +1,392 @@
+// Package vcr provides a record-and-replay http.RoundTripper for tests.
+//
+// In record mode requests are forwarded to the real Portal64 API and the
+// sanitized interactions are written to a YAML cassette. In replay mode the
+// cassette is served without any network access.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mode selects whether a Recorder records or replays interactions
+type Mode string
+
+const (
+	// ModeReplay serves responses from the cassette only
+	ModeReplay Mode = "replay"
+	// ModeRecord forwards requests upstream and overwrites the cassette
+	ModeRecord Mode = "record"
+)
+
+// CassetteVersion is the format version written to new cassettes
+const CassetteVersion = 1
+
+// redacted replaces sanitized header and query values
+const redacted = "REDACTED"
+
+// sensitiveHeaders are never written to a cassette
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "Proxy-Authorization"}
+
+// sensitiveParams are redacted in recorded request URLs
+var sensitiveParams = []string{"api_key", "apikey", "token", "access_token", "password", "secret"}
+
+// personalFields are redacted in recorded response bodies: the names,
+// birth years and member numbers of players. The player ID is kept, as
+// requests are matched by it.
+var personalFields = []string{"name", "firstname", "birth_year", "pkz", "id_person", "fide_id"}
+
+// personMarkers are fields only objects describing a person have. Only in
+// such objects "name" is a personal name.
+var personMarkers = []string{"firstname", "birth_year", "pkz", "id_person"}
+
+// BodySanitizer rewrites a response body before it is written to a
+// cassette
+type BodySanitizer func(body []byte) []byte
+
+// Cassette holds a sequence of recorded HTTP interactions
+type Cassette struct {
+	Version      int           `yaml:"version"`
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+// Interaction represents a single request/response pair
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+// Request represents a recorded request. URL holds path and query only,
+// so cassettes replay against any base URL.
+type Request struct {
+	Method  string              `yaml:"method"`
+	URL     string              `yaml:"url"`
+	Headers map[string][]string `yaml:"headers,omitempty"`
+}
+
+// Response represents a recorded response
+type Response struct {
+	StatusCode int                 `yaml:"status_code"`
+	Headers    map[string][]string `yaml:"headers,omitempty"`
+	Body       string              `yaml:"body"`
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions
+type Recorder struct {
+	// SanitizeBody rewrites recorded response bodies, SanitizePersonalData
+	// unless replaced after New
+	SanitizeBody BodySanitizer
+
+	mu        sync.Mutex
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	cassette  *Cassette
+	used      []bool
+}
+
+// New creates a recorder for the cassette at path. In replay mode the
+// cassette must exist; transport is only used when recording and defaults
+// to http.DefaultTransport.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		SanitizeBody: SanitizePersonalData,
+		path:         path,
+		mode:         mode,
+		transport:    transport,
+		cassette:     &Cassette{Version: CassetteVersion},
+	}
+
+	switch mode {
+	case ModeRecord:
+		return r, nil
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := yaml.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+		return r, nil
+	default:
+		return nil, fmt.Errorf("unknown vcr mode: %s", mode)
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Stop writes the cassette to disk when recording
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := yaml.Marshal(r.cassette)
+	if err != nil {
+		return fmt.Errorf("failed to serialize cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// record forwards the request upstream and stores the sanitized interaction
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := body
+	if r.SanitizeBody != nil {
+		recorded = r.SanitizeBody(body)
+	}
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     sanitizeURL(req.URL),
+			Headers: sanitizeHeaders(req.Header),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    sanitizeHeaders(resp.Header),
+			Body:       string(recorded),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := sanitizeURL(req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != key {
+			continue
+		}
+		r.used[i] = true
+
+		header := http.Header{}
+		for k, v := range interaction.Response.Headers {
+			header[k] = v
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("vcr: no recorded interaction for %s %s in %s", req.Method, key, r.path)
+}
+
+// sanitizeURL strips scheme and host and redacts sensitive query parameters
+func sanitizeURL(u *url.URL) string {
+	query := u.Query()
+	for _, param := range sensitiveParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+		}
+	}
+
+	result := u.Path
+	if encoded := query.Encode(); encoded != "" {
+		result += "?" + encoded
+	}
+	return result
+}
+
+// sanitizeHeaders drops sensitive and volatile headers
+func sanitizeHeaders(h http.Header) map[string][]string {
+	result := make(map[string][]string)
+	for k, v := range h {
+		if isSensitiveHeader(k) || k == "Date" {
+			continue
+		}
+		result[k] = v
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// SanitizePersonalData redacts the personalFields of persons in a JSON
+// body: strings are replaced by "REDACTED" and numbers by 0. Other bodies
+// are returned unchanged.
+func SanitizePersonalData(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+	if !redactPersons(data) {
+		return body
+	}
+
+	sanitized, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	if bytes.HasSuffix(body, []byte("\n")) {
+		sanitized = append(sanitized, '\n')
+	}
+	return sanitized
+}
+
+// redactPersons redacts the personal fields of all persons in a decoded
+// JSON value and reports whether it changed anything
+func redactPersons(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if hasAny(v, personMarkers) {
+			for _, field := range personalFields {
+				switch v[field].(type) {
+				case string:
+					v[field] = redacted
+					changed = true
+				case json.Number:
+					v[field] = 0
+					changed = true
+				}
+			}
+		}
+		for _, field := range v {
+			changed = redactPersons(field) || changed
+		}
+	case []interface{}:
+		for _, item := range v {
+			changed = redactPersons(item) || changed
+		}
+	}
+	return changed
+}
+
+func hasAny(object map[string]interface{}, fields []string) bool {
+	for _, field := range fields {
+		if _, ok := object[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveHeader reports whether a header must not be recorded
+func isSensitiveHeader(name string) bool {
+	for _, h := range sensitiveHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open creates a recorder for the named cassette under test/fixtures/cassettes.
+// The mode is taken from the VCR_MODE environment variable (default replay)
+// and the cassette is saved when the test finishes.
+func Open(t *testing.T, name string, transport http.RoundTripper) *Recorder {
+	t.Helper()
+
+	mode := Mode(os.Getenv("VCR_MODE"))
+	if mode == "" {
+		mode = ModeReplay
+	}
+
+	rec, err := New(CassettePath(t, name), mode, transport)
+	if err != nil {
+		t.Fatalf("failed to open cassette %s: %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("failed to save cassette %s: %v", name, err)
+		}
+	})
+
+	return rec
+}
+
+// CassettePath returns the absolute path of a named cassette
+func CassettePath(t *testing.T, name string) string {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(wd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(wd)
+		if parent == wd {
+			t.Fatal("Could not find project root (go.mod not found)")
+		}
+		wd = parent
+	}
+
+	return filepath.Join(wd, "test", "fixtures", "cassettes", name+".yaml")
+}
+
+// BaseURL returns the upstream URL used when recording cassettes
+func BaseURL() string {
+	if u := os.Getenv("PORTAL64_API_URL"); u != "" {
+		return u
+	}
+	return "http://localhost:8080"
+}
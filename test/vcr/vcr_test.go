@@ -0,0 +1,109 @@
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+
+	rec, err := New(path, ModeRecord, nil)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("GET", upstream.URL+"/api/v1/players?query=M%C3%BCller&token=abc", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := (&http.Client{Transport: rec}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NoError(t, rec.Stop())
+
+	replay, err := New(path, ModeReplay, nil)
+	require.NoError(t, err)
+
+	interaction := replay.cassette.Interactions[0]
+	assert.Equal(t, "/api/v1/players?query=M%C3%BCller&token=REDACTED", interaction.Request.URL)
+	assert.NotContains(t, interaction.Request.Headers, "Authorization")
+	assert.NotContains(t, interaction.Response.Headers, "Set-Cookie")
+
+	// Replay works against a different host and with the secret replaced
+	req, _ = http.NewRequest("GET", "http://other.example/api/v1/players?query=M%C3%BCller&token=xyz", nil)
+	resp, err = (&http.Client{Transport: replay}).Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, `{"success":true}`, string(body))
+
+	// Each interaction is served once
+	req, _ = http.NewRequest("GET", "http://other.example/api/v1/players?query=M%C3%BCller", nil)
+	_, err = (&http.Client{Transport: replay}).Do(req)
+	assert.Error(t, err)
+}
+
+func TestRecorder_SanitizesPersonalData(t *testing.T) {
+	player := `{"success":true,"data":{"club":{"id":"C0327","name":"SC Altbach"},` +
+		`"players":[{"id":"C0327-297","name":"Tran","firstname":"Minh Cuong","birth_year":1990,"pkz":"10254937","fide_id":24663832,"current_dwz":1643}]}}` + "\n"
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			w.Write([]byte("name: Tran"))
+			return
+		}
+		w.Write([]byte(player))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.yaml")
+	rec, err := New(path, ModeRecord, nil)
+	require.NoError(t, err)
+	for _, p := range []string{"/api/v1/clubs/C0327/profile", "/plain"} {
+		resp, err := (&http.Client{Transport: rec}).Get(upstream.URL + p)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Contains(t, string(body), "Tran", "the caller gets the unsanitized body")
+	}
+	require.NoError(t, rec.Stop())
+
+	replay, err := New(path, ModeReplay, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":{"club":{"id":"C0327","name":"SC Altbach"},`+
+		`"players":[{"birth_year":0,"current_dwz":1643,"fide_id":0,"firstname":"REDACTED","id":"C0327-297","name":"REDACTED","pkz":"REDACTED"}]},"success":true}`+"\n",
+		replay.cassette.Interactions[0].Response.Body)
+	assert.Equal(t, "name: Tran", replay.cassette.Interactions[1].Response.Body)
+
+	// The hook can be replaced
+	rec, err = New(path, ModeRecord, nil)
+	require.NoError(t, err)
+	rec.SanitizeBody = nil
+	resp, err := (&http.Client{Transport: rec}).Get(upstream.URL + "/api/v1/clubs/C0327/profile")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, player, rec.cassette.Interactions[0].Response.Body)
+}
+
+func TestCassettes_Sanitized(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(filepath.Dir(CassettePath(t, "any")), "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	for _, path := range paths {
+		replay, err := New(path, ModeReplay, nil)
+		require.NoError(t, err)
+		for _, interaction := range replay.cassette.Interactions {
+			body := interaction.Response.Body
+			assert.Equal(t, body, string(SanitizePersonalData([]byte(body))), "%s contains personal data", path)
+		}
+	}
+}